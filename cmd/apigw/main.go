@@ -8,12 +8,22 @@ import (
 	"syscall"
 	"vc/internal/apigw/apiv1"
 	"vc/internal/apigw/db"
+	"vc/internal/apigw/deferred"
+	"vc/internal/apigw/erasure"
+	"vc/internal/apigw/federation"
+	"vc/internal/apigw/grpcserver"
 	"vc/internal/apigw/httpserver"
 	"vc/internal/apigw/inbound"
 	"vc/internal/apigw/outbound"
+	"vc/internal/apigw/webhook"
 	"vc/pkg/configuration"
+	"vc/pkg/httphelpers"
 	"vc/pkg/logger"
+	"vc/pkg/messagebroker/kafka"
 	"vc/pkg/trace"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 type service interface {
@@ -33,7 +43,7 @@ func main() {
 		panic(err)
 	}
 
-	log, err := logger.New(serviceName, cfg.Common.Log.FolderPath, cfg.Common.Production)
+	log, err := logger.New(serviceName, cfg.Common.Log.FolderPath, cfg.Common.Log.Format, cfg.Common.Production)
 	if err != nil {
 		panic(err)
 	}
@@ -41,6 +51,21 @@ func main() {
 	// main function log
 	mainLog := log.New("main")
 
+	if err := log.SetRedaction(cfg.Common.Log.Redaction.Fields, cfg.Common.Log.Redaction.Allow); err != nil {
+		panic(err)
+	}
+
+	if err := log.SetLevels(cfg.Common.Log.Levels); err != nil {
+		panic(err)
+	}
+
+	configWatcher, err := configuration.NewWatcher(ctx, cfg, log)
+	if err != nil {
+		panic(err)
+	}
+	configWatcher.Subscribe("log_levels", configuration.LogLevels(log))
+	configWatcher.Subscribe("log_redaction", configuration.LogRedaction(log))
+
 	tracer, err := trace.New(ctx, cfg, serviceName, log)
 	if err != nil {
 		panic(err)
@@ -62,7 +87,45 @@ func main() {
 		}
 	}
 
-	apiv1Client, err := apiv1.New(ctx, dbService, tracer, cfg, log)
+	var federationService *federation.Service
+	if cfg.APIGW.TrustModel.Type == "openid_federation" {
+		federationService, err = federation.New(ctx, cfg, log)
+		services["federationService"] = federationService
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var webhookService *webhook.Service
+	if cfg.APIGW.Webhook.Enabled {
+		webhookService, err = webhook.New(ctx, wg, dbService, tracer, cfg, log)
+		services["webhookService"] = webhookService
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var kafkaAdmin *kafka.AdminClient
+	if cfg.Common.Kafka.Enabled {
+		kafkaAdmin = kafka.NewAdminClient(ctx, cfg, log)
+		services["kafkaAdmin"] = kafkaAdmin
+	}
+
+	apiv1Client, err := apiv1.New(ctx, dbService, federationService, webhookService, kafkaAdmin, tracer, cfg, log)
+	if err != nil {
+		panic(err)
+	}
+
+	if cfg.APIGW.Deferred.Enabled {
+		deferredService, err := deferred.New(ctx, wg, apiv1Client, cfg, log)
+		services["deferredService"] = deferredService
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	erasureService, err := erasure.New(ctx, wg, apiv1Client, cfg, log)
+	services["erasureService"] = erasureService
 	if err != nil {
 		panic(err)
 	}
@@ -72,6 +135,21 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+	configWatcher.Subscribe("httpserver", httpService.Reload)
+	httpService.AddHealthCheck("mongodb", dbService.Ping)
+	httpService.AddHealthCheck("issuer", httphelpers.GRPCHealthCheck(cfg.Issuer.GRPCServer.Addr, grpc.WithTransportCredentials(insecure.NewCredentials())))
+	httpService.AddHealthCheck("registry", httphelpers.GRPCHealthCheck(cfg.Registry.GRPCServer.Addr, grpc.WithTransportCredentials(insecure.NewCredentials())))
+	if cfg.IsAsyncEnabled(mainLog) {
+		httpService.AddHealthCheck("kafka", kafkaAdmin.Ping)
+	}
+
+	if cfg.APIGW.GRPCServer != nil {
+		grpcService, err := grpcserver.New(ctx, cfg, apiv1Client, tracer, eventPublisher, httpService.RateLimit, log)
+		services["grpcService"] = grpcService
+		if err != nil {
+			panic(err)
+		}
+	}
 
 	if cfg.IsAsyncEnabled(mainLog) {
 		eventConsumer, err := inbound.New(ctx, cfg, apiv1Client, tracer, log.New("eventConsumer"))
@@ -89,6 +167,12 @@ func main() {
 
 	mainLog.Info("HALTING SIGNAL!")
 
+	// drain in-flight requests before closing the services they depend on
+	if err := services["httpService"].Close(ctx); err != nil {
+		mainLog.Error(err, "httpService shutdown")
+	}
+	delete(services, "httpService")
+
 	for serviceName, service := range services {
 		if err := service.Close(ctx); err != nil {
 			mainLog.Trace("serviceName", serviceName, "error", err)
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+)
+
+const usage = `datastorectl exports and imports the documents of an authentic source through the apigw admin API
+
+Usage:
+  datastorectl export -url http://apigw:8080 -authentic-source SUNET [-format ndjson|cbor] [-out file]
+  datastorectl import -url http://apigw:8080 -authentic-source SUNET [-format ndjson|cbor] [-in file] [-dry-run]
+
+The API key is read from the VC_API_KEY environment variable.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "export":
+		err = export(os.Args[2:])
+	case "import":
+		err = importArchive(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// newRequest builds a request to the admin documents endpoint
+func newRequest(method, baseURL, path string, query url.Values, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, baseURL+"/api/v1/admin/documents/"+path+"?"+query.Encode(), body)
+	if err != nil {
+		return nil, err
+	}
+	if apiKey := os.Getenv("VC_API_KEY"); apiKey != "" {
+		req.Header.Set("X-API-Key", apiKey)
+	}
+	return req, nil
+}
+
+// problem returns the error of a non 200 response
+func problem(resp *http.Response) error {
+	b, _ := io.ReadAll(resp.Body)
+	return fmt.Errorf("%s: %s", resp.Status, b)
+}
+
+func export(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	baseURL := flags.String("url", "http://localhost:8080", "apigw base url")
+	authenticSource := flags.String("authentic-source", "", "authentic source to export")
+	format := flags.String("format", "ndjson", "archive format, ndjson or cbor")
+	out := flags.String("out", "", "archive file, stdout if empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	req, err := newRequest(http.MethodGet, *baseURL, "export", url.Values{
+		"authentic_source": {*authenticSource},
+		"format":           {*format},
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return problem(resp)
+	}
+
+	w := os.Stdout
+	if *out != "" {
+		w, err = os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer w.Close()
+	}
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return err
+	}
+
+	// trailers are available once the body is read
+	if resp.Trailer.Get("X-Export-Status") != "complete" {
+		return fmt.Errorf("export incomplete after %s documents", resp.Trailer.Get("X-Export-Count"))
+	}
+
+	fmt.Fprintf(os.Stderr, "exported %s documents\n", resp.Trailer.Get("X-Export-Count"))
+
+	return nil
+}
+
+func importArchive(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	baseURL := flags.String("url", "http://localhost:8080", "apigw base url")
+	authenticSource := flags.String("authentic-source", "", "authentic source of the documents")
+	format := flags.String("format", "ndjson", "archive format, ndjson or cbor")
+	in := flags.String("in", "", "archive file, stdin if empty")
+	dryRun := flags.Bool("dry-run", false, "only validate the archive")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	r := os.Stdin
+	if *in != "" {
+		f, err := os.Open(*in)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	req, err := newRequest(http.MethodPost, *baseURL, "import", url.Values{
+		"authentic_source": {*authenticSource},
+		"format":           {*format},
+		"dry_run":          {strconv.FormatBool(*dryRun)},
+	}, r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", map[string]string{"ndjson": "application/x-ndjson", "cbor": "application/cbor-seq"}[*format])
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return problem(resp)
+	}
+
+	reply := struct {
+		DryRun   bool              `json:"dry_run"`
+		Total    int               `json:"total"`
+		Imported int               `json:"imported"`
+		Errors   []json.RawMessage `json:"errors"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		return err
+	}
+
+	for _, e := range reply.Errors {
+		fmt.Fprintln(os.Stderr, string(e))
+	}
+	fmt.Fprintf(os.Stderr, "dry_run: %t, total: %d, imported: %d, errors: %d\n", reply.DryRun, reply.Total, reply.Imported, len(reply.Errors))
+
+	if len(reply.Errors) > 0 {
+		return fmt.Errorf("%d documents failed", len(reply.Errors))
+	}
+
+	return nil
+}
@@ -10,9 +10,14 @@ import (
 	"vc/internal/issuer/auditlog"
 	"vc/internal/issuer/grpcserver"
 	"vc/internal/issuer/httpserver"
+	"vc/internal/issuer/keys"
 	"vc/pkg/configuration"
+	"vc/pkg/httphelpers"
 	"vc/pkg/logger"
 	"vc/pkg/trace"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 type service interface {
@@ -32,7 +37,7 @@ func main() {
 		panic(err)
 	}
 
-	log, err := logger.New(serviceName, cfg.Common.Log.FolderPath, cfg.Common.Production)
+	log, err := logger.New(serviceName, cfg.Common.Log.FolderPath, cfg.Common.Log.Format, cfg.Common.Production)
 	if err != nil {
 		panic(err)
 	}
@@ -40,6 +45,21 @@ func main() {
 	// main function log
 	mainLog := log.New("main")
 
+	if err := log.SetRedaction(cfg.Common.Log.Redaction.Fields, cfg.Common.Log.Redaction.Allow); err != nil {
+		panic(err)
+	}
+
+	if err := log.SetLevels(cfg.Common.Log.Levels); err != nil {
+		panic(err)
+	}
+
+	configWatcher, err := configuration.NewWatcher(ctx, cfg, log)
+	if err != nil {
+		panic(err)
+	}
+	configWatcher.Subscribe("log_levels", configuration.LogLevels(log))
+	configWatcher.Subscribe("log_redaction", configuration.LogRedaction(log))
+
 	tracer, err := trace.New(ctx, cfg, serviceName, log)
 	if err != nil {
 		panic(err)
@@ -51,7 +71,13 @@ func main() {
 		panic(err)
 	}
 
-	apiv1Client, err := apiv1.New(ctx, auditLogService, cfg, tracer, log)
+	keysService, err := keys.New(ctx, cfg, log)
+	services["keysService"] = keysService
+	if err != nil {
+		panic(err)
+	}
+
+	apiv1Client, err := apiv1.New(ctx, auditLogService, keysService, cfg, tracer, log)
 	if err != nil {
 		panic(err)
 	}
@@ -61,6 +87,8 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+	configWatcher.Subscribe("httpserver", httpService.Reload)
+	httpService.AddHealthCheck("registry", httphelpers.GRPCHealthCheck(cfg.Registry.GRPCServer.Addr, grpc.WithTransportCredentials(insecure.NewCredentials())))
 
 	grpcService, err := grpcserver.New(ctx, cfg, apiv1Client, log)
 	services["grpcService"] = grpcService
@@ -76,6 +104,12 @@ func main() {
 
 	mainLog.Info("HALTING SIGNAL!")
 
+	// drain in-flight requests before closing the services they depend on
+	if err := services["httpService"].Close(ctx); err != nil {
+		mainLog.Error(err, "httpService shutdown")
+	}
+	delete(services, "httpService")
+
 	for serviceName, service := range services {
 		if err := service.Close(ctx); err != nil {
 			mainLog.Trace("serviceName", serviceName, "error", err)
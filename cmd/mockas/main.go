@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"os"
 	"os/signal"
 	"sync"
@@ -10,7 +11,9 @@ import (
 	"vc/internal/mockas/httpserver"
 	"vc/internal/mockas/inbound"
 	"vc/pkg/configuration"
+	"vc/pkg/httphelpers"
 	"vc/pkg/logger"
+	"vc/pkg/messagebroker/kafka"
 	"vc/pkg/trace"
 )
 
@@ -26,12 +29,15 @@ func main() {
 		serviceName string = "mockas"
 	)
 
+	scenarioPath := flag.String("scenario", "", "load a YAML scenario into apigw and exit")
+	flag.Parse()
+
 	cfg, err := configuration.New(ctx)
 	if err != nil {
 		panic(err)
 	}
 
-	log, err := logger.New(serviceName, cfg.Common.Log.FolderPath, cfg.Common.Production)
+	log, err := logger.New(serviceName, cfg.Common.Log.FolderPath, cfg.Common.Log.Format, cfg.Common.Production)
 	if err != nil {
 		panic(err)
 	}
@@ -39,6 +45,10 @@ func main() {
 	// main function log
 	mainLog := log.New("main")
 
+	if err := log.SetRedaction(cfg.Common.Log.Redaction.Fields, cfg.Common.Log.Redaction.Allow); err != nil {
+		panic(err)
+	}
+
 	tracer, err := trace.New(ctx, cfg, serviceName, log)
 	if err != nil {
 		panic(err)
@@ -49,11 +59,31 @@ func main() {
 		panic(err)
 	}
 
+	if *scenarioPath != "" {
+		scenario, err := apiv1.ReadScenario(*scenarioPath)
+		if err != nil {
+			panic(err)
+		}
+		if _, err := apiv1Client.LoadScenario(ctx, scenario); err != nil {
+			panic(err)
+		}
+		if err := tracer.Shutdown(ctx); err != nil {
+			mainLog.Error(err, "Tracer shutdown")
+		}
+		return
+	}
+
 	httpService, err := httpserver.New(ctx, cfg, apiv1Client, tracer, log)
 	services["httpService"] = httpService
 	if err != nil {
 		panic(err)
 	}
+	httpService.AddHealthCheck("apigw", httphelpers.HTTPHealthCheck(cfg.MockAS.DatastoreURL+"/health/live"))
+	if cfg.IsAsyncEnabled(mainLog) {
+		kafkaAdmin := kafka.NewAdminClient(ctx, cfg, log)
+		services["kafkaAdmin"] = kafkaAdmin
+		httpService.AddHealthCheck("kafka", kafkaAdmin.Ping)
+	}
 
 	if cfg.IsAsyncEnabled(mainLog) {
 		eventConsumer, err := inbound.New(ctx, cfg, apiv1Client, tracer, log.New("eventConsumer"))
@@ -71,6 +101,12 @@ func main() {
 
 	mainLog.Info("HALTING SIGNAL!")
 
+	// drain in-flight requests before closing the services they depend on
+	if err := services["httpService"].Close(ctx); err != nil {
+		mainLog.Error(err, "httpService shutdown")
+	}
+	delete(services, "httpService")
+
 	for serviceName, service := range services {
 		if err := service.Close(ctx); err != nil {
 			mainLog.Trace("serviceName", serviceName, "error", err)
@@ -9,8 +9,11 @@ import (
 	"vc/internal/persistent/apiv1"
 	"vc/internal/persistent/db"
 	"vc/internal/persistent/httpserver"
+	"vc/internal/persistent/outbound"
+	"vc/internal/persistent/retention"
 	"vc/pkg/configuration"
 	"vc/pkg/logger"
+	"vc/pkg/messagebroker/kafka"
 	"vc/pkg/trace"
 )
 
@@ -31,7 +34,7 @@ func main() {
 		panic(err)
 	}
 
-	log, err := logger.New(serviceName, cfg.Common.Log.FolderPath, cfg.Common.Production)
+	log, err := logger.New(serviceName, cfg.Common.Log.FolderPath, cfg.Common.Log.Format, cfg.Common.Production)
 	if err != nil {
 		panic(err)
 	}
@@ -39,6 +42,10 @@ func main() {
 	// main function log
 	mainLog := log.New("main")
 
+	if err := log.SetRedaction(cfg.Common.Log.Redaction.Fields, cfg.Common.Log.Redaction.Allow); err != nil {
+		panic(err)
+	}
+
 	tracer, err := trace.New(ctx, cfg, serviceName, log)
 	if err != nil {
 		panic(err)
@@ -51,6 +58,24 @@ func main() {
 		panic(err)
 	}
 
+	var eventPublisher retention.EventPublisher
+	if cfg.IsAsyncEnabled(mainLog) {
+		var err error
+		eventPublisher, err = outbound.New(ctx, cfg, tracer, log)
+		services["eventPublisher"] = eventPublisher
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	if cfg.Persistent.Retention.Enabled {
+		retentionService, err := retention.New(ctx, wg, dbService, eventPublisher, tracer, cfg, log)
+		services["retentionService"] = retentionService
+		if err != nil {
+			panic(err)
+		}
+	}
+
 	apiv1Client, err := apiv1.New(ctx, dbService, tracer, cfg, log)
 	if err != nil {
 		log.Error(err, "apiv1Client")
@@ -61,6 +86,12 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+	httpService.AddHealthCheck("mongodb", dbService.Ping)
+	if cfg.IsAsyncEnabled(mainLog) {
+		kafkaAdmin := kafka.NewAdminClient(ctx, cfg, log)
+		services["kafkaAdmin"] = kafkaAdmin
+		httpService.AddHealthCheck("kafka", kafkaAdmin.Ping)
+	}
 
 	// Handle sigterm and await termChan signal
 	termChan := make(chan os.Signal, 1)
@@ -70,6 +101,12 @@ func main() {
 
 	mainLog.Info("HALTING SIGNAL!")
 
+	// drain in-flight requests before closing the services they depend on
+	if err := services["httpService"].Close(ctx); err != nil {
+		mainLog.Error(err, "httpService shutdown")
+	}
+	delete(services, "httpService")
+
 	for serviceName, service := range services {
 		if err := service.Close(ctx); err != nil {
 			mainLog.Trace("serviceName", serviceName, "error", err)
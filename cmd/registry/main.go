@@ -9,7 +9,10 @@ import (
 	"vc/internal/registry/apiv1"
 	"vc/internal/registry/db"
 	"vc/internal/registry/httpserver"
+	"vc/internal/registry/rpcserver"
+	"vc/internal/registry/statuslist"
 	"vc/internal/registry/tree"
+	"vc/internal/registry/watch"
 	"vc/pkg/configuration"
 	"vc/pkg/logger"
 	"vc/pkg/trace"
@@ -32,7 +35,7 @@ func main() {
 		panic(err)
 	}
 
-	log, err := logger.New(serviceName, cfg.Common.Log.FolderPath, cfg.Common.Production)
+	log, err := logger.New(serviceName, cfg.Common.Log.FolderPath, cfg.Common.Log.Format, cfg.Common.Production)
 	if err != nil {
 		panic(err)
 	}
@@ -40,6 +43,10 @@ func main() {
 	// main function log
 	mainLog := log.New("main")
 
+	if err := log.SetRedaction(cfg.Common.Log.Redaction.Fields, cfg.Common.Log.Redaction.Allow); err != nil {
+		panic(err)
+	}
+
 	tracer, err := trace.New(ctx, cfg, serviceName, log)
 	if err != nil {
 		panic(err)
@@ -57,7 +64,25 @@ func main() {
 		panic(err)
 	}
 
-	apiv1Client, err := apiv1.New(ctx, cfg, treeService, log)
+	statusListService, err := statuslist.New(ctx, dbService, cfg, log)
+	services["statusListService"] = statusListService
+	if err != nil {
+		panic(err)
+	}
+
+	watchService, err := watch.New(ctx, dbService, cfg, log)
+	services["watchService"] = watchService
+	if err != nil {
+		panic(err)
+	}
+
+	apiv1Client, err := apiv1.New(ctx, cfg, treeService, statusListService, watchService, log)
+	if err != nil {
+		panic(err)
+	}
+
+	rpcService, err := rpcserver.New(ctx, apiv1Client, cfg, log)
+	services["rpcService"] = rpcService
 	if err != nil {
 		panic(err)
 	}
@@ -67,6 +92,7 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+	httpService.AddHealthCheck("db", dbService.Ping)
 
 	// Handle sigterm and await termChan signal
 	termChan := make(chan os.Signal, 1)
@@ -76,6 +102,12 @@ func main() {
 
 	mainLog.Info("HALTING SIGNAL!")
 
+	// drain in-flight requests before closing the services they depend on
+	if err := services["httpService"].Close(ctx); err != nil {
+		mainLog.Error(err, "httpService shutdown")
+	}
+	delete(services, "httpService")
+
 	for serviceName, service := range services {
 		if err := service.Close(ctx); err != nil {
 			mainLog.Error(err, "serviceName", serviceName)
@@ -12,7 +12,9 @@ import (
 	"vc/internal/ui/httpserver"
 	"vc/internal/ui/outbound"
 	"vc/pkg/configuration"
+	"vc/pkg/httphelpers"
 	"vc/pkg/logger"
+	"vc/pkg/messagebroker/kafka"
 	"vc/pkg/trace"
 )
 
@@ -38,7 +40,7 @@ func main() {
 		panic(err)
 	}
 
-	log, err := logger.New(serviceName, cfg.Common.Log.FolderPath, cfg.Common.Production)
+	log, err := logger.New(serviceName, cfg.Common.Log.FolderPath, cfg.Common.Log.Format, cfg.Common.Production)
 	if err != nil {
 		panic(err)
 	}
@@ -46,6 +48,10 @@ func main() {
 	// main function log
 	mainLog := log.New("main")
 
+	if err := log.SetRedaction(cfg.Common.Log.Redaction.Fields, cfg.Common.Log.Redaction.Allow); err != nil {
+		panic(err)
+	}
+
 	tracer, err := trace.New(ctx, cfg, serviceName, log)
 	if err != nil {
 		panic(err)
@@ -73,6 +79,13 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+	httpService.AddHealthCheck("apigw", httphelpers.HTTPHealthCheck(cfg.UI.Services.APIGW.BaseURL+"/health/live"))
+	httpService.AddHealthCheck("mockas", httphelpers.HTTPHealthCheck(cfg.UI.Services.MockAS.BaseURL+"/health/live"))
+	if cfg.IsAsyncEnabled(mainLog) {
+		kafkaAdmin := kafka.NewAdminClient(ctx, cfg, log)
+		services["kafkaAdmin"] = kafkaAdmin
+		httpService.AddHealthCheck("kafka", kafkaAdmin.Ping)
+	}
 
 	// Handle sigterm and await termChan signal
 	termChan := make(chan os.Signal, 1)
@@ -82,6 +95,12 @@ func main() {
 
 	mainLog.Info("HALTING SIGNAL!")
 
+	// drain in-flight requests before closing the services they depend on
+	if err := services["httpService"].Close(ctx); err != nil {
+		mainLog.Error(err, "httpService shutdown")
+	}
+	delete(services, "httpService")
+
 	for serviceName, service := range services {
 		if err := service.Close(ctx); err != nil {
 			mainLog.Trace("serviceName", serviceName, "error", err)
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"vc/internal/apigw/apiv1"
+	"vc/pkg/model"
+)
+
+func upload(args []string) error {
+	flags := flag.NewFlagSet("upload", flag.ExitOnError)
+	baseURL := flags.String("url", "http://localhost:8080", "apigw base url")
+	in := flags.String("in", "", "upload request json, stdin if empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	request := &apiv1.UploadRequest{}
+	if err := readJSON(*in, request); err != nil {
+		return err
+	}
+	if request.Meta == nil {
+		return fmt.Errorf("upload request has no meta")
+	}
+
+	if err := postJSON(*baseURL+"/api/v1/upload", nil, request, nil); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "uploaded %s %s\n", request.Meta.DocumentType, request.Meta.DocumentID)
+
+	return nil
+}
+
+func issue(args []string) error {
+	flags := flag.NewFlagSet("issue", flag.ExitOnError)
+	baseURL := flags.String("url", "http://localhost:8080", "apigw base url")
+	authenticSource := flags.String("authentic-source", "", "authentic source of the document")
+	documentType := flags.String("document-type", "", "document type, e.g. EHIC, PDA1 or ELM")
+	credentialType := flags.String("credential-type", "sdjwt", "credential type")
+	collectID := flags.String("collect-id", "", "collect id of the document")
+	identity := flags.String("identity", "", "identity json of the holder")
+	dryRun := flags.Bool("dry-run", false, "return the unsigned payload and disclosures")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	request := &apiv1.CredentialRequest{
+		AuthenticSource: *authenticSource,
+		DocumentType:    *documentType,
+		CredentialType:  *credentialType,
+		CollectID:       *collectID,
+		Identity:        &model.Identity{},
+		DryRun:          *dryRun,
+	}
+	if *identity == "" {
+		return fmt.Errorf("-identity is required")
+	}
+	if err := readJSON(*identity, request.Identity); err != nil {
+		return err
+	}
+
+	reply := json.RawMessage{}
+	if err := postJSON(*baseURL+"/api/v1/credential", nil, request, &reply); err != nil {
+		return err
+	}
+
+	return printJSON(reply)
+}
+
+func revoke(args []string) error {
+	flags := flag.NewFlagSet("revoke", flag.ExitOnError)
+	baseURL := flags.String("url", "http://localhost:8080", "apigw base url")
+	authenticSource := flags.String("authentic-source", "", "authentic source of the document")
+	documentType := flags.String("document-type", "", "document type, e.g. EHIC, PDA1 or ELM")
+	revocationID := flags.String("revocation-id", "", "revocation id of the document")
+	reasonCode := flags.String("reason-code", "", "CRL reason code, e.g. key_compromise")
+	reason := flags.String("reason", "", "reason for revocation")
+	revokedAt := flags.Int64("revoked-at", 0, "unix time the revocation takes effect, now if zero")
+	revision := flags.String("revision", "*", "expected document revision, any if *")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	request := &apiv1.RevokeDocumentRequest{
+		AuthenticSource: *authenticSource,
+		DocumentType:    *documentType,
+		Revocation: &model.Revocation{
+			ID:         *revocationID,
+			Revoked:    true,
+			RevokedAt:  *revokedAt,
+			Reason:     *reason,
+			ReasonCode: *reasonCode,
+		},
+	}
+
+	header := http.Header{}
+	header.Set("If-Match", *revision)
+	if err := postJSON(*baseURL+"/api/v1/document/revoke", header, request, nil); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "revoked %s\n", *revocationID)
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"vc/pkg/sdjwt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func decode(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("decode: missing format, sdjwt")
+	}
+
+	switch args[0] {
+	case "sdjwt":
+		return decodeSDJWT(args[1:])
+	default:
+		return fmt.Errorf("decode: unsupported format %q", args[0])
+	}
+}
+
+// decodedSDJWT is an SD-JWT with the disclosures applied, the signature is not verified
+type decodedSDJWT struct {
+	Header      map[string]any `json:"header"`
+	Claims      map[string]any `json:"claims"`
+	Disclosures int            `json:"disclosures"`
+	KeyBinding  bool           `json:"key_binding"`
+}
+
+func decodeSDJWT(args []string) error {
+	flags := flag.NewFlagSet("decode sdjwt", flag.ExitOnError)
+	in := flags.String("in", "", "file with the SD-JWT, stdin if empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	b, err := readInput(*in)
+	if err != nil {
+		return err
+	}
+
+	// the last element after ~ is the key binding JWT, or empty
+	parts := strings.Split(strings.TrimSpace(string(b)), "~")
+	disclosures := []string{}
+	if len(parts) > 1 {
+		disclosures = parts[1 : len(parts)-1]
+	}
+
+	claims := jwt.MapClaims{}
+	token, _, err := jwt.NewParser().ParseUnverified(parts[0], claims)
+	if err != nil {
+		return err
+	}
+
+	disclosed, err := sdjwt.DisclosedClaims(claims, disclosures)
+	if err != nil {
+		return err
+	}
+
+	return printJSON(&decodedSDJWT{
+		Header:      token.Header,
+		Claims:      disclosed,
+		Disclosures: len(disclosures),
+		KeyBinding:  len(parts) > 1 && parts[len(parts)-1] != "",
+	})
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+const usage = `vcctl runs operational tasks against a running environment
+
+Usage:
+  vcctl upload -url http://apigw:8080 [-in document.json]
+  vcctl issue -url http://apigw:8080 -authentic-source SUNET -document-type EHIC -credential-type sdjwt -collect-id ID -identity identity.json [-dry-run]
+  vcctl revoke -url http://apigw:8080 -authentic-source SUNET -document-type EHIC -revocation-id ID [-reason-code code] [-reason text] [-revision n]
+  vcctl registry proof -url http://registry:8080 -entity ENTITY [-public-key key.pem]
+  vcctl registry validate -addr registry:8090 -entity ENTITY
+  vcctl decode sdjwt [-in credential.txt]
+  vcctl replay -brokers kafka:9092 [-topic topic_upload] [-in messages.ndjson]
+
+The API key is read from the VC_API_KEY environment variable.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "upload":
+		err = upload(os.Args[2:])
+	case "issue":
+		err = issue(os.Args[2:])
+	case "revoke":
+		err = revoke(os.Args[2:])
+	case "registry":
+		err = registry(os.Args[2:])
+	case "decode":
+		err = decode(os.Args[2:])
+	case "replay":
+		err = replay(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// postJSON posts body to url and decodes the response into reply, if not nil
+func postJSON(url string, header http.Header, body, reply any) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if apiKey := os.Getenv("VC_API_KEY"); apiKey != "" {
+		req.Header.Set("X-API-Key", apiKey)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return problem(resp)
+	}
+
+	if reply == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(reply)
+}
+
+// problem returns the error of a non 200 response
+func problem(resp *http.Response) error {
+	b, _ := io.ReadAll(resp.Body)
+	return fmt.Errorf("%s: %s", resp.Status, b)
+}
+
+// readInput reads the file path, or stdin if path is empty
+func readInput(path string) ([]byte, error) {
+	if path == "" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// readJSON decodes the file path, or stdin if path is empty, into v
+func readJSON(path string, v any) error {
+	b, err := readInput(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// printJSON writes v indented to stdout
+func printJSON(v any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+	"vc/internal/gen/registry/apiv1_registry"
+	"vc/pkg/merkleproof"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func registry(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("registry: missing command, proof or validate")
+	}
+
+	switch args[0] {
+	case "proof":
+		return registryProof(args[1:])
+	case "validate":
+		return registryValidate(args[1:])
+	default:
+		return fmt.Errorf("registry: unknown command %q", args[0])
+	}
+}
+
+// registryProof prints the inclusion proof of an entity, verified against the registry public key if given
+func registryProof(args []string) error {
+	flags := flag.NewFlagSet("registry proof", flag.ExitOnError)
+	baseURL := flags.String("url", "http://localhost:8080", "registry base url")
+	entity := flags.String("entity", "", "entity to prove")
+	publicKey := flags.String("public-key", "", "PEM public key of the registry, the proof is verified if set")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	reply := struct {
+		Data *merkleproof.InclusionProof `json:"data"`
+	}{}
+	if err := postJSON(*baseURL+"/inclusion_proof", nil, map[string]string{"entity": *entity}, &reply); err != nil {
+		return err
+	}
+
+	if *publicKey != "" {
+		pem, err := os.ReadFile(*publicKey)
+		if err != nil {
+			return err
+		}
+		key, err := jwt.ParseECPublicKeyFromPEM(pem)
+		if err != nil {
+			return err
+		}
+		if err := reply.Data.Verify(*entity, key); err != nil {
+			return fmt.Errorf("inclusion proof: %w", err)
+		}
+		fmt.Fprintln(os.Stderr, "inclusion proof verified")
+	}
+
+	return printJSON(reply.Data)
+}
+
+// registryValidate asks the registry over gRPC if an entity is valid
+func registryValidate(args []string) error {
+	flags := flag.NewFlagSet("registry validate", flag.ExitOnError)
+	addr := flags.String("addr", "localhost:8090", "registry grpc address")
+	entity := flags.String("entity", "", "entity to validate")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	conn, err := grpc.NewClient(*addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := apiv1_registry.NewRegistryServiceClient(conn).Validate(ctx, &apiv1_registry.ValidateRequest{Entity: *entity})
+	if err != nil {
+		return err
+	}
+
+	return printJSON(map[string]any{"entity": *entity, "valid": resp.Valid})
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"vc/pkg/logger"
+	"vc/pkg/messagebroker/kafka"
+	"vc/pkg/model"
+
+	"github.com/IBM/sarama"
+)
+
+// replayMessage is one line of a replay file
+type replayMessage struct {
+	Topic   string            `json:"topic"`
+	Key     string            `json:"key"`
+	Headers map[string]string `json:"headers"`
+	Value   json.RawMessage   `json:"value"`
+}
+
+func replay(args []string) error {
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	brokers := flags.String("brokers", "localhost:9092", "comma separated kafka brokers")
+	topic := flags.String("topic", "", "topic to publish to, overrides the topic of the messages")
+	in := flags.String("in", "", "ndjson file of messages with topic, key, headers and value, stdin if empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	r := os.Stdin
+	if *in != "" {
+		f, err := os.Open(*in)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	cfg := &model.Cfg{Common: model.Common{Kafka: model.Kafka{Brokers: strings.Split(*brokers, ",")}}}
+	client, err := kafka.NewSyncProducerClient(context.Background(), kafka.CommonProducerConfig(cfg), cfg, nil, logger.NewSimple("vcctl"))
+	if err != nil {
+		return err
+	}
+	defer client.Close(context.Background())
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	published := 0
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		message := &replayMessage{}
+		if err := json.Unmarshal(scanner.Bytes(), message); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		if *topic != "" {
+			message.Topic = *topic
+		}
+		if message.Topic == "" {
+			return fmt.Errorf("line %d: no topic", line)
+		}
+
+		headers := make([]sarama.RecordHeader, 0, len(message.Headers))
+		for k, v := range message.Headers {
+			headers = append(headers, sarama.RecordHeader{Key: []byte(k), Value: []byte(v)})
+		}
+
+		if err := client.PublishMessage(message.Topic, message.Key, message.Value, headers); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		published++
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "published %d messages\n", published)
+
+	return nil
+}
@@ -7,7 +7,9 @@ import (
 	"sync"
 	"syscall"
 	"vc/internal/verifier/apiv1"
+	"vc/internal/verifier/db"
 	"vc/internal/verifier/httpserver"
+	"vc/internal/verifier/trust"
 	"vc/pkg/configuration"
 	"vc/pkg/logger"
 	"vc/pkg/trace"
@@ -30,7 +32,7 @@ func main() {
 		panic(err)
 	}
 
-	log, err := logger.New(serviceName, cfg.Common.Log.FolderPath, cfg.Common.Production)
+	log, err := logger.New(serviceName, cfg.Common.Log.FolderPath, cfg.Common.Log.Format, cfg.Common.Production)
 	if err != nil {
 		panic(err)
 	}
@@ -38,12 +40,34 @@ func main() {
 	// main function log
 	mainLog := log.New("main")
 
+	if err := log.SetRedaction(cfg.Common.Log.Redaction.Fields, cfg.Common.Log.Redaction.Allow); err != nil {
+		panic(err)
+	}
+
 	tracer, err := trace.New(ctx, cfg, serviceName, log)
 	if err != nil {
 		panic(err)
 	}
 
-	apiv1, err := apiv1.New(ctx, cfg, log)
+	var trustService *trust.Service
+	if cfg.Verifier.Trust.Enabled {
+		trustService, err = trust.New(ctx, wg, cfg, log)
+		services["trustService"] = trustService
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var dbService *db.Service
+	if cfg.Verifier.Analytics.Enabled || cfg.Verifier.Session.Store == "mongo" {
+		dbService, err = db.New(ctx, cfg, tracer, log)
+		services["dbService"] = dbService
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	apiv1, err := apiv1.New(ctx, dbService, trustService, cfg, log)
 	if err != nil {
 		panic(err)
 	}
@@ -53,6 +77,9 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+	if dbService != nil {
+		httpserver.AddHealthCheck("mongodb", dbService.Ping)
+	}
 
 	// Handle sigterm and await termChan signal
 	termChan := make(chan os.Signal, 1)
@@ -62,6 +89,12 @@ func main() {
 
 	mainLog.Info("HALTING SIGNAL!")
 
+	// drain in-flight requests before closing the services they depend on
+	if err := services["httpserver"].Close(ctx); err != nil {
+		mainLog.Error(err, "httpserver shutdown")
+	}
+	delete(services, "httpserver")
+
 	for serviceName, service := range services {
 		if err := service.Close(ctx); err != nil {
 			mainLog.Trace("serviceName", serviceName, "error", err)
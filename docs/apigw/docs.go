@@ -15,9 +15,36 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/consent": {
+        "/.well-known/openid-federation": {
+            "get": {
+                "description": "OpenID Federation entity configuration, an entity-statement+jwt",
+                "produces": [
+                    "application/entity-statement+jwt"
+                ],
+                "tags": [
+                    "federation"
+                ],
+                "summary": "Entity configuration",
+                "operationId": "openid-federation",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/attachments/migrate": {
             "post": {
-                "description": "Add consent endpoint",
+                "description": "Moves base64 data uris of at least object_store.min_size bytes from stored document data to the object storage, leaving references. Documents changed during the migration are skipped, their uploads already stored the attachments. The migration can be run again.",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,10 +52,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "dc4eu"
+                    "admin"
                 ],
-                "summary": "AddConsent",
-                "operationId": "add-consent",
+                "summary": "Migrate attachments",
+                "operationId": "admin-migrate-attachments",
                 "parameters": [
                     {
                         "description": " ",
@@ -36,9 +63,52 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/apiv1.AddConsentRequest"
+                            "$ref": "#/definitions/apiv1.MigrateAttachmentsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.MigrateAttachmentsReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
                         }
                     }
+                }
+            }
+        },
+        "/admin/documents/export": {
+            "get": {
+                "description": "Streams all documents of an authentic source as NDJSON or a CBOR sequence",
+                "produces": [
+                    "application/x-ndjson",
+                    "application/cbor-seq"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Export documents",
+                "operationId": "admin-export-documents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentic source",
+                        "name": "authentic_source",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ndjson or cbor",
+                        "name": "format",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
                     "200": {
@@ -47,15 +117,67 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/helpers.ErrorResponse"
+                            "$ref": "#/definitions/helpers.Problem"
                         }
                     }
                 }
             }
         },
-        "/consent/get": {
+        "/admin/documents/import": {
             "post": {
-                "description": "Get consent endpoint",
+                "description": "Imports an NDJSON or CBOR sequence archive, with dry_run the archive is only validated",
+                "consumes": [
+                    "application/x-ndjson",
+                    "application/cbor-seq"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Import documents",
+                "operationId": "admin-import-documents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentic source",
+                        "name": "authentic_source",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ndjson or cbor",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Validate only",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.ImportDocumentsReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/encryption/rotate": {
+            "post": {
+                "description": "Rewraps the data encryption keys of the identities of stored documents, deferred credentials and identity reviews, and of the disclosures of issued credentials, wrapped by a previous key encryption key, after which the previous key can be removed from previous_kek_paths or kms.previous_key_ids",
                 "consumes": [
                     "application/json"
                 ],
@@ -63,10 +185,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "dc4eu"
+                    "admin"
                 ],
-                "summary": "GetConsent",
-                "operationId": "get-consent",
+                "summary": "Rotate encryption keys",
+                "operationId": "admin-rotate-encryption-keys",
                 "parameters": [
                     {
                         "description": " ",
@@ -74,7 +196,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/apiv1.GetConsentRequest"
+                            "$ref": "#/definitions/apiv1.RotateEncryptionKeysRequest"
                         }
                     }
                 ],
@@ -82,21 +204,21 @@ const docTemplate = `{
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/model.Consent"
+                            "$ref": "#/definitions/apiv1.RotateEncryptionKeysReply"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/helpers.ErrorResponse"
+                            "$ref": "#/definitions/helpers.Problem"
                         }
                     }
                 }
             }
         },
-        "/credential": {
+        "/admin/subjects/erasure": {
             "post": {
-                "description": "Create credential endpoint",
+                "description": "Starts a job that deletes the documents only identifying the subject, removes the subject from shared documents, deletes consents, credential notifications and deferred credentials, and pseudonymizes the consent audit. The job is run by the erasure worker, poll it with the returned job_id",
                 "consumes": [
                     "application/json"
                 ],
@@ -104,10 +226,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "dc4eu"
+                    "admin"
                 ],
-                "summary": "Credential",
-                "operationId": "create-credential",
+                "summary": "Erase data subject",
+                "operationId": "admin-erase-subject",
                 "parameters": [
                     {
                         "description": " ",
@@ -115,59 +237,78 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/apiv1.CredentialRequest"
+                            "$ref": "#/definitions/apiv1.SubjectDataRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key get the stored response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/apiv1_issuer.MakeSDJWTReply"
+                            "$ref": "#/definitions/apiv1.ErasureJobReply"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/helpers.ErrorResponse"
+                            "$ref": "#/definitions/helpers.Problem"
                         }
                     }
                 }
             }
         },
-        "/credential/.well-known/jwks": {
+        "/admin/subjects/erasure/{job_id}": {
             "get": {
-                "description": "JWKS endpoint",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns the status and erased counts of an erasure job, a job interrupted by a restart is resumed",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "dc4eu"
+                    "admin"
+                ],
+                "summary": "Erasure job status",
+                "operationId": "admin-erasure-job-status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentic source",
+                        "name": "authentic_source",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "JWKS",
-                "operationId": "issuer-JWKS",
                 "responses": {
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/apiv1_issuer.JwksReply"
+                            "$ref": "#/definitions/apiv1.ErasureJobReply"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/helpers.ErrorResponse"
+                            "$ref": "#/definitions/helpers.Problem"
                         }
                     }
                 }
             }
         },
-        "/document": {
+        "/admin/subjects/export": {
             "post": {
-                "description": "Get document endpoint",
+                "description": "Returns the documents, identities, status history, consents, consent audit, credential notifications and deferred credentials of a data subject",
                 "consumes": [
                     "application/json"
                 ],
@@ -175,10 +316,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "dc4eu"
+                    "admin"
                 ],
-                "summary": "GetDocument",
-                "operationId": "get-document",
+                "summary": "Export data subject",
+                "operationId": "admin-export-subject",
                 "parameters": [
                     {
                         "description": " ",
@@ -186,7 +327,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/apiv1.GetDocumentRequest"
+                            "$ref": "#/definitions/apiv1.SubjectDataRequest"
                         }
                     }
                 ],
@@ -194,19 +335,21 @@ const docTemplate = `{
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/apiv1.GetDocumentReply"
+                            "$ref": "#/definitions/apiv1.ExportSubjectDataReply"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/helpers.ErrorResponse"
+                            "$ref": "#/definitions/helpers.Problem"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "delete one document endpoint",
+            }
+        },
+        "/consent": {
+            "post": {
+                "description": "Add consent endpoint",
                 "consumes": [
                     "application/json"
                 ],
@@ -216,8 +359,8 @@ const docTemplate = `{
                 "tags": [
                     "dc4eu"
                 ],
-                "summary": "DeleteDocument",
-                "operationId": "delete-document",
+                "summary": "AddConsent",
+                "operationId": "add-consent",
                 "parameters": [
                     {
                         "description": " ",
@@ -225,8 +368,14 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/apiv1.DeleteDocumentRequest"
+                            "$ref": "#/definitions/apiv1.AddConsentRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key get the stored response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -236,15 +385,15 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/helpers.ErrorResponse"
+                            "$ref": "#/definitions/helpers.Problem"
                         }
                     }
                 }
             }
         },
-        "/document/collect_id": {
+        "/consent/get": {
             "post": {
-                "description": "Get one document with collect id",
+                "description": "Get consent endpoint",
                 "consumes": [
                     "application/json"
                 ],
@@ -254,8 +403,8 @@ const docTemplate = `{
                 "tags": [
                     "dc4eu"
                 ],
-                "summary": "GetDocumentByCollectID",
-                "operationId": "get-document-collect-id",
+                "summary": "GetConsent",
+                "operationId": "get-consent",
                 "parameters": [
                     {
                         "description": " ",
@@ -263,7 +412,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/apiv1.GetDocumentCollectIDRequest"
+                            "$ref": "#/definitions/apiv1.GetConsentRequest"
                         }
                     }
                 ],
@@ -271,21 +420,21 @@ const docTemplate = `{
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/apiv1.GetDocumentCollectIDReply"
+                            "$ref": "#/definitions/model.Consent"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/helpers.ErrorResponse"
+                            "$ref": "#/definitions/helpers.Problem"
                         }
                     }
                 }
             }
         },
-        "/document/identity": {
-            "put": {
-                "description": "Adding array of identities to one document",
+        "/credential": {
+            "post": {
+                "description": "Create credential endpoint, with dry_run the unsigned payload and disclosures are returned without signing. If deferred issuance is enabled and the document is not yet available, only transactionID is returned",
                 "consumes": [
                     "application/json"
                 ],
@@ -295,8 +444,8 @@ const docTemplate = `{
                 "tags": [
                     "dc4eu"
                 ],
-                "summary": "AddDocumentIdentity",
-                "operationId": "add-document-identity",
+                "summary": "Credential",
+                "operationId": "create-credential",
                 "parameters": [
                     {
                         "description": " ",
@@ -304,24 +453,47 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/apiv1.AddDocumentIdentityRequest"
+                            "$ref": "#/definitions/apiv1.CredentialRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key get the stored response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1_issuer.MakeSDJWTReply"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/helpers.ErrorResponse"
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Collect id used the maximum number of times",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    },
+                    "410": {
+                        "description": "Collect id expired",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete identity to document endpoint",
+            }
+        },
+        "/credential/.well-known/jwks": {
+            "get": {
+                "description": "JWKS endpoint",
                 "consumes": [
                     "application/json"
                 ],
@@ -331,35 +503,27 @@ const docTemplate = `{
                 "tags": [
                     "dc4eu"
                 ],
-                "summary": "DeleteDocumentIdentity",
-                "operationId": "delete-document-identity",
-                "parameters": [
-                    {
-                        "description": " ",
-                        "name": "req",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/apiv1.DeleteDocumentIdentityRequest"
-                        }
-                    }
-                ],
+                "summary": "JWKS",
+                "operationId": "issuer-JWKS",
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1_issuer.JwksReply"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/helpers.ErrorResponse"
+                            "$ref": "#/definitions/helpers.Problem"
                         }
                     }
                 }
             }
         },
-        "/document/list": {
+        "/credential/deferred": {
             "post": {
-                "description": "List documents for an identity",
+                "description": "OpenID4VCI deferred credential endpoint, returns ISSUANCE_PENDING until the authentic source has provided the document",
                 "consumes": [
                     "application/json"
                 ],
@@ -369,8 +533,8 @@ const docTemplate = `{
                 "tags": [
                     "dc4eu"
                 ],
-                "summary": "DocumentList",
-                "operationId": "document-list",
+                "summary": "DeferredCredential",
+                "operationId": "deferred-credential",
                 "parameters": [
                     {
                         "description": " ",
@@ -378,7 +542,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/apiv1.DocumentListRequest"
+                            "$ref": "#/definitions/apiv1.DeferredCredentialRequest"
                         }
                     }
                 ],
@@ -386,21 +550,21 @@ const docTemplate = `{
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/apiv1.DocumentListReply"
+                            "$ref": "#/definitions/apiv1_issuer.MakeSDJWTReply"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/helpers.ErrorResponse"
+                            "$ref": "#/definitions/helpers.Problem"
                         }
                     }
                 }
             }
         },
-        "/document/revoke": {
+        "/credential/notification": {
             "post": {
-                "description": "Revoke one document",
+                "description": "OpenID4VCI notification endpoint, the wallet reports if the credential was accepted, failed or deleted",
                 "consumes": [
                     "application/json"
                 ],
@@ -410,8 +574,8 @@ const docTemplate = `{
                 "tags": [
                     "dc4eu"
                 ],
-                "summary": "RevokeDocument",
-                "operationId": "revoke-document",
+                "summary": "CredentialNotification",
+                "operationId": "credential-notification",
                 "parameters": [
                     {
                         "description": " ",
@@ -419,26 +583,32 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/apiv1.RevokeDocumentRequest"
+                            "$ref": "#/definitions/apiv1.CredentialNotificationRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key get the stored response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Success"
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/helpers.ErrorResponse"
+                            "$ref": "#/definitions/helpers.Problem"
                         }
                     }
                 }
             }
         },
-        "/identity/mapping": {
+        "/credential/refresh": {
             "post": {
-                "description": "Identity mapping endpoint",
+                "description": "Re-issues a credential from the current document, with a new validity window and the credential type it was issued as. Claims that did not change keep their digests. The new credential has its own notification_id, both credentials reference each other, and only the newest credential of a chain can be refreshed.",
                 "consumes": [
                     "application/json"
                 ],
@@ -448,8 +618,8 @@ const docTemplate = `{
                 "tags": [
                     "dc4eu"
                 ],
-                "summary": "IdentityMapping",
-                "operationId": "identity-mapping",
+                "summary": "RefreshCredential",
+                "operationId": "refresh-credential",
                 "parameters": [
                     {
                         "description": " ",
@@ -457,29 +627,47 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/apiv1.IdentityMappingRequest"
+                            "$ref": "#/definitions/apiv1.RefreshCredentialRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key get the stored response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/apiv1.IdentityMappingReply"
+                            "$ref": "#/definitions/apiv1_issuer.MakeSDJWTReply"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/helpers.ErrorResponse"
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Credential already refreshed",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    },
+                    "410": {
+                        "description": "Document revoked",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
                         }
                     }
                 }
             }
         },
-        "/notification": {
+        "/document": {
             "post": {
-                "description": "notification endpoint",
+                "description": "Get document endpoint, attachments in object storage are presigned download urls",
                 "consumes": [
                     "application/json"
                 ],
@@ -489,8 +677,8 @@ const docTemplate = `{
                 "tags": [
                     "dc4eu"
                 ],
-                "summary": "Notification",
-                "operationId": "generic-notification",
+                "summary": "GetDocument",
+                "operationId": "get-document",
                 "parameters": [
                     {
                         "description": " ",
@@ -498,7 +686,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/apiv1.NotificationRequest"
+                            "$ref": "#/definitions/apiv1.GetDocumentRequest"
                         }
                     }
                 ],
@@ -506,21 +694,25 @@ const docTemplate = `{
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/apiv1.NotificationReply"
+                            "$ref": "#/definitions/apiv1.GetDocumentReply"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Document revision"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/helpers.ErrorResponse"
+                            "$ref": "#/definitions/helpers.Problem"
                         }
                     }
                 }
-            }
-        },
-        "/revoke": {
-            "post": {
-                "description": "Revoke endpoint",
+            },
+            "delete": {
+                "description": "delete one document endpoint",
                 "consumes": [
                     "application/json"
                 ],
@@ -530,38 +722,54 @@ const docTemplate = `{
                 "tags": [
                     "dc4eu"
                 ],
-                "summary": "Revoke",
-                "operationId": "generic-revoke",
+                "summary": "DeleteDocument",
+                "operationId": "delete-document",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document revision, from ETag",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
                     {
                         "description": " ",
                         "name": "req",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/apiv1.RevokeRequest"
+                            "$ref": "#/definitions/apiv1.DeleteDocumentRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key get the stored response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success",
-                        "schema": {
-                            "$ref": "#/definitions/apiv1.RevokeReply"
-                        }
+                        "description": "Success"
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/helpers.ErrorResponse"
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
                         }
                     }
                 }
             }
         },
-        "/upload": {
+        "/document/changes": {
             "post": {
-                "description": "Upload endpoint",
+                "description": "Returns the metadata of the documents created, updated or revoked since the sync token, oldest first. An empty since returns every document. Changes of the last seconds are returned by the next sync.",
                 "consumes": [
                     "application/json"
                 ],
@@ -571,8 +779,8 @@ const docTemplate = `{
                 "tags": [
                     "dc4eu"
                 ],
-                "summary": "Upload",
-                "operationId": "generic-upload",
+                "summary": "DocumentChanges",
+                "operationId": "document-changes",
                 "parameters": [
                     {
                         "description": " ",
@@ -580,26 +788,1581 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/apiv1.UploadRequest"
+                            "$ref": "#/definitions/apiv1.DocumentChangesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.DocumentChangesReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/document/collect_id": {
+            "post": {
+                "description": "Get one document with collect id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dc4eu"
+                ],
+                "summary": "GetDocumentByCollectID",
+                "operationId": "get-document-collect-id",
+                "parameters": [
+                    {
+                        "description": " ",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.GetDocumentCollectIDRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.GetDocumentCollectIDReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/document/consent": {
+            "post": {
+                "description": "Record a holder consent for issuance or sharing of a document",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dc4eu"
+                ],
+                "summary": "AddDocumentConsent",
+                "operationId": "add-document-consent",
+                "parameters": [
+                    {
+                        "description": " ",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.AddDocumentConsentRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key get the stored response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.AddDocumentConsentReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/document/consent/list": {
+            "post": {
+                "description": "List the consents of a document, including withdrawn ones, and their audit trail",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dc4eu"
+                ],
+                "summary": "DocumentConsentList",
+                "operationId": "document-consent-list",
+                "parameters": [
+                    {
+                        "description": " ",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.DocumentConsentListRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.DocumentConsentListReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
                         }
                     }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Success"
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/helpers.ErrorResponse"
-                        }
+                }
+            }
+        },
+        "/document/consent/withdraw": {
+            "post": {
+                "description": "Withdraw an active consent of a document, the consent is kept with its withdrawal time",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dc4eu"
+                ],
+                "summary": "WithdrawDocumentConsent",
+                "operationId": "withdraw-document-consent",
+                "parameters": [
+                    {
+                        "description": " ",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.WithdrawDocumentConsentRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key get the stored response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.AddDocumentConsentReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/document/diff": {
+            "post": {
+                "description": "Returns the changes of the document data from from_version to to_version, as JSON pointers ordered by path",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dc4eu"
+                ],
+                "summary": "DocumentDiff",
+                "operationId": "document-diff",
+                "parameters": [
+                    {
+                        "description": " ",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.DocumentDiffRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.DocumentDiffReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "No such version",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/document/identity": {
+            "put": {
+                "description": "Adding array of identities to one document",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dc4eu"
+                ],
+                "summary": "AddDocumentIdentity",
+                "operationId": "add-document-identity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document revision, from ETag",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": " ",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.AddDocumentIdentityRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key get the stored response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete identity to document endpoint",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dc4eu"
+                ],
+                "summary": "DeleteDocumentIdentity",
+                "operationId": "delete-document-identity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document revision, from ETag",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": " ",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.DeleteDocumentIdentityRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key get the stored response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/document/list": {
+            "post": {
+                "description": "List documents for an identity, with If-None-Match or If-Modified-Since an unchanged list is not sent again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dc4eu"
+                ],
+                "summary": "DocumentList",
+                "operationId": "document-list",
+                "parameters": [
+                    {
+                        "description": " ",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.DocumentListRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the list the client has",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified of the list the client has",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.DocumentListReply"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Hash of the revisions of the listed documents"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Last modification of a listed document"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/document/revoke": {
+            "post": {
+                "description": "Revoke one document",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dc4eu"
+                ],
+                "summary": "RevokeDocument",
+                "operationId": "revoke-document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document revision, from ETag",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": " ",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.RevokeDocumentRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key get the stored response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/document/status_history": {
+            "post": {
+                "description": "Returns the status changes of a document, oldest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dc4eu"
+                ],
+                "summary": "DocumentStatusHistory",
+                "operationId": "document-status-history",
+                "parameters": [
+                    {
+                        "description": " ",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.DocumentStatusHistoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.DocumentStatusHistoryReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/document/versions": {
+            "post": {
+                "description": "Returns the versions of a document without their document data, oldest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dc4eu"
+                ],
+                "summary": "DocumentVersions",
+                "operationId": "document-versions",
+                "parameters": [
+                    {
+                        "description": " ",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.DocumentVersionsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.DocumentVersionsReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/federation/trust_chain": {
+            "get": {
+                "description": "Trust chain from the entity configuration to a configured trust anchor",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "federation"
+                ],
+                "summary": "Trust chain",
+                "operationId": "federation-trust-chain",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.TrustChainReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/identity/mapping": {
+            "post": {
+                "description": "Identity mapping endpoint",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dc4eu"
+                ],
+                "summary": "IdentityMapping",
+                "operationId": "identity-mapping",
+                "parameters": [
+                    {
+                        "description": " ",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.IdentityMappingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.IdentityMappingReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/identity/review/decision": {
+            "post": {
+                "description": "Approve or reject a pending identity review, the next collect request with the identity gets the document if approved",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dc4eu"
+                ],
+                "summary": "IdentityReviewDecision",
+                "operationId": "identity-review-decision",
+                "parameters": [
+                    {
+                        "description": " ",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.IdentityReviewDecisionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.IdentityReviewDecisionReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown review",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Review already decided",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/identity/review/list": {
+            "post": {
+                "description": "List the collect requests whose identity matched the document loosely, oldest first. Pending reviews are the manual review queue",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dc4eu"
+                ],
+                "summary": "IdentityReviewList",
+                "operationId": "identity-review-list",
+                "parameters": [
+                    {
+                        "description": " ",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.IdentityReviewListRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.IdentityReviewListReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/notification": {
+            "post": {
+                "description": "notification endpoint",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dc4eu"
+                ],
+                "summary": "Notification",
+                "operationId": "generic-notification",
+                "parameters": [
+                    {
+                        "description": " ",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.NotificationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.NotificationReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/statistics": {
+            "get": {
+                "description": "Issuance statistics per authentic source, recent errors and queue depth. Issuance counters are kept in memory since start.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dc4eu"
+                ],
+                "summary": "Statistics",
+                "operationId": "statistics",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.StatisticsReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/upload": {
+            "post": {
+                "description": "Upload endpoint. A document that is already stored is replaced if the upload has another document_data_version, the document data of every version is kept. With signed uploads enabled the request may be a compact JWS with the request as payload, or carry a detached JWS over the body. The protected header holds kid, iat, nonce and optionally exp.",
+                "consumes": [
+                    "application/json",
+                    "application/jose"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dc4eu"
+                ],
+                "summary": "Upload",
+                "operationId": "generic-upload",
+                "parameters": [
+                    {
+                        "description": " ",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.UploadRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key get the stored response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Detached JWS, header..signature, over the request body",
+                        "name": "X-JWS-Signature",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid or missing upload signature",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Replayed upload, or the document is already stored with this document_data_version",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhook": {
+            "post": {
+                "description": "Subscribe a webhook to the document lifecycle events of an authentic source, all events if events is empty. The url must start with a prefix in apigw.webhook.allow_list and resolve to public addresses. Deliveries are signed in the X-Webhook-Signature header as t=\u003cunix time\u003e,v1=\u003chex HMAC-SHA256 of \"\u003cunix time\u003e.\u003cbody\u003e\"\u003e",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dc4eu"
+                ],
+                "summary": "AddWebhook",
+                "operationId": "add-webhook",
+                "parameters": [
+                    {
+                        "description": " ",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.AddWebhookRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key get the stored response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.AddWebhookReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a webhook subscription, its pending deliveries are given up",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dc4eu"
+                ],
+                "summary": "DeleteWebhook",
+                "operationId": "delete-webhook",
+                "parameters": [
+                    {
+                        "description": " ",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.DeleteWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhook/deliveries": {
+            "post": {
+                "description": "Delivery log of a webhook subscription, newest first, limit defaults to 100",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dc4eu"
+                ],
+                "summary": "WebhookDeliveries",
+                "operationId": "webhook-deliveries",
+                "parameters": [
+                    {
+                        "description": " ",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.WebhookDeliveriesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.WebhookDeliveriesReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhook/list": {
+            "post": {
+                "description": "List the webhook subscriptions of an authentic source",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dc4eu"
+                ],
+                "summary": "ListWebhooks",
+                "operationId": "list-webhooks",
+                "parameters": [
+                    {
+                        "description": " ",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.ListWebhooksRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.ListWebhooksReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "apiv1.AddConsentRequest": {
+            "type": "object",
+            "required": [
+                "authentic_source",
+                "authentic_source_person_id"
+            ],
+            "properties": {
+                "authentic_source": {
+                    "type": "string"
+                },
+                "authentic_source_person_id": {
+                    "type": "string"
+                },
+                "consent_to": {
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.AddDocumentConsentReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.DocumentConsent"
+                }
+            }
+        },
+        "apiv1.AddDocumentConsentRequest": {
+            "type": "object",
+            "required": [
+                "approved_by",
+                "authentic_source",
+                "document_id",
+                "document_type",
+                "scope"
+            ],
+            "properties": {
+                "approved_by": {
+                    "description": "ApprovedBy is the holder who gave the consent, e.g. the authentic source person id",
+                    "type": "string"
+                },
+                "authentic_source": {
+                    "type": "string"
+                },
+                "document_id": {
+                    "type": "string"
+                },
+                "document_type": {
+                    "type": "string"
+                },
+                "scope": {
+                    "description": "Scope is what the consent covers, issuance and/or sharing",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "apiv1.AddDocumentIdentityRequest": {
+            "type": "object",
+            "required": [
+                "authentic_source",
+                "document_id",
+                "document_type",
+                "identities"
+            ],
+            "properties": {
+                "authentic_source": {
+                    "description": "required: true\nexample: SUNET",
+                    "type": "string"
+                },
+                "document_id": {
+                    "description": "required: true\nexample: 7a00fe1a-3e1a-11ef-9272-fb906803d1b8",
+                    "type": "string"
+                },
+                "document_type": {
+                    "description": "required: true\nexample: PDA1",
+                    "type": "string"
+                },
+                "identities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Identity"
+                    }
+                }
+            }
+        },
+        "apiv1.AddWebhookReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.WebhookSubscription"
+                }
+            }
+        },
+        "apiv1.AddWebhookRequest": {
+            "type": "object",
+            "required": [
+                "authentic_source",
+                "secret",
+                "url"
+            ],
+            "properties": {
+                "authentic_source": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "type": "string",
+                    "minLength": 32
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.AuthenticSourceStatistics": {
+            "type": "object",
+            "properties": {
+                "authentic_source": {
+                    "type": "string"
+                },
+                "credentials_failed": {
+                    "type": "integer"
+                },
+                "credentials_issued": {
+                    "type": "integer"
+                },
+                "documents": {
+                    "type": "integer"
+                },
+                "revoked": {
+                    "type": "integer"
+                }
+            }
+        },
+        "apiv1.CredentialNotificationRequest": {
+            "type": "object",
+            "required": [
+                "event",
+                "notification_id"
+            ],
+            "properties": {
+                "event": {
+                    "type": "string",
+                    "enum": [
+                        "credential_accepted",
+                        "credential_failure",
+                        "credential_deleted"
+                    ]
+                },
+                "event_description": {
+                    "type": "string"
+                },
+                "notification_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.CredentialRequest": {
+            "type": "object",
+            "required": [
+                "authentic_source",
+                "collect_id",
+                "credential_type",
+                "document_type",
+                "identity"
+            ],
+            "properties": {
+                "authentic_source": {
+                    "type": "string"
+                },
+                "collect_id": {
+                    "type": "string"
+                },
+                "credential_type": {
+                    "type": "string"
+                },
+                "document_type": {
+                    "type": "string"
+                },
+                "dry_run": {
+                    "description": "DryRun returns the unsigned payload and disclosures instead of a signed credential",
+                    "type": "boolean"
+                },
+                "identity": {
+                    "$ref": "#/definitions/model.Identity"
+                },
+                "proof": {
+                    "description": "Proof is the proof of possession of the holder key, the credential is bound to the key in its cnf claim",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/openid4vci.Proof"
+                        }
+                    ]
+                }
+            }
+        },
+        "apiv1.DeferredCredentialRequest": {
+            "type": "object",
+            "required": [
+                "transaction_id"
+            ],
+            "properties": {
+                "transaction_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.DeleteDocumentIdentityRequest": {
+            "type": "object",
+            "required": [
+                "authentic_source",
+                "authentic_source_person_id",
+                "document_id",
+                "document_type"
+            ],
+            "properties": {
+                "authentic_source": {
+                    "description": "required: true\nexample: SUNET",
+                    "type": "string"
+                },
+                "authentic_source_person_id": {
+                    "description": "required: true\nexample: 83c1a3c8-3e1a-11ef-9c01-6b6642c8d638",
+                    "type": "string"
+                },
+                "document_id": {
+                    "description": "required: true\nexample: 7a00fe1a-3e1a-11ef-9272-fb906803d1b8",
+                    "type": "string"
+                },
+                "document_type": {
+                    "description": "required: true\nexample: PDA1",
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.DeleteDocumentRequest": {
+            "type": "object",
+            "required": [
+                "authentic_source",
+                "document_id",
+                "document_type"
+            ],
+            "properties": {
+                "authentic_source": {
+                    "description": "required: true\nexample: skatteverket",
+                    "type": "string"
+                },
+                "document_id": {
+                    "description": "required: true\nexample: 5e7a981c-c03f-11ee-b116-9b12c59362b9",
+                    "type": "string"
+                },
+                "document_type": {
+                    "description": "required: true\nexample: PDA1",
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.DeleteWebhookRequest": {
+            "type": "object",
+            "required": [
+                "authentic_source",
+                "id"
+            ],
+            "properties": {
+                "authentic_source": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.DocumentChange": {
+            "type": "object",
+            "properties": {
+                "change": {
+                    "description": "Change is created, updated or revoked",
+                    "type": "string"
+                },
+                "meta": {
+                    "$ref": "#/definitions/model.MetaData"
+                }
+            }
+        },
+        "apiv1.DocumentChangesReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/apiv1.DocumentChange"
+                    }
+                },
+                "has_more": {
+                    "description": "HasMore is true if there are more changes, fetch them with next_since right away",
+                    "type": "boolean"
+                },
+                "next_since": {
+                    "description": "NextSince is sent as since by the next sync",
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.DocumentChangesRequest": {
+            "type": "object",
+            "required": [
+                "authentic_source"
+            ],
+            "properties": {
+                "authentic_source": {
+                    "type": "string"
+                },
+                "document_type": {
+                    "type": "string"
+                },
+                "limit": {
+                    "description": "Limit is the most changes in the reply, 100 if not set",
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 1
+                },
+                "since": {
+                    "description": "Since is the next_since of the previous reply, empty for a full sync",
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.DocumentConsentListReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object",
+                    "properties": {
+                        "audit": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ConsentAuditEntry"
+                            }
+                        },
+                        "consents": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.DocumentConsent"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "apiv1.DocumentConsentListRequest": {
+            "type": "object",
+            "required": [
+                "authentic_source",
+                "document_id",
+                "document_type"
+            ],
+            "properties": {
+                "authentic_source": {
+                    "type": "string"
+                },
+                "document_id": {
+                    "type": "string"
+                },
+                "document_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.DocumentDiff": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/jsondiff.Change"
+                    }
+                },
+                "from_version": {
+                    "type": "string"
+                },
+                "to_version": {
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.DocumentDiffReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/apiv1.DocumentDiff"
+                }
+            }
+        },
+        "apiv1.DocumentDiffRequest": {
+            "type": "object",
+            "required": [
+                "authentic_source",
+                "document_id",
+                "document_type",
+                "from_version",
+                "to_version"
+            ],
+            "properties": {
+                "authentic_source": {
+                    "type": "string"
+                },
+                "document_id": {
+                    "type": "string"
+                },
+                "document_type": {
+                    "type": "string"
+                },
+                "from_version": {
+                    "description": "required: true\nexample: \"1.0.0\"",
+                    "type": "string"
+                },
+                "to_version": {
+                    "description": "required: true\nexample: \"1.1.0\"",
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.DocumentListReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DocumentList"
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "apiv1.AddConsentRequest": {
+        },
+        "apiv1.DocumentListRequest": {
+            "type": "object",
+            "required": [
+                "identity"
+            ],
+            "properties": {
+                "authentic_source": {
+                    "type": "string"
+                },
+                "document_type": {
+                    "type": "string"
+                },
+                "identity": {
+                    "$ref": "#/definitions/model.Identity"
+                },
+                "valid_from": {
+                    "type": "integer"
+                },
+                "valid_to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "apiv1.DocumentStatusHistoryReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DocumentStatus"
+                    }
+                }
+            }
+        },
+        "apiv1.DocumentStatusHistoryRequest": {
+            "type": "object",
+            "required": [
+                "authentic_source",
+                "document_id",
+                "document_type"
+            ],
+            "properties": {
+                "authentic_source": {
+                    "type": "string"
+                },
+                "document_id": {
+                    "type": "string"
+                },
+                "document_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.DocumentVersionsReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DocumentVersion"
+                    }
+                }
+            }
+        },
+        "apiv1.DocumentVersionsRequest": {
+            "type": "object",
+            "required": [
+                "authentic_source",
+                "document_id",
+                "document_type"
+            ],
+            "properties": {
+                "authentic_source": {
+                    "type": "string"
+                },
+                "document_id": {
+                    "type": "string"
+                },
+                "document_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.ErasureJobReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.ErasureJob"
+                }
+            }
+        },
+        "apiv1.ExportSubjectDataReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/apiv1.SubjectDataExport"
+                }
+            }
+        },
+        "apiv1.GetConsentRequest": {
             "type": "object",
             "required": [
                 "authentic_source",
@@ -611,371 +2374,584 @@ const docTemplate = `{
                 },
                 "authentic_source_person_id": {
                     "type": "string"
+                }
+            }
+        },
+        "apiv1.GetDocumentCollectIDReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.Document"
+                }
+            }
+        },
+        "apiv1.GetDocumentCollectIDRequest": {
+            "type": "object",
+            "required": [
+                "authentic_source",
+                "collect_id",
+                "document_type",
+                "identity"
+            ],
+            "properties": {
+                "authentic_source": {
+                    "type": "string"
                 },
-                "consent_to": {
+                "collect_id": {
                     "type": "string"
                 },
-                "session_id": {
+                "document_type": {
                     "type": "string"
+                },
+                "identity": {
+                    "$ref": "#/definitions/model.Identity"
                 }
             }
         },
-        "apiv1.AddDocumentIdentityRequest": {
+        "apiv1.GetDocumentReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.Document"
+                }
+            }
+        },
+        "apiv1.GetDocumentRequest": {
             "type": "object",
             "required": [
                 "authentic_source",
                 "document_id",
-                "document_type",
-                "identities"
+                "document_type"
             ],
             "properties": {
                 "authentic_source": {
-                    "description": "required: true\nexample: SUNET",
                     "type": "string"
                 },
                 "document_id": {
-                    "description": "required: true\nexample: 7a00fe1a-3e1a-11ef-9272-fb906803d1b8",
                     "type": "string"
                 },
                 "document_type": {
-                    "description": "required: true\nexample: PDA1",
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.IdentityMappingReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.IDMapping"
+                }
+            }
+        },
+        "apiv1.IdentityMappingRequest": {
+            "type": "object",
+            "required": [
+                "authentic_source",
+                "identity"
+            ],
+            "properties": {
+                "authentic_source": {
+                    "description": "required: true\nexample: SUNET",
                     "type": "string"
                 },
-                "identities": {
+                "identity": {
+                    "$ref": "#/definitions/model.Identity"
+                }
+            }
+        },
+        "apiv1.IdentityReviewDecisionReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.IdentityReview"
+                }
+            }
+        },
+        "apiv1.IdentityReviewDecisionRequest": {
+            "type": "object",
+            "required": [
+                "authentic_source",
+                "decision",
+                "review_id",
+                "reviewer"
+            ],
+            "properties": {
+                "authentic_source": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "decision": {
+                    "description": "Decision approves the collect request, or rejects it",
+                    "type": "string",
+                    "enum": [
+                        "approved",
+                        "rejected"
+                    ]
+                },
+                "review_id": {
+                    "type": "string"
+                },
+                "reviewer": {
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.IdentityReviewListReply": {
+            "type": "object",
+            "properties": {
+                "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.Identity"
+                        "$ref": "#/definitions/model.IdentityReview"
                     }
                 }
             }
         },
-        "apiv1.CredentialRequest": {
+        "apiv1.IdentityReviewListRequest": {
             "type": "object",
             "required": [
-                "authentic_source",
-                "collect_id",
-                "credential_type",
-                "document_type",
-                "identity"
+                "authentic_source"
             ],
             "properties": {
                 "authentic_source": {
                     "type": "string"
                 },
-                "collect_id": {
+                "limit": {
+                    "description": "Limit is the maximum number of reviews, 100 if 0",
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 0
+                },
+                "status": {
+                    "description": "Status of the reviews, pending if empty",
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "approved",
+                        "rejected"
+                    ]
+                }
+            }
+        },
+        "apiv1.ImportDocumentError": {
+            "type": "object",
+            "properties": {
+                "code": {
                     "type": "string"
                 },
-                "credential_type": {
+                "detail": {
                     "type": "string"
                 },
-                "document_type": {
+                "document_id": {
                     "type": "string"
                 },
-                "identity": {
-                    "$ref": "#/definitions/model.Identity"
+                "index": {
+                    "description": "Index of the document in the archive, starting at 0",
+                    "type": "integer"
                 }
             }
         },
-        "apiv1.DeleteDocumentIdentityRequest": {
+        "apiv1.ImportDocumentsReply": {
             "type": "object",
-            "required": [
-                "authentic_source",
-                "authentic_source_person_id",
-                "document_id",
-                "document_type"
-            ],
             "properties": {
-                "authentic_source": {
-                    "description": "required: true\nexample: SUNET",
-                    "type": "string"
+                "dry_run": {
+                    "type": "boolean"
                 },
-                "authentic_source_person_id": {
-                    "description": "required: true\nexample: 83c1a3c8-3e1a-11ef-9c01-6b6642c8d638",
-                    "type": "string"
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/apiv1.ImportDocumentError"
+                    }
                 },
-                "document_id": {
-                    "description": "required: true\nexample: 7a00fe1a-3e1a-11ef-9272-fb906803d1b8",
-                    "type": "string"
+                "imported": {
+                    "type": "integer"
                 },
-                "document_type": {
-                    "description": "required: true\nexample: PDA1",
-                    "type": "string"
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "apiv1.DeleteDocumentRequest": {
+        "apiv1.IssuanceError": {
             "type": "object",
-            "required": [
-                "authentic_source",
-                "document_id",
-                "document_type"
-            ],
             "properties": {
                 "authentic_source": {
-                    "description": "required: true\nexample: skatteverket",
                     "type": "string"
                 },
-                "document_id": {
-                    "description": "required: true\nexample: 5e7a981c-c03f-11ee-b116-9b12c59362b9",
+                "document_type": {
                     "type": "string"
                 },
-                "document_type": {
-                    "description": "required: true\nexample: PDA1",
+                "error": {
                     "type": "string"
+                },
+                "time": {
+                    "type": "integer"
                 }
             }
         },
-        "apiv1.DocumentListReply": {
+        "apiv1.ListWebhooksReply": {
             "type": "object",
             "properties": {
                 "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.DocumentList"
+                        "$ref": "#/definitions/model.WebhookSubscription"
                     }
                 }
             }
         },
-        "apiv1.DocumentListRequest": {
+        "apiv1.ListWebhooksRequest": {
             "type": "object",
             "required": [
-                "identity"
+                "authentic_source"
             ],
             "properties": {
                 "authentic_source": {
                     "type": "string"
-                },
-                "document_type": {
-                    "type": "string"
-                },
-                "identity": {
-                    "$ref": "#/definitions/model.Identity"
-                },
-                "valid_from": {
-                    "type": "integer"
-                },
-                "valid_to": {
-                    "type": "integer"
                 }
             }
         },
-        "apiv1.GetConsentRequest": {
+        "apiv1.MigrateAttachmentsReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object",
+                    "properties": {
+                        "attachments": {
+                            "description": "Attachments is the number of attachments moved to the object storage",
+                            "type": "integer"
+                        },
+                        "documents": {
+                            "description": "Documents is the number of stored documents and document versions that had attachments moved",
+                            "type": "integer"
+                        }
+                    }
+                }
+            }
+        },
+        "apiv1.MigrateAttachmentsRequest": {
             "type": "object",
             "required": [
-                "authentic_source",
-                "authentic_source_person_id"
+                "authentic_source"
             ],
             "properties": {
                 "authentic_source": {
                     "type": "string"
-                },
-                "authentic_source_person_id": {
-                    "type": "string"
                 }
             }
         },
-        "apiv1.GetDocumentCollectIDReply": {
+        "apiv1.NotificationReply": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/model.Document"
+                    "$ref": "#/definitions/model.QR"
                 }
             }
         },
-        "apiv1.GetDocumentCollectIDRequest": {
+        "apiv1.NotificationRequest": {
             "type": "object",
             "required": [
                 "authentic_source",
-                "collect_id",
-                "document_type",
-                "identity"
+                "document_id",
+                "document_type"
             ],
             "properties": {
                 "authentic_source": {
                     "type": "string"
                 },
-                "collect_id": {
+                "document_id": {
                     "type": "string"
                 },
                 "document_type": {
                     "type": "string"
-                },
-                "identity": {
-                    "$ref": "#/definitions/model.Identity"
                 }
             }
         },
-        "apiv1.GetDocumentReply": {
+        "apiv1.RefreshCredentialRequest": {
             "type": "object",
+            "required": [
+                "authentic_source",
+                "notification_id",
+                "trigger"
+            ],
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/model.Document"
+                "authentic_source": {
+                    "type": "string"
+                },
+                "notification_id": {
+                    "description": "NotificationID identifies the credential to refresh, as returned when it was issued",
+                    "type": "string"
+                },
+                "trigger": {
+                    "description": "Trigger is wallet_request when the wallet asks for a new credential, expected_update when the authentic source\nupdated the document the credential was issued from",
+                    "type": "string",
+                    "enum": [
+                        "wallet_request",
+                        "expected_update"
+                    ]
                 }
             }
         },
-        "apiv1.GetDocumentRequest": {
+        "apiv1.RevokeDocumentRequest": {
             "type": "object",
             "required": [
                 "authentic_source",
-                "document_id",
-                "document_type"
+                "document_type",
+                "revocation"
             ],
             "properties": {
-                "authentic_source": {
+                "actor": {
+                    "description": "Actor is recorded in the status history, defaults to the authentic source",
                     "type": "string"
                 },
-                "document_id": {
+                "authentic_source": {
                     "type": "string"
                 },
                 "document_type": {
                     "type": "string"
+                },
+                "revocation": {
+                    "$ref": "#/definitions/model.Revocation"
                 }
             }
         },
-        "apiv1.IdentityMappingReply": {
+        "apiv1.RotateEncryptionKeysReply": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/model.IDMapping"
+                    "type": "object",
+                    "properties": {
+                        "rewrapped": {
+                            "type": "integer"
+                        }
+                    }
                 }
             }
         },
-        "apiv1.IdentityMappingRequest": {
+        "apiv1.RotateEncryptionKeysRequest": {
             "type": "object",
             "required": [
-                "authentic_source",
-                "identity"
+                "authentic_source"
             ],
             "properties": {
                 "authentic_source": {
-                    "description": "required: true\nexample: SUNET",
                     "type": "string"
-                },
-                "identity": {
-                    "$ref": "#/definitions/model.Identity"
                 }
             }
         },
-        "apiv1.NotificationReply": {
+        "apiv1.StatisticsReply": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/model.QR"
+                "authentic_sources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/apiv1.AuthenticSourceStatistics"
+                    }
+                },
+                "queue_depth": {
+                    "description": "QueueDepth is the number of uploads waiting in kafka, omitted when kafka is disabled or unreachable",
+                    "type": "integer"
+                },
+                "recent_errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/apiv1.IssuanceError"
+                    }
+                },
+                "timestamp": {
+                    "type": "integer"
                 }
             }
         },
-        "apiv1.NotificationRequest": {
+        "apiv1.SubjectDataExport": {
             "type": "object",
-            "required": [
-                "authentic_source",
-                "document_id",
-                "document_type"
-            ],
             "properties": {
                 "authentic_source": {
                     "type": "string"
                 },
-                "document_id": {
+                "authentic_source_person_id": {
                     "type": "string"
                 },
-                "document_type": {
-                    "type": "string"
+                "consent": {
+                    "$ref": "#/definitions/model.Consent"
+                },
+                "consent_audit": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ConsentAuditEntry"
+                    }
+                },
+                "credential_notifications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CredentialNotification"
+                    }
+                },
+                "deferred_credentials": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DeferredCredential"
+                    }
+                },
+                "document_consents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DocumentConsent"
+                    }
+                },
+                "documents": {
+                    "description": "Documents with identities and status history",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CompleteDocument"
+                    }
+                },
+                "exported_at": {
+                    "description": "format: int64",
+                    "type": "integer"
                 }
             }
         },
-        "apiv1.RevokeDocumentRequest": {
+        "apiv1.SubjectDataRequest": {
             "type": "object",
             "required": [
                 "authentic_source",
-                "document_type",
-                "revocation"
+                "authentic_source_person_id"
             ],
             "properties": {
                 "authentic_source": {
+                    "description": "required: true\nexample: SUNET",
                     "type": "string"
                 },
-                "document_type": {
+                "authentic_source_person_id": {
+                    "description": "required: true\nexample: 65636cbc-c03f-11ee-8dc4-67135cc9bd8a",
                     "type": "string"
-                },
-                "revocation": {
-                    "$ref": "#/definitions/model.Revocation"
                 }
             }
         },
-        "apiv1.RevokeReply": {
+        "apiv1.TrustChainReply": {
             "type": "object",
             "properties": {
-                "data": {
-                    "type": "object",
-                    "properties": {
-                        "status": {
-                            "type": "boolean"
-                        }
+                "trust_chain": {
+                    "description": "TrustChain starts with the entity configuration and ends with the entity configuration of a trust anchor",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
                 }
             }
         },
-        "apiv1.RevokeRequest": {
+        "apiv1.UploadRequest": {
             "type": "object",
+            "required": [
+                "document_data",
+                "document_data_version",
+                "meta"
+            ],
             "properties": {
-                "authentic_source": {
+                "document_data": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "document_data_version": {
                     "type": "string"
                 },
-                "document_id": {
+                "document_display": {
+                    "$ref": "#/definitions/model.DocumentDisplay"
+                },
+                "identities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Identity"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/model.MetaData"
+                }
+            }
+        },
+        "apiv1.WebhookDeliveriesReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.WebhookDelivery"
+                    }
+                }
+            }
+        },
+        "apiv1.WebhookDeliveriesRequest": {
+            "type": "object",
+            "required": [
+                "authentic_source",
+                "id"
+            ],
+            "properties": {
+                "authentic_source": {
                     "type": "string"
                 },
-                "document_type": {
+                "id": {
                     "type": "string"
                 },
-                "revocation_id": {
-                    "type": "string"
+                "limit": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 1
                 }
             }
         },
-        "apiv1.UploadRequest": {
+        "apiv1.WithdrawDocumentConsentRequest": {
             "type": "object",
             "required": [
-                "document_data",
-                "document_data_version",
-                "meta"
+                "authentic_source",
+                "consent_id",
+                "document_id",
+                "document_type"
             ],
             "properties": {
-                "document_data": {
-                    "type": "object",
-                    "additionalProperties": {}
-                },
-                "document_data_version": {
+                "authentic_source": {
                     "type": "string"
                 },
-                "document_display": {
-                    "$ref": "#/definitions/model.DocumentDisplay"
+                "consent_id": {
+                    "type": "string"
                 },
-                "identities": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.Identity"
-                    }
+                "document_id": {
+                    "type": "string"
                 },
-                "meta": {
-                    "$ref": "#/definitions/model.MetaData"
+                "document_type": {
+                    "type": "string"
                 }
             }
         },
         "apiv1_issuer.Jwk": {
             "type": "object",
             "properties": {
+                "alg": {
+                    "type": "string"
+                },
                 "crv": {
                     "type": "string"
                 },
                 "d": {
                     "type": "string"
                 },
+                "e": {
+                    "type": "string"
+                },
                 "kid": {
                     "type": "string"
                 },
                 "kty": {
                     "type": "string"
                 },
+                "n": {
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string"
+                },
                 "x": {
                     "type": "string"
                 },
@@ -1017,23 +2993,73 @@ const docTemplate = `{
                 },
                 "jwt": {
                     "type": "string"
+                },
+                "notificationID": {
+                    "description": "notificationID is set by the apigw, for the wallet to report what became of the credential",
+                    "type": "string"
+                },
+                "payload": {
+                    "description": "payload is the JSON encoded unsigned payload, set on a dry run",
+                    "type": "string"
+                },
+                "transactionID": {
+                    "description": "transactionID is set by the apigw when the credential is deferred, for the wallet to collect it later",
+                    "type": "string"
                 }
             }
         },
-        "helpers.Error": {
+        "helpers.Problem": {
             "type": "object",
             "properties": {
-                "details": {},
+                "code": {
+                    "description": "Code is a stable, machine-readable error code",
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "errors": {
+                    "description": "Errors holds field-level errors, e.g. from validation",
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "additionalProperties": {}
+                    }
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
                 "title": {
                     "type": "string"
+                },
+                "trace_id": {
+                    "description": "TraceID of the request, for correlation with logs and traces",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
                 }
             }
         },
-        "helpers.ErrorResponse": {
+        "jsondiff.Change": {
             "type": "object",
             "properties": {
-                "error": {
-                    "$ref": "#/definitions/helpers.Error"
+                "from": {
+                    "description": "From is the value before the change, left out when the value is added"
+                },
+                "op": {
+                    "description": "required: true\nexample: changed",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Path is the JSON pointer, RFC 6901, of the changed value\nrequired: true\nexample: /person/given_name",
+                    "type": "string"
+                },
+                "to": {
+                    "description": "To is the value after the change, left out when the value is removed"
                 }
             }
         },
@@ -1050,6 +3076,48 @@ const docTemplate = `{
                 }
             }
         },
+        "model.CompleteDocument": {
+            "type": "object",
+            "required": [
+                "document_data",
+                "document_data_version",
+                "document_display",
+                "identities",
+                "meta"
+            ],
+            "properties": {
+                "document_data": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "document_data_version": {
+                    "description": "required: true\nexample: \"1.0.0\"",
+                    "type": "string"
+                },
+                "document_display": {
+                    "$ref": "#/definitions/model.DocumentDisplay"
+                },
+                "identities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Identity"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/model.MetaData"
+                },
+                "qr": {
+                    "$ref": "#/definitions/model.QR"
+                },
+                "status_history": {
+                    "description": "StatusHistory holds the status changes of the document, oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DocumentStatus"
+                    }
+                }
+            }
+        },
         "model.Consent": {
             "type": "object",
             "required": [
@@ -1072,6 +3140,163 @@ const docTemplate = `{
                 }
             }
         },
+        "model.ConsentAuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Action is created or withdrawn",
+                    "type": "string"
+                },
+                "approved_by": {
+                    "type": "string"
+                },
+                "consent_id": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.CredentialNotification": {
+            "type": "object",
+            "properties": {
+                "authentic_source": {
+                    "description": "required: true\nexample: SUNET",
+                    "type": "string"
+                },
+                "credential_type": {
+                    "description": "CredentialType is the credential type the credential was requested as, reused when it is refreshed\nexample: urn:eudi:pda1:1",
+                    "type": "string"
+                },
+                "document_id": {
+                    "description": "required: true\nexample: 5e7a981c-c03f-11ee-b116-9b12c59362b9",
+                    "type": "string"
+                },
+                "document_type": {
+                    "description": "required: true\nexample: PDA1",
+                    "type": "string"
+                },
+                "events": {
+                    "description": "Events are the wallet reported events, oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CredentialNotificationEvent"
+                    }
+                },
+                "issued_at": {
+                    "description": "required: true\nexample: 509567558\nformat: int64",
+                    "type": "integer"
+                },
+                "notification_id": {
+                    "description": "required: true\nexample: 3fbc6a6e-0d51-4a0b-9d5e-1c9f4f0e7d2a",
+                    "type": "string"
+                },
+                "refresh_of": {
+                    "description": "RefreshOf is the notification_id of the credential this one refreshes\nexample: 3fbc6a6e-0d51-4a0b-9d5e-1c9f4f0e7d2a",
+                    "type": "string"
+                },
+                "refresh_trigger": {
+                    "description": "RefreshTrigger tells why the credential was refreshed, wallet_request or expected_update\nexample: wallet_request",
+                    "type": "string"
+                },
+                "refreshed_by": {
+                    "description": "RefreshedBy is the notification_id of the credential that replaced this one",
+                    "type": "string"
+                }
+            }
+        },
+        "model.CredentialNotificationEvent": {
+            "type": "object",
+            "properties": {
+                "event": {
+                    "description": "Event is one of credential_accepted, credential_failure or credential_deleted\nrequired: true\nexample: credential_accepted",
+                    "type": "string"
+                },
+                "event_description": {
+                    "description": "required: false\nexample: Could not store the credential",
+                    "type": "string"
+                },
+                "timestamp": {
+                    "description": "required: true\nexample: 509567558\nformat: int64",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.DeferredCredential": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "Attempts is the number of failed document collection retries\nrequired: true\nexample: 3",
+                    "type": "integer"
+                },
+                "authentic_source": {
+                    "description": "required: true\nexample: SUNET",
+                    "type": "string"
+                },
+                "collect_id": {
+                    "description": "required: true\nexample: 98fe67fc-c03f-11ee-bbee-4345224d414f",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "required: true\nexample: 509567558\nformat: int64",
+                    "type": "integer"
+                },
+                "credential": {
+                    "description": "Credential is set once the status is issued\nrequired: false",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.DeferredCredentialResult"
+                        }
+                    ]
+                },
+                "credential_type": {
+                    "description": "required: true\nexample: sdjwt",
+                    "type": "string"
+                },
+                "document_type": {
+                    "description": "required: true\nexample: PDA1",
+                    "type": "string"
+                },
+                "identity": {
+                    "description": "required: true",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Identity"
+                        }
+                    ]
+                },
+                "status": {
+                    "description": "Status is one of pending, issued or failed\nrequired: true\nexample: pending",
+                    "type": "string"
+                },
+                "transaction_id": {
+                    "description": "required: true\nexample: 8c4c1d35-5b5e-4a4e-8d3b-2f5f4e1a9c7e",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "required: false\nexample: 509567558\nformat: int64",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.DeferredCredentialResult": {
+            "type": "object",
+            "properties": {
+                "disclosures": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "jwt": {
+                    "type": "string"
+                },
+                "notification_id": {
+                    "type": "string"
+                }
+            }
+        },
         "model.Document": {
             "type": "object",
             "required": [
@@ -1085,6 +3310,56 @@ const docTemplate = `{
                 }
             }
         },
+        "model.DocumentConsent": {
+            "type": "object",
+            "required": [
+                "approved_by",
+                "authentic_source",
+                "consent_id",
+                "created_at",
+                "document_id",
+                "document_type",
+                "scope"
+            ],
+            "properties": {
+                "approved_by": {
+                    "description": "ApprovedBy is the holder who gave the consent, e.g. the authentic source person id\nrequired: true\nexample: 65636cbc-c03f-11ee-8dc4-67135cc9bd8a",
+                    "type": "string"
+                },
+                "authentic_source": {
+                    "description": "required: true\nexample: SUNET",
+                    "type": "string"
+                },
+                "consent_id": {
+                    "description": "required: true\nexample: 0b8f3c5e-8e0a-4d7b-9f2a-6a1f2c3d4e5f",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "required: true\nexample: 509567558\nformat: int64",
+                    "type": "integer"
+                },
+                "document_id": {
+                    "description": "required: true\nexample: 5e7a981c-c03f-11ee-b116-9b12c59362b9",
+                    "type": "string"
+                },
+                "document_type": {
+                    "description": "required: true\nexample: PDA1",
+                    "type": "string"
+                },
+                "scope": {
+                    "description": "Scope is what the consent covers, issuance and/or sharing\nrequired: true\nexample: [\"issuance\"]",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "withdrawn_at": {
+                    "description": "WithdrawnAt is zero while the consent is active\nrequired: false\nexample: 509567558\nformat: int64",
+                    "type": "integer"
+                }
+            }
+        },
         "model.DocumentDisplay": {
             "type": "object",
             "required": [
@@ -1126,6 +3401,135 @@ const docTemplate = `{
                 }
             }
         },
+        "model.DocumentStatus": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "description": "Actor is who changed the status\nrequired: true\nexample: SUNET",
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "required: false\nexample: lost or stolen",
+                    "type": "string"
+                },
+                "reason_code": {
+                    "description": "required: false\nexample: key_compromise",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "required: true\nexample: revoked",
+                    "type": "string"
+                },
+                "timestamp": {
+                    "description": "Timestamp is when the status takes effect\nrequired: true\nexample: 509567558\nformat: int64",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.DocumentVersion": {
+            "type": "object",
+            "properties": {
+                "authentic_source": {
+                    "description": "required: true\nexample: SUNET",
+                    "type": "string"
+                },
+                "document_data": {
+                    "description": "DocumentData is left out when versions are listed",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "document_data_version": {
+                    "description": "required: true\nexample: \"1.0.0\"",
+                    "type": "string"
+                },
+                "document_id": {
+                    "description": "required: true\nexample: 5e7a981c-c03f-11ee-b116-9b12c59362b9",
+                    "type": "string"
+                },
+                "document_type": {
+                    "description": "required: true\nexample: PDA1",
+                    "type": "string"
+                },
+                "uploaded_at": {
+                    "description": "UploadedAt is when the version was uploaded\nrequired: true\nexample: 509567558\nformat: int64",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ErasureCounts": {
+            "type": "object",
+            "properties": {
+                "consent_audit_entries": {
+                    "description": "ConsentAuditEntries pseudonymized, the entries are kept",
+                    "type": "integer"
+                },
+                "consents": {
+                    "type": "integer"
+                },
+                "credential_notifications": {
+                    "type": "integer"
+                },
+                "deferred_credentials": {
+                    "type": "integer"
+                },
+                "document_consents": {
+                    "type": "integer"
+                },
+                "documents": {
+                    "description": "Documents deleted, the subject was their only identity",
+                    "type": "integer"
+                },
+                "identities": {
+                    "description": "Identities removed from documents shared with other identities",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ErasureJob": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "Attempts is the number of times the job has been run\nrequired: true\nexample: 1",
+                    "type": "integer"
+                },
+                "authentic_source": {
+                    "description": "required: true\nexample: SUNET",
+                    "type": "string"
+                },
+                "completed_at": {
+                    "description": "required: false\nexample: 509567558\nformat: int64",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "description": "required: true\nexample: 509567558\nformat: int64",
+                    "type": "integer"
+                },
+                "erased": {
+                    "description": "required: true",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ErasureCounts"
+                        }
+                    ]
+                },
+                "error": {
+                    "description": "Error of the last failed attempt, a failed job can be submitted again since erasure is idempotent\nrequired: false\nexample: context deadline exceeded",
+                    "type": "string"
+                },
+                "job_id": {
+                    "description": "required: true\nexample: 2b1d8f5c-9a3e-4f7b-8c6d-1e2f3a4b5c6d",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status is one of pending, running, completed or failed\nrequired: true\nexample: completed",
+                    "type": "string"
+                },
+                "subject_hash": {
+                    "description": "SubjectHash is the SHA-256 of the authentic source person id, as kept in the consent audit\nrequired: true\nexample: sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+                    "type": "string"
+                }
+            }
+        },
         "model.IDMapping": {
             "type": "object",
             "properties": {
@@ -1221,6 +3625,74 @@ const docTemplate = `{
                 }
             }
         },
+        "model.IdentityReview": {
+            "type": "object",
+            "properties": {
+                "authentic_source": {
+                    "description": "required: true\nexample: SUNET",
+                    "type": "string"
+                },
+                "authentic_source_person_id": {
+                    "description": "AuthenticSourcePersonID of the best matching identity of the document\nrequired: false\nexample: 65636cbc-c03f-11ee-8dc4-67135cc9bd8a",
+                    "type": "string"
+                },
+                "collect_id": {
+                    "description": "required: true\nexample: 98fe67fc-c03f-11ee-bbee-4345224d414f",
+                    "type": "string"
+                },
+                "comment": {
+                    "description": "required: false",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "required: true\nexample: 509567558\nformat: int64",
+                    "type": "integer"
+                },
+                "decided_at": {
+                    "description": "required: false\nexample: 509567558\nformat: int64",
+                    "type": "integer"
+                },
+                "document_id": {
+                    "description": "required: true\nexample: 7a00fe1a-3e1a-11ef-9272-fb906803d1b8",
+                    "type": "string"
+                },
+                "document_type": {
+                    "description": "required: true\nexample: PDA1",
+                    "type": "string"
+                },
+                "field_scores": {
+                    "description": "FieldScores are the scores of family_name, given_name and birth_date\nrequired: true",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "identity": {
+                    "description": "Identity is the identity of the collect request\nrequired: true",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Identity"
+                        }
+                    ]
+                },
+                "review_id": {
+                    "description": "required: true\nexample: 0b7d6a0e-8f3c-4d0a-9a53-3c1f9a4d2e61",
+                    "type": "string"
+                },
+                "reviewer": {
+                    "description": "required: false\nexample: admin",
+                    "type": "string"
+                },
+                "score": {
+                    "description": "Score of the best matching identity, from 0 to 1\nrequired: true\nexample: 0.84",
+                    "type": "number"
+                },
+                "status": {
+                    "description": "Status is one of pending, approved or rejected\nrequired: true\nexample: pending",
+                    "type": "string"
+                }
+            }
+        },
         "model.IdentitySchema": {
             "type": "object",
             "required": [
@@ -1267,20 +3739,24 @@ const docTemplate = `{
                 },
                 "document_type": {
                     "description": "required: true\nexample: PDA1",
-                    "type": "string",
-                    "enum": [
-                        "PDA1",
-                        "EHIC"
-                    ]
+                    "type": "string"
                 },
                 "document_version": {
                     "description": "required: true\nexample: \"1.0.0\"",
                     "type": "string"
                 },
+                "modified_at": {
+                    "description": "ModifiedAt is the unix time the document was last created, updated or revoked, set by apigw and ignored if sent by the client\nrequired: false\nexample: 509567558\nformat: int64",
+                    "type": "integer"
+                },
                 "real_data": {
                     "description": "RealData is a flag to indicate if the document contains real data\nrequired: true\nexample: true",
                     "type": "boolean"
                 },
+                "revision": {
+                    "description": "Revision is incremented on every update of the document, and is returned as ETag\nrequired: false\nexample: 1\nformat: int64",
+                    "type": "integer"
+                },
                 "revocation": {
                     "description": "Revocation is a collection of fields representing a revocation",
                     "allOf": [
@@ -1288,6 +3764,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.Revocation"
                         }
                     ]
+                },
+                "signed_by": {
+                    "description": "SignedBy is the key a signed upload was verified with, set by apigw and ignored if sent by the client\nrequired: false",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.UploadSigner"
+                        }
+                    ]
                 }
             }
         },
@@ -1319,6 +3803,18 @@ const docTemplate = `{
                     "description": "Reason is the reason for revocation\nrequired: false\nexample: lost or stolen",
                     "type": "string"
                 },
+                "reason_code": {
+                    "description": "ReasonCode is the reason for revocation, as a CRL reason code\nrequired: false\nexample: key_compromise",
+                    "type": "string",
+                    "enum": [
+                        "unspecified",
+                        "key_compromise",
+                        "affiliation_changed",
+                        "superseded",
+                        "cessation_of_operation",
+                        "privilege_withdrawn"
+                    ]
+                },
                 "reference": {
                     "$ref": "#/definitions/model.RevocationReference"
                 },
@@ -1345,6 +3841,148 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "model.UploadSigner": {
+            "type": "object",
+            "properties": {
+                "authentic_source": {
+                    "description": "AuthenticSource the key is registered for",
+                    "type": "string"
+                },
+                "jws": {
+                    "description": "JWS is the verified compact JWS with its payload, the request body as sent",
+                    "type": "string"
+                },
+                "kid": {
+                    "description": "KID of the key in the JWKS of the authentic source",
+                    "type": "string"
+                },
+                "signed_at": {
+                    "description": "SignedAt is the iat of the signature, unix time",
+                    "type": "integer"
+                },
+                "thumbprint": {
+                    "description": "Thumbprint is the base64url encoded RFC 7638 SHA-256 thumbprint of the key",
+                    "type": "string"
+                }
+            }
+        },
+        "model.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "required: true\nexample: 1",
+                    "type": "integer"
+                },
+                "delivered_at": {
+                    "description": "required: false\nexample: 509567558\nformat: int64",
+                    "type": "integer"
+                },
+                "event": {
+                    "description": "required: true",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.WebhookEvent"
+                        }
+                    ]
+                },
+                "last_error": {
+                    "description": "LastError of the latest failed attempt\nrequired: false\nexample: webhook responded with status 503",
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "description": "NextAttemptAt is when a pending delivery is tried next\nrequired: false\nexample: 509567558\nformat: int64",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "Status is one of pending, delivered or failed\nrequired: true\nexample: delivered",
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "description": "required: true\nexample: 0f3c3c5e-7c37-4d0e-a3a7-2f1f5b8f7f9a",
+                    "type": "string"
+                }
+            }
+        },
+        "model.WebhookEvent": {
+            "type": "object",
+            "properties": {
+                "authentic_source": {
+                    "description": "required: true\nexample: SUNET",
+                    "type": "string"
+                },
+                "data": {
+                    "description": "Data holds event specific fields\nrequired: false",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "document_id": {
+                    "description": "required: true\nexample: 5e7a981c-c03f-11ee-b116-9b12c59362b9",
+                    "type": "string"
+                },
+                "document_type": {
+                    "description": "required: true\nexample: PDA1",
+                    "type": "string"
+                },
+                "event": {
+                    "description": "required: true\nexample: document_uploaded",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID is the delivery id, also sent as X-Webhook-Delivery\nrequired: true\nexample: 6d5b7c1e-3e2f-4c4b-9d0a-8e1f2a3b4c5d",
+                    "type": "string"
+                },
+                "timestamp": {
+                    "description": "required: true\nexample: 509567558\nformat: int64",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.WebhookSubscription": {
+            "type": "object",
+            "properties": {
+                "authentic_source": {
+                    "description": "required: true\nexample: SUNET",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "required: true\nexample: 509567558\nformat: int64",
+                    "type": "integer"
+                },
+                "events": {
+                    "description": "Events the subscription is called on, all events if empty\nrequired: false\nexample: [\"document_uploaded\",\"document_revoked\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "description": "required: true\nexample: 0f3c3c5e-7c37-4d0e-a3a7-2f1f5b8f7f9a",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "required: true\nexample: https://hooks.example.com/vc",
+                    "type": "string"
+                }
+            }
+        },
+        "openid4vci.Proof": {
+            "type": "object",
+            "required": [
+                "jwt",
+                "proof_type"
+            ],
+            "properties": {
+                "jwt": {
+                    "type": "string"
+                },
+                "proof_type": {
+                    "type": "string",
+                    "enum": [
+                        "jwt"
+                    ]
+                }
+            }
         }
     }
 }`
@@ -15,9 +15,73 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/revoke": {
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "JSON Web Key Set with the public keys of all configured signing keys, credentials carry the kid of their key in the header",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "issuer"
+                ],
+                "summary": "JWKS",
+                "operationId": "issuer-jwks",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1_issuer.Keys"
+                        }
+                    }
+                }
+            }
+        },
+        "/auditlog/export": {
+            "get": {
+                "description": "Exports the hash chained audit log entries within a time range, with a signature over the batch",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "issuer"
+                ],
+                "summary": "Export audit log",
+                "operationId": "issuer-export-audit-log",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "RFC 3339, inclusive",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339, exclusive",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.ExportAuditLogReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/credential/cwt": {
             "post": {
-                "description": "Revoke endpoint",
+                "description": "Creates a credential as CBOR Web Token, a COSE_Sign1 message signed with the issuer key. The claims are not selectively disclosable. With a proof the credential is bound to the holder key in its cnf claim.",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,10 +89,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "dc4eu"
+                    "issuer"
                 ],
-                "summary": "Revoke",
-                "operationId": "generic-revoke",
+                "summary": "Create CWT credential",
+                "operationId": "issuer-create-cwt",
                 "parameters": [
                     {
                         "description": " ",
@@ -36,7 +100,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/apiv1.RevokeRequest"
+                            "$ref": "#/definitions/apiv1.CreateCWTRequest"
                         }
                     }
                 ],
@@ -44,13 +108,13 @@ const docTemplate = `{
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/apiv1.RevokeReply"
+                            "$ref": "#/definitions/apiv1.CreateCWTReply"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/helpers.ErrorResponse"
+                            "$ref": "#/definitions/helpers.Problem"
                         }
                     }
                 }
@@ -58,50 +122,199 @@ const docTemplate = `{
         }
     },
     "definitions": {
-        "apiv1.RevokeReply": {
+        "apiv1.CWTCredential": {
+            "type": "object",
+            "properties": {
+                "cwt": {
+                    "description": "CWT is the base64url encoded COSE_Sign1 message, without padding",
+                    "type": "string"
+                },
+                "kid": {
+                    "description": "KID of the issuer key the CWT is signed with, also in the unprotected header",
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.CreateCWTReply": {
             "type": "object",
             "properties": {
                 "data": {
+                    "$ref": "#/definitions/apiv1.CWTCredential"
+                }
+            }
+        },
+        "apiv1.CreateCWTRequest": {
+            "type": "object",
+            "required": [
+                "document_data",
+                "document_type"
+            ],
+            "properties": {
+                "consent_ids": {
+                    "description": "ConsentIDs references the holder consents the credential is issued under",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "credential_type": {
+                    "description": "CredentialType selects the credential profile, the profile of the document type if empty",
+                    "type": "string"
+                },
+                "document_data": {
                     "type": "object",
-                    "properties": {
-                        "status": {
-                            "type": "boolean"
+                    "additionalProperties": {}
+                },
+                "document_type": {
+                    "type": "string"
+                },
+                "proof": {
+                    "description": "Proof is the proof of possession of the holder key, the credential is bound to the key in its cnf claim",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/openid4vci.Proof"
                         }
+                    ]
+                }
+            }
+        },
+        "apiv1.ExportAuditLogReply": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/auditlog.AuditLog"
                     }
+                },
+                "signature": {
+                    "description": "Signature is a JWT signed with the active issuer key, its digest claim is the hex encoded SHA-256 of the JSON encoded entries",
+                    "type": "string"
                 }
             }
         },
-        "apiv1.RevokeRequest": {
+        "apiv1_issuer.Jwk": {
             "type": "object",
             "properties": {
-                "authentic_source": {
+                "alg": {
                     "type": "string"
                 },
-                "document_id": {
+                "crv": {
                     "type": "string"
                 },
-                "document_type": {
+                "d": {
+                    "type": "string"
+                },
+                "e": {
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "kty": {
+                    "type": "string"
+                },
+                "n": {
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string"
+                },
+                "x": {
+                    "type": "string"
+                },
+                "y": {
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1_issuer.Keys": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/apiv1_issuer.Jwk"
+                    }
+                }
+            }
+        },
+        "auditlog.AuditLog": {
+            "type": "object",
+            "properties": {
+                "date": {
                     "type": "string"
                 },
-                "revocation_id": {
+                "event": {
                     "type": "string"
+                },
+                "hash": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "message": {},
+                "prev_hash": {
+                    "type": "string"
+                },
+                "seq": {
+                    "description": "Seq, PrevHash and Hash chain the entries, see VerifyChain",
+                    "type": "integer"
                 }
             }
         },
-        "helpers.Error": {
+        "helpers.Problem": {
             "type": "object",
             "properties": {
-                "details": {},
+                "code": {
+                    "description": "Code is a stable, machine-readable error code",
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "errors": {
+                    "description": "Errors holds field-level errors, e.g. from validation",
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "additionalProperties": {}
+                    }
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
                 "title": {
                     "type": "string"
+                },
+                "trace_id": {
+                    "description": "TraceID of the request, for correlation with logs and traces",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
                 }
             }
         },
-        "helpers.ErrorResponse": {
+        "openid4vci.Proof": {
             "type": "object",
+            "required": [
+                "jwt",
+                "proof_type"
+            ],
             "properties": {
-                "error": {
-                    "$ref": "#/definitions/helpers.Error"
+                "jwt": {
+                    "type": "string"
+                },
+                "proof_type": {
+                    "type": "string",
+                    "enum": [
+                        "jwt"
+                    ]
                 }
             }
         }
@@ -112,7 +325,7 @@ const docTemplate = `{
 var SwaggerInfo = &swag.Spec{
 	Version:          "0.1.0",
 	Host:             "",
-	BasePath:         "/issuer/api/v1",
+	BasePath:         "/api/v1",
 	Schemes:          []string{},
 	Title:            "Issuer API",
 	Description:      "",
@@ -15,9 +15,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/ladok/pdf/sign": {
+        "/inclusion_proof": {
             "post": {
-                "description": "validates an entity in the registry",
+                "description": "Leaf index, audit path and signed tree head for an entity, verifiable offline",
                 "consumes": [
                     "application/json"
                 ],
@@ -27,8 +27,8 @@ const docTemplate = `{
                 "tags": [
                     "registry"
                 ],
-                "summary": "Validate entity",
-                "operationId": "registry-validate",
+                "summary": "Inclusion proof",
+                "operationId": "registry-inclusion-proof",
                 "parameters": [
                     {
                         "description": " ",
@@ -36,7 +36,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/apiv1_registry.ValidateRequest"
+                            "$ref": "#/definitions/apiv1.InclusionProofRequest"
                         }
                     }
                 ],
@@ -44,13 +44,81 @@ const docTemplate = `{
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/apiv1.ValidateReply"
+                            "$ref": "#/definitions/apiv1.InclusionProofReply"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/helpers.ErrorResponse"
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/statuslists/token": {
+            "get": {
+                "description": "Token status list referenced from the status claim of SD-JWT VCs",
+                "produces": [
+                    "application/statuslist+jwt",
+                    "application/statuslist+cwt"
+                ],
+                "tags": [
+                    "registry"
+                ],
+                "summary": "Token status list",
+                "operationId": "registry-token-status-list",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/statuslists/{purpose}": {
+            "get": {
+                "description": "Bitstring status list credential for a status purpose",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/vc+jwt"
+                ],
+                "tags": [
+                    "registry"
+                ],
+                "summary": "Bitstring status list",
+                "operationId": "registry-bitstring-status-list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "revocation or suspension",
+                        "name": "purpose",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/statuslist.BitstringStatusListCredential"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
                         }
                     }
                 }
@@ -58,44 +126,127 @@ const docTemplate = `{
         }
     },
     "definitions": {
-        "apiv1.ValidateReply": {
+        "apiv1.InclusionProofReply": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/apiv1_registry.ValidateReply"
+                    "$ref": "#/definitions/merkleproof.InclusionProof"
                 }
             }
         },
-        "apiv1_registry.ValidateReply": {
+        "apiv1.InclusionProofRequest": {
             "type": "object",
+            "required": [
+                "entity"
+            ],
             "properties": {
-                "Valid": {
-                    "type": "boolean"
+                "entity": {
+                    "type": "string"
                 }
             }
         },
-        "apiv1_registry.ValidateRequest": {
+        "helpers.Problem": {
             "type": "object",
             "properties": {
-                "Entity": {
+                "code": {
+                    "description": "Code is a stable, machine-readable error code",
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "errors": {
+                    "description": "Errors holds field-level errors, e.g. from validation",
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "additionalProperties": {}
+                    }
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "trace_id": {
+                    "description": "TraceID of the request, for correlation with logs and traces",
+                    "type": "string"
+                },
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "helpers.Error": {
+        "merkleproof.InclusionProof": {
             "type": "object",
             "properties": {
-                "details": {},
-                "title": {
+                "audit_path": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "leaf_index": {
+                    "type": "integer"
+                },
+                "signed_tree_head": {
                     "type": "string"
                 }
             }
         },
-        "helpers.ErrorResponse": {
+        "statuslist.BitstringStatusList": {
             "type": "object",
             "properties": {
-                "error": {
-                    "$ref": "#/definitions/helpers.Error"
+                "encodedList": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "statusPurpose": {
+                    "type": "string"
+                },
+                "ttl": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "statuslist.BitstringStatusListCredential": {
+            "type": "object",
+            "properties": {
+                "@context": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "credentialSubject": {
+                    "$ref": "#/definitions/statuslist.BitstringStatusList"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "issuer": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "validFrom": {
+                    "type": "string"
+                },
+                "validUntil": {
+                    "type": "string"
                 }
             }
         }
@@ -106,7 +257,7 @@ const docTemplate = `{
 var SwaggerInfo = &swag.Spec{
 	Version:          "0.1.0",
 	Host:             "",
-	BasePath:         "/api/v1",
+	BasePath:         "/",
 	Schemes:          []string{},
 	Title:            "Registry API",
 	Description:      "",
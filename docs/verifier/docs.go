@@ -14,16 +14,998 @@ const docTemplate = `{
     },
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
-    "paths": {}
+    "paths": {
+        "/analytics/daily": {
+            "get": {
+                "description": "Number of valid and invalid verifications and the average latency per UTC day, days without verifications are left out",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "verifier"
+                ],
+                "summary": "Daily analytics",
+                "operationId": "verifier-analytics-daily",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day, YYYY-MM-DD, 30 days before to if empty",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, YYYY-MM-DD, today if empty",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.DailyAnalyticsReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/analytics/failures": {
+            "get": {
+                "description": "Number of failed verifications per reason, most frequent first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "verifier"
+                ],
+                "summary": "Failure analytics",
+                "operationId": "verifier-analytics-failures",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day, YYYY-MM-DD, 30 days before to if empty",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, YYYY-MM-DD, today if empty",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.FailureAnalyticsReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/analytics/issuers": {
+            "get": {
+                "description": "Issuers ranked by the number of verified credentials, with the number of valid and invalid verifications",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "verifier"
+                ],
+                "summary": "Issuer analytics",
+                "operationId": "verifier-analytics-issuers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day, YYYY-MM-DD, 30 days before to if empty",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, YYYY-MM-DD, today if empty",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of issuers, at most 100, 10 if empty",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.IssuerAnalyticsReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/audit": {
+            "get": {
+                "description": "The audit trail of verifications and presentation sessions, newest first. Each entry has what the relying party requested, what the wallet presented and the outcome with the failed policy rules. Values in failure reasons are redacted and claims are only named. With Accept: text/csv the page is returned as CSV.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "verifier"
+                ],
+                "summary": "Audit",
+                "operationId": "verifier-audit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day, YYYY-MM-DD, 30 days before to if empty",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, YYYY-MM-DD, today if empty",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only verifications of presentation sessions",
+                        "name": "sessions_only",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only valid or invalid verifications",
+                        "name": "valid",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only verifications of this vct",
+                        "name": "credential_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Entries to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of entries, at most 10000, 100 if empty",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.AuditReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/session": {
+            "post": {
+                "description": "Creates a presentation session, the wallet fetches the request from request_uri. Poll the session status or register a webhook to get the result. Transaction data binds the presentation to transactions the wallet confirms in the key binding JWT.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "verifier"
+                ],
+                "summary": "Create session",
+                "operationId": "verifier-create-session",
+                "parameters": [
+                    {
+                        "description": " ",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.CreateSessionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.CreateSessionReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/session/{session_id}": {
+            "get": {
+                "description": "Returns the status of a presentation session, and the verification result when the wallet has responded",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "verifier"
+                ],
+                "summary": "Session status",
+                "operationId": "verifier-session-status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "session id",
+                        "name": "session_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.Session"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/session/{session_id}/request": {
+            "get": {
+                "description": "Returns the presentation request the wallet fetches from request_uri",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "verifier"
+                ],
+                "summary": "Session request object",
+                "operationId": "verifier-session-request-object",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "session id",
+                        "name": "session_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.SessionRequestObjectReply"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/session/{session_id}/response": {
+            "post": {
+                "description": "Receives the wallet response, verifies the credential, its key binding JWT against the session nonce, the verifier client_id and the credential cnf key, and the wallet attestation, then fires the session webhook",
+                "consumes": [
+                    "application/json",
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "verifier"
+                ],
+                "summary": "Session response",
+                "operationId": "verifier-session-response",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "session id",
+                        "name": "session_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": " ",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.SessionResponseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.Session"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/verify": {
+            "post": {
+                "description": "Verifies the issuer signature of a credential with the issuer key matching the kid in its header, checks that the disclosure salts encode at least verifier.sd_jwt.min_salt_bits bits, checks the status list entry of the credential when enabled, then evaluates the verification policy",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "verifier"
+                ],
+                "summary": "Verify credential",
+                "operationId": "verifier-verify-credential",
+                "parameters": [
+                    {
+                        "description": " ",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.VerifyCredentialRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.VerifyCredentialReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "apiv1.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "credential_type": {
+                    "type": "string"
+                },
+                "issuer": {
+                    "description": "Issuer and CredentialType is what the wallet presented",
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "type": "integer"
+                },
+                "policy": {
+                    "description": "Policy and TransactionDataTypes is what the relying party requested",
+                    "type": "string"
+                },
+                "policy_failures": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "schema_errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "session_id": {
+                    "description": "SessionID is empty for verifications that were not part of a presentation session",
+                    "type": "string"
+                },
+                "transaction_data_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "apiv1.AuditReply": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/apiv1.AuditEntry"
+                    }
+                },
+                "next_offset": {
+                    "description": "NextOffset is the offset of the next page, zero on the last page",
+                    "type": "integer"
+                }
+            }
+        },
+        "apiv1.CreateSessionReply": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "integer"
+                },
+                "qr": {
+                    "$ref": "#/definitions/model.QR"
+                },
+                "request_uri": {
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.CreateSessionRequest": {
+            "type": "object",
+            "properties": {
+                "policy": {
+                    "description": "Policy is the name of the verification policy to apply to the response, the default policy if empty",
+                    "type": "string"
+                },
+                "transaction_data": {
+                    "description": "TransactionData are transactions the wallet must confirm with the presentation, e.g. a payment.\nEach object has a type and the OpenID4VP transaction data fields of that type.",
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "additionalProperties": {}
+                    }
+                },
+                "webhook_url": {
+                    "description": "WebhookURL is called with the session when the wallet has responded, optional. It must start with a prefix\nin verifier.session.webhook_allow_list.",
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.DailyAnalyticsReply": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/db.DailyStatistics"
+                    }
+                }
+            }
+        },
+        "apiv1.FailureAnalyticsReply": {
+            "type": "object",
+            "properties": {
+                "failures": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/db.FailureStatistics"
+                    }
+                }
+            }
+        },
+        "apiv1.IssuerAnalyticsReply": {
+            "type": "object",
+            "properties": {
+                "issuers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/db.IssuerStatistics"
+                    }
+                }
+            }
+        },
+        "apiv1.Session": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "type": "integer"
+                },
+                "nonce": {
+                    "type": "string"
+                },
+                "policy": {
+                    "description": "Policy is the verification policy applied to the response, the default policy if empty",
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/apiv1.VerifyCredentialReply"
+                },
+                "session_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "transaction_data": {
+                    "description": "TransactionData are the transactions the presentation is bound to, confirmed hashes are in the result",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/apiv1.TransactionData"
+                    }
+                }
+            }
+        },
+        "apiv1.SessionRequestObjectReply": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "description": "ClientID identifies the verifier, the wallet sets it as audience of the key binding JWT",
+                    "type": "string"
+                },
+                "nonce": {
+                    "type": "string"
+                },
+                "response_mode": {
+                    "type": "string"
+                },
+                "response_type": {
+                    "type": "string"
+                },
+                "response_uri": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "transaction_data": {
+                    "description": "TransactionData are the base64url encoded transaction data objects, the wallet echoes their hashes in the key binding JWT",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "apiv1.SessionResponseRequest": {
+            "type": "object",
+            "required": [
+                "sessionID",
+                "vp_token"
+            ],
+            "properties": {
+                "sessionID": {
+                    "type": "string"
+                },
+                "vp_token": {
+                    "type": "string"
+                },
+                "wallet_attestation": {
+                    "description": "WalletAttestation is the wallet instance attestation, validated when enabled",
+                    "type": "string"
+                },
+                "wallet_attestation_pop": {
+                    "description": "WalletAttestationPoP proves possession of the attested wallet key, with the session nonce",
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.TransactionData": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "Data is the transaction data object, with type and credential_ids",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "encoded": {
+                    "description": "Encoded is the base64url encoded JSON of Data, as sent in the transaction_data request parameter",
+                    "type": "string"
+                },
+                "hash": {
+                    "description": "Hash is the base64url encoded sha-256 hash of Encoded, the wallet echoes it in transaction_data_hashes of the key binding JWT",
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.VerifyCredentialReply": {
+            "type": "object",
+            "properties": {
+                "kid": {
+                    "type": "string"
+                },
+                "policy": {
+                    "description": "Policy is the outcome of the verification policy, set when a policy applies and the credential is otherwise valid",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/policy.Result"
+                        }
+                    ]
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "rendering": {
+                    "description": "Rendering is the disclosed claims labeled for the requested locale, set when the credential is valid",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/sdjwt.Rendering"
+                        }
+                    ]
+                },
+                "schema_errors": {
+                    "description": "SchemaErrors lists the disclosed claims that do not conform to the vct schema",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sdjwt.SchemaViolation"
+                    }
+                },
+                "transaction_data_hashes": {
+                    "description": "TransactionDataHashes are the hashes of the session transaction data the wallet confirmed in the key binding JWT",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "valid": {
+                    "type": "boolean"
+                },
+                "vct_name": {
+                    "description": "VCTName is the name from the vct type metadata, set when type metadata resolution is enabled",
+                    "type": "string"
+                },
+                "wallet_attestation": {
+                    "description": "WalletAttestation is set in session results when wallet attestation validation is enabled",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/apiv1.WalletAttestationResult"
+                        }
+                    ]
+                }
+            }
+        },
+        "apiv1.VerifyCredentialRequest": {
+            "type": "object",
+            "required": [
+                "credential"
+            ],
+            "properties": {
+                "credential": {
+                    "description": "Credential is an SD-JWT, disclosures are used to validate the disclosed claims against the vct schema",
+                    "type": "string"
+                },
+                "locale": {
+                    "description": "Locale, e.g. sv-SE, renders the disclosed claims of a valid credential with the display metadata of its vct type metadata, no rendering if empty or type metadata resolution is disabled",
+                    "type": "string"
+                },
+                "policy": {
+                    "description": "Policy is the name of the verification policy to apply, the default policy if empty",
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.WalletAttestationResult": {
+            "type": "object",
+            "properties": {
+                "provider": {
+                    "description": "Provider is the wallet provider, the iss of the attestation",
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "valid": {
+                    "type": "boolean"
+                },
+                "wallet_name": {
+                    "description": "WalletName is the name of the wallet solution, if the attestation has one",
+                    "type": "string"
+                }
+            }
+        },
+        "db.DailyStatistics": {
+            "type": "object",
+            "properties": {
+                "avg_latency_ms": {
+                    "type": "number"
+                },
+                "day": {
+                    "type": "string"
+                },
+                "invalid": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "valid": {
+                    "type": "integer"
+                }
+            }
+        },
+        "db.FailureStatistics": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "db.IssuerStatistics": {
+            "type": "object",
+            "properties": {
+                "invalid": {
+                    "type": "integer"
+                },
+                "issuer": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "valid": {
+                    "type": "integer"
+                }
+            }
+        },
+        "helpers.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code is a stable, machine-readable error code",
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "errors": {
+                    "description": "Errors holds field-level errors, e.g. from validation",
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "additionalProperties": {}
+                    }
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "trace_id": {
+                    "description": "TraceID of the request, for correlation with logs and traces",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.QR": {
+            "type": "object",
+            "required": [
+                "base64_image",
+                "deep_link"
+            ],
+            "properties": {
+                "base64_image": {
+                    "description": "required: true\nexample: \"ZWFzdGVyIGVnZyE=\"",
+                    "type": "string"
+                },
+                "deep_link": {
+                    "description": "required: true\nexample: \"https://example.com\"",
+                    "type": "string"
+                }
+            }
+        },
+        "policy.Result": {
+            "type": "object",
+            "properties": {
+                "passed": {
+                    "type": "boolean"
+                },
+                "policy": {
+                    "type": "string"
+                },
+                "rules": {
+                    "description": "Rules holds the outcome of each evaluated rule",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/policy.RuleResult"
+                    }
+                }
+            }
+        },
+        "policy.RuleResult": {
+            "type": "object",
+            "properties": {
+                "passed": {
+                    "type": "boolean"
+                },
+                "reason": {
+                    "description": "Reason is set when the rule failed",
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "string"
+                }
+            }
+        },
+        "sdjwt.RenderedClaim": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "array",
+                    "items": {}
+                },
+                "svg_id": {
+                    "type": "string"
+                },
+                "value": {}
+            }
+        },
+        "sdjwt.Rendering": {
+            "type": "object",
+            "properties": {
+                "background_color": {
+                    "type": "string"
+                },
+                "claims": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sdjwt.RenderedClaim"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "lang": {
+                    "type": "string"
+                },
+                "logo": {
+                    "$ref": "#/definitions/sdjwt.VCTMLogo"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "svg_templates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sdjwt.VCTMSVGTemplate"
+                    }
+                },
+                "text_color": {
+                    "type": "string"
+                }
+            }
+        },
+        "sdjwt.SchemaViolation": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Path is a JSON pointer to the claim, e.g. /address/country",
+                    "type": "string"
+                }
+            }
+        },
+        "sdjwt.VCTMLogo": {
+            "type": "object",
+            "properties": {
+                "alt_text": {
+                    "type": "string"
+                },
+                "uri": {
+                    "type": "string"
+                },
+                "uri#integrity": {
+                    "type": "string"
+                }
+            }
+        },
+        "sdjwt.VCTMSVGTemplate": {
+            "type": "object",
+            "properties": {
+                "properties": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "uri": {
+                    "type": "string"
+                },
+                "uri#integrity": {
+                    "type": "string"
+                }
+            }
+        }
+    }
 }`
 
 // SwaggerInfo holds exported Swagger Info so clients can modify it
 var SwaggerInfo = &swag.Spec{
-	Version:          "",
+	Version:          "0.1.0",
 	Host:             "",
-	BasePath:         "",
+	BasePath:         "/api/v1",
 	Schemes:          []string{},
-	Title:            "",
+	Title:            "Verifier API",
 	Description:      "",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
@@ -0,0 +1,50 @@
+package apiv1
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+const (
+	// ArchiveFormatNDJSON is newline delimited JSON, one document per line
+	ArchiveFormatNDJSON = "ndjson"
+
+	// ArchiveFormatCBOR is a CBOR sequence, RFC 8742, one document per data item
+	ArchiveFormatCBOR = "cbor"
+)
+
+// ArchiveMediaType maps an archive format to its media type
+var ArchiveMediaType = map[string]string{
+	ArchiveFormatNDJSON: "application/x-ndjson",
+	ArchiveFormatCBOR:   "application/cbor-seq",
+}
+
+type archiveEncoder interface {
+	Encode(v any) error
+}
+
+type archiveDecoder interface {
+	Decode(v any) error
+}
+
+func newArchiveEncoder(format string, w io.Writer) archiveEncoder {
+	if format == ArchiveFormatCBOR {
+		return cbor.NewEncoder(w)
+	}
+	return json.NewEncoder(w)
+}
+
+func newArchiveDecoder(format string, r io.Reader) (archiveDecoder, error) {
+	if format == ArchiveFormatCBOR {
+		// document data must decode to map[string]any to be stored in mongo
+		decMode, err := cbor.DecOptions{DefaultMapType: reflect.TypeOf(map[string]any(nil))}.DecMode()
+		if err != nil {
+			return nil, err
+		}
+		return decMode.NewDecoder(r), nil
+	}
+	return json.NewDecoder(r), nil
+}
@@ -0,0 +1,56 @@
+package apiv1
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"vc/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	docs := []*model.CompleteDocument{
+		{
+			Meta: &model.MetaData{
+				AuthenticSource: "SUNET",
+				DocumentType:    "PDA1",
+				DocumentID:      "doc-1",
+				Revision:        3,
+				Revocation:      &model.Revocation{ID: "rev-1", Revoked: true, Reason: "lost"},
+			},
+			Identities:   []model.Identity{{AuthenticSourcePersonID: "person-1", FamilyName: "Svensson"}},
+			DocumentData: map[string]any{"nested": map[string]any{"a": "b"}},
+		},
+		{
+			Meta: &model.MetaData{AuthenticSource: "SUNET", DocumentType: "EHIC", DocumentID: "doc-2"},
+		},
+	}
+
+	for _, format := range []string{ArchiveFormatNDJSON, ArchiveFormatCBOR} {
+		t.Run(format, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			encoder := newArchiveEncoder(format, buf)
+			for _, doc := range docs {
+				assert.NoError(t, encoder.Encode(doc))
+			}
+
+			decoder, err := newArchiveDecoder(format, buf)
+			assert.NoError(t, err)
+
+			got := []*model.CompleteDocument{}
+			for {
+				doc := &model.CompleteDocument{}
+				if err := decoder.Decode(doc); errors.Is(err, io.EOF) {
+					break
+				} else {
+					assert.NoError(t, err)
+				}
+				got = append(got, doc)
+			}
+
+			assert.Equal(t, docs, got)
+		})
+	}
+}
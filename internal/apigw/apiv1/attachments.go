@@ -0,0 +1,44 @@
+package apiv1
+
+import (
+	"context"
+	"time"
+	"vc/pkg/objectstore"
+)
+
+// extractAttachments moves the large base64 data uris of documentData to the object storage, if it is enabled
+func (c *Client) extractAttachments(ctx context.Context, documentData map[string]any) error {
+	if c.objects == nil {
+		return nil
+	}
+
+	ctx, span := c.tracer.Start(ctx, "apiv1:extractAttachments")
+	defer span.End()
+
+	_, err := objectstore.Extract(ctx, c.objects, documentData, c.cfg.APIGW.ObjectStore.MinSize)
+	return err
+}
+
+// inlineAttachments replaces the attachment references of documentData with their data uris, if the object storage
+// is enabled
+func (c *Client) inlineAttachments(ctx context.Context, documentData any) error {
+	if c.objects == nil {
+		return nil
+	}
+
+	ctx, span := c.tracer.Start(ctx, "apiv1:inlineAttachments")
+	defer span.End()
+
+	return objectstore.Inline(ctx, c.objects, documentData)
+}
+
+// presignAttachments replaces the attachment references of documentData with presigned download urls, if the object
+// storage is enabled
+func (c *Client) presignAttachments(ctx context.Context, documentData any) error {
+	if c.objects == nil {
+		return nil
+	}
+
+	ttl := time.Duration(c.cfg.APIGW.ObjectStore.PresignTTL) * time.Second
+	return objectstore.Presign(ctx, c.objects, documentData, ttl)
+}
@@ -0,0 +1,19 @@
+package apiv1
+
+import (
+	"context"
+	"testing"
+	"vc/pkg/objectstore"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUploadObjectReference(t *testing.T) {
+	client := &Client{}
+
+	err := client.Upload(context.Background(), &UploadRequest{
+		DocumentData: map[string]any{"portrait": objectstore.RefPrefix + objectstore.Key([]byte("portrait of someone else"))},
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), objectstore.ErrReference.Error())
+}
@@ -3,10 +3,19 @@ package apiv1
 import (
 	"context"
 	"vc/internal/apigw/db"
+	"vc/internal/apigw/federation"
+	"vc/internal/apigw/webhook"
+	"vc/pkg/configuration"
 	"vc/pkg/datastoreclient"
+	"vc/pkg/identitymatch"
 	"vc/pkg/logger"
+	"vc/pkg/messagebroker/kafka"
 	"vc/pkg/model"
+	"vc/pkg/objectstore"
+	"vc/pkg/schemaregistry"
 	"vc/pkg/trace"
+
+	"github.com/lestrrat-go/jwx/jwk"
 )
 
 //	@title		Datastore API
@@ -20,22 +29,80 @@ type Client struct {
 	log             *logger.Log
 	tracer          *trace.Tracer
 	datastoreClient *datastoreclient.Client
+	issuance        *issuanceStatistics
+	collectMetrics  *collectMetrics
+	metrics         *lifecycleMetrics
+	federation      *federation.Service
+	webhook         *webhook.Service
+	kafkaAdmin      *kafka.AdminClient
+	schemas         *schemaregistry.Registry
+	profiles        *configuration.Profiles
+
+	// objects holds the binary attachments of document data, nil unless the object storage is enabled
+	objects objectstore.Store
+
+	// uploadKeys are the keys signed uploads are verified with, by authentic source
+	uploadKeys map[string]jwk.Set
+
+	// identityMatcher scores the identity of collect requests, nil unless identity matching is enabled
+	identityMatcher *identitymatch.Matcher
 }
 
-// New creates a new instance of the public api
-func New(ctx context.Context, db *db.Service, tracer *trace.Tracer, cfg *model.Cfg, log *logger.Log) (*Client, error) {
+// New creates a new instance of the public api, federation is nil unless the trust model is openid_federation,
+// webhook is nil unless webhooks are enabled and kafkaAdmin is nil unless kafka is enabled
+func New(ctx context.Context, db *db.Service, federation *federation.Service, webhook *webhook.Service, kafkaAdmin *kafka.AdminClient, tracer *trace.Tracer, cfg *model.Cfg, log *logger.Log) (*Client, error) {
 	c := &Client{
-		cfg:    cfg,
-		db:     db,
-		log:    log.New("apiv1"),
-		tracer: tracer,
+		cfg:        cfg,
+		db:         db,
+		log:        log.New("apiv1"),
+		tracer:     tracer,
+		issuance:   newIssuanceStatistics(),
+		federation: federation,
+		webhook:    webhook,
+		kafkaAdmin: kafkaAdmin,
+	}
+
+	if cfg.APIGW.DocumentSchema.Enabled {
+		c.schemas = newSchemaRegistry(&cfg.APIGW.DocumentSchema)
+	}
+
+	var err error
+	c.collectMetrics, err = newCollectMetrics()
+	if err != nil {
+		return nil, err
+	}
+	c.metrics, err = newLifecycleMetrics()
+	if err != nil {
+		return nil, err
+	}
+
+	c.profiles, err = configuration.NewProfiles(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.APIGW.SignedUpload.Enabled {
+		c.uploadKeys, err = loadUploadKeys(&cfg.APIGW.SignedUpload)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.APIGW.IdentityMatching.Enabled {
+		c.identityMatcher = identitymatch.New(cfg.APIGW.IdentityMatching)
+	}
+
+	if cfg.APIGW.ObjectStore.Enabled {
+		c.objects, err = objectstore.NewS3(&cfg.APIGW.ObjectStore)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Specifies the issuer configuration based on the issuer identifier, should be initialized in main I guess.
 	issuerIdentifier := cfg.Issuer.Identifier
 	issuerCFG := cfg.AuthenticSources[issuerIdentifier]
 
-	var err error
 	c.datastoreClient, err = datastoreclient.New(&datastoreclient.Config{URL: issuerCFG.AuthenticSourceEndpoint.URL})
 	if err != nil {
 		return nil, err
@@ -0,0 +1,100 @@
+package apiv1
+
+import (
+	"context"
+	"errors"
+	"time"
+	"vc/pkg/helpers"
+	"vc/pkg/model"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// outcomes of the use of a collect code, the outcome attribute of apigw.collect.uses
+const (
+	collectOutcomeIssued   = "issued"
+	collectOutcomeExpired  = "expired"
+	collectOutcomeConsumed = "consumed"
+)
+
+// collectMetrics counts collect codes created at upload and their uses, issued over created is the conversion rate
+type collectMetrics struct {
+	offers metric.Int64Counter
+	uses   metric.Int64Counter
+}
+
+func newCollectMetrics() (*collectMetrics, error) {
+	meter := otel.Meter("vc/apigw")
+
+	m := &collectMetrics{}
+	var err error
+	m.offers, err = meter.Int64Counter("apigw.collect.offers", metric.WithDescription("Number of collect codes created by uploads"))
+	if err != nil {
+		return nil, err
+	}
+	m.uses, err = meter.Int64Counter("apigw.collect.uses", metric.WithDescription("Number of collect code uses by outcome, issued, expired or consumed"))
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func collectAttributes(meta *model.MetaData) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("authentic_source", meta.AuthenticSource),
+		attribute.String("document_type", meta.DocumentType),
+	}
+}
+
+func (m *collectMetrics) offered(ctx context.Context, meta *model.MetaData) {
+	m.offers.Add(ctx, 1, metric.WithAttributes(collectAttributes(meta)...))
+}
+
+func (m *collectMetrics) used(ctx context.Context, meta *model.MetaData, outcome string) {
+	m.uses.Add(ctx, 1, metric.WithAttributes(append(collectAttributes(meta), attribute.String("outcome", outcome))...))
+}
+
+// newCollect returns the collect code of an upload, by default the document id, valid for ttl seconds from now unless
+// the upload sets valid_until. ttl 0 never expires.
+func newCollect(collect *model.Collect, documentID string, ttl int64, now time.Time) *model.Collect {
+	if collect == nil {
+		collect = &model.Collect{}
+	}
+	if collect.ID == "" {
+		collect.ID = documentID
+	}
+	if collect.ValidUntil == 0 && ttl > 0 {
+		collect.ValidUntil = now.Add(time.Duration(ttl) * time.Second).Unix()
+	}
+	collect.Uses = 0
+
+	return collect
+}
+
+// collectExpired returns ErrCollectIDExpired if collect is past its valid_until
+func collectExpired(collect *model.Collect, now time.Time) error {
+	if collect != nil && collect.ValidUntil != 0 && now.Unix() > collect.ValidUntil {
+		return helpers.ErrCollectIDExpired
+	}
+	return nil
+}
+
+// useCollectID counts one use of the collect code of the document, before the credential is issued
+func (c *Client) useCollectID(ctx context.Context, meta *model.MetaData) error {
+	if err := collectExpired(meta.Collect, time.Now()); err != nil {
+		c.collectMetrics.used(ctx, meta, collectOutcomeExpired)
+		return err
+	}
+
+	if err := c.db.VCDatastoreColl.UseCollectID(ctx, meta, c.cfg.APIGW.CollectCode.MaxUses); err != nil {
+		if errors.Is(err, helpers.ErrCollectIDConsumed) {
+			c.collectMetrics.used(ctx, meta, collectOutcomeConsumed)
+		}
+		return err
+	}
+
+	return nil
+}
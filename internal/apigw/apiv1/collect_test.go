@@ -0,0 +1,81 @@
+package apiv1
+
+import (
+	"testing"
+	"time"
+	"vc/pkg/helpers"
+	"vc/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCollect(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	tts := []struct {
+		name    string
+		collect *model.Collect
+		ttl     int64
+		want    *model.Collect
+	}{
+		{
+			name: "default",
+			want: &model.Collect{ID: "document-1"},
+		},
+		{
+			name: "ttl",
+			ttl:  3600,
+			want: &model.Collect{ID: "document-1", ValidUntil: 1700003600},
+		},
+		{
+			name:    "upload sets collect",
+			collect: &model.Collect{ID: "collect-1", ValidUntil: 1700000060},
+			ttl:     3600,
+			want:    &model.Collect{ID: "collect-1", ValidUntil: 1700000060},
+		},
+		{
+			name:    "uses are reset",
+			collect: &model.Collect{ID: "collect-1", Uses: 3},
+			want:    &model.Collect{ID: "collect-1"},
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, newCollect(tt.collect, "document-1", tt.ttl, now))
+		})
+	}
+}
+
+func TestCollectExpired(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	tts := []struct {
+		name    string
+		collect *model.Collect
+		wantErr error
+	}{
+		{
+			name: "no collect",
+		},
+		{
+			name:    "never expires",
+			collect: &model.Collect{ID: "collect-1"},
+		},
+		{
+			name:    "valid",
+			collect: &model.Collect{ID: "collect-1", ValidUntil: 1700000000},
+		},
+		{
+			name:    "expired",
+			collect: &model.Collect{ID: "collect-1", ValidUntil: 1699999999},
+			wantErr: helpers.ErrCollectIDExpired,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, collectExpired(tt.collect, now))
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package apiv1
+
+import (
+	"context"
+	"testing"
+	"vc/pkg/helpers"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCredentialNotificationValidation(t *testing.T) {
+	tts := []struct {
+		name           string
+		req            *CredentialNotificationRequest
+		wantField      string
+		wantValidation string
+	}{
+		{
+			name:           "without notification_id",
+			req:            &CredentialNotificationRequest{Event: model.CredentialNotificationAccepted},
+			wantField:      "notification_id",
+			wantValidation: "required",
+		},
+		{
+			name:           "without event",
+			req:            &CredentialNotificationRequest{NotificationID: "notification-1"},
+			wantField:      "event",
+			wantValidation: "required",
+		},
+		{
+			name:           "unknown event",
+			req:            &CredentialNotificationRequest{NotificationID: "notification-1", Event: "credential_revoked"},
+			wantField:      "event",
+			wantValidation: "oneof",
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{cfg: &model.Cfg{}, log: logger.NewSimple("testing_apiv1")}
+
+			notification, err := c.CredentialNotification(context.Background(), tt.req)
+			assert.Nil(t, notification)
+
+			var validationErr *helpers.Error
+			if assert.ErrorAs(t, err, &validationErr) {
+				assert.Equal(t, "validation_error", validationErr.Title)
+				details, _ := validationErr.Err.([]map[string]any)
+				if assert.Len(t, details, 1) {
+					assert.Equal(t, tt.wantField, details[0]["field"])
+					assert.Equal(t, tt.wantValidation, details[0]["validation"])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package apiv1
+
+import (
+	"context"
+	"errors"
+	"time"
+	"vc/internal/gen/issuer/apiv1_issuer"
+	"vc/pkg/helpers"
+	"vc/pkg/model"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Credential refresh triggers
+const (
+	RefreshTriggerWalletRequest  = "wallet_request"
+	RefreshTriggerExpectedUpdate = "expected_update"
+)
+
+// RefreshCredentialRequest is the request for RefreshCredential
+type RefreshCredentialRequest struct {
+	AuthenticSource string `json:"authentic_source" validate:"required"`
+
+	// NotificationID identifies the credential to refresh, as returned when it was issued
+	NotificationID string `json:"notification_id" validate:"required"`
+
+	// Trigger is wallet_request when the wallet asks for a new credential, expected_update when the authentic source
+	// updated the document the credential was issued from
+	Trigger string `json:"trigger" validate:"required,oneof=wallet_request expected_update"`
+}
+
+// RefreshCredential issues a new credential for a previously issued one
+//
+//	@Summary		RefreshCredential
+//	@ID				refresh-credential
+//	@Description	Re-issues a credential from the current document, with a new validity window and the credential type it was issued as. Claims that did not change keep their digests. The new credential has its own notification_id, both credentials reference each other, and only the newest credential of a chain can be refreshed.
+//	@Tags			dc4eu
+//	@Accept			json
+//	@Produce		json
+//	@Success		200				{object}	apiv1_issuer.MakeSDJWTReply	"Success"
+//	@Failure		400				{object}	helpers.Problem				"Bad Request"
+//	@Failure		409				{object}	helpers.Problem				"Credential already refreshed"
+//	@Failure		410				{object}	helpers.Problem				"Document revoked"
+//	@Param			req				body		RefreshCredentialRequest	true	" "
+//	@Param			Idempotency-Key	header		string						false	"Retries with the same key get the stored response"
+//	@Router			/credential/refresh [post]
+func (c *Client) RefreshCredential(ctx context.Context, req *RefreshCredentialRequest) (*apiv1_issuer.MakeSDJWTReply, error) {
+	ctx, span := c.tracer.Start(ctx, "apiv1:RefreshCredential")
+	defer span.End()
+
+	if err := helpers.Check(ctx, c.cfg, req, c.log); err != nil {
+		return nil, err
+	}
+
+	previous, err := c.db.VCCredentialNotificationColl.Get(ctx, req.NotificationID)
+	if err != nil {
+		if errors.Is(err, helpers.ErrNoDocumentFound) {
+			return nil, helpers.ErrInvalidNotificationID
+		}
+		return nil, err
+	}
+	if previous.AuthenticSource != req.AuthenticSource {
+		return nil, helpers.ErrInvalidNotificationID
+	}
+	if previous.RefreshedBy != "" {
+		return nil, helpers.ErrCredentialRefreshed
+	}
+
+	reply, err := c.refreshCredential(ctx, req, previous)
+	c.issuance.record(previous.AuthenticSource, previous.DocumentType, err)
+	c.metrics.issued(ctx, previous.AuthenticSource, previous.DocumentType, true, err)
+
+	return reply, err
+}
+
+func (c *Client) refreshCredential(ctx context.Context, req *RefreshCredentialRequest, previous *model.CredentialNotification) (*apiv1_issuer.MakeSDJWTReply, error) {
+	credentialType, err := c.sdjwtCredentialType(previous.CredentialType, previous.DocumentType)
+	if err != nil {
+		return nil, err
+	}
+
+	document, err := c.db.VCDatastoreColl.Get(ctx, &model.MetaData{
+		AuthenticSource: previous.AuthenticSource,
+		DocumentType:    previous.DocumentType,
+		DocumentID:      previous.DocumentID,
+	})
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, helpers.ErrNoDocumentFound
+		}
+		return nil, err
+	}
+	if document.DocumentData == nil {
+		return nil, helpers.ErrNoDocumentFound
+	}
+	if document.Meta.Revocation.IsRevoked(time.Now().Unix()) {
+		return nil, helpers.ErrDocumentIsRevoked
+	}
+
+	if err := c.inlineAttachments(ctx, document.DocumentData); err != nil {
+		return nil, err
+	}
+
+	documentData, err := c.credentialDocumentData(ctx, document.Meta.DocumentType, document.DocumentData)
+	if err != nil {
+		return nil, err
+	}
+
+	consentIDs, err := c.issuanceConsentIDs(ctx, document.Meta)
+	if err != nil {
+		return nil, err
+	}
+
+	// concurrent refreshes of the credential issue one new credential
+	notification := &model.CredentialNotification{
+		NotificationID:  uuid.NewString(),
+		AuthenticSource: document.Meta.AuthenticSource,
+		DocumentType:    document.Meta.DocumentType,
+		DocumentID:      document.Meta.DocumentID,
+		Events:          []model.CredentialNotificationEvent{},
+		CredentialType:  previous.CredentialType,
+		RefreshOf:       previous.NotificationID,
+		RefreshTrigger:  req.Trigger,
+		HolderJWK:       previous.HolderJWK,
+	}
+	if err := c.db.VCCredentialNotificationColl.ClaimRefresh(ctx, previous.NotificationID, notification.NotificationID); err != nil {
+		return nil, err
+	}
+
+	reply, err := c.makeSDJWT(ctx, &apiv1_issuer.MakeSDJWTRequest{
+		DocumentType:        document.Meta.DocumentType,
+		DocumentData:        documentData,
+		ConsentIDs:          consentIDs,
+		CredentialType:      credentialType,
+		PreviousDisclosures: previous.Disclosures,
+		HolderJWK:           previous.HolderJWK,
+	})
+	if err != nil {
+		// no credential replaced the previous one
+		if err := c.db.VCCredentialNotificationColl.ReleaseRefresh(ctx, previous.NotificationID, notification.NotificationID); err != nil {
+			c.log.Error(err, "failed to release credential refresh", "notification_id", previous.NotificationID)
+		}
+		return nil, err
+	}
+
+	notification.IssuedAt = time.Now().Unix()
+	notification.Disclosures = reply.Disclosures
+	// the credential is already signed, a wallet without a notification_id can still use it
+	if err := c.db.VCCredentialNotificationColl.Add(ctx, notification); err != nil {
+		c.log.Error(err, "failed to add credential notification", "document_id", notification.DocumentID)
+	} else {
+		reply.NotificationID = notification.NotificationID
+	}
+
+	c.log.Info("credential refreshed", "notification_id", notification.NotificationID, "refresh_of", previous.NotificationID, "trigger", req.Trigger, "document_id", notification.DocumentID)
+
+	c.publishWebhook(ctx, model.WebhookEventCredentialRefreshed, document.Meta, map[string]any{
+		"credential_type": previous.CredentialType,
+		"notification_id": reply.NotificationID,
+		"refresh_of":      previous.NotificationID,
+		"trigger":         req.Trigger,
+	})
+
+	return reply, nil
+}
@@ -0,0 +1,276 @@
+package apiv1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"vc/internal/apigw/db"
+	"vc/internal/gen/issuer/apiv1_issuer"
+	"vc/pkg/configuration"
+	"vc/pkg/datastoreclient"
+	"vc/pkg/helpers"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+	"vc/pkg/trace"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// mockDeferredClient returns a client of the mock database of mt and the datastore at datastoreURL
+func mockDeferredClient(t *testing.T, mt *mtest.T, datastoreURL string) *Client {
+	ctx := context.Background()
+	log := logger.NewSimple("testing_apiv1")
+	tracer, err := trace.NewForTesting(ctx, "test", log)
+	assert.NoError(t, err)
+
+	cfg := &model.Cfg{APIGW: model.APIGW{Deferred: model.Deferred{Enabled: true, MaxAttempts: 3, BatchSize: 10}}}
+
+	service, err := db.NewForTesting(mt.Client, cfg, tracer, log)
+	assert.NoError(t, err)
+
+	profiles, err := configuration.NewProfiles(cfg)
+	assert.NoError(t, err)
+
+	datastore, err := datastoreclient.New(&datastoreclient.Config{URL: datastoreURL})
+	assert.NoError(t, err)
+
+	return &Client{
+		cfg:             cfg,
+		db:              service,
+		log:             log,
+		tracer:          tracer,
+		datastoreClient: datastore,
+		issuance:        newIssuanceStatistics(),
+		profiles:        profiles,
+	}
+}
+
+// mockDeferred is a stored deferred credential
+func mockDeferred(status string, attempts int) bson.D {
+	d := bson.D{
+		{Key: "transaction_id", Value: "transaction-1"},
+		{Key: "authentic_source", Value: "SUNET"},
+		{Key: "document_type", Value: "PDA1"},
+		{Key: "collect_id", Value: "collect-1"},
+		{Key: "identity", Value: bson.D{{Key: "authentic_source_person_id", Value: "person-1"}}},
+		{Key: "status", Value: status},
+		{Key: "attempts", Value: attempts},
+	}
+	if status == model.DeferredStatusIssued {
+		d = append(d, bson.E{Key: "credential", Value: bson.D{
+			{Key: "jwt", Value: "eyJhbGciOiJFUzI1NiJ9.e30.c2ln"},
+			{Key: "disclosures", Value: bson.A{"WyJzYWx0IiwiZ2l2ZW5fbmFtZSIsIk1hZ251cyJd"}},
+			{Key: "notification_id", Value: "notification-1"},
+		}})
+	}
+	return d
+}
+
+func TestDocumentNotReady(t *testing.T) {
+	tts := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "no document", err: helpers.ErrNoDocumentFound, want: true},
+		{name: "datastore not found", err: fmt.Errorf("collect: %w", datastoreclient.ErrNotFound), want: true},
+		{name: "identity review pending", err: helpers.ErrIdentityReviewPending, want: true},
+		{name: "identity review rejected", err: helpers.ErrIdentityReviewRejected},
+		{name: "revoked", err: helpers.ErrDocumentIsRevoked},
+		{name: "datastore failure", err: datastoreclient.ErrInvalidRequest},
+		{name: "no error"},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, documentNotReady(tt.err))
+		})
+	}
+}
+
+func TestDeferredCredential(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	tts := []struct {
+		name       string
+		stored     []bson.D
+		deleteErr  bool
+		want       *apiv1_issuer.MakeSDJWTReply
+		wantErr    error
+		wantDelete bool
+	}{
+		{
+			name:    "unknown transaction_id",
+			wantErr: helpers.ErrInvalidTransactionID,
+		},
+		{
+			name:    "pending",
+			stored:  []bson.D{mockDeferred(model.DeferredStatusPending, 1)},
+			wantErr: helpers.ErrIssuancePending,
+		},
+		{
+			name:    "failed",
+			stored:  []bson.D{mockDeferred(model.DeferredStatusFailed, 3)},
+			wantErr: helpers.ErrNoDocumentFound,
+		},
+		{
+			name:   "issued",
+			stored: []bson.D{mockDeferred(model.DeferredStatusIssued, 1)},
+			want: &apiv1_issuer.MakeSDJWTReply{
+				Jwt:            "eyJhbGciOiJFUzI1NiJ9.e30.c2ln",
+				Disclosures:    []string{"WyJzYWx0IiwiZ2l2ZW5fbmFtZSIsIk1hZ251cyJd"},
+				NotificationID: "notification-1",
+			},
+			wantDelete: true,
+		},
+		{
+			name:       "issued but not deleted",
+			stored:     []bson.D{mockDeferred(model.DeferredStatusIssued, 1)},
+			deleteErr:  true,
+			wantDelete: true,
+		},
+	}
+
+	for _, tt := range tts {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			c := mockDeferredClient(t, mt, "http://datastore.invalid")
+
+			mt.AddMockResponses(mtest.CreateCursorResponse(0, "vc.deferred_credential", mtest.FirstBatch, tt.stored...))
+			if tt.deleteErr {
+				mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 11600, Message: "interrupted"}))
+			} else {
+				mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}})
+			}
+
+			got, err := c.DeferredCredential(context.Background(), &DeferredCredentialRequest{TransactionID: "transaction-1"})
+			if tt.deleteErr {
+				assert.Error(t, err, "a credential that could not be consumed is not returned")
+			} else {
+				assert.Equal(t, tt.wantErr, err)
+			}
+			assert.Equal(t, tt.want, got)
+
+			events := mt.GetAllStartedEvents()
+			assert.Equal(t, tt.wantDelete, events[len(events)-1].CommandName == "delete")
+		})
+	}
+
+	mt.Run("without transaction_id", func(mt *mtest.T) {
+		c := mockDeferredClient(t, mt, "http://datastore.invalid")
+
+		_, err := c.DeferredCredential(context.Background(), &DeferredCredentialRequest{})
+		assert.Error(t, err)
+		assert.Empty(t, mt.GetAllStartedEvents())
+	})
+}
+
+func TestProcessDeferred(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	tts := []struct {
+		name         string
+		attempts     int
+		status       int
+		body         string
+		wantStatus   string
+		wantAttempts int32
+	}{
+		{
+			name:         "document not ready",
+			status:       http.StatusNotFound,
+			body:         `{}`,
+			wantStatus:   model.DeferredStatusPending,
+			wantAttempts: 1,
+		},
+		{
+			name:         "attempts exhausted",
+			attempts:     2,
+			status:       http.StatusNotFound,
+			body:         `{}`,
+			wantStatus:   model.DeferredStatusFailed,
+			wantAttempts: 3,
+		},
+		{
+			name:         "datastore failure is retried",
+			status:       http.StatusInternalServerError,
+			body:         `{}`,
+			wantStatus:   model.DeferredStatusPending,
+			wantAttempts: 1,
+		},
+		{
+			name:         "revoked document fails at once",
+			status:       http.StatusOK,
+			body:         `{"data":{"meta":{"authentic_source":"SUNET","document_type":"PDA1","document_id":"doc-1","revocation":{"revoked":true}},"document_data":{"a":1}}}`,
+			wantStatus:   model.DeferredStatusFailed,
+			wantAttempts: 1,
+		},
+	}
+
+	for _, tt := range tts {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			datastore := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer datastore.Close()
+
+			c := mockDeferredClient(t, mt, datastore.URL)
+			mt.AddMockResponses(
+				mtest.CreateCursorResponse(0, "vc.deferred_credential", mtest.FirstBatch, mockDeferred(model.DeferredStatusPending, tt.attempts)),
+				bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}},
+			)
+
+			issued, err := c.ProcessDeferred(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, 0, issued)
+
+			events := mt.GetAllStartedEvents()
+			assert.Equal(t, "update", events[len(events)-1].CommandName)
+			update := events[len(events)-1].Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("u", "$set").Document()
+			assert.Equal(t, tt.wantStatus, update.Lookup("status").StringValue())
+			assert.Equal(t, tt.wantAttempts, update.Lookup("attempts").Int32())
+		})
+	}
+
+	mt.Run("pending query failure", func(mt *mtest.T) {
+		c := mockDeferredClient(t, mt, "http://datastore.invalid")
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 11600, Message: "interrupted"}))
+
+		issued, err := c.ProcessDeferred(context.Background())
+		assert.Error(t, err)
+		assert.Equal(t, 0, issued)
+	})
+
+	mt.Run("update failure does not stop the batch", func(mt *mtest.T) {
+		datastore := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{}`))
+		}))
+		defer datastore.Close()
+
+		c := mockDeferredClient(t, mt, datastore.URL)
+		second := mockDeferred(model.DeferredStatusPending, 0)
+		second[0].Value = "transaction-2"
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "vc.deferred_credential", mtest.FirstBatch, mockDeferred(model.DeferredStatusPending, 0), second),
+			mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 11600, Message: "interrupted"}),
+			bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}},
+		)
+
+		_, err := c.ProcessDeferred(context.Background())
+		assert.NoError(t, err)
+
+		updates := 0
+		for _, event := range mt.GetAllStartedEvents() {
+			if event.CommandName == "update" {
+				updates++
+			}
+		}
+		assert.Equal(t, 2, updates)
+	})
+}
@@ -0,0 +1,161 @@
+package apiv1
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"vc/internal/apigw/db"
+	"vc/pkg/helpers"
+	"vc/pkg/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Document change types
+const (
+	DocumentChangeCreated = "created"
+	DocumentChangeUpdated = "updated"
+	DocumentChangeRevoked = "revoked"
+)
+
+// changesSettleTime holds back the changes of the last seconds, writes of concurrent requests may still commit with
+// an earlier modification time than a change already returned
+const changesSettleTime = 2 * time.Second
+
+// DocumentChangesRequest is the request for DocumentChanges
+type DocumentChangesRequest struct {
+	AuthenticSource string `json:"authentic_source" validate:"required"`
+	DocumentType    string `json:"document_type"`
+
+	// Since is the next_since of the previous reply, empty for a full sync
+	Since string `json:"since"`
+
+	// Limit is the most changes in the reply, 100 if not set
+	Limit int64 `json:"limit" validate:"omitempty,gte=1,lte=1000"`
+}
+
+// DocumentChange is a document created, updated or revoked since the previous sync
+type DocumentChange struct {
+	// Change is created, updated or revoked
+	Change string          `json:"change"`
+	Meta   *model.MetaData `json:"meta"`
+}
+
+// DocumentChangesReply is the reply for DocumentChanges
+type DocumentChangesReply struct {
+	Data []*DocumentChange `json:"data"`
+
+	// NextSince is sent as since by the next sync
+	NextSince string `json:"next_since"`
+
+	// HasMore is true if there are more changes, fetch them with next_since right away
+	HasMore bool `json:"has_more"`
+}
+
+// DocumentChanges returns the documents of an authentic source created, updated or revoked since the previous sync
+//
+//	@Summary		DocumentChanges
+//	@ID				document-changes
+//	@Description	Returns the metadata of the documents created, updated or revoked since the sync token, oldest first. An empty since returns every document. Changes of the last seconds are returned by the next sync.
+//	@Tags			dc4eu
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	DocumentChangesReply	"Success"
+//	@Failure		400	{object}	helpers.Problem			"Bad Request"
+//	@Param			req	body		DocumentChangesRequest	true	" "
+//	@Router			/document/changes [post]
+func (c *Client) DocumentChanges(ctx context.Context, req *DocumentChangesRequest) (*DocumentChangesReply, error) {
+	ctx, span := c.tracer.Start(ctx, "apiv1:DocumentChanges")
+	defer span.End()
+
+	after, err := parseSyncToken(req.Since)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = 100
+	}
+
+	// one more than the limit tells if there are more changes
+	changes, err := c.db.VCDatastoreColl.Changes(ctx, &db.ChangesQuery{
+		AuthenticSource: req.AuthenticSource,
+		DocumentType:    req.DocumentType,
+		After:           after,
+		Before:          time.Now().Add(-changesSettleTime).Unix(),
+		Limit:           limit + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &DocumentChangesReply{
+		Data:      []*DocumentChange{},
+		NextSince: req.Since,
+	}
+	if int64(len(changes)) > limit {
+		changes = changes[:limit]
+		reply.HasMore = true
+	}
+	for _, change := range changes {
+		reply.Data = append(reply.Data, &DocumentChange{
+			Change: documentChangeType(change.Meta),
+			Meta:   change.Meta,
+		})
+	}
+	if len(changes) > 0 {
+		reply.NextSince = syncToken(changes[len(changes)-1].Position)
+	}
+
+	return reply, nil
+}
+
+// documentChangeType tells a revoked document from a created or updated one
+func documentChangeType(meta *model.MetaData) string {
+	switch {
+	case meta.Revocation != nil && meta.Revocation.Revoked:
+		return DocumentChangeRevoked
+	case meta.Revision <= 1:
+		return DocumentChangeCreated
+	default:
+		return DocumentChangeUpdated
+	}
+}
+
+// syncToken encodes the position of the last change returned
+func syncToken(position db.ChangePosition) string {
+	return base64.RawURLEncoding.EncodeToString(fmt.Appendf(nil, "%d.%s", position.ModifiedAt, position.ID.Hex()))
+}
+
+// parseSyncToken decodes a token from syncToken, the empty token is the zero position
+func parseSyncToken(token string) (db.ChangePosition, error) {
+	position := db.ChangePosition{}
+	if token == "" {
+		return position, nil
+	}
+
+	invalid := helpers.NewErrorDetails("validation_error", "since is not a sync token")
+
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return position, invalid
+	}
+	modifiedAt, id, ok := strings.Cut(string(decoded), ".")
+	if !ok {
+		return position, invalid
+	}
+	position.ModifiedAt, err = strconv.ParseInt(modifiedAt, 10, 64)
+	if err != nil {
+		return position, invalid
+	}
+	position.ID, err = primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return position, invalid
+	}
+
+	return position, nil
+}
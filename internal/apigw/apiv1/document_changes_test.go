@@ -0,0 +1,46 @@
+package apiv1
+
+import (
+	"testing"
+	"vc/internal/apigw/db"
+	"vc/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSyncToken(t *testing.T) {
+	position := db.ChangePosition{ModifiedAt: 1714564800, ID: primitive.NewObjectID()}
+
+	got, err := parseSyncToken(syncToken(position))
+	assert.NoError(t, err)
+	assert.Equal(t, position, got)
+
+	got, err = parseSyncToken("")
+	assert.NoError(t, err)
+	assert.Equal(t, db.ChangePosition{}, got)
+
+	for _, token := range []string{"not base64!", "MTcxNDU2NDgwMA", "MTcxNDU2NDgwMC54eXo"} {
+		_, err := parseSyncToken(token)
+		assert.Error(t, err, token)
+	}
+}
+
+func TestDocumentChangeType(t *testing.T) {
+	tts := []struct {
+		name string
+		meta *model.MetaData
+		want string
+	}{
+		{name: "created", meta: &model.MetaData{Revision: 1}, want: DocumentChangeCreated},
+		{name: "stored before revisions", meta: &model.MetaData{}, want: DocumentChangeCreated},
+		{name: "updated", meta: &model.MetaData{Revision: 3, Revocation: &model.Revocation{}}, want: DocumentChangeUpdated},
+		{name: "revoked", meta: &model.MetaData{Revision: 2, Revocation: &model.Revocation{Revoked: true}}, want: DocumentChangeRevoked},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, documentChangeType(tt.meta))
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package apiv1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"time"
+	"vc/pkg/helpers"
+	"vc/pkg/model"
+	"vc/pkg/schemaregistry"
+)
+
+// newSchemaRegistry creates the registry of document_data schemas, local schemas take precedence over remote and built-in ones
+func newSchemaRegistry(cfg *model.DocumentSchema) *schemaregistry.Registry {
+	sources := []schemaregistry.Source{}
+	if cfg.Dir != "" {
+		sources = append(sources, schemaregistry.NewFSSource(os.DirFS(cfg.Dir)))
+	}
+	if cfg.URL != "" {
+		sources = append(sources, schemaregistry.NewHTTPSource(&http.Client{Timeout: 10 * time.Second}, cfg.URL))
+	}
+	sources = append(sources, schemaregistry.BuiltinSource())
+
+	return schemaregistry.New(time.Duration(cfg.CacheTTL)*time.Second, sources...)
+}
+
+// validateDocumentData validates data against the schema of documentType and version, if schema validation is enabled
+func (c *Client) validateDocumentData(ctx context.Context, documentType, version string, data map[string]any) error {
+	if c.schemas == nil {
+		return nil
+	}
+
+	ctx, span := c.tracer.Start(ctx, "apiv1:validateDocumentData")
+	defer span.End()
+
+	err := c.schemas.Validate(ctx, documentType, version, data)
+	if errors.Is(err, schemaregistry.ErrSchemaNotFound) {
+		if c.cfg.APIGW.DocumentSchema.Required {
+			return helpers.ErrNoDocumentSchema
+		}
+		c.log.Debug("no schema, document_data not validated", "document_type", documentType, "document_data_version", version)
+		return nil
+	}
+
+	var validationErr *schemaregistry.ValidationError
+	if errors.As(err, &validationErr) {
+		details := make([]map[string]any, 0, len(validationErr.Violations))
+		for _, v := range validationErr.Violations {
+			details = append(details, map[string]any{"field": "/document_data" + v.Path, "message": v.Message})
+		}
+		return helpers.NewErrorDetails("SCHEMA_VALIDATION_ERROR", details)
+	}
+
+	return err
+}
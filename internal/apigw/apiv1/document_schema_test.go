@@ -0,0 +1,67 @@
+package apiv1
+
+import (
+	"context"
+	"testing"
+	"vc/pkg/helpers"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+	"vc/pkg/trace"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateDocumentData(t *testing.T) {
+	ctx := context.Background()
+	log := logger.NewSimple("testing_apiv1")
+	tracer, err := trace.NewForTesting(ctx, "test", log)
+	assert.NoError(t, err)
+
+	tts := []struct {
+		name         string
+		required     bool
+		documentType string
+		version      string
+		data         map[string]any
+		want         error
+	}{
+		{
+			name:         "schema violation",
+			documentType: "PDA1",
+			version:      "1.0.0",
+			data:         map[string]any{"personalDetails": map[string]any{"surname": "Svensson", "forenames": "Magnus", "dateBirth": "1970-01-01", "nationality": 46}},
+			want: helpers.NewErrorDetails("SCHEMA_VALIDATION_ERROR", []map[string]any{
+				{"field": "/document_data/personalDetails/nationality", "message": "got number, want string"},
+			}),
+		},
+		{
+			name:         "no schema",
+			documentType: "PDA1",
+			version:      "0.1.0",
+			data:         map[string]any{},
+		},
+		{
+			name:         "no schema required",
+			required:     true,
+			documentType: "PDA1",
+			version:      "0.1.0",
+			data:         map[string]any{},
+			want:         helpers.ErrNoDocumentSchema,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &model.Cfg{APIGW: model.APIGW{DocumentSchema: model.DocumentSchema{Enabled: true, Required: tt.required, CacheTTL: 60}}}
+			c := &Client{
+				cfg:     cfg,
+				log:     log,
+				tracer:  tracer,
+				schemas: newSchemaRegistry(&cfg.APIGW.DocumentSchema),
+			}
+
+			err := c.validateDocumentData(ctx, tt.documentType, tt.version, tt.data)
+			assert.Equal(t, tt.want, err)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package apiv1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+	"vc/pkg/documenttype"
+	"vc/pkg/helpers"
+)
+
+// validateDocumentType validates data by the module of documentType, after schema validation
+func (c *Client) validateDocumentType(ctx context.Context, documentType string, data map[string]any) error {
+	module, ok := documenttype.Lookup(documentType)
+	if !ok {
+		return helpers.ErrNoKnownDocumentType
+	}
+
+	ctx, span := c.tracer.Start(ctx, "apiv1:validateDocumentType")
+	defer span.End()
+
+	err := module.Validate(ctx, data)
+
+	var validationErr *documenttype.ValidationError
+	if errors.As(err, &validationErr) {
+		details := make([]map[string]any, 0, len(validationErr.Violations))
+		for _, v := range validationErr.Violations {
+			details = append(details, map[string]any{"field": "/document_data" + v.Path, "message": v.Message})
+		}
+		return helpers.NewErrorDetails("DOCUMENT_VALIDATION_ERROR", details)
+	}
+
+	return err
+}
+
+// credentialDocumentData returns the document_data sent to the issuer, enriched and mapped by the module of
+// documentType. Documents stored before their document type lost its module are sent as they are.
+func (c *Client) credentialDocumentData(ctx context.Context, documentType string, documentData any) ([]byte, error) {
+	module, ok := documenttype.Lookup(documentType)
+	data, isObject := documentData.(map[string]any)
+	if !ok || !isObject {
+		return json.Marshal(documentData)
+	}
+
+	if err := module.Enrich(ctx, data, time.Now()); err != nil {
+		return nil, err
+	}
+
+	credentialData, err := module.MapToCredential(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(credentialData)
+}
@@ -0,0 +1,43 @@
+package apiv1
+
+import (
+	"context"
+	"vc/pkg/helpers"
+	"vc/pkg/model"
+)
+
+// newDocumentVersion returns the version history entry of doc, uploaded at uploadedAt
+func newDocumentVersion(doc *model.CompleteDocument, uploadedAt int64) *model.DocumentVersion {
+	return &model.DocumentVersion{
+		AuthenticSource:     doc.Meta.AuthenticSource,
+		DocumentType:        doc.Meta.DocumentType,
+		DocumentID:          doc.Meta.DocumentID,
+		DocumentDataVersion: doc.DocumentDataVersion,
+		DocumentData:        doc.DocumentData,
+		UploadedAt:          uploadedAt,
+	}
+}
+
+// reupload replaces the stored document with upload if upload has another document_data_version, else
+// ErrDocumentAlreadyExists is returned. The stored version is added to the version history as it may have been uploaded
+// before version history was kept, its upload time is then unknown and recorded as 0.
+func (c *Client) reupload(ctx context.Context, upload *model.CompleteDocument) error {
+	return c.db.WithTransaction(ctx, func(ctx context.Context) error {
+		stored, err := c.db.VCDatastoreColl.Get(ctx, upload.Meta)
+		if err != nil {
+			return err
+		}
+		if stored.DocumentDataVersion == upload.DocumentDataVersion {
+			return helpers.ErrDocumentAlreadyExists
+		}
+
+		if err := c.db.VCDocumentVersionColl.Add(ctx, newDocumentVersion(stored, 0)); err != nil {
+			return err
+		}
+
+		upload.Meta.Revision = stored.Meta.Revision
+		upload.StatusHistory = stored.StatusHistory
+
+		return c.db.VCDatastoreColl.Replace(ctx, upload)
+	})
+}
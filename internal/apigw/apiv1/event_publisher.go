@@ -2,9 +2,11 @@ package apiv1
 
 import (
 	"context"
+	"vc/pkg/model"
 )
 
 type EventPublisher interface {
 	Upload(uploadRequest *UploadRequest) error
+	CredentialNotification(notification *model.CredentialNotification) error
 	Close(ctx context.Context) error
 }
@@ -0,0 +1,318 @@
+package apiv1
+
+import (
+	"context"
+	"errors"
+	"io"
+	"vc/pkg/helpers"
+	"vc/pkg/model"
+	"vc/pkg/objectstore"
+)
+
+// ExportDocumentsRequest is the request for ExportDocuments
+type ExportDocumentsRequest struct {
+	AuthenticSource string `form:"authentic_source" validate:"required"`
+
+	// Format of the archive, ndjson (default) or cbor
+	Format string `form:"format" validate:"omitempty,oneof=ndjson cbor"`
+}
+
+// ExportDocuments writes all documents of an authentic source, with identities, metadata and revocation state, as an archive to w
+//
+//	@Summary		Export documents
+//	@ID				admin-export-documents
+//	@Description	Streams all documents of an authentic source as NDJSON or a CBOR sequence
+//	@Tags			admin
+//	@Produce		application/x-ndjson
+//	@Produce		application/cbor-seq
+//	@Success		200					"Success"
+//	@Failure		400					{object}	helpers.Problem	"Bad Request"
+//	@Param			authentic_source	query		string			true	"Authentic source"
+//	@Param			format				query		string			false	"ndjson or cbor"
+//	@Router			/admin/documents/export [get]
+func (c *Client) ExportDocuments(ctx context.Context, req *ExportDocumentsRequest, w io.Writer) (int, error) {
+	ctx, span := c.tracer.Start(ctx, "apiv1:ExportDocuments")
+	defer span.End()
+
+	encoder := newArchiveEncoder(req.Format, w)
+
+	n := 0
+	err := c.db.VCDatastoreColl.ExportDocuments(ctx, req.AuthenticSource, func(doc *model.CompleteDocument) error {
+		n++
+		return encoder.Encode(doc)
+	})
+	if err != nil {
+		return n, err
+	}
+
+	c.log.Info("exported documents", "authentic_source", req.AuthenticSource, "documents", n)
+
+	return n, nil
+}
+
+// ImportDocumentsRequest is the request for ImportDocuments
+type ImportDocumentsRequest struct {
+	// AuthenticSource every document in the archive must belong to
+	AuthenticSource string `form:"authentic_source" validate:"required"`
+
+	// Format of the archive, ndjson (default) or cbor
+	Format string `form:"format" validate:"omitempty,oneof=ndjson cbor"`
+
+	// DryRun validates the archive without storing any document
+	DryRun bool `form:"dry_run"`
+}
+
+// ImportDocumentError is a document that could not be imported
+type ImportDocumentError struct {
+	// Index of the document in the archive, starting at 0
+	Index      int    `json:"index"`
+	DocumentID string `json:"document_id,omitempty"`
+	Code       string `json:"code"`
+	Detail     string `json:"detail,omitempty"`
+}
+
+// ImportDocumentsReply is the reply for ImportDocuments
+type ImportDocumentsReply struct {
+	DryRun   bool                   `json:"dry_run"`
+	Total    int                    `json:"total"`
+	Imported int                    `json:"imported"`
+	Errors   []*ImportDocumentError `json:"errors"`
+}
+
+// ImportDocuments reads an archive made by ExportDocuments and stores each document as is, documents that already exist are reported and skipped
+//
+//	@Summary		Import documents
+//	@ID				admin-import-documents
+//	@Description	Imports an NDJSON or CBOR sequence archive, with dry_run the archive is only validated
+//	@Tags			admin
+//	@Accept			application/x-ndjson
+//	@Accept			application/cbor-seq
+//	@Produce		json
+//	@Success		200					{object}	ImportDocumentsReply	"Success"
+//	@Failure		400					{object}	helpers.Problem			"Bad Request"
+//	@Param			authentic_source	query		string					true	"Authentic source"
+//	@Param			format				query		string					false	"ndjson or cbor"
+//	@Param			dry_run				query		bool					false	"Validate only"
+//	@Router			/admin/documents/import [post]
+func (c *Client) ImportDocuments(ctx context.Context, req *ImportDocumentsRequest, r io.Reader) (*ImportDocumentsReply, error) {
+	ctx, span := c.tracer.Start(ctx, "apiv1:ImportDocuments")
+	defer span.End()
+
+	decoder, err := newArchiveDecoder(req.Format, r)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &ImportDocumentsReply{
+		DryRun: req.DryRun,
+		Errors: []*ImportDocumentError{},
+	}
+
+	for {
+		doc := &model.CompleteDocument{}
+		if err := decoder.Decode(doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			// the archive can not be read past a malformed document
+			return nil, err
+		}
+		index := reply.Total
+		reply.Total++
+
+		if err := c.importDocument(ctx, req, doc); err != nil {
+			problem := helpers.NewProblem(err)
+			importErr := &ImportDocumentError{Index: index, Code: problem.Code, Detail: problem.Detail}
+			if doc.Meta != nil {
+				importErr.DocumentID = doc.Meta.DocumentID
+			}
+			reply.Errors = append(reply.Errors, importErr)
+			continue
+		}
+		if !req.DryRun {
+			reply.Imported++
+		}
+	}
+
+	c.log.Info("imported documents", "authentic_source", req.AuthenticSource, "dry_run", req.DryRun, "total", reply.Total, "imported", reply.Imported, "errors", len(reply.Errors))
+
+	return reply, nil
+}
+
+// importDocument validates doc and stores it unless the request is a dry run
+func (c *Client) importDocument(ctx context.Context, req *ImportDocumentsRequest, doc *model.CompleteDocument) error {
+	if doc.Meta == nil {
+		return helpers.NewErrorDetails("validation_error", "meta is missing")
+	}
+	if err := helpers.CheckSimple(doc.Meta); err != nil {
+		return err
+	}
+	for i := range doc.Identities {
+		if err := helpers.CheckSimple(&doc.Identities[i]); err != nil {
+			return err
+		}
+	}
+	if doc.Meta.AuthenticSource != req.AuthenticSource {
+		return helpers.ErrNotAuthorized
+	}
+	if err := c.validateDocumentData(ctx, doc.Meta.DocumentType, doc.DocumentDataVersion, doc.DocumentData); err != nil {
+		return err
+	}
+	if err := c.validateDocumentType(ctx, doc.Meta.DocumentType, doc.DocumentData); err != nil {
+		return err
+	}
+
+	exists, err := c.db.VCDatastoreColl.Exists(ctx, doc.Meta)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return helpers.ErrDocumentAlreadyExists
+	}
+
+	if req.DryRun {
+		return nil
+	}
+
+	if err := c.extractAttachments(ctx, doc.DocumentData); err != nil {
+		return err
+	}
+
+	return c.db.VCDatastoreColl.Import(ctx, doc)
+}
+
+// RotateEncryptionKeysRequest is the request for RotateEncryptionKeys
+type RotateEncryptionKeysRequest struct {
+	AuthenticSource string `json:"authentic_source" validate:"required"`
+}
+
+// RotateEncryptionKeysReply is the reply for RotateEncryptionKeys
+type RotateEncryptionKeysReply struct {
+	Data struct {
+		Rewrapped int `json:"rewrapped"`
+	} `json:"data"`
+}
+
+// RotateEncryptionKeys rewraps the identity and disclosure data encryption keys of an authentic source with the current
+// key encryption key
+//
+//	@Summary		Rotate encryption keys
+//	@ID				admin-rotate-encryption-keys
+//	@Description	Rewraps the data encryption keys of the identities of stored documents, deferred credentials and identity reviews, and of the disclosures of issued credentials, wrapped by a previous key encryption key, after which the previous key can be removed from previous_kek_paths or kms.previous_key_ids
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	RotateEncryptionKeysReply	"Success"
+//	@Failure		400	{object}	helpers.Problem				"Bad Request"
+//	@Param			req	body		RotateEncryptionKeysRequest	true	" "
+//	@Router			/admin/encryption/rotate [post]
+func (c *Client) RotateEncryptionKeys(ctx context.Context, req *RotateEncryptionKeysRequest) (*RotateEncryptionKeysReply, error) {
+	ctx, span := c.tracer.Start(ctx, "apiv1:RotateEncryptionKeys")
+	defer span.End()
+
+	reply := &RotateEncryptionKeysReply{}
+	for _, rotate := range []func(context.Context, string, int64) (int, error){
+		c.db.VCDatastoreColl.RotateIdentityKeys,
+		c.db.VCDeferredCredentialColl.RotateIdentityKeys,
+		c.db.VCDeferredCredentialColl.RotateDisclosureKeys,
+		c.db.VCIdentityReviewColl.RotateIdentityKeys,
+		c.db.VCCredentialNotificationColl.RotateEncryptionKeys,
+	} {
+		for {
+			n, err := rotate(ctx, req.AuthenticSource, 1000)
+			if err != nil {
+				return nil, err
+			}
+			if n == 0 {
+				break
+			}
+			reply.Data.Rewrapped += n
+		}
+	}
+
+	c.log.Info("rotated identity encryption keys", "authentic_source", req.AuthenticSource, "rewrapped", reply.Data.Rewrapped)
+
+	return reply, nil
+}
+
+// MigrateAttachmentsRequest is the request for MigrateAttachments
+type MigrateAttachmentsRequest struct {
+	AuthenticSource string `json:"authentic_source" validate:"required"`
+}
+
+// MigrateAttachmentsReply is the reply for MigrateAttachments
+type MigrateAttachmentsReply struct {
+	Data struct {
+		// Documents is the number of stored documents and document versions that had attachments moved
+		Documents int `json:"documents"`
+
+		// Attachments is the number of attachments moved to the object storage
+		Attachments int `json:"attachments"`
+	} `json:"data"`
+}
+
+// MigrateAttachments moves the binary attachments embedded in the stored documents of an authentic source, and in
+// their versions, to the object storage
+//
+//	@Summary		Migrate attachments
+//	@ID				admin-migrate-attachments
+//	@Description	Moves base64 data uris of at least object_store.min_size bytes from stored document data to the object storage, leaving references. Documents changed during the migration are skipped, their uploads already stored the attachments. The migration can be run again.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	MigrateAttachmentsReply		"Success"
+//	@Failure		400	{object}	helpers.Problem				"Bad Request"
+//	@Param			req	body		MigrateAttachmentsRequest	true	" "
+//	@Router			/admin/attachments/migrate [post]
+func (c *Client) MigrateAttachments(ctx context.Context, req *MigrateAttachmentsRequest) (*MigrateAttachmentsReply, error) {
+	ctx, span := c.tracer.Start(ctx, "apiv1:MigrateAttachments")
+	defer span.End()
+
+	if c.objects == nil {
+		return nil, helpers.NewErrorDetails("validation_error", "object storage is not enabled")
+	}
+	minSize := c.cfg.APIGW.ObjectStore.MinSize
+
+	reply := &MigrateAttachmentsReply{}
+	err := c.db.VCDatastoreColl.ExportDocuments(ctx, req.AuthenticSource, func(doc *model.CompleteDocument) error {
+		n, err := objectstore.Extract(ctx, c.objects, doc.DocumentData, minSize)
+		if err != nil || n == 0 {
+			return err
+		}
+
+		if err := c.db.VCDatastoreColl.Replace(ctx, doc); err != nil {
+			if errors.Is(err, helpers.ErrPreconditionFailed) {
+				c.log.Debug("document changed during attachment migration", "document_id", doc.Meta.DocumentID)
+				return nil
+			}
+			return err
+		}
+		reply.Data.Documents++
+		reply.Data.Attachments += n
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.db.VCDocumentVersionColl.ForEach(ctx, req.AuthenticSource, func(version *model.DocumentVersion) error {
+		n, err := objectstore.Extract(ctx, c.objects, version.DocumentData, minSize)
+		if err != nil || n == 0 {
+			return err
+		}
+
+		if err := c.db.VCDocumentVersionColl.SetDocumentData(ctx, version); err != nil {
+			return err
+		}
+		reply.Data.Documents++
+		reply.Data.Attachments += n
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.log.Info("migrated attachments", "authentic_source", req.AuthenticSource, "documents", reply.Data.Documents, "attachments", reply.Data.Attachments)
+
+	return reply, nil
+}
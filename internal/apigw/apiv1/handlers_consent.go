@@ -4,7 +4,10 @@ import (
 	"context"
 	"time"
 	"vc/internal/apigw/db"
+	"vc/pkg/helpers"
 	"vc/pkg/model"
+
+	"github.com/google/uuid"
 )
 
 // AddConsentRequest is the request for AddConsent
@@ -23,9 +26,10 @@ type AddConsentRequest struct {
 //	@Tags			dc4eu
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	"Success"
-//	@Failure		400	{object}	helpers.ErrorResponse	"Bad Request"
-//	@Param			req	body		AddConsentRequest		true	" "
+//	@Success		200				"Success"
+//	@Failure		400				{object}	helpers.Problem		"Bad Request"
+//	@Param			req				body		AddConsentRequest	true	" "
+//	@Param			Idempotency-Key	header		string				false	"Retries with the same key get the stored response"
 //	@Router			/consent [post]
 func (c *Client) AddConsent(ctx context.Context, req *AddConsentRequest) error {
 	err := c.db.VCConsentColl.Add(ctx, &db.AddConsentQuery{
@@ -58,9 +62,9 @@ type GetConsentRequest struct {
 //	@Tags			dc4eu
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{object} model.Consent	"Success"
-//	@Failure		400	{object}		helpers.ErrorResponse	"Bad Request"
-//	@Param			req	body			GetConsentRequest		true	" "
+//	@Success		200	{object}	model.Consent		"Success"
+//	@Failure		400	{object}	helpers.Problem		"Bad Request"
+//	@Param			req	body		GetConsentRequest	true	" "
 //	@Router			/consent/get [post]
 func (c *Client) GetConsent(ctx context.Context, req *GetConsentRequest) (*model.Consent, error) {
 	res, err := c.db.VCConsentColl.Get(ctx, &db.GetConsentQuery{
@@ -73,3 +77,225 @@ func (c *Client) GetConsent(ctx context.Context, req *GetConsentRequest) (*model
 
 	return res, nil
 }
+
+// AddDocumentConsentRequest is the request for AddDocumentConsent
+type AddDocumentConsentRequest struct {
+	AuthenticSource string `json:"authentic_source" validate:"required"`
+	DocumentType    string `json:"document_type" validate:"required"`
+	DocumentID      string `json:"document_id" validate:"required"`
+
+	// ApprovedBy is the holder who gave the consent, e.g. the authentic source person id
+	ApprovedBy string `json:"approved_by" validate:"required"`
+
+	// Scope is what the consent covers, issuance and/or sharing
+	Scope []string `json:"scope" validate:"required,min=1,dive,oneof=issuance sharing"`
+}
+
+// AddDocumentConsentReply is the reply for AddDocumentConsent
+type AddDocumentConsentReply struct {
+	Data *model.DocumentConsent `json:"data"`
+}
+
+// AddDocumentConsent records a holder consent for a document
+//
+//	@Summary		AddDocumentConsent
+//	@ID				add-document-consent
+//	@Description	Record a holder consent for issuance or sharing of a document
+//	@Tags			dc4eu
+//	@Accept			json
+//	@Produce		json
+//	@Success		200				{object}	AddDocumentConsentReply		"Success"
+//	@Failure		400				{object}	helpers.Problem				"Bad Request"
+//	@Failure		404				{object}	helpers.Problem				"Not Found"
+//	@Param			req				body		AddDocumentConsentRequest	true	" "
+//	@Param			Idempotency-Key	header		string						false	"Retries with the same key get the stored response"
+//	@Router			/document/consent [post]
+func (c *Client) AddDocumentConsent(ctx context.Context, req *AddDocumentConsentRequest) (*AddDocumentConsentReply, error) {
+	ctx, span := c.tracer.Start(ctx, "apiv1:AddDocumentConsent")
+	defer span.End()
+
+	if err := helpers.Check(ctx, c.cfg, req, c.log); err != nil {
+		return nil, err
+	}
+
+	consent := &model.DocumentConsent{
+		ConsentID:       uuid.NewString(),
+		AuthenticSource: req.AuthenticSource,
+		DocumentType:    req.DocumentType,
+		DocumentID:      req.DocumentID,
+		ApprovedBy:      req.ApprovedBy,
+		Scope:           req.Scope,
+		CreatedAt:       time.Now().Unix(),
+	}
+
+	err := c.db.WithTransaction(ctx, func(ctx context.Context) error {
+		if _, err := c.db.VCDatastoreColl.GetDocument(ctx, &db.GetDocumentQuery{
+			Meta: &model.MetaData{
+				AuthenticSource: req.AuthenticSource,
+				DocumentType:    req.DocumentType,
+				DocumentID:      req.DocumentID,
+			},
+		}); err != nil {
+			return err
+		}
+
+		if err := c.db.VCDocumentConsentColl.Add(ctx, consent); err != nil {
+			return err
+		}
+
+		return c.db.VCConsentAuditColl.Add(ctx, &model.ConsentAuditEntry{
+			ConsentID:  consent.ConsentID,
+			Action:     "created",
+			ApprovedBy: consent.ApprovedBy,
+			Timestamp:  consent.CreatedAt,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &AddDocumentConsentReply{Data: consent}, nil
+}
+
+// DocumentConsentListRequest is the request for DocumentConsentList
+type DocumentConsentListRequest struct {
+	AuthenticSource string `json:"authentic_source" validate:"required"`
+	DocumentType    string `json:"document_type" validate:"required"`
+	DocumentID      string `json:"document_id" validate:"required"`
+}
+
+// DocumentConsentListReply is the reply for DocumentConsentList
+type DocumentConsentListReply struct {
+	Data struct {
+		Consents []*model.DocumentConsent   `json:"consents"`
+		Audit    []*model.ConsentAuditEntry `json:"audit"`
+	} `json:"data"`
+}
+
+// DocumentConsentList lists the consents of a document and their audit trail
+//
+//	@Summary		DocumentConsentList
+//	@ID				document-consent-list
+//	@Description	List the consents of a document, including withdrawn ones, and their audit trail
+//	@Tags			dc4eu
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	DocumentConsentListReply	"Success"
+//	@Failure		400	{object}	helpers.Problem				"Bad Request"
+//	@Param			req	body		DocumentConsentListRequest	true	" "
+//	@Router			/document/consent/list [post]
+func (c *Client) DocumentConsentList(ctx context.Context, req *DocumentConsentListRequest) (*DocumentConsentListReply, error) {
+	ctx, span := c.tracer.Start(ctx, "apiv1:DocumentConsentList")
+	defer span.End()
+
+	if err := helpers.Check(ctx, c.cfg, req, c.log); err != nil {
+		return nil, err
+	}
+
+	consents, err := c.db.VCDocumentConsentColl.List(ctx, &db.DocumentConsentQuery{
+		AuthenticSource: req.AuthenticSource,
+		DocumentType:    req.DocumentType,
+		DocumentID:      req.DocumentID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	consentIDs := make([]string, 0, len(consents))
+	for _, consent := range consents {
+		consentIDs = append(consentIDs, consent.ConsentID)
+	}
+
+	audit, err := c.db.VCConsentAuditColl.List(ctx, consentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &DocumentConsentListReply{}
+	reply.Data.Consents = consents
+	reply.Data.Audit = audit
+
+	return reply, nil
+}
+
+// WithdrawDocumentConsentRequest is the request for WithdrawDocumentConsent
+type WithdrawDocumentConsentRequest struct {
+	AuthenticSource string `json:"authentic_source" validate:"required"`
+	DocumentType    string `json:"document_type" validate:"required"`
+	DocumentID      string `json:"document_id" validate:"required"`
+	ConsentID       string `json:"consent_id" validate:"required"`
+}
+
+// WithdrawDocumentConsent withdraws an active consent of a document
+//
+//	@Summary		WithdrawDocumentConsent
+//	@ID				withdraw-document-consent
+//	@Description	Withdraw an active consent of a document, the consent is kept with its withdrawal time
+//	@Tags			dc4eu
+//	@Accept			json
+//	@Produce		json
+//	@Success		200				{object}	AddDocumentConsentReply			"Success"
+//	@Failure		400				{object}	helpers.Problem					"Bad Request"
+//	@Failure		404				{object}	helpers.Problem					"Not Found"
+//	@Param			req				body		WithdrawDocumentConsentRequest	true	" "
+//	@Param			Idempotency-Key	header		string							false	"Retries with the same key get the stored response"
+//	@Router			/document/consent/withdraw [post]
+func (c *Client) WithdrawDocumentConsent(ctx context.Context, req *WithdrawDocumentConsentRequest) (*AddDocumentConsentReply, error) {
+	ctx, span := c.tracer.Start(ctx, "apiv1:WithdrawDocumentConsent")
+	defer span.End()
+
+	if err := helpers.Check(ctx, c.cfg, req, c.log); err != nil {
+		return nil, err
+	}
+
+	var consent *model.DocumentConsent
+	err := c.db.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		consent, err = c.db.VCDocumentConsentColl.Withdraw(ctx, &db.DocumentConsentQuery{
+			AuthenticSource: req.AuthenticSource,
+			DocumentType:    req.DocumentType,
+			DocumentID:      req.DocumentID,
+		}, req.ConsentID, time.Now().Unix())
+		if err != nil {
+			return err
+		}
+
+		return c.db.VCConsentAuditColl.Add(ctx, &model.ConsentAuditEntry{
+			ConsentID:  consent.ConsentID,
+			Action:     "withdrawn",
+			ApprovedBy: consent.ApprovedBy,
+			Timestamp:  consent.WithdrawnAt,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &AddDocumentConsentReply{Data: consent}, nil
+}
+
+// issuanceConsentIDs returns the ids of the active issuance consents of a document
+func (c *Client) issuanceConsentIDs(ctx context.Context, meta *model.MetaData) ([]string, error) {
+	if meta == nil {
+		return nil, nil
+	}
+
+	consents, err := c.db.VCDocumentConsentColl.List(ctx, &db.DocumentConsentQuery{
+		AuthenticSource: meta.AuthenticSource,
+		DocumentType:    meta.DocumentType,
+		DocumentID:      meta.DocumentID,
+		ActiveOnly:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	consentIDs := []string{}
+	for _, consent := range consents {
+		if consent.HasScope(model.ConsentScopeIssuance) {
+			consentIDs = append(consentIDs, consent.ConsentID)
+		}
+	}
+
+	return consentIDs, nil
+}
@@ -5,8 +5,11 @@ import (
 	"time"
 	"vc/internal/apigw/db"
 	"vc/pkg/helpers"
+	"vc/pkg/jsondiff"
 	"vc/pkg/model"
+	"vc/pkg/objectstore"
 
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.opentelemetry.io/otel/codes"
 )
 
@@ -23,28 +26,41 @@ type UploadRequest struct {
 //
 //	@Summary		Upload
 //	@ID				generic-upload
-//	@Description	Upload endpoint
+//	@Description	Upload endpoint. A document that is already stored is replaced if the upload has another document_data_version, the document data of every version is kept. With signed uploads enabled the request may be a compact JWS with the request as payload, or carry a detached JWS over the body. The protected header holds kid, iat, nonce and optionally exp.
 //	@Tags			dc4eu
-//	@Accept			json
+//	@Accept			json,application/jose
 //	@Produce		json
-//	@Success		200	"Success"
-//	@Failure		400	{object}	helpers.ErrorResponse	"Bad Request"
-//	@Param			req	body		UploadRequest			true	" "
+//	@Success		200				"Success"
+//	@Failure		400				{object}	helpers.Problem	"Bad Request"
+//	@Failure		401				{object}	helpers.Problem	"Invalid or missing upload signature"
+//	@Failure		409				{object}	helpers.Problem	"Replayed upload, or the document is already stored with this document_data_version"
+//	@Param			req				body		UploadRequest	true	" "
+//	@Param			Idempotency-Key	header		string			false	"Retries with the same key get the stored response"
+//	@Param			X-JWS-Signature	header		string			false	"Detached JWS, header..signature, over the request body"
 //	@Router			/upload [post]
 func (c *Client) Upload(ctx context.Context, req *UploadRequest) error {
+	// attachment references are only created by the object storage, uploaded ones could read other documents' attachments
+	if err := objectstore.CheckReferences(req.DocumentData); err != nil {
+		return helpers.NewErrorDetails("validation_error", err.Error())
+	}
+	if err := c.validateDocumentData(ctx, req.Meta.DocumentType, req.DocumentDataVersion, req.DocumentData); err != nil {
+		return err
+	}
+	if err := c.validateDocumentType(ctx, req.Meta.DocumentType, req.DocumentData); err != nil {
+		return err
+	}
+	if err := c.extractAttachments(ctx, req.DocumentData); err != nil {
+		return err
+	}
+
 	qr, err := req.Meta.QRGenerator(ctx, c.cfg.Common.QR.BaseURL, c.cfg.Common.QR.RecoveryLevel, c.cfg.Common.QR.Size)
 	if err != nil {
 		c.log.Debug("QR code generation failed", "error", err)
 		return err
 	}
 
-	if req.Meta.Collect == nil || req.Meta.Collect.ID == "" {
-		collect := &model.Collect{
-			ID: req.Meta.DocumentID,
-		}
-
-		req.Meta.Collect = collect
-	}
+	now := time.Now()
+	req.Meta.Collect = newCollect(req.Meta.Collect, req.Meta.DocumentID, c.cfg.APIGW.CollectCode.TTL, now)
 
 	if req.Meta.Revocation == nil {
 		req.Meta.Revocation = &model.Revocation{
@@ -70,9 +86,22 @@ func (c *Client) Upload(ctx context.Context, req *UploadRequest) error {
 	}
 
 	if err := c.db.VCDatastoreColl.Save(ctx, upload); err != nil {
-		c.log.Debug("Failed to save document", "error", err)
-		return err
+		if !mongo.IsDuplicateKeyError(err) {
+			c.log.Debug("Failed to save document", "error", err)
+			return err
+		}
+		if err := c.reupload(ctx, upload); err != nil {
+			c.log.Debug("Failed to replace document", "error", err)
+			return err
+		}
+	}
+	if err := c.db.VCDocumentVersionColl.Add(ctx, newDocumentVersion(upload, now.Unix())); err != nil {
+		c.log.Error(err, "failed to add document version", "document_id", upload.Meta.DocumentID)
 	}
+	c.collectMetrics.offered(ctx, upload.Meta)
+	c.metrics.uploaded(ctx, upload.Meta)
+
+	c.publishWebhook(ctx, model.WebhookEventDocumentUploaded, upload.Meta, nil)
 
 	return nil
 }
@@ -97,9 +126,9 @@ type NotificationReply struct {
 //	@Tags			dc4eu
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{object}	NotificationReply		"Success"
-//	@Failure		400	{object}	helpers.ErrorResponse	"Bad Request"
-//	@Param			req	body		NotificationRequest		true	" "
+//	@Success		200	{object}	NotificationReply	"Success"
+//	@Failure		400	{object}	helpers.Problem		"Bad Request"
+//	@Param			req	body		NotificationRequest	true	" "
 //	@Router			/notification [post]
 func (c *Client) Notification(ctx context.Context, req *NotificationRequest) (*NotificationReply, error) {
 	qrCode, err := c.db.VCDatastoreColl.GetQR(ctx, &model.MetaData{
@@ -139,7 +168,7 @@ type IdentityMappingReply struct {
 //	@Accept			json
 //	@Produce		json
 //	@Success		200	{object}	IdentityMappingReply	"Success"
-//	@Failure		400	{object}	helpers.ErrorResponse	"Bad Request"
+//	@Failure		400	{object}	helpers.Problem			"Bad Request"
 //	@Param			req	body		IdentityMappingRequest	true	" "
 //	@Router			/identity/mapping [post]
 func (c *Client) IdentityMapping(ctx context.Context, reg *IdentityMappingRequest) (*IdentityMappingReply, error) {
@@ -175,6 +204,9 @@ type AddDocumentIdentityRequest struct {
 	DocumentID string `json:"document_id" validate:"required"`
 
 	Identities []*model.Identity `json:"identities" validate:"required"`
+
+	// Revision is the expected document revision, from the If-Match header
+	Revision *int64 `json:"-"`
 }
 
 // AddDocumentIdentity adds an identity to a document
@@ -186,8 +218,11 @@ type AddDocumentIdentityRequest struct {
 //	@Accept			json
 //	@Produce		json
 //	@Success		200
-//	@Failure		400	{object}	helpers.ErrorResponse		"Bad Request"
-//	@Param			req	body		AddDocumentIdentityRequest	true	" "
+//	@Failure		400				{object}	helpers.Problem				"Bad Request"
+//	@Failure		412				{object}	helpers.Problem				"Precondition Failed"
+//	@Param			If-Match		header		string						true	"Document revision, from ETag"
+//	@Param			req				body		AddDocumentIdentityRequest	true	" "
+//	@Param			Idempotency-Key	header		string						false	"Retries with the same key get the stored response"
 //	@Router			/document/identity [put]
 func (c *Client) AddDocumentIdentity(ctx context.Context, req *AddDocumentIdentityRequest) error {
 	err := c.db.VCDatastoreColl.AddDocumentIdentity(ctx, &db.AddDocumentIdentityQuery{
@@ -195,11 +230,18 @@ func (c *Client) AddDocumentIdentity(ctx context.Context, req *AddDocumentIdenti
 		DocumentType:    req.DocumentType,
 		DocumentID:      req.DocumentID,
 		Identities:      req.Identities,
+		Revision:        req.Revision,
 	})
 	if err != nil {
 		return err
 	}
 
+	c.publishWebhook(ctx, model.WebhookEventIdentityAdded, &model.MetaData{
+		AuthenticSource: req.AuthenticSource,
+		DocumentType:    req.DocumentType,
+		DocumentID:      req.DocumentID,
+	}, nil)
+
 	return nil
 }
 
@@ -220,6 +262,9 @@ type DeleteDocumentIdentityRequest struct {
 	// required: true
 	// example: 83c1a3c8-3e1a-11ef-9c01-6b6642c8d638
 	AuthenticSourcePersonID string `json:"authentic_source_person_id" validate:"required"`
+
+	// Revision is the expected document revision, from the If-Match header
+	Revision *int64 `json:"-"`
 }
 
 // DeleteDocumentIdentity deletes an identity from a document
@@ -231,8 +276,11 @@ type DeleteDocumentIdentityRequest struct {
 //	@Accept			json
 //	@Produce		json
 //	@Success		200
-//	@Failure		400	{object}	helpers.ErrorResponse			"Bad Request"
-//	@Param			req	body		DeleteDocumentIdentityRequest	true	" "
+//	@Failure		400				{object}	helpers.Problem					"Bad Request"
+//	@Failure		412				{object}	helpers.Problem					"Precondition Failed"
+//	@Param			If-Match		header		string							true	"Document revision, from ETag"
+//	@Param			req				body		DeleteDocumentIdentityRequest	true	" "
+//	@Param			Idempotency-Key	header		string							false	"Retries with the same key get the stored response"
 //	@Router			/document/identity [delete]
 func (c *Client) DeleteDocumentIdentity(ctx context.Context, req *DeleteDocumentIdentityRequest) error {
 	err := c.db.VCDatastoreColl.DeleteDocumentIdentity(ctx, &db.DeleteDocumentIdentityQuery{
@@ -240,6 +288,7 @@ func (c *Client) DeleteDocumentIdentity(ctx context.Context, req *DeleteDocument
 		DocumentType:            req.DocumentType,
 		DocumentID:              req.DocumentID,
 		AuthenticSourcePersonID: req.AuthenticSourcePersonID,
+		Revision:                req.Revision,
 	})
 	if err != nil {
 		return err
@@ -261,6 +310,9 @@ type DeleteDocumentRequest struct {
 	// required: true
 	// example: PDA1
 	DocumentType string `json:"document_type" validate:"required"`
+
+	// Revision is the expected document revision, from the If-Match header
+	Revision *int64 `json:"-"`
 }
 
 // DeleteDocument deletes a specific document
@@ -271,21 +323,28 @@ type DeleteDocumentRequest struct {
 //	@Tags			dc4eu
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	"Success"
-//	@Failure		400	{object}	helpers.ErrorResponse	"Bad Request"
-//	@Param			req	body		DeleteDocumentRequest	true	" "
+//	@Success		200				"Success"
+//	@Failure		400				{object}	helpers.Problem			"Bad Request"
+//	@Failure		412				{object}	helpers.Problem			"Precondition Failed"
+//	@Param			If-Match		header		string					true	"Document revision, from ETag"
+//	@Param			req				body		DeleteDocumentRequest	true	" "
+//	@Param			Idempotency-Key	header		string					false	"Retries with the same key get the stored response"
 //	@Router			/document [delete]
 func (c *Client) DeleteDocument(ctx context.Context, req *DeleteDocumentRequest) error {
-	err := c.db.VCDatastoreColl.Delete(ctx, &model.MetaData{
+	meta := &model.MetaData{
 		AuthenticSource: req.AuthenticSource,
 		DocumentType:    req.DocumentType,
 		DocumentID:      req.DocumentID,
-	})
-	if err != nil {
-		return err
 	}
 
-	return nil
+	return c.db.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := c.db.VCDatastoreColl.Delete(ctx, meta, req.Revision); err != nil {
+			return err
+		}
+
+		_, err := c.db.VCDocumentVersionColl.DeleteByDocument(ctx, meta)
+		return err
+	})
 }
 
 // GetDocumentRequest is the request for GetDocument
@@ -304,13 +363,14 @@ type GetDocumentReply struct {
 //
 //	@Summary		GetDocument
 //	@ID				get-document
-//	@Description	Get document endpoint
+//	@Description	Get document endpoint, attachments in object storage are presigned download urls
 //	@Tags			dc4eu
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{object}	GetDocumentReply		"Success"
-//	@Failure		400	{object}	helpers.ErrorResponse	"Bad Request"
-//	@Param			req	body		GetDocumentRequest		true	" "
+//	@Success		200	{object}	GetDocumentReply	"Success"
+//	@Header			200	{string}	ETag				"Document revision"
+//	@Failure		400	{object}	helpers.Problem		"Bad Request"
+//	@Param			req	body		GetDocumentRequest	true	" "
 //	@Router			/document [post]
 func (c *Client) GetDocument(ctx context.Context, req *GetDocumentRequest) (*GetDocumentReply, error) {
 	query := &db.GetDocumentQuery{
@@ -324,6 +384,9 @@ func (c *Client) GetDocument(ctx context.Context, req *GetDocumentRequest) (*Get
 	if err != nil {
 		return nil, err
 	}
+	if err := c.presignAttachments(ctx, doc.DocumentData); err != nil {
+		return nil, err
+	}
 	reply := &GetDocumentReply{
 		Data: doc,
 	}
@@ -349,13 +412,18 @@ type DocumentListReply struct {
 //
 //	@Summary		DocumentList
 //	@ID				document-list
-//	@Description	List documents for an identity
+//	@Description	List documents for an identity, with If-None-Match or If-Modified-Since an unchanged list is not sent again
 //	@Tags			dc4eu
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{object}	DocumentListReply		"Success"
-//	@Failure		400	{object}	helpers.ErrorResponse	"Bad Request"
-//	@Param			req	body		DocumentListRequest		true	" "
+//	@Success		200					{object}	DocumentListReply	"Success"
+//	@Header			200					{string}	ETag				"Hash of the revisions of the listed documents"
+//	@Header			200					{string}	Last-Modified		"Last modification of a listed document"
+//	@Success		304					"Not Modified"
+//	@Failure		400					{object}	helpers.Problem		"Bad Request"
+//	@Param			req					body		DocumentListRequest	true	" "
+//	@Param			If-None-Match		header		string				false	"ETag of the list the client has"
+//	@Param			If-Modified-Since	header		string				false	"Last-Modified of the list the client has"
 //	@Router			/document/list [post]
 func (c *Client) DocumentList(ctx context.Context, req *DocumentListRequest) (*DocumentListReply, error) {
 	docs, err := c.db.VCDatastoreColl.DocumentList(ctx, &db.DocumentListQuery{
@@ -396,7 +464,7 @@ type GetDocumentCollectIDReply struct {
 //	@Accept			json
 //	@Produce		json
 //	@Success		200	{object}	GetDocumentCollectIDReply	"Success"
-//	@Failure		400	{object}	helpers.ErrorResponse		"Bad Request"
+//	@Failure		400	{object}	helpers.Problem				"Bad Request"
 //	@Param			req	body		GetDocumentCollectIDRequest	true	" "
 //	@Router			/document/collect_id [post]
 func (c *Client) GetDocumentCollectID(ctx context.Context, req *GetDocumentCollectIDRequest) (*GetDocumentCollectIDReply, error) {
@@ -411,7 +479,13 @@ func (c *Client) GetDocumentCollectID(ctx context.Context, req *GetDocumentColle
 		},
 	}
 
-	doc, err := c.db.VCDatastoreColl.GetDocumentCollectID(ctx, query)
+	var doc *model.Document
+	var err error
+	if c.identityMatcher != nil {
+		doc, err = c.matchDocumentCollectID(ctx, query)
+	} else {
+		doc, err = c.db.VCDatastoreColl.GetDocumentCollectID(ctx, query)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -427,6 +501,168 @@ type RevokeDocumentRequest struct {
 	AuthenticSource string            `json:"authentic_source" validate:"required"`
 	DocumentType    string            `json:"document_type" validate:"required"`
 	Revocation      *model.Revocation `json:"revocation" validate:"required"`
+
+	// Actor is recorded in the status history, defaults to the authentic source
+	Actor string `json:"actor,omitempty"`
+
+	// Revision is the expected document revision, from the If-Match header
+	Revision *int64 `json:"-"`
+}
+
+// DocumentStatusHistoryRequest is the request for DocumentStatusHistory
+type DocumentStatusHistoryRequest struct {
+	AuthenticSource string `json:"authentic_source" validate:"required"`
+	DocumentType    string `json:"document_type" validate:"required"`
+	DocumentID      string `json:"document_id" validate:"required"`
+}
+
+// DocumentStatusHistoryReply is the reply for DocumentStatusHistory
+type DocumentStatusHistoryReply struct {
+	Data []model.DocumentStatus `json:"data"`
+}
+
+// DocumentStatusHistory returns the status history of a document
+//
+//	@Summary		DocumentStatusHistory
+//	@ID				document-status-history
+//	@Description	Returns the status changes of a document, oldest first
+//	@Tags			dc4eu
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	DocumentStatusHistoryReply		"Success"
+//	@Failure		400	{object}	helpers.Problem					"Bad Request"
+//	@Param			req	body		DocumentStatusHistoryRequest	true	" "
+//	@Router			/document/status_history [post]
+func (c *Client) DocumentStatusHistory(ctx context.Context, req *DocumentStatusHistoryRequest) (*DocumentStatusHistoryReply, error) {
+	if err := helpers.Check(ctx, c.cfg, req, c.log); err != nil {
+		return nil, err
+	}
+
+	history, err := c.db.VCDatastoreColl.StatusHistory(ctx, &model.MetaData{
+		AuthenticSource: req.AuthenticSource,
+		DocumentType:    req.DocumentType,
+		DocumentID:      req.DocumentID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &DocumentStatusHistoryReply{Data: history}, nil
+}
+
+// DocumentVersionsRequest is the request for DocumentVersions
+type DocumentVersionsRequest struct {
+	AuthenticSource string `json:"authentic_source" validate:"required"`
+	DocumentType    string `json:"document_type" validate:"required"`
+	DocumentID      string `json:"document_id" validate:"required"`
+}
+
+// DocumentVersionsReply is the reply for DocumentVersions
+type DocumentVersionsReply struct {
+	Data []*model.DocumentVersion `json:"data"`
+}
+
+// DocumentVersions returns the document_data_versions a document has been uploaded with
+//
+//	@Summary		DocumentVersions
+//	@ID				document-versions
+//	@Description	Returns the versions of a document without their document data, oldest first
+//	@Tags			dc4eu
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	DocumentVersionsReply	"Success"
+//	@Failure		400	{object}	helpers.Problem			"Bad Request"
+//	@Param			req	body		DocumentVersionsRequest	true	" "
+//	@Router			/document/versions [post]
+func (c *Client) DocumentVersions(ctx context.Context, req *DocumentVersionsRequest) (*DocumentVersionsReply, error) {
+	if err := helpers.Check(ctx, c.cfg, req, c.log); err != nil {
+		return nil, err
+	}
+
+	versions, err := c.db.VCDocumentVersionColl.ListByDocument(ctx, &model.MetaData{
+		AuthenticSource: req.AuthenticSource,
+		DocumentType:    req.DocumentType,
+		DocumentID:      req.DocumentID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &DocumentVersionsReply{Data: versions}, nil
+}
+
+// DocumentDiffRequest is the request for DocumentDiff
+type DocumentDiffRequest struct {
+	AuthenticSource string `json:"authentic_source" validate:"required"`
+	DocumentType    string `json:"document_type" validate:"required"`
+	DocumentID      string `json:"document_id" validate:"required"`
+
+	// required: true
+	// example: "1.0.0"
+	FromVersion string `json:"from_version" validate:"required"`
+
+	// required: true
+	// example: "1.1.0"
+	ToVersion string `json:"to_version" validate:"required"`
+}
+
+// DocumentDiff is the changes of the document data from one version of a document to another
+type DocumentDiff struct {
+	FromVersion string             `json:"from_version"`
+	ToVersion   string             `json:"to_version"`
+	Changes     []*jsondiff.Change `json:"changes"`
+}
+
+// DocumentDiffReply is the reply for DocumentDiff
+type DocumentDiffReply struct {
+	Data *DocumentDiff `json:"data"`
+}
+
+// DocumentDiff returns the changes of the document data between two versions of a document
+//
+//	@Summary		DocumentDiff
+//	@ID				document-diff
+//	@Description	Returns the changes of the document data from from_version to to_version, as JSON pointers ordered by path
+//	@Tags			dc4eu
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	DocumentDiffReply	"Success"
+//	@Failure		400	{object}	helpers.Problem		"Bad Request"
+//	@Failure		404	{object}	helpers.Problem		"No such version"
+//	@Param			req	body		DocumentDiffRequest	true	" "
+//	@Router			/document/diff [post]
+func (c *Client) DocumentDiff(ctx context.Context, req *DocumentDiffRequest) (*DocumentDiffReply, error) {
+	if err := helpers.Check(ctx, c.cfg, req, c.log); err != nil {
+		return nil, err
+	}
+
+	meta := &model.MetaData{
+		AuthenticSource: req.AuthenticSource,
+		DocumentType:    req.DocumentType,
+		DocumentID:      req.DocumentID,
+	}
+
+	from, err := c.db.VCDocumentVersionColl.Get(ctx, meta, req.FromVersion)
+	if err != nil {
+		return nil, err
+	}
+	to, err := c.db.VCDocumentVersionColl.Get(ctx, meta, req.ToVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := jsondiff.Diff(from.DocumentData, to.DocumentData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DocumentDiffReply{
+		Data: &DocumentDiff{
+			FromVersion: req.FromVersion,
+			ToVersion:   req.ToVersion,
+			Changes:     changes,
+		},
+	}, nil
 }
 
 // RevokeDocument revokes a specific document
@@ -437,9 +673,12 @@ type RevokeDocumentRequest struct {
 //	@Tags			dc4eu
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	"Success"
-//	@Failure		400	{object}	helpers.ErrorResponse	"Bad Request"
-//	@Param			req	body		RevokeDocumentRequest	true	" "
+//	@Success		200				"Success"
+//	@Failure		400				{object}	helpers.Problem			"Bad Request"
+//	@Failure		412				{object}	helpers.Problem			"Precondition Failed"
+//	@Param			If-Match		header		string					true	"Document revision, from ETag"
+//	@Param			req				body		RevokeDocumentRequest	true	" "
+//	@Param			Idempotency-Key	header		string					false	"Retries with the same key get the stored response"
 //	@Router			/document/revoke [post]
 func (c *Client) RevokeDocument(ctx context.Context, req *RevokeDocumentRequest) error {
 	ctx, span := c.tracer.Start(ctx, "db:apigw:datastore:revoke")
@@ -449,29 +688,61 @@ func (c *Client) RevokeDocument(ctx context.Context, req *RevokeDocumentRequest)
 		return helpers.ErrNoRevocationID
 	}
 
-	doc, err := c.db.VCDatastoreColl.GetByRevocationID(ctx, &model.MetaData{
-		AuthenticSource: req.AuthenticSource,
-		DocumentType:    req.DocumentType,
-		Revocation:      &model.Revocation{ID: req.Revocation.ID},
+	var revoked *model.MetaData
+	err := c.db.WithTransaction(ctx, func(ctx context.Context) error {
+		doc, err := c.db.VCDatastoreColl.GetByRevocationID(ctx, &model.MetaData{
+			AuthenticSource: req.AuthenticSource,
+			DocumentType:    req.DocumentType,
+			Revocation:      &model.Revocation{ID: req.Revocation.ID},
+		})
+		if err != nil {
+			return err
+		}
+		c.log.Debug("Document found", "document_id", doc.Meta.DocumentID)
+
+		if req.Revision != nil && *req.Revision != doc.Meta.Revision {
+			return helpers.ErrPreconditionFailed
+		}
+
+		doc.Meta.Revocation = req.Revocation
+
+		if req.Revocation.RevokedAt == 0 {
+			doc.Meta.Revocation.RevokedAt = time.Now().Unix()
+			doc.Meta.Revocation.Revoked = true
+		}
+
+		actor := req.Actor
+		if actor == "" {
+			actor = req.AuthenticSource
+		}
+		doc.StatusHistory = append(doc.StatusHistory, model.DocumentStatus{
+			Status:     model.DocumentStatusRevoked,
+			ReasonCode: doc.Meta.Revocation.ReasonCode,
+			Reason:     doc.Meta.Revocation.Reason,
+			Actor:      actor,
+			Timestamp:  doc.Meta.Revocation.RevokedAt,
+		})
+
+		if err := c.db.VCDatastoreColl.Replace(ctx, doc); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			c.log.Error(err, "replace failed")
+			return err
+		}
+		c.log.Debug("Document enqueued for update", "document_id", doc.Meta.DocumentID)
+		revoked = doc.Meta
+
+		return nil
 	})
 	if err != nil {
 		return err
 	}
-	c.log.Debug("Document found", "document_id", doc.Meta.DocumentID)
 
-	doc.Meta.Revocation = req.Revocation
+	c.metrics.revoked(ctx, revoked)
 
-	if req.Revocation.RevokedAt == 0 {
-		doc.Meta.Revocation.RevokedAt = time.Now().Unix()
-		doc.Meta.Revocation.Revoked = true
-	}
-
-	if err := c.db.VCDatastoreColl.Replace(ctx, doc); err != nil {
-		span.SetStatus(codes.Error, err.Error())
-		c.log.Error(err, "replace failed")
-		return err
-	}
-	c.log.Debug("Document enqueued for update", "document_id", doc.Meta.DocumentID)
+	c.publishWebhook(ctx, model.WebhookEventDocumentRevoked, revoked, map[string]any{
+		"revoked_at":  revoked.Revocation.RevokedAt,
+		"reason_code": revoked.Revocation.ReasonCode,
+	})
 
 	return nil
 }
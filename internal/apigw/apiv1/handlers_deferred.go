@@ -0,0 +1,145 @@
+package apiv1
+
+import (
+	"context"
+	"errors"
+	"time"
+	"vc/internal/gen/issuer/apiv1_issuer"
+	"vc/pkg/datastoreclient"
+	"vc/pkg/helpers"
+	"vc/pkg/model"
+
+	"github.com/google/uuid"
+)
+
+// documentNotReady reports if err means the authentic source has not yet provided the document, or a reviewer has not
+// yet decided on the identity
+func documentNotReady(err error) bool {
+	return errors.Is(err, helpers.ErrNoDocumentFound) || errors.Is(err, datastoreclient.ErrNotFound) || errors.Is(err, helpers.ErrIdentityReviewPending)
+}
+
+func (c *Client) deferCredential(ctx context.Context, req *CredentialRequest) (*apiv1_issuer.MakeSDJWTReply, error) {
+	now := time.Now().Unix()
+	deferred := &model.DeferredCredential{
+		TransactionID:   uuid.NewString(),
+		AuthenticSource: req.AuthenticSource,
+		DocumentType:    req.DocumentType,
+		CredentialType:  req.CredentialType,
+		CollectID:       req.CollectID,
+		Identity:        req.Identity,
+		HolderJWK:       req.holderJWK,
+		Status:          model.DeferredStatusPending,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := c.db.VCDeferredCredentialColl.Add(ctx, deferred); err != nil {
+		return nil, err
+	}
+
+	c.log.Info("credential deferred", "transaction_id", deferred.TransactionID, "authentic_source", req.AuthenticSource, "document_type", req.DocumentType)
+
+	return &apiv1_issuer.MakeSDJWTReply{TransactionID: deferred.TransactionID}, nil
+}
+
+// DeferredCredentialRequest is the request for DeferredCredential
+type DeferredCredentialRequest struct {
+	TransactionID string `json:"transaction_id" validate:"required"`
+}
+
+// DeferredCredential returns a deferred credential once issued
+//
+//	@Summary		DeferredCredential
+//	@ID				deferred-credential
+//	@Description	OpenID4VCI deferred credential endpoint, returns ISSUANCE_PENDING until the authentic source has provided the document
+//	@Tags			dc4eu
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	apiv1_issuer.MakeSDJWTReply	"Success"
+//	@Failure		400	{object}	helpers.Problem				"Bad Request"
+//	@Param			req	body		DeferredCredentialRequest	true	" "
+//	@Router			/credential/deferred [post]
+func (c *Client) DeferredCredential(ctx context.Context, req *DeferredCredentialRequest) (*apiv1_issuer.MakeSDJWTReply, error) {
+	if err := helpers.Check(ctx, c.cfg, req, c.log); err != nil {
+		return nil, err
+	}
+
+	deferred, err := c.db.VCDeferredCredentialColl.Get(ctx, req.TransactionID)
+	if err != nil {
+		if errors.Is(err, helpers.ErrNoDocumentFound) {
+			return nil, helpers.ErrInvalidTransactionID
+		}
+		return nil, err
+	}
+
+	switch deferred.Status {
+	case model.DeferredStatusPending:
+		return nil, helpers.ErrIssuancePending
+	case model.DeferredStatusFailed:
+		return nil, helpers.ErrNoDocumentFound
+	}
+
+	// a transaction_id is good for one credential
+	if err := c.db.VCDeferredCredentialColl.Delete(ctx, req.TransactionID); err != nil {
+		return nil, err
+	}
+
+	return &apiv1_issuer.MakeSDJWTReply{
+		Jwt:            deferred.Credential.JWT,
+		Disclosures:    deferred.Credential.Disclosures,
+		NotificationID: deferred.Credential.NotificationID,
+	}, nil
+}
+
+// ProcessDeferred retries document collection for one batch of pending deferred credentials, and returns the number
+// of issued credentials
+func (c *Client) ProcessDeferred(ctx context.Context) (int, error) {
+	ctx, span := c.tracer.Start(ctx, "apiv1:ProcessDeferred")
+	defer span.End()
+
+	pending, err := c.db.VCDeferredCredentialColl.Pending(ctx, c.cfg.APIGW.Deferred.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	issued := 0
+	for _, deferred := range pending {
+		req := &CredentialRequest{
+			AuthenticSource: deferred.AuthenticSource,
+			Identity:        deferred.Identity,
+			DocumentType:    deferred.DocumentType,
+			CredentialType:  deferred.CredentialType,
+			CollectID:       deferred.CollectID,
+			holderJWK:       deferred.HolderJWK,
+		}
+
+		reply, err := c.credential(ctx, req)
+		deferred.UpdatedAt = time.Now().Unix()
+
+		if err == nil {
+			deferred.Status = model.DeferredStatusIssued
+			deferred.Credential = &model.DeferredCredentialResult{
+				JWT:            reply.GetJwt(),
+				Disclosures:    reply.GetDisclosures(),
+				NotificationID: reply.GetNotificationID(),
+			}
+			c.issuance.record(req.AuthenticSource, req.DocumentType, nil)
+			issued++
+		} else {
+			if !documentNotReady(err) {
+				c.log.Error(err, "deferred credential attempt failed", "transaction_id", deferred.TransactionID)
+			}
+			deferred.Attempts++
+			if deferred.Attempts >= c.cfg.APIGW.Deferred.MaxAttempts || errors.Is(err, helpers.ErrDocumentIsRevoked) || errors.Is(err, helpers.ErrIdentityReviewRejected) {
+				deferred.Status = model.DeferredStatusFailed
+				c.issuance.record(req.AuthenticSource, req.DocumentType, err)
+				c.log.Info("deferred credential failed", "transaction_id", deferred.TransactionID, "attempts", deferred.Attempts)
+			}
+		}
+
+		if err := c.db.VCDeferredCredentialColl.Update(ctx, deferred); err != nil {
+			c.log.Error(err, "failed to update deferred credential", "transaction_id", deferred.TransactionID)
+		}
+	}
+
+	return issued, nil
+}
@@ -0,0 +1,50 @@
+package apiv1
+
+import (
+	"context"
+)
+
+// EntityConfiguration returns the signed OpenID Federation entity configuration of the credential issuer
+//
+//	@Summary		Entity configuration
+//	@ID				openid-federation
+//	@Description	OpenID Federation entity configuration, an entity-statement+jwt
+//	@Tags			federation
+//	@Produce		application/entity-statement+jwt
+//	@Success		200	{string}	string			"Success"
+//	@Failure		500	{object}	helpers.Problem	"Internal Server Error"
+//	@Router			/.well-known/openid-federation [get]
+func (c *Client) EntityConfiguration(ctx context.Context) (string, error) {
+	ctx, span := c.tracer.Start(ctx, "apiv1:EntityConfiguration")
+	defer span.End()
+
+	return c.federation.EntityConfiguration(ctx)
+}
+
+// TrustChainReply is the reply for TrustChain
+type TrustChainReply struct {
+	// TrustChain starts with the entity configuration and ends with the entity configuration of a trust anchor
+	TrustChain []string `json:"trust_chain"`
+}
+
+// TrustChain resolves the trust chain of the credential issuer through its authority hints
+//
+//	@Summary		Trust chain
+//	@ID				federation-trust-chain
+//	@Description	Trust chain from the entity configuration to a configured trust anchor
+//	@Tags			federation
+//	@Produce		json
+//	@Success		200	{object}	TrustChainReply	"Success"
+//	@Failure		400	{object}	helpers.Problem	"Bad Request"
+//	@Router			/federation/trust_chain [get]
+func (c *Client) TrustChain(ctx context.Context) (*TrustChainReply, error) {
+	ctx, span := c.tracer.Start(ctx, "apiv1:TrustChain")
+	defer span.End()
+
+	chain, err := c.federation.TrustChain(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TrustChainReply{TrustChain: chain}, nil
+}
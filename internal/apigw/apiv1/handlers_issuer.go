@@ -3,12 +3,20 @@ package apiv1
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"time"
+	"vc/internal/apigw/db"
 	"vc/internal/gen/issuer/apiv1_issuer"
 	"vc/internal/gen/registry/apiv1_registry"
 	"vc/pkg/datastoreclient"
 	"vc/pkg/helpers"
+	"vc/pkg/keyresolver"
 	"vc/pkg/model"
+	"vc/pkg/openid4vci"
+	"vc/pkg/tenant"
+	"vc/pkg/trace"
 
+	"github.com/google/uuid"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
@@ -20,27 +28,75 @@ type CredentialRequest struct {
 	DocumentType    string          `json:"document_type" validate:"required"`
 	CredentialType  string          `json:"credential_type" validate:"required"`
 	CollectID       string          `json:"collect_id" validate:"required"`
+
+	// DryRun returns the unsigned payload and disclosures instead of a signed credential
+	DryRun bool `json:"dry_run"`
+
+	// Proof is the proof of possession of the holder key, the credential is bound to the key in its cnf claim
+	Proof *openid4vci.Proof `json:"proof"`
+
+	// holderJWK is the holder key of the verified Proof
+	holderJWK string
 }
 
 // Credential makes a credential
 //
 //	@Summary		Credential
 //	@ID				create-credential
-//	@Description	Create credential endpoint
+//	@Description	Create credential endpoint, with dry_run the unsigned payload and disclosures are returned without signing. If deferred issuance is enabled and the document is not yet available, only transactionID is returned
 //	@Tags			dc4eu
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{object}	apiv1_issuer.MakeSDJWTReply	"Success"
-//	@Failure		400	{object}	helpers.ErrorResponse		"Bad Request"
-//	@Param			req	body		CredentialRequest			true	" "
+//	@Success		200				{object}	apiv1_issuer.MakeSDJWTReply	"Success"
+//	@Failure		400				{object}	helpers.Problem				"Bad Request"
+//	@Failure		409				{object}	helpers.Problem				"Collect id used the maximum number of times"
+//	@Failure		410				{object}	helpers.Problem				"Collect id expired"
+//	@Param			req				body		CredentialRequest			true	" "
+//	@Param			Idempotency-Key	header		string						false	"Retries with the same key get the stored response"
 //	@Router			/credential [post]
 func (c *Client) Credential(ctx context.Context, req *CredentialRequest) (*apiv1_issuer.MakeSDJWTReply, error) {
-	document, _, err := c.datastoreClient.Document.CollectID(ctx, &datastoreclient.DocumentCollectIDQuery{
-		AuthenticSource: req.AuthenticSource,
-		DocumentType:    req.DocumentType,
-		CollectID:       req.CollectID,
-		Identity:        req.Identity,
-	})
+	// verified once here, a deferred credential is issued after the proof is too old to verify
+	if err := c.verifyProof(ctx, req); err != nil {
+		return nil, err
+	}
+
+	reply, err := c.credential(ctx, req)
+	if documentNotReady(err) && c.cfg.APIGW.Deferred.Enabled && !req.DryRun {
+		return c.deferCredential(ctx, req)
+	}
+	if !req.DryRun {
+		c.issuance.record(req.AuthenticSource, req.DocumentType, err)
+		c.metrics.issued(ctx, req.AuthenticSource, req.DocumentType, false, err)
+	}
+
+	return reply, err
+}
+
+func (c *Client) credential(ctx context.Context, req *CredentialRequest) (*apiv1_issuer.MakeSDJWTReply, error) {
+	credentialType, err := c.sdjwtCredentialType(req.CredentialType, req.DocumentType)
+	if err != nil {
+		return nil, err
+	}
+
+	var document *model.Document
+	if c.identityMatcher != nil {
+		// matched here, the review errors do not survive the datastore client
+		document, err = c.matchDocumentCollectID(ctx, &db.GetDocumentCollectIDQuery{
+			Identity: req.Identity,
+			Meta: &model.MetaData{
+				AuthenticSource: req.AuthenticSource,
+				DocumentType:    req.DocumentType,
+				Collect:         &model.Collect{ID: req.CollectID},
+			},
+		})
+	} else {
+		document, _, err = c.datastoreClient.Document.CollectID(ctx, &datastoreclient.DocumentCollectIDQuery{
+			AuthenticSource: req.AuthenticSource,
+			DocumentType:    req.DocumentType,
+			CollectID:       req.CollectID,
+			Identity:        req.Identity,
+		})
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -49,13 +105,118 @@ func (c *Client) Credential(ctx context.Context, req *CredentialRequest) (*apiv1
 		return nil, helpers.ErrNoDocumentFound
 	}
 
-	documentData, err := json.Marshal(document.DocumentData)
+	if document.Meta != nil && document.Meta.Revocation.IsRevoked(time.Now().Unix()) {
+		return nil, helpers.ErrDocumentIsRevoked
+	}
+
+	if err := c.inlineAttachments(ctx, document.DocumentData); err != nil {
+		return nil, err
+	}
+
+	documentData, err := c.credentialDocumentData(ctx, req.DocumentType, document.DocumentData)
+	if err != nil {
+		return nil, err
+	}
+
+	consentIDs, err := c.issuanceConsentIDs(ctx, document.Meta)
 	if err != nil {
 		return nil, err
 	}
 
-	// Build SDJWT
-	conn, err := grpc.NewClient(c.cfg.Issuer.GRPCServer.Addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if !req.DryRun {
+		if err := c.useCollectID(ctx, document.Meta); err != nil {
+			return nil, err
+		}
+	}
+
+	reply, err := c.makeSDJWT(ctx, &apiv1_issuer.MakeSDJWTRequest{
+		DocumentType:   req.DocumentType,
+		DocumentData:   documentData,
+		ConsentIDs:     consentIDs,
+		DryRun:         req.DryRun,
+		CredentialType: credentialType,
+		HolderJWK:      req.holderJWK,
+	})
+	if err != nil {
+		if !req.DryRun {
+			// no credential was issued with the collect code
+			if err := c.db.VCDatastoreColl.ReleaseCollectID(ctx, document.Meta); err != nil {
+				c.log.Error(err, "failed to release collect id", "document_id", document.Meta.DocumentID)
+			}
+		}
+		return nil, err
+	}
+
+	if !req.DryRun {
+		c.collectMetrics.used(ctx, document.Meta, collectOutcomeIssued)
+
+		notification := &model.CredentialNotification{
+			NotificationID:  uuid.NewString(),
+			AuthenticSource: document.Meta.AuthenticSource,
+			DocumentType:    document.Meta.DocumentType,
+			DocumentID:      document.Meta.DocumentID,
+			IssuedAt:        time.Now().Unix(),
+			Events:          []model.CredentialNotificationEvent{},
+			CredentialType:  req.CredentialType,
+			Disclosures:     reply.Disclosures,
+			HolderJWK:       req.holderJWK,
+		}
+		// the credential is already signed, a wallet without a notification_id can still use it
+		if err := c.db.VCCredentialNotificationColl.Add(ctx, notification); err != nil {
+			c.log.Error(err, "failed to add credential notification", "document_id", notification.DocumentID)
+		} else {
+			reply.NotificationID = notification.NotificationID
+		}
+
+		c.publishWebhook(ctx, model.WebhookEventCredentialIssued, document.Meta, map[string]any{
+			"credential_type": req.CredentialType,
+			"notification_id": reply.NotificationID,
+		})
+	}
+
+	return reply, nil
+}
+
+// verifyProof verifies the proof of req, if any, and sets the holder key the credential is bound to
+func (c *Client) verifyProof(ctx context.Context, req *CredentialRequest) error {
+	if req.Proof == nil {
+		return nil
+	}
+
+	key, err := req.Proof.HolderKey(ctx, keyresolver.Chain{keyresolver.DIDJWK{}, keyresolver.DIDKey{}}, c.cfg.Issuer.JWTAttribute.Issuer)
+	if err != nil {
+		c.log.Debug("proof", "err", err)
+		return helpers.ErrInvalidProof
+	}
+	b, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+	req.holderJWK = string(b)
+
+	return nil
+}
+
+// sdjwtCredentialType returns the credential type of the profile of credentialType and documentType sent to the
+// issuer. Without configured profiles credential_type is not checked, and the issuer configuration applies.
+func (c *Client) sdjwtCredentialType(credentialType, documentType string) (string, error) {
+	profile, err := c.profiles.Resolve(credentialType, documentType)
+	if err != nil {
+		return "", err
+	}
+	if profile == nil {
+		return "", nil
+	}
+	if !profile.Supports(model.CredentialFormatSDJWT) {
+		return "", helpers.ErrUnsupportedCredentialFormat
+	}
+
+	return profile.CredentialType, nil
+}
+
+// makeSDJWT calls the issuer, which signs with the key of the tenant
+func (c *Client) makeSDJWT(ctx context.Context, in *apiv1_issuer.MakeSDJWTRequest) (*apiv1_issuer.MakeSDJWTReply, error) {
+	conn, err := grpc.NewClient(c.cfg.Issuer.GRPCServer.Addr, grpc.WithTransportCredentials(insecure.NewCredentials()), trace.GRPCClientOption())
 	if err != nil {
 		c.log.Error(err, "Failed to connect to issuer")
 		return nil, err
@@ -63,10 +224,7 @@ func (c *Client) Credential(ctx context.Context, req *CredentialRequest) (*apiv1
 	defer conn.Close()
 	client := apiv1_issuer.NewIssuerServiceClient(conn)
 
-	reply, err := client.MakeSDJWT(ctx, &apiv1_issuer.MakeSDJWTRequest{
-		DocumentType: req.DocumentType,
-		DocumentData: documentData,
-	})
+	reply, err := client.MakeSDJWT(tenant.OutgoingContext(ctx), in)
 	if err != nil {
 		c.log.Error(err, "failed to call MakeSDJWT")
 		return nil, err
@@ -75,6 +233,48 @@ func (c *Client) Credential(ctx context.Context, req *CredentialRequest) (*apiv1
 	return reply, nil
 }
 
+// CredentialNotificationRequest is the OpenID4VCI notification request
+type CredentialNotificationRequest struct {
+	NotificationID   string `json:"notification_id" validate:"required"`
+	Event            string `json:"event" validate:"required,oneof=credential_accepted credential_failure credential_deleted"`
+	EventDescription string `json:"event_description"`
+}
+
+// CredentialNotification records what became of an issued credential
+//
+//	@Summary		CredentialNotification
+//	@ID				credential-notification
+//	@Description	OpenID4VCI notification endpoint, the wallet reports if the credential was accepted, failed or deleted
+//	@Tags			dc4eu
+//	@Accept			json
+//	@Produce		json
+//	@Success		204				"No Content"
+//	@Failure		400				{object}	helpers.Problem					"Bad Request"
+//	@Param			req				body		CredentialNotificationRequest	true	" "
+//	@Param			Idempotency-Key	header		string							false	"Retries with the same key get the stored response"
+//	@Router			/credential/notification [post]
+func (c *Client) CredentialNotification(ctx context.Context, req *CredentialNotificationRequest) (*model.CredentialNotification, error) {
+	if err := helpers.Check(ctx, c.cfg, req, c.log); err != nil {
+		return nil, err
+	}
+
+	notification, err := c.db.VCCredentialNotificationColl.AddEvent(ctx, req.NotificationID, &model.CredentialNotificationEvent{
+		Event:            req.Event,
+		EventDescription: req.EventDescription,
+		Timestamp:        time.Now().Unix(),
+	})
+	if err != nil {
+		if errors.Is(err, helpers.ErrNoDocumentFound) {
+			return nil, helpers.ErrInvalidNotificationID
+		}
+		return nil, err
+	}
+
+	c.log.Info("credential notification", "notification_id", req.NotificationID, "event", req.Event, "document_id", notification.DocumentID)
+
+	return notification, nil
+}
+
 // RevokeRequest is the request for GenericRevoke
 type RevokeRequest struct {
 	AuthenticSource string `json:"authentic_source"`
@@ -91,21 +291,10 @@ type RevokeReply struct {
 }
 
 // Revoke revokes a document
-//
-//	@Summary		Revoke
-//	@ID				generic-revoke
-//	@Description	Revoke endpoint
-//	@Tags			dc4eu
-//	@Accept			json
-//	@Produce		json
-//	@Success		200	{object}	RevokeReply				"Success"
-//	@Failure		400	{object}	helpers.ErrorResponse	"Bad Request"
-//	@Param			req	body		RevokeRequest			true	" "
-//	@Router			/revoke [post]
 func (c *Client) Revoke(ctx context.Context, req *RevokeRequest) (*RevokeReply, error) {
 	optInsecure := grpc.WithTransportCredentials(insecure.NewCredentials())
 
-	conn, err := grpc.NewClient(c.cfg.Registry.GRPCServer.Addr, optInsecure)
+	conn, err := grpc.NewClient(c.cfg.Registry.GRPCServer.Addr, optInsecure, trace.GRPCClientOption())
 	if err != nil {
 		return nil, err
 	}
@@ -138,13 +327,13 @@ func (c *Client) Revoke(ctx context.Context, req *RevokeRequest) (*RevokeReply,
 //	@Accept			json
 //	@Produce		json
 //	@Success		200	{object}	apiv1_issuer.JwksReply	"Success"
-//	@Failure		400	{object}	helpers.ErrorResponse	"Bad Request"
+//	@Failure		400	{object}	helpers.Problem			"Bad Request"
 //	@Router			/credential/.well-known/jwks [get]
 func (c *Client) JWKS(ctx context.Context) (*apiv1_issuer.JwksReply, error) {
 	c.log.Debug("jwk")
 	optInsecure := grpc.WithTransportCredentials(insecure.NewCredentials())
 
-	conn, err := grpc.NewClient(c.cfg.Issuer.GRPCServer.Addr, optInsecure)
+	conn, err := grpc.NewClient(c.cfg.Issuer.GRPCServer.Addr, optInsecure, trace.GRPCClientOption())
 	if err != nil {
 		return nil, err
 	}
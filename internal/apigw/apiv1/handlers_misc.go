@@ -2,7 +2,9 @@ package apiv1
 
 import (
 	"context"
+	"time"
 	"vc/internal/gen/status/apiv1_status"
+	"vc/pkg/messagebroker/kafka"
 	"vc/pkg/model"
 )
 
@@ -18,3 +20,67 @@ func (c *Client) Health(ctx context.Context, req *apiv1_status.StatusRequest) (*
 
 	return status, nil
 }
+
+// AuthenticSourceStatistics holds document and issuance counters for one authentic source
+type AuthenticSourceStatistics struct {
+	AuthenticSource   string `json:"authentic_source"`
+	Documents         int64  `json:"documents"`
+	Revoked           int64  `json:"revoked"`
+	CredentialsIssued int64  `json:"credentials_issued"`
+	CredentialsFailed int64  `json:"credentials_failed"`
+}
+
+// StatisticsReply is the reply for Statistics
+type StatisticsReply struct {
+	Timestamp        int64                        `json:"timestamp"`
+	AuthenticSources []*AuthenticSourceStatistics `json:"authentic_sources"`
+	RecentErrors     []*IssuanceError             `json:"recent_errors"`
+
+	// QueueDepth is the number of uploads waiting in kafka, omitted when kafka is disabled or unreachable
+	QueueDepth *int64 `json:"queue_depth,omitempty"`
+}
+
+// Statistics return issuance statistics per authentic source
+//
+//	@Summary		Statistics
+//	@ID				statistics
+//	@Description	Issuance statistics per authentic source, recent errors and queue depth. Issuance counters are kept in memory since start.
+//	@Tags			dc4eu
+//	@Produce		json
+//	@Success		200	{object}	StatisticsReply	"Success"
+//	@Failure		400	{object}	helpers.Problem	"Bad Request"
+//	@Router			/statistics [get]
+func (c *Client) Statistics(ctx context.Context) (*StatisticsReply, error) {
+	ctx, span := c.tracer.Start(ctx, "apiv1:Statistics")
+	defer span.End()
+
+	documentStatistics, err := c.db.VCDatastoreColl.Statistics(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := map[string]*AuthenticSourceStatistics{}
+	for _, d := range documentStatistics {
+		if _, ok := stats[d.AuthenticSource]; !ok {
+			stats[d.AuthenticSource] = &AuthenticSourceStatistics{AuthenticSource: d.AuthenticSource}
+		}
+		stats[d.AuthenticSource].Documents += d.Documents
+		stats[d.AuthenticSource].Revoked += d.Revoked
+	}
+
+	reply := &StatisticsReply{
+		Timestamp: time.Now().Unix(),
+	}
+	reply.AuthenticSources, reply.RecentErrors = c.issuance.merge(stats)
+
+	if c.kafkaAdmin != nil {
+		lag, err := c.kafkaAdmin.Lag(kafka.ConsumerGroupUploadAPIGW, kafka.TopicUpload)
+		if err != nil {
+			c.log.Error(err, "failed to get queue depth")
+		} else {
+			reply.QueueDepth = &lag
+		}
+	}
+
+	return reply, nil
+}
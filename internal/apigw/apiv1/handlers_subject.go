@@ -0,0 +1,272 @@
+package apiv1
+
+import (
+	"context"
+	"errors"
+	"time"
+	"vc/internal/apigw/db"
+	"vc/pkg/helpers"
+	"vc/pkg/model"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// SubjectDataRequest identifies a data subject within an authentic source
+type SubjectDataRequest struct {
+	// required: true
+	// example: SUNET
+	AuthenticSource string `json:"authentic_source" validate:"required"`
+
+	// required: true
+	// example: 65636cbc-c03f-11ee-8dc4-67135cc9bd8a
+	AuthenticSourcePersonID string `json:"authentic_source_person_id" validate:"required"`
+}
+
+// SubjectDataExport is all data connected to a data subject
+type SubjectDataExport struct {
+	AuthenticSource         string `json:"authentic_source"`
+	AuthenticSourcePersonID string `json:"authentic_source_person_id"`
+
+	// format: int64
+	ExportedAt int64 `json:"exported_at"`
+
+	// Documents with identities and status history
+	Documents               []*model.CompleteDocument       `json:"documents"`
+	Consent                 *model.Consent                  `json:"consent,omitempty"`
+	DocumentConsents        []*model.DocumentConsent        `json:"document_consents"`
+	ConsentAudit            []*model.ConsentAuditEntry      `json:"consent_audit"`
+	CredentialNotifications []*model.CredentialNotification `json:"credential_notifications"`
+	DeferredCredentials     []*model.DeferredCredential     `json:"deferred_credentials"`
+}
+
+// ExportSubjectDataReply is the reply for ExportSubjectData
+type ExportSubjectDataReply struct {
+	Data *SubjectDataExport `json:"data"`
+}
+
+// ExportSubjectData returns all data connected to an authentic source person id, GDPR right of access and portability
+//
+//	@Summary		Export data subject
+//	@ID				admin-export-subject
+//	@Description	Returns the documents, identities, status history, consents, consent audit, credential notifications and deferred credentials of a data subject
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	ExportSubjectDataReply	"Success"
+//	@Failure		400	{object}	helpers.Problem			"Bad Request"
+//	@Param			req	body		SubjectDataRequest		true	" "
+//	@Router			/admin/subjects/export [post]
+func (c *Client) ExportSubjectData(ctx context.Context, req *SubjectDataRequest) (*ExportSubjectDataReply, error) {
+	ctx, span := c.tracer.Start(ctx, "apiv1:ExportSubjectData")
+	defer span.End()
+
+	if err := helpers.Check(ctx, c.cfg, req, c.log); err != nil {
+		return nil, err
+	}
+
+	docs, err := c.db.VCDatastoreColl.FindBySubject(ctx, req.AuthenticSource, req.AuthenticSourcePersonID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &SubjectDataExport{
+		AuthenticSource:         req.AuthenticSource,
+		AuthenticSourcePersonID: req.AuthenticSourcePersonID,
+		ExportedAt:              time.Now().Unix(),
+		Documents:               docs,
+		DocumentConsents:        []*model.DocumentConsent{},
+		ConsentAudit:            []*model.ConsentAuditEntry{},
+		CredentialNotifications: []*model.CredentialNotification{},
+	}
+
+	consentIDs := []string{}
+	for _, doc := range docs {
+		consents, err := c.db.VCDocumentConsentColl.List(ctx, &db.DocumentConsentQuery{
+			AuthenticSource: doc.Meta.AuthenticSource,
+			DocumentType:    doc.Meta.DocumentType,
+			DocumentID:      doc.Meta.DocumentID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, consent := range consents {
+			consentIDs = append(consentIDs, consent.ConsentID)
+		}
+		export.DocumentConsents = append(export.DocumentConsents, consents...)
+
+		notifications, err := c.db.VCCredentialNotificationColl.ListByDocument(ctx, doc.Meta)
+		if err != nil {
+			return nil, err
+		}
+		export.CredentialNotifications = append(export.CredentialNotifications, notifications...)
+	}
+
+	if len(consentIDs) > 0 {
+		export.ConsentAudit, err = c.db.VCConsentAuditColl.List(ctx, consentIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	export.Consent, err = c.db.VCConsentColl.Get(ctx, &db.GetConsentQuery{
+		AuthenticSource:         req.AuthenticSource,
+		AuthenticSourcePersonID: req.AuthenticSourcePersonID,
+	})
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+
+	export.DeferredCredentials, err = c.db.VCDeferredCredentialColl.ListBySubject(ctx, req.AuthenticSource, req.AuthenticSourcePersonID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.log.Info("exported data subject", "authentic_source", req.AuthenticSource, "documents", len(docs))
+
+	return &ExportSubjectDataReply{Data: export}, nil
+}
+
+// ErasureJobReply is the reply for EraseSubjectData and ErasureJobStatus
+type ErasureJobReply struct {
+	Data *model.ErasureJob `json:"data"`
+}
+
+// EraseSubjectData starts an erasure job for all data connected to an authentic source person id, GDPR right to erasure
+//
+//	@Summary		Erase data subject
+//	@ID				admin-erase-subject
+//	@Description	Starts a job that deletes the documents only identifying the subject, removes the subject from shared documents, deletes consents, credential notifications and deferred credentials, and pseudonymizes the consent audit. The job is run by the erasure worker, poll it with the returned job_id
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Success		200				{object}	ErasureJobReply		"Success"
+//	@Failure		400				{object}	helpers.Problem		"Bad Request"
+//	@Param			req				body		SubjectDataRequest	true	" "
+//	@Param			Idempotency-Key	header		string				false	"Retries with the same key get the stored response"
+//	@Router			/admin/subjects/erasure [post]
+func (c *Client) EraseSubjectData(ctx context.Context, req *SubjectDataRequest) (*ErasureJobReply, error) {
+	ctx, span := c.tracer.Start(ctx, "apiv1:EraseSubjectData")
+	defer span.End()
+
+	if err := helpers.Check(ctx, c.cfg, req, c.log); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	job := &model.ErasureJob{
+		JobID:           uuid.NewString(),
+		AuthenticSource: req.AuthenticSource,
+		SubjectHash:     db.Pseudonym(req.AuthenticSourcePersonID),
+		Status:          model.ErasureJobPending,
+		Subject:         &model.Identity{AuthenticSourcePersonID: req.AuthenticSourcePersonID},
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := c.db.VCErasureJobColl.Add(ctx, job); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return &ErasureJobReply{Data: job}, nil
+}
+
+// ErasureJobStatusRequest is the request for ErasureJobStatus
+type ErasureJobStatusRequest struct {
+	AuthenticSource string `form:"authentic_source" validate:"required"`
+	JobID           string `uri:"job_id" validate:"required"`
+}
+
+// ErasureJobStatus returns an erasure job
+//
+//	@Summary		Erasure job status
+//	@ID				admin-erasure-job-status
+//	@Description	Returns the status and erased counts of an erasure job, a job interrupted by a restart is resumed
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200					{object}	ErasureJobReply	"Success"
+//	@Failure		400					{object}	helpers.Problem	"Bad Request"
+//	@Param			job_id				path		string			true	"Job ID"
+//	@Param			authentic_source	query		string			true	"Authentic source"
+//	@Router			/admin/subjects/erasure/{job_id} [get]
+func (c *Client) ErasureJobStatus(ctx context.Context, req *ErasureJobStatusRequest) (*ErasureJobReply, error) {
+	ctx, span := c.tracer.Start(ctx, "apiv1:ErasureJobStatus")
+	defer span.End()
+
+	job, err := c.db.VCErasureJobColl.Get(ctx, req.AuthenticSource, req.JobID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ErasureJobReply{Data: job}, nil
+}
+
+// erasureJobLease is how long a running erasure job may go without progress before another worker resumes it
+const erasureJobLease = 10 * time.Minute
+
+// ProcessErasureJobs runs the pending erasure jobs of the tenant of ctx, and running jobs left without progress, and
+// returns the number of completed jobs. A failed run is retried by the next call until cfg.APIGW.Erasure.MaxAttempts.
+func (c *Client) ProcessErasureJobs(ctx context.Context) (int, error) {
+	ctx, span := c.tracer.Start(ctx, "apiv1:ProcessErasureJobs")
+	defer span.End()
+
+	completed := 0
+	// a job failing within this call is left to the next call
+	claimed := []string{}
+	for {
+		job, err := c.db.VCErasureJobColl.Claim(ctx, time.Now().Add(-erasureJobLease).Unix(), claimed)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return completed, err
+		}
+		if job == nil {
+			return completed, nil
+		}
+		claimed = append(claimed, job.JobID)
+
+		if err := c.runErasureJob(ctx, job); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return completed, err
+		}
+		if job.Status == model.ErasureJobCompleted {
+			completed++
+		}
+	}
+}
+
+// runErasureJob runs a claimed erasure job and stores its outcome, an error is only returned if the outcome could not
+// be stored, the job is then resumed once its lease has expired
+func (c *Client) runErasureJob(ctx context.Context, job *model.ErasureJob) error {
+	ctx, span := c.tracer.Start(ctx, "apiv1:runErasureJob")
+	defer span.End()
+
+	err := c.db.EraseSubject(ctx, job)
+	switch {
+	case err == nil:
+		job.Status = model.ErasureJobCompleted
+		job.Error = ""
+		job.CompletedAt = time.Now().Unix()
+	case job.Attempts >= c.cfg.APIGW.Erasure.MaxAttempts:
+		span.SetStatus(codes.Error, err.Error())
+		c.log.Error(err, "erasure job failed", "job_id", job.JobID, "attempts", job.Attempts)
+		job.Status = model.ErasureJobFailed
+		job.Error = err.Error()
+		job.CompletedAt = time.Now().Unix()
+	default:
+		c.log.Info("erasure job attempt failed, retrying", "job_id", job.JobID, "attempts", job.Attempts, "error", err)
+		job.Status = model.ErasureJobPending
+		job.Error = err.Error()
+	}
+
+	if err := c.db.VCErasureJobColl.Update(ctx, job); err != nil {
+		c.log.Error(err, "failed to update erasure job", "job_id", job.JobID)
+		return err
+	}
+
+	if job.Status == model.ErasureJobCompleted {
+		c.log.Info("erasure job done", "job_id", job.JobID, "documents", job.Erased.Documents, "identities", job.Erased.Identities)
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+package apiv1
+
+import (
+	"context"
+	"time"
+	"vc/pkg/helpers"
+	"vc/pkg/httphelpers"
+	"vc/pkg/model"
+
+	"github.com/google/uuid"
+)
+
+// publishWebhook queues a webhook event, the request that caused the event does not fail if queueing fails
+func (c *Client) publishWebhook(ctx context.Context, event string, meta *model.MetaData, data map[string]any) {
+	if c.webhook == nil || meta == nil {
+		return
+	}
+
+	if err := c.webhook.Publish(ctx, event, meta, data); err != nil {
+		c.log.Error(err, "failed to publish webhook event", "event", event, "document_id", meta.DocumentID)
+	}
+}
+
+// AddWebhookRequest is the request for AddWebhook
+type AddWebhookRequest struct {
+	AuthenticSource string   `json:"authentic_source" validate:"required"`
+	URL             string   `json:"url" validate:"required,url"`
+	Secret          string   `json:"secret" validate:"required,min=32"`
+	Events          []string `json:"events" validate:"dive,oneof=document_uploaded identity_added credential_issued credential_refreshed document_revoked"`
+}
+
+// AddWebhookReply is the reply for AddWebhook
+type AddWebhookReply struct {
+	Data *model.WebhookSubscription `json:"data"`
+}
+
+// AddWebhook subscribes a webhook to document lifecycle events
+//
+//	@Summary		AddWebhook
+//	@ID				add-webhook
+//	@Description	Subscribe a webhook to the document lifecycle events of an authentic source, all events if events is empty. The url must start with a prefix in apigw.webhook.allow_list and resolve to public addresses. Deliveries are signed in the X-Webhook-Signature header as t=<unix time>,v1=<hex HMAC-SHA256 of "<unix time>.<body>">
+//	@Tags			dc4eu
+//	@Accept			json
+//	@Produce		json
+//	@Success		200				{object}	AddWebhookReply		"Success"
+//	@Failure		400				{object}	helpers.Problem		"Bad Request"
+//	@Param			req				body		AddWebhookRequest	true	" "
+//	@Param			Idempotency-Key	header		string				false	"Retries with the same key get the stored response"
+//	@Router			/webhook [post]
+func (c *Client) AddWebhook(ctx context.Context, req *AddWebhookRequest) (*AddWebhookReply, error) {
+	if err := helpers.Check(ctx, c.cfg, req, c.log); err != nil {
+		return nil, err
+	}
+
+	// deliveries are checked again when connecting, the host may resolve to another address by then
+	if !httphelpers.AllowedURL(req.URL, c.cfg.APIGW.Webhook.AllowList) {
+		return nil, helpers.NewErrorDetails("VALIDATION_ERROR", []map[string]any{
+			{"field": "url", "message": "not in the webhook allow list"},
+		})
+	}
+	if err := httphelpers.CheckPublicURL(ctx, req.URL); err != nil {
+		return nil, helpers.NewErrorDetails("VALIDATION_ERROR", []map[string]any{
+			{"field": "url", "message": err.Error()},
+		})
+	}
+
+	subscription := &model.WebhookSubscription{
+		ID:              uuid.NewString(),
+		AuthenticSource: req.AuthenticSource,
+		URL:             req.URL,
+		Secret:          req.Secret,
+		Events:          req.Events,
+		CreatedAt:       time.Now().Unix(),
+	}
+	if subscription.Events == nil {
+		subscription.Events = []string{}
+	}
+
+	if err := c.db.VCWebhookColl.Add(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	return &AddWebhookReply{Data: subscription}, nil
+}
+
+// ListWebhooksRequest is the request for ListWebhooks
+type ListWebhooksRequest struct {
+	AuthenticSource string `json:"authentic_source" validate:"required"`
+}
+
+// ListWebhooksReply is the reply for ListWebhooks
+type ListWebhooksReply struct {
+	Data []*model.WebhookSubscription `json:"data"`
+}
+
+// ListWebhooks lists the webhooks of an authentic source
+//
+//	@Summary		ListWebhooks
+//	@ID				list-webhooks
+//	@Description	List the webhook subscriptions of an authentic source
+//	@Tags			dc4eu
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	ListWebhooksReply	"Success"
+//	@Failure		400	{object}	helpers.Problem		"Bad Request"
+//	@Param			req	body		ListWebhooksRequest	true	" "
+//	@Router			/webhook/list [post]
+func (c *Client) ListWebhooks(ctx context.Context, req *ListWebhooksRequest) (*ListWebhooksReply, error) {
+	if err := helpers.Check(ctx, c.cfg, req, c.log); err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := c.db.VCWebhookColl.List(ctx, req.AuthenticSource)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListWebhooksReply{Data: subscriptions}, nil
+}
+
+// DeleteWebhookRequest is the request for DeleteWebhook
+type DeleteWebhookRequest struct {
+	AuthenticSource string `json:"authentic_source" validate:"required"`
+	ID              string `json:"id" validate:"required"`
+}
+
+// DeleteWebhook deletes a webhook
+//
+//	@Summary		DeleteWebhook
+//	@ID				delete-webhook
+//	@Description	Delete a webhook subscription, its pending deliveries are given up
+//	@Tags			dc4eu
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	"Success"
+//	@Failure		400	{object}	helpers.Problem			"Bad Request"
+//	@Param			req	body		DeleteWebhookRequest	true	" "
+//	@Router			/webhook [delete]
+func (c *Client) DeleteWebhook(ctx context.Context, req *DeleteWebhookRequest) error {
+	if err := helpers.Check(ctx, c.cfg, req, c.log); err != nil {
+		return err
+	}
+
+	return c.db.VCWebhookColl.Delete(ctx, req.AuthenticSource, req.ID)
+}
+
+// WebhookDeliveriesRequest is the request for WebhookDeliveries
+type WebhookDeliveriesRequest struct {
+	AuthenticSource string `json:"authentic_source" validate:"required"`
+	ID              string `json:"id" validate:"required"`
+	Limit           int64  `json:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+// WebhookDeliveriesReply is the reply for WebhookDeliveries
+type WebhookDeliveriesReply struct {
+	Data []*model.WebhookDelivery `json:"data"`
+}
+
+// WebhookDeliveries returns the delivery log of a webhook
+//
+//	@Summary		WebhookDeliveries
+//	@ID				webhook-deliveries
+//	@Description	Delivery log of a webhook subscription, newest first, limit defaults to 100
+//	@Tags			dc4eu
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	WebhookDeliveriesReply		"Success"
+//	@Failure		400	{object}	helpers.Problem				"Bad Request"
+//	@Param			req	body		WebhookDeliveriesRequest	true	" "
+//	@Router			/webhook/deliveries [post]
+func (c *Client) WebhookDeliveries(ctx context.Context, req *WebhookDeliveriesRequest) (*WebhookDeliveriesReply, error) {
+	if err := helpers.Check(ctx, c.cfg, req, c.log); err != nil {
+		return nil, err
+	}
+
+	subscription, err := c.db.VCWebhookColl.Get(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription.AuthenticSource != req.AuthenticSource {
+		return nil, helpers.ErrNoDocumentFound
+	}
+
+	if req.Limit == 0 {
+		req.Limit = 100
+	}
+
+	deliveries, err := c.db.VCWebhookDeliveryColl.List(ctx, req.ID, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WebhookDeliveriesReply{Data: deliveries}, nil
+}
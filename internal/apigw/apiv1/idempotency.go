@@ -0,0 +1,31 @@
+package apiv1
+
+import (
+	"context"
+	"vc/pkg/model"
+)
+
+// ReserveIdempotencyKey stores record, pending, if its key is not already stored. The stored record is returned if it
+// is, or nil if record was stored.
+func (c *Client) ReserveIdempotencyKey(ctx context.Context, record *model.IdempotencyRecord) (*model.IdempotencyRecord, error) {
+	ctx, span := c.tracer.Start(ctx, "apiv1:ReserveIdempotencyKey")
+	defer span.End()
+
+	return c.db.VCIdempotencyColl.Reserve(ctx, record)
+}
+
+// CompleteIdempotencyKey stores the response of a reserved record
+func (c *Client) CompleteIdempotencyKey(ctx context.Context, record *model.IdempotencyRecord) error {
+	ctx, span := c.tracer.Start(ctx, "apiv1:CompleteIdempotencyKey")
+	defer span.End()
+
+	return c.db.VCIdempotencyColl.Complete(ctx, record)
+}
+
+// ReleaseIdempotencyKey deletes a reserved record, so the request can be retried with the same key
+func (c *Client) ReleaseIdempotencyKey(ctx context.Context, record *model.IdempotencyRecord) error {
+	ctx, span := c.tracer.Start(ctx, "apiv1:ReleaseIdempotencyKey")
+	defer span.End()
+
+	return c.db.VCIdempotencyColl.Release(ctx, record)
+}
@@ -0,0 +1,215 @@
+package apiv1
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+	"vc/internal/apigw/db"
+	"vc/pkg/helpers"
+	"vc/pkg/identitymatch"
+	"vc/pkg/model"
+
+	"github.com/google/uuid"
+)
+
+// matchDocumentCollectID returns the document of the collect code of query if its identity matches an identity of the
+// document. A request with authentic_source_person_id must match it exactly, else the best matching identity is
+// scored: a good match returns the document, a borderline match is queued for review and returns
+// ErrIdentityReviewPending until a reviewer decides.
+func (c *Client) matchDocumentCollectID(ctx context.Context, query *db.GetDocumentCollectIDQuery) (*model.Document, error) {
+	if query.Identity.AuthenticSourcePersonID != "" {
+		return c.db.VCDatastoreColl.GetDocumentCollectID(ctx, query)
+	}
+
+	doc, err := c.db.VCDatastoreColl.GetDocumentCollectIDCandidate(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []model.Identity{}
+	for _, identity := range doc.Identities {
+		if identity.Schema != nil && identity.Schema.Name == query.Identity.Schema.Name {
+			candidates = append(candidates, identity)
+		}
+	}
+
+	best, result := c.identityMatcher.Best(query.Identity, candidates)
+	if result == nil || result.Decision == identitymatch.DecisionReject {
+		return nil, helpers.ErrNoDocumentFound
+	}
+
+	document := &model.Document{
+		Meta:         doc.Meta,
+		DocumentData: doc.DocumentData,
+	}
+	if result.Decision == identitymatch.DecisionApprove {
+		return document, nil
+	}
+
+	review, err := c.identityReview(ctx, doc.Meta, query.Identity, &candidates[best], result)
+	if err != nil {
+		return nil, err
+	}
+
+	switch review.Status {
+	case model.IdentityReviewApproved:
+		return document, nil
+	case model.IdentityReviewRejected:
+		return nil, helpers.ErrIdentityReviewRejected
+	default:
+		return nil, helpers.ErrIdentityReviewPending
+	}
+}
+
+// identityReview returns the review of the collect request of identity, a pending review is added if it has none
+func (c *Client) identityReview(ctx context.Context, meta *model.MetaData, identity, matched *model.Identity, result *identitymatch.Result) (*model.IdentityReview, error) {
+	requestHash := identityRequestHash(identity)
+
+	review, err := c.db.VCIdentityReviewColl.GetByRequest(ctx, meta, requestHash)
+	if err == nil {
+		return review, nil
+	}
+	if !errors.Is(err, helpers.ErrNoDocumentFound) {
+		return nil, err
+	}
+
+	review = &model.IdentityReview{
+		ReviewID:                uuid.NewString(),
+		AuthenticSource:         meta.AuthenticSource,
+		DocumentType:            meta.DocumentType,
+		DocumentID:              meta.DocumentID,
+		CollectID:               meta.Collect.ID,
+		RequestHash:             requestHash,
+		Identity:                identity,
+		AuthenticSourcePersonID: matched.AuthenticSourcePersonID,
+		Score:                   result.Score,
+		FieldScores:             result.Fields,
+		Status:                  model.IdentityReviewPending,
+		CreatedAt:               time.Now().Unix(),
+	}
+	if err := c.db.VCIdentityReviewColl.Add(ctx, review); err != nil {
+		if errors.Is(err, helpers.ErrDuplicateKey) {
+			// a concurrent request with the same identity added the review
+			return c.db.VCIdentityReviewColl.GetByRequest(ctx, meta, requestHash)
+		}
+		return nil, err
+	}
+
+	c.log.Info("identity queued for review", "review_id", review.ReviewID, "document_id", meta.DocumentID, "score", result.Score)
+
+	return review, nil
+}
+
+// identityRequestHash identifies the normalized names and birth date of identity, so retries of a collect request find
+// its review
+func identityRequestHash(identity *model.Identity) string {
+	h := sha256.Sum256([]byte(strings.Join([]string{
+		identity.Schema.Name,
+		identitymatch.Normalize(identity.FamilyName),
+		identitymatch.Normalize(identity.GivenName),
+		identity.BirthDate,
+	}, "\x00")))
+
+	return hex.EncodeToString(h[:])
+}
+
+// IdentityReviewListRequest is the request for IdentityReviewList
+type IdentityReviewListRequest struct {
+	AuthenticSource string `json:"authentic_source" validate:"required"`
+
+	// Status of the reviews, pending if empty
+	Status string `json:"status" validate:"omitempty,oneof=pending approved rejected"`
+
+	// Limit is the maximum number of reviews, 100 if 0
+	Limit int64 `json:"limit" validate:"omitempty,gte=0,lte=1000"`
+}
+
+// IdentityReviewListReply is the reply for IdentityReviewList
+type IdentityReviewListReply struct {
+	Data []*model.IdentityReview `json:"data"`
+}
+
+// IdentityReviewList lists the identity reviews of an authentic source
+//
+//	@Summary		IdentityReviewList
+//	@ID				identity-review-list
+//	@Description	List the collect requests whose identity matched the document loosely, oldest first. Pending reviews are the manual review queue
+//	@Tags			dc4eu
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	IdentityReviewListReply		"Success"
+//	@Failure		400	{object}	helpers.Problem				"Bad Request"
+//	@Param			req	body		IdentityReviewListRequest	true	" "
+//	@Router			/identity/review/list [post]
+func (c *Client) IdentityReviewList(ctx context.Context, req *IdentityReviewListRequest) (*IdentityReviewListReply, error) {
+	if err := helpers.Check(ctx, c.cfg, req, c.log); err != nil {
+		return nil, err
+	}
+	if req.Status == "" {
+		req.Status = model.IdentityReviewPending
+	}
+	if req.Limit == 0 {
+		req.Limit = 100
+	}
+
+	reviews, err := c.db.VCIdentityReviewColl.List(ctx, req.AuthenticSource, req.Status, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IdentityReviewListReply{Data: reviews}, nil
+}
+
+// IdentityReviewDecisionRequest is the request for IdentityReviewDecision
+type IdentityReviewDecisionRequest struct {
+	AuthenticSource string `json:"authentic_source" validate:"required"`
+	ReviewID        string `json:"review_id" validate:"required"`
+
+	// Decision approves the collect request, or rejects it
+	Decision string `json:"decision" validate:"required,oneof=approved rejected"`
+
+	Reviewer string `json:"reviewer" validate:"required"`
+	Comment  string `json:"comment"`
+}
+
+// IdentityReviewDecisionReply is the reply for IdentityReviewDecision
+type IdentityReviewDecisionReply struct {
+	Data *model.IdentityReview `json:"data"`
+}
+
+// IdentityReviewDecision approves or rejects a pending identity review
+//
+//	@Summary		IdentityReviewDecision
+//	@ID				identity-review-decision
+//	@Description	Approve or reject a pending identity review, the next collect request with the identity gets the document if approved
+//	@Tags			dc4eu
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	IdentityReviewDecisionReply		"Success"
+//	@Failure		400	{object}	helpers.Problem					"Bad Request"
+//	@Failure		404	{object}	helpers.Problem					"Unknown review"
+//	@Failure		409	{object}	helpers.Problem					"Review already decided"
+//	@Param			req	body		IdentityReviewDecisionRequest	true	" "
+//	@Router			/identity/review/decision [post]
+func (c *Client) IdentityReviewDecision(ctx context.Context, req *IdentityReviewDecisionRequest) (*IdentityReviewDecisionReply, error) {
+	if err := helpers.Check(ctx, c.cfg, req, c.log); err != nil {
+		return nil, err
+	}
+
+	review, err := c.db.VCIdentityReviewColl.Decide(ctx, req.AuthenticSource, req.ReviewID, &model.IdentityReview{
+		Status:    req.Decision,
+		Reviewer:  req.Reviewer,
+		Comment:   req.Comment,
+		DecidedAt: time.Now().Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.log.Info("identity review decided", "review_id", review.ReviewID, "decision", review.Status, "reviewer", review.Reviewer)
+
+	return &IdentityReviewDecisionReply{Data: review}, nil
+}
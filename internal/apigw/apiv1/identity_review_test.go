@@ -0,0 +1,50 @@
+package apiv1
+
+import (
+	"testing"
+	"vc/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentityRequestHash(t *testing.T) {
+	identity := func(schema, familyName, givenName, birthDate string) *model.Identity {
+		return &model.Identity{
+			Schema:     &model.IdentitySchema{Name: schema},
+			FamilyName: familyName,
+			GivenName:  givenName,
+			BirthDate:  birthDate,
+		}
+	}
+	base := identityRequestHash(identity("SE", "Müller", "Anna", "1990-01-02"))
+
+	tts := []struct {
+		name     string
+		identity *model.Identity
+		same     bool
+	}{
+		{
+			name:     "normalized names",
+			identity: identity("SE", " MULLER ", "anna", "1990-01-02"),
+			same:     true,
+		},
+		{
+			name:     "birth date",
+			identity: identity("SE", "Müller", "Anna", "1990-02-01"),
+		},
+		{
+			name:     "schema",
+			identity: identity("DE", "Müller", "Anna", "1990-01-02"),
+		},
+		{
+			name:     "names are not concatenated",
+			identity: identity("SE", "MüllerAnna", "", "1990-01-02"),
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.same, identityRequestHash(tt.identity) == base)
+		})
+	}
+}
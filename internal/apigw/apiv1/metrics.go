@@ -0,0 +1,68 @@
+package apiv1
+
+import (
+	"context"
+	"vc/pkg/model"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// outcomes of a credential issuance, the outcome attribute of apigw.credentials
+const (
+	credentialOutcomeIssued = "issued"
+	credentialOutcomeFailed = "failed"
+)
+
+// lifecycleMetrics counts the documents uploaded and revoked and the credentials issued, by authentic source and
+// document type
+type lifecycleMetrics struct {
+	uploads     metric.Int64Counter
+	credentials metric.Int64Counter
+	revocations metric.Int64Counter
+}
+
+func newLifecycleMetrics() (*lifecycleMetrics, error) {
+	meter := otel.Meter("vc/apigw")
+
+	m := &lifecycleMetrics{}
+	var err error
+	m.uploads, err = meter.Int64Counter("apigw.documents.uploaded", metric.WithDescription("Number of documents uploaded"))
+	if err != nil {
+		return nil, err
+	}
+	m.credentials, err = meter.Int64Counter("apigw.credentials", metric.WithDescription("Number of credential issuances and refreshes by outcome, issued or failed"))
+	if err != nil {
+		return nil, err
+	}
+	m.revocations, err = meter.Int64Counter("apigw.documents.revoked", metric.WithDescription("Number of documents revoked"))
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (m *lifecycleMetrics) uploaded(ctx context.Context, meta *model.MetaData) {
+	m.uploads.Add(ctx, 1, metric.WithAttributes(collectAttributes(meta)...))
+}
+
+// issued counts a credential issuance or refresh, err is nil on success
+func (m *lifecycleMetrics) issued(ctx context.Context, authenticSource, documentType string, refresh bool, err error) {
+	outcome := credentialOutcomeIssued
+	if err != nil {
+		outcome = credentialOutcomeFailed
+	}
+
+	m.credentials.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("authentic_source", authenticSource),
+		attribute.String("document_type", documentType),
+		attribute.String("outcome", outcome),
+		attribute.Bool("refresh", refresh),
+	))
+}
+
+func (m *lifecycleMetrics) revoked(ctx context.Context, meta *model.MetaData) {
+	m.revocations.Add(ctx, 1, metric.WithAttributes(collectAttributes(meta)...))
+}
@@ -0,0 +1,168 @@
+package apiv1
+
+import (
+	"context"
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+	"vc/pkg/helpers"
+	"vc/pkg/model"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lestrrat-go/jwx/jwk"
+)
+
+// uploadSigningAlgorithms are the JWS algorithms accepted for signed uploads
+var uploadSigningAlgorithms = []string{"ES256", "ES384", "ES512", "EdDSA", "RS256", "PS256"}
+
+// uploadJWSHeader is the protected header of a signed upload, iat, exp and nonce are carried in the header so the
+// payload is the upload request as is
+type uploadJWSHeader struct {
+	Alg   string   `json:"alg"`
+	KID   string   `json:"kid"`
+	IAT   int64    `json:"iat"`
+	EXP   int64    `json:"exp,omitempty"`
+	Nonce string   `json:"nonce"`
+	Crit  []string `json:"crit,omitempty"`
+}
+
+// signedUpload is a verified signed upload
+type signedUpload struct {
+	payload   []byte
+	signer    *model.UploadSigner
+	nonce     string
+	expiresAt time.Time
+}
+
+// DetachedJWS returns the compact JWS of a detached JWS, header..signature, over payload
+func DetachedJWS(detached string, payload []byte) (string, error) {
+	header, signature, ok := strings.Cut(detached, "..")
+	if !ok || header == "" || signature == "" {
+		return "", helpers.ErrInvalidUploadSignature
+	}
+
+	return header + "." + base64.RawURLEncoding.EncodeToString(payload) + "." + signature, nil
+}
+
+// loadUploadKeys reads the JWKS of every authentic source that signs its uploads
+func loadUploadKeys(cfg *model.SignedUpload) (map[string]jwk.Set, error) {
+	keys := map[string]jwk.Set{}
+	for authenticSource, path := range cfg.JWKSPaths {
+		set, err := jwk.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		keys[authenticSource] = set
+	}
+	return keys, nil
+}
+
+// verifyUploadJWS verifies the signature of a compact JWS with the key of the authentic source in its payload, and
+// checks iat and exp at now. Every failure is ErrInvalidUploadSignature, the reason is not told to the client.
+func verifyUploadJWS(keys map[string]jwk.Set, cfg *model.SignedUpload, compact string, now time.Time) (*signedUpload, error) {
+	parts := strings.Split(compact, ".")
+	if len(parts) != 3 {
+		return nil, helpers.ErrInvalidUploadSignature
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, helpers.ErrInvalidUploadSignature
+	}
+	header := &uploadJWSHeader{}
+	if err := json.Unmarshal(headerJSON, header); err != nil {
+		return nil, helpers.ErrInvalidUploadSignature
+	}
+	// no extensions are understood, among them the unencoded payload option of RFC 7797
+	if len(header.Crit) > 0 || header.KID == "" || header.Nonce == "" || header.IAT == 0 {
+		return nil, helpers.ErrInvalidUploadSignature
+	}
+	if !slices.Contains(uploadSigningAlgorithms, header.Alg) {
+		return nil, helpers.ErrInvalidUploadSignature
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, helpers.ErrInvalidUploadSignature
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, helpers.ErrInvalidUploadSignature
+	}
+
+	// the authentic source is read before the signature is verified only to select its keys
+	upload := &UploadRequest{}
+	if err := json.Unmarshal(payload, upload); err != nil || upload.Meta == nil {
+		return nil, helpers.ErrInvalidUploadSignature
+	}
+	authenticSource := upload.Meta.AuthenticSource
+
+	set, ok := keys[authenticSource]
+	if !ok {
+		return nil, helpers.ErrInvalidUploadSignature
+	}
+	key, ok := set.LookupKeyID(header.KID)
+	if !ok {
+		return nil, helpers.ErrInvalidUploadSignature
+	}
+	if key.Algorithm() != "" && key.Algorithm() != header.Alg {
+		return nil, helpers.ErrInvalidUploadSignature
+	}
+
+	var publicKey any
+	if err := key.Raw(&publicKey); err != nil {
+		return nil, helpers.ErrInvalidUploadSignature
+	}
+	if err := jwt.GetSigningMethod(header.Alg).Verify(parts[0]+"."+parts[1], signature, publicKey); err != nil {
+		return nil, helpers.ErrInvalidUploadSignature
+	}
+
+	leeway := time.Duration(cfg.Leeway) * time.Second
+	iat := time.Unix(header.IAT, 0)
+	expiresAt := iat.Add(time.Duration(cfg.MaxAge) * time.Second)
+	if header.EXP != 0 && time.Unix(header.EXP, 0).Before(expiresAt) {
+		expiresAt = time.Unix(header.EXP, 0)
+	}
+	if now.Add(leeway).Before(iat) || !now.Add(-leeway).Before(expiresAt) {
+		return nil, helpers.ErrInvalidUploadSignature
+	}
+
+	thumbprint, err := key.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+
+	return &signedUpload{
+		payload: payload,
+		signer: &model.UploadSigner{
+			AuthenticSource: authenticSource,
+			KID:             header.KID,
+			Thumbprint:      base64.RawURLEncoding.EncodeToString(thumbprint),
+			SignedAt:        header.IAT,
+			JWS:             compact,
+		},
+		nonce:     header.Nonce,
+		expiresAt: expiresAt.Add(leeway),
+	}, nil
+}
+
+// VerifyUploadSignature verifies a signed upload, given as compact JWS, and uses up its nonce. The payload, the upload
+// request, and the key it was signed with are returned.
+func (c *Client) VerifyUploadSignature(ctx context.Context, compact string) ([]byte, *model.UploadSigner, error) {
+	ctx, span := c.tracer.Start(ctx, "apiv1:VerifyUploadSignature")
+	defer span.End()
+
+	signed, err := verifyUploadJWS(c.uploadKeys, &c.cfg.APIGW.SignedUpload, compact, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := c.db.VCUploadNonceColl.Use(ctx, signed.signer.AuthenticSource, signed.nonce, signed.expiresAt); err != nil {
+		return nil, nil, err
+	}
+
+	return signed.payload, signed.signer, nil
+}
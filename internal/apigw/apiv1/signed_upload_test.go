@@ -0,0 +1,110 @@
+package apiv1
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+	"vc/pkg/helpers"
+	"vc/pkg/model"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/stretchr/testify/assert"
+)
+
+func signUpload(t *testing.T, key *ecdsa.PrivateKey, header map[string]any, payload []byte) string {
+	headerJSON, err := json.Marshal(header)
+	assert.NoError(t, err)
+
+	signingString := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	signature, err := jwt.SigningMethodES256.Sign(signingString, key)
+	assert.NoError(t, err)
+
+	return signingString + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestVerifyUploadJWS(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	sunetKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	publicKey, err := jwk.New(&sunetKey.PublicKey)
+	assert.NoError(t, err)
+	assert.NoError(t, publicKey.Set(jwk.KeyIDKey, "sunet-1"))
+	set := jwk.NewSet()
+	set.Add(publicKey)
+	keys := map[string]jwk.Set{"SUNET": set}
+
+	cfg := &model.SignedUpload{Enabled: true, MaxAge: 300, Leeway: 30}
+
+	payload, err := json.Marshal(&UploadRequest{
+		Meta:                &model.MetaData{AuthenticSource: "SUNET", DocumentType: "PDA1", DocumentID: "doc-1", DocumentVersion: "1.0.0"},
+		DocumentData:        map[string]any{"a": "b"},
+		DocumentDataVersion: "1.0.0",
+	})
+	assert.NoError(t, err)
+
+	header := func(changes map[string]any) map[string]any {
+		h := map[string]any{"alg": "ES256", "kid": "sunet-1", "iat": now.Unix(), "nonce": "n-1"}
+		for k, v := range changes {
+			if v == nil {
+				delete(h, k)
+				continue
+			}
+			h[k] = v
+		}
+		return h
+	}
+
+	tts := []struct {
+		name    string
+		jws     string
+		wantErr error
+	}{
+		{name: "ok", jws: signUpload(t, sunetKey, header(nil), payload)},
+		{name: "exp in future", jws: signUpload(t, sunetKey, header(map[string]any{"exp": now.Add(time.Minute).Unix()}), payload)},
+		{name: "other key", jws: signUpload(t, otherKey, header(nil), payload), wantErr: helpers.ErrInvalidUploadSignature},
+		{name: "unknown kid", jws: signUpload(t, sunetKey, header(map[string]any{"kid": "sunet-2"}), payload), wantErr: helpers.ErrInvalidUploadSignature},
+		{name: "no nonce", jws: signUpload(t, sunetKey, header(map[string]any{"nonce": nil}), payload), wantErr: helpers.ErrInvalidUploadSignature},
+		{name: "too old", jws: signUpload(t, sunetKey, header(map[string]any{"iat": now.Add(-10 * time.Minute).Unix()}), payload), wantErr: helpers.ErrInvalidUploadSignature},
+		{name: "issued in future", jws: signUpload(t, sunetKey, header(map[string]any{"iat": now.Add(time.Minute).Unix()}), payload), wantErr: helpers.ErrInvalidUploadSignature},
+		{name: "expired", jws: signUpload(t, sunetKey, header(map[string]any{"exp": now.Add(-time.Minute).Unix()}), payload), wantErr: helpers.ErrInvalidUploadSignature},
+		{name: "unencoded payload", jws: signUpload(t, sunetKey, header(map[string]any{"b64": false, "crit": []string{"b64"}}), payload), wantErr: helpers.ErrInvalidUploadSignature},
+		{name: "hmac", jws: signUpload(t, sunetKey, header(map[string]any{"alg": "HS256"}), payload), wantErr: helpers.ErrInvalidUploadSignature},
+		{name: "not a jws", jws: "a.b", wantErr: helpers.ErrInvalidUploadSignature},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			signed, err := verifyUploadJWS(keys, cfg, tt.jws, now)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, payload, signed.payload)
+			assert.Equal(t, "n-1", signed.nonce)
+			assert.Equal(t, "SUNET", signed.signer.AuthenticSource)
+			assert.Equal(t, "sunet-1", signed.signer.KID)
+			assert.Equal(t, tt.jws, signed.signer.JWS)
+			assert.NotEmpty(t, signed.signer.Thumbprint)
+			assert.True(t, signed.expiresAt.After(now))
+		})
+	}
+}
+
+func TestDetachedJWS(t *testing.T) {
+	compact, err := DetachedJWS("aGVhZGVy..c2ln", []byte("body"))
+	assert.NoError(t, err)
+	assert.Equal(t, "aGVhZGVy.Ym9keQ.c2ln", compact)
+
+	_, err = DetachedJWS("aGVhZGVy.Ym9keQ.c2ln", []byte("body"))
+	assert.ErrorIs(t, err, helpers.ErrInvalidUploadSignature)
+}
@@ -0,0 +1,84 @@
+package apiv1
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// recentErrorsSize is the number of failed issuances kept for the statistics endpoint
+const recentErrorsSize = 20
+
+// IssuanceError is a failed credential issuance
+type IssuanceError struct {
+	Time            int64  `json:"time"`
+	AuthenticSource string `json:"authentic_source"`
+	DocumentType    string `json:"document_type"`
+	Error           string `json:"error"`
+}
+
+// issuanceStatistics counts credential issuances per authentic source since start
+type issuanceStatistics struct {
+	mu           sync.Mutex
+	issued       map[string]int64
+	failed       map[string]int64
+	recentErrors []*IssuanceError
+}
+
+func newIssuanceStatistics() *issuanceStatistics {
+	return &issuanceStatistics{
+		issued: map[string]int64{},
+		failed: map[string]int64{},
+	}
+}
+
+// record counts one issuance, err is nil on success
+func (s *issuanceStatistics) record(authenticSource, documentType string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err == nil {
+		s.issued[authenticSource]++
+		return
+	}
+
+	s.failed[authenticSource]++
+	s.recentErrors = append([]*IssuanceError{{
+		Time:            time.Now().Unix(),
+		AuthenticSource: authenticSource,
+		DocumentType:    documentType,
+		Error:           err.Error(),
+	}}, s.recentErrors...)
+	if len(s.recentErrors) > recentErrorsSize {
+		s.recentErrors = s.recentErrors[:recentErrorsSize]
+	}
+}
+
+// merge adds the issuance counters to stats, newest errors first
+func (s *issuanceStatistics) merge(stats map[string]*AuthenticSourceStatistics) ([]*AuthenticSourceStatistics, []*IssuanceError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	get := func(authenticSource string) *AuthenticSourceStatistics {
+		if _, ok := stats[authenticSource]; !ok {
+			stats[authenticSource] = &AuthenticSourceStatistics{AuthenticSource: authenticSource}
+		}
+		return stats[authenticSource]
+	}
+	for authenticSource, n := range s.issued {
+		get(authenticSource).CredentialsIssued = n
+	}
+	for authenticSource, n := range s.failed {
+		get(authenticSource).CredentialsFailed = n
+	}
+
+	res := make([]*AuthenticSourceStatistics, 0, len(stats))
+	for _, v := range stats {
+		res = append(res, v)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].AuthenticSource < res[j].AuthenticSource
+	})
+
+	return res, append([]*IssuanceError{}, s.recentErrors...)
+}
@@ -0,0 +1,34 @@
+package apiv1
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssuanceStatistics(t *testing.T) {
+	s := newIssuanceStatistics()
+
+	s.record("SUNET", "EHIC", nil)
+	s.record("SUNET", "EHIC", nil)
+	s.record("SUNET", "PDA1", errors.New("no document found"))
+	for i := 0; i < recentErrorsSize+5; i++ {
+		s.record("FK", "EHIC", fmt.Errorf("error %d", i))
+	}
+
+	stats, recentErrors := s.merge(map[string]*AuthenticSourceStatistics{
+		"SUNET": {AuthenticSource: "SUNET", Documents: 3, Revoked: 1},
+		"ICA":   {AuthenticSource: "ICA", Documents: 1},
+	})
+
+	assert.Equal(t, []*AuthenticSourceStatistics{
+		{AuthenticSource: "FK", CredentialsFailed: recentErrorsSize + 5},
+		{AuthenticSource: "ICA", Documents: 1},
+		{AuthenticSource: "SUNET", Documents: 3, Revoked: 1, CredentialsIssued: 2, CredentialsFailed: 1},
+	}, stats)
+
+	assert.Len(t, recentErrors, recentErrorsSize)
+	assert.Equal(t, fmt.Sprintf("error %d", recentErrorsSize+4), recentErrors[0].Error)
+}
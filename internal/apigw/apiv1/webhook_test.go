@@ -0,0 +1,68 @@
+package apiv1
+
+import (
+	"context"
+	"testing"
+	"vc/pkg/helpers"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddWebhookURL(t *testing.T) {
+	tts := []struct {
+		name      string
+		url       string
+		allowList []string
+		want      string
+	}{
+		{
+			name: "empty allow list",
+			url:  "https://source.example.com/hooks",
+			want: "not in the webhook allow list",
+		},
+		{
+			name:      "not in the allow list",
+			url:       "https://source.example.com.attacker.net/hooks",
+			allowList: []string{"https://source.example.com/"},
+			want:      "not in the webhook allow list",
+		},
+		{
+			name:      "loopback",
+			url:       "http://127.0.0.1:8080/hooks",
+			allowList: []string{"http://127.0.0.1:8080/"},
+			want:      "127.0.0.1: address is not public",
+		},
+		{
+			name:      "link-local",
+			url:       "http://169.254.169.254/latest/meta-data",
+			allowList: []string{"http://169.254.169.254/"},
+			want:      "169.254.169.254: address is not public",
+		},
+		{
+			name:      "private",
+			url:       "http://10.0.0.1/hooks",
+			allowList: []string{"http://10.0.0.1/"},
+			want:      "10.0.0.1: address is not public",
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{
+				cfg: &model.Cfg{APIGW: model.APIGW{Webhook: model.Webhook{Enabled: true, AllowList: tt.allowList}}},
+				log: logger.NewSimple("testing_apiv1"),
+			}
+
+			_, err := c.AddWebhook(context.Background(), &AddWebhookRequest{
+				AuthenticSource: "SUNET",
+				URL:             tt.url,
+				Secret:          "a6c9b3f0e2d14b7d9f8e1c2a5b4d3e6f",
+			})
+			assert.Equal(t, helpers.NewErrorDetails("VALIDATION_ERROR", []map[string]any{
+				{"field": "url", "message": tt.want},
+			}), err)
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"vc/pkg/fieldcrypt"
+	"vc/pkg/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// newEncryptor creates the identity field encryptor of the configured key encryption keys, nil if encryption is
+// disabled
+func newEncryptor(ctx context.Context, cfg *model.Cfg) (*fieldcrypt.Encryptor, error) {
+	encryptionCfg := cfg.APIGW.FieldEncryption
+	if !encryptionCfg.Enabled {
+		return nil, nil
+	}
+
+	var kek fieldcrypt.KeyProvider
+	switch encryptionCfg.KEKType {
+	case "awskms":
+		kms, err := fieldcrypt.NewKMS(ctx, encryptionCfg.KMS)
+		if err != nil {
+			return nil, err
+		}
+		kek = kms
+	case "local", "":
+		current, err := fieldcrypt.LoadKey(encryptionCfg.KEKPath)
+		if err != nil {
+			return nil, err
+		}
+
+		previous := make([][]byte, 0, len(encryptionCfg.PreviousKEKPaths))
+		for _, path := range encryptionCfg.PreviousKEKPaths {
+			key, err := fieldcrypt.LoadKey(path)
+			if err != nil {
+				return nil, err
+			}
+			previous = append(previous, key)
+		}
+
+		keyring, err := fieldcrypt.NewLocalKeyring(current, previous...)
+		if err != nil {
+			return nil, err
+		}
+		kek = keyring
+	default:
+		return nil, fmt.Errorf("unknown field encryption kek_type %q", encryptionCfg.KEKType)
+	}
+
+	indexKey, err := fieldcrypt.LoadKey(encryptionCfg.BlindIndexKeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return fieldcrypt.New(kek, indexKey, encryptionCfg.Fields), nil
+}
+
+// identityFilter adds an equality match of an identity field to filter, by blind index if the field is encrypted
+func (s *Service) identityFilter(filter bson.M, path, field, value string) {
+	if s.encryptor.Encrypted(field) {
+		filter[path+"blind_index."+field] = bson.M{"$eq": s.encryptor.BlindIndex(field, value)}
+		return
+	}
+	filter[path+field] = bson.M{"$eq": value}
+}
+
+// encryptIdentity returns a copy of identity with the designated fields encrypted, identity itself if encryption is
+// disabled
+func (s *Service) encryptIdentity(identity *model.Identity) (*model.Identity, error) {
+	if s.encryptor == nil || identity == nil {
+		return identity, nil
+	}
+
+	encrypted, err := s.encryptor.EncryptIdentity(*identity)
+	if err != nil {
+		return nil, err
+	}
+
+	return &encrypted, nil
+}
+
+// decryptIdentity decrypts identity in place
+func (s *Service) decryptIdentity(identity *model.Identity) error {
+	if s.encryptor == nil || identity == nil {
+		return nil
+	}
+
+	decrypted, err := s.encryptor.DecryptIdentity(*identity)
+	if err != nil {
+		return err
+	}
+	*identity = decrypted
+
+	return nil
+}
+
+// encryptDocument returns a copy of doc with its identities encrypted, doc itself if encryption is disabled
+func (c *VCDatastoreColl) encryptDocument(doc *model.CompleteDocument) (*model.CompleteDocument, error) {
+	if c.Service.encryptor == nil {
+		return doc, nil
+	}
+
+	stored := *doc
+	stored.Identities = make([]model.Identity, 0, len(doc.Identities))
+	for _, identity := range doc.Identities {
+		encrypted, err := c.Service.encryptor.EncryptIdentity(identity)
+		if err != nil {
+			return nil, err
+		}
+		stored.Identities = append(stored.Identities, encrypted)
+	}
+
+	return &stored, nil
+}
+
+// decryptDocument decrypts the identities of doc in place
+func (c *VCDatastoreColl) decryptDocument(doc *model.CompleteDocument) error {
+	if c.Service.encryptor == nil {
+		return nil
+	}
+
+	for i, identity := range doc.Identities {
+		decrypted, err := c.Service.encryptor.DecryptIdentity(identity)
+		if err != nil {
+			return err
+		}
+		doc.Identities[i] = decrypted
+	}
+
+	return nil
+}
+
+// RotateIdentityKeys rewraps at most limit documents of authenticSource whose identity keys are wrapped by a previous
+// key encryption key, and returns the number of rewrapped documents
+func (c *VCDatastoreColl) RotateIdentityKeys(ctx context.Context, authenticSource string, limit int64) (int, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:datastore:rotateIdentityKeys")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if c.Service.encryptor == nil {
+		return 0, nil
+	}
+
+	filter := bson.M{
+		"meta.authentic_source": bson.M{"$eq": authenticSource},
+		"identities": bson.M{"$elemMatch": bson.M{
+			"encryption_key":        bson.M{"$ne": nil},
+			"encryption_key.key_id": bson.M{"$ne": c.Service.encryptor.KeyID()},
+		}},
+	}
+
+	cursor, err := coll.Find(ctx, filter, options.Find().SetLimit(limit))
+	if err != nil {
+		return 0, err
+	}
+
+	docs := []*model.CompleteDocument{}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return 0, err
+	}
+
+	rewrapped := 0
+	for _, doc := range docs {
+		for i := range doc.Identities {
+			if _, err := c.Service.encryptor.Rewrap(&doc.Identities[i]); err != nil {
+				return rewrapped, err
+			}
+		}
+
+		// the revision is kept, a concurrent update wins and the document is rewrapped on the next run
+		update := bson.M{"$set": bson.M{"identities": doc.Identities}}
+		docFilter := withRevision(bson.M{
+			"meta.document_id":      bson.M{"$eq": doc.Meta.DocumentID},
+			"meta.authentic_source": bson.M{"$eq": doc.Meta.AuthenticSource},
+			"meta.document_type":    bson.M{"$eq": doc.Meta.DocumentType},
+		}, &doc.Meta.Revision)
+
+		result, err := coll.UpdateOne(ctx, docFilter, update)
+		if err != nil {
+			return rewrapped, err
+		}
+		if result.ModifiedCount > 0 {
+			rewrapped++
+		}
+	}
+
+	return rewrapped, nil
+}
@@ -0,0 +1,122 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"vc/pkg/model"
+)
+
+// EraseSubject erases the data of the subject of job. Documents only identifying the subject are deleted with all data
+// kept by document, the subject is removed from documents shared with other identities, and the deferred credentials
+// and consent of the subject are deleted. Each document is erased in its own transaction and the counts of job are
+// stored as they commit, so an interrupted job is resumed where it stopped.
+func (s *Service) EraseSubject(ctx context.Context, job *model.ErasureJob) error {
+	ctx, span := s.tracer.Start(ctx, "db:eraseSubject")
+	defer span.End()
+
+	if job.Subject == nil || job.Subject.AuthenticSourcePersonID == "" {
+		return errors.New("erasure job has no subject")
+	}
+	authenticSourcePersonID := job.Subject.AuthenticSourcePersonID
+
+	docs, err := s.VCDatastoreColl.FindBySubject(ctx, job.AuthenticSource, authenticSourcePersonID)
+	if err != nil {
+		return err
+	}
+
+	for _, doc := range docs {
+		if sharedDocument(doc, authenticSourcePersonID) {
+			err := s.VCDatastoreColl.DeleteDocumentIdentity(ctx, &DeleteDocumentIdentityQuery{
+				AuthenticSource:         doc.Meta.AuthenticSource,
+				DocumentType:            doc.Meta.DocumentType,
+				DocumentID:              doc.Meta.DocumentID,
+				AuthenticSourcePersonID: authenticSourcePersonID,
+			})
+			if err != nil {
+				return err
+			}
+			job.Erased.Identities++
+		} else {
+			erased, err := s.eraseDocument(ctx, doc.Meta)
+			if err != nil {
+				return err
+			}
+			job.Erased.Add(erased)
+		}
+
+		if err := s.VCErasureJobColl.Update(ctx, job); err != nil {
+			return err
+		}
+	}
+
+	job.Erased.DeferredCredentials, err = s.VCDeferredCredentialColl.DeleteBySubject(ctx, job.AuthenticSource, authenticSourcePersonID)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.VCConsentColl.Delete(ctx, &GetConsentQuery{
+		AuthenticSource:         job.AuthenticSource,
+		AuthenticSourcePersonID: authenticSourcePersonID,
+	})
+	if err != nil {
+		return err
+	}
+	if deleted {
+		job.Erased.Consents = 1
+	}
+
+	return nil
+}
+
+// eraseDocument deletes a document in one transaction, with its consents, credential notifications and their
+// disclosures, versions and identity reviews. The consent audit of its consents is pseudonymized, the entries are kept.
+func (s *Service) eraseDocument(ctx context.Context, meta *model.MetaData) (model.ErasureCounts, error) {
+	var erased model.ErasureCounts
+	err := s.WithTransaction(ctx, func(ctx context.Context) error {
+		// counts are only kept once the transaction commits
+		erased = model.ErasureCounts{Documents: 1}
+
+		consentIDs, err := s.VCDocumentConsentColl.DeleteByDocument(ctx, meta)
+		if err != nil {
+			return err
+		}
+		erased.DocumentConsents = int64(len(consentIDs))
+
+		if len(consentIDs) > 0 {
+			erased.ConsentAuditEntries, err = s.VCConsentAuditColl.Pseudonymize(ctx, consentIDs)
+			if err != nil {
+				return err
+			}
+		}
+
+		erased.CredentialNotifications, err = s.VCCredentialNotificationColl.DeleteByDocument(ctx, meta)
+		if err != nil {
+			return err
+		}
+
+		if _, err := s.VCDocumentVersionColl.DeleteByDocument(ctx, meta); err != nil {
+			return err
+		}
+
+		if _, err := s.VCIdentityReviewColl.DeleteByDocument(ctx, meta); err != nil {
+			return err
+		}
+
+		return s.VCDatastoreColl.Delete(ctx, meta, nil)
+	})
+	if err != nil {
+		return model.ErasureCounts{}, err
+	}
+
+	return erased, nil
+}
+
+// sharedDocument reports if doc has identities of other persons than authenticSourcePersonID
+func sharedDocument(doc *model.CompleteDocument, authenticSourcePersonID string) bool {
+	for _, identity := range doc.Identities {
+		if identity.AuthenticSourcePersonID != authenticSourcePersonID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+	"vc/pkg/trace"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// mockService returns a service of the mock client of mt, without transactions
+func mockService(t *testing.T, mt *mtest.T) *Service {
+	log := logger.NewSimple("test")
+	tracer, err := trace.NewForTesting(context.Background(), "test", log)
+	assert.NoError(t, err)
+
+	s, err := NewForTesting(mt.Client, &model.Cfg{}, tracer, log)
+	assert.NoError(t, err)
+
+	return s
+}
+
+// command is a command sent to a collection
+type command struct {
+	name       string
+	collection string
+}
+
+// commands returns the commands mt has sent
+func commands(mt *mtest.T) []command {
+	res := []command{}
+	for _, event := range mt.GetAllStartedEvents() {
+		collection, _ := event.Command.Lookup(event.CommandName).StringValueOK()
+		res = append(res, command{name: event.CommandName, collection: collection})
+	}
+	return res
+}
+
+func TestEraseSubject(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	identity := func(personID string) bson.D {
+		return bson.D{{Key: "authentic_source_person_id", Value: personID}}
+	}
+	document := func(documentID string, identities ...any) bson.D {
+		return bson.D{
+			{Key: "meta", Value: bson.D{
+				{Key: "authentic_source", Value: "SUNET"},
+				{Key: "document_type", Value: "PDA1"},
+				{Key: "document_id", Value: documentID},
+			}},
+			{Key: "identities", Value: bson.A(identities)},
+		}
+	}
+	ok := func(n int) bson.D {
+		return bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: n}, {Key: "nModified", Value: n}}
+	}
+
+	mt.Run("all collections of the subject", func(mt *mtest.T) {
+		s := mockService(t, mt)
+
+		mt.AddMockResponses(
+			// the documents of the subject, doc-1 only identifies the subject, doc-2 is shared
+			mtest.CreateCursorResponse(0, "vc.datastore", mtest.FirstBatch,
+				document("doc-1", identity("person-1")),
+				document("doc-2", identity("person-1"), identity("person-2")),
+			),
+			// doc-1 is erased
+			mtest.CreateCursorResponse(0, "vc.document_consent", mtest.FirstBatch, bson.D{{Key: "consent_id", Value: "consent-1"}}),
+			ok(1),
+			mtest.CreateCursorResponse(0, "vc.consent_audit", mtest.FirstBatch, bson.D{{Key: "consent_id", Value: "consent-1"}, {Key: "approved_by", Value: "person-1"}}),
+			ok(1),
+			ok(2),
+			ok(3),
+			ok(1),
+			ok(1),
+			ok(1),
+			// person-1 is removed from doc-2
+			ok(1),
+			ok(1),
+			// the deferred credentials and consent of the subject
+			ok(2),
+			ok(1),
+		)
+
+		job := &model.ErasureJob{JobID: "job-1", AuthenticSource: "SUNET", Subject: &model.Identity{AuthenticSourcePersonID: "person-1"}}
+		assert.NoError(t, s.EraseSubject(context.Background(), job))
+
+		assert.Equal(t, []command{
+			{"find", "datastore"},
+			{"find", "document_consent"},
+			{"delete", "document_consent"},
+			{"find", "consent_audit"},
+			{"update", "consent_audit"},
+			// with the disclosures of the issued credentials
+			{"delete", "credential_notification"},
+			{"delete", "document_version"},
+			// the identity review queue
+			{"delete", "identity_review"},
+			{"delete", "datastore"},
+			{"update", "erasure_job"},
+			{"update", "datastore"},
+			{"update", "erasure_job"},
+			// with the identities and the disclosures of the deferred credentials
+			{"delete", "deferred_credential"},
+			{"delete", "consent"},
+		}, commands(mt))
+
+		assert.Equal(t, model.ErasureCounts{
+			Documents:               1,
+			Identities:              1,
+			DocumentConsents:        1,
+			CredentialNotifications: 2,
+			DeferredCredentials:     2,
+			Consents:                1,
+			ConsentAuditEntries:     1,
+		}, job.Erased)
+	})
+
+	mt.Run("failure stops the job", func(mt *mtest.T) {
+		s := mockService(t, mt)
+
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "vc.datastore", mtest.FirstBatch, document("doc-1", identity("person-1"))),
+			mtest.CreateCursorResponse(0, "vc.document_consent", mtest.FirstBatch),
+			mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 11600, Message: "interrupted"}),
+		)
+
+		job := &model.ErasureJob{JobID: "job-1", AuthenticSource: "SUNET", Subject: &model.Identity{AuthenticSourcePersonID: "person-1"}}
+		assert.Error(t, s.EraseSubject(context.Background(), job))
+		assert.Equal(t, model.ErasureCounts{}, job.Erased, "the counts of a failed document are not kept")
+	})
+
+	mt.Run("without subject", func(mt *mtest.T) {
+		s := mockService(t, mt)
+
+		assert.Error(t, s.EraseSubject(context.Background(), &model.ErasureJob{JobID: "job-1", AuthenticSource: "SUNET"}))
+		assert.Empty(t, commands(mt))
+	})
+}
+
+func TestErasureJobUpdate(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	tts := []struct {
+		name      string
+		status    string
+		matched   int
+		wantErr   bool
+		wantUnset bool
+	}{
+		{
+			name:    "running keeps the subject",
+			status:  model.ErasureJobRunning,
+			matched: 1,
+		},
+		{
+			name:      "completed removes the subject",
+			status:    model.ErasureJobCompleted,
+			matched:   1,
+			wantUnset: true,
+		},
+		{
+			name:      "failed removes the subject",
+			status:    model.ErasureJobFailed,
+			matched:   1,
+			wantUnset: true,
+		},
+		{
+			name:    "unknown job",
+			status:  model.ErasureJobRunning,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tts {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			s := mockService(t, mt)
+			mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: tt.matched}, {Key: "nModified", Value: tt.matched}})
+
+			err := s.VCErasureJobColl.Update(context.Background(), &model.ErasureJob{JobID: "job-1", Status: tt.status})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("u").Document()
+			_, err = update.LookupErr("$unset", "subject")
+			assert.Equal(t, tt.wantUnset, err == nil)
+		})
+	}
+}
@@ -14,14 +14,19 @@ import (
 // VCConsentColl is consent collection
 type VCConsentColl struct {
 	Service *Service
-	Coll    *mongo.Collection
-	log     *logger.Log
+	tenantCollection
+	log *logger.Log
 }
 
 func (c *VCConsentColl) createIndex(ctx context.Context) error {
 	ctx, span := c.Service.tracer.Start(ctx, "db:vc:consent:createIndex")
 	defer span.End()
 
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
 	indexModel := mongo.IndexModel{
 		Keys: bson.D{
 			{
@@ -35,7 +40,7 @@ func (c *VCConsentColl) createIndex(ctx context.Context) error {
 			Unique: &[]bool{true}[0],
 		},
 	}
-	_, err := c.Coll.Indexes().CreateOne(ctx, indexModel)
+	_, err = coll.Indexes().CreateOne(ctx, indexModel)
 	if err != nil {
 		return err
 	}
@@ -54,7 +59,12 @@ func (c *VCConsentColl) Add(ctx context.Context, consent *AddConsentQuery) error
 	ctx, span := c.Service.tracer.Start(ctx, "db:vc:consent:add")
 	defer span.End()
 
-	_, err := c.Coll.InsertOne(ctx, consent)
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = coll.InsertOne(ctx, consent)
 	if err != nil {
 		if mongo.IsDuplicateKeyError(err) {
 			return helpers.ErrDuplicateKey
@@ -77,6 +87,11 @@ func (c *VCConsentColl) Get(ctx context.Context, query *GetConsentQuery) (*model
 	ctx, span := c.Service.tracer.Start(ctx, "db:vc:consent:get")
 	defer span.End()
 
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	filter := bson.M{
 		"authentic_source":           bson.M{"$eq": query.AuthenticSource},
 		"authentic_source_person_id": bson.M{"$eq": query.AuthenticSourcePersonID},
@@ -87,9 +102,32 @@ func (c *VCConsentColl) Get(ctx context.Context, query *GetConsentQuery) (*model
 	})
 
 	res := &AddConsentQuery{}
-	if err := c.Coll.FindOne(ctx, filter, opts).Decode(res); err != nil {
+	if err := coll.FindOne(ctx, filter, opts).Decode(res); err != nil {
 		return nil, err
 	}
 
 	return res.Consent, nil
 }
+
+// Delete deletes the consent of a person, and reports if there was one
+func (c *VCConsentColl) Delete(ctx context.Context, query *GetConsentQuery) (bool, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:consent:delete")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	filter := bson.M{
+		"authentic_source":           bson.M{"$eq": query.AuthenticSource},
+		"authentic_source_person_id": bson.M{"$eq": query.AuthenticSourcePersonID},
+	}
+
+	res, err := coll.DeleteOne(ctx, filter)
+	if err != nil {
+		return false, err
+	}
+
+	return res.DeletedCount > 0, nil
+}
@@ -0,0 +1,278 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"vc/pkg/helpers"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// VCCredentialNotificationColl is the credential notification collection
+type VCCredentialNotificationColl struct {
+	Service *Service
+	tenantCollection
+	log *logger.Log
+}
+
+func (c *VCCredentialNotificationColl) createIndex(ctx context.Context) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:credential_notification:createIndex")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	indexNotificationIDUniq := mongo.IndexModel{
+		Keys:    bson.D{{Key: "notification_id", Value: 1}},
+		Options: options.Index().SetName("notification_id_uniq").SetUnique(true),
+	}
+
+	_, err = coll.Indexes().CreateOne(ctx, indexNotificationIDUniq)
+	return err
+}
+
+// withoutDisclosures is the projection of credential notifications read for other uses than a refresh
+var withoutDisclosures = bson.M{"_id": 0, "disclosures": 0, "disclosures_key": 0}
+
+// Add adds a credential notification, its disclosures are stored encrypted if field encryption is enabled
+func (c *VCCredentialNotificationColl) Add(ctx context.Context, notification *model.CredentialNotification) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:credential_notification:add")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	stored := *notification
+	if c.Service.encryptor != nil && len(notification.Disclosures) > 0 {
+		var err error
+		stored.Disclosures, stored.DisclosuresKey, err = c.Service.encryptor.EncryptValues("disclosures", notification.Disclosures)
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err := coll.InsertOne(ctx, &stored); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return helpers.ErrDuplicateKey
+		}
+		return err
+	}
+
+	return nil
+}
+
+// AddEvent appends event to the credential notification and returns it, ErrNoDocumentFound if notificationID is unknown
+func (c *VCCredentialNotificationColl) AddEvent(ctx context.Context, notificationID string, event *model.CredentialNotificationEvent) (*model.CredentialNotification, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:credential_notification:add_event")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"notification_id": bson.M{"$eq": notificationID}}
+	update := bson.M{"$push": bson.M{"events": event}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After).SetProjection(withoutDisclosures)
+
+	res := &model.CredentialNotification{}
+	if err := coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(res); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, helpers.ErrNoDocumentFound
+		}
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Get returns the credential notification of notificationID, ErrNoDocumentFound if it is unknown
+func (c *VCCredentialNotificationColl) Get(ctx context.Context, notificationID string) (*model.CredentialNotification, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:credential_notification:get")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"notification_id": bson.M{"$eq": notificationID}}
+
+	res := &model.CredentialNotification{}
+	if err := coll.FindOne(ctx, filter, options.FindOne().SetProjection(bson.M{"_id": 0})).Decode(res); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, helpers.ErrNoDocumentFound
+		}
+		return nil, err
+	}
+
+	if c.Service.encryptor != nil {
+		var err error
+		if res.Disclosures, err = c.Service.encryptor.DecryptValues("disclosures", res.Disclosures, res.DisclosuresKey); err != nil {
+			return nil, err
+		}
+		res.DisclosuresKey = nil
+	}
+
+	return res, nil
+}
+
+// ClaimRefresh sets refreshed_by of the credential notification, ErrCredentialRefreshed if another refresh claimed it
+// first. Concurrent refreshes of a credential issue at most one new credential.
+func (c *VCCredentialNotificationColl) ClaimRefresh(ctx context.Context, notificationID, refreshedBy string) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:credential_notification:claimRefresh")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{
+		"notification_id": bson.M{"$eq": notificationID},
+		"refreshed_by":    bson.M{"$exists": false},
+	}
+
+	res, err := coll.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"refreshed_by": refreshedBy}})
+	if err != nil {
+		return err
+	}
+	if res.ModifiedCount == 0 {
+		return helpers.ErrCredentialRefreshed
+	}
+
+	return nil
+}
+
+// ReleaseRefresh undoes ClaimRefresh when no credential was issued by the refresh
+func (c *VCCredentialNotificationColl) ReleaseRefresh(ctx context.Context, notificationID, refreshedBy string) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:credential_notification:releaseRefresh")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{
+		"notification_id": bson.M{"$eq": notificationID},
+		"refreshed_by":    bson.M{"$eq": refreshedBy},
+	}
+
+	_, err = coll.UpdateOne(ctx, filter, bson.M{"$unset": bson.M{"refreshed_by": ""}})
+	return err
+}
+
+// ListByDocument returns the credential notifications of a document
+func (c *VCCredentialNotificationColl) ListByDocument(ctx context.Context, meta *model.MetaData) ([]*model.CredentialNotification, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:credential_notification:listByDocument")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := coll.Find(ctx, documentFilter(meta), options.Find().SetProjection(withoutDisclosures))
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*model.CredentialNotification{}
+	if err := cursor.All(ctx, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// DeleteByDocument deletes the credential notifications of a document
+func (c *VCCredentialNotificationColl) DeleteByDocument(ctx context.Context, meta *model.MetaData) (int64, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:credential_notification:deleteByDocument")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := coll.DeleteMany(ctx, documentFilter(meta))
+	if err != nil {
+		return 0, err
+	}
+
+	return res.DeletedCount, nil
+}
+
+// RotateEncryptionKeys rewraps at most limit disclosure keys of the credential notifications of authenticSource that
+// are wrapped by a previous key encryption key, and returns the number of rewrapped credential notifications
+func (c *VCCredentialNotificationColl) RotateEncryptionKeys(ctx context.Context, authenticSource string, limit int64) (int, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:credential_notification:rotateEncryptionKeys")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if c.Service.encryptor == nil {
+		return 0, nil
+	}
+
+	filter := bson.M{
+		"authentic_source":       bson.M{"$eq": authenticSource},
+		"disclosures_key":        bson.M{"$ne": nil},
+		"disclosures_key.key_id": bson.M{"$ne": c.Service.encryptor.KeyID()},
+	}
+	opts := options.Find().SetLimit(limit).SetProjection(bson.M{"notification_id": 1, "disclosures_key": 1})
+
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	res := []*model.CredentialNotification{}
+	if err := cursor.All(ctx, &res); err != nil {
+		return 0, err
+	}
+
+	rewrapped := 0
+	for _, notification := range res {
+		key, _, err := c.Service.encryptor.RewrapKey(notification.DisclosuresKey)
+		if err != nil {
+			return rewrapped, err
+		}
+
+		notificationFilter := bson.M{
+			"notification_id":        bson.M{"$eq": notification.NotificationID},
+			"disclosures_key.key_id": bson.M{"$eq": notification.DisclosuresKey.KeyID},
+		}
+
+		result, err := coll.UpdateOne(ctx, notificationFilter, bson.M{"$set": bson.M{"disclosures_key": key}})
+		if err != nil {
+			return rewrapped, err
+		}
+		if result.ModifiedCount > 0 {
+			rewrapped++
+		}
+	}
+
+	return rewrapped, nil
+}
+
+// documentFilter matches the records of a document in collections that reference documents by top level fields
+func documentFilter(meta *model.MetaData) bson.M {
+	return bson.M{
+		"authentic_source": bson.M{"$eq": meta.AuthenticSource},
+		"document_type":    bson.M{"$eq": meta.DocumentType},
+		"document_id":      bson.M{"$eq": meta.DocumentID},
+	}
+}
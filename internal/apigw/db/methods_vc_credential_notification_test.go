@@ -0,0 +1,117 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"vc/pkg/helpers"
+	"vc/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestCredentialNotificationAdd(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	tts := []struct {
+		name     string
+		response bson.D
+		want     error
+	}{
+		{
+			name:     "added",
+			response: mtest.CreateSuccessResponse(),
+		},
+		{
+			name: "duplicate notification_id",
+			response: mtest.CreateWriteErrorsResponse(mtest.WriteError{
+				Index:   0,
+				Code:    11000,
+				Message: "E11000 duplicate key error collection: vc.credential_notification index: notification_id_uniq",
+			}),
+			want: helpers.ErrDuplicateKey,
+		},
+	}
+
+	for _, tt := range tts {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			s := mockService(t, mt)
+			mt.AddMockResponses(tt.response)
+
+			err := s.VCCredentialNotificationColl.Add(context.Background(), &model.CredentialNotification{
+				NotificationID:  "notification-1",
+				AuthenticSource: "SUNET",
+				DocumentType:    "PDA1",
+				DocumentID:      "doc-1",
+				Events:          []model.CredentialNotificationEvent{},
+			})
+			assert.Equal(t, tt.want, err)
+		})
+	}
+}
+
+func TestCredentialNotificationAddEvent(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	notification := bson.D{
+		{Key: "notification_id", Value: "notification-1"},
+		{Key: "authentic_source", Value: "SUNET"},
+		{Key: "document_type", Value: "PDA1"},
+		{Key: "document_id", Value: "doc-1"},
+		{Key: "issued_at", Value: int64(1714564800)},
+		{Key: "events", Value: bson.A{bson.D{
+			{Key: "event", Value: model.CredentialNotificationAccepted},
+			{Key: "timestamp", Value: int64(1714564900)},
+		}}},
+	}
+
+	tts := []struct {
+		name     string
+		response bson.D
+		want     *model.CredentialNotification
+		wantErr  error
+	}{
+		{
+			name:     "known notification_id",
+			response: bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: notification}},
+			want: &model.CredentialNotification{
+				NotificationID:  "notification-1",
+				AuthenticSource: "SUNET",
+				DocumentType:    "PDA1",
+				DocumentID:      "doc-1",
+				IssuedAt:        1714564800,
+				Events: []model.CredentialNotificationEvent{
+					{Event: model.CredentialNotificationAccepted, Timestamp: 1714564900},
+				},
+			},
+		},
+		{
+			name:     "unknown notification_id",
+			response: bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}},
+			wantErr:  helpers.ErrNoDocumentFound,
+		},
+	}
+
+	for _, tt := range tts {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			s := mockService(t, mt)
+			mt.AddMockResponses(tt.response)
+
+			got, err := s.VCCredentialNotificationColl.AddEvent(context.Background(), "notification-1", &model.CredentialNotificationEvent{
+				Event:     model.CredentialNotificationAccepted,
+				Timestamp: 1714564900,
+			})
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+
+			command := mt.GetStartedEvent().Command
+			assert.Equal(t, "notification-1", command.Lookup("query", "notification_id", "$eq").StringValue())
+			assert.Equal(t, model.CredentialNotificationAccepted, command.Lookup("update", "$push", "events", "event").StringValue())
+
+			// the disclosures are only read for a refresh
+			_, err = command.LookupErr("fields", "disclosures")
+			assert.NoError(t, err)
+		})
+	}
+}
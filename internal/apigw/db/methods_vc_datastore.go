@@ -2,6 +2,8 @@ package db
 
 import (
 	"context"
+	"errors"
+	"time"
 	"vc/pkg/helpers"
 	"vc/pkg/logger"
 	"vc/pkg/model"
@@ -16,14 +18,19 @@ import (
 // VCDatastoreColl is the generic collection
 type VCDatastoreColl struct {
 	Service *Service
-	Coll    *mongo.Collection
-	log     *logger.Log
+	tenantCollection
+	log *logger.Log
 }
 
 func (c *VCDatastoreColl) createIndex(ctx context.Context) error {
 	ctx, span := c.Service.tracer.Start(ctx, "db:vc:datastore:createIndex")
 	defer span.End()
 
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
 	indexDocumentIDInAuthenticSourceUniq := mongo.IndexModel{
 		Keys: bson.D{
 			primitive.E{Key: "meta.document_id", Value: 1},
@@ -32,7 +39,15 @@ func (c *VCDatastoreColl) createIndex(ctx context.Context) error {
 		},
 		Options: options.Index().SetName("document_unique_within_namespace").SetUnique(true),
 	}
-	_, err := c.Coll.Indexes().CreateMany(ctx, []mongo.IndexModel{indexDocumentIDInAuthenticSourceUniq})
+	indexChanges := mongo.IndexModel{
+		Keys: bson.D{
+			primitive.E{Key: "meta.authentic_source", Value: 1},
+			primitive.E{Key: "meta.modified_at", Value: 1},
+			primitive.E{Key: "_id", Value: 1},
+		},
+		Options: options.Index().SetName("document_changes"),
+	}
+	_, err = coll.Indexes().CreateMany(ctx, []mongo.IndexModel{indexDocumentIDInAuthenticSourceUniq, indexChanges})
 	if err != nil {
 		return err
 	}
@@ -44,8 +59,22 @@ func (c *VCDatastoreColl) Save(ctx context.Context, doc *model.CompleteDocument)
 	ctx, span := c.Service.tracer.Start(ctx, "db:vc:datastore:save")
 	defer span.End()
 
-	_, err := c.Coll.InsertOne(ctx, doc)
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	if doc.Meta != nil {
+		doc.Meta.Revision = 1
+		doc.Meta.ModifiedAt = time.Now().Unix()
+	}
+
+	stored, err := c.encryptDocument(doc)
 	if err != nil {
+		return err
+	}
+
+	if _, err := coll.InsertOne(ctx, stored); err != nil {
 		span.SetStatus(codes.Error, err.Error())
 
 		return err
@@ -54,6 +83,39 @@ func (c *VCDatastoreColl) Save(ctx context.Context, doc *model.CompleteDocument)
 	return nil
 }
 
+// withRevision adds the expected document revision to filter, nil matches any revision.
+// Documents stored before revisions were introduced lack the field and are treated as revision 0.
+func withRevision(filter bson.M, revision *int64) bson.M {
+	if revision == nil {
+		return filter
+	}
+	if *revision == 0 {
+		filter["meta.revision"] = bson.M{"$in": bson.A{0, nil}}
+	} else {
+		filter["meta.revision"] = bson.M{"$eq": *revision}
+	}
+	return filter
+}
+
+// revisionMismatch returns ErrPreconditionFailed if a document matching filter exists, else ErrNoDocumentFound
+func (c *VCDatastoreColl) revisionMismatch(ctx context.Context, filter bson.M) error {
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	delete(filter, "meta.revision")
+
+	n, err := coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return helpers.ErrPreconditionFailed
+	}
+	return helpers.ErrNoDocumentFound
+}
+
 // IDMappingQuery is the query to get authentic source person id
 type IDMappingQuery struct {
 	AuthenticSource string
@@ -62,24 +124,33 @@ type IDMappingQuery struct {
 
 // IDMapping return authentic source person id if any
 func (c *VCDatastoreColl) IDMapping(ctx context.Context, query *IDMappingQuery) (string, error) {
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return "", err
+	}
+
 	filter := bson.M{
 		"meta.authentic_source":     bson.M{"$eq": query.AuthenticSource},
 		"identities.schema.version": bson.M{"$eq": query.Identity.Schema.Version},
-		"identities.family_name":    bson.M{"$eq": query.Identity.FamilyName},
-		"identities.given_name":     bson.M{"$eq": query.Identity.GivenName},
-		"identities.birth_date":     bson.M{"$eq": query.Identity.BirthDate},
 	}
+	c.Service.identityFilter(filter, "identities.", "family_name", query.Identity.FamilyName)
+	c.Service.identityFilter(filter, "identities.", "given_name", query.Identity.GivenName)
+	c.Service.identityFilter(filter, "identities.", "birth_date", query.Identity.BirthDate)
 
 	opts := options.FindOne().SetProjection(bson.M{
 		"identities.authentic_source_person_id": 1,
+		"identities.encryption_key":             1,
 	})
 	res := &model.CompleteDocument{}
-	if err := c.Coll.FindOne(ctx, filter, opts).Decode(&res); err != nil {
+	if err := coll.FindOne(ctx, filter, opts).Decode(&res); err != nil {
 		return "", err
 	}
 	if res.Identities == nil || len(res.Identities) == 0 {
 		return "", helpers.ErrNoIdentityFound
 	}
+	if err := c.decryptDocument(res); err != nil {
+		return "", err
+	}
 
 	return res.Identities[0].AuthenticSourcePersonID, nil
 }
@@ -90,25 +161,48 @@ type AddDocumentIdentityQuery struct {
 	DocumentType    string            `json:"document_type" bson:"document_type"`
 	DocumentID      string            `json:"document_id" bson:"document_id"`
 	Identities      []*model.Identity `json:"identities" bson:"identities"`
+	Revision        *int64            `json:"-" bson:"-"`
 }
 
 // AddDocumentIdentity adds document identity
 func (c *VCDatastoreColl) AddDocumentIdentity(ctx context.Context, query *AddDocumentIdentityQuery) error {
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
 	filter := bson.M{
 		"meta.authentic_source": bson.M{"$eq": query.AuthenticSource},
 		"meta.document_id":      bson.M{"$eq": query.DocumentID},
 		"meta.document_type":    bson.M{"$eq": query.DocumentType},
 	}
+	filter = withRevision(filter, query.Revision)
+
+	identities := query.Identities
+	if c.Service.encryptor != nil {
+		identities = make([]*model.Identity, 0, len(query.Identities))
+		for _, identity := range query.Identities {
+			encrypted, err := c.Service.encryptor.EncryptIdentity(*identity)
+			if err != nil {
+				return err
+			}
+			identities = append(identities, &encrypted)
+		}
+	}
 
 	// This needs to make sure no duplicate authentic_source_person_id is added in the future
-	update := bson.M{"$addToSet": bson.M{"identities": bson.M{"$each": query.Identities}}}
+	update := bson.M{
+		"$addToSet": bson.M{"identities": bson.M{"$each": identities}},
+		"$inc":      bson.M{"meta.revision": 1},
+		"$set":      bson.M{"meta.modified_at": time.Now().Unix()},
+	}
 
-	result, err := c.Coll.UpdateOne(ctx, filter, update)
+	result, err := coll.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return err
 	}
-	if result.ModifiedCount == 0 {
-		return helpers.ErrNoDocumentFound
+	if result.MatchedCount == 0 {
+		return c.revisionMismatch(ctx, filter)
 	}
 
 	return nil
@@ -120,39 +214,66 @@ type DeleteDocumentIdentityQuery struct {
 	DocumentType            string `json:"document_type" bson:"document_type"`
 	DocumentID              string `json:"document_id" bson:"document_id"`
 	AuthenticSourcePersonID string `json:"authentic_source_person_id" bson:"authentic_source_person_id"`
+	Revision                *int64 `json:"-" bson:"-"`
 }
 
 // DeleteDocumentIdentity deletes identity in document
 func (c *VCDatastoreColl) DeleteDocumentIdentity(ctx context.Context, query *DeleteDocumentIdentityQuery) error {
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
 	filter := bson.M{
 		"meta.authentic_source": bson.M{"$eq": query.AuthenticSource},
 		"meta.document_id":      bson.M{"$eq": query.DocumentID},
 		"meta.document_type":    bson.M{"$eq": query.DocumentType},
 	}
+	filter = withRevision(filter, query.Revision)
+
+	pull := bson.M{}
+	c.Service.identityFilter(pull, "", "authentic_source_person_id", query.AuthenticSourcePersonID)
 
-	update := bson.M{"$pull": bson.M{"identities": bson.M{"authentic_source_person_id": query.AuthenticSourcePersonID}}}
-	_, err := c.Coll.UpdateOne(ctx, filter, update)
+	update := bson.M{
+		"$pull": bson.M{"identities": pull},
+		"$inc":  bson.M{"meta.revision": 1},
+		"$set":  bson.M{"meta.modified_at": time.Now().Unix()},
+	}
+	result, err := coll.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return err
 	}
+	if result.MatchedCount == 0 && query.Revision != nil {
+		return c.revisionMismatch(ctx, filter)
+	}
 	return nil
 }
 
-// Delete deletes a document
-func (c *VCDatastoreColl) Delete(ctx context.Context, doc *model.MetaData) error {
+// Delete deletes a document, revision nil deletes any revision
+func (c *VCDatastoreColl) Delete(ctx context.Context, doc *model.MetaData, revision *int64) error {
 	ctx, span := c.Service.tracer.Start(ctx, "db:vc:datastore:delete")
 	defer span.End()
 
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
 	filter := bson.M{
 		"meta.document_id":      bson.M{"$eq": doc.DocumentID},
 		"meta.authentic_source": bson.M{"$eq": doc.AuthenticSource},
 		"meta.document_type":    bson.M{"$eq": doc.DocumentType},
 	}
-	_, err := c.Coll.DeleteOne(ctx, filter)
+	filter = withRevision(filter, revision)
+
+	result, err := coll.DeleteOne(ctx, filter)
 	if err != nil {
 		span.SetStatus(codes.Error, err.Error())
 		return err
 	}
+	if result.DeletedCount == 0 && revision != nil {
+		return c.revisionMismatch(ctx, filter)
+	}
 	//c.service.log.Info("deleted document", "document_id", doc.DocumentID, "from authentic_source", doc.AuthenticSource)
 	return nil
 
@@ -166,18 +287,23 @@ type GetDocumentForCredential struct {
 
 // GetDocumentForCredential return matching document if any, or error
 func (c *VCDatastoreColl) GetDocumentForCredential(ctx context.Context, query *GetDocumentForCredential) (*model.Document, error) {
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	filter := bson.M{
-		"meta.authentic_source":                 bson.M{"$eq": query.Meta.AuthenticSource},
-		"meta.document_type":                    bson.M{"$eq": query.Meta.DocumentType},
-		"identities.authentic_source_person_id": bson.M{"$eq": query.Identity.AuthenticSourcePersonID},
+		"meta.authentic_source": bson.M{"$eq": query.Meta.AuthenticSource},
+		"meta.document_type":    bson.M{"$eq": query.Meta.DocumentType},
 	}
+	c.Service.identityFilter(filter, "identities.", "authentic_source_person_id", query.Identity.AuthenticSourcePersonID)
 	opt := options.FindOne().SetProjection(bson.M{
 		"meta":          1,
 		"document_data": 1,
 	})
 
 	res := &model.CompleteDocument{}
-	if err := c.Coll.FindOne(ctx, filter, opt).Decode(res); err != nil {
+	if err := coll.FindOne(ctx, filter, opt).Decode(res); err != nil {
 		return nil, err
 	}
 
@@ -196,6 +322,11 @@ type GetDocumentQuery struct {
 
 // GetDocument return matching document if any, or error
 func (c *VCDatastoreColl) GetDocument(ctx context.Context, query *GetDocumentQuery) (*model.Document, error) {
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	filter := bson.M{
 		"meta.authentic_source": bson.M{"$eq": query.Meta.AuthenticSource},
 		"meta.document_type":    bson.M{"$eq": query.Meta.DocumentType},
@@ -208,7 +339,7 @@ func (c *VCDatastoreColl) GetDocument(ctx context.Context, query *GetDocumentQue
 	})
 
 	res := &model.CompleteDocument{}
-	if err := c.Coll.FindOne(ctx, filter, opt).Decode(res); err != nil {
+	if err := coll.FindOne(ctx, filter, opt).Decode(res); err != nil {
 		return nil, err
 	}
 
@@ -230,6 +361,11 @@ type DocumentListQuery struct {
 
 // DocumentList return matching documents if any, or error
 func (c *VCDatastoreColl) DocumentList(ctx context.Context, query *DocumentListQuery) ([]*model.DocumentList, error) {
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	if err := helpers.Check(ctx, c.Service.cfg, query, c.Service.log); err != nil {
 		return nil, err
 	}
@@ -247,14 +383,14 @@ func (c *VCDatastoreColl) DocumentList(ctx context.Context, query *DocumentListQ
 	}
 
 	if query.Identity.AuthenticSourcePersonID != "" {
-		filter["identities.authentic_source_person_id"] = bson.M{"$eq": query.Identity.AuthenticSourcePersonID}
+		c.Service.identityFilter(filter, "identities.", "authentic_source_person_id", query.Identity.AuthenticSourcePersonID)
 	} else {
-		filter["identities.family_name"] = bson.M{"$eq": query.Identity.FamilyName}
-		filter["identities.given_name"] = bson.M{"$eq": query.Identity.GivenName}
-		filter["identities.birth_date"] = bson.M{"$eq": query.Identity.BirthDate}
+		c.Service.identityFilter(filter, "identities.", "family_name", query.Identity.FamilyName)
+		c.Service.identityFilter(filter, "identities.", "given_name", query.Identity.GivenName)
+		c.Service.identityFilter(filter, "identities.", "birth_date", query.Identity.BirthDate)
 	}
 
-	cursor, err := c.Coll.Find(ctx, filter)
+	cursor, err := coll.Find(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -269,6 +405,11 @@ func (c *VCDatastoreColl) DocumentList(ctx context.Context, query *DocumentListQ
 
 // GetQR return matching document and return its QR code, else error
 func (c *VCDatastoreColl) GetQR(ctx context.Context, attr *model.MetaData) (*model.QR, error) {
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	filter := bson.M{
 		"meta.authentic_source": bson.M{"$eq": attr.AuthenticSource},
 		"meta.document_type":    bson.M{"$eq": attr.DocumentType},
@@ -279,7 +420,7 @@ func (c *VCDatastoreColl) GetQR(ctx context.Context, attr *model.MetaData) (*mod
 	})
 
 	res := &model.CompleteDocument{}
-	if err := c.Coll.FindOne(ctx, filter, opt).Decode(res); err != nil {
+	if err := coll.FindOne(ctx, filter, opt).Decode(res); err != nil {
 		return nil, err
 	}
 	return res.QR, nil
@@ -293,6 +434,11 @@ type GetDocumentCollectIDQuery struct {
 
 // GetDocumentCollectID return matching document if any, or error
 func (c *VCDatastoreColl) GetDocumentCollectID(ctx context.Context, query *GetDocumentCollectIDQuery) (*model.Document, error) {
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	filter := bson.M{
 		"meta.authentic_source":  bson.M{"$eq": query.Meta.AuthenticSource},
 		"meta.collect.id":        bson.M{"$eq": query.Meta.Collect.ID},
@@ -301,11 +447,11 @@ func (c *VCDatastoreColl) GetDocumentCollectID(ctx context.Context, query *GetDo
 	}
 
 	if query.Identity.AuthenticSourcePersonID != "" {
-		filter["identities.authentic_source_person_id"] = bson.M{"$eq": query.Identity.AuthenticSourcePersonID}
+		c.Service.identityFilter(filter, "identities.", "authentic_source_person_id", query.Identity.AuthenticSourcePersonID)
 	} else {
-		filter["identities.family_name"] = bson.M{"$eq": query.Identity.FamilyName}
-		filter["identities.given_name"] = bson.M{"$eq": query.Identity.GivenName}
-		filter["identities.birth_date"] = bson.M{"$eq": query.Identity.BirthDate}
+		c.Service.identityFilter(filter, "identities.", "family_name", query.Identity.FamilyName)
+		c.Service.identityFilter(filter, "identities.", "given_name", query.Identity.GivenName)
+		c.Service.identityFilter(filter, "identities.", "birth_date", query.Identity.BirthDate)
 	}
 
 	opts := options.FindOne().SetProjection(bson.M{
@@ -314,7 +460,7 @@ func (c *VCDatastoreColl) GetDocumentCollectID(ctx context.Context, query *GetDo
 	})
 
 	res := &model.CompleteDocument{}
-	if err := c.Coll.FindOne(ctx, filter, opts).Decode(res); err != nil {
+	if err := coll.FindOne(ctx, filter, opts).Decode(res); err != nil {
 		return nil, err
 	}
 
@@ -325,35 +471,479 @@ func (c *VCDatastoreColl) GetDocumentCollectID(ctx context.Context, query *GetDo
 	return reply, nil
 }
 
+// GetDocumentCollectIDCandidate returns the document of the collect code of query with its identities decrypted,
+// whatever their names and birth date, for the identity of query to be scored against
+func (c *VCDatastoreColl) GetDocumentCollectIDCandidate(ctx context.Context, query *GetDocumentCollectIDQuery) (*model.CompleteDocument, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:datastore:getDocumentCollectIDCandidate")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"meta.authentic_source":  bson.M{"$eq": query.Meta.AuthenticSource},
+		"meta.collect.id":        bson.M{"$eq": query.Meta.Collect.ID},
+		"meta.document_type":     bson.M{"$eq": query.Meta.DocumentType},
+		"identities.schema.name": bson.M{"$eq": query.Identity.Schema.Name},
+	}
+
+	opts := options.FindOne().SetProjection(bson.M{
+		"meta":          1,
+		"identities":    1,
+		"document_data": 1,
+	})
+
+	res := &model.CompleteDocument{}
+	if err := coll.FindOne(ctx, filter, opts).Decode(res); err != nil {
+		return nil, err
+	}
+	if err := c.decryptDocument(res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// UseCollectID counts one use of the collect code of the document, ErrCollectIDConsumed is returned if it has been used
+// maxUses times. maxUses 0 is unlimited. ErrNoDocumentFound is returned for meta without a collect code.
+func (c *VCDatastoreColl) UseCollectID(ctx context.Context, meta *model.MetaData, maxUses int64) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:datastore:useCollectID")
+	defer span.End()
+
+	if meta == nil || meta.Collect == nil {
+		return helpers.ErrNoDocumentFound
+	}
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{
+		"meta.authentic_source": bson.M{"$eq": meta.AuthenticSource},
+		"meta.document_type":    bson.M{"$eq": meta.DocumentType},
+		"meta.document_id":      bson.M{"$eq": meta.DocumentID},
+		"meta.collect.id":       bson.M{"$eq": meta.Collect.ID},
+	}
+	if maxUses > 0 {
+		// $not matches documents uploaded before uses was counted
+		filter["meta.collect.uses"] = bson.M{"$not": bson.M{"$gte": maxUses}}
+	}
+
+	result, err := coll.UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"meta.collect.uses": 1}})
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return helpers.ErrCollectIDConsumed
+	}
+	return nil
+}
+
+// ReleaseCollectID takes back one use of the collect code of the document, when no credential was issued
+func (c *VCDatastoreColl) ReleaseCollectID(ctx context.Context, meta *model.MetaData) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:datastore:releaseCollectID")
+	defer span.End()
+
+	if meta == nil || meta.Collect == nil {
+		return helpers.ErrNoDocumentFound
+	}
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{
+		"meta.authentic_source": bson.M{"$eq": meta.AuthenticSource},
+		"meta.document_type":    bson.M{"$eq": meta.DocumentType},
+		"meta.document_id":      bson.M{"$eq": meta.DocumentID},
+		"meta.collect.id":       bson.M{"$eq": meta.Collect.ID},
+		"meta.collect.uses":     bson.M{"$gt": 0},
+	}
+
+	_, err = coll.UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"meta.collect.uses": -1}})
+	return err
+}
+
+// Get gets one document by meta.document_id, meta.authentic_source and meta.document_type
+func (c *VCDatastoreColl) Get(ctx context.Context, meta *model.MetaData) (*model.CompleteDocument, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:datastore:get")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"meta.authentic_source": bson.M{"$eq": meta.AuthenticSource},
+		"meta.document_type":    bson.M{"$eq": meta.DocumentType},
+		"meta.document_id":      bson.M{"$eq": meta.DocumentID},
+	}
+	res := &model.CompleteDocument{}
+	if err := coll.FindOne(ctx, filter).Decode(res); err != nil {
+		return nil, err
+	}
+	if err := c.decryptDocument(res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 // GetByRevocationID gets one document by meta.revocation.id and meta.authentic_source
 func (c *VCDatastoreColl) GetByRevocationID(ctx context.Context, q *model.MetaData) (*model.CompleteDocument, error) {
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	filter := bson.M{
 		"meta.authentic_source": bson.M{"$eq": q.AuthenticSource},
 		"meta.document_type":    bson.M{"$eq": q.DocumentType},
 		"meta.revocation.id":    bson.M{"$eq": q.Revocation.ID},
 	}
 	res := &model.CompleteDocument{}
-	if err := c.Coll.FindOne(ctx, filter).Decode(res); err != nil {
+	if err := coll.FindOne(ctx, filter).Decode(res); err != nil {
+		return nil, err
+	}
+	if err := c.decryptDocument(res); err != nil {
 		return nil, err
 	}
 	return res, nil
 }
 
-// Replace replaces one document
+// StatusHistory returns the status history of one document, ErrNoDocumentFound if there is no such document
+func (c *VCDatastoreColl) StatusHistory(ctx context.Context, meta *model.MetaData) ([]model.DocumentStatus, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:datastore:statusHistory")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"meta.authentic_source": bson.M{"$eq": meta.AuthenticSource},
+		"meta.document_type":    bson.M{"$eq": meta.DocumentType},
+		"meta.document_id":      bson.M{"$eq": meta.DocumentID},
+	}
+	opts := options.FindOne().SetProjection(bson.M{"status_history": 1})
+
+	res := &model.CompleteDocument{}
+	if err := coll.FindOne(ctx, filter, opts).Decode(res); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, helpers.ErrNoDocumentFound
+		}
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	if res.StatusHistory == nil {
+		return []model.DocumentStatus{}, nil
+	}
+	return res.StatusHistory, nil
+}
+
+// Replace replaces one document if its stored revision is unchanged, and increments the revision. doc gets the new
+// revision only if it was replaced.
 func (c *VCDatastoreColl) Replace(ctx context.Context, doc *model.CompleteDocument) error {
 	ctx, span := c.Service.tracer.Start(ctx, "db:vc:datastore:replace")
 	defer span.End()
 
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	revision := doc.Meta.Revision
 	filter := bson.M{
 		"meta.document_id":      bson.M{"$eq": doc.Meta.DocumentID},
 		"meta.authentic_source": bson.M{"$eq": doc.Meta.AuthenticSource},
 	}
+	filter = withRevision(filter, &revision)
+
+	meta := *doc.Meta
+	meta.Revision++
+	meta.ModifiedAt = time.Now().Unix()
+	updated := *doc
+	updated.Meta = &meta
+	stored, err := c.encryptDocument(&updated)
+	if err != nil {
+		return err
+	}
 
-	_, err := c.Coll.ReplaceOne(ctx, filter, doc)
+	result, err := coll.ReplaceOne(ctx, filter, stored)
 	if err != nil {
 		span.SetStatus(codes.Error, err.Error())
 		return err
 	}
+	if result.MatchedCount == 0 {
+		return c.revisionMismatch(ctx, filter)
+	}
+
+	doc.Meta.Revision = meta.Revision
+	doc.Meta.ModifiedAt = meta.ModifiedAt
 	c.log.Info("updated document", "document_id", doc.Meta.DocumentID)
 	return nil
 }
+
+// DocumentStatistics holds the number of documents for one authentic source and document type
+type DocumentStatistics struct {
+	AuthenticSource string `json:"authentic_source" bson:"authentic_source"`
+	DocumentType    string `json:"document_type" bson:"document_type"`
+	Documents       int64  `json:"documents" bson:"documents"`
+	Revoked         int64  `json:"revoked" bson:"revoked"`
+}
+
+// Statistics return the number of documents and revoked documents grouped by authentic source and document type
+func (c *VCDatastoreColl) Statistics(ctx context.Context) ([]*DocumentStatistics, error) {
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"authentic_source": "$meta.authentic_source",
+				"document_type":    "$meta.document_type",
+			},
+			"documents": bson.M{"$sum": 1},
+			"revoked": bson.M{"$sum": bson.M{
+				"$cond": bson.A{bson.M{"$eq": bson.A{"$meta.revocation.revoked", true}}, 1, 0},
+			}},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":              0,
+			"authentic_source": "$_id.authentic_source",
+			"document_type":    "$_id.document_type",
+			"documents":        1,
+			"revoked":          1,
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "authentic_source", Value: 1}, {Key: "document_type", Value: 1}}}},
+	}
+
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*DocumentStatistics{}
+	if err := cursor.All(ctx, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ExportDocuments calls fn for every document of authenticSource, ordered by document type and document id
+func (c *VCDatastoreColl) ExportDocuments(ctx context.Context, authenticSource string, fn func(doc *model.CompleteDocument) error) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:datastore:exportDocuments")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"meta.authentic_source": bson.M{"$eq": authenticSource}}
+	opts := options.Find().SetSort(bson.D{{Key: "meta.document_type", Value: 1}, {Key: "meta.document_id", Value: 1}})
+
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		doc := &model.CompleteDocument{}
+		if err := cursor.Decode(doc); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return err
+		}
+		if err := c.decryptDocument(doc); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return err
+		}
+		if err := fn(doc); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// FindBySubject returns the documents of authenticSource with an identity of authenticSourcePersonID
+func (c *VCDatastoreColl) FindBySubject(ctx context.Context, authenticSource, authenticSourcePersonID string) ([]*model.CompleteDocument, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:datastore:findBySubject")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"meta.authentic_source": bson.M{"$eq": authenticSource}}
+	c.Service.identityFilter(filter, "identities.", "authentic_source_person_id", authenticSourcePersonID)
+
+	cursor, err := coll.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 0}))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	docs := []*model.CompleteDocument{}
+	if err := cursor.All(ctx, &docs); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	for _, doc := range docs {
+		if err := c.decryptDocument(doc); err != nil {
+			return nil, err
+		}
+	}
+
+	return docs, nil
+}
+
+// Exists reports if a document with the same document id, authentic source and document type is stored
+func (c *VCDatastoreColl) Exists(ctx context.Context, meta *model.MetaData) (bool, error) {
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	filter := bson.M{
+		"meta.document_id":      bson.M{"$eq": meta.DocumentID},
+		"meta.authentic_source": bson.M{"$eq": meta.AuthenticSource},
+		"meta.document_type":    bson.M{"$eq": meta.DocumentType},
+	}
+
+	n, err := coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+// Import inserts an exported document as is, unlike Save the revision is kept. It is modified now, so that it is
+// in the changes of this deployment.
+func (c *VCDatastoreColl) Import(ctx context.Context, doc *model.CompleteDocument) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:datastore:import")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	doc.Meta.ModifiedAt = time.Now().Unix()
+
+	stored, err := c.encryptDocument(doc)
+	if err != nil {
+		return err
+	}
+
+	if _, err := coll.InsertOne(ctx, stored); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// ChangePosition orders the changes of documents by modification time, and insertion within the same second
+type ChangePosition struct {
+	ModifiedAt int64
+	ID         primitive.ObjectID
+}
+
+// ChangesQuery is the query for Changes
+type ChangesQuery struct {
+	AuthenticSource string
+	DocumentType    string
+
+	// After is the position of the last change already read, the zero position starts from the first document
+	After ChangePosition
+
+	// Before leaves out documents modified at or after this unix time, their writes may still be committed out of order
+	Before int64
+
+	Limit int64
+}
+
+// DocumentChange is the metadata of a changed document and its position in the changes
+type DocumentChange struct {
+	Position ChangePosition
+	Meta     *model.MetaData
+}
+
+// Changes returns the metadata of the documents modified after query.After, oldest first. Documents stored before
+// modification times were introduced come first.
+func (c *VCDatastoreColl) Changes(ctx context.Context, query *ChangesQuery) ([]*DocumentChange, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:datastore:changes")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sameSecond := bson.M{"meta.modified_at": bson.M{"$eq": query.After.ModifiedAt}, "_id": bson.M{"$gt": query.After.ID}}
+	if query.After.ModifiedAt == 0 {
+		sameSecond["meta.modified_at"] = bson.M{"$in": bson.A{0, nil}}
+	}
+
+	filter := bson.M{
+		"meta.authentic_source": bson.M{"$eq": query.AuthenticSource},
+		"$and": bson.A{
+			bson.M{"$or": bson.A{
+				bson.M{"meta.modified_at": bson.M{"$gt": query.After.ModifiedAt}},
+				sameSecond,
+			}},
+			bson.M{"$or": bson.A{
+				bson.M{"meta.modified_at": bson.M{"$lt": query.Before}},
+				bson.M{"meta.modified_at": nil},
+			}},
+		},
+	}
+	if query.DocumentType != "" {
+		filter["meta.document_type"] = bson.M{"$eq": query.DocumentType}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "meta.modified_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetProjection(bson.M{"meta": 1}).
+		SetLimit(query.Limit)
+
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	res := []struct {
+		ID   primitive.ObjectID `bson:"_id"`
+		Meta *model.MetaData    `bson:"meta"`
+	}{}
+	if err := cursor.All(ctx, &res); err != nil {
+		return nil, err
+	}
+
+	changes := make([]*DocumentChange, 0, len(res))
+	for _, doc := range res {
+		changes = append(changes, &DocumentChange{
+			Position: ChangePosition{ModifiedAt: doc.Meta.ModifiedAt, ID: doc.ID},
+			Meta:     doc.Meta,
+		})
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"vc/pkg/helpers"
+	"vc/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestUseCollectID(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	meta := &model.MetaData{
+		AuthenticSource: "SUNET",
+		DocumentType:    "PDA1",
+		DocumentID:      "doc-1",
+		Collect:         &model.Collect{ID: "collect-1"},
+	}
+
+	tts := []struct {
+		name        string
+		meta        *model.MetaData
+		response    bson.D
+		wantErr     error
+		wantCommand bool
+	}{
+		{
+			name:    "no meta",
+			wantErr: helpers.ErrNoDocumentFound,
+		},
+		{
+			name:    "no collect code",
+			meta:    &model.MetaData{AuthenticSource: "SUNET", DocumentType: "PDA1", DocumentID: "doc-1"},
+			wantErr: helpers.ErrNoDocumentFound,
+		},
+		{
+			name:        "used",
+			meta:        meta,
+			response:    bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}},
+			wantCommand: true,
+		},
+		{
+			name:        "consumed",
+			meta:        meta,
+			response:    bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}},
+			wantErr:     helpers.ErrCollectIDConsumed,
+			wantCommand: true,
+		},
+	}
+
+	for _, tt := range tts {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			s := mockService(t, mt)
+			if tt.response != nil {
+				mt.AddMockResponses(tt.response)
+			}
+
+			err := s.VCDatastoreColl.UseCollectID(context.Background(), tt.meta, 1)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantCommand, len(mt.GetAllStartedEvents()) == 1)
+		})
+	}
+}
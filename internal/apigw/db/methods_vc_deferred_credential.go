@@ -0,0 +1,357 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"vc/pkg/helpers"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// VCDeferredCredentialColl is the deferred credential collection
+type VCDeferredCredentialColl struct {
+	Service *Service
+	tenantCollection
+	log *logger.Log
+}
+
+func (c *VCDeferredCredentialColl) createIndex(ctx context.Context) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:deferred_credential:createIndex")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	indexTransactionIDUniq := mongo.IndexModel{
+		Keys:    bson.D{{Key: "transaction_id", Value: 1}},
+		Options: options.Index().SetName("transaction_id_uniq").SetUnique(true),
+	}
+	indexStatus := mongo.IndexModel{
+		Keys:    bson.D{{Key: "status", Value: 1}, {Key: "updated_at", Value: 1}},
+		Options: options.Index().SetName("status_updated_at"),
+	}
+
+	_, err = coll.Indexes().CreateMany(ctx, []mongo.IndexModel{indexTransactionIDUniq, indexStatus})
+	return err
+}
+
+// Add adds a deferred credential, its identity is stored encrypted if field encryption is enabled
+func (c *VCDeferredCredentialColl) Add(ctx context.Context, deferred *model.DeferredCredential) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:deferred_credential:add")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	stored := *deferred
+	stored.Identity, err = c.Service.encryptIdentity(deferred.Identity)
+	if err != nil {
+		return err
+	}
+
+	if _, err := coll.InsertOne(ctx, &stored); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return helpers.ErrDuplicateKey
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Get returns the deferred credential of transactionID, ErrNoDocumentFound if it is unknown
+func (c *VCDeferredCredentialColl) Get(ctx context.Context, transactionID string) (*model.DeferredCredential, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:deferred_credential:get")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"transaction_id": bson.M{"$eq": transactionID}}
+
+	res := &model.DeferredCredential{}
+	if err := coll.FindOne(ctx, filter, options.FindOne().SetProjection(bson.M{"_id": 0})).Decode(res); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, helpers.ErrNoDocumentFound
+		}
+		return nil, err
+	}
+
+	if err := c.Service.decryptIdentity(res.Identity); err != nil {
+		return nil, err
+	}
+	if c.Service.encryptor != nil && res.Credential != nil {
+		var err error
+		if res.Credential.Disclosures, err = c.Service.encryptor.DecryptValues("disclosures", res.Credential.Disclosures, res.Credential.DisclosuresKey); err != nil {
+			return nil, err
+		}
+		res.Credential.DisclosuresKey = nil
+	}
+
+	return res, nil
+}
+
+// Pending returns at most limit pending deferred credentials, least recently tried first
+func (c *VCDeferredCredentialColl) Pending(ctx context.Context, limit int64) ([]*model.DeferredCredential, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:deferred_credential:pending")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"status": bson.M{"$eq": model.DeferredStatusPending}}
+	opts := options.Find().SetSort(bson.D{{Key: "updated_at", Value: 1}}).SetLimit(limit).SetProjection(bson.M{"_id": 0})
+
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*model.DeferredCredential{}
+	if err := cursor.All(ctx, &res); err != nil {
+		return nil, err
+	}
+
+	for _, deferred := range res {
+		if err := c.Service.decryptIdentity(deferred.Identity); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// Update replaces the status, attempts and credential of a deferred credential, the disclosures of the credential are
+// stored encrypted if field encryption is enabled
+func (c *VCDeferredCredentialColl) Update(ctx context.Context, deferred *model.DeferredCredential) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:deferred_credential:update")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	credential := deferred.Credential
+	if c.Service.encryptor != nil && credential != nil && len(credential.Disclosures) > 0 {
+		stored := *credential
+		var err error
+		stored.Disclosures, stored.DisclosuresKey, err = c.Service.encryptor.EncryptValues("disclosures", credential.Disclosures)
+		if err != nil {
+			return err
+		}
+		credential = &stored
+	}
+
+	filter := bson.M{"transaction_id": bson.M{"$eq": deferred.TransactionID}}
+	update := bson.M{"$set": bson.M{
+		"status":     deferred.Status,
+		"attempts":   deferred.Attempts,
+		"updated_at": deferred.UpdatedAt,
+		"credential": credential,
+	}}
+
+	res, err := coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return helpers.ErrNoDocumentFound
+	}
+
+	return nil
+}
+
+// Delete deletes the deferred credential of transactionID
+func (c *VCDeferredCredentialColl) Delete(ctx context.Context, transactionID string) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:deferred_credential:delete")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = coll.DeleteOne(ctx, bson.M{"transaction_id": bson.M{"$eq": transactionID}})
+	return err
+}
+
+// subjectFilter matches the deferred credentials of a person
+func (c *VCDeferredCredentialColl) subjectFilter(authenticSource, authenticSourcePersonID string) bson.M {
+	filter := bson.M{"authentic_source": bson.M{"$eq": authenticSource}}
+	c.Service.identityFilter(filter, "identity.", "authentic_source_person_id", authenticSourcePersonID)
+
+	return filter
+}
+
+// ListBySubject returns the deferred credentials of a person
+func (c *VCDeferredCredentialColl) ListBySubject(ctx context.Context, authenticSource, authenticSourcePersonID string) ([]*model.DeferredCredential, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:deferred_credential:listBySubject")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().SetProjection(bson.M{"_id": 0})
+	cursor, err := coll.Find(ctx, c.subjectFilter(authenticSource, authenticSourcePersonID), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*model.DeferredCredential{}
+	if err := cursor.All(ctx, &res); err != nil {
+		return nil, err
+	}
+
+	for _, deferred := range res {
+		if err := c.Service.decryptIdentity(deferred.Identity); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// DeleteBySubject deletes the deferred credentials of a person
+func (c *VCDeferredCredentialColl) DeleteBySubject(ctx context.Context, authenticSource, authenticSourcePersonID string) (int64, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:deferred_credential:deleteBySubject")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := coll.DeleteMany(ctx, c.subjectFilter(authenticSource, authenticSourcePersonID))
+	if err != nil {
+		return 0, err
+	}
+
+	return res.DeletedCount, nil
+}
+
+// RotateIdentityKeys rewraps at most limit identity keys of the deferred credentials of authenticSource that are wrapped
+// by a previous key encryption key, and returns the number of rewrapped deferred credentials
+func (c *VCDeferredCredentialColl) RotateIdentityKeys(ctx context.Context, authenticSource string, limit int64) (int, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:deferred_credential:rotateIdentityKeys")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if c.Service.encryptor == nil {
+		return 0, nil
+	}
+
+	filter := bson.M{
+		"authentic_source":               bson.M{"$eq": authenticSource},
+		"identity.encryption_key":        bson.M{"$ne": nil},
+		"identity.encryption_key.key_id": bson.M{"$ne": c.Service.encryptor.KeyID()},
+	}
+
+	cursor, err := coll.Find(ctx, filter, options.Find().SetLimit(limit))
+	if err != nil {
+		return 0, err
+	}
+
+	res := []*model.DeferredCredential{}
+	if err := cursor.All(ctx, &res); err != nil {
+		return 0, err
+	}
+
+	rewrapped := 0
+	for _, deferred := range res {
+		previousKeyID := deferred.Identity.EncryptionKey.KeyID
+		if _, err := c.Service.encryptor.Rewrap(deferred.Identity); err != nil {
+			return rewrapped, err
+		}
+
+		update := bson.M{"$set": bson.M{"identity.encryption_key": deferred.Identity.EncryptionKey}}
+		deferredFilter := bson.M{
+			"transaction_id":                 bson.M{"$eq": deferred.TransactionID},
+			"identity.encryption_key.key_id": bson.M{"$eq": previousKeyID},
+		}
+
+		result, err := coll.UpdateOne(ctx, deferredFilter, update)
+		if err != nil {
+			return rewrapped, err
+		}
+		if result.ModifiedCount > 0 {
+			rewrapped++
+		}
+	}
+
+	return rewrapped, nil
+}
+
+// RotateDisclosureKeys rewraps at most limit disclosure keys of the issued deferred credentials of authenticSource that
+// are wrapped by a previous key encryption key, and returns the number of rewrapped deferred credentials
+func (c *VCDeferredCredentialColl) RotateDisclosureKeys(ctx context.Context, authenticSource string, limit int64) (int, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:deferred_credential:rotateDisclosureKeys")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if c.Service.encryptor == nil {
+		return 0, nil
+	}
+
+	filter := bson.M{
+		"authentic_source":                  bson.M{"$eq": authenticSource},
+		"credential.disclosures_key":        bson.M{"$ne": nil},
+		"credential.disclosures_key.key_id": bson.M{"$ne": c.Service.encryptor.KeyID()},
+	}
+	opts := options.Find().SetLimit(limit).SetProjection(bson.M{"transaction_id": 1, "credential.disclosures_key": 1})
+
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	res := []*model.DeferredCredential{}
+	if err := cursor.All(ctx, &res); err != nil {
+		return 0, err
+	}
+
+	rewrapped := 0
+	for _, deferred := range res {
+		key, _, err := c.Service.encryptor.RewrapKey(deferred.Credential.DisclosuresKey)
+		if err != nil {
+			return rewrapped, err
+		}
+
+		deferredFilter := bson.M{
+			"transaction_id":                    bson.M{"$eq": deferred.TransactionID},
+			"credential.disclosures_key.key_id": bson.M{"$eq": deferred.Credential.DisclosuresKey.KeyID},
+		}
+
+		result, err := coll.UpdateOne(ctx, deferredFilter, bson.M{"$set": bson.M{"credential.disclosures_key": key}})
+		if err != nil {
+			return rewrapped, err
+		}
+		if result.ModifiedCount > 0 {
+			rewrapped++
+		}
+	}
+
+	return rewrapped, nil
+}
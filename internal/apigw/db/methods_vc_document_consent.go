@@ -0,0 +1,287 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"vc/pkg/helpers"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// VCDocumentConsentColl is the document consent collection
+type VCDocumentConsentColl struct {
+	Service *Service
+	tenantCollection
+	log *logger.Log
+}
+
+func (c *VCDocumentConsentColl) createIndex(ctx context.Context) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:document_consent:createIndex")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	indexConsentIDUniq := mongo.IndexModel{
+		Keys:    bson.D{{Key: "consent_id", Value: 1}},
+		Options: options.Index().SetName("consent_id_uniq").SetUnique(true),
+	}
+	indexDocument := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "authentic_source", Value: 1},
+			{Key: "document_type", Value: 1},
+			{Key: "document_id", Value: 1},
+		},
+		Options: options.Index().SetName("document"),
+	}
+
+	_, err = coll.Indexes().CreateMany(ctx, []mongo.IndexModel{indexConsentIDUniq, indexDocument})
+	return err
+}
+
+// Add adds a document consent
+func (c *VCDocumentConsentColl) Add(ctx context.Context, consent *model.DocumentConsent) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:document_consent:add")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, err := coll.InsertOne(ctx, consent); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return helpers.ErrDuplicateKey
+		}
+		return err
+	}
+
+	return nil
+}
+
+// DocumentConsentQuery is the query for the consents of one document
+type DocumentConsentQuery struct {
+	AuthenticSource string `validate:"required"`
+	DocumentType    string `validate:"required"`
+	DocumentID      string `validate:"required"`
+
+	// ActiveOnly excludes withdrawn consents
+	ActiveOnly bool
+}
+
+// List returns the consents of a document, oldest first
+func (c *VCDocumentConsentColl) List(ctx context.Context, query *DocumentConsentQuery) ([]*model.DocumentConsent, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:document_consent:list")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"authentic_source": bson.M{"$eq": query.AuthenticSource},
+		"document_type":    bson.M{"$eq": query.DocumentType},
+		"document_id":      bson.M{"$eq": query.DocumentID},
+	}
+	if query.ActiveOnly {
+		filter["withdrawn_at"] = bson.M{"$eq": 0}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}).SetProjection(bson.M{"_id": 0})
+
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*model.DocumentConsent{}
+	if err := cursor.All(ctx, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// DeleteByDocument deletes the consents of a document, and returns their consent ids
+func (c *VCDocumentConsentColl) DeleteByDocument(ctx context.Context, meta *model.MetaData) ([]string, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:document_consent:deleteByDocument")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	consents, err := c.List(ctx, &DocumentConsentQuery{
+		AuthenticSource: meta.AuthenticSource,
+		DocumentType:    meta.DocumentType,
+		DocumentID:      meta.DocumentID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	consentIDs := make([]string, 0, len(consents))
+	for _, consent := range consents {
+		consentIDs = append(consentIDs, consent.ConsentID)
+	}
+	if len(consentIDs) == 0 {
+		return consentIDs, nil
+	}
+
+	if _, err := coll.DeleteMany(ctx, bson.M{"consent_id": bson.M{"$in": consentIDs}}); err != nil {
+		return nil, err
+	}
+
+	return consentIDs, nil
+}
+
+// Withdraw marks an active consent of a document as withdrawn and returns it
+func (c *VCDocumentConsentColl) Withdraw(ctx context.Context, query *DocumentConsentQuery, consentID string, withdrawnAt int64) (*model.DocumentConsent, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:document_consent:withdraw")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"consent_id":       bson.M{"$eq": consentID},
+		"authentic_source": bson.M{"$eq": query.AuthenticSource},
+		"document_type":    bson.M{"$eq": query.DocumentType},
+		"document_id":      bson.M{"$eq": query.DocumentID},
+		"withdrawn_at":     bson.M{"$eq": 0},
+	}
+	update := bson.M{"$set": bson.M{"withdrawn_at": withdrawnAt}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After).SetProjection(bson.M{"_id": 0})
+
+	res := &model.DocumentConsent{}
+	if err := coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(res); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, helpers.ErrNoConsentFound
+		}
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// VCConsentAuditColl is the append only audit trail of document consents
+type VCConsentAuditColl struct {
+	Service *Service
+	tenantCollection
+	log *logger.Log
+}
+
+func (c *VCConsentAuditColl) createIndex(ctx context.Context) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:consent_audit:createIndex")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "consent_id", Value: 1}, {Key: "timestamp", Value: 1}},
+		Options: options.Index().SetName("consent_id"),
+	}
+
+	_, err = coll.Indexes().CreateOne(ctx, indexModel)
+	return err
+}
+
+// Add appends an audit entry, entries are never deleted and only updated by Pseudonymize
+func (c *VCConsentAuditColl) Add(ctx context.Context, entry *model.ConsentAuditEntry) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:consent_audit:add")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = coll.InsertOne(ctx, entry)
+	return err
+}
+
+// List returns the audit entries of the consents, oldest first
+func (c *VCConsentAuditColl) List(ctx context.Context, consentIDs []string) ([]*model.ConsentAuditEntry, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:consent_audit:list")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"consent_id": bson.M{"$in": consentIDs}}
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}}).SetProjection(bson.M{"_id": 0})
+
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*model.ConsentAuditEntry{}
+	if err := cursor.All(ctx, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Pseudonymize replaces approved_by of the audit entries of the consents by its SHA-256, the entries are kept
+func (c *VCConsentAuditColl) Pseudonymize(ctx context.Context, consentIDs []string) (int64, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:consent_audit:pseudonymize")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	entries, err := c.List(ctx, consentIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	var n int64
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.ApprovedBy, pseudonymPrefix) {
+			continue
+		}
+		filter := bson.M{
+			"consent_id":  bson.M{"$eq": entry.ConsentID},
+			"approved_by": bson.M{"$eq": entry.ApprovedBy},
+		}
+		update := bson.M{"$set": bson.M{"approved_by": Pseudonym(entry.ApprovedBy)}}
+
+		res, err := coll.UpdateMany(ctx, filter, update)
+		if err != nil {
+			return n, err
+		}
+		n += res.ModifiedCount
+	}
+
+	return n, nil
+}
+
+// pseudonymPrefix marks a pseudonymized value
+const pseudonymPrefix = "sha256:"
+
+// Pseudonym returns the hex encoded SHA-256 of value, prefixed by sha256:
+func Pseudonym(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return pseudonymPrefix + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,182 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"vc/pkg/helpers"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// VCDocumentVersionColl is the version history of the document data of documents
+type VCDocumentVersionColl struct {
+	Service *Service
+	tenantCollection
+	log *logger.Log
+}
+
+func (c *VCDocumentVersionColl) createIndex(ctx context.Context) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:document_version:createIndex")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	indexVersionUniq := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "authentic_source", Value: 1},
+			{Key: "document_type", Value: 1},
+			{Key: "document_id", Value: 1},
+			{Key: "document_data_version", Value: 1},
+		},
+		Options: options.Index().SetName("document_version_uniq").SetUnique(true),
+	}
+
+	_, err = coll.Indexes().CreateOne(ctx, indexVersionUniq)
+	return err
+}
+
+// Add adds a version of a document, a version that is already stored is kept as is
+func (c *VCDocumentVersionColl) Add(ctx context.Context, version *model.DocumentVersion) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:document_version:add")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{
+		"authentic_source":      bson.M{"$eq": version.AuthenticSource},
+		"document_type":         bson.M{"$eq": version.DocumentType},
+		"document_id":           bson.M{"$eq": version.DocumentID},
+		"document_data_version": bson.M{"$eq": version.DocumentDataVersion},
+	}
+	update := bson.M{"$setOnInsert": version}
+
+	_, err = coll.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// Get returns one version of a document, ErrNoDocumentFound if there is no such version
+func (c *VCDocumentVersionColl) Get(ctx context.Context, meta *model.MetaData, documentDataVersion string) (*model.DocumentVersion, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:document_version:get")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := documentFilter(meta)
+	filter["document_data_version"] = bson.M{"$eq": documentDataVersion}
+
+	res := &model.DocumentVersion{}
+	if err := coll.FindOne(ctx, filter, options.FindOne().SetProjection(bson.M{"_id": 0})).Decode(res); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, helpers.ErrNoDocumentFound
+		}
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ListByDocument returns the versions of a document without their document data, oldest first
+func (c *VCDocumentVersionColl) ListByDocument(ctx context.Context, meta *model.MetaData) ([]*model.DocumentVersion, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:document_version:listByDocument")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().SetProjection(bson.M{"_id": 0, "document_data": 0}).SetSort(bson.D{{Key: "uploaded_at", Value: 1}})
+	cursor, err := coll.Find(ctx, documentFilter(meta), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*model.DocumentVersion{}
+	if err := cursor.All(ctx, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// DeleteByDocument deletes the versions of a document
+func (c *VCDocumentVersionColl) DeleteByDocument(ctx context.Context, meta *model.MetaData) (int64, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:document_version:deleteByDocument")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := coll.DeleteMany(ctx, documentFilter(meta))
+	if err != nil {
+		return 0, err
+	}
+
+	return res.DeletedCount, nil
+}
+
+// ForEach calls fn with each version of the documents of authenticSource
+func (c *VCDocumentVersionColl) ForEach(ctx context.Context, authenticSource string, fn func(version *model.DocumentVersion) error) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:document_version:forEach")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"authentic_source": bson.M{"$eq": authenticSource}}
+	cursor, err := coll.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 0}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		version := &model.DocumentVersion{}
+		if err := cursor.Decode(version); err != nil {
+			return err
+		}
+		if err := fn(version); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// SetDocumentData replaces the document data of a stored version
+func (c *VCDocumentVersionColl) SetDocumentData(ctx context.Context, version *model.DocumentVersion) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:document_version:setDocumentData")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	filter := documentFilter(&model.MetaData{
+		AuthenticSource: version.AuthenticSource,
+		DocumentType:    version.DocumentType,
+		DocumentID:      version.DocumentID,
+	})
+	filter["document_data_version"] = bson.M{"$eq": version.DocumentDataVersion}
+
+	_, err = coll.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"document_data": version.DocumentData}})
+	return err
+}
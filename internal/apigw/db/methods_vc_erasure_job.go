@@ -0,0 +1,168 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+	"vc/pkg/helpers"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// VCErasureJobColl is the data subject erasure job collection
+type VCErasureJobColl struct {
+	Service *Service
+	tenantCollection
+	log *logger.Log
+}
+
+func (c *VCErasureJobColl) createIndex(ctx context.Context) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:erasure_job:createIndex")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	indexJobIDUniq := mongo.IndexModel{
+		Keys:    bson.D{{Key: "job_id", Value: 1}},
+		Options: options.Index().SetName("job_id_uniq").SetUnique(true),
+	}
+
+	indexStatus := mongo.IndexModel{
+		Keys:    bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}},
+		Options: options.Index().SetName("status_created_at"),
+	}
+
+	_, err = coll.Indexes().CreateMany(ctx, []mongo.IndexModel{indexJobIDUniq, indexStatus})
+	return err
+}
+
+// Add adds an erasure job, its subject is encrypted like stored identities
+func (c *VCErasureJobColl) Add(ctx context.Context, job *model.ErasureJob) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:erasure_job:add")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	stored := *job
+	subject, err := c.Service.encryptIdentity(job.Subject)
+	if err != nil {
+		return err
+	}
+	stored.Subject = subject
+
+	_, err = coll.InsertOne(ctx, &stored)
+	return err
+}
+
+// Claim marks the oldest pending job, or a running job without progress since staleBefore, that is not in exclude as
+// running and returns it with its subject decrypted. It returns nil if there is no such job.
+func (c *VCErasureJobColl) Claim(ctx context.Context, staleBefore int64, exclude []string) (*model.ErasureJob, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:erasure_job:claim")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"status": bson.M{"$eq": model.ErasureJobPending}},
+			bson.M{"status": bson.M{"$eq": model.ErasureJobRunning}, "updated_at": bson.M{"$lt": staleBefore}},
+		},
+		"job_id": bson.M{"$nin": exclude},
+	}
+	update := bson.M{
+		"$set": bson.M{"status": model.ErasureJobRunning, "updated_at": time.Now().Unix()},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "created_at", Value: 1}}).
+		SetProjection(bson.M{"_id": 0}).
+		SetReturnDocument(options.After)
+
+	job := &model.ErasureJob{}
+	if err := coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(job); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if err := c.Service.decryptIdentity(job.Subject); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Get returns an erasure job of authenticSource, ErrNoDocumentFound if there is no such job
+func (c *VCErasureJobColl) Get(ctx context.Context, authenticSource, jobID string) (*model.ErasureJob, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:erasure_job:get")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"authentic_source": bson.M{"$eq": authenticSource},
+		"job_id":           bson.M{"$eq": jobID},
+	}
+
+	res := &model.ErasureJob{}
+	if err := coll.FindOne(ctx, filter, options.FindOne().SetProjection(bson.M{"_id": 0, "subject": 0})).Decode(res); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, helpers.ErrNoDocumentFound
+		}
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Update stores the status, counts and error of an erasure job, the subject is removed once the job has ended
+func (c *VCErasureJobColl) Update(ctx context.Context, job *model.ErasureJob) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:erasure_job:update")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	job.UpdatedAt = time.Now().Unix()
+	update := bson.M{
+		"$set": bson.M{
+			"status":       job.Status,
+			"erased":       job.Erased,
+			"error":        job.Error,
+			"completed_at": job.CompletedAt,
+			"updated_at":   job.UpdatedAt,
+		},
+	}
+	if job.Status == model.ErasureJobCompleted || job.Status == model.ErasureJobFailed {
+		update["$unset"] = bson.M{"subject": ""}
+	}
+
+	res, err := coll.UpdateOne(ctx, bson.M{"job_id": bson.M{"$eq": job.JobID}}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return helpers.ErrNoDocumentFound
+	}
+
+	return nil
+}
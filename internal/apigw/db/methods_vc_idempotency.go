@@ -0,0 +1,123 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"vc/pkg/helpers"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// VCIdempotencyColl is the collection of responses to requests with an Idempotency-Key header
+type VCIdempotencyColl struct {
+	Service *Service
+	tenantCollection
+	log *logger.Log
+}
+
+func (c *VCIdempotencyColl) createIndex(ctx context.Context) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:idempotency:createIndex")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	indexKeyUniq := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "client", Value: 1},
+			{Key: "route", Value: 1},
+			{Key: "key", Value: 1},
+		},
+		Options: options.Index().SetName("client_route_key_uniq").SetUnique(true),
+	}
+
+	indexTTL := mongo.IndexModel{
+		Keys:    bson.D{{Key: "created_at", Value: 1}},
+		Options: options.Index().SetName("created_at_ttl").SetExpireAfterSeconds(int32(c.Service.cfg.APIGW.Idempotency.TTL)),
+	}
+
+	_, err = coll.Indexes().CreateMany(ctx, []mongo.IndexModel{indexKeyUniq, indexTTL})
+	return err
+}
+
+func idempotencyFilter(record *model.IdempotencyRecord) bson.M {
+	return bson.M{
+		"client": bson.M{"$eq": record.Client},
+		"route":  bson.M{"$eq": record.Route},
+		"key":    bson.M{"$eq": record.Key},
+	}
+}
+
+// Reserve stores record, pending, if its key is not already stored. The stored record is returned if it is, or nil if
+// record was stored.
+func (c *VCIdempotencyColl) Reserve(ctx context.Context, record *model.IdempotencyRecord) (*model.IdempotencyRecord, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:idempotency:reserve")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = coll.InsertOne(ctx, record)
+	if err == nil {
+		return nil, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return nil, err
+	}
+
+	stored := &model.IdempotencyRecord{}
+	if err := coll.FindOne(ctx, idempotencyFilter(record), options.FindOne().SetProjection(bson.M{"_id": 0})).Decode(stored); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			// expired in between, the client may retry
+			return nil, helpers.ErrIdempotencyKeyInProgress
+		}
+		return nil, err
+	}
+
+	return stored, nil
+}
+
+// Complete stores the response of a reserved record
+func (c *VCIdempotencyColl) Complete(ctx context.Context, record *model.IdempotencyRecord) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:idempotency:complete")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":      model.IdempotencyCompleted,
+			"status_code": record.StatusCode,
+			"headers":     record.Headers,
+			"body":        record.Body,
+		},
+	}
+
+	_, err = coll.UpdateOne(ctx, idempotencyFilter(record), update)
+	return err
+}
+
+// Release deletes a reserved record, the key can then be used again
+func (c *VCIdempotencyColl) Release(ctx context.Context, record *model.IdempotencyRecord) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:idempotency:release")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = coll.DeleteOne(ctx, idempotencyFilter(record))
+	return err
+}
@@ -0,0 +1,267 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"vc/pkg/helpers"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// VCIdentityReviewColl is the manual review queue of collect requests whose identity matched only loosely
+type VCIdentityReviewColl struct {
+	Service *Service
+	tenantCollection
+	log *logger.Log
+}
+
+func (c *VCIdentityReviewColl) createIndex(ctx context.Context) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:identity_review:createIndex")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	indexReviewIDUniq := mongo.IndexModel{
+		Keys:    bson.D{{Key: "review_id", Value: 1}},
+		Options: options.Index().SetName("review_id_uniq").SetUnique(true),
+	}
+	indexRequestUniq := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "authentic_source", Value: 1},
+			{Key: "document_type", Value: 1},
+			{Key: "document_id", Value: 1},
+			{Key: "collect_id", Value: 1},
+			{Key: "request_hash", Value: 1},
+		},
+		Options: options.Index().SetName("request_uniq").SetUnique(true),
+	}
+	indexStatus := mongo.IndexModel{
+		Keys:    bson.D{{Key: "authentic_source", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: 1}},
+		Options: options.Index().SetName("authentic_source_status_created_at"),
+	}
+
+	_, err = coll.Indexes().CreateMany(ctx, []mongo.IndexModel{indexReviewIDUniq, indexRequestUniq, indexStatus})
+	return err
+}
+
+// Add adds a review, ErrDuplicateKey if the collect request already has one. The identity of the review is stored
+// encrypted if field encryption is enabled.
+func (c *VCIdentityReviewColl) Add(ctx context.Context, review *model.IdentityReview) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:identity_review:add")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	stored := *review
+	stored.Identity, err = c.Service.encryptIdentity(review.Identity)
+	if err != nil {
+		return err
+	}
+
+	if _, err := coll.InsertOne(ctx, &stored); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return helpers.ErrDuplicateKey
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetByRequest returns the review of the collect request of the document with requestHash, ErrNoDocumentFound if it
+// has none
+func (c *VCIdentityReviewColl) GetByRequest(ctx context.Context, meta *model.MetaData, requestHash string) (*model.IdentityReview, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:identity_review:getByRequest")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := documentFilter(meta)
+	filter["collect_id"] = bson.M{"$eq": meta.Collect.ID}
+	filter["request_hash"] = bson.M{"$eq": requestHash}
+
+	res := &model.IdentityReview{}
+	if err := coll.FindOne(ctx, filter, options.FindOne().SetProjection(bson.M{"_id": 0})).Decode(res); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, helpers.ErrNoDocumentFound
+		}
+		return nil, err
+	}
+
+	if err := c.Service.decryptIdentity(res.Identity); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// List returns at most limit reviews of authenticSource with status, oldest first
+func (c *VCIdentityReviewColl) List(ctx context.Context, authenticSource, status string, limit int64) ([]*model.IdentityReview, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:identity_review:list")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"authentic_source": bson.M{"$eq": authenticSource},
+		"status":           bson.M{"$eq": status},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}).SetLimit(limit).SetProjection(bson.M{"_id": 0})
+
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*model.IdentityReview{}
+	if err := cursor.All(ctx, &res); err != nil {
+		return nil, err
+	}
+
+	for _, review := range res {
+		if err := c.Service.decryptIdentity(review.Identity); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// Decide sets the status, reviewer and comment of a pending review and returns it. ErrNoDocumentFound is returned if
+// authenticSource has no review reviewID, ErrIdentityReviewDecided if it is already decided.
+func (c *VCIdentityReviewColl) Decide(ctx context.Context, authenticSource, reviewID string, decision *model.IdentityReview) (*model.IdentityReview, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:identity_review:decide")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"authentic_source": bson.M{"$eq": authenticSource},
+		"review_id":        bson.M{"$eq": reviewID},
+	}
+	pending := bson.M{"status": bson.M{"$eq": model.IdentityReviewPending}}
+	for k, v := range filter {
+		pending[k] = v
+	}
+	update := bson.M{"$set": bson.M{
+		"status":     decision.Status,
+		"reviewer":   decision.Reviewer,
+		"comment":    decision.Comment,
+		"decided_at": decision.DecidedAt,
+	}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After).SetProjection(bson.M{"_id": 0})
+
+	res := &model.IdentityReview{}
+	err = coll.FindOneAndUpdate(ctx, pending, update, opts).Decode(res)
+	if err == nil {
+		if err := c.Service.decryptIdentity(res.Identity); err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+
+	n, err := coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, helpers.ErrNoDocumentFound
+	}
+	return nil, helpers.ErrIdentityReviewDecided
+}
+
+// DeleteByDocument deletes the reviews of a document
+func (c *VCIdentityReviewColl) DeleteByDocument(ctx context.Context, meta *model.MetaData) (int64, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:identity_review:deleteByDocument")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := coll.DeleteMany(ctx, documentFilter(meta))
+	if err != nil {
+		return 0, err
+	}
+
+	return res.DeletedCount, nil
+}
+
+// RotateIdentityKeys rewraps at most limit identity keys of the reviews of authenticSource that are wrapped by a
+// previous key encryption key, and returns the number of rewrapped reviews
+func (c *VCIdentityReviewColl) RotateIdentityKeys(ctx context.Context, authenticSource string, limit int64) (int, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:identity_review:rotateIdentityKeys")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if c.Service.encryptor == nil {
+		return 0, nil
+	}
+
+	filter := bson.M{
+		"authentic_source":               bson.M{"$eq": authenticSource},
+		"identity.encryption_key":        bson.M{"$ne": nil},
+		"identity.encryption_key.key_id": bson.M{"$ne": c.Service.encryptor.KeyID()},
+	}
+	opts := options.Find().SetLimit(limit).SetProjection(bson.M{"review_id": 1, "identity.encryption_key": 1})
+
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	res := []*model.IdentityReview{}
+	if err := cursor.All(ctx, &res); err != nil {
+		return 0, err
+	}
+
+	rewrapped := 0
+	for _, review := range res {
+		key, _, err := c.Service.encryptor.RewrapKey(review.Identity.EncryptionKey)
+		if err != nil {
+			return rewrapped, err
+		}
+
+		reviewFilter := bson.M{
+			"review_id":                      bson.M{"$eq": review.ReviewID},
+			"identity.encryption_key.key_id": bson.M{"$eq": review.Identity.EncryptionKey.KeyID},
+		}
+
+		result, err := coll.UpdateOne(ctx, reviewFilter, bson.M{"$set": bson.M{"identity.encryption_key": key}})
+		if err != nil {
+			return rewrapped, err
+		}
+		if result.ModifiedCount > 0 {
+			rewrapped++
+		}
+	}
+
+	return rewrapped, nil
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"time"
+	"vc/pkg/helpers"
+	"vc/pkg/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// VCUploadNonceColl is the collection of the nonces of signed uploads, kept until the upload signature expires
+type VCUploadNonceColl struct {
+	Service *Service
+	tenantCollection
+	log *logger.Log
+}
+
+type uploadNonce struct {
+	AuthenticSource string    `bson:"authentic_source"`
+	Nonce           string    `bson:"nonce"`
+	ExpiresAt       time.Time `bson:"expires_at"`
+}
+
+func (c *VCUploadNonceColl) createIndex(ctx context.Context) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:upload_nonce:createIndex")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	indexNonceUniq := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "authentic_source", Value: 1},
+			{Key: "nonce", Value: 1},
+		},
+		Options: options.Index().SetName("authentic_source_nonce_uniq").SetUnique(true),
+	}
+
+	indexTTL := mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetName("expires_at_ttl").SetExpireAfterSeconds(0),
+	}
+
+	_, err = coll.Indexes().CreateMany(ctx, []mongo.IndexModel{indexNonceUniq, indexTTL})
+	return err
+}
+
+// Use stores the nonce of a signed upload until expiresAt, ErrUploadReplayed is returned if it is already stored
+func (c *VCUploadNonceColl) Use(ctx context.Context, authenticSource, nonce string, expiresAt time.Time) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:upload_nonce:use")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = coll.InsertOne(ctx, &uploadNonce{
+		AuthenticSource: authenticSource,
+		Nonce:           nonce,
+		ExpiresAt:       expiresAt,
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return helpers.ErrUploadReplayed
+	}
+	return err
+}
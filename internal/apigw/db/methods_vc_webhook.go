@@ -0,0 +1,291 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"vc/pkg/helpers"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// VCWebhookColl is the webhook subscription collection
+type VCWebhookColl struct {
+	Service *Service
+	tenantCollection
+	log *logger.Log
+}
+
+func (c *VCWebhookColl) createIndex(ctx context.Context) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:webhook:createIndex")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	indexIDUniq := mongo.IndexModel{
+		Keys:    bson.D{{Key: "id", Value: 1}},
+		Options: options.Index().SetName("id_uniq").SetUnique(true),
+	}
+	indexAuthenticSource := mongo.IndexModel{
+		Keys:    bson.D{{Key: "authentic_source", Value: 1}},
+		Options: options.Index().SetName("authentic_source"),
+	}
+
+	_, err = coll.Indexes().CreateMany(ctx, []mongo.IndexModel{indexIDUniq, indexAuthenticSource})
+	return err
+}
+
+// Add adds a webhook subscription
+func (c *VCWebhookColl) Add(ctx context.Context, subscription *model.WebhookSubscription) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:webhook:add")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, err := coll.InsertOne(ctx, subscription); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return helpers.ErrDuplicateKey
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Delete deletes a webhook subscription of authenticSource, ErrNoDocumentFound if there is no such subscription
+func (c *VCWebhookColl) Delete(ctx context.Context, authenticSource, id string) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:webhook:delete")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{
+		"authentic_source": bson.M{"$eq": authenticSource},
+		"id":               bson.M{"$eq": id},
+	}
+
+	res, err := coll.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return helpers.ErrNoDocumentFound
+	}
+
+	return nil
+}
+
+// List returns the webhook subscriptions of authenticSource, oldest first
+func (c *VCWebhookColl) List(ctx context.Context, authenticSource string) ([]*model.WebhookSubscription, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:webhook:list")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"authentic_source": bson.M{"$eq": authenticSource}}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}).SetProjection(bson.M{"_id": 0})
+
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*model.WebhookSubscription{}
+	if err := cursor.All(ctx, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Matching returns the webhook subscriptions of authenticSource that are called on event
+func (c *VCWebhookColl) Matching(ctx context.Context, authenticSource, event string) ([]*model.WebhookSubscription, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:webhook:matching")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"authentic_source": bson.M{"$eq": authenticSource},
+		"$or": bson.A{
+			bson.M{"events": bson.M{"$size": 0}},
+			bson.M{"events": bson.M{"$eq": event}},
+		},
+	}
+
+	cursor, err := coll.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 0}))
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*model.WebhookSubscription{}
+	if err := cursor.All(ctx, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Get returns a webhook subscription, ErrNoDocumentFound if there is no such subscription
+func (c *VCWebhookColl) Get(ctx context.Context, id string) (*model.WebhookSubscription, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:webhook:get")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &model.WebhookSubscription{}
+	if err := coll.FindOne(ctx, bson.M{"id": bson.M{"$eq": id}}).Decode(res); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, helpers.ErrNoDocumentFound
+		}
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// VCWebhookDeliveryColl is the webhook delivery log collection
+type VCWebhookDeliveryColl struct {
+	Service *Service
+	tenantCollection
+	log *logger.Log
+}
+
+func (c *VCWebhookDeliveryColl) createIndex(ctx context.Context) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:webhook_delivery:createIndex")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	indexIDUniq := mongo.IndexModel{
+		Keys:    bson.D{{Key: "event.id", Value: 1}},
+		Options: options.Index().SetName("event_id_uniq").SetUnique(true),
+	}
+	indexDue := mongo.IndexModel{
+		Keys:    bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+		Options: options.Index().SetName("status_next_attempt_at"),
+	}
+	indexSubscription := mongo.IndexModel{
+		Keys:    bson.D{{Key: "subscription_id", Value: 1}, {Key: "event.timestamp", Value: -1}},
+		Options: options.Index().SetName("subscription_id_timestamp"),
+	}
+
+	_, err = coll.Indexes().CreateMany(ctx, []mongo.IndexModel{indexIDUniq, indexDue, indexSubscription})
+	return err
+}
+
+// Add adds a webhook delivery
+func (c *VCWebhookDeliveryColl) Add(ctx context.Context, delivery *model.WebhookDelivery) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:webhook_delivery:add")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = coll.InsertOne(ctx, delivery)
+	return err
+}
+
+// Due returns at most limit pending deliveries whose next attempt is at or before now
+func (c *VCWebhookDeliveryColl) Due(ctx context.Context, now, limit int64) ([]*model.WebhookDelivery, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:webhook_delivery:due")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"status":          bson.M{"$eq": model.WebhookDeliveryPending},
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).SetLimit(limit).SetProjection(bson.M{"_id": 0})
+
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*model.WebhookDelivery{}
+	if err := cursor.All(ctx, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Update replaces the delivery state of a webhook delivery
+func (c *VCWebhookDeliveryColl) Update(ctx context.Context, delivery *model.WebhookDelivery) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:webhook_delivery:update")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"event.id": bson.M{"$eq": delivery.Event.ID}}
+	update := bson.M{"$set": bson.M{
+		"status":          delivery.Status,
+		"attempts":        delivery.Attempts,
+		"next_attempt_at": delivery.NextAttemptAt,
+		"last_error":      delivery.LastError,
+		"delivered_at":    delivery.DeliveredAt,
+	}}
+
+	_, err = coll.UpdateOne(ctx, filter, update)
+	return err
+}
+
+// List returns at most limit deliveries of a subscription, newest first
+func (c *VCWebhookDeliveryColl) List(ctx context.Context, subscriptionID string, limit int64) ([]*model.WebhookDelivery, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:webhook_delivery:list")
+	defer span.End()
+
+	coll, err := c.coll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"subscription_id": bson.M{"$eq": subscriptionID}}
+	opts := options.Find().SetSort(bson.D{{Key: "event.timestamp", Value: -1}}).SetLimit(limit).SetProjection(bson.M{"_id": 0})
+
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*model.WebhookDelivery{}
+	if err := cursor.All(ctx, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
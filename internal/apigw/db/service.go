@@ -6,8 +6,11 @@ import (
 	"time"
 
 	"vc/internal/gen/status/apiv1_status"
+	"vc/pkg/fieldcrypt"
 	"vc/pkg/logger"
 	"vc/pkg/model"
+	"vc/pkg/mongotx"
+	"vc/pkg/tenant"
 	"vc/pkg/trace"
 
 	"go.mongodb.org/mongo-driver/mongo"
@@ -29,14 +32,29 @@ type DB interface {
 
 // Service is the database service
 type Service struct {
-	dbClient   *mongo.Client
-	cfg        *model.Cfg
-	log        *logger.Log
-	tracer     *trace.Tracer
-	probeStore *apiv1_status.StatusProbeStore
-
-	VCDatastoreColl *VCDatastoreColl
-	VCConsentColl   *VCConsentColl
+	dbClient     *mongo.Client
+	transactions *mongotx.Runner
+	cfg          *model.Cfg
+	log          *logger.Log
+	tracer       *trace.Tracer
+	probeStore   *apiv1_status.StatusProbeStore
+	encryptor    *fieldcrypt.Encryptor
+	tenants      *tenant.Tenants
+
+	VCDatastoreColl       *VCDatastoreColl
+	VCConsentColl         *VCConsentColl
+	VCDocumentConsentColl *VCDocumentConsentColl
+	VCConsentAuditColl    *VCConsentAuditColl
+
+	VCCredentialNotificationColl *VCCredentialNotificationColl
+	VCDeferredCredentialColl     *VCDeferredCredentialColl
+	VCWebhookColl                *VCWebhookColl
+	VCWebhookDeliveryColl        *VCWebhookDeliveryColl
+	VCErasureJobColl             *VCErasureJobColl
+	VCIdempotencyColl            *VCIdempotencyColl
+	VCUploadNonceColl            *VCUploadNonceColl
+	VCDocumentVersionColl        *VCDocumentVersionColl
+	VCIdentityReviewColl         *VCIdentityReviewColl
 }
 
 // New creates a new database service
@@ -48,6 +66,17 @@ func New(ctx context.Context, cfg *model.Cfg, tracer *trace.Tracer, log *logger.
 		probeStore: &apiv1_status.StatusProbeStore{},
 	}
 
+	var err error
+	service.encryptor, err = newEncryptor(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	service.tenants, err = tenant.New(cfg.Common.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
 	defer cancel()
 
@@ -56,20 +85,119 @@ func New(ctx context.Context, cfg *model.Cfg, tracer *trace.Tracer, log *logger.
 	}
 
 	service.VCDatastoreColl = &VCDatastoreColl{
-		Service: service,
-		Coll:    service.dbClient.Database("vc").Collection("datastore"),
-		log:     log.New("VCDatastoreColl"),
+		Service:          service,
+		tenantCollection: service.collections("datastore"),
+		log:              log.New("VCDatastoreColl"),
 	}
-	if err := service.VCDatastoreColl.createIndex(ctx); err != nil {
+	if err := service.forEachTenant(ctx, service.VCDatastoreColl.createIndex); err != nil {
 		return nil, err
 	}
 
 	service.VCConsentColl = &VCConsentColl{
-		Service: service,
-		Coll:    service.dbClient.Database("vc").Collection("consent"),
-		log:     log.New("VCConsentColl"),
+		Service:          service,
+		tenantCollection: service.collections("consent"),
+		log:              log.New("VCConsentColl"),
+	}
+	if err := service.forEachTenant(ctx, service.VCConsentColl.createIndex); err != nil {
+		return nil, err
+	}
+
+	service.VCDocumentConsentColl = &VCDocumentConsentColl{
+		Service:          service,
+		tenantCollection: service.collections("document_consent"),
+		log:              log.New("VCDocumentConsentColl"),
+	}
+	if err := service.forEachTenant(ctx, service.VCDocumentConsentColl.createIndex); err != nil {
+		return nil, err
+	}
+
+	service.VCConsentAuditColl = &VCConsentAuditColl{
+		Service:          service,
+		tenantCollection: service.collections("consent_audit"),
+		log:              log.New("VCConsentAuditColl"),
 	}
-	if err := service.VCConsentColl.createIndex(ctx); err != nil {
+	if err := service.forEachTenant(ctx, service.VCConsentAuditColl.createIndex); err != nil {
+		return nil, err
+	}
+
+	service.VCCredentialNotificationColl = &VCCredentialNotificationColl{
+		Service:          service,
+		tenantCollection: service.collections("credential_notification"),
+		log:              log.New("VCCredentialNotificationColl"),
+	}
+	if err := service.forEachTenant(ctx, service.VCCredentialNotificationColl.createIndex); err != nil {
+		return nil, err
+	}
+
+	service.VCDeferredCredentialColl = &VCDeferredCredentialColl{
+		Service:          service,
+		tenantCollection: service.collections("deferred_credential"),
+		log:              log.New("VCDeferredCredentialColl"),
+	}
+	if err := service.forEachTenant(ctx, service.VCDeferredCredentialColl.createIndex); err != nil {
+		return nil, err
+	}
+
+	service.VCWebhookColl = &VCWebhookColl{
+		Service:          service,
+		tenantCollection: service.collections("webhook"),
+		log:              log.New("VCWebhookColl"),
+	}
+	if err := service.forEachTenant(ctx, service.VCWebhookColl.createIndex); err != nil {
+		return nil, err
+	}
+
+	service.VCWebhookDeliveryColl = &VCWebhookDeliveryColl{
+		Service:          service,
+		tenantCollection: service.collections("webhook_delivery"),
+		log:              log.New("VCWebhookDeliveryColl"),
+	}
+	if err := service.forEachTenant(ctx, service.VCWebhookDeliveryColl.createIndex); err != nil {
+		return nil, err
+	}
+
+	service.VCErasureJobColl = &VCErasureJobColl{
+		Service:          service,
+		tenantCollection: service.collections("erasure_job"),
+		log:              log.New("VCErasureJobColl"),
+	}
+	if err := service.forEachTenant(ctx, service.VCErasureJobColl.createIndex); err != nil {
+		return nil, err
+	}
+
+	service.VCIdempotencyColl = &VCIdempotencyColl{
+		Service:          service,
+		tenantCollection: service.collections("idempotency"),
+		log:              log.New("VCIdempotencyColl"),
+	}
+	if err := service.forEachTenant(ctx, service.VCIdempotencyColl.createIndex); err != nil {
+		return nil, err
+	}
+
+	service.VCUploadNonceColl = &VCUploadNonceColl{
+		Service:          service,
+		tenantCollection: service.collections("upload_nonce"),
+		log:              log.New("VCUploadNonceColl"),
+	}
+	if err := service.forEachTenant(ctx, service.VCUploadNonceColl.createIndex); err != nil {
+		return nil, err
+	}
+
+	service.VCDocumentVersionColl = &VCDocumentVersionColl{
+		Service:          service,
+		tenantCollection: service.collections("document_version"),
+		log:              log.New("VCDocumentVersionColl"),
+	}
+	if err := service.forEachTenant(ctx, service.VCDocumentVersionColl.createIndex); err != nil {
+		return nil, err
+	}
+
+	service.VCIdentityReviewColl = &VCIdentityReviewColl{
+		Service:          service,
+		tenantCollection: service.collections("identity_review"),
+		log:              log.New("VCIdentityReviewColl"),
+	}
+	if err := service.forEachTenant(ctx, service.VCIdentityReviewColl.createIndex); err != nil {
 		return nil, err
 	}
 
@@ -78,17 +206,58 @@ func New(ctx context.Context, cfg *model.Cfg, tracer *trace.Tracer, log *logger.
 	return service, nil
 }
 
+// NewForTesting returns a database service of client, without indexes, field encryption or transactions, for
+// testing with a mock client
+func NewForTesting(client *mongo.Client, cfg *model.Cfg, tracer *trace.Tracer, log *logger.Log) (*Service, error) {
+	tenants, err := tenant.New(cfg.Common.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Service{
+		dbClient:     client,
+		transactions: &mongotx.Runner{},
+		cfg:          cfg,
+		log:          log.New("db"),
+		tracer:       tracer,
+		probeStore:   &apiv1_status.StatusProbeStore{},
+		tenants:      tenants,
+	}
+
+	s.VCDatastoreColl = &VCDatastoreColl{Service: s, tenantCollection: s.collections("datastore"), log: log.New("VCDatastoreColl")}
+	s.VCConsentColl = &VCConsentColl{Service: s, tenantCollection: s.collections("consent"), log: log.New("VCConsentColl")}
+	s.VCDocumentConsentColl = &VCDocumentConsentColl{Service: s, tenantCollection: s.collections("document_consent"), log: log.New("VCDocumentConsentColl")}
+	s.VCConsentAuditColl = &VCConsentAuditColl{Service: s, tenantCollection: s.collections("consent_audit"), log: log.New("VCConsentAuditColl")}
+	s.VCCredentialNotificationColl = &VCCredentialNotificationColl{Service: s, tenantCollection: s.collections("credential_notification"), log: log.New("VCCredentialNotificationColl")}
+	s.VCDeferredCredentialColl = &VCDeferredCredentialColl{Service: s, tenantCollection: s.collections("deferred_credential"), log: log.New("VCDeferredCredentialColl")}
+	s.VCWebhookColl = &VCWebhookColl{Service: s, tenantCollection: s.collections("webhook"), log: log.New("VCWebhookColl")}
+	s.VCWebhookDeliveryColl = &VCWebhookDeliveryColl{Service: s, tenantCollection: s.collections("webhook_delivery"), log: log.New("VCWebhookDeliveryColl")}
+	s.VCErasureJobColl = &VCErasureJobColl{Service: s, tenantCollection: s.collections("erasure_job"), log: log.New("VCErasureJobColl")}
+	s.VCIdempotencyColl = &VCIdempotencyColl{Service: s, tenantCollection: s.collections("idempotency"), log: log.New("VCIdempotencyColl")}
+	s.VCUploadNonceColl = &VCUploadNonceColl{Service: s, tenantCollection: s.collections("upload_nonce"), log: log.New("VCUploadNonceColl")}
+	s.VCDocumentVersionColl = &VCDocumentVersionColl{Service: s, tenantCollection: s.collections("document_version"), log: log.New("VCDocumentVersionColl")}
+	s.VCIdentityReviewColl = &VCIdentityReviewColl{Service: s, tenantCollection: s.collections("identity_review"), log: log.New("VCIdentityReviewColl")}
+
+	return s, nil
+}
+
 // connect connects to the database
 func (s *Service) connect(ctx context.Context) error {
 	ctx, span := s.tracer.Start(ctx, "apigw:db:connect")
 	defer span.End()
 
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(s.cfg.Common.Mongo.URI))
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(s.cfg.Common.Mongo.URI).SetMonitor(trace.NewMongoMonitor(nil)))
 	if err != nil {
 		return err
 	}
 	s.dbClient = client
 
+	s.transactions, err = mongotx.New(ctx, client, s.cfg.Common.Mongo.Transactions)
+	if err != nil {
+		return err
+	}
+	s.log.Info("Transactions", "enabled", s.transactions.Enabled())
+
 	return nil
 }
 
@@ -118,6 +287,11 @@ func (s *Service) Status(ctx context.Context) *apiv1_status.StatusProbe {
 	return probe
 }
 
+// Ping checks that the database is reachable
+func (s *Service) Ping(ctx context.Context) error {
+	return s.dbClient.Ping(ctx, nil)
+}
+
 // Close closes the database connection
 func (s *Service) Close(ctx context.Context) error {
 	if err := s.dbClient.Disconnect(ctx); err != nil {
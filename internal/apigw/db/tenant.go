@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"vc/pkg/tenant"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// database is the mongo database of the default tenant, other tenants have their own database named after it
+const database = "vc"
+
+// tenantCollection is a collection with a copy in the database of every tenant
+type tenantCollection map[string]*mongo.Collection
+
+// coll returns the collection of the tenant of ctx, ErrUnknownTenant if the tenant is not configured
+func (t tenantCollection) coll(ctx context.Context) (*mongo.Collection, error) {
+	name := tenant.FromContext(ctx)
+	coll, ok := t[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", tenant.ErrUnknownTenant, name)
+	}
+	return coll, nil
+}
+
+// collections returns the collection name in the database of every tenant
+func (s *Service) collections(name string) tenantCollection {
+	colls := tenantCollection{}
+	for _, t := range s.tenants.Names() {
+		colls[t] = s.dbClient.Database(tenant.Database(database, t)).Collection(name)
+	}
+	return colls
+}
+
+// forEachTenant runs fn with the context of every tenant
+func (s *Service) forEachTenant(ctx context.Context, fn func(ctx context.Context) error) error {
+	for _, t := range s.tenants.Names() {
+		if err := fn(tenant.NewContext(ctx, t)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"vc/pkg/model"
+	"vc/pkg/tenant"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestTenantCollectionUnknownTenant(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("unknown tenant", func(mt *mtest.T) {
+		s := mockService(t, mt)
+
+		ctx := tenant.NewContext(context.Background(), "unknown")
+		err := s.VCCredentialNotificationColl.Add(ctx, &model.CredentialNotification{NotificationID: "notification-1"})
+		assert.ErrorIs(t, err, tenant.ErrUnknownTenant)
+		assert.Empty(t, mt.GetAllStartedEvents())
+	})
+}
@@ -0,0 +1,22 @@
+package db
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+// WithTransaction runs fn in a transaction, every write made with the ctx passed to fn either fully commits or rolls back.
+// fn runs once, side effects belong after WithTransaction returns. If the deployment has no transactions, e.g. a
+// standalone MongoDB, fn is run without a transaction.
+func (s *Service) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	ctx, span := s.tracer.Start(ctx, "db:transaction")
+	defer span.End()
+
+	if err := s.transactions.Run(ctx, fn); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package deferred
+
+import (
+	"context"
+	"sync"
+	"time"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+	"vc/pkg/tenant"
+)
+
+// Processor retries the pending deferred credentials
+type Processor interface {
+	ProcessDeferred(ctx context.Context) (int, error)
+}
+
+// Service periodically retries document collection for deferred credentials
+type Service struct {
+	cfg       *model.Cfg
+	log       *logger.Log
+	processor Processor
+	tenants   *tenant.Tenants
+	wg        *sync.WaitGroup
+	quitChan  chan struct{}
+	ticker    *time.Ticker
+}
+
+// New creates a new deferred issuance service
+func New(ctx context.Context, wg *sync.WaitGroup, processor Processor, cfg *model.Cfg, log *logger.Log) (*Service, error) {
+	s := &Service{
+		cfg:       cfg,
+		log:       log.New("deferred"),
+		processor: processor,
+		wg:        wg,
+		quitChan:  make(chan struct{}),
+		ticker:    time.NewTicker(time.Duration(cfg.APIGW.Deferred.Interval) * time.Second),
+	}
+
+	var err error
+	s.tenants, err = tenant.New(cfg.Common.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	s.wg.Add(1)
+	go func() {
+		for {
+			select {
+			case <-s.ticker.C:
+				for _, name := range s.tenants.Names() {
+					issued, err := s.processor.ProcessDeferred(tenant.NewContext(ctx, name))
+					if err != nil {
+						s.log.Error(err, "process deferred credentials failed", "tenant", name)
+						continue
+					}
+					if issued > 0 {
+						s.log.Info("deferred credentials issued", "issued", issued, "tenant", name)
+					}
+				}
+			case <-s.quitChan:
+				s.log.Info("Stop processing")
+				s.ticker.Stop()
+				s.wg.Done()
+				return
+			}
+		}
+	}()
+
+	s.log.Info("Started")
+
+	return s, nil
+}
+
+// Close closes the deferred issuance service
+func (s *Service) Close(ctx context.Context) error {
+	s.quitChan <- struct{}{}
+
+	s.log.Info("Stopped")
+	return nil
+}
@@ -0,0 +1,80 @@
+package erasure
+
+import (
+	"context"
+	"sync"
+	"time"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+	"vc/pkg/tenant"
+)
+
+// Processor runs the pending data subject erasure jobs
+type Processor interface {
+	ProcessErasureJobs(ctx context.Context) (int, error)
+}
+
+// Service periodically runs the pending data subject erasure jobs of every tenant
+type Service struct {
+	cfg       *model.Cfg
+	log       *logger.Log
+	processor Processor
+	tenants   *tenant.Tenants
+	wg        *sync.WaitGroup
+	quitChan  chan struct{}
+	ticker    *time.Ticker
+}
+
+// New creates a new erasure job service
+func New(ctx context.Context, wg *sync.WaitGroup, processor Processor, cfg *model.Cfg, log *logger.Log) (*Service, error) {
+	s := &Service{
+		cfg:       cfg,
+		log:       log.New("erasure"),
+		processor: processor,
+		wg:        wg,
+		quitChan:  make(chan struct{}),
+		ticker:    time.NewTicker(time.Duration(cfg.APIGW.Erasure.Interval) * time.Second),
+	}
+
+	var err error
+	s.tenants, err = tenant.New(cfg.Common.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	s.wg.Add(1)
+	go func() {
+		for {
+			select {
+			case <-s.ticker.C:
+				for _, name := range s.tenants.Names() {
+					completed, err := s.processor.ProcessErasureJobs(tenant.NewContext(ctx, name))
+					if err != nil {
+						s.log.Error(err, "process erasure jobs failed", "tenant", name)
+						continue
+					}
+					if completed > 0 {
+						s.log.Info("erasure jobs completed", "completed", completed, "tenant", name)
+					}
+				}
+			case <-s.quitChan:
+				s.log.Info("Stop processing")
+				s.ticker.Stop()
+				s.wg.Done()
+				return
+			}
+		}
+	}()
+
+	s.log.Info("Started")
+
+	return s, nil
+}
+
+// Close closes the erasure job service, a running job is finished first
+func (s *Service) Close(ctx context.Context) error {
+	s.quitChan <- struct{}{}
+
+	s.log.Info("Stopped")
+	return nil
+}
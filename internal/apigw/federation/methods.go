@@ -0,0 +1,89 @@
+package federation
+
+import (
+	"context"
+	"strings"
+	"time"
+	"vc/pkg/openidfederation"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// metadata returns the entity metadata, the credential issuer endpoints are served by the apigw
+func (s *Service) metadata() map[string]any {
+	entityID := strings.TrimSuffix(s.cfg.APIGW.TrustModel.EntityID, "/")
+
+	federationEntity := map[string]any{}
+	if s.cfg.APIGW.TrustModel.OrganizationName != "" {
+		federationEntity["organization_name"] = s.cfg.APIGW.TrustModel.OrganizationName
+	}
+
+	return map[string]any{
+		"federation_entity": federationEntity,
+		"openid_credential_issuer": map[string]any{
+			"credential_issuer":   entityID,
+			"credential_endpoint": entityID + "/api/v1/credential",
+			"jwks_uri":            entityID + "/api/v1/credential/.well-known/jwks",
+		},
+	}
+}
+
+// cached returns the statements of c, or builds them again once half of their lifetime has passed
+func (s *Service) cached(c **cachedStatements, build func() ([]string, error)) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if *c != nil && time.Now().Before((*c).expiresAt) {
+		return (*c).statements, nil
+	}
+
+	statements, err := build()
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := time.Duration(s.cfg.APIGW.TrustModel.TTL) * time.Second
+	*c = &cachedStatements{statements: statements, expiresAt: time.Now().Add(ttl / 2)}
+
+	return statements, nil
+}
+
+// EntityConfiguration returns the signed entity configuration
+func (s *Service) EntityConfiguration(ctx context.Context) (string, error) {
+	statements, err := s.cached(&s.entityConfiguration, func() ([]string, error) {
+		statement, err := openidfederation.NewEntityConfiguration(
+			strings.TrimSuffix(s.cfg.APIGW.TrustModel.EntityID, "/"),
+			s.keys,
+			s.cfg.APIGW.TrustModel.AuthorityHints,
+			s.metadata(),
+			time.Duration(s.cfg.APIGW.TrustModel.TTL)*time.Second,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		signed, err := statement.Sign(jwt.SigningMethodES256, s.signingKey, s.kid)
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{signed}, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return statements[0], nil
+}
+
+// TrustChain returns the trust chain from the entity configuration to a trust anchor
+func (s *Service) TrustChain(ctx context.Context) ([]string, error) {
+	entityConfiguration, err := s.EntityConfiguration(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.cached(&s.trustChain, func() ([]string, error) {
+		return s.resolver.ResolveTrustChain(ctx, entityConfiguration)
+	})
+}
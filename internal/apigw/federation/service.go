@@ -0,0 +1,111 @@
+package federation
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"encoding/base64"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+	"vc/pkg/helpers"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+	"vc/pkg/openidfederation"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lestrrat-go/jwx/jwk"
+)
+
+// Service publishes the OpenID Federation entity configuration of the credential issuer
+type Service struct {
+	cfg        *model.Cfg
+	log        *logger.Log
+	signingKey *ecdsa.PrivateKey
+	kid        string
+	keys       jwk.Set
+	resolver   *openidfederation.Resolver
+
+	mu                  sync.Mutex
+	entityConfiguration *cachedStatements
+	trustChain          *cachedStatements
+}
+
+type cachedStatements struct {
+	statements []string
+	expiresAt  time.Time
+}
+
+// New creates a new federation service
+func New(ctx context.Context, cfg *model.Cfg, log *logger.Log) (*Service, error) {
+	s := &Service{
+		cfg: cfg,
+		log: log.New("federation"),
+	}
+
+	if err := s.loadSigningKey(); err != nil {
+		return nil, err
+	}
+
+	trustAnchors := map[string]jwk.Set{}
+	for _, trustAnchor := range cfg.APIGW.TrustModel.TrustAnchors {
+		keys, err := jwk.ReadFile(trustAnchor.JWKSPath)
+		if err != nil {
+			return nil, err
+		}
+		trustAnchors[trustAnchor.EntityID] = keys
+	}
+	s.resolver = openidfederation.NewResolver(&http.Client{Timeout: 10 * time.Second}, trustAnchors)
+
+	s.log.Info("Started")
+
+	return s, nil
+}
+
+func (s *Service) loadSigningKey() error {
+	keyByte, err := os.ReadFile(s.cfg.APIGW.TrustModel.SigningKeyPath)
+	if err != nil {
+		s.log.Error(err, "Failed to read federation signing key, please create a ECDSA prime256v1 key and save it to the path")
+		return err
+	}
+
+	if keyByte == nil {
+		return helpers.ErrPrivateKeyMissing
+	}
+
+	s.signingKey, err = jwt.ParseECPrivateKeyFromPEM(keyByte)
+	if err != nil {
+		return err
+	}
+
+	publicKey, err := jwk.New(s.signingKey.Public())
+	if err != nil {
+		return err
+	}
+	thumbprint, err := publicKey.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return err
+	}
+	s.kid = base64.RawURLEncoding.EncodeToString(thumbprint)
+	if err := publicKey.Set(jwk.KeyIDKey, s.kid); err != nil {
+		return err
+	}
+	if err := publicKey.Set(jwk.AlgorithmKey, jwt.SigningMethodES256.Alg()); err != nil {
+		return err
+	}
+	if err := publicKey.Set(jwk.KeyUsageKey, "sig"); err != nil {
+		return err
+	}
+
+	s.keys = jwk.NewSet()
+	s.keys.Add(publicKey)
+
+	return nil
+}
+
+// Close closes the federation service
+func (s *Service) Close(ctx context.Context) error {
+	s.log.Info("Stopped")
+	return nil
+}
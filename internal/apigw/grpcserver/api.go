@@ -0,0 +1,17 @@
+package grpcserver
+
+import (
+	"context"
+	"vc/internal/apigw/apiv1"
+	"vc/pkg/httphelpers"
+)
+
+// Apiv1 interface
+type Apiv1 interface {
+	Upload(ctx context.Context, req *apiv1.UploadRequest) error
+	AddDocumentIdentity(ctx context.Context, req *apiv1.AddDocumentIdentityRequest) error
+	GetDocumentCollectID(ctx context.Context, req *apiv1.GetDocumentCollectIDRequest) (*apiv1.GetDocumentCollectIDReply, error)
+	RevokeDocument(ctx context.Context, req *apiv1.RevokeDocumentRequest) error
+
+	httphelpers.IdempotencyStore
+}
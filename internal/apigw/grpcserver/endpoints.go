@@ -0,0 +1,175 @@
+package grpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"vc/internal/apigw/apiv1"
+	"vc/internal/gen/apigw/apiv1_apigw"
+	"vc/pkg/helpers"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+// decodeJSON unmarshals b into v, an empty b leaves v untouched
+func decodeJSON(b []byte, v any) error {
+	if len(b) == 0 {
+		return nil
+	}
+	return json.Unmarshal(b, v)
+}
+
+// Upload uploads a document, published to kafka when it is enabled
+func (s *Service) Upload(ctx context.Context, in *apiv1_apigw.UploadRequest) (*apiv1_apigw.Empty, error) {
+	ctx, span := s.tracer.Start(ctx, "grpcserver:Upload")
+	defer span.End()
+
+	request := &apiv1.UploadRequest{DocumentDataVersion: in.DocumentDataVersion}
+	for _, field := range []struct {
+		b []byte
+		v any
+	}{
+		{in.Meta, &request.Meta},
+		{in.Identities, &request.Identities},
+		{in.DocumentDisplay, &request.DocumentDisplay},
+		{in.DocumentData, &request.DocumentData},
+	} {
+		if err := decodeJSON(field.b, field.v); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+	}
+	if err := helpers.CheckSimple(request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if err := s.authorize(ctx, request.Meta.AuthenticSource); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	// uploads can only be signed over HTTP
+	if s.cfg.APIGW.SignedUpload.Required {
+		span.SetStatus(codes.Error, helpers.ErrUploadSignatureRequired.Error())
+		return nil, helpers.ErrUploadSignatureRequired
+	}
+	request.Meta.SignedBy = nil
+
+	if s.cfg.Common.Kafka.Enabled {
+		if err := s.eventPublisher.Upload(request); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+		return &apiv1_apigw.Empty{}, nil
+	}
+
+	if err := s.apiv1.Upload(ctx, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return &apiv1_apigw.Empty{}, nil
+}
+
+// AddIdentity adds identities to a document
+func (s *Service) AddIdentity(ctx context.Context, in *apiv1_apigw.AddIdentityRequest) (*apiv1_apigw.Empty, error) {
+	ctx, span := s.tracer.Start(ctx, "grpcserver:AddIdentity")
+	defer span.End()
+
+	request := &apiv1.AddDocumentIdentityRequest{
+		AuthenticSource: in.AuthenticSource,
+		DocumentType:    in.DocumentType,
+		DocumentID:      in.DocumentID,
+		Revision:        &in.Revision,
+	}
+	if err := decodeJSON(in.Identities, &request.Identities); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if err := helpers.CheckSimple(request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if err := s.authorize(ctx, request.AuthenticSource); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	if err := s.apiv1.AddDocumentIdentity(ctx, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return &apiv1_apigw.Empty{}, nil
+}
+
+// Collect returns the document matching collect id and identity
+func (s *Service) Collect(ctx context.Context, in *apiv1_apigw.CollectRequest) (*apiv1_apigw.CollectReply, error) {
+	ctx, span := s.tracer.Start(ctx, "grpcserver:Collect")
+	defer span.End()
+
+	request := &apiv1.GetDocumentCollectIDRequest{
+		AuthenticSource: in.AuthenticSource,
+		DocumentType:    in.DocumentType,
+		CollectID:       in.CollectID,
+	}
+	if err := decodeJSON(in.Identity, &request.Identity); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if err := helpers.CheckSimple(request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if err := s.authorize(ctx, request.AuthenticSource); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	reply, err := s.apiv1.GetDocumentCollectID(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	document, err := json.Marshal(reply.Data)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return &apiv1_apigw.CollectReply{
+		Document: document,
+		Revision: reply.Data.Meta.Revision,
+	}, nil
+}
+
+// Revoke revokes a document
+func (s *Service) Revoke(ctx context.Context, in *apiv1_apigw.RevokeRequest) (*apiv1_apigw.Empty, error) {
+	ctx, span := s.tracer.Start(ctx, "grpcserver:Revoke")
+	defer span.End()
+
+	request := &apiv1.RevokeDocumentRequest{
+		AuthenticSource: in.AuthenticSource,
+		DocumentType:    in.DocumentType,
+		Revision:        &in.Revision,
+	}
+	if err := decodeJSON(in.Revocation, &request.Revocation); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if err := helpers.CheckSimple(request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if err := s.authorize(ctx, request.AuthenticSource); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	if err := s.apiv1.RevokeDocument(ctx, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return &apiv1_apigw.Empty{}, nil
+}
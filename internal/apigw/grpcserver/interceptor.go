@@ -0,0 +1,249 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+	"vc/internal/gen/apigw/apiv1_apigw"
+	"vc/pkg/helpers"
+	"vc/pkg/model"
+	"vc/pkg/tenant"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxIdempotencyKeyLength is the longest accepted idempotency-key, same as the Idempotency-Key header of the HTTP API
+const maxIdempotencyKeyLength = 255
+
+// authenticSourceKey is the context key of the authenticated authentic source
+type authenticSourceKey struct{}
+
+// grpcCodes maps the HTTP status of a problem to a gRPC status code
+var grpcCodes = map[int]codes.Code{
+	http.StatusBadRequest:            codes.InvalidArgument,
+	http.StatusUnauthorized:          codes.Unauthenticated,
+	http.StatusForbidden:             codes.PermissionDenied,
+	http.StatusNotFound:              codes.NotFound,
+	http.StatusConflict:              codes.AlreadyExists,
+	http.StatusGone:                  codes.NotFound,
+	http.StatusPreconditionFailed:    codes.FailedPrecondition,
+	http.StatusPreconditionRequired:  codes.FailedPrecondition,
+	http.StatusRequestEntityTooLarge: codes.ResourceExhausted,
+	http.StatusUnprocessableEntity:   codes.InvalidArgument,
+	http.StatusTooManyRequests:       codes.ResourceExhausted,
+	http.StatusServiceUnavailable:    codes.Unavailable,
+}
+
+// problemInterceptor converts errors to a gRPC status, with the stable error code of docs/errors.md as message
+func (s *Service) problemInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	if err == nil {
+		return resp, nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return nil, err
+	}
+
+	problem := helpers.NewProblem(err)
+	code, ok := grpcCodes[problem.Status]
+	if !ok {
+		code = codes.Internal
+	}
+	if problem.Detail != "" {
+		return nil, status.Errorf(code, "%s: %s", problem.Code, problem.Detail)
+	}
+	if len(problem.Errors) > 0 {
+		details, _ := json.Marshal(problem.Errors)
+		return nil, status.Errorf(code, "%s: %s", problem.Code, details)
+	}
+
+	return nil, status.Error(code, problem.Code)
+}
+
+// authInterceptor authenticates the client by a verified TLS client certificate or the x-api-key metadata, same
+// credentials as the HTTP API, and sets the tenant of the client
+func (s *Service) authInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if !s.cfg.APIGW.APIServer.Auth.Enabled {
+		return handler(ctx, req)
+	}
+
+	authenticSource, ok := s.authenticate(ctx)
+	if !ok {
+		s.log.Info("authentication failed", "method", info.FullMethod)
+		return nil, helpers.ErrNotAuthenticated
+	}
+
+	ctx = tenant.NewContext(ctx, s.tenants.ByAuthenticSource(authenticSource))
+	return handler(context.WithValue(ctx, authenticSourceKey{}, authenticSource), req)
+}
+
+// authenticate returns the authentic source of the client certificate or x-api-key metadata, false if neither authenticates
+func (s *Service) authenticate(ctx context.Context) (string, bool) {
+	cfg := s.cfg.APIGW.APIServer.Auth
+
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.VerifiedChains) > 0 {
+			sum := sha256.Sum256(tlsInfo.State.PeerCertificates[0].Raw)
+			fingerprint := hex.EncodeToString(sum[:])
+
+			if authenticSource, ok := cfg.ClientCertificates[fingerprint]; ok {
+				return authenticSource, true
+			}
+			if authenticSource, ok := s.tenants.ClientCertificates()[fingerprint]; ok {
+				return authenticSource, true
+			}
+		}
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, key := range md.Get("x-api-key") {
+		for _, apiKeys := range []map[string]string{cfg.APIKeys, s.tenants.APIKeys()} {
+			for k, authenticSource := range apiKeys {
+				if subtle.ConstantTimeCompare([]byte(k), []byte(key)) == 1 {
+					return authenticSource, true
+				}
+			}
+		}
+	}
+
+	return "", false
+}
+
+// rateLimitInterceptor limits the calls of a client with the rate limit of the HTTP API and sets the retry-after
+// header when it is exceeded. Authenticated clients are limited by authentic source, others by IP address.
+func (s *Service) rateLimitInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if s.rateLimit == nil {
+		return handler(ctx, req)
+	}
+
+	key := ""
+	if s.cfg.APIGW.APIServer.Auth.Enabled {
+		if authenticSource, ok := s.authenticate(ctx); ok {
+			key = "client:" + authenticSource
+		}
+	}
+	if key == "" {
+		if p, ok := peer.FromContext(ctx); ok {
+			host, _, err := net.SplitHostPort(p.Addr.String())
+			if err != nil {
+				host = p.Addr.String()
+			}
+			key = "ip:" + host
+		}
+	}
+
+	ok, retryAfter := s.rateLimit(key)
+	if !ok {
+		grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))))
+		return nil, helpers.ErrTooManyRequests
+	}
+
+	return handler(ctx, req)
+}
+
+// idempotentMethods are the mutating methods that accept an idempotency-key metadata, all of them reply Empty
+var idempotentMethods = map[string]bool{
+	apiv1_apigw.APIGWService_Upload_FullMethodName:      true,
+	apiv1_apigw.APIGWService_AddIdentity_FullMethodName: true,
+	apiv1_apigw.APIGWService_Revoke_FullMethodName:      true,
+}
+
+// idempotencyInterceptor keeps the outcome of calls made with an idempotency-key metadata in the idempotency store of
+// the HTTP API, a retry with the same key and request gets the stored reply, a retry with another request is rejected.
+// Failed calls are not stored, they can be retried with the same key. It has to run after the auth interceptor.
+func (s *Service) idempotencyInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	keys := md.Get("idempotency-key")
+	if len(keys) == 0 || keys[0] == "" || !idempotentMethods[info.FullMethod] {
+		return handler(ctx, req)
+	}
+	key := keys[0]
+
+	if len(key) > maxIdempotencyKeyLength {
+		return nil, helpers.ErrInvalidIdempotencyKey
+	}
+
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return handler(ctx, req)
+	}
+	body, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	requestHash := sha256.Sum256(body)
+
+	client, _ := ctx.Value(authenticSourceKey{}).(string)
+	record := &model.IdempotencyRecord{
+		Client:      client,
+		Route:       info.FullMethod,
+		Key:         key,
+		RequestHash: hex.EncodeToString(requestHash[:]),
+		Status:      model.IdempotencyPending,
+		CreatedAt:   time.Now(),
+	}
+
+	stored, err := s.apiv1.ReserveIdempotencyKey(ctx, record)
+	if err != nil {
+		s.log.Error(err, "reserve idempotency key")
+		return nil, err
+	}
+
+	if stored != nil {
+		switch {
+		case stored.RequestHash != record.RequestHash:
+			return nil, helpers.ErrIdempotencyKeyReused
+		case stored.Status != model.IdempotencyCompleted:
+			return nil, helpers.ErrIdempotencyKeyInProgress
+		}
+		grpc.SetHeader(ctx, metadata.Pairs("idempotent-replayed", "true"))
+		return &apiv1_apigw.Empty{}, nil
+	}
+
+	resp, err := handler(ctx, req)
+
+	// the call is done, the outcome is stored even if the client has gone
+	ctx = context.WithoutCancel(ctx)
+
+	if err != nil {
+		if err := s.apiv1.ReleaseIdempotencyKey(ctx, record); err != nil {
+			s.log.Error(err, "release idempotency key")
+		}
+		return nil, err
+	}
+
+	record.StatusCode = http.StatusOK
+	if err := s.apiv1.CompleteIdempotencyKey(ctx, record); err != nil {
+		s.log.Error(err, "complete idempotency key")
+	}
+
+	return resp, nil
+}
+
+// authorize returns ErrNotAuthorized if the authenticated client does not act for authenticSource
+func (s *Service) authorize(ctx context.Context, authenticSource string) error {
+	v, ok := ctx.Value(authenticSourceKey{}).(string)
+	if !ok {
+		return nil
+	}
+
+	if v != authenticSource {
+		s.log.Info("not authorized", "client", v, "authentic_source", authenticSource)
+		return helpers.ErrNotAuthorized
+	}
+
+	return nil
+}
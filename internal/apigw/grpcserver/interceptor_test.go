@@ -0,0 +1,331 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"net"
+	"testing"
+	"time"
+	"vc/internal/apigw/apiv1"
+	"vc/internal/gen/apigw/apiv1_apigw"
+	"vc/pkg/helpers"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+	"vc/pkg/tenant"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func mockService(t *testing.T) *Service {
+	cfg := &model.Cfg{APIGW: model.APIGW{APIServer: model.APIServer{Auth: model.Authentication{
+		Enabled:            true,
+		APIKeys:            map[string]string{"key-sunet": "SUNET"},
+		ClientCertificates: map[string]string{fingerprint([]byte("cert-sunet")): "SUNET"},
+	}}}}
+	cfg.Common.Tenants = []model.Tenant{{
+		Name:               "edu",
+		AuthenticSources:   []string{"LADOK"},
+		APIKeys:            map[string]string{"key-ladok": "LADOK"},
+		ClientCertificates: map[string]string{fingerprint([]byte("cert-ladok")): "LADOK"},
+	}}
+
+	tenants, err := tenant.New(cfg.Common.Tenants)
+	assert.NoError(t, err)
+
+	return &Service{cfg: cfg, tenants: tenants, log: logger.NewSimple("test")}
+}
+
+func fingerprint(raw []byte) string {
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// mockPeer returns ctx with a peer of addr, a verified client certificate of raw if it is not nil
+func mockPeer(ctx context.Context, addr string, raw []byte) context.Context {
+	p := &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(addr), Port: 4711}}
+	if raw != nil {
+		cert := &x509.Certificate{Raw: raw}
+		p.AuthInfo = credentials.TLSInfo{State: tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{cert},
+			VerifiedChains:   [][]*x509.Certificate{{cert}},
+		}}
+	}
+	return peer.NewContext(ctx, p)
+}
+
+func TestInterceptors(t *testing.T) {
+	tts := []struct {
+		name            string
+		apiKey          string
+		clientCert      []byte
+		authenticSource string
+		wantTenant      string
+		wantCode        codes.Code
+		wantMessage     string
+	}{
+		{
+			name:            "authorized",
+			apiKey:          "key-sunet",
+			authenticSource: "SUNET",
+			wantCode:        codes.OK,
+		},
+		{
+			name:            "tenant api key",
+			apiKey:          "key-ladok",
+			authenticSource: "LADOK",
+			wantTenant:      "edu",
+			wantCode:        codes.OK,
+		},
+		{
+			name:            "other authentic source",
+			apiKey:          "key-sunet",
+			authenticSource: "OTHER",
+			wantCode:        codes.PermissionDenied,
+			wantMessage:     "NOT_AUTHORIZED",
+		},
+		{
+			name:            "client certificate",
+			clientCert:      []byte("cert-sunet"),
+			authenticSource: "SUNET",
+			wantCode:        codes.OK,
+		},
+		{
+			name:            "tenant client certificate",
+			clientCert:      []byte("cert-ladok"),
+			authenticSource: "LADOK",
+			wantTenant:      "edu",
+			wantCode:        codes.OK,
+		},
+		{
+			name:            "unknown client certificate",
+			clientCert:      []byte("cert-unknown"),
+			authenticSource: "SUNET",
+			wantCode:        codes.Unauthenticated,
+			wantMessage:     "NOT_AUTHENTICATED",
+		},
+		{
+			name:            "unknown api key",
+			apiKey:          "key-unknown",
+			authenticSource: "SUNET",
+			wantCode:        codes.Unauthenticated,
+			wantMessage:     "NOT_AUTHENTICATED",
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			s := mockService(t)
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", tt.apiKey))
+			ctx = mockPeer(ctx, "192.0.2.1", tt.clientCert)
+			info := &grpc.UnaryServerInfo{FullMethod: "/v1.apigw.APIGWService/Revoke"}
+
+			handler := func(ctx context.Context, req any) (any, error) {
+				return s.authInterceptor(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+					assert.Equal(t, tt.wantTenant, tenant.FromContext(ctx))
+					return "ok", s.authorize(ctx, tt.authenticSource)
+				})
+			}
+
+			_, err := s.problemInterceptor(ctx, nil, info, handler)
+			st, _ := status.FromError(err)
+			assert.Equal(t, tt.wantCode, st.Code())
+			assert.Equal(t, tt.wantMessage, st.Message())
+		})
+	}
+}
+
+func TestProblemInterceptorNotFound(t *testing.T) {
+	s := mockService(t)
+	_, err := s.problemInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+		return nil, helpers.ErrNoDocumentFound
+	})
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestRateLimitInterceptor(t *testing.T) {
+	tts := []struct {
+		name    string
+		apiKey  string
+		addr    string
+		wantKey string
+	}{
+		{
+			name:    "authenticated",
+			apiKey:  "key-sunet",
+			addr:    "192.0.2.1",
+			wantKey: "client:SUNET",
+		},
+		{
+			name:    "unknown api key",
+			apiKey:  "key-unknown",
+			addr:    "192.0.2.1",
+			wantKey: "ip:192.0.2.1",
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			s := mockService(t)
+			var keys []string
+			s.rateLimit = func(key string) (bool, time.Duration) {
+				keys = append(keys, key)
+				return len(keys) == 1, 1500 * time.Millisecond
+			}
+
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", tt.apiKey))
+			ctx = mockPeer(ctx, tt.addr, nil)
+			info := &grpc.UnaryServerInfo{FullMethod: apiv1_apigw.APIGWService_Revoke_FullMethodName}
+			handler := func(ctx context.Context, req any) (any, error) {
+				return s.rateLimitInterceptor(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+					return "ok", nil
+				})
+			}
+
+			_, err := s.problemInterceptor(ctx, nil, info, handler)
+			assert.NoError(t, err)
+
+			_, err = s.problemInterceptor(ctx, nil, info, handler)
+			st, _ := status.FromError(err)
+			assert.Equal(t, codes.ResourceExhausted, st.Code())
+			assert.Equal(t, "TOO_MANY_REQUESTS", st.Message())
+
+			assert.Equal(t, []string{tt.wantKey, tt.wantKey}, keys)
+		})
+	}
+}
+
+// mockStore is the idempotency store of the Apiv1 interface
+type mockStore struct {
+	Apiv1
+	records map[string]*model.IdempotencyRecord
+}
+
+func (m *mockStore) ReserveIdempotencyKey(ctx context.Context, record *model.IdempotencyRecord) (*model.IdempotencyRecord, error) {
+	id := record.Client + record.Route + record.Key
+	if stored, ok := m.records[id]; ok {
+		return stored, nil
+	}
+	m.records[id] = record
+	return nil, nil
+}
+
+func (m *mockStore) CompleteIdempotencyKey(ctx context.Context, record *model.IdempotencyRecord) error {
+	record.Status = model.IdempotencyCompleted
+	m.records[record.Client+record.Route+record.Key] = record
+	return nil
+}
+
+func (m *mockStore) ReleaseIdempotencyKey(ctx context.Context, record *model.IdempotencyRecord) error {
+	delete(m.records, record.Client+record.Route+record.Key)
+	return nil
+}
+
+func TestIdempotencyInterceptor(t *testing.T) {
+	revoke := &apiv1_apigw.RevokeRequest{AuthenticSource: "SUNET", DocumentType: "PDA1", Revision: 1}
+	collect := &apiv1_apigw.CollectRequest{AuthenticSource: "SUNET"}
+
+	tts := []struct {
+		name      string
+		method    string
+		key       string
+		requests  []any
+		handleErr error
+		wantCalls int
+		wantCodes []codes.Code
+	}{
+		{
+			name:      "retry is replayed",
+			method:    apiv1_apigw.APIGWService_Revoke_FullMethodName,
+			key:       "key-1",
+			requests:  []any{revoke, revoke},
+			wantCalls: 1,
+			wantCodes: []codes.Code{codes.OK, codes.OK},
+		},
+		{
+			name:      "key reused with another request",
+			method:    apiv1_apigw.APIGWService_Revoke_FullMethodName,
+			key:       "key-1",
+			requests:  []any{revoke, &apiv1_apigw.RevokeRequest{AuthenticSource: "SUNET", DocumentType: "PDA1", Revision: 2}},
+			wantCalls: 1,
+			wantCodes: []codes.Code{codes.OK, codes.InvalidArgument},
+		},
+		{
+			name:      "failed call is retried",
+			method:    apiv1_apigw.APIGWService_Revoke_FullMethodName,
+			key:       "key-1",
+			requests:  []any{revoke, revoke},
+			handleErr: helpers.ErrNoDocumentFound,
+			wantCalls: 2,
+			wantCodes: []codes.Code{codes.NotFound, codes.NotFound},
+		},
+		{
+			name:      "without key",
+			method:    apiv1_apigw.APIGWService_Revoke_FullMethodName,
+			requests:  []any{revoke, revoke},
+			wantCalls: 2,
+			wantCodes: []codes.Code{codes.OK, codes.OK},
+		},
+		{
+			name:      "not idempotent method",
+			method:    apiv1_apigw.APIGWService_Collect_FullMethodName,
+			key:       "key-1",
+			requests:  []any{collect, collect},
+			wantCalls: 2,
+			wantCodes: []codes.Code{codes.OK, codes.OK},
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			s := mockService(t)
+			s.apiv1 = &mockStore{records: map[string]*model.IdempotencyRecord{}}
+
+			ctx := context.WithValue(context.Background(), authenticSourceKey{}, "SUNET")
+			if tt.key != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("idempotency-key", tt.key))
+			}
+			info := &grpc.UnaryServerInfo{FullMethod: tt.method}
+
+			calls := 0
+			handler := func(ctx context.Context, req any) (any, error) {
+				return s.idempotencyInterceptor(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+					calls++
+					if tt.handleErr != nil {
+						return nil, tt.handleErr
+					}
+					return &apiv1_apigw.Empty{}, nil
+				})
+			}
+
+			for i, req := range tt.requests {
+				_, err := s.problemInterceptor(ctx, req, info, handler)
+				assert.Equal(t, tt.wantCodes[i], status.Code(err), "request %d", i)
+			}
+			assert.Equal(t, tt.wantCalls, calls)
+		})
+	}
+}
+
+func TestIdempotencyInterceptorInvalidKey(t *testing.T) {
+	s := mockService(t)
+	s.apiv1 = &mockStore{records: map[string]*model.IdempotencyRecord{}}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("idempotency-key", string(make([]byte, 256))))
+	_, err := s.idempotencyInterceptor(ctx, &apiv1_apigw.RevokeRequest{}, &grpc.UnaryServerInfo{FullMethod: apiv1_apigw.APIGWService_Revoke_FullMethodName}, func(ctx context.Context, req any) (any, error) {
+		return nil, errors.New("not called")
+	})
+	assert.ErrorIs(t, err, helpers.ErrInvalidIdempotencyKey)
+}
+
+var _ Apiv1 = (*apiv1.Client)(nil)
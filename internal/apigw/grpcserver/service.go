@@ -0,0 +1,91 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"time"
+	"vc/internal/apigw/apiv1"
+	"vc/internal/gen/apigw/apiv1_apigw"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+	"vc/pkg/tenant"
+	"vc/pkg/trace"
+
+	"google.golang.org/grpc"
+)
+
+// Service is the service object for grpcserver
+type Service struct {
+	log            *logger.Log
+	cfg            *model.Cfg
+	apiv1          Apiv1
+	tracer         *trace.Tracer
+	eventPublisher apiv1.EventPublisher
+	tenants        *tenant.Tenants
+	rateLimit      RateLimitFunc
+	listener       net.Listener
+	server         *grpc.Server
+	apiv1_apigw.UnimplementedAPIGWServiceServer
+}
+
+// RateLimitFunc takes a token of the rate limit for a client key, it returns false and the time until the next token
+// if there is none
+type RateLimitFunc func(key string) (bool, time.Duration)
+
+// New creates a new gRPC server service, eventPublisher and rateLimit may be nil. rateLimit is the rate limit of the
+// HTTP API, a client gets one limit for both.
+func New(ctx context.Context, cfg *model.Cfg, apiv1 *apiv1.Client, tracer *trace.Tracer, eventPublisher apiv1.EventPublisher, rateLimit RateLimitFunc, log *logger.Log) (*Service, error) {
+	s := &Service{
+		log:            log.New("grpcserver"),
+		cfg:            cfg,
+		apiv1:          apiv1,
+		tracer:         tracer,
+		eventPublisher: eventPublisher,
+		rateLimit:      rateLimit,
+	}
+
+	var err error
+	s.tenants, err = tenant.New(cfg.Common.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	s.listener, err = net.Listen("tcp", s.cfg.APIGW.GRPCServer.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	interceptors := []grpc.UnaryServerInterceptor{s.problemInterceptor, s.rateLimitInterceptor, s.authInterceptor}
+	if s.cfg.APIGW.Idempotency.Enabled {
+		interceptors = append(interceptors, s.idempotencyInterceptor)
+	}
+
+	opts := []grpc.ServerOption{trace.GRPCServerOption(), grpc.ChainUnaryInterceptor(interceptors...)}
+	if s.cfg.APIGW.GRPCServer.TLS.Enabled {
+		creds, err := tlsCredentials(s.cfg.APIGW.GRPCServer.TLS)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	s.server = grpc.NewServer(opts...)
+	apiv1_apigw.RegisterAPIGWServiceServer(s.server, s)
+
+	go func() {
+		if err := s.server.Serve(s.listener); err != nil {
+			s.log.Error(err, "failed to serve")
+		}
+	}()
+
+	s.log.Info("Started", "addr", s.cfg.APIGW.GRPCServer.Addr, "tls", s.cfg.APIGW.GRPCServer.TLS.Enabled)
+
+	return s, nil
+}
+
+// Close stops the gRPC server after in-flight calls have finished
+func (s *Service) Close(ctx context.Context) error {
+	s.server.GracefulStop()
+	s.log.Info("Stopped")
+	return nil
+}
@@ -0,0 +1,43 @@
+package grpcserver
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
+	"vc/pkg/model"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// tlsCredentials returns the server credentials of cfg, with the same settings as the HTTP API. With a client CA file
+// client certificates are requested and verified, clients without one can still authenticate with an API key.
+func tlsCredentials(cfg model.TLS) (credentials.TransportCredentials, error) {
+	cert, err := tls.LoadX509KeyPair(cfg.CertFilePath, cfg.KeyFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:       tls.VersionTLS12,
+		CurvePreferences: []tls.CurveID{tls.CurveP521, tls.CurveP384, tls.CurveP256},
+		Certificates:     []tls.Certificate{cert},
+	}
+
+	if cfg.ClientCAFilePath != "" {
+		caPEM, err := os.ReadFile(cfg.ClientCAFilePath)
+		if err != nil {
+			return nil, err
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, errors.New("no certificates found in client CA file")
+		}
+
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
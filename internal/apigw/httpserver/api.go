@@ -2,9 +2,11 @@ package httpserver
 
 import (
 	"context"
+	"io"
 	"vc/internal/apigw/apiv1"
 	"vc/internal/gen/issuer/apiv1_issuer"
 	"vc/internal/gen/status/apiv1_status"
+	"vc/pkg/httphelpers"
 	"vc/pkg/model"
 )
 
@@ -12,23 +14,61 @@ import (
 type Apiv1 interface {
 	// datastore endpoints
 	Upload(ctx context.Context, req *apiv1.UploadRequest) error
+	VerifyUploadSignature(ctx context.Context, compact string) ([]byte, *model.UploadSigner, error)
 	Notification(ctx context.Context, req *apiv1.NotificationRequest) (*apiv1.NotificationReply, error)
 	AddDocumentIdentity(ctx context.Context, req *apiv1.AddDocumentIdentityRequest) error
 	DeleteDocumentIdentity(ctx context.Context, req *apiv1.DeleteDocumentIdentityRequest) error
 	IdentityMapping(ctx context.Context, reg *apiv1.IdentityMappingRequest) (*apiv1.IdentityMappingReply, error)
 	GetDocument(ctx context.Context, req *apiv1.GetDocumentRequest) (*apiv1.GetDocumentReply, error)
 	DocumentList(ctx context.Context, req *apiv1.DocumentListRequest) (*apiv1.DocumentListReply, error)
+	DocumentChanges(ctx context.Context, req *apiv1.DocumentChangesRequest) (*apiv1.DocumentChangesReply, error)
 	DeleteDocument(ctx context.Context, req *apiv1.DeleteDocumentRequest) error
 	GetDocumentCollectID(ctx context.Context, req *apiv1.GetDocumentCollectIDRequest) (*apiv1.GetDocumentCollectIDReply, error)
 	RevokeDocument(ctx context.Context, req *apiv1.RevokeDocumentRequest) error
+	DocumentStatusHistory(ctx context.Context, req *apiv1.DocumentStatusHistoryRequest) (*apiv1.DocumentStatusHistoryReply, error)
+	DocumentVersions(ctx context.Context, req *apiv1.DocumentVersionsRequest) (*apiv1.DocumentVersionsReply, error)
+	DocumentDiff(ctx context.Context, req *apiv1.DocumentDiffRequest) (*apiv1.DocumentDiffReply, error)
 	AddConsent(ctx context.Context, req *apiv1.AddConsentRequest) error
 	GetConsent(ctx context.Context, req *apiv1.GetConsentRequest) (*model.Consent, error)
+	AddDocumentConsent(ctx context.Context, req *apiv1.AddDocumentConsentRequest) (*apiv1.AddDocumentConsentReply, error)
+	DocumentConsentList(ctx context.Context, req *apiv1.DocumentConsentListRequest) (*apiv1.DocumentConsentListReply, error)
+	WithdrawDocumentConsent(ctx context.Context, req *apiv1.WithdrawDocumentConsentRequest) (*apiv1.AddDocumentConsentReply, error)
 
 	// credential endpoints
 	Revoke(ctx context.Context, req *apiv1.RevokeRequest) (*apiv1.RevokeReply, error)
 	Credential(ctx context.Context, req *apiv1.CredentialRequest) (*apiv1_issuer.MakeSDJWTReply, error)
+	DeferredCredential(ctx context.Context, req *apiv1.DeferredCredentialRequest) (*apiv1_issuer.MakeSDJWTReply, error)
+	CredentialNotification(ctx context.Context, req *apiv1.CredentialNotificationRequest) (*model.CredentialNotification, error)
+	RefreshCredential(ctx context.Context, req *apiv1.RefreshCredentialRequest) (*apiv1_issuer.MakeSDJWTReply, error)
 	JWKS(ctx context.Context) (*apiv1_issuer.JwksReply, error)
 
+	// admin endpoints
+	ExportDocuments(ctx context.Context, req *apiv1.ExportDocumentsRequest, w io.Writer) (int, error)
+	ImportDocuments(ctx context.Context, req *apiv1.ImportDocumentsRequest, r io.Reader) (*apiv1.ImportDocumentsReply, error)
+	RotateEncryptionKeys(ctx context.Context, req *apiv1.RotateEncryptionKeysRequest) (*apiv1.RotateEncryptionKeysReply, error)
+	MigrateAttachments(ctx context.Context, req *apiv1.MigrateAttachmentsRequest) (*apiv1.MigrateAttachmentsReply, error)
+	ExportSubjectData(ctx context.Context, req *apiv1.SubjectDataRequest) (*apiv1.ExportSubjectDataReply, error)
+	EraseSubjectData(ctx context.Context, req *apiv1.SubjectDataRequest) (*apiv1.ErasureJobReply, error)
+	ErasureJobStatus(ctx context.Context, req *apiv1.ErasureJobStatusRequest) (*apiv1.ErasureJobReply, error)
+
+	// webhook endpoints
+	AddWebhook(ctx context.Context, req *apiv1.AddWebhookRequest) (*apiv1.AddWebhookReply, error)
+	ListWebhooks(ctx context.Context, req *apiv1.ListWebhooksRequest) (*apiv1.ListWebhooksReply, error)
+	DeleteWebhook(ctx context.Context, req *apiv1.DeleteWebhookRequest) error
+	WebhookDeliveries(ctx context.Context, req *apiv1.WebhookDeliveriesRequest) (*apiv1.WebhookDeliveriesReply, error)
+
+	// identity review endpoints
+	IdentityReviewList(ctx context.Context, req *apiv1.IdentityReviewListRequest) (*apiv1.IdentityReviewListReply, error)
+	IdentityReviewDecision(ctx context.Context, req *apiv1.IdentityReviewDecisionRequest) (*apiv1.IdentityReviewDecisionReply, error)
+
+	// federation endpoints
+	EntityConfiguration(ctx context.Context) (string, error)
+	TrustChain(ctx context.Context) (*apiv1.TrustChainReply, error)
+
+	// Idempotency-Key responses
+	httphelpers.IdempotencyStore
+
 	// misc endpoints
 	Health(ctx context.Context, req *apiv1_status.StatusRequest) (*apiv1_status.StatusReply, error)
+	Statistics(ctx context.Context) (*apiv1.StatisticsReply, error)
 }
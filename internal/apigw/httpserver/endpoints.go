@@ -2,12 +2,23 @@ package httpserver
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
 	"vc/internal/apigw/apiv1"
 	"vc/internal/gen/status/apiv1_status"
+	"vc/pkg/helpers"
+	"vc/pkg/model"
 
 	"go.opentelemetry.io/otel/codes"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 func (s *Service) endpointUpload(ctx context.Context, c *gin.Context) (any, error) {
@@ -15,7 +26,11 @@ func (s *Service) endpointUpload(ctx context.Context, c *gin.Context) (any, erro
 	defer span.End()
 
 	request := &apiv1.UploadRequest{}
-	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+	if err := s.bindUpload(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if err := s.httpHelpers.Auth.Authorize(c, request.Meta.AuthenticSource); err != nil {
 		span.SetStatus(codes.Error, err.Error())
 		return nil, err
 	}
@@ -37,6 +52,51 @@ func (s *Service) endpointUpload(ctx context.Context, c *gin.Context) (any, erro
 	return nil, nil
 }
 
+// bindUpload binds the upload request, a signed upload is verified and its signer set in the meta data
+func (s *Service) bindUpload(ctx context.Context, c *gin.Context, request *apiv1.UploadRequest) error {
+	detached := c.GetHeader("X-JWS-Signature")
+	if !s.cfg.APIGW.SignedUpload.Enabled || (c.ContentType() != "application/jose" && detached == "") {
+		if s.cfg.APIGW.SignedUpload.Required {
+			return helpers.ErrUploadSignatureRequired
+		}
+		if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+			return err
+		}
+		if request.Meta != nil {
+			request.Meta.SignedBy = nil
+		}
+		return nil
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return err
+	}
+
+	compact := strings.TrimSpace(string(body))
+	if detached != "" {
+		compact, err = apiv1.DetachedJWS(detached, body)
+		if err != nil {
+			return err
+		}
+	}
+
+	payload, signer, err := s.apiv1.VerifyUploadSignature(ctx, compact)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(payload, request); err != nil {
+		return err
+	}
+	if err := binding.Validator.ValidateStruct(request); err != nil {
+		return err
+	}
+	request.Meta.SignedBy = signer
+
+	return nil
+}
+
 func (s *Service) endpointNotification(ctx context.Context, c *gin.Context) (any, error) {
 	ctx, span := s.tracer.Start(ctx, "httpserver:endpointNotification")
 	defer span.End()
@@ -63,6 +123,16 @@ func (s *Service) endpointAddDocumentIdentity(ctx context.Context, c *gin.Contex
 		span.SetStatus(codes.Error, err.Error())
 		return nil, err
 	}
+	if err := s.httpHelpers.Auth.Authorize(c, request.AuthenticSource); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	revision, err := s.httpHelpers.Binding.IfMatch(ctx, c)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	request.Revision = revision
 	if err := s.apiv1.AddDocumentIdentity(ctx, request); err != nil {
 		span.SetStatus(codes.Error, err.Error())
 		return nil, err
@@ -79,6 +149,16 @@ func (s *Service) endpointDeleteDocumentIdentity(ctx context.Context, c *gin.Con
 		span.SetStatus(codes.Error, err.Error())
 		return nil, err
 	}
+	if err := s.httpHelpers.Auth.Authorize(c, request.AuthenticSource); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	revision, err := s.httpHelpers.Binding.IfMatch(ctx, c)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	request.Revision = revision
 	if err := s.apiv1.DeleteDocumentIdentity(ctx, request); err != nil {
 		span.SetStatus(codes.Error, err.Error())
 		return nil, err
@@ -99,6 +179,7 @@ func (s *Service) endpointGetDocument(ctx context.Context, c *gin.Context) (any,
 		span.SetStatus(codes.Error, err.Error())
 		return nil, err
 	}
+	s.httpHelpers.Rendering.ETag(c, reply.Data.Meta.Revision)
 	return reply, nil
 }
 
@@ -111,6 +192,16 @@ func (s *Service) endpointRevokeDocument(ctx context.Context, c *gin.Context) (a
 		span.SetStatus(codes.Error, err.Error())
 		return nil, err
 	}
+	if err := s.httpHelpers.Auth.Authorize(c, request.AuthenticSource); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	revision, err := s.httpHelpers.Binding.IfMatch(ctx, c)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	request.Revision = revision
 	if err := s.apiv1.RevokeDocument(ctx, request); err != nil {
 		span.SetStatus(codes.Error, err.Error())
 		return nil, err
@@ -118,6 +209,69 @@ func (s *Service) endpointRevokeDocument(ctx context.Context, c *gin.Context) (a
 	return nil, nil
 }
 
+func (s *Service) endpointDocumentStatusHistory(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tracer.Start(ctx, "httpserver:endpointDocumentStatusHistory")
+	defer span.End()
+
+	request := &apiv1.DocumentStatusHistoryRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if err := s.httpHelpers.Auth.Authorize(c, request.AuthenticSource); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	reply, err := s.apiv1.DocumentStatusHistory(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *Service) endpointDocumentVersions(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tracer.Start(ctx, "httpserver:endpointDocumentVersions")
+	defer span.End()
+
+	request := &apiv1.DocumentVersionsRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if err := s.httpHelpers.Auth.Authorize(c, request.AuthenticSource); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	reply, err := s.apiv1.DocumentVersions(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *Service) endpointDocumentDiff(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tracer.Start(ctx, "httpserver:endpointDocumentDiff")
+	defer span.End()
+
+	request := &apiv1.DocumentDiffRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if err := s.httpHelpers.Auth.Authorize(c, request.AuthenticSource); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	reply, err := s.apiv1.DocumentDiff(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
 func (s *Service) endpointDeleteDocument(ctx context.Context, c *gin.Context) (any, error) {
 	ctx, span := s.tracer.Start(ctx, "httpserver:endpointDeleteDocument")
 	defer span.End()
@@ -127,11 +281,20 @@ func (s *Service) endpointDeleteDocument(ctx context.Context, c *gin.Context) (a
 		span.SetStatus(codes.Error, err.Error())
 		return nil, err
 	}
-	err := s.apiv1.DeleteDocument(ctx, request)
+	if err := s.httpHelpers.Auth.Authorize(c, request.AuthenticSource); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	revision, err := s.httpHelpers.Binding.IfMatch(ctx, c)
 	if err != nil {
 		span.SetStatus(codes.Error, err.Error())
 		return nil, err
 	}
+	request.Revision = revision
+	if err := s.apiv1.DeleteDocument(ctx, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
 	return nil, nil
 }
 
@@ -183,6 +346,49 @@ func (s *Service) endpointDocumentList(ctx context.Context, c *gin.Context) (any
 		span.SetStatus(codes.Error, err.Error())
 		return nil, err
 	}
+	etag, lastModified := documentListValidators(reply.Data)
+	if s.httpHelpers.Rendering.NotModified(c, etag, lastModified) {
+		return nil, nil
+	}
+	return reply, nil
+}
+
+// documentListValidators returns the ETag of a document list, a hash of the revisions of its documents, and the last
+// modification time of its documents
+func documentListValidators(docs []*model.DocumentList) (string, int64) {
+	revisions := make([]string, 0, len(docs))
+	lastModified := int64(0)
+	for _, doc := range docs {
+		if doc.Meta == nil {
+			continue
+		}
+		revisions = append(revisions, fmt.Sprintf("%s/%s/%s/%d", doc.Meta.AuthenticSource, doc.Meta.DocumentType, doc.Meta.DocumentID, doc.Meta.Revision))
+		lastModified = max(lastModified, doc.Meta.ModifiedAt)
+	}
+	slices.Sort(revisions)
+
+	sum := sha256.Sum256([]byte(strings.Join(revisions, "\n")))
+	return base64.RawURLEncoding.EncodeToString(sum[:16]), lastModified
+}
+
+func (s *Service) endpointDocumentChanges(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tracer.Start(ctx, "httpserver:endpointDocumentChanges")
+	defer span.End()
+
+	request := &apiv1.DocumentChangesRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if err := s.httpHelpers.Auth.Authorize(c, request.AuthenticSource); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	reply, err := s.apiv1.DocumentChanges(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
 	return reply, nil
 }
 
@@ -219,6 +425,69 @@ func (s *Service) endpointGetConsent(ctx context.Context, c *gin.Context) (any,
 	return reply, nil
 }
 
+func (s *Service) endpointAddDocumentConsent(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tracer.Start(ctx, "httpserver:endpointAddDocumentConsent")
+	defer span.End()
+
+	request := &apiv1.AddDocumentConsentRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if err := s.httpHelpers.Auth.Authorize(c, request.AuthenticSource); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	reply, err := s.apiv1.AddDocumentConsent(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *Service) endpointDocumentConsentList(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tracer.Start(ctx, "httpserver:endpointDocumentConsentList")
+	defer span.End()
+
+	request := &apiv1.DocumentConsentListRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if err := s.httpHelpers.Auth.Authorize(c, request.AuthenticSource); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	reply, err := s.apiv1.DocumentConsentList(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *Service) endpointWithdrawDocumentConsent(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tracer.Start(ctx, "httpserver:endpointWithdrawDocumentConsent")
+	defer span.End()
+
+	request := &apiv1.WithdrawDocumentConsentRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if err := s.httpHelpers.Auth.Authorize(c, request.AuthenticSource); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	reply, err := s.apiv1.WithdrawDocumentConsent(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
 func (s *Service) endpointHealth(ctx context.Context, c *gin.Context) (any, error) {
 	ctx, span := s.tracer.Start(ctx, "httpserver:endpointHealth")
 	defer span.End()
@@ -231,6 +500,18 @@ func (s *Service) endpointHealth(ctx context.Context, c *gin.Context) (any, erro
 	return reply, nil
 }
 
+func (s *Service) endpointStatistics(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tracer.Start(ctx, "httpserver:endpointStatistics")
+	defer span.End()
+
+	reply, err := s.apiv1.Statistics(ctx)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
 func (s *Service) endpointCredential(ctx context.Context, c *gin.Context) (any, error) {
 	ctx, span := s.tracer.Start(ctx, "httpserver:endpointCredential")
 	defer span.End()
@@ -248,6 +529,72 @@ func (s *Service) endpointCredential(ctx context.Context, c *gin.Context) (any,
 	return reply, nil
 }
 
+func (s *Service) endpointCredentialNotification(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tracer.Start(ctx, "httpserver:endpointCredentialNotification")
+	defer span.End()
+
+	request := &apiv1.CredentialNotificationRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	notification, err := s.apiv1.CredentialNotification(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	if s.cfg.Common.Kafka.Enabled {
+		// the event is already persisted, the wallet should not retry because of a broker failure
+		if err := s.eventPublisher.CredentialNotification(notification); err != nil {
+			s.log.Error(err, "publish credential notification failed", "notification_id", notification.NotificationID)
+		}
+	}
+
+	c.Status(http.StatusNoContent)
+	c.Writer.WriteHeaderNow()
+
+	return nil, nil
+}
+
+func (s *Service) endpointRefreshCredential(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tracer.Start(ctx, "httpserver:endpointRefreshCredential")
+	defer span.End()
+
+	request := &apiv1.RefreshCredentialRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if err := s.httpHelpers.Auth.Authorize(c, request.AuthenticSource); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	reply, err := s.apiv1.RefreshCredential(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *Service) endpointDeferredCredential(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tracer.Start(ctx, "httpserver:endpointDeferredCredential")
+	defer span.End()
+
+	request := &apiv1.DeferredCredentialRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	reply, err := s.apiv1.DeferredCredential(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
 func (s *Service) endpointJWKS(ctx context.Context, c *gin.Context) (any, error) {
 	ctx, span := s.tracer.Start(ctx, "httpserver:endpointJWKS")
 	defer span.End()
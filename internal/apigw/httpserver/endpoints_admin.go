@@ -0,0 +1,197 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+	"vc/internal/apigw/apiv1"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func (s *Service) endpointExportDocuments(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tracer.Start(ctx, "httpserver:endpointExportDocuments")
+	defer span.End()
+
+	request := &apiv1.ExportDocumentsRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if err := s.httpHelpers.Auth.Authorize(c, request.AuthenticSource); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if request.Format == "" {
+		request.Format = apiv1.ArchiveFormatNDJSON
+	}
+
+	// large archives outlive the server write timeout
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+
+	// the archive is streamed, so the outcome is sent as trailers
+	c.Header("Trailer", "X-Export-Status, X-Export-Count")
+	c.Header("Content-Type", apiv1.ArchiveMediaType[request.Format])
+	c.Header("Content-Disposition", "attachment; filename=\""+request.AuthenticSource+"."+request.Format+"\"")
+	c.Status(http.StatusOK)
+	c.Writer.WriteHeaderNow()
+
+	n, err := s.apiv1.ExportDocuments(ctx, request, c.Writer)
+	c.Writer.Header().Set("X-Export-Count", strconv.Itoa(n))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		s.log.Error(err, "export failed", "authentic_source", request.AuthenticSource)
+		c.Writer.Header().Set("X-Export-Status", "incomplete")
+		return nil, nil
+	}
+	c.Writer.Header().Set("X-Export-Status", "complete")
+
+	return nil, nil
+}
+
+func (s *Service) endpointImportDocuments(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tracer.Start(ctx, "httpserver:endpointImportDocuments")
+	defer span.End()
+
+	request := &apiv1.ImportDocumentsRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if err := s.httpHelpers.Auth.Authorize(c, request.AuthenticSource); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	// large archives outlive the server read timeout
+	if err := http.NewResponseController(c.Writer).SetReadDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+
+	reply, err := s.apiv1.ImportDocuments(ctx, request, c.Request.Body)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+func (s *Service) endpointRotateEncryptionKeys(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tracer.Start(ctx, "httpserver:endpointRotateEncryptionKeys")
+	defer span.End()
+
+	request := &apiv1.RotateEncryptionKeysRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if err := s.httpHelpers.Auth.Authorize(c, request.AuthenticSource); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	reply, err := s.apiv1.RotateEncryptionKeys(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+func (s *Service) endpointMigrateAttachments(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tracer.Start(ctx, "httpserver:endpointMigrateAttachments")
+	defer span.End()
+
+	request := &apiv1.MigrateAttachmentsRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if err := s.httpHelpers.Auth.Authorize(c, request.AuthenticSource); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	reply, err := s.apiv1.MigrateAttachments(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+func (s *Service) endpointExportSubjectData(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tracer.Start(ctx, "httpserver:endpointExportSubjectData")
+	defer span.End()
+
+	request := &apiv1.SubjectDataRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if err := s.httpHelpers.Auth.Authorize(c, request.AuthenticSource); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	reply, err := s.apiv1.ExportSubjectData(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+func (s *Service) endpointEraseSubjectData(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tracer.Start(ctx, "httpserver:endpointEraseSubjectData")
+	defer span.End()
+
+	request := &apiv1.SubjectDataRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if err := s.httpHelpers.Auth.Authorize(c, request.AuthenticSource); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	reply, err := s.apiv1.EraseSubjectData(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+func (s *Service) endpointErasureJobStatus(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tracer.Start(ctx, "httpserver:endpointErasureJobStatus")
+	defer span.End()
+
+	request := &apiv1.ErasureJobStatusRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if err := s.httpHelpers.Auth.Authorize(c, request.AuthenticSource); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	reply, err := s.apiv1.ErasureJobStatus(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return reply, nil
+}
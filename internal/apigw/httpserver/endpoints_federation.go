@@ -0,0 +1,36 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"vc/pkg/openidfederation"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func (s *Service) endpointEntityConfiguration(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tracer.Start(ctx, "httpserver:endpointEntityConfiguration")
+	defer span.End()
+
+	entityConfiguration, err := s.apiv1.EntityConfiguration(ctx)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	c.Data(http.StatusOK, openidfederation.MediaTypeEntityStatement, []byte(entityConfiguration))
+	return nil, nil
+}
+
+func (s *Service) endpointTrustChain(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tracer.Start(ctx, "httpserver:endpointTrustChain")
+	defer span.End()
+
+	reply, err := s.apiv1.TrustChain(ctx)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
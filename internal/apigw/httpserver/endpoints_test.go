@@ -0,0 +1,136 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"vc/internal/apigw/apiv1"
+	"vc/pkg/helpers"
+	"vc/pkg/httphelpers"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+	"vc/pkg/trace"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockApiv1 implements the methods of Apiv1 that a test sets, the others panic
+type mockApiv1 struct {
+	Apiv1
+	credentialNotification func(req *apiv1.CredentialNotificationRequest) (*model.CredentialNotification, error)
+}
+
+func (m *mockApiv1) CredentialNotification(ctx context.Context, req *apiv1.CredentialNotificationRequest) (*model.CredentialNotification, error) {
+	return m.credentialNotification(req)
+}
+
+// mockEventPublisher records the published credential notifications
+type mockEventPublisher struct {
+	apiv1.EventPublisher
+	err           error
+	notifications []*model.CredentialNotification
+}
+
+func (m *mockEventPublisher) CredentialNotification(notification *model.CredentialNotification) error {
+	m.notifications = append(m.notifications, notification)
+	return m.err
+}
+
+func TestEndpointCredentialNotification(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	log := logger.NewSimple("testing_httpserver")
+
+	tracer, err := trace.NewForTesting(ctx, "test", log)
+	assert.NoError(t, err)
+
+	notification := &model.CredentialNotification{
+		NotificationID:  "notification-1",
+		AuthenticSource: "SUNET",
+		DocumentType:    "PDA1",
+		DocumentID:      "doc-1",
+		Events:          []model.CredentialNotificationEvent{{Event: model.CredentialNotificationAccepted}},
+	}
+
+	tts := []struct {
+		name          string
+		kafka         bool
+		publishErr    error
+		apiErr        error
+		wantErr       error
+		wantStatus    int
+		wantPublished int
+	}{
+		{
+			name:       "without kafka",
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:          "published",
+			kafka:         true,
+			wantStatus:    http.StatusNoContent,
+			wantPublished: 1,
+		},
+		{
+			name:          "publish failure is not the wallet's",
+			kafka:         true,
+			publishErr:    errors.New("broker unavailable"),
+			wantStatus:    http.StatusNoContent,
+			wantPublished: 1,
+		},
+		{
+			name:    "unknown notification_id",
+			kafka:   true,
+			apiErr:  helpers.ErrInvalidNotificationID,
+			wantErr: helpers.ErrInvalidNotificationID,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &model.Cfg{}
+			cfg.Common.Kafka.Enabled = tt.kafka
+
+			httpHelpers, err := httphelpers.New(ctx, tracer, cfg, log)
+			assert.NoError(t, err)
+
+			publisher := &mockEventPublisher{err: tt.publishErr}
+			var got *apiv1.CredentialNotificationRequest
+			s := &Service{
+				cfg:    cfg,
+				log:    log,
+				tracer: tracer,
+				apiv1: &mockApiv1{credentialNotification: func(req *apiv1.CredentialNotificationRequest) (*model.CredentialNotification, error) {
+					got = req
+					if tt.apiErr != nil {
+						return nil, tt.apiErr
+					}
+					return notification, nil
+				}},
+				eventPublisher: publisher,
+				httpHelpers:    httpHelpers,
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/credential/notification",
+				strings.NewReader(`{"notification_id":"notification-1","event":"credential_accepted"}`))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			reply, err := s.endpointCredentialNotification(ctx, c)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Nil(t, reply)
+			assert.Equal(t, &apiv1.CredentialNotificationRequest{NotificationID: "notification-1", Event: "credential_accepted"}, got)
+			assert.Len(t, publisher.notifications, tt.wantPublished)
+
+			if tt.wantErr == nil {
+				assert.Equal(t, tt.wantStatus, w.Code)
+				assert.True(t, c.Writer.Written(), "RegEndpoint does not render a body over the status")
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package httpserver
 import (
 	"context"
 	"net/http"
+	"time"
 	"vc/internal/apigw/apiv1"
 	"vc/pkg/httphelpers"
 	"vc/pkg/logger"
@@ -13,10 +14,25 @@ import (
 	_ "vc/docs/apigw"
 
 	"github.com/gin-gonic/gin"
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+// idempotentRoutes are the mutating routes that accept an Idempotency-Key header
+var idempotentRoutes = []string{
+	"POST /api/v1/upload",
+	"PUT /api/v1/document/identity",
+	"DELETE /api/v1/document/identity",
+	"DELETE /api/v1/document",
+	"POST /api/v1/document/revoke",
+	"POST /api/v1/consent",
+	"POST /api/v1/document/consent",
+	"POST /api/v1/document/consent/withdraw",
+	"POST /api/v1/credential",
+	"POST /api/v1/credential/notification",
+	"POST /api/v1/credential/refresh",
+	"POST /api/v1/webhook",
+	"POST /api/v1/admin/subjects/erasure",
+}
+
 // Service is the service object for httpserver
 type Service struct {
 	cfg            *model.Cfg
@@ -47,15 +63,21 @@ func New(ctx context.Context, cfg *model.Cfg, apiv1 *apiv1.Client, tracer *trace
 		return nil, err
 	}
 
-	rgRoot, err := s.httpHelpers.Server.Default(ctx, s.server, s.gin, s.cfg.APIGW.APIServer.Addr)
+	rgRoot, err := s.httpHelpers.Server.Default(ctx, s.server, s.gin, s.cfg.APIGW.APIServer)
 	if err != nil {
 		return nil, err
 	}
 
 	s.httpHelpers.Server.RegEndpoint(ctx, rgRoot, http.MethodGet, "health", s.endpointHealth)
 
-	rgDocs := rgRoot.Group("/swagger")
-	rgDocs.GET("/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	if s.cfg.APIGW.TrustModel.Type == "openid_federation" {
+		s.httpHelpers.Server.RegEndpoint(ctx, rgRoot, http.MethodGet, ".well-known/openid-federation", s.endpointEntityConfiguration)
+		s.httpHelpers.Server.RegEndpoint(ctx, rgRoot, http.MethodGet, "federation/trust_chain", s.endpointTrustChain)
+	}
+
+	if err := s.httpHelpers.Server.Docs(ctx, rgRoot); err != nil {
+		return nil, err
+	}
 
 	rgAPIv1 := rgRoot.Group("api/v1")
 
@@ -63,6 +85,14 @@ func New(ctx context.Context, cfg *model.Cfg, apiv1 *apiv1.Client, tracer *trace
 		rgAPIv1.Use(s.httpHelpers.Middleware.BasicAuth(ctx, s.cfg.APIGW.APIServer.BasicAuth.Users))
 	}
 
+	if s.cfg.APIGW.APIServer.Auth.Enabled {
+		rgAPIv1.Use(s.httpHelpers.Auth.Middleware(ctx, s.cfg.APIGW.APIServer.Auth))
+	}
+
+	if s.cfg.APIGW.Idempotency.Enabled {
+		rgAPIv1.Use(s.httpHelpers.Middleware.Idempotency(ctx, s.apiv1, idempotentRoutes))
+	}
+
 	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPost, "/upload", s.endpointUpload)
 	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPost, "/notification", s.endpointNotification)
 	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPut, "/document/identity", s.endpointAddDocumentIdentity)
@@ -71,12 +101,48 @@ func New(ctx context.Context, cfg *model.Cfg, apiv1 *apiv1.Client, tracer *trace
 	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPost, "/document/collect_id", s.endpointGetDocumentCollectID)
 	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPost, "/identity/mapping", s.endpointIdentityMapping)
 	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPost, "/document/list", s.endpointDocumentList)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPost, "/document/changes", s.endpointDocumentChanges)
 	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPost, "/document", s.endpointGetDocument)
 	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPost, "/consent", s.endpointAddConsent)
 	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPost, "/consent/get", s.endpointGetConsent)
 	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPost, "/document/revoke", s.endpointRevokeDocument)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPost, "/document/status_history", s.endpointDocumentStatusHistory)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPost, "/document/versions", s.endpointDocumentVersions)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPost, "/document/diff", s.endpointDocumentDiff)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPost, "/document/consent", s.endpointAddDocumentConsent)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPost, "/document/consent/list", s.endpointDocumentConsentList)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPost, "/document/consent/withdraw", s.endpointWithdrawDocumentConsent)
 	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPost, "/credential", s.endpointCredential)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPost, "/credential/deferred", s.endpointDeferredCredential)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPost, "/credential/notification", s.endpointCredentialNotification)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPost, "/credential/refresh", s.endpointRefreshCredential)
 	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodGet, "/credential/.well-known/jwks", s.endpointJWKS)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodGet, "/statistics", s.endpointStatistics)
+
+	if s.cfg.APIGW.Webhook.Enabled {
+		s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPost, "/webhook", s.endpointAddWebhook)
+		s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodDelete, "/webhook", s.endpointDeleteWebhook)
+		s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPost, "/webhook/list", s.endpointListWebhooks)
+		s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPost, "/webhook/deliveries", s.endpointWebhookDeliveries)
+	}
+
+	if s.cfg.APIGW.IdentityMatching.Enabled {
+		s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPost, "/identity/review/list", s.endpointIdentityReviewList)
+		s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPost, "/identity/review/decision", s.endpointIdentityReviewDecision)
+	}
+
+	rgAdmin := rgAPIv1.Group("/admin")
+	s.httpHelpers.Server.RegEndpoint(ctx, rgAdmin, http.MethodGet, "/documents/export", s.endpointExportDocuments)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgAdmin, http.MethodPost, "/documents/import", s.endpointImportDocuments)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgAdmin, http.MethodPost, "/subjects/export", s.endpointExportSubjectData)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgAdmin, http.MethodPost, "/subjects/erasure", s.endpointEraseSubjectData)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgAdmin, http.MethodGet, "/subjects/erasure/:job_id", s.endpointErasureJobStatus)
+	if s.cfg.APIGW.FieldEncryption.Enabled {
+		s.httpHelpers.Server.RegEndpoint(ctx, rgAdmin, http.MethodPost, "/encryption/rotate", s.endpointRotateEncryptionKeys)
+	}
+	if s.cfg.APIGW.ObjectStore.Enabled {
+		s.httpHelpers.Server.RegEndpoint(ctx, rgAdmin, http.MethodPost, "/attachments/migrate", s.endpointMigrateAttachments)
+	}
 
 	// Run http server
 	go func() {
@@ -91,8 +157,28 @@ func New(ctx context.Context, cfg *model.Cfg, apiv1 *apiv1.Client, tracer *trace
 	return s, nil
 }
 
-// Close closing httpserver
+// Reload applies the rate limit and body size limits of a reloaded configuration
+func (s *Service) Reload(ctx context.Context, cfg *model.Cfg) error {
+	s.httpHelpers.Server.UpdateLimits(ctx, cfg.APIGW.APIServer)
+	return nil
+}
+
+// RateLimit takes a token of the rate limit of the API for key, the gRPC API shares it
+func (s *Service) RateLimit(key string) (bool, time.Duration) {
+	return s.httpHelpers.Middleware.Allow(key)
+}
+
+// AddHealthCheck adds a dependency check to the readiness probe
+func (s *Service) AddHealthCheck(name string, check httphelpers.HealthCheck) {
+	s.httpHelpers.Server.AddHealthCheck(name, check)
+}
+
+// Close drains in-flight requests and stops the httpserver
 func (s *Service) Close(ctx context.Context) error {
+	if err := s.httpHelpers.Server.Shutdown(ctx, s.server, s.cfg.APIGW.APIServer); err != nil {
+		return err
+	}
+
 	s.log.Info("Stopped")
 	return nil
 }
@@ -8,6 +8,7 @@ import (
 	"vc/pkg/messagebroker"
 	"vc/pkg/messagebroker/kafka"
 	"vc/pkg/model"
+	"vc/pkg/tenant"
 	"vc/pkg/trace"
 
 	"github.com/IBM/sarama"
@@ -20,19 +21,24 @@ func New(ctx context.Context, cfg *model.Cfg, apiv1 *apiv1.Client, tracer *trace
 		return nil, nil
 	}
 
+	tenants, err := tenant.New(cfg.Common.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
 	client, err := kafka.NewConsumerClient(ctx, cfg, cfg.Common.Kafka.Brokers, log.New("kafka_consumer_client"))
 	if err != nil {
 		return nil, err
 	}
 
 	handlerConfigs := []kafka.HandlerConfig{
-		{Topic: kafka.TopicUpload, ConsumerGroup: "topic_upload_consumer_group_apigw"},
+		{Topic: kafka.TopicUpload, ConsumerGroup: kafka.ConsumerGroupUploadAPIGW},
 		// add more kafka.HandlerConfig here...
 	}
 
 	handlerFactory := func(topic string) sarama.ConsumerGroupHandler {
 		handlersMap := map[string]kafka.MessageHandler{
-			kafka.TopicUpload: newUploadMessageHandler(log.New("kafka_upload_handler"), apiv1, tracer),
+			kafka.TopicUpload: newUploadMessageHandler(log.New("kafka_upload_handler"), apiv1, tenants, tracer),
 			// add more handlers here...
 		}
 		return &kafka.ConsumerGroupHandler{Handlers: handlersMap, Log: log.New("kafka_consumer_group_handler")}
@@ -44,19 +50,21 @@ func New(ctx context.Context, cfg *model.Cfg, apiv1 *apiv1.Client, tracer *trace
 	return client, nil
 }
 
-func newUploadMessageHandler(log *logger.Log, apiv1 *apiv1.Client, tracer *trace.Tracer) *UploadMessageHandler {
+func newUploadMessageHandler(log *logger.Log, apiv1 *apiv1.Client, tenants *tenant.Tenants, tracer *trace.Tracer) *UploadMessageHandler {
 	return &UploadMessageHandler{
-		log:    log,
-		apiv1:  apiv1,
-		tracer: tracer,
+		log:     log,
+		apiv1:   apiv1,
+		tenants: tenants,
+		tracer:  tracer,
 	}
 }
 
 // UploadMessageHandler struct that handles Kafka messages of type UploadRequest
 type UploadMessageHandler struct {
-	log    *logger.Log
-	apiv1  *apiv1.Client
-	tracer *trace.Tracer
+	log     *logger.Log
+	apiv1   *apiv1.Client
+	tenants *tenant.Tenants
+	tracer  *trace.Tracer
 }
 
 // HandleMessage handles Kafka message of type UploadRequest
@@ -67,6 +75,11 @@ func (h *UploadMessageHandler) HandleMessage(ctx context.Context, message *saram
 		return err
 	}
 
+	// the document is stored in the database of the tenant of its authentic source
+	if uploadRequest.Meta != nil {
+		ctx = tenant.NewContext(ctx, h.tenants.ByAuthenticSource(uploadRequest.Meta.AuthenticSource))
+	}
+
 	err := h.apiv1.Upload(ctx, &uploadRequest)
 	if err != nil {
 		h.log.Error(err, "Failed to handle UploadRequest")
@@ -51,6 +51,25 @@ func (s *kafkaMessageProducer) Upload(uploadRequest *apiv1.UploadRequest) error
 	return s.client.PublishMessage(kafka.TopicUpload, uploadRequest.Meta.DocumentID, jsonMarshaled, headers)
 }
 
+// CredentialNotification publish a CredentialNotification message to a Kafka topic
+func (s *kafkaMessageProducer) CredentialNotification(notification *model.CredentialNotification) error {
+	if notification == nil {
+		return errors.New("param notification is nil")
+	}
+
+	jsonMarshaled, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	paramType := reflect.TypeOf(notification).Elem().Name()
+	headers := []sarama.RecordHeader{
+		{Key: []byte(kafka.TypeOfStructInMessageValue), Value: []byte(paramType)},
+	}
+
+	return s.client.PublishMessage(kafka.TopicCredentialNotification, notification.NotificationID, jsonMarshaled, headers)
+}
+
 // Close closes all resources used/started by the publisher
 func (s *kafkaMessageProducer) Close(ctx context.Context) error {
 	if s.client != nil {
@@ -0,0 +1,158 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+	"vc/pkg/helpers"
+	"vc/pkg/model"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// maxBackoff caps the delay between delivery attempts
+const maxBackoff = 6 * 60 * 60
+
+// Publish queues event for delivery to every subscription of the document's authentic source that listens to it
+func (s *Service) Publish(ctx context.Context, event string, meta *model.MetaData, data map[string]any) error {
+	ctx, span := s.tracer.Start(ctx, "webhook:publish")
+	defer span.End()
+
+	subscriptions, err := s.db.VCWebhookColl.Matching(ctx, meta.AuthenticSource, event)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	now := time.Now().Unix()
+	for _, subscription := range subscriptions {
+		delivery := &model.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			Event: model.WebhookEvent{
+				ID:              uuid.NewString(),
+				Event:           event,
+				Timestamp:       now,
+				AuthenticSource: meta.AuthenticSource,
+				DocumentType:    meta.DocumentType,
+				DocumentID:      meta.DocumentID,
+				Data:            data,
+			},
+			Status:        model.WebhookDeliveryPending,
+			NextAttemptAt: now,
+		}
+		if err := s.db.VCWebhookDeliveryColl.Add(ctx, delivery); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Deliver tries one batch of due deliveries, and returns the number of delivered events
+func (s *Service) Deliver(ctx context.Context) (int, error) {
+	ctx, span := s.tracer.Start(ctx, "webhook:deliver")
+	defer span.End()
+
+	due, err := s.db.VCWebhookDeliveryColl.Due(ctx, time.Now().Unix(), s.cfg.APIGW.Webhook.BatchSize)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return 0, err
+	}
+
+	delivered := 0
+	for _, delivery := range due {
+		subscription, err := s.db.VCWebhookColl.Get(ctx, delivery.SubscriptionID)
+		if err != nil && !errors.Is(err, helpers.ErrNoDocumentFound) {
+			s.log.Error(err, "failed to get webhook subscription", "subscription_id", delivery.SubscriptionID)
+			continue
+		}
+
+		delivery.Attempts++
+		now := time.Now().Unix()
+
+		if subscription == nil {
+			delivery.Status = model.WebhookDeliveryFailed
+			delivery.LastError = "subscription deleted"
+		} else if err := s.post(ctx, subscription, &delivery.Event); err != nil {
+			s.log.Debug("webhook delivery failed", "subscription_id", subscription.ID, "event_id", delivery.Event.ID, "error", err)
+			delivery.LastError = err.Error()
+			if delivery.Attempts >= s.cfg.APIGW.Webhook.MaxAttempts {
+				delivery.Status = model.WebhookDeliveryFailed
+			} else {
+				delivery.NextAttemptAt = now + backoff(s.cfg.APIGW.Webhook.Backoff, delivery.Attempts)
+			}
+		} else {
+			delivery.Status = model.WebhookDeliveryDelivered
+			delivery.DeliveredAt = now
+			delivery.LastError = ""
+			delivered++
+		}
+
+		if err := s.db.VCWebhookDeliveryColl.Update(ctx, delivery); err != nil {
+			s.log.Error(err, "failed to update webhook delivery", "event_id", delivery.Event.ID)
+		}
+	}
+
+	return delivered, nil
+}
+
+// post posts event to the subscription url, signed with the subscription secret
+func (s *Service) post(ctx context.Context, subscription *model.WebhookSubscription, event *model.WebhookEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", event.Event)
+	req.Header.Set("X-Webhook-Delivery", event.ID)
+	req.Header.Set("X-Webhook-Signature", "t="+timestamp+",v1="+sign(subscription.Secret, timestamp, body))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// sign returns the hex encoded HMAC-SHA256 of timestamp, a dot and body
+func sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// backoff returns the number of seconds to wait after attempts failed attempts, doubling from base
+func backoff(base, attempts int) int64 {
+	delay := int64(base)
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxBackoff)
+}
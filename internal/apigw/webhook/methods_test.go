@@ -0,0 +1,92 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"vc/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	tts := []struct {
+		name     string
+		attempts int
+		want     int64
+	}{
+		{
+			name:     "first retry",
+			attempts: 1,
+			want:     30,
+		},
+		{
+			name:     "doubled",
+			attempts: 3,
+			want:     120,
+		},
+		{
+			name:     "capped",
+			attempts: 20,
+			want:     maxBackoff,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, backoff(30, tt.attempts))
+		})
+	}
+}
+
+func TestPost(t *testing.T) {
+	secret := "0123456789abcdef0123456789abcdef"
+
+	tts := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{
+			name:   "delivered",
+			status: http.StatusNoContent,
+		},
+		{
+			name:    "server error",
+			status:  http.StatusServiceUnavailable,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+
+				signature := r.Header.Get("X-Webhook-Signature")
+				timestamp := strings.TrimPrefix(strings.Split(signature, ",")[0], "t=")
+				assert.Equal(t, "t="+timestamp+",v1="+sign(secret, timestamp, body), signature)
+				assert.Equal(t, "document_uploaded", r.Header.Get("X-Webhook-Event"))
+				assert.Equal(t, "1", r.Header.Get("X-Webhook-Delivery"))
+
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			s := &Service{httpClient: server.Client()}
+			err := s.post(context.Background(), &model.WebhookSubscription{URL: server.URL, Secret: secret}, &model.WebhookEvent{
+				ID:    "1",
+				Event: model.WebhookEventDocumentUploaded,
+			})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+	"vc/internal/apigw/db"
+	"vc/pkg/httphelpers"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+	"vc/pkg/tenant"
+	"vc/pkg/trace"
+)
+
+// Service delivers document lifecycle events to the webhook subscriptions
+type Service struct {
+	cfg        *model.Cfg
+	log        *logger.Log
+	tracer     *trace.Tracer
+	db         *db.Service
+	httpClient *http.Client
+	tenants    *tenant.Tenants
+	wg         *sync.WaitGroup
+	quitChan   chan struct{}
+	ticker     *time.Ticker
+}
+
+// New creates a new webhook service
+func New(ctx context.Context, wg *sync.WaitGroup, db *db.Service, tracer *trace.Tracer, cfg *model.Cfg, log *logger.Log) (*Service, error) {
+	s := &Service{
+		cfg:        cfg,
+		log:        log.New("webhook"),
+		tracer:     tracer,
+		db:         db,
+		httpClient: httphelpers.NewPublicClient(time.Duration(cfg.APIGW.Webhook.Timeout) * time.Second),
+		wg:         wg,
+		quitChan:   make(chan struct{}),
+		ticker:     time.NewTicker(time.Duration(cfg.APIGW.Webhook.Interval) * time.Second),
+	}
+
+	var err error
+	s.tenants, err = tenant.New(cfg.Common.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	s.wg.Add(1)
+	go func() {
+		for {
+			select {
+			case <-s.ticker.C:
+				for _, name := range s.tenants.Names() {
+					if _, err := s.Deliver(tenant.NewContext(ctx, name)); err != nil {
+						s.log.Error(err, "deliver failed", "tenant", name)
+					}
+				}
+			case <-s.quitChan:
+				s.log.Info("Stop delivering")
+				s.ticker.Stop()
+				s.wg.Done()
+				return
+			}
+		}
+	}()
+
+	s.log.Info("Started")
+
+	return s, nil
+}
+
+// Close closes the webhook service
+func (s *Service) Close(ctx context.Context) error {
+	s.quitChan <- struct{}{}
+
+	s.log.Info("Stopped")
+	return nil
+}
@@ -0,0 +1,241 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v3.21.12
+// source: v1-apigw.proto
+
+package apiv1_apigw
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	APIGWService_Upload_FullMethodName      = "/v1.apigw.APIGWService/Upload"
+	APIGWService_AddIdentity_FullMethodName = "/v1.apigw.APIGWService/AddIdentity"
+	APIGWService_Collect_FullMethodName     = "/v1.apigw.APIGWService/Collect"
+	APIGWService_Revoke_FullMethodName      = "/v1.apigw.APIGWService/Revoke"
+)
+
+// APIGWServiceClient is the client API for APIGWService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// APIGWService mirrors the document and identity endpoints of the HTTP API.
+// Nested model structures are JSON encoded with the same schema as the HTTP API.
+type APIGWServiceClient interface {
+	Upload(ctx context.Context, in *UploadRequest, opts ...grpc.CallOption) (*Empty, error)
+	AddIdentity(ctx context.Context, in *AddIdentityRequest, opts ...grpc.CallOption) (*Empty, error)
+	Collect(ctx context.Context, in *CollectRequest, opts ...grpc.CallOption) (*CollectReply, error)
+	Revoke(ctx context.Context, in *RevokeRequest, opts ...grpc.CallOption) (*Empty, error)
+}
+
+type aPIGWServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAPIGWServiceClient(cc grpc.ClientConnInterface) APIGWServiceClient {
+	return &aPIGWServiceClient{cc}
+}
+
+func (c *aPIGWServiceClient) Upload(ctx context.Context, in *UploadRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, APIGWService_Upload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIGWServiceClient) AddIdentity(ctx context.Context, in *AddIdentityRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, APIGWService_AddIdentity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIGWServiceClient) Collect(ctx context.Context, in *CollectRequest, opts ...grpc.CallOption) (*CollectReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CollectReply)
+	err := c.cc.Invoke(ctx, APIGWService_Collect_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIGWServiceClient) Revoke(ctx context.Context, in *RevokeRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, APIGWService_Revoke_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// APIGWServiceServer is the server API for APIGWService service.
+// All implementations must embed UnimplementedAPIGWServiceServer
+// for forward compatibility.
+//
+// APIGWService mirrors the document and identity endpoints of the HTTP API.
+// Nested model structures are JSON encoded with the same schema as the HTTP API.
+type APIGWServiceServer interface {
+	Upload(context.Context, *UploadRequest) (*Empty, error)
+	AddIdentity(context.Context, *AddIdentityRequest) (*Empty, error)
+	Collect(context.Context, *CollectRequest) (*CollectReply, error)
+	Revoke(context.Context, *RevokeRequest) (*Empty, error)
+	mustEmbedUnimplementedAPIGWServiceServer()
+}
+
+// UnimplementedAPIGWServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAPIGWServiceServer struct{}
+
+func (UnimplementedAPIGWServiceServer) Upload(context.Context, *UploadRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Upload not implemented")
+}
+func (UnimplementedAPIGWServiceServer) AddIdentity(context.Context, *AddIdentityRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddIdentity not implemented")
+}
+func (UnimplementedAPIGWServiceServer) Collect(context.Context, *CollectRequest) (*CollectReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Collect not implemented")
+}
+func (UnimplementedAPIGWServiceServer) Revoke(context.Context, *RevokeRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Revoke not implemented")
+}
+func (UnimplementedAPIGWServiceServer) mustEmbedUnimplementedAPIGWServiceServer() {}
+func (UnimplementedAPIGWServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeAPIGWServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to APIGWServiceServer will
+// result in compilation errors.
+type UnsafeAPIGWServiceServer interface {
+	mustEmbedUnimplementedAPIGWServiceServer()
+}
+
+func RegisterAPIGWServiceServer(s grpc.ServiceRegistrar, srv APIGWServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAPIGWServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&APIGWService_ServiceDesc, srv)
+}
+
+func _APIGWService_Upload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIGWServiceServer).Upload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: APIGWService_Upload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIGWServiceServer).Upload(ctx, req.(*UploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _APIGWService_AddIdentity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddIdentityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIGWServiceServer).AddIdentity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: APIGWService_AddIdentity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIGWServiceServer).AddIdentity(ctx, req.(*AddIdentityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _APIGWService_Collect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CollectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIGWServiceServer).Collect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: APIGWService_Collect_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIGWServiceServer).Collect(ctx, req.(*CollectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _APIGWService_Revoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIGWServiceServer).Revoke(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: APIGWService_Revoke_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIGWServiceServer).Revoke(ctx, req.(*RevokeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// APIGWService_ServiceDesc is the grpc.ServiceDesc for APIGWService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var APIGWService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "v1.apigw.APIGWService",
+	HandlerType: (*APIGWServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Upload",
+			Handler:    _APIGWService_Upload_Handler,
+		},
+		{
+			MethodName: "AddIdentity",
+			Handler:    _APIGWService_AddIdentity_Handler,
+		},
+		{
+			MethodName: "Collect",
+			Handler:    _APIGWService_Collect_Handler,
+		},
+		{
+			MethodName: "Revoke",
+			Handler:    _APIGWService_Revoke_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "v1-apigw.proto",
+}
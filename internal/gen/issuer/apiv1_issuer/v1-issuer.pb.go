@@ -25,8 +25,17 @@ type MakeSDJWTRequest struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	DocumentType string `protobuf:"bytes,1,opt,name=documentType,proto3" json:"documentType,omitempty"`
-	DocumentData []byte `protobuf:"bytes,2,opt,name=documentData,proto3" json:"documentData,omitempty"`
+	DocumentType string   `protobuf:"bytes,1,opt,name=documentType,proto3" json:"documentType,omitempty"`
+	DocumentData []byte   `protobuf:"bytes,2,opt,name=documentData,proto3" json:"documentData,omitempty"`
+	ConsentIDs   []string `protobuf:"bytes,3,rep,name=consentIDs,proto3" json:"consentIDs,omitempty"`
+	// dryRun returns the unsigned payload instead of a signed jwt
+	DryRun bool `protobuf:"varint,4,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
+	// credentialType selects the credential profile, the profile of documentType if empty
+	CredentialType string `protobuf:"bytes,5,opt,name=credentialType,proto3" json:"credentialType,omitempty"`
+	// previousDisclosures are the disclosures of the credential this one refreshes, unchanged claims keep their digests
+	PreviousDisclosures []string `protobuf:"bytes,6,rep,name=previousDisclosures,proto3" json:"previousDisclosures,omitempty"`
+	// holderJWK is the holder public key of a verified proof of possession, the credential is bound to it in cnf
+	HolderJWK string `protobuf:"bytes,7,opt,name=holderJWK,proto3" json:"holderJWK,omitempty"`
 }
 
 func (x *MakeSDJWTRequest) Reset() {
@@ -75,6 +84,41 @@ func (x *MakeSDJWTRequest) GetDocumentData() []byte {
 	return nil
 }
 
+func (x *MakeSDJWTRequest) GetConsentIDs() []string {
+	if x != nil {
+		return x.ConsentIDs
+	}
+	return nil
+}
+
+func (x *MakeSDJWTRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *MakeSDJWTRequest) GetCredentialType() string {
+	if x != nil {
+		return x.CredentialType
+	}
+	return ""
+}
+
+func (x *MakeSDJWTRequest) GetPreviousDisclosures() []string {
+	if x != nil {
+		return x.PreviousDisclosures
+	}
+	return nil
+}
+
+func (x *MakeSDJWTRequest) GetHolderJWK() string {
+	if x != nil {
+		return x.HolderJWK
+	}
+	return ""
+}
+
 type MakeSDJWTReply struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -82,6 +126,12 @@ type MakeSDJWTReply struct {
 
 	Jwt         string   `protobuf:"bytes,1,opt,name=jwt,proto3" json:"jwt,omitempty"`
 	Disclosures []string `protobuf:"bytes,2,rep,name=disclosures,proto3" json:"disclosures,omitempty"`
+	// payload is the JSON encoded unsigned payload, set on a dry run
+	Payload string `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	// notificationID is set by the apigw, for the wallet to report what became of the credential
+	NotificationID string `protobuf:"bytes,4,opt,name=notificationID,proto3" json:"notificationID,omitempty"`
+	// transactionID is set by the apigw when the credential is deferred, for the wallet to collect it later
+	TransactionID string `protobuf:"bytes,5,opt,name=transactionID,proto3" json:"transactionID,omitempty"`
 }
 
 func (x *MakeSDJWTReply) Reset() {
@@ -130,6 +180,147 @@ func (x *MakeSDJWTReply) GetDisclosures() []string {
 	return nil
 }
 
+func (x *MakeSDJWTReply) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+func (x *MakeSDJWTReply) GetNotificationID() string {
+	if x != nil {
+		return x.NotificationID
+	}
+	return ""
+}
+
+func (x *MakeSDJWTReply) GetTransactionID() string {
+	if x != nil {
+		return x.TransactionID
+	}
+	return ""
+}
+
+type MakeSDJWTBatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// id identifies the item in the replies, which may come in another order than the requests
+	Id      string            `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Request *MakeSDJWTRequest `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"`
+}
+
+func (x *MakeSDJWTBatchRequest) Reset() {
+	*x = MakeSDJWTBatchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_issuer_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MakeSDJWTBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MakeSDJWTBatchRequest) ProtoMessage() {}
+
+func (x *MakeSDJWTBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_issuer_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MakeSDJWTBatchRequest.ProtoReflect.Descriptor instead.
+func (*MakeSDJWTBatchRequest) Descriptor() ([]byte, []int) {
+	return file_v1_issuer_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *MakeSDJWTBatchRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *MakeSDJWTBatchRequest) GetRequest() *MakeSDJWTRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+type MakeSDJWTBatchReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id    string          `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Reply *MakeSDJWTReply `protobuf:"bytes,2,opt,name=reply,proto3" json:"reply,omitempty"`
+	// error is set instead of reply when the credential of the item could not be made
+	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *MakeSDJWTBatchReply) Reset() {
+	*x = MakeSDJWTBatchReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_issuer_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MakeSDJWTBatchReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MakeSDJWTBatchReply) ProtoMessage() {}
+
+func (x *MakeSDJWTBatchReply) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_issuer_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MakeSDJWTBatchReply.ProtoReflect.Descriptor instead.
+func (*MakeSDJWTBatchReply) Descriptor() ([]byte, []int) {
+	return file_v1_issuer_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *MakeSDJWTBatchReply) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *MakeSDJWTBatchReply) GetReply() *MakeSDJWTReply {
+	if x != nil {
+		return x.Reply
+	}
+	return nil
+}
+
+func (x *MakeSDJWTBatchReply) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 type Empty struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -139,7 +330,7 @@ type Empty struct {
 func (x *Empty) Reset() {
 	*x = Empty{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_v1_issuer_proto_msgTypes[2]
+		mi := &file_v1_issuer_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -152,7 +343,7 @@ func (x *Empty) String() string {
 func (*Empty) ProtoMessage() {}
 
 func (x *Empty) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_issuer_proto_msgTypes[2]
+	mi := &file_v1_issuer_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -165,7 +356,7 @@ func (x *Empty) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Empty.ProtoReflect.Descriptor instead.
 func (*Empty) Descriptor() ([]byte, []int) {
-	return file_v1_issuer_proto_rawDescGZIP(), []int{2}
+	return file_v1_issuer_proto_rawDescGZIP(), []int{4}
 }
 
 type JwksReply struct {
@@ -180,7 +371,7 @@ type JwksReply struct {
 func (x *JwksReply) Reset() {
 	*x = JwksReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_v1_issuer_proto_msgTypes[3]
+		mi := &file_v1_issuer_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -193,7 +384,7 @@ func (x *JwksReply) String() string {
 func (*JwksReply) ProtoMessage() {}
 
 func (x *JwksReply) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_issuer_proto_msgTypes[3]
+	mi := &file_v1_issuer_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -206,7 +397,7 @@ func (x *JwksReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use JwksReply.ProtoReflect.Descriptor instead.
 func (*JwksReply) Descriptor() ([]byte, []int) {
-	return file_v1_issuer_proto_rawDescGZIP(), []int{3}
+	return file_v1_issuer_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *JwksReply) GetIssuer() string {
@@ -234,7 +425,7 @@ type Keys struct {
 func (x *Keys) Reset() {
 	*x = Keys{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_v1_issuer_proto_msgTypes[4]
+		mi := &file_v1_issuer_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -247,7 +438,7 @@ func (x *Keys) String() string {
 func (*Keys) ProtoMessage() {}
 
 func (x *Keys) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_issuer_proto_msgTypes[4]
+	mi := &file_v1_issuer_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -260,7 +451,7 @@ func (x *Keys) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Keys.ProtoReflect.Descriptor instead.
 func (*Keys) Descriptor() ([]byte, []int) {
-	return file_v1_issuer_proto_rawDescGZIP(), []int{4}
+	return file_v1_issuer_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *Keys) GetKeys() []*Jwk {
@@ -281,12 +472,16 @@ type Jwk struct {
 	X   string `protobuf:"bytes,4,opt,name=x,proto3" json:"x,omitempty"`
 	Y   string `protobuf:"bytes,5,opt,name=y,proto3" json:"y,omitempty"`
 	D   string `protobuf:"bytes,6,opt,name=d,proto3" json:"d,omitempty"`
+	Alg string `protobuf:"bytes,7,opt,name=alg,proto3" json:"alg,omitempty"`
+	Use string `protobuf:"bytes,8,opt,name=use,proto3" json:"use,omitempty"`
+	N   string `protobuf:"bytes,9,opt,name=n,proto3" json:"n,omitempty"`
+	E   string `protobuf:"bytes,10,opt,name=e,proto3" json:"e,omitempty"`
 }
 
 func (x *Jwk) Reset() {
 	*x = Jwk{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_v1_issuer_proto_msgTypes[5]
+		mi := &file_v1_issuer_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -299,7 +494,7 @@ func (x *Jwk) String() string {
 func (*Jwk) ProtoMessage() {}
 
 func (x *Jwk) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_issuer_proto_msgTypes[5]
+	mi := &file_v1_issuer_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -312,7 +507,7 @@ func (x *Jwk) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Jwk.ProtoReflect.Descriptor instead.
 func (*Jwk) Descriptor() ([]byte, []int) {
-	return file_v1_issuer_proto_rawDescGZIP(), []int{5}
+	return file_v1_issuer_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *Jwk) GetKid() string {
@@ -357,47 +552,116 @@ func (x *Jwk) GetD() string {
 	return ""
 }
 
+func (x *Jwk) GetAlg() string {
+	if x != nil {
+		return x.Alg
+	}
+	return ""
+}
+
+func (x *Jwk) GetUse() string {
+	if x != nil {
+		return x.Use
+	}
+	return ""
+}
+
+func (x *Jwk) GetN() string {
+	if x != nil {
+		return x.N
+	}
+	return ""
+}
+
+func (x *Jwk) GetE() string {
+	if x != nil {
+		return x.E
+	}
+	return ""
+}
+
 var File_v1_issuer_proto protoreflect.FileDescriptor
 
 var file_v1_issuer_proto_rawDesc = []byte{
 	0x0a, 0x0f, 0x76, 0x31, 0x2d, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x12, 0x09, 0x76, 0x31, 0x2e, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x22, 0x5a, 0x0a, 0x10,
-	0x4d, 0x61, 0x6b, 0x65, 0x53, 0x44, 0x4a, 0x57, 0x54, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x22, 0x0a, 0x0c, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74,
-	0x54, 0x79, 0x70, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74,
-	0x44, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x64, 0x6f, 0x63, 0x75,
-	0x6d, 0x65, 0x6e, 0x74, 0x44, 0x61, 0x74, 0x61, 0x22, 0x44, 0x0a, 0x0e, 0x4d, 0x61, 0x6b, 0x65,
-	0x53, 0x44, 0x4a, 0x57, 0x54, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6a, 0x77,
-	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6a, 0x77, 0x74, 0x12, 0x20, 0x0a, 0x0b,
-	0x64, 0x69, 0x73, 0x63, 0x6c, 0x6f, 0x73, 0x75, 0x72, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
-	0x09, 0x52, 0x0b, 0x64, 0x69, 0x73, 0x63, 0x6c, 0x6f, 0x73, 0x75, 0x72, 0x65, 0x73, 0x22, 0x07,
-	0x0a, 0x05, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x48, 0x0a, 0x09, 0x4a, 0x77, 0x6b, 0x73, 0x52,
-	0x65, 0x70, 0x6c, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x12, 0x23, 0x0a, 0x04,
-	0x6a, 0x77, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x76, 0x31, 0x2e,
-	0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x2e, 0x6b, 0x65, 0x79, 0x73, 0x52, 0x04, 0x6a, 0x77, 0x6b,
-	0x73, 0x22, 0x2a, 0x0a, 0x04, 0x6b, 0x65, 0x79, 0x73, 0x12, 0x22, 0x0a, 0x04, 0x6b, 0x65, 0x79,
-	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x76, 0x31, 0x2e, 0x69, 0x73, 0x73,
-	0x75, 0x65, 0x72, 0x2e, 0x6a, 0x77, 0x6b, 0x52, 0x04, 0x6b, 0x65, 0x79, 0x73, 0x22, 0x65, 0x0a,
-	0x03, 0x6a, 0x77, 0x6b, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x03, 0x6b, 0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x72, 0x76, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x03, 0x63, 0x72, 0x76, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x74, 0x79, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x74, 0x79, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x01, 0x78, 0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18, 0x05, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x01, 0x79, 0x12, 0x0c, 0x0a, 0x01, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x01, 0x64, 0x32, 0x88, 0x01, 0x0a, 0x0d, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x53,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x45, 0x0a, 0x09, 0x4d, 0x61, 0x6b, 0x65, 0x53, 0x44,
-	0x4a, 0x57, 0x54, 0x12, 0x1b, 0x2e, 0x76, 0x31, 0x2e, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x2e,
-	0x4d, 0x61, 0x6b, 0x65, 0x53, 0x44, 0x4a, 0x57, 0x54, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x19, 0x2e, 0x76, 0x31, 0x2e, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x2e, 0x4d, 0x61, 0x6b,
-	0x65, 0x53, 0x44, 0x4a, 0x57, 0x54, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x30, 0x0a,
-	0x04, 0x4a, 0x57, 0x4b, 0x53, 0x12, 0x10, 0x2e, 0x76, 0x31, 0x2e, 0x69, 0x73, 0x73, 0x75, 0x65,
-	0x72, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x14, 0x2e, 0x76, 0x31, 0x2e, 0x69, 0x73, 0x73,
-	0x75, 0x65, 0x72, 0x2e, 0x4a, 0x77, 0x6b, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x42,
-	0x25, 0x5a, 0x23, 0x76, 0x63, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x67,
-	0x65, 0x6e, 0x2f, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x2f, 0x61, 0x70, 0x69, 0x76, 0x31, 0x5f,
-	0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6f, 0x12, 0x09, 0x76, 0x31, 0x2e, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x22, 0x8a, 0x02, 0x0a,
+	0x10, 0x4d, 0x61, 0x6b, 0x65, 0x53, 0x44, 0x4a, 0x57, 0x54, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x22, 0x0a, 0x0c, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e,
+	0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e,
+	0x74, 0x44, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x64, 0x6f, 0x63,
+	0x75, 0x6d, 0x65, 0x6e, 0x74, 0x44, 0x61, 0x74, 0x61, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6e,
+	0x73, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x63,
+	0x6f, 0x6e, 0x73, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x72, 0x79,
+	0x52, 0x75, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x64, 0x72, 0x79, 0x52, 0x75,
+	0x6e, 0x12, 0x26, 0x0a, 0x0e, 0x63, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x54,
+	0x79, 0x70, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x63, 0x72, 0x65, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x61, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x12, 0x30, 0x0a, 0x13, 0x70, 0x72, 0x65,
+	0x76, 0x69, 0x6f, 0x75, 0x73, 0x44, 0x69, 0x73, 0x63, 0x6c, 0x6f, 0x73, 0x75, 0x72, 0x65, 0x73,
+	0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x13, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73,
+	0x44, 0x69, 0x73, 0x63, 0x6c, 0x6f, 0x73, 0x75, 0x72, 0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x68,
+	0x6f, 0x6c, 0x64, 0x65, 0x72, 0x4a, 0x57, 0x4b, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x68, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x4a, 0x57, 0x4b, 0x22, 0xac, 0x01, 0x0a, 0x0e, 0x4d, 0x61,
+	0x6b, 0x65, 0x53, 0x44, 0x4a, 0x57, 0x54, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6a, 0x77, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6a, 0x77, 0x74, 0x12, 0x20,
+	0x0a, 0x0b, 0x64, 0x69, 0x73, 0x63, 0x6c, 0x6f, 0x73, 0x75, 0x72, 0x65, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x69, 0x73, 0x63, 0x6c, 0x6f, 0x73, 0x75, 0x72, 0x65, 0x73,
+	0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x26, 0x0a, 0x0e, 0x6e, 0x6f,
+	0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0e, 0x6e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x49, 0x44, 0x12, 0x24, 0x0a, 0x0d, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x49, 0x44, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x74, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x22, 0x5e, 0x0a, 0x15, 0x4d, 0x61, 0x6b, 0x65,
+	0x53, 0x44, 0x4a, 0x57, 0x54, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69,
+	0x64, 0x12, 0x35, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x76, 0x31, 0x2e, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x2e, 0x4d,
+	0x61, 0x6b, 0x65, 0x53, 0x44, 0x4a, 0x57, 0x54, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52,
+	0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x6c, 0x0a, 0x13, 0x4d, 0x61, 0x6b, 0x65,
+	0x53, 0x44, 0x4a, 0x57, 0x54, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x2f, 0x0a, 0x05, 0x72, 0x65, 0x70, 0x6c, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x76, 0x31, 0x2e, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x2e, 0x4d, 0x61, 0x6b, 0x65, 0x53,
+	0x44, 0x4a, 0x57, 0x54, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x52, 0x05, 0x72, 0x65, 0x70, 0x6c, 0x79,
+	0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x07, 0x0a, 0x05, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22,
+	0x48, 0x0a, 0x09, 0x4a, 0x77, 0x6b, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x16, 0x0a, 0x06,
+	0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x69, 0x73,
+	0x73, 0x75, 0x65, 0x72, 0x12, 0x23, 0x0a, 0x04, 0x6a, 0x77, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x76, 0x31, 0x2e, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x2e, 0x6b,
+	0x65, 0x79, 0x73, 0x52, 0x04, 0x6a, 0x77, 0x6b, 0x73, 0x22, 0x2a, 0x0a, 0x04, 0x6b, 0x65, 0x79,
+	0x73, 0x12, 0x22, 0x0a, 0x04, 0x6b, 0x65, 0x79, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x0e, 0x2e, 0x76, 0x31, 0x2e, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x2e, 0x6a, 0x77, 0x6b, 0x52,
+	0x04, 0x6b, 0x65, 0x79, 0x73, 0x22, 0xa5, 0x01, 0x0a, 0x03, 0x6a, 0x77, 0x6b, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x69, 0x64, 0x12,
+	0x10, 0x0a, 0x03, 0x63, 0x72, 0x76, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x63, 0x72,
+	0x76, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x74, 0x79, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x01,
+	0x78, 0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x01, 0x79, 0x12,
+	0x0c, 0x0a, 0x01, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x01, 0x64, 0x12, 0x10, 0x0a,
+	0x03, 0x61, 0x6c, 0x67, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x61, 0x6c, 0x67, 0x12,
+	0x10, 0x0a, 0x03, 0x75, 0x73, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x73,
+	0x65, 0x12, 0x0c, 0x0a, 0x01, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x01, 0x6e, 0x12,
+	0x0c, 0x0a, 0x01, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x01, 0x65, 0x32, 0xe2, 0x01,
+	0x0a, 0x0d, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12,
+	0x45, 0x0a, 0x09, 0x4d, 0x61, 0x6b, 0x65, 0x53, 0x44, 0x4a, 0x57, 0x54, 0x12, 0x1b, 0x2e, 0x76,
+	0x31, 0x2e, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x2e, 0x4d, 0x61, 0x6b, 0x65, 0x53, 0x44, 0x4a,
+	0x57, 0x54, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x76, 0x31, 0x2e, 0x69,
+	0x73, 0x73, 0x75, 0x65, 0x72, 0x2e, 0x4d, 0x61, 0x6b, 0x65, 0x53, 0x44, 0x4a, 0x57, 0x54, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x58, 0x0a, 0x0e, 0x4d, 0x61, 0x6b, 0x65, 0x53, 0x44,
+	0x4a, 0x57, 0x54, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x20, 0x2e, 0x76, 0x31, 0x2e, 0x69, 0x73,
+	0x73, 0x75, 0x65, 0x72, 0x2e, 0x4d, 0x61, 0x6b, 0x65, 0x53, 0x44, 0x4a, 0x57, 0x54, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x76, 0x31, 0x2e,
+	0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x2e, 0x4d, 0x61, 0x6b, 0x65, 0x53, 0x44, 0x4a, 0x57, 0x54,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01,
+	0x12, 0x30, 0x0a, 0x04, 0x4a, 0x57, 0x4b, 0x53, 0x12, 0x10, 0x2e, 0x76, 0x31, 0x2e, 0x69, 0x73,
+	0x73, 0x75, 0x65, 0x72, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x14, 0x2e, 0x76, 0x31, 0x2e,
+	0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x2e, 0x4a, 0x77, 0x6b, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x22, 0x00, 0x42, 0x25, 0x5a, 0x23, 0x76, 0x63, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61,
+	0x6c, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x2f, 0x61, 0x70, 0x69,
+	0x76, 0x31, 0x5f, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
 }
 
 var (
@@ -412,27 +676,33 @@ func file_v1_issuer_proto_rawDescGZIP() []byte {
 	return file_v1_issuer_proto_rawDescData
 }
 
-var file_v1_issuer_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_v1_issuer_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_v1_issuer_proto_goTypes = []any{
-	(*MakeSDJWTRequest)(nil), // 0: v1.issuer.MakeSDJWTRequest
-	(*MakeSDJWTReply)(nil),   // 1: v1.issuer.MakeSDJWTReply
-	(*Empty)(nil),            // 2: v1.issuer.Empty
-	(*JwksReply)(nil),        // 3: v1.issuer.JwksReply
-	(*Keys)(nil),             // 4: v1.issuer.keys
-	(*Jwk)(nil),              // 5: v1.issuer.jwk
+	(*MakeSDJWTRequest)(nil),      // 0: v1.issuer.MakeSDJWTRequest
+	(*MakeSDJWTReply)(nil),        // 1: v1.issuer.MakeSDJWTReply
+	(*MakeSDJWTBatchRequest)(nil), // 2: v1.issuer.MakeSDJWTBatchRequest
+	(*MakeSDJWTBatchReply)(nil),   // 3: v1.issuer.MakeSDJWTBatchReply
+	(*Empty)(nil),                 // 4: v1.issuer.Empty
+	(*JwksReply)(nil),             // 5: v1.issuer.JwksReply
+	(*Keys)(nil),                  // 6: v1.issuer.keys
+	(*Jwk)(nil),                   // 7: v1.issuer.jwk
 }
 var file_v1_issuer_proto_depIdxs = []int32{
-	4, // 0: v1.issuer.JwksReply.jwks:type_name -> v1.issuer.keys
-	5, // 1: v1.issuer.keys.keys:type_name -> v1.issuer.jwk
-	0, // 2: v1.issuer.IssuerService.MakeSDJWT:input_type -> v1.issuer.MakeSDJWTRequest
-	2, // 3: v1.issuer.IssuerService.JWKS:input_type -> v1.issuer.Empty
-	1, // 4: v1.issuer.IssuerService.MakeSDJWT:output_type -> v1.issuer.MakeSDJWTReply
-	3, // 5: v1.issuer.IssuerService.JWKS:output_type -> v1.issuer.JwksReply
-	4, // [4:6] is the sub-list for method output_type
-	2, // [2:4] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	0, // 0: v1.issuer.MakeSDJWTBatchRequest.request:type_name -> v1.issuer.MakeSDJWTRequest
+	1, // 1: v1.issuer.MakeSDJWTBatchReply.reply:type_name -> v1.issuer.MakeSDJWTReply
+	6, // 2: v1.issuer.JwksReply.jwks:type_name -> v1.issuer.keys
+	7, // 3: v1.issuer.keys.keys:type_name -> v1.issuer.jwk
+	0, // 4: v1.issuer.IssuerService.MakeSDJWT:input_type -> v1.issuer.MakeSDJWTRequest
+	2, // 5: v1.issuer.IssuerService.MakeSDJWTBatch:input_type -> v1.issuer.MakeSDJWTBatchRequest
+	4, // 6: v1.issuer.IssuerService.JWKS:input_type -> v1.issuer.Empty
+	1, // 7: v1.issuer.IssuerService.MakeSDJWT:output_type -> v1.issuer.MakeSDJWTReply
+	3, // 8: v1.issuer.IssuerService.MakeSDJWTBatch:output_type -> v1.issuer.MakeSDJWTBatchReply
+	5, // 9: v1.issuer.IssuerService.JWKS:output_type -> v1.issuer.JwksReply
+	7, // [7:10] is the sub-list for method output_type
+	4, // [4:7] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_v1_issuer_proto_init() }
@@ -466,7 +736,7 @@ func file_v1_issuer_proto_init() {
 			}
 		}
 		file_v1_issuer_proto_msgTypes[2].Exporter = func(v any, i int) any {
-			switch v := v.(*Empty); i {
+			switch v := v.(*MakeSDJWTBatchRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -478,7 +748,7 @@ func file_v1_issuer_proto_init() {
 			}
 		}
 		file_v1_issuer_proto_msgTypes[3].Exporter = func(v any, i int) any {
-			switch v := v.(*JwksReply); i {
+			switch v := v.(*MakeSDJWTBatchReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -490,7 +760,7 @@ func file_v1_issuer_proto_init() {
 			}
 		}
 		file_v1_issuer_proto_msgTypes[4].Exporter = func(v any, i int) any {
-			switch v := v.(*Keys); i {
+			switch v := v.(*Empty); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -502,6 +772,30 @@ func file_v1_issuer_proto_init() {
 			}
 		}
 		file_v1_issuer_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*JwksReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_issuer_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*Keys); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_issuer_proto_msgTypes[7].Exporter = func(v any, i int) any {
 			switch v := v.(*Jwk); i {
 			case 0:
 				return &v.state
@@ -520,7 +814,7 @@ func file_v1_issuer_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_v1_issuer_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,8 +19,9 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	IssuerService_MakeSDJWT_FullMethodName = "/v1.issuer.IssuerService/MakeSDJWT"
-	IssuerService_JWKS_FullMethodName      = "/v1.issuer.IssuerService/JWKS"
+	IssuerService_MakeSDJWT_FullMethodName      = "/v1.issuer.IssuerService/MakeSDJWT"
+	IssuerService_MakeSDJWTBatch_FullMethodName = "/v1.issuer.IssuerService/MakeSDJWTBatch"
+	IssuerService_JWKS_FullMethodName           = "/v1.issuer.IssuerService/JWKS"
 )
 
 // IssuerServiceClient is the client API for IssuerService service.
@@ -28,6 +29,9 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type IssuerServiceClient interface {
 	MakeSDJWT(ctx context.Context, in *MakeSDJWTRequest, opts ...grpc.CallOption) (*MakeSDJWTReply, error)
+	// MakeSDJWTBatch makes the credentials of a stream of requests concurrently, a reply is streamed back for each
+	// request as soon as it is done
+	MakeSDJWTBatch(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[MakeSDJWTBatchRequest, MakeSDJWTBatchReply], error)
 	JWKS(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*JwksReply, error)
 }
 
@@ -49,6 +53,19 @@ func (c *issuerServiceClient) MakeSDJWT(ctx context.Context, in *MakeSDJWTReques
 	return out, nil
 }
 
+func (c *issuerServiceClient) MakeSDJWTBatch(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[MakeSDJWTBatchRequest, MakeSDJWTBatchReply], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &IssuerService_ServiceDesc.Streams[0], IssuerService_MakeSDJWTBatch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[MakeSDJWTBatchRequest, MakeSDJWTBatchReply]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type IssuerService_MakeSDJWTBatchClient = grpc.BidiStreamingClient[MakeSDJWTBatchRequest, MakeSDJWTBatchReply]
+
 func (c *issuerServiceClient) JWKS(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*JwksReply, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(JwksReply)
@@ -64,6 +81,9 @@ func (c *issuerServiceClient) JWKS(ctx context.Context, in *Empty, opts ...grpc.
 // for forward compatibility.
 type IssuerServiceServer interface {
 	MakeSDJWT(context.Context, *MakeSDJWTRequest) (*MakeSDJWTReply, error)
+	// MakeSDJWTBatch makes the credentials of a stream of requests concurrently, a reply is streamed back for each
+	// request as soon as it is done
+	MakeSDJWTBatch(grpc.BidiStreamingServer[MakeSDJWTBatchRequest, MakeSDJWTBatchReply]) error
 	JWKS(context.Context, *Empty) (*JwksReply, error)
 	mustEmbedUnimplementedIssuerServiceServer()
 }
@@ -78,6 +98,9 @@ type UnimplementedIssuerServiceServer struct{}
 func (UnimplementedIssuerServiceServer) MakeSDJWT(context.Context, *MakeSDJWTRequest) (*MakeSDJWTReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method MakeSDJWT not implemented")
 }
+func (UnimplementedIssuerServiceServer) MakeSDJWTBatch(grpc.BidiStreamingServer[MakeSDJWTBatchRequest, MakeSDJWTBatchReply]) error {
+	return status.Errorf(codes.Unimplemented, "method MakeSDJWTBatch not implemented")
+}
 func (UnimplementedIssuerServiceServer) JWKS(context.Context, *Empty) (*JwksReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method JWKS not implemented")
 }
@@ -120,6 +143,13 @@ func _IssuerService_MakeSDJWT_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _IssuerService_MakeSDJWTBatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(IssuerServiceServer).MakeSDJWTBatch(&grpc.GenericServerStream[MakeSDJWTBatchRequest, MakeSDJWTBatchReply]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type IssuerService_MakeSDJWTBatchServer = grpc.BidiStreamingServer[MakeSDJWTBatchRequest, MakeSDJWTBatchReply]
+
 func _IssuerService_JWKS_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Empty)
 	if err := dec(in); err != nil {
@@ -154,6 +184,13 @@ var IssuerService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _IssuerService_JWKS_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "MakeSDJWTBatch",
+			Handler:       _IssuerService_MakeSDJWTBatch_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
 	Metadata: "v1-issuer.proto",
 }
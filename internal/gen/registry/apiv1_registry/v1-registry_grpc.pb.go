@@ -22,8 +22,10 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	RegistryService_Add_FullMethodName      = "/v1.registry.RegistryService/Add"
 	RegistryService_Revoke_FullMethodName   = "/v1.registry.RegistryService/Revoke"
+	RegistryService_Suspend_FullMethodName  = "/v1.registry.RegistryService/Suspend"
 	RegistryService_Validate_FullMethodName = "/v1.registry.RegistryService/Validate"
 	RegistryService_Status_FullMethodName   = "/v1.registry.RegistryService/Status"
+	RegistryService_Watch_FullMethodName    = "/v1.registry.RegistryService/Watch"
 )
 
 // RegistryServiceClient is the client API for RegistryService service.
@@ -32,8 +34,10 @@ const (
 type RegistryServiceClient interface {
 	Add(ctx context.Context, in *AddRequest, opts ...grpc.CallOption) (*AddReply, error)
 	Revoke(ctx context.Context, in *RevokeRequest, opts ...grpc.CallOption) (*RevokeReply, error)
+	Suspend(ctx context.Context, in *SuspendRequest, opts ...grpc.CallOption) (*SuspendReply, error)
 	Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateReply, error)
 	Status(ctx context.Context, in *apiv1_status.StatusRequest, opts ...grpc.CallOption) (*apiv1_status.StatusReply, error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StatusEvent], error)
 }
 
 type registryServiceClient struct {
@@ -64,6 +68,16 @@ func (c *registryServiceClient) Revoke(ctx context.Context, in *RevokeRequest, o
 	return out, nil
 }
 
+func (c *registryServiceClient) Suspend(ctx context.Context, in *SuspendRequest, opts ...grpc.CallOption) (*SuspendReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SuspendReply)
+	err := c.cc.Invoke(ctx, RegistryService_Suspend_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *registryServiceClient) Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateReply, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ValidateReply)
@@ -84,14 +98,35 @@ func (c *registryServiceClient) Status(ctx context.Context, in *apiv1_status.Sta
 	return out, nil
 }
 
+func (c *registryServiceClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StatusEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &RegistryService_ServiceDesc.Streams[0], RegistryService_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, StatusEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RegistryService_WatchClient = grpc.ServerStreamingClient[StatusEvent]
+
 // RegistryServiceServer is the server API for RegistryService service.
 // All implementations must embed UnimplementedRegistryServiceServer
 // for forward compatibility.
 type RegistryServiceServer interface {
 	Add(context.Context, *AddRequest) (*AddReply, error)
 	Revoke(context.Context, *RevokeRequest) (*RevokeReply, error)
+	Suspend(context.Context, *SuspendRequest) (*SuspendReply, error)
 	Validate(context.Context, *ValidateRequest) (*ValidateReply, error)
 	Status(context.Context, *apiv1_status.StatusRequest) (*apiv1_status.StatusReply, error)
+	Watch(*WatchRequest, grpc.ServerStreamingServer[StatusEvent]) error
 	mustEmbedUnimplementedRegistryServiceServer()
 }
 
@@ -108,12 +143,18 @@ func (UnimplementedRegistryServiceServer) Add(context.Context, *AddRequest) (*Ad
 func (UnimplementedRegistryServiceServer) Revoke(context.Context, *RevokeRequest) (*RevokeReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Revoke not implemented")
 }
+func (UnimplementedRegistryServiceServer) Suspend(context.Context, *SuspendRequest) (*SuspendReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Suspend not implemented")
+}
 func (UnimplementedRegistryServiceServer) Validate(context.Context, *ValidateRequest) (*ValidateReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Validate not implemented")
 }
 func (UnimplementedRegistryServiceServer) Status(context.Context, *apiv1_status.StatusRequest) (*apiv1_status.StatusReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Status not implemented")
 }
+func (UnimplementedRegistryServiceServer) Watch(*WatchRequest, grpc.ServerStreamingServer[StatusEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
 func (UnimplementedRegistryServiceServer) mustEmbedUnimplementedRegistryServiceServer() {}
 func (UnimplementedRegistryServiceServer) testEmbeddedByValue()                         {}
 
@@ -171,6 +212,24 @@ func _RegistryService_Revoke_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RegistryService_Suspend_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuspendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServiceServer).Suspend(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RegistryService_Suspend_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServiceServer).Suspend(ctx, req.(*SuspendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _RegistryService_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ValidateRequest)
 	if err := dec(in); err != nil {
@@ -207,6 +266,17 @@ func _RegistryService_Status_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RegistryService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RegistryServiceServer).Watch(m, &grpc.GenericServerStream[WatchRequest, StatusEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RegistryService_WatchServer = grpc.ServerStreamingServer[StatusEvent]
+
 // RegistryService_ServiceDesc is the grpc.ServiceDesc for RegistryService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -222,6 +292,10 @@ var RegistryService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Revoke",
 			Handler:    _RegistryService_Revoke_Handler,
 		},
+		{
+			MethodName: "Suspend",
+			Handler:    _RegistryService_Suspend_Handler,
+		},
 		{
 			MethodName: "Validate",
 			Handler:    _RegistryService_Validate_Handler,
@@ -231,6 +305,12 @@ var RegistryService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _RegistryService_Status_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _RegistryService_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "v1-registry.proto",
 }
@@ -0,0 +1,65 @@
+package apiv1
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchItem is one credential of a batch
+type BatchItem struct {
+	// ID identifies the item in the results
+	ID      string
+	Request *CreateCredentialRequest
+}
+
+// BatchResult is the outcome of one item of a batch, Err is set instead of Reply if the credential could not be made
+type BatchResult struct {
+	ID    string
+	Reply *CreateCredentialReply
+	Err   error
+}
+
+// MakeSDJWTBatch makes the credentials of items with a pool of issuer.batch.workers workers, so that building,
+// hashing and signing of many credentials overlap. The result of each item is sent to results as soon as it is done,
+// not in the order of items. It returns when items is closed and every result is sent, or when ctx is done.
+func (c *Client) MakeSDJWTBatch(ctx context.Context, items <-chan *BatchItem, results chan<- *BatchResult) {
+	ctx, span := c.tracer.Start(ctx, "apiv1:MakeSDJWTBatch")
+	defer span.End()
+
+	runBatch(ctx, c.cfg.Issuer.Batch.Workers, items, results, c.MakeSDJWT)
+}
+
+// runBatch runs makeCredential for each item in workers goroutines, at least one
+func runBatch(ctx context.Context, workers int, items <-chan *BatchItem, results chan<- *BatchResult, makeCredential func(context.Context, *CreateCredentialRequest) (*CreateCredentialReply, error)) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				var item *BatchItem
+				select {
+				case <-ctx.Done():
+					return
+				case next, ok := <-items:
+					if !ok {
+						return
+					}
+					item = next
+				}
+
+				reply, err := makeCredential(ctx, item.Request)
+				select {
+				case <-ctx.Done():
+					return
+				case results <- &BatchResult{ID: item.ID, Reply: reply, Err: err}:
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
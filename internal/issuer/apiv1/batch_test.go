@@ -0,0 +1,92 @@
+package apiv1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunBatch(t *testing.T) {
+	errUnknown := errors.New("unknown document type")
+
+	var running, maxRunning atomic.Int64
+	makeCredential := func(ctx context.Context, req *CreateCredentialRequest) (*CreateCredentialReply, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+
+		if req.DocumentType != "EHIC" {
+			return nil, errUnknown
+		}
+		return &CreateCredentialReply{}, nil
+	}
+
+	items := make(chan *BatchItem)
+	results := make(chan *BatchResult)
+	go func() {
+		defer close(items)
+		for i := 0; i < 20; i++ {
+			documentType := "EHIC"
+			if i%5 == 0 {
+				documentType = "unknown"
+			}
+			items <- &BatchItem{ID: fmt.Sprint(i), Request: &CreateCredentialRequest{DocumentType: documentType}}
+		}
+	}()
+	go func() {
+		defer close(results)
+		runBatch(context.Background(), 4, items, results, makeCredential)
+	}()
+
+	got := map[string]error{}
+	for result := range results {
+		if result.Err == nil {
+			assert.NotNil(t, result.Reply)
+		}
+		got[result.ID] = result.Err
+	}
+
+	assert.Len(t, got, 20)
+	for i := 0; i < 20; i++ {
+		if i%5 == 0 {
+			assert.Equal(t, errUnknown, got[fmt.Sprint(i)])
+		} else {
+			assert.NoError(t, got[fmt.Sprint(i)])
+		}
+	}
+	assert.LessOrEqual(t, maxRunning.Load(), int64(4))
+}
+
+func TestRunBatchCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	items := make(chan *BatchItem, 1)
+	items <- &BatchItem{ID: "1", Request: &CreateCredentialRequest{}}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// nobody reads results, the workers must still return when ctx is done
+		runBatch(ctx, 2, items, make(chan *BatchResult), func(context.Context, *CreateCredentialRequest) (*CreateCredentialReply, error) {
+			return &CreateCredentialReply{}, nil
+		})
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("runBatch did not return when ctx was done")
+	}
+}
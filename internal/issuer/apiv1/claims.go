@@ -0,0 +1,86 @@
+package apiv1
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"vc/pkg/model"
+	"vc/pkg/sdjwt"
+)
+
+// mapClaims maps documentData to claims by the claim mapping of a credential profile, claims without a value in
+// documentData are left out
+func mapClaims(mapping []model.ClaimMapping, documentData []byte) (sdjwt.InstructionsV2, error) {
+	data := map[string]any{}
+	if err := json.Unmarshal(documentData, &data); err != nil {
+		return nil, err
+	}
+
+	instruction := sdjwt.InstructionsV2{}
+	for _, claim := range mapping {
+		value, ok := jsonPointer(data, claim.Path)
+		if !ok {
+			continue
+		}
+
+		names := strings.Split(claim.Claim, ".")
+		children := &instruction
+		for _, name := range names[:len(names)-1] {
+			children = parentChildren(children, name)
+		}
+		*children = append(*children, &sdjwt.ChildInstructionV2{
+			Name:                names[len(names)-1],
+			Value:               value,
+			SelectiveDisclosure: claim.SelectiveDisclosure,
+		})
+	}
+
+	return instruction, nil
+}
+
+// parentChildren returns the children of the parent claim name in instructions, the parent is added if missing
+func parentChildren(instructions *sdjwt.InstructionsV2, name string) *sdjwt.InstructionsV2 {
+	for _, instruction := range *instructions {
+		if parent, ok := instruction.(*sdjwt.ParentInstructionV2); ok && parent.Name == name {
+			return (*sdjwt.InstructionsV2)(&parent.Children)
+		}
+	}
+
+	parent := &sdjwt.ParentInstructionV2{Name: name}
+	*instructions = append(*instructions, parent)
+	return (*sdjwt.InstructionsV2)(&parent.Children)
+}
+
+// jsonPointer returns the value at pointer in data, RFC 6901
+func jsonPointer(data any, pointer string) (any, bool) {
+	if pointer == "" {
+		return data, true
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, false
+	}
+
+	value := data
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+
+		switch v := value.(type) {
+		case map[string]any:
+			next, ok := v[token]
+			if !ok {
+				return nil, false
+			}
+			value = next
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return value, value != nil
+}
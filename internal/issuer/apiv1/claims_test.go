@@ -0,0 +1,61 @@
+package apiv1
+
+import (
+	"testing"
+	"vc/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var mockClaimsDocumentData = []byte(`{
+	"title": "Master of Science",
+	"credentialSubject": {"name": "Alice", "birthDate": "1990-01-01"},
+	"achievements": [{"title": "Thesis"}],
+	"a/b": "escaped"
+}`)
+
+func TestMapClaims(t *testing.T) {
+	tts := []struct {
+		name    string
+		mapping []model.ClaimMapping
+		want    map[string]any
+	}{
+		{
+			name: "flat",
+			mapping: []model.ClaimMapping{
+				{Claim: "title", Path: "/title"},
+				{Claim: "first_achievement", Path: "/achievements/0/title"},
+				{Claim: "escaped", Path: "/a~1b"},
+			},
+			want: map[string]any{"title": "Master of Science", "first_achievement": "Thesis", "escaped": "escaped"},
+		},
+		{
+			name: "nested",
+			mapping: []model.ClaimMapping{
+				{Claim: "student.name", Path: "/credentialSubject/name", SelectiveDisclosure: true},
+				{Claim: "student.birth_date", Path: "/credentialSubject/birthDate", SelectiveDisclosure: true},
+			},
+			want: map[string]any{"student": map[string]any{"name": "Alice", "birth_date": "1990-01-01"}},
+		},
+		{
+			name: "missing values are left out",
+			mapping: []model.ClaimMapping{
+				{Claim: "title", Path: "/title"},
+				{Claim: "grade", Path: "/grade"},
+				{Claim: "second_achievement", Path: "/achievements/1/title"},
+			},
+			want: map[string]any{"title": "Master of Science"},
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			instruction, err := mapClaims(tt.mapping, mockClaimsDocumentData)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, instruction.Claims())
+		})
+	}
+
+	_, err := mapClaims(nil, []byte("not json"))
+	assert.Error(t, err)
+}
@@ -2,15 +2,19 @@ package apiv1
 
 import (
 	"context"
-	"crypto/ecdsa"
-	"os"
+	"errors"
+	"fmt"
 	"time"
 	"vc/internal/gen/issuer/apiv1_issuer"
 	"vc/internal/issuer/auditlog"
+	"vc/internal/issuer/keys"
+	"vc/pkg/configuration"
 	"vc/pkg/helpers"
 	"vc/pkg/logger"
 	"vc/pkg/model"
 	"vc/pkg/sdjwt"
+	"vc/pkg/statuslist"
+	"vc/pkg/tenant"
 	"vc/pkg/trace"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -18,36 +22,60 @@ import (
 
 //	@title		Issuer API
 //	@version	0.1.0
-//	@BasePath	/issuer/api/v1
+//	@BasePath	/api/v1
 
 // Client holds the public api object
 type Client struct {
-	cfg        *model.Cfg
-	log        *logger.Log
-	tracer     *trace.Tracer
-	auditLog   *auditlog.Service
-	privateKey *ecdsa.PrivateKey
-	publicKey  *ecdsa.PublicKey
-	jwkClaim   jwt.MapClaims
-	jwkBytes   []byte
-	jwkProto   *apiv1_issuer.Jwk
+	cfg      *model.Cfg
+	log      *logger.Log
+	tracer   *trace.Tracer
+	auditLog *auditlog.Service
+	keys     *keys.Service
+	tenants  *tenant.Tenants
+	jwkClaim jwt.MapClaims
+	jwkBytes []byte
+	jwkProto *apiv1_issuer.Jwk
+	vctm     *sdjwt.VCTMResolver
+	profiles *configuration.Profiles
 
 	ehicClient *ehicClient
 	pda1Client *pda1Client
+	elmClient  *elmClient
+
+	// instructions map the document data of the built-in document types to claims
+	instructions map[string]instructionFunc
 }
 
+// instructionFunc maps the document data of a document type to the claims of a credential
+type instructionFunc func(ctx context.Context, documentData []byte) (sdjwt.InstructionsV2, error)
+
 // New creates a new instance of the public api
-func New(ctx context.Context, auditLog *auditlog.Service, cfg *model.Cfg, tracer *trace.Tracer, log *logger.Log) (*Client, error) {
+func New(ctx context.Context, auditLog *auditlog.Service, keys *keys.Service, cfg *model.Cfg, tracer *trace.Tracer, log *logger.Log) (*Client, error) {
 	c := &Client{
 		cfg:      cfg,
 		log:      log.New("apiv1"),
 		tracer:   tracer,
 		auditLog: auditLog,
+		keys:     keys,
 		jwkProto: &apiv1_issuer.Jwk{},
 		jwkClaim: jwt.MapClaims{},
 	}
 
 	var err error
+	c.tenants, err = tenant.New(cfg.Common.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	c.profiles, err = configuration.NewProfiles(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.vctmEnabled() {
+		c.vctm = sdjwt.NewVCTMResolver(nil, time.Duration(cfg.Issuer.VCTM.CacheTTL)*time.Second, cfg.Issuer.VCTM.AllowList)
+	}
+
 	c.ehicClient, err = newEHICClient(tracer, c.log.New("ehic"))
 	if err != nil {
 		return nil, err
@@ -58,6 +86,17 @@ func New(ctx context.Context, auditLog *auditlog.Service, cfg *model.Cfg, tracer
 		return nil, err
 	}
 
+	c.elmClient, err = newELMClient(tracer, c.log.New("elm"))
+	if err != nil {
+		return nil, err
+	}
+
+	c.instructions = map[string]instructionFunc{
+		"PDA1": c.pda1Client.instruction,
+		"EHIC": c.ehicClient.instruction,
+		"ELM":  c.elmClient.instruction,
+	}
+
 	if err := c.initKeys(ctx); err != nil {
 		return nil, err
 	}
@@ -68,38 +107,123 @@ func New(ctx context.Context, auditLog *auditlog.Service, cfg *model.Cfg, tracer
 }
 
 func (c *Client) initKeys(ctx context.Context) error {
-	keyByte, err := os.ReadFile(c.cfg.Issuer.SigningKeyPath)
-	if err != nil {
-		c.log.Error(err, "Failed to read signing key, please create a ECDSA prime256v1 key and save it to the path")
+	if err := c.createJWK(ctx); err != nil {
 		return err
 	}
 
-	if keyByte == nil {
-		return helpers.ErrPrivateKeyMissing
+	for _, name := range c.tenants.Names() {
+		if t := c.tenants.Get(name); t != nil && t.SigningKID != "" {
+			if _, err := c.keys.ByKID(t.SigningKID, time.Now()); errors.Is(err, helpers.ErrNoActiveSigningKey) {
+				c.log.Info("Signing key of tenant is not active now", "tenant", name, "kid", t.SigningKID)
+			} else if err != nil {
+				return err
+			}
+		}
 	}
 
-	c.privateKey, err = jwt.ParseECPrivateKeyFromPEM(keyByte)
-	if err != nil {
-		return err
+	for _, profile := range c.profiles.All() {
+		if profile.SigningKID == "" && profile.SigningAlg == "" {
+			continue
+		}
+		key, err := c.profileSigningKey(profile)
+		if errors.Is(err, helpers.ErrNoActiveSigningKey) {
+			c.log.Info("Signing key of credential profile is not active now", "credential_type", profile.CredentialType, "kid", profile.SigningKID, "alg", profile.SigningAlg)
+		} else if err != nil {
+			return fmt.Errorf("credential_profiles.%s: %w", profile.CredentialType, err)
+		} else if profile.SigningAlg != "" && key.Signer.Algorithm() != profile.SigningAlg {
+			return fmt.Errorf("credential_profiles.%s: signing key %s has algorithm %s, not %s", profile.CredentialType, key.KID, key.Signer.Algorithm(), profile.SigningAlg)
+		}
 	}
 
-	c.publicKey = &c.privateKey.PublicKey
+	return nil
+}
 
-	if err := c.createJWK(ctx); err != nil {
-		return err
+// vctmEnabled reports if the type metadata of any credential is resolved
+func (c *Client) vctmEnabled() bool {
+	for _, profile := range c.profiles.All() {
+		if profile.VCTM != nil && *profile.VCTM {
+			return true
+		}
 	}
+	return c.cfg.Issuer.VCTM.Enabled
+}
 
-	return nil
+// resolveVCTM reports if the type metadata of credentials of profile is resolved
+func (c *Client) resolveVCTM(profile *configuration.Profile) bool {
+	if profile != nil && profile.VCTM != nil {
+		return *profile.VCTM
+	}
+	return c.cfg.Issuer.VCTM.Enabled
+}
+
+// signingKey returns the signing key of the tenant of ctx, else the key of the credential profile, else the active key.
+// The algorithm of the key sets the alg header of the SD-JWT and COSE credentials.
+func (c *Client) signingKey(ctx context.Context, profile *configuration.Profile) (*keys.Key, error) {
+	if t := c.tenants.Get(tenant.FromContext(ctx)); t != nil && t.SigningKID != "" {
+		return c.keys.ByKID(t.SigningKID, time.Now())
+	}
+
+	if profile != nil && (profile.SigningKID != "" || profile.SigningAlg != "") {
+		return c.profileSigningKey(profile)
+	}
+
+	return c.keys.Active(time.Now())
+}
+
+// profileSigningKey returns the key with the signing_kid of profile, else the active key with its signing_alg
+func (c *Client) profileSigningKey(profile *configuration.Profile) (*keys.Key, error) {
+	if profile.SigningKID != "" {
+		return c.keys.ByKID(profile.SigningKID, time.Now())
+	}
+	return c.keys.ByAlgorithm(profile.SigningAlg, time.Now())
+}
+
+// profile returns the credential profile of credentialType, or of documentType if credentialType is empty, if it
+// allows format. The profile is nil if no profiles are configured.
+func (c *Client) profile(credentialType, documentType, format string) (*configuration.Profile, error) {
+	profile, err := c.profiles.Resolve(credentialType, documentType)
+	if err != nil {
+		return nil, err
+	}
+	if profile != nil && !profile.Supports(format) {
+		return nil, helpers.ErrUnsupportedCredentialFormat
+	}
+
+	return profile, nil
 }
 
-func (c *Client) sign(ctx context.Context, instruction sdjwt.InstructionsV2) (*sdjwt.SDJWT, error) {
+// statusListEnabled reports if credentials of profile get a status list entry
+func (c *Client) statusListEnabled(profile *configuration.Profile) bool {
+	if profile != nil && profile.StatusList != nil {
+		return *profile.StatusList
+	}
+	return c.cfg.Issuer.StatusList.Enabled
+}
+
+// jwtConfig returns the signing key and the credential config of documentType, the claims are validated against the vct schema.
+// The credential profile, if not nil, overrides the issuer configuration.
+func (c *Client) jwtConfig(ctx context.Context, profile *configuration.Profile, documentType string, instruction sdjwt.InstructionsV2) (*keys.Key, *sdjwt.Config, error) {
+	key, err := c.signingKey(ctx, profile)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	jwtConfig := &sdjwt.Config{
+		KID: key.KID,
 		ISS: c.cfg.Issuer.JWTAttribute.Issuer,
 		VCT: c.cfg.Issuer.JWTAttribute.VerifiableCredentialType,
-		CNF: c.jwkClaim,
+	}
+	if vct, ok := c.cfg.Issuer.JWTAttribute.DocumentTypeVCT[documentType]; ok {
+		jwtConfig.VCT = vct
+	}
+	if profile != nil && profile.VCT != "" {
+		jwtConfig.VCT = profile.VCT
 	}
 
-	if c.cfg.Issuer.JWTAttribute.EnableNotBefore {
+	if profile != nil && profile.ValidDuration > 0 {
+		jwtConfig.NBF = time.Now().Unix()
+		jwtConfig.EXP = time.Now().Add(time.Duration(profile.ValidDuration) * time.Second).Unix()
+	} else if c.cfg.Issuer.JWTAttribute.EnableNotBefore {
 		jwtConfig.NBF = time.Now().Unix()
 		jwtConfig.EXP = time.Now().Add(time.Duration(c.cfg.Issuer.JWTAttribute.ValidDuration) * time.Second).Unix()
 	}
@@ -108,10 +232,74 @@ func (c *Client) sign(ctx context.Context, instruction sdjwt.InstructionsV2) (*s
 		jwtConfig.Status = c.cfg.Issuer.JWTAttribute.Status
 	}
 
-	signedCredential, err := instruction.SDJWT(jwt.SigningMethodES256, c.privateKey, jwtConfig)
+	if c.vctm != nil && c.resolveVCTM(profile) {
+		vctm, err := c.vctm.Resolve(ctx, jwtConfig.VCT, "")
+		if err != nil {
+			return nil, nil, err
+		}
+		jwtConfig.VCTIntegrity = sdjwt.Integrity(vctm.Raw)
+
+		claims := instruction.Claims()
+		claims["iss"] = jwtConfig.ISS
+		claims["vct"] = jwtConfig.VCT
+		if err := vctm.ValidateClaims(claims); err != nil {
+			return nil, nil, schemaError(err)
+		}
+	}
+
+	return key, jwtConfig, nil
+}
+
+// sign signs the credential bound to the holder key of cnf, status is added as the status claim if not nil. Claims
+// disclosed unchanged by previousDisclosures keep their digests.
+func (c *Client) sign(ctx context.Context, profile *configuration.Profile, documentType string, instruction sdjwt.InstructionsV2, cnf jwt.MapClaims, status *statuslist.StatusReference, previousDisclosures []string) (*sdjwt.SDJWT, error) {
+	key, jwtConfig, err := c.jwtConfig(ctx, profile, documentType, instruction)
+	if err != nil {
+		return nil, err
+	}
+	jwtConfig.CNF = cnf
+	jwtConfig.StatusList = status
+	jwtConfig.PreviousDisclosures = previousDisclosures
+
+	signedCredential, err := instruction.SDJWT(key.Signer.SigningMethod(), key.Signer, jwtConfig)
 	if err != nil {
 		return nil, err
 	}
 
 	return signedCredential, nil
 }
+
+// preview returns the unsigned payload and the disclosures the credential would be signed with
+func (c *Client) preview(ctx context.Context, profile *configuration.Profile, documentType string, instruction sdjwt.InstructionsV2, cnf jwt.MapClaims, previousDisclosures []string) (*CredentialPreview, error) {
+	_, jwtConfig, err := c.jwtConfig(ctx, profile, documentType, instruction)
+	if err != nil {
+		return nil, err
+	}
+	jwtConfig.CNF = cnf
+	jwtConfig.PreviousDisclosures = previousDisclosures
+
+	payload, disclosures, err := instruction.Unsigned(jwtConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CredentialPreview{
+		Payload:     payload,
+		Disclosures: disclosures.ArrayHashes(),
+	}, nil
+}
+
+// schemaError converts a schema validation error to an error with per claim details
+func schemaError(err error) error {
+	var schemaErr *sdjwt.SchemaError
+	if !errors.As(err, &schemaErr) {
+		return err
+	}
+
+	details := make([]map[string]any, 0, len(schemaErr.Violations))
+	for _, v := range schemaErr.Violations {
+		details = append(details, map[string]any{"field": v.Path, "message": v.Message})
+	}
+
+	return helpers.NewErrorDetails("SCHEMA_VALIDATION_ERROR", details)
+}
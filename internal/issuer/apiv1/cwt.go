@@ -0,0 +1,147 @@
+package apiv1
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+	"vc/pkg/cose"
+	"vc/pkg/helpers"
+	"vc/pkg/model"
+	"vc/pkg/openid4vci"
+	"vc/pkg/statuslist"
+)
+
+// CreateCWTRequest is the request for MakeCWT
+type CreateCWTRequest struct {
+	DocumentType string         `json:"document_type" validate:"required"`
+	DocumentData map[string]any `json:"document_data" validate:"required"`
+
+	// CredentialType selects the credential profile, the profile of the document type if empty
+	CredentialType string `json:"credential_type"`
+
+	// ConsentIDs references the holder consents the credential is issued under
+	ConsentIDs []string `json:"consent_ids"`
+
+	// Proof is the proof of possession of the holder key, the credential is bound to the key in its cnf claim
+	Proof *openid4vci.Proof `json:"proof"`
+}
+
+// CWTCredential is a credential encoded as CWT
+type CWTCredential struct {
+	// CWT is the base64url encoded COSE_Sign1 message, without padding
+	CWT string `json:"cwt"`
+
+	// KID of the issuer key the CWT is signed with, also in the unprotected header
+	KID string `json:"kid"`
+}
+
+// CreateCWTReply is the reply for MakeCWT
+type CreateCWTReply struct {
+	Data *CWTCredential `json:"data"`
+}
+
+// createCWTEvent is the audit log message for an issued CWT credential
+type createCWTEvent struct {
+	DocumentType string   `json:"document_type"`
+	KID          string   `json:"kid"`
+	ConsentIDs   []string `json:"consent_ids,omitempty"`
+	StatusEntity string   `json:"status_entity,omitempty"`
+}
+
+// MakeCWT creates a credential encoded as CWT for constrained devices
+//
+//	@Summary		Create CWT credential
+//	@ID				issuer-create-cwt
+//	@Description	Creates a credential as CBOR Web Token, a COSE_Sign1 message signed with the issuer key. The claims are not selectively disclosable. With a proof the credential is bound to the holder key in its cnf claim.
+//	@Tags			issuer
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	CreateCWTReply		"Success"
+//	@Failure		400	{object}	helpers.Problem		"Bad Request"
+//	@Param			req	body		CreateCWTRequest	true	" "
+//	@Router			/credential/cwt [post]
+func (c *Client) MakeCWT(ctx context.Context, req *CreateCWTRequest) (*CreateCWTReply, error) {
+	ctx, span := c.tracer.Start(ctx, "apiv1:MakeCWT")
+	defer span.End()
+
+	if err := helpers.CheckSimple(req); err != nil {
+		return nil, err
+	}
+
+	documentData, err := json.Marshal(req.DocumentData)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := c.profile(req.CredentialType, req.DocumentType, model.CredentialFormatCWT)
+	if err != nil {
+		return nil, err
+	}
+
+	instruction, err := c.instruction(ctx, profile, req.DocumentType, documentData)
+	if err != nil {
+		return nil, err
+	}
+
+	// same issuer, vct and validity as the SD-JWT credential
+	key, jwtConfig, err := c.jwtConfig(ctx, profile, req.DocumentType, instruction)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &cose.Claims{
+		Issuer:    jwtConfig.ISS,
+		IssuedAt:  time.Now().Unix(),
+		NotBefore: jwtConfig.NBF,
+		ExpiresAt: jwtConfig.EXP,
+		Private:   instruction.Claims(),
+	}
+	claims.Private["vct"] = jwtConfig.VCT
+
+	// same holder binding as the cnf claim of the SD-JWT credential
+	holderKey, err := c.holderKey(ctx, req.Proof, "")
+	if err != nil {
+		return nil, err
+	}
+	if holderKey != nil {
+		var publicKey any
+		if err := holderKey.Raw(&publicKey); err != nil {
+			return nil, err
+		}
+		cnfKey, err := cose.NewKey(publicKey, holderKey.KeyID())
+		if err != nil {
+			return nil, err
+		}
+		claims.Confirmation = &cose.Confirmation{Key: cnfKey}
+	}
+
+	var statusEntity string
+	if c.statusListEnabled(profile) {
+		var status *statuslist.StatusReference
+		status, statusEntity, err = c.allocateStatus(ctx)
+		if err != nil {
+			return nil, err
+		}
+		claims.Status = &statuslist.Status{StatusList: status}
+	}
+
+	signed, err := cose.SignCWT(key.Signer, key.KID, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	c.auditLog.AddAuditLog(ctx, "create_credential_cwt", &createCWTEvent{
+		DocumentType: req.DocumentType,
+		KID:          key.KID,
+		ConsentIDs:   req.ConsentIDs,
+		StatusEntity: statusEntity,
+	})
+
+	return &CreateCWTReply{
+		Data: &CWTCredential{
+			CWT: base64.RawURLEncoding.EncodeToString(signed),
+			KID: key.KID,
+		},
+	}, nil
+}
@@ -0,0 +1,70 @@
+package apiv1
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"vc/pkg/cose"
+	"vc/pkg/helpers"
+	"vc/pkg/openid4vci"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMakeCWTHolderBinding(t *testing.T) {
+	ctx := context.Background()
+	issuer := mockIssuer(t)
+	holderKey := mockGenerateECDSAKey(t)
+
+	invalidProof := mockProof(t, holderKey)
+	invalidProof.JWT += "x"
+
+	tts := []struct {
+		name    string
+		proof   *openid4vci.Proof
+		wantCNF bool
+		wantErr error
+	}{
+		{
+			name:    "bound to the proof key",
+			proof:   mockProof(t, holderKey),
+			wantCNF: true,
+		},
+		{
+			name: "unbound without proof",
+		},
+		{
+			name:    "invalid proof",
+			proof:   invalidProof,
+			wantErr: helpers.ErrInvalidProof,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			reply, err := issuer.MakeCWT(ctx, &CreateCWTRequest{
+				DocumentType: "TEST",
+				DocumentData: map[string]any{"name": "Alice"},
+				Proof:        tt.proof,
+			})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			data, err := base64.RawURLEncoding.DecodeString(reply.Data.CWT)
+			assert.NoError(t, err)
+			_, claims, err := cose.ParseCWT(data)
+			assert.NoError(t, err)
+
+			if !tt.wantCNF {
+				assert.Nil(t, claims.Confirmation)
+				return
+			}
+			cnfKey, err := claims.Confirmation.Key.PublicKey()
+			assert.NoError(t, err)
+			assert.True(t, holderKey.PublicKey.Equal(cnfKey), "cnf is the holder key, not the issuer key")
+		})
+	}
+}
@@ -2,6 +2,7 @@ package apiv1
 
 import (
 	"context"
+	"encoding/json"
 	"vc/pkg/ehic"
 	"vc/pkg/logger"
 	"vc/pkg/sdjwt"
@@ -151,3 +152,12 @@ func (c *ehicClient) sdjwt(ctx context.Context, doc *ehic.Document) sdjwt.Instru
 
 	return instruction
 }
+
+// instruction decodes documentData as an EHIC and maps it to claims
+func (c *ehicClient) instruction(ctx context.Context, documentData []byte) (sdjwt.InstructionsV2, error) {
+	doc := &ehic.Document{}
+	if err := json.Unmarshal(documentData, &doc); err != nil {
+		return nil, err
+	}
+	return c.sdjwt(ctx, doc), nil
+}
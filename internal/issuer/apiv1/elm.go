@@ -0,0 +1,134 @@
+package apiv1
+
+import (
+	"context"
+	"encoding/json"
+	"vc/pkg/education"
+	"vc/pkg/logger"
+	"vc/pkg/sdjwt"
+	"vc/pkg/trace"
+)
+
+type elmClient struct {
+	log    *logger.Log
+	tracer *trace.Tracer
+}
+
+func newELMClient(tracer *trace.Tracer, log *logger.Log) (*elmClient, error) {
+	c := &elmClient{
+		log:    log,
+		tracer: tracer,
+	}
+
+	return c, nil
+}
+
+// sdjwt maps a learning credential to claims, the learner is selectively disclosable and the achievements are disclosed as a whole
+func (c *elmClient) sdjwt(ctx context.Context, doc *education.Document) sdjwt.InstructionsV2 {
+	_, span := c.tracer.Start(ctx, "apiv1:elm:sdjwt")
+	defer span.End()
+
+	learner := []any{
+		&sdjwt.ChildInstructionV2{
+			Name:                "dateOfBirth",
+			Value:               doc.Learner.DateOfBirth,
+			SelectiveDisclosure: true,
+		},
+		&sdjwt.ChildInstructionV2{
+			Name:                "familyName",
+			Value:               doc.Learner.FamilyName,
+			SelectiveDisclosure: true,
+		},
+		&sdjwt.ChildInstructionV2{
+			Name:                "givenName",
+			Value:               doc.Learner.GivenName,
+			SelectiveDisclosure: true,
+		},
+	}
+	if doc.Learner.NationalID != "" {
+		learner = append(learner, &sdjwt.ChildInstructionV2{
+			Name:                "nationalID",
+			Value:               doc.Learner.NationalID,
+			SelectiveDisclosure: true,
+		})
+	}
+	if doc.Learner.CitizenshipCountry != "" {
+		learner = append(learner, &sdjwt.ChildInstructionV2{
+			Name:                "citizenshipCountry",
+			Value:               doc.Learner.CitizenshipCountry,
+			SelectiveDisclosure: true,
+		})
+	}
+
+	achievements := make([]sdjwt.ChildInstructionV2, 0, len(doc.LearningAchievements))
+	for i := range doc.LearningAchievements {
+		achievements = append(achievements, sdjwt.ChildInstructionV2{
+			Value: achievementClaim(&doc.LearningAchievements[i]),
+		})
+	}
+
+	instruction := sdjwt.InstructionsV2{
+		&sdjwt.ChildInstructionV2{
+			Name:  "issuanceDate",
+			Value: doc.IssuanceDate,
+		},
+		&sdjwt.ParentInstructionV2{
+			Name:     "learner",
+			Children: learner,
+		},
+		&sdjwt.ChildArrayInstructionV2{
+			Name:     "learningAchievements",
+			Children: achievements,
+		},
+	}
+
+	return instruction
+}
+
+// achievementClaim is the claim value of a learning achievement, empty optional fields are left out
+func achievementClaim(achievement *education.LearningAchievement) map[string]any {
+	claim := map[string]any{
+		"id":    achievement.ID,
+		"title": achievement.Title,
+		"awardingBody": map[string]any{
+			"id":        achievement.AwardingBody.ID,
+			"legalName": achievement.AwardingBody.LegalName,
+			"country":   achievement.AwardingBody.Country,
+		},
+		"awardingDate": achievement.AwardingDate,
+	}
+	if achievement.Description != "" {
+		claim["description"] = achievement.Description
+	}
+	if achievement.Grade != "" {
+		claim["grade"] = achievement.Grade
+	}
+
+	specifiedBy := map[string]any{}
+	if achievement.SpecifiedBy.Title != "" {
+		specifiedBy["title"] = achievement.SpecifiedBy.Title
+	}
+	if achievement.SpecifiedBy.EQFLevel != 0 {
+		specifiedBy["eqfLevel"] = achievement.SpecifiedBy.EQFLevel
+	}
+	if achievement.SpecifiedBy.ISCEDFCode != "" {
+		specifiedBy["iscedfCode"] = achievement.SpecifiedBy.ISCEDFCode
+	}
+	if achievement.SpecifiedBy.CreditPoints != 0 {
+		specifiedBy["creditPoints"] = achievement.SpecifiedBy.CreditPoints
+	}
+	if len(specifiedBy) > 0 {
+		claim["specifiedBy"] = specifiedBy
+	}
+
+	return claim
+}
+
+// instruction decodes documentData as an ELM learning credential and maps it to claims
+func (c *elmClient) instruction(ctx context.Context, documentData []byte) (sdjwt.InstructionsV2, error) {
+	doc := &education.Document{}
+	if err := json.Unmarshal(documentData, &doc); err != nil {
+		return nil, err
+	}
+	return c.sdjwt(ctx, doc), nil
+}
@@ -0,0 +1,65 @@
+package apiv1
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"vc/pkg/education"
+	"vc/pkg/logger"
+	"vc/pkg/sdjwt"
+	"vc/pkg/trace"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestELMSDJWT(t *testing.T) {
+	ctx := context.Background()
+	log := logger.NewSimple("testing_apiv1")
+	tracer, err := trace.NewForTesting(ctx, "test", log)
+	assert.NoError(t, err)
+
+	client, err := newELMClient(tracer, log)
+	assert.NoError(t, err)
+
+	doc := &education.Document{
+		Learner: education.Person{
+			GivenName:   "Magnus",
+			FamilyName:  "Svensson",
+			DateOfBirth: "1990-01-01",
+		},
+		LearningAchievements: []education.LearningAchievement{
+			{
+				ID:           "urn:uuid:1",
+				Title:        "Master of Science in Computer Science",
+				AwardingBody: education.Organisation{ID: "SE:KTH", LegalName: "KTH Royal Institute of Technology", Country: "SE"},
+				AwardingDate: "2015-06-12",
+				SpecifiedBy:  education.Qualification{EQFLevel: 7, CreditPoints: 120},
+			},
+		},
+		IssuanceDate: "2024-01-01",
+	}
+
+	instruction := client.sdjwt(ctx, doc)
+
+	vctm := &sdjwt.VCTM{}
+	assert.NoError(t, json.Unmarshal(education.TypeMetadata, vctm))
+	claims := instruction.Claims()
+	claims["iss"] = "https://issuer.sunet.se"
+	claims["vct"] = education.VCT
+	assert.NoError(t, vctm.ValidateClaims(claims))
+
+	payload, disclosures, err := instruction.Unsigned(&sdjwt.Config{ISS: "https://issuer.sunet.se", VCT: education.VCT})
+	assert.NoError(t, err)
+	assert.Len(t, disclosures, 3, "given name, family name and date of birth")
+	assert.Len(t, payload["learner"].(jwt.MapClaims)["_sd"], 3)
+	assert.Equal(t, []any{
+		map[string]any{
+			"id":           "urn:uuid:1",
+			"title":        "Master of Science in Computer Science",
+			"awardingBody": map[string]any{"id": "SE:KTH", "legalName": "KTH Royal Institute of Technology", "country": "SE"},
+			"awardingDate": "2015-06-12",
+			"specifiedBy":  map[string]any{"eqfLevel": 7, "creditPoints": float64(120)},
+		},
+	}, payload["learningAchievements"])
+}
@@ -5,11 +5,13 @@ import (
 	"encoding/json"
 	"vc/internal/gen/issuer/apiv1_issuer"
 	"vc/internal/gen/registry/apiv1_registry"
-	"vc/pkg/ehic"
+	"vc/pkg/configuration"
 	"vc/pkg/helpers"
-	"vc/pkg/pda1"
-
+	"vc/pkg/model"
+	"vc/pkg/openid4vci"
 	"vc/pkg/sdjwt"
+	"vc/pkg/statuslist"
+	"vc/pkg/trace"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -37,11 +39,49 @@ func (c *Client) Get(ctx context.Context, indata *GetRequest) (*GetReply, error)
 type CreateCredentialRequest struct {
 	DocumentType string `json:"document_type" validate:"required"`
 	DocumentData []byte `json:"document_data" validate:"required"`
+
+	// CredentialType selects the credential profile, the profile of the document type if empty
+	CredentialType string `json:"credential_type"`
+
+	// ConsentIDs references the holder consents the credential is issued under
+	ConsentIDs []string `json:"consent_ids"`
+
+	// DryRun returns the unsigned payload and disclosures as preview, nothing is signed or audit logged
+	DryRun bool `json:"dry_run"`
+
+	// PreviousDisclosures are the disclosures of the credential this one refreshes, claims that did not change keep
+	// their salts and digests
+	PreviousDisclosures []string `json:"previous_disclosures"`
+
+	// Proof is the proof of possession of the holder key, the credential is bound to the key in its cnf claim
+	Proof *openid4vci.Proof `json:"proof"`
+
+	// HolderJWK is the holder key of a proof the apigw already verified, used if there is no Proof
+	HolderJWK string `json:"-"`
+}
+
+// createCredentialEvent is the audit log message for an issued credential
+type createCredentialEvent struct {
+	*sdjwt.PresentationFlat
+	ConsentIDs []string `json:"consent_ids,omitempty"`
+
+	// StatusEntity is the registry entity of the status list entry, used to revoke the credential
+	StatusEntity string `json:"status_entity,omitempty"`
+
+	// Refresh is true if the credential refreshes a previous one
+	Refresh bool `json:"refresh,omitempty"`
 }
 
-// CreateCredentialReply is the reply for Credential
+// CredentialPreview is the unsigned credential of a dry run
+type CredentialPreview struct {
+	Payload     map[string]any `json:"payload"`
+	Disclosures []string       `json:"disclosures"`
+}
+
+// CreateCredentialReply is the reply for Credential, Preview is set instead of Data on a dry run
 type CreateCredentialReply struct {
-	Data *sdjwt.PresentationFlat `json:"data"`
+	Data    *sdjwt.PresentationFlat `json:"data,omitempty"`
+	Preview *CredentialPreview      `json:"preview,omitempty"`
 }
 
 // MakeSDJWT creates a credential
@@ -54,30 +94,53 @@ func (c *Client) MakeSDJWT(ctx context.Context, req *CreateCredentialRequest) (*
 		return nil, err
 	}
 
-	// Build SDJWT
-	var instruction sdjwt.InstructionsV2
-	switch req.DocumentType {
-	case "PDA1":
-		doc := &pda1.Document{}
-		if err := json.Unmarshal(req.DocumentData, &doc); err != nil {
+	profile, err := c.profile(req.CredentialType, req.DocumentType, model.CredentialFormatSDJWT)
+	if err != nil {
+		return nil, err
+	}
+
+	instruction, err := c.instruction(ctx, profile, req.DocumentType, req.DocumentData)
+	if err != nil {
+		return nil, err
+	}
+
+	holderKey, err := c.holderKey(ctx, req.Proof, req.HolderJWK)
+	if err != nil {
+		return nil, err
+	}
+	cnf, err := cnfClaim(holderKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.DryRun {
+		preview, err := c.preview(ctx, profile, req.DocumentType, instruction, cnf, req.PreviousDisclosures)
+		if err != nil {
 			return nil, err
 		}
-		instruction = c.pda1Client.sdjwt(ctx, doc)
+		return &CreateCredentialReply{Preview: preview}, nil
+	}
 
-	case "EHIC":
-		doc := &ehic.Document{}
-		if err := json.Unmarshal(req.DocumentData, &doc); err != nil {
+	var status *statuslist.StatusReference
+	var statusEntity string
+	if c.statusListEnabled(profile) {
+		status, statusEntity, err = c.allocateStatus(ctx)
+		if err != nil {
 			return nil, err
 		}
-		instruction = c.ehicClient.sdjwt(ctx, doc)
 	}
 
-	signedCredential, err := c.sign(ctx, instruction)
+	signedCredential, err := c.sign(ctx, profile, req.DocumentType, instruction, cnf, status, req.PreviousDisclosures)
 	if err != nil {
 		return nil, err
 	}
 
-	c.auditLog.AddAuditLog(ctx, "create_credential", signedCredential.PresentationFlat())
+	c.auditLog.AddAuditLog(ctx, "create_credential", &createCredentialEvent{
+		PresentationFlat: signedCredential.PresentationFlat(),
+		ConsentIDs:       req.ConsentIDs,
+		StatusEntity:     statusEntity,
+		Refresh:          len(req.PreviousDisclosures) > 0,
+	})
 	reply := &CreateCredentialReply{
 		Data: signedCredential.PresentationFlat(),
 	}
@@ -85,6 +148,20 @@ func (c *Client) MakeSDJWT(ctx context.Context, req *CreateCredentialRequest) (*
 	return reply, nil
 }
 
+// instruction returns the claims of a credential of documentType, mapped by the claims of the profile if it has any
+func (c *Client) instruction(ctx context.Context, profile *configuration.Profile, documentType string, documentData []byte) (sdjwt.InstructionsV2, error) {
+	if profile != nil && len(profile.Claims) > 0 {
+		return mapClaims(profile.Claims, documentData)
+	}
+
+	instruction, ok := c.instructions[documentType]
+	if !ok {
+		return nil, helpers.ErrNoKnownDocumentType
+	}
+
+	return instruction(ctx, documentData)
+}
+
 // RevokeRequest is the request for GenericRevoke
 type RevokeRequest struct {
 	AuthenticSource string `json:"authentic_source"`
@@ -101,24 +178,13 @@ type RevokeReply struct {
 }
 
 // Revoke revokes a document
-//
-//	@Summary		Revoke
-//	@ID				generic-revoke
-//	@Description	Revoke endpoint
-//	@Tags			dc4eu
-//	@Accept			json
-//	@Produce		json
-//	@Success		200	{object}	RevokeReply				"Success"
-//	@Failure		400	{object}	helpers.ErrorResponse	"Bad Request"
-//	@Param			req	body		RevokeRequest			true	" "
-//	@Router			/revoke [post]
 func (c *Client) Revoke(ctx context.Context, req *RevokeRequest) (*RevokeReply, error) {
 	ctx, span := c.tracer.Start(ctx, "apiv1:Revoke")
 	defer span.End()
 
 	optInsecure := grpc.WithTransportCredentials(insecure.NewCredentials())
 
-	conn, err := grpc.Dial(c.cfg.Registry.GRPCServer.Addr, optInsecure)
+	conn, err := grpc.Dial(c.cfg.Registry.GRPCServer.Addr, optInsecure, trace.GRPCClientOption())
 	if err != nil {
 		return nil, err
 	}
@@ -145,15 +211,27 @@ func (c *Client) Revoke(ctx context.Context, req *RevokeRequest) (*RevokeReply,
 	return reply, nil
 }
 
-// JWKS creates a credential
+// JWKS returns the public keys of all configured signing keys
+//
+//	@Summary		JWKS
+//	@ID				issuer-jwks
+//	@Description	JSON Web Key Set with the public keys of all configured signing keys, credentials carry the kid of their key in the header
+//	@Tags			issuer
+//	@Produce		json
+//	@Success		200	{object}	apiv1_issuer.Keys	"Success"
+//	@Router			/.well-known/jwks.json [get]
 func (c *Client) JWKS(ctx context.Context, in *apiv1_issuer.Empty) (*apiv1_issuer.JwksReply, error) {
 	ctx, span := c.tracer.Start(ctx, "apiv1:JWKS")
 	defer span.End()
 
-	keys := &apiv1_issuer.Keys{
-		Keys: []*apiv1_issuer.Jwk{
-			c.jwkProto,
-		},
+	jwksBytes, err := json.Marshal(c.keys.JWKS())
+	if err != nil {
+		return nil, err
+	}
+
+	keys := &apiv1_issuer.Keys{}
+	if err := json.Unmarshal(jwksBytes, keys); err != nil {
+		return nil, err
 	}
 
 	reply := &apiv1_issuer.JwksReply{
@@ -0,0 +1,105 @@
+package apiv1
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+	"vc/internal/issuer/auditlog"
+	"vc/pkg/helpers"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ExportAuditLogRequest is the request for ExportAuditLog
+type ExportAuditLogRequest struct {
+	// From in RFC 3339 format, inclusive
+	From string `form:"from" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+
+	// To in RFC 3339 format, exclusive
+	To string `form:"to" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+// ExportAuditLogReply is the reply for ExportAuditLog
+type ExportAuditLogReply struct {
+	Entries []*auditlog.AuditLog `json:"entries"`
+
+	// Signature is a JWT signed with the active issuer key, its digest claim is the hex encoded SHA-256 of the JSON encoded entries
+	Signature string `json:"signature"`
+}
+
+// ExportAuditLog exports the hash chained audit log entries within a time range
+//
+//	@Summary		Export audit log
+//	@ID				issuer-export-audit-log
+//	@Description	Exports the hash chained audit log entries within a time range, with a signature over the batch
+//	@Tags			issuer
+//	@Produce		json
+//	@Success		200		{object}	ExportAuditLogReply	"Success"
+//	@Failure		400		{object}	helpers.Problem		"Bad Request"
+//	@Param			from	query		string				true	"RFC 3339, inclusive"
+//	@Param			to		query		string				true	"RFC 3339, exclusive"
+//	@Router			/auditlog/export [get]
+func (c *Client) ExportAuditLog(ctx context.Context, req *ExportAuditLogRequest) (*ExportAuditLogReply, error) {
+	ctx, span := c.tracer.Start(ctx, "apiv1:ExportAuditLog")
+	defer span.End()
+
+	if err := helpers.CheckSimple(req); err != nil {
+		return nil, err
+	}
+
+	from, err := time.Parse(time.RFC3339, req.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := time.Parse(time.RFC3339, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := c.auditLog.Export(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(entries)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(b)
+
+	claims := jwt.MapClaims{
+		"iss":    c.cfg.Issuer.JWTAttribute.Issuer,
+		"iat":    time.Now().Unix(),
+		"from":   req.From,
+		"to":     req.To,
+		"count":  len(entries),
+		"digest": hex.EncodeToString(digest[:]),
+	}
+	if len(entries) > 0 {
+		claims["first_seq"] = entries[0].Seq
+		claims["last_seq"] = entries[len(entries)-1].Seq
+		claims["last_hash"] = entries[len(entries)-1].Hash
+	}
+
+	key, err := c.keys.Active(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	token := jwt.NewWithClaims(key.Signer.SigningMethod(), claims)
+	token.Header["kid"] = key.KID
+
+	signature, err := token.SignedString(key.Signer)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &ExportAuditLogReply{
+		Entries:   entries,
+		Signature: signature,
+	}
+
+	return reply, nil
+}
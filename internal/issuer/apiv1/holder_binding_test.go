@@ -0,0 +1,189 @@
+package apiv1
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	verifier "vc/internal/verifier/apiv1"
+	"vc/pkg/configuration"
+	"vc/pkg/helpers"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+	"vc/pkg/openid4vci"
+	"vc/pkg/sdjwt"
+	"vc/pkg/tenant"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/stretchr/testify/assert"
+)
+
+const mockIssuerID = "https://issuer.sunet.se"
+
+// mockIssuer returns an issuer of TEST credentials, the name claim is mapped from document data
+func mockIssuer(t *testing.T) *Client {
+	client := mockClient(t)
+	client.cfg.Issuer.JWTAttribute.Issuer = mockIssuerID
+
+	var err error
+	client.profiles, err = configuration.NewProfiles(client.cfg)
+	assert.NoError(t, err)
+	client.tenants, err = tenant.New(nil)
+	assert.NoError(t, err)
+	client.instructions = map[string]instructionFunc{
+		"TEST": func(ctx context.Context, documentData []byte) (sdjwt.InstructionsV2, error) {
+			return mapClaims([]model.ClaimMapping{{Claim: "name", Path: "/name", SelectiveDisclosure: true}}, documentData)
+		},
+	}
+
+	return client
+}
+
+// mockIssuerJWKS serves the public key of the issuer signing key
+func mockIssuerJWKS(t *testing.T, client *Client) *httptest.Server {
+	signingKey, err := client.keys.Active(time.Now())
+	assert.NoError(t, err)
+	key, err := jwk.New(signingKey.Signer.Public())
+	assert.NoError(t, err)
+	assert.NoError(t, key.Set(jwk.KeyIDKey, signingKey.KID))
+	assert.NoError(t, key.Set(jwk.AlgorithmKey, "ES256"))
+	set := jwk.NewSet()
+	set.Add(key)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// mockProof returns a proof of possession of holderKey addressed to the issuer
+func mockProof(t *testing.T, holderKey *ecdsa.PrivateKey) *openid4vci.Proof {
+	holderJWK, err := jwk.New(holderKey.Public())
+	assert.NoError(t, err)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{"aud": mockIssuerID, "iat": time.Now().Unix()})
+	token.Header["typ"] = "openid4vci-proof+jwt"
+	token.Header["jwk"] = holderJWK
+	signed, err := token.SignedString(holderKey)
+	assert.NoError(t, err)
+
+	return &openid4vci.Proof{ProofType: openid4vci.ProofTypeJWT, JWT: signed}
+}
+
+// mockPresentation presents credential with all disclosures and, if holderKey is not nil, a key binding JWT
+func mockPresentation(t *testing.T, credential *sdjwt.PresentationFlat, holderKey *ecdsa.PrivateKey, audience, nonce string) string {
+	presentation := credential.JWT + "~" + strings.Join(credential.Disclosures, "~") + "~"
+	if holderKey == nil {
+		return presentation
+	}
+
+	sdHash := sha256.Sum256([]byte(presentation))
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud":     audience,
+		"nonce":   nonce,
+		"iat":     time.Now().Unix(),
+		"sd_hash": base64.RawURLEncoding.EncodeToString(sdHash[:]),
+	})
+	token.Header["typ"] = "kb+jwt"
+	signed, err := token.SignedString(holderKey)
+	assert.NoError(t, err)
+
+	return presentation + signed
+}
+
+func TestIssueAndPresent(t *testing.T) {
+	ctx := context.Background()
+	issuer := mockIssuer(t)
+	jwksServer := mockIssuerJWKS(t, issuer)
+
+	holderKey := mockGenerateECDSAKey(t)
+	otherKey := mockGenerateECDSAKey(t)
+
+	tts := []struct {
+		name              string
+		proof             *openid4vci.Proof
+		presentWith       *ecdsa.PrivateKey
+		requireKeyBinding bool
+		wantValid         bool
+	}{
+		{
+			name:        "bound and presented with key binding",
+			proof:       mockProof(t, holderKey),
+			presentWith: holderKey,
+			wantValid:   true,
+		},
+		{
+			name:        "bound and presented without key binding",
+			proof:       mockProof(t, holderKey),
+			presentWith: nil,
+		},
+		{
+			name:        "bound and presented with another key",
+			proof:       mockProof(t, holderKey),
+			presentWith: otherKey,
+		},
+		{
+			name:      "unbound",
+			wantValid: true,
+		},
+		{
+			name:              "unbound with key binding required",
+			requireKeyBinding: true,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			issued, err := issuer.MakeSDJWT(ctx, &CreateCredentialRequest{
+				DocumentType: "TEST",
+				DocumentData: []byte(`{"name": "Alice"}`),
+				Proof:        tt.proof,
+			})
+			assert.NoError(t, err)
+
+			cfg := &model.Cfg{Verifier: model.Verifier{
+				IssuerJWKSURL: jwksServer.URL,
+				ExternalURL:   "https://verifier.sunet.se",
+				Session:       model.VerifierSession{TTL: 60},
+				SDJWT:         model.VerifierSDJWT{RequireKeyBinding: tt.requireKeyBinding},
+			}}
+			client, err := verifier.New(ctx, nil, nil, cfg, logger.NewSimple("testing_verifier"))
+			assert.NoError(t, err)
+
+			created, err := client.CreateSession(ctx, &verifier.CreateSessionRequest{})
+			assert.NoError(t, err)
+			requestObject, err := client.SessionRequestObject(ctx, &verifier.SessionRequest{SessionID: created.SessionID})
+			assert.NoError(t, err)
+
+			session, err := client.SessionResponse(ctx, &verifier.SessionResponseRequest{
+				SessionID: created.SessionID,
+				VPToken:   mockPresentation(t, issued.Data, tt.presentWith, requestObject.ClientID, requestObject.Nonce),
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantValid, session.Result.Valid, session.Result.Reason)
+		})
+	}
+}
+
+func TestMakeSDJWTInvalidProof(t *testing.T) {
+	issuer := mockIssuer(t)
+
+	proof := mockProof(t, mockGenerateECDSAKey(t))
+	proof.JWT += "x"
+
+	_, err := issuer.MakeSDJWT(context.Background(), &CreateCredentialRequest{
+		DocumentType: "TEST",
+		DocumentData: []byte(`{"name": "Alice"}`),
+		Proof:        proof,
+	})
+	assert.ErrorIs(t, err, helpers.ErrInvalidProof)
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"time"
+	"vc/pkg/helpers"
+	"vc/pkg/keyresolver"
+	"vc/pkg/openid4vci"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/lestrrat-go/jwx/jwk"
@@ -22,7 +25,13 @@ func (c *Client) createJWK(ctx context.Context) error {
 	ctx, cancel := context.WithDeadline(ctx, time.Now().Add(2*time.Second))
 	defer cancel()
 
-	key, err := jwk.New(c.privateKey)
+	signingKey, err := c.keys.Active(time.Now())
+	if err != nil {
+		return err
+	}
+
+	// only the public key is available when the key is held by a key manager
+	key, err := jwk.New(signingKey.Signer.Public())
 	if err != nil {
 		return err
 	}
@@ -44,9 +53,52 @@ func (c *Client) createJWK(ctx context.Context) error {
 		"kty": c.jwkProto.Kty,
 		"x":   c.jwkProto.X,
 		"y":   c.jwkProto.Y,
-		"d":   c.jwkProto.D,
 	}
 	c.jwkClaim["jwk"] = jwkClaim
 
 	return nil
 }
+
+// holderKey returns the holder key the credential is bound to, from proof or from holderJWK, the key of a proof the
+// apigw already verified. It returns nil if there is neither, the credential then has no cnf and can not be presented
+// with key binding.
+func (c *Client) holderKey(ctx context.Context, proof *openid4vci.Proof, holderJWK string) (jwk.Key, error) {
+	switch {
+	case proof != nil:
+		key, err := proof.HolderKey(ctx, keyresolver.Chain{keyresolver.DIDJWK{}, keyresolver.DIDKey{}}, c.cfg.Issuer.JWTAttribute.Issuer)
+		if err != nil {
+			c.log.Debug("proof", "err", err)
+			return nil, helpers.ErrInvalidProof
+		}
+		return key, nil
+	case holderJWK != "":
+		key, err := jwk.ParseKey([]byte(holderJWK))
+		if err != nil {
+			return nil, helpers.ErrInvalidProof
+		}
+		if _, ok := key.(interface{ D() []byte }); ok {
+			return nil, helpers.ErrInvalidProof
+		}
+		return key, nil
+	}
+
+	return nil, nil
+}
+
+// cnfClaim returns the cnf claim of holderKey, nil if there is no holder key
+func cnfClaim(holderKey jwk.Key) (jwt.MapClaims, error) {
+	if holderKey == nil {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(holderKey)
+	if err != nil {
+		return nil, err
+	}
+	claim := jwt.MapClaims{}
+	if err := json.Unmarshal(b, &claim); err != nil {
+		return nil, err
+	}
+
+	return jwt.MapClaims{"jwk": claim}, nil
+}
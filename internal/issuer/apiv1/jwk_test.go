@@ -1,14 +1,19 @@
 package apiv1
 
 import (
-	"bytes"
 	"context"
 	"crypto/ecdsa"
 	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
 	"testing"
+	"time"
+	"vc/internal/gen/issuer/apiv1_issuer"
 	"vc/internal/issuer/auditlog"
+	"vc/internal/issuer/keys"
 	"vc/pkg/logger"
 	"vc/pkg/model"
+	"vc/pkg/signing"
 	"vc/pkg/trace"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -16,10 +21,8 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-var nonRandom = bytes.NewReader([]byte("01234567890123456789012345678901234567890123456789ABCDEF"))
-
 func mockGenerateECDSAKey(t *testing.T) *ecdsa.PrivateKey {
-	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), nonRandom)
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
 	assert.NoError(t, err)
 
 	return privateKey
@@ -77,12 +80,20 @@ func mockClient(t *testing.T) *Client {
 
 	assert.NoError(t, err)
 
+	signer, err := signing.NewSoftware(mockGenerateECDSAKey(t))
+	assert.NoError(t, err)
+
+	keyService, err := keys.NewStatic(logger.NewSimple("testing_apiv1"), &keys.Key{KID: "singing_", Signer: signer})
+	assert.NoError(t, err)
+
 	client := &Client{
-		cfg:        cfg,
-		log:        logger.NewSimple("testing_apiv1"),
-		tracer:     tracer,
-		auditLog:   auditlog,
-		privateKey: mockGenerateECDSAKey(t),
+		cfg:      cfg,
+		log:      logger.NewSimple("testing_apiv1"),
+		tracer:   tracer,
+		auditLog: auditlog,
+		keys:     keyService,
+		jwkProto: &apiv1_issuer.Jwk{},
+		jwkClaim: jwt.MapClaims{},
 	}
 
 	client.createJWK(ctx)
@@ -90,24 +101,17 @@ func mockClient(t *testing.T) *Client {
 	return client
 }
 
-// Sometimes this test does not behave deterministically
+// coordinate encodes an EC public key coordinate of a P-256 key as a JWK
+func coordinate(v interface{ FillBytes([]byte) []byte }) string {
+	return base64.RawURLEncoding.EncodeToString(v.FillBytes(make([]byte, 32)))
+}
+
 func TestCreateJWK(t *testing.T) {
 	tts := []struct {
 		name string
-		want jwt.MapClaims
 	}{
 		{
 			name: "Test 1",
-			want: jwt.MapClaims{
-				"jwk": jwt.MapClaims{
-					"crv": "P-256",
-					"kid": "singing_",
-					"kty": "EC",
-					"d":   "MTIzNDU2Nzg5MDEyMzQ1Njc4OTAxMjM0NTY3ODkwMTI",
-					"x":   "YiPlXIq3VAfGMMoVzAKB2wYLy0e5n9nYkjmAbBCIdBs",
-					"y":   "d2P8TyUlmM1jop1yUH-fHBYXgbijF0IY4fPA7bQZuDE",
-				},
-			},
 		},
 	}
 
@@ -118,7 +122,21 @@ func TestCreateJWK(t *testing.T) {
 			err := client.createJWK(context.Background())
 			assert.NoError(t, err)
 
-			if diff := cmp.Diff(tt.want, client.jwkClaim); diff != "" {
+			signingKey, err := client.keys.Active(time.Now())
+			assert.NoError(t, err)
+			pub := signingKey.Signer.Public().(*ecdsa.PublicKey)
+
+			want := jwt.MapClaims{
+				"jwk": jwt.MapClaims{
+					"crv": "P-256",
+					"kid": "singing_",
+					"kty": "EC",
+					"x":   coordinate(pub.X),
+					"y":   coordinate(pub.Y),
+				},
+			}
+
+			if diff := cmp.Diff(want, client.jwkClaim); diff != "" {
 				t.Errorf("MakeGatewayInfo() mismatch (-want +got):\n%s", diff)
 			}
 		})
@@ -2,6 +2,7 @@ package apiv1
 
 import (
 	"context"
+	"encoding/json"
 	"vc/pkg/logger"
 	"vc/pkg/pda1"
 	"vc/pkg/sdjwt"
@@ -372,3 +373,12 @@ func (c *pda1Client) sdjwt(ctx context.Context, doc *pda1.Document) sdjwt.Instru
 
 	return instruction
 }
+
+// instruction decodes documentData as a PDA1 and maps it to claims
+func (c *pda1Client) instruction(ctx context.Context, documentData []byte) (sdjwt.InstructionsV2, error) {
+	doc := &pda1.Document{}
+	if err := json.Unmarshal(documentData, &doc); err != nil {
+		return nil, err
+	}
+	return c.sdjwt(ctx, doc), nil
+}
@@ -0,0 +1,45 @@
+package apiv1
+
+import (
+	"context"
+	"fmt"
+	"vc/internal/gen/registry/apiv1_registry"
+	"vc/pkg/statuslist"
+	"vc/pkg/trace"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// allocateStatus adds a new entity to the registry and returns its entry in the token status list. The entity
+// identifies the credential when it is revoked or suspended.
+func (c *Client) allocateStatus(ctx context.Context) (*statuslist.StatusReference, string, error) {
+	ctx, span := c.tracer.Start(ctx, "apiv1:allocateStatus")
+	defer span.End()
+
+	optInsecure := grpc.WithTransportCredentials(insecure.NewCredentials())
+
+	conn, err := grpc.Dial(c.cfg.Registry.GRPCServer.Addr, optInsecure, trace.GRPCClientOption())
+	if err != nil {
+		return nil, "", err
+	}
+	defer conn.Close()
+
+	entity := uuid.NewString()
+
+	client := apiv1_registry.NewRegistryServiceClient(conn)
+	resp, err := client.Add(ctx, &apiv1_registry.AddRequest{
+		Entity: entity,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	ref := &statuslist.StatusReference{
+		Idx: resp.StatusListIndex,
+		URI: fmt.Sprintf("%s/%s", c.cfg.Registry.StatusList.BaseURL, statuslist.TokenPath),
+	}
+
+	return ref, entity, nil
+}
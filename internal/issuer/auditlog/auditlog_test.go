@@ -0,0 +1,109 @@
+package auditlog
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"vc/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockEntries(t *testing.T, c *chain, dates ...time.Time) []*AuditLog {
+	entries := []*AuditLog{}
+	for i, date := range dates {
+		entry := &AuditLog{
+			EventType: "create_credential",
+			Date:      date.Format(time.RFC3339),
+			ID:        strings.Repeat("a", i+1),
+			Message:   struct{ JWT string }{JWT: "eyJ"},
+		}
+		assert.NoError(t, c.link(entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestVerifyChain(t *testing.T) {
+	now := time.Now()
+
+	tts := []struct {
+		name    string
+		tamper  func(entries []*AuditLog) []*AuditLog
+		wantErr error
+	}{
+		{
+			name:   "intact",
+			tamper: func(entries []*AuditLog) []*AuditLog { return entries },
+		},
+		{
+			name:   "consecutive subset",
+			tamper: func(entries []*AuditLog) []*AuditLog { return entries[1:] },
+		},
+		{
+			name: "altered message",
+			tamper: func(entries []*AuditLog) []*AuditLog {
+				entries[1].Message = "altered"
+				return entries
+			},
+			wantErr: ErrChainBroken,
+		},
+		{
+			name: "removed entry",
+			tamper: func(entries []*AuditLog) []*AuditLog {
+				return append(entries[:1], entries[2:]...)
+			},
+			wantErr: ErrChainBroken,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := mockEntries(t, &chain{}, now, now, now)
+			assert.ErrorIs(t, VerifyChain(tt.tamper(entries)), tt.wantErr)
+		})
+	}
+}
+
+func TestJSONLSink(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	sink, err := newJSONLSink(model.AuditLogJSONL{Path: path, MaxSize: 300, MaxFiles: 10})
+	assert.NoError(t, err)
+	defer sink.close()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	dates := []time.Time{}
+	for i := 0; i < 6; i++ {
+		dates = append(dates, start.Add(time.Duration(i)*time.Hour))
+	}
+	for _, entry := range mockEntries(t, &chain{}, dates...) {
+		assert.NoError(t, sink.write(ctx, entry))
+	}
+	assert.Greater(t, len(sink.files()), 1, "expected rotated files")
+
+	entries, err := sink.read(start.Add(time.Hour), start.Add(4*time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, uint64(2), entries[0].Seq)
+	assert.NoError(t, VerifyChain(entries))
+
+	last, err := sink.last()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(6), last.Seq)
+}
+
+func TestFormatRFC5424(t *testing.T) {
+	entry := &AuditLog{EventType: "create credential", ID: "1", Seq: 7, Hash: "abc"}
+
+	msg, err := formatRFC5424(entry, "issuer", "vc-issuer", 42)
+	assert.NoError(t, err)
+
+	fields := strings.SplitN(msg, " ", 7)
+	assert.Equal(t, "<134>1", fields[0])
+	assert.Equal(t, []string{"issuer", "vc-issuer", "42", "create_credential"}, fields[2:6])
+	assert.True(t, strings.HasPrefix(fields[6], `[audit@32473 id="1" seq="7" hash="abc"] {`))
+}
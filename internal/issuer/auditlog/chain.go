@@ -0,0 +1,74 @@
+package auditlog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// chain links audit entries by hash, each entry hash covers the previous hash,
+// so removing or altering an entry breaks every later hash
+type chain struct {
+	seq      uint64
+	lastHash string
+}
+
+// link sets sequence number, previous hash and hash on entry.
+// The message is first normalized to its decoded JSON form, so the hash is the same when the entry is read back.
+func (c *chain) link(entry *AuditLog) error {
+	b, err := json.Marshal(entry.Message)
+	if err != nil {
+		return err
+	}
+	var message any
+	if err := json.Unmarshal(b, &message); err != nil {
+		return err
+	}
+	entry.Message = message
+
+	c.seq++
+	entry.Seq = c.seq
+	entry.PrevHash = c.lastHash
+	entry.Hash = ""
+
+	hash, err := entryHash(entry)
+	if err != nil {
+		return err
+	}
+	entry.Hash = hash
+	c.lastHash = hash
+
+	return nil
+}
+
+// entryHash returns the hex encoded SHA-256 of the entry with an empty hash
+func entryHash(entry *AuditLog) (string, error) {
+	e := *entry
+	e.Hash = ""
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// VerifyChain checks that entries are consecutive and that every hash is intact
+func VerifyChain(entries []*AuditLog) error {
+	for i, entry := range entries {
+		hash, err := entryHash(entry)
+		if err != nil {
+			return err
+		}
+		if hash != entry.Hash {
+			return ErrChainBroken
+		}
+		if i > 0 && (entry.PrevHash != entries[i-1].Hash || entry.Seq != entries[i-1].Seq+1) {
+			return ErrChainBroken
+		}
+	}
+
+	return nil
+}
@@ -17,18 +17,34 @@ func (s *Service) AddAuditLog(ctx context.Context, eventType string, message any
 	}
 }
 
-// processAuditLog processes the audit log entries from the channel and sends them to the webhook
+// processAuditLog processes the audit log entries from the channel, chains them and writes them to every sink
 func (s *Service) processAuditLog(ctx context.Context) {
 	for {
 		select {
 		case <-ctx.Done():
 			s.log.Info("Audit log service stopped")
+			return
 		case auditLog := <-s.auditLogChan:
 			s.log.Info("Processing audit log", "event", auditLog.EventType, "id", auditLog.ID)
-			err := s.SendWebHook(ctx, auditLog)
-			if err != nil {
-				s.log.Error(err, "Error sending webhook")
+			if err := s.chain.link(auditLog); err != nil {
+				s.log.Error(err, "Error chaining audit log")
+				continue
+			}
+			for name, sink := range s.sinks {
+				if err := sink.write(ctx, auditLog); err != nil {
+					s.log.Error(err, "Error writing audit log", "sink", name)
+				}
 			}
 		}
 	}
 }
+
+// Export returns the entries dated within [from, to), oldest first, read from the jsonl sink
+func (s *Service) Export(ctx context.Context, from, to time.Time) ([]*AuditLog, error) {
+	jsonl, ok := s.sinks["jsonl"].(*jsonlSink)
+	if !ok {
+		return nil, ErrExportNotConfigured
+	}
+
+	return jsonl.read(from, to)
+}
@@ -13,6 +13,11 @@ type AuditLog struct {
 	Date      string `json:"date"`
 	ID        string `json:"id"`
 	Message   any    `json:"message"`
+
+	// Seq, PrevHash and Hash chain the entries, see VerifyChain
+	Seq      uint64 `json:"seq"`
+	PrevHash string `json:"prev_hash"`
+	Hash     string `json:"hash"`
 }
 
 // Service holds auditlog service
@@ -21,6 +26,8 @@ type Service struct {
 	log          *logger.Log
 	auditLogChan chan *AuditLog
 	wg           sync.WaitGroup
+	sinks        map[string]sink
+	chain        *chain
 }
 
 // New creates a new auditlog service
@@ -29,6 +36,25 @@ func New(ctx context.Context, cfg *model.Cfg, log *logger.Log) (*Service, error)
 		cfg:          cfg,
 		log:          log.New("auditlog"),
 		auditLogChan: make(chan *AuditLog),
+		chain:        &chain{},
+	}
+
+	var err error
+	service.sinks, err = service.newSinks()
+	if err != nil {
+		return nil, err
+	}
+
+	// continue the hash chain from the last exported entry
+	if jsonl, ok := service.sinks["jsonl"].(*jsonlSink); ok {
+		last, err := jsonl.last()
+		if err != nil {
+			return nil, err
+		}
+		if last != nil {
+			service.chain.seq = last.Seq
+			service.chain.lastHash = last.Hash
+		}
 	}
 
 	service.wg.Add(1)
@@ -45,6 +71,12 @@ func (s *Service) Close(ctx context.Context) error {
 	s.wg.Done()
 	s.wg.Wait()
 
+	for name, sink := range s.sinks {
+		if err := sink.close(); err != nil {
+			s.log.Error(err, "Error closing sink", "sink", name)
+		}
+	}
+
 	s.log.Info("Stopped")
 
 	return nil
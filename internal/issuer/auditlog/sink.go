@@ -0,0 +1,59 @@
+package auditlog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrChainBroken is returned when the audit log hash chain does not verify
+	ErrChainBroken = errors.New("audit log hash chain is broken")
+
+	// ErrExportNotConfigured is returned by Export when the jsonl sink is not enabled
+	ErrExportNotConfigured = errors.New("audit log export requires the jsonl sink")
+)
+
+// sink receives every audit log entry
+type sink interface {
+	write(ctx context.Context, entry *AuditLog) error
+	close() error
+}
+
+// webhookSink posts entries to the notification endpoint of the issuer authentic source
+type webhookSink struct {
+	service *Service
+}
+
+func (s *webhookSink) write(ctx context.Context, entry *AuditLog) error {
+	return s.service.SendWebHook(ctx, entry)
+}
+
+func (s *webhookSink) close() error {
+	return nil
+}
+
+// newSinks creates the configured sinks
+func (s *Service) newSinks() (map[string]sink, error) {
+	sinks := map[string]sink{}
+	for _, name := range s.cfg.Issuer.AuditLog.Sinks {
+		switch name {
+		case "webhook":
+			sinks[name] = &webhookSink{service: s}
+		case "syslog":
+			sinks[name] = newSyslogSink(s.cfg.Issuer.AuditLog.Syslog)
+		case "jsonl":
+			jsonl, err := newJSONLSink(s.cfg.Issuer.AuditLog.JSONL)
+			if err != nil {
+				return nil, err
+			}
+			sinks[name] = jsonl
+		case "http":
+			sinks[name] = newHTTPSink(s.cfg.Issuer.AuditLog.HTTP)
+		default:
+			return nil, fmt.Errorf("unknown audit log sink %q", name)
+		}
+	}
+
+	return sinks, nil
+}
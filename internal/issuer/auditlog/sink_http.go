@@ -0,0 +1,53 @@
+package auditlog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+	"vc/pkg/model"
+)
+
+// httpSink pushes entries as JSON to a configured url
+type httpSink struct {
+	url    string
+	client *http.Client
+}
+
+func newHTTPSink(cfg model.AuditLogHTTP) *httpSink {
+	return &httpSink{
+		url:    cfg.URL,
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (s *httpSink) write(ctx context.Context, entry *AuditLog) error {
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("audit log push responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (s *httpSink) close() error {
+	return nil
+}
@@ -0,0 +1,182 @@
+package auditlog
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+	"vc/pkg/model"
+)
+
+// jsonlSink appends entries as JSON Lines to a file, rotated to path.1 ... path.N when it exceeds max size
+type jsonlSink struct {
+	cfg  model.AuditLogJSONL
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+func newJSONLSink(cfg model.AuditLogJSONL) (*jsonlSink, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("audit_log.jsonl.path is not configured")
+	}
+
+	s := &jsonlSink{cfg: cfg}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *jsonlSink) open() error {
+	file, err := os.OpenFile(s.cfg.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	s.file = file
+	s.size = info.Size()
+
+	return nil
+}
+
+func (s *jsonlSink) rotatedPath(n int) string {
+	return fmt.Sprintf("%s.%d", s.cfg.Path, n)
+}
+
+// rotate renames path to path.1, shifting older files up and dropping the oldest
+func (s *jsonlSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Remove(s.rotatedPath(s.cfg.MaxFiles)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for n := s.cfg.MaxFiles - 1; n >= 1; n-- {
+		if err := os.Rename(s.rotatedPath(n), s.rotatedPath(n+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	if s.cfg.MaxFiles > 0 {
+		if err := os.Rename(s.cfg.Path, s.rotatedPath(1)); err != nil {
+			return err
+		}
+	} else if err := os.Remove(s.cfg.Path); err != nil {
+		return err
+	}
+
+	return s.open()
+}
+
+func (s *jsonlSink) write(ctx context.Context, entry *AuditLog) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.size > 0 && s.size+int64(len(line)) > s.cfg.MaxSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+
+	return err
+}
+
+func (s *jsonlSink) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
+
+// files returns the existing log files, oldest first
+func (s *jsonlSink) files() []string {
+	files := []string{}
+	for n := s.cfg.MaxFiles; n >= 1; n-- {
+		if _, err := os.Stat(s.rotatedPath(n)); err == nil {
+			files = append(files, s.rotatedPath(n))
+		}
+	}
+	return append(files, s.cfg.Path)
+}
+
+// read returns the entries dated within [from, to), oldest first
+func (s *jsonlSink) read(from, to time.Time) ([]*AuditLog, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := []*AuditLog{}
+	for _, path := range s.files() {
+		err := readJSONL(path, func(entry *AuditLog) {
+			date, err := time.Parse(time.RFC3339, entry.Date)
+			if err != nil || date.Before(from) || !date.Before(to) {
+				return
+			}
+			entries = append(entries, entry)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return entries, nil
+}
+
+// last returns the newest entry, or nil if the log is empty
+func (s *jsonlSink) last() (*AuditLog, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	files := s.files()
+	for i := len(files) - 1; i >= 0; i-- {
+		var last *AuditLog
+		if err := readJSONL(files[i], func(entry *AuditLog) { last = entry }); err != nil {
+			return nil, err
+		}
+		if last != nil {
+			return last, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func readJSONL(path string, fn func(entry *AuditLog)) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		entry := &AuditLog{}
+		if err := json.Unmarshal(scanner.Bytes(), entry); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		fn(entry)
+	}
+
+	return scanner.Err()
+}
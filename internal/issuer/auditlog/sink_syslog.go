@@ -0,0 +1,126 @@
+package auditlog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"vc/pkg/model"
+)
+
+const (
+	// syslogPriority is facility local0 (16) and severity informational (6)
+	syslogPriority = 16*8 + 6
+
+	// syslogSDID is the structured data id, 32473 is the private enterprise number reserved for documentation
+	syslogSDID = "audit@32473"
+)
+
+// syslogSink sends entries as RFC 5424 messages, framed with octet counting (RFC 6587) over tcp
+type syslogSink struct {
+	cfg      model.AuditLogSyslog
+	hostname string
+	mu       sync.Mutex
+	conn     net.Conn
+}
+
+func newSyslogSink(cfg model.AuditLogSyslog) *syslogSink {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+
+	return &syslogSink{
+		cfg:      cfg,
+		hostname: hostname,
+	}
+}
+
+// formatRFC5424 formats entry as an RFC 5424 syslog message
+func formatRFC5424(entry *AuditLog, hostname, appName string, procID int) (string, error) {
+	msg, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+
+	structuredData := fmt.Sprintf("[%s id=\"%s\" seq=\"%d\" hash=\"%s\"]", syslogSDID, sdEscape(entry.ID), entry.Seq, entry.Hash)
+
+	return fmt.Sprintf("<%d>1 %s %s %s %d %s %s %s",
+		syslogPriority,
+		time.Now().UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		headerField(hostname, 255),
+		headerField(appName, 48),
+		procID,
+		headerField(entry.EventType, 32),
+		structuredData,
+		msg,
+	), nil
+}
+
+// headerField makes s a valid header field, printable US-ASCII without spaces, "-" if empty
+func headerField(s string, maxLen int) string {
+	s = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return '_'
+		}
+		return r
+	}, s)
+	if len(s) > maxLen {
+		s = s[:maxLen]
+	}
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// sdEscape escapes a structured data parameter value
+func sdEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(s)
+}
+
+func (s *syslogSink) write(ctx context.Context, entry *AuditLog) error {
+	msg, err := formatRFC5424(entry, s.hostname, s.cfg.AppName, os.Getpid())
+	if err != nil {
+		return err
+	}
+	if s.cfg.Network == "tcp" {
+		msg = fmt.Sprintf("%d %s", len(msg), msg)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// reconnect once, the syslog server might have closed an idle connection
+	for attempt := 0; attempt < 2; attempt++ {
+		if s.conn == nil {
+			dialer := &net.Dialer{Timeout: 5 * time.Second}
+			s.conn, err = dialer.DialContext(ctx, s.cfg.Network, s.cfg.Addr)
+			if err != nil {
+				return err
+			}
+		}
+
+		if _, err = s.conn.Write([]byte(msg)); err == nil {
+			return nil
+		}
+		s.conn.Close()
+		s.conn = nil
+	}
+
+	return err
+}
+
+func (s *syslogSink) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.Close()
+}
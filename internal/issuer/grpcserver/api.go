@@ -10,6 +10,7 @@ import (
 // Apiv1 interface
 type Apiv1 interface {
 	MakeSDJWT(ctx context.Context, req *apiv1.CreateCredentialRequest) (*apiv1.CreateCredentialReply, error)
+	MakeSDJWTBatch(ctx context.Context, items <-chan *apiv1.BatchItem, results chan<- *apiv1.BatchResult)
 	JWKS(ctx context.Context, req *apiv1_issuer.Empty) (*apiv1_issuer.JwksReply, error)
 
 	Health(ctx context.Context, req *apiv1_status.StatusRequest) (*apiv1_status.StatusReply, error)
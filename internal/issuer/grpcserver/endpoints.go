@@ -2,20 +2,101 @@ package grpcserver
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
 	"vc/internal/gen/issuer/apiv1_issuer"
 	"vc/internal/issuer/apiv1"
+	"vc/pkg/tenant"
 )
 
-// MakeSDJWT creates an sd-jwt and return it, else error
+// MakeSDJWT creates an sd-jwt and return it, else error. It is signed with the key of the tenant in the metadata.
 func (s *Service) MakeSDJWT(ctx context.Context, in *apiv1_issuer.MakeSDJWTRequest) (*apiv1_issuer.MakeSDJWTReply, error) {
-	reply, err := s.apiv1.MakeSDJWT(ctx, &apiv1.CreateCredentialRequest{
-		DocumentType: in.DocumentType,
-		DocumentData: in.DocumentData,
-	})
+	reply, err := s.apiv1.MakeSDJWT(tenant.FromIncomingContext(ctx), createCredentialRequest(in))
 	if err != nil {
 		return nil, err
 	}
 
+	return makeSDJWTReply(reply)
+}
+
+// MakeSDJWTBatch creates the sd-jwts of a stream of requests and streams back a reply for each, in the order they are
+// done. It is signed with the key of the tenant in the metadata.
+func (s *Service) MakeSDJWTBatch(stream apiv1_issuer.IssuerService_MakeSDJWTBatchServer) error {
+	ctx := tenant.FromIncomingContext(stream.Context())
+
+	items := make(chan *apiv1.BatchItem)
+	results := make(chan *apiv1.BatchResult)
+	recvErr := make(chan error, 1)
+
+	go func() {
+		defer close(items)
+		for {
+			in, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				recvErr <- nil
+				return
+			}
+			if err != nil {
+				recvErr <- err
+				return
+			}
+
+			select {
+			case items <- &apiv1.BatchItem{ID: in.Id, Request: createCredentialRequest(in.Request)}:
+			case <-ctx.Done():
+				recvErr <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	go func() {
+		defer close(results)
+		s.apiv1.MakeSDJWTBatch(ctx, items, results)
+	}()
+
+	for result := range results {
+		out := &apiv1_issuer.MakeSDJWTBatchReply{Id: result.ID}
+		if result.Err == nil {
+			out.Reply, result.Err = makeSDJWTReply(result.Reply)
+		}
+		if result.Err != nil {
+			out.Error = result.Err.Error()
+		}
+
+		if err := stream.Send(out); err != nil {
+			return err
+		}
+	}
+
+	return <-recvErr
+}
+
+func createCredentialRequest(in *apiv1_issuer.MakeSDJWTRequest) *apiv1.CreateCredentialRequest {
+	return &apiv1.CreateCredentialRequest{
+		DocumentType:        in.GetDocumentType(),
+		DocumentData:        in.GetDocumentData(),
+		ConsentIDs:          in.GetConsentIDs(),
+		DryRun:              in.GetDryRun(),
+		CredentialType:      in.GetCredentialType(),
+		PreviousDisclosures: in.GetPreviousDisclosures(),
+		HolderJWK:           in.GetHolderJWK(),
+	}
+}
+
+func makeSDJWTReply(reply *apiv1.CreateCredentialReply) (*apiv1_issuer.MakeSDJWTReply, error) {
+	if reply.Preview != nil {
+		payload, err := json.Marshal(reply.Preview.Payload)
+		if err != nil {
+			return nil, err
+		}
+		return &apiv1_issuer.MakeSDJWTReply{
+			Disclosures: reply.Preview.Disclosures,
+			Payload:     string(payload),
+		}, nil
+	}
+
 	return &apiv1_issuer.MakeSDJWTReply{
 		Jwt:         reply.Data.JWT,
 		Disclosures: reply.Data.Disclosures,
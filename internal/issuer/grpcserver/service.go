@@ -7,6 +7,7 @@ import (
 	"vc/internal/issuer/apiv1"
 	"vc/pkg/logger"
 	"vc/pkg/model"
+	"vc/pkg/trace"
 
 	"google.golang.org/grpc"
 )
@@ -33,7 +34,7 @@ func New(ctx context.Context, cfg *model.Cfg, apiv1 *apiv1.Client, log *logger.L
 		s.log.Error(err, "failed to listen", "addr", s.cfg.Issuer.GRPCServer.Addr)
 	}
 
-	s.server = grpc.NewServer()
+	s.server = grpc.NewServer(trace.GRPCServerOption())
 	apiv1_issuer.RegisterIssuerServiceServer(s.server, s)
 	s.log.Info("gRPC server listening")
 	if err := s.server.Serve(listener); err != nil {
@@ -2,10 +2,15 @@ package httpserver
 
 import (
 	"context"
+	"vc/internal/gen/issuer/apiv1_issuer"
 	"vc/internal/gen/status/apiv1_status"
+	"vc/internal/issuer/apiv1"
 )
 
 // Apiv1 interface
 type Apiv1 interface {
 	Health(ctx context.Context, req *apiv1_status.StatusRequest) (*apiv1_status.StatusReply, error)
+	JWKS(ctx context.Context, req *apiv1_issuer.Empty) (*apiv1_issuer.JwksReply, error)
+	ExportAuditLog(ctx context.Context, req *apiv1.ExportAuditLogRequest) (*apiv1.ExportAuditLogReply, error)
+	MakeCWT(ctx context.Context, req *apiv1.CreateCWTRequest) (*apiv1.CreateCWTReply, error)
 }
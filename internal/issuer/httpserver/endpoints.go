@@ -2,7 +2,9 @@ package httpserver
 
 import (
 	"context"
+	"vc/internal/gen/issuer/apiv1_issuer"
 	"vc/internal/gen/status/apiv1_status"
+	"vc/internal/issuer/apiv1"
 
 	"go.opentelemetry.io/otel/codes"
 
@@ -21,3 +23,49 @@ func (s *Service) endpointHealth(ctx context.Context, c *gin.Context) (interface
 	}
 	return reply, nil
 }
+
+func (s *Service) endpointJWKS(ctx context.Context, c *gin.Context) (interface{}, error) {
+	ctx, span := s.tracer.Start(ctx, "httpserver:endpointJWKS")
+	defer span.End()
+
+	reply, err := s.apiv1.JWKS(ctx, &apiv1_issuer.Empty{})
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply.Jwks, nil
+}
+
+func (s *Service) endpointExportAuditLog(ctx context.Context, c *gin.Context) (interface{}, error) {
+	ctx, span := s.tracer.Start(ctx, "httpserver:endpointExportAuditLog")
+	defer span.End()
+
+	request := &apiv1.ExportAuditLogRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	reply, err := s.apiv1.ExportAuditLog(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *Service) endpointMakeCWT(ctx context.Context, c *gin.Context) (interface{}, error) {
+	ctx, span := s.tracer.Start(ctx, "httpserver:endpointMakeCWT")
+	defer span.End()
+
+	request := &apiv1.CreateCWTRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	reply, err := s.apiv1.MakeCWT(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
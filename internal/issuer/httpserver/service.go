@@ -13,8 +13,6 @@ import (
 	_ "vc/docs/issuer"
 
 	"github.com/gin-gonic/gin"
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
 // Service is the service object for httpserver
@@ -45,15 +43,30 @@ func New(ctx context.Context, cfg *model.Cfg, apiv1 *apiv1.Client, tracer *trace
 		return nil, err
 	}
 
-	rgRoot, err := s.httpHelpers.Server.Default(ctx, s.server, s.gin, s.cfg.Issuer.APIServer.Addr)
+	rgRoot, err := s.httpHelpers.Server.Default(ctx, s.server, s.gin, s.cfg.Issuer.APIServer)
 	if err != nil {
 		return nil, err
 	}
 
 	s.httpHelpers.Server.RegEndpoint(ctx, rgRoot, http.MethodGet, "health", s.endpointHealth)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgRoot, http.MethodGet, ".well-known/jwks.json", s.endpointJWKS)
 
-	rgDocs := rgRoot.Group("/swagger")
-	rgDocs.GET("/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	if err := s.httpHelpers.Server.Docs(ctx, rgRoot); err != nil {
+		return nil, err
+	}
+
+	rgAPIv1 := rgRoot.Group("api/v1")
+
+	if s.cfg.Issuer.APIServer.BasicAuth.Enabled {
+		rgAPIv1.Use(s.httpHelpers.Middleware.BasicAuth(ctx, s.cfg.Issuer.APIServer.BasicAuth.Users))
+	}
+
+	if s.cfg.Issuer.APIServer.Auth.Enabled {
+		rgAPIv1.Use(s.httpHelpers.Auth.Middleware(ctx, s.cfg.Issuer.APIServer.Auth))
+	}
+
+	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodGet, "auditlog/export", s.endpointExportAuditLog)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPost, "credential/cwt", s.endpointMakeCWT)
 
 	// Run http server
 	go func() {
@@ -68,8 +81,23 @@ func New(ctx context.Context, cfg *model.Cfg, apiv1 *apiv1.Client, tracer *trace
 	return s, nil
 }
 
-// Close closing httpserver
+// Reload applies the rate limit and body size limits of a reloaded configuration
+func (s *Service) Reload(ctx context.Context, cfg *model.Cfg) error {
+	s.httpHelpers.Server.UpdateLimits(ctx, cfg.Issuer.APIServer)
+	return nil
+}
+
+// AddHealthCheck adds a dependency check to the readiness probe
+func (s *Service) AddHealthCheck(name string, check httphelpers.HealthCheck) {
+	s.httpHelpers.Server.AddHealthCheck(name, check)
+}
+
+// Close drains in-flight requests and stops the httpserver
 func (s *Service) Close(ctx context.Context) error {
+	if err := s.httpHelpers.Server.Shutdown(ctx, s.server, s.cfg.Issuer.APIServer); err != nil {
+		return err
+	}
+
 	s.log.Info("Stopped")
 	return nil
 }
@@ -0,0 +1,229 @@
+package keys
+
+import (
+	"context"
+	"crypto"
+	"encoding/base64"
+	"fmt"
+	"time"
+	"vc/pkg/helpers"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+	"vc/pkg/signing"
+
+	"github.com/lestrrat-go/jwx/jwk"
+)
+
+// Key is a signing key with a validity window, a zero NotBefore or NotAfter is unbounded
+type Key struct {
+	KID       string
+	Signer    *signing.Signer
+	NotBefore time.Time
+	NotAfter  time.Time
+}
+
+// validAt returns true if the key may be used for signing at t
+func (k *Key) validAt(t time.Time) bool {
+	if !k.NotBefore.IsZero() && t.Before(k.NotBefore) {
+		return false
+	}
+	if !k.NotAfter.IsZero() && !t.Before(k.NotAfter) {
+		return false
+	}
+	return true
+}
+
+// Service holds the issuer signing keys and selects the active key for signing
+type Service struct {
+	log  *logger.Log
+	keys []*Key
+	jwks jwk.Set
+}
+
+// New loads the signing keys from cfg.Issuer.Keys, or the single key from signing_key_path and signing
+func New(ctx context.Context, cfg *model.Cfg, log *logger.Log) (*Service, error) {
+	keyCfgs := cfg.Issuer.Keys
+	if len(keyCfgs) == 0 {
+		if (cfg.Issuer.Signing.Type == "" || cfg.Issuer.Signing.Type == "software") && cfg.Issuer.SigningKeyPath == "" {
+			return nil, helpers.ErrPrivateKeyMissing
+		}
+		keyCfgs = []model.IssuerKey{{
+			SigningKeyPath: cfg.Issuer.SigningKeyPath,
+			Signing:        cfg.Issuer.Signing,
+		}}
+	}
+
+	keys := make([]*Key, 0, len(keyCfgs))
+	for _, keyCfg := range keyCfgs {
+		key, err := newKey(ctx, keyCfg)
+		if err != nil {
+			log.Error(err, "Failed to load signing key, for type software please create a ECDSA prime256v1 key and save it to the path", "kid", keyCfg.KID)
+			for _, loaded := range keys {
+				loaded.Signer.Close()
+			}
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	s, err := NewStatic(log, keys...)
+	if err != nil {
+		// keys after the failing one were never added to s, close every loaded key
+		for _, loaded := range keys {
+			loaded.Signer.Close()
+		}
+		return nil, err
+	}
+
+	if _, err := s.Active(time.Now()); err != nil {
+		s.log.Info("No signing key is active now", "keys", len(s.keys))
+	}
+
+	s.log.Info("Started")
+
+	return s, nil
+}
+
+// NewStatic creates a service holding already loaded keys
+func NewStatic(log *logger.Log, keys ...*Key) (*Service, error) {
+	s := &Service{
+		log:  log.New("keys"),
+		jwks: jwk.NewSet(),
+	}
+
+	for _, key := range keys {
+		if err := s.add(key); err != nil {
+			return s, err
+		}
+		s.log.Info("Signing key loaded", "kid", key.KID, "alg", key.Signer.Algorithm())
+	}
+
+	return s, nil
+}
+
+// newKey parses the validity window before the signer is created, so a failing key leaves no key manager session open
+func newKey(ctx context.Context, cfg model.IssuerKey) (*Key, error) {
+	key := &Key{
+		KID: cfg.KID,
+	}
+
+	var err error
+	if cfg.NotBefore != "" {
+		if key.NotBefore, err = time.Parse(time.RFC3339, cfg.NotBefore); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.NotAfter != "" {
+		if key.NotAfter, err = time.Parse(time.RFC3339, cfg.NotAfter); err != nil {
+			return nil, err
+		}
+	}
+
+	if key.Signer, err = signing.New(ctx, cfg.Signing, cfg.SigningKeyPath); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// add adds key to the service and its public key to the JWKS, the kid defaults to the JWK thumbprint
+func (s *Service) add(key *Key) error {
+	publicKey, err := jwk.New(key.Signer.Public())
+	if err != nil {
+		return err
+	}
+
+	if key.KID == "" {
+		thumbprint, err := publicKey.Thumbprint(crypto.SHA256)
+		if err != nil {
+			return err
+		}
+		key.KID = base64.RawURLEncoding.EncodeToString(thumbprint)
+	}
+
+	if err := publicKey.Set(jwk.KeyIDKey, key.KID); err != nil {
+		return err
+	}
+	if err := publicKey.Set(jwk.AlgorithmKey, key.Signer.Algorithm()); err != nil {
+		return err
+	}
+	if err := publicKey.Set(jwk.KeyUsageKey, string(jwk.ForSignature)); err != nil {
+		return err
+	}
+
+	s.keys = append(s.keys, key)
+	s.jwks.Add(publicKey)
+
+	return nil
+}
+
+// Active returns the key to sign with at t, the valid key with the latest not before is preferred
+func (s *Service) Active(t time.Time) (*Key, error) {
+	return active(s.keys, t)
+}
+
+// ByAlgorithm returns the key to sign with at t among the keys with the JOSE algorithm alg, as Active
+func (s *Service) ByAlgorithm(alg string, t time.Time) (*Key, error) {
+	var keys []*Key
+	for _, key := range s.keys {
+		if key.Signer.Algorithm() == alg {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no signing key with algorithm %s is configured", alg)
+	}
+
+	return active(keys, t)
+}
+
+// active returns the valid key of keys at t with the latest not before
+func active(keys []*Key, t time.Time) (*Key, error) {
+	var active *Key
+	for _, key := range keys {
+		if !key.validAt(t) {
+			continue
+		}
+		if active == nil || key.NotBefore.After(active.NotBefore) {
+			active = key
+		}
+	}
+
+	if active == nil {
+		return nil, helpers.ErrNoActiveSigningKey
+	}
+
+	return active, nil
+}
+
+// JWKS returns the public keys of all configured keys, retired keys stay published to verify credentials already issued
+func (s *Service) JWKS() jwk.Set {
+	return s.jwks
+}
+
+// Close closes the key manager sessions
+func (s *Service) Close(ctx context.Context) error {
+	for _, key := range s.keys {
+		if err := key.Signer.Close(); err != nil {
+			s.log.Error(err, "close signing key", "kid", key.KID)
+		}
+	}
+
+	s.log.Info("Stopped")
+	return nil
+}
+
+// ByKID returns the key with kid if it may be used for signing at t
+func (s *Service) ByKID(kid string, t time.Time) (*Key, error) {
+	for _, key := range s.keys {
+		if key.KID != kid {
+			continue
+		}
+		if !key.validAt(t) {
+			return nil, helpers.ErrNoActiveSigningKey
+		}
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("signing key %s is not configured", kid)
+}
@@ -0,0 +1,158 @@
+package keys
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+	"time"
+	"vc/pkg/helpers"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+	"vc/pkg/signing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockService(t *testing.T, keys ...*Key) *Service {
+	for _, key := range keys {
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, err)
+
+		key.Signer, err = signing.NewSoftware(privateKey)
+		assert.NoError(t, err)
+	}
+
+	s, err := NewStatic(logger.NewSimple("testing_keys"), keys...)
+	assert.NoError(t, err)
+
+	return s
+}
+
+func TestActive(t *testing.T) {
+	rotation := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s := mockService(t,
+		&Key{KID: "old", NotAfter: rotation.Add(time.Hour)},
+		&Key{KID: "new", NotBefore: rotation},
+		&Key{KID: "retired", NotAfter: rotation.Add(-time.Hour)},
+	)
+
+	tts := []struct {
+		name    string
+		at      time.Time
+		wantKID string
+	}{
+		{name: "only old", at: rotation.Add(-time.Minute), wantKID: "old"},
+		{name: "overlap prefers newest", at: rotation.Add(time.Minute), wantKID: "new"},
+		{name: "after rotation", at: rotation.Add(2 * time.Hour), wantKID: "new"},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := s.Active(tt.at)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantKID, key.KID)
+		})
+	}
+
+	assert.Equal(t, 3, s.JWKS().Len())
+}
+
+func TestActiveNoKey(t *testing.T) {
+	s := mockService(t, &Key{KID: "future", NotBefore: time.Now().Add(time.Hour)})
+
+	_, err := s.Active(time.Now())
+	assert.ErrorIs(t, err, helpers.ErrNoActiveSigningKey)
+}
+
+func TestDefaultKID(t *testing.T) {
+	s := mockService(t, &Key{})
+
+	key, err := s.Active(time.Now())
+	assert.NoError(t, err)
+	assert.NotEmpty(t, key.KID)
+
+	_, ok := s.JWKS().LookupKeyID(key.KID)
+	assert.True(t, ok)
+}
+
+func TestByKID(t *testing.T) {
+	s := mockService(t,
+		&Key{KID: "default"},
+		&Key{KID: "tenant"},
+		&Key{KID: "retired", NotAfter: time.Now().Add(-time.Hour)},
+	)
+
+	key, err := s.ByKID("tenant", time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, "tenant", key.KID)
+
+	_, err = s.ByKID("retired", time.Now())
+	assert.ErrorIs(t, err, helpers.ErrNoActiveSigningKey)
+
+	_, err = s.ByKID("missing", time.Now())
+	assert.Error(t, err)
+}
+
+func TestByAlgorithm(t *testing.T) {
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	assert.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	_, edKeyOld, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	rotation := time.Now().Add(-time.Hour)
+	keys := []*Key{
+		{KID: "arf", NotBefore: rotation},
+		{KID: "es384"},
+		{KID: "internal", NotBefore: rotation},
+		{KID: "internal-old", NotAfter: rotation.Add(2 * time.Hour)},
+	}
+	for i, privateKey := range []crypto.Signer{p256, p384, edKey, edKeyOld} {
+		keys[i].Signer, err = signing.NewSoftware(privateKey)
+		assert.NoError(t, err)
+	}
+	s, err := NewStatic(logger.NewSimple("testing_keys"), keys...)
+	assert.NoError(t, err)
+
+	tts := []struct {
+		alg     string
+		wantKID string
+	}{
+		{alg: "ES256", wantKID: "arf"},
+		{alg: "ES384", wantKID: "es384"},
+		{alg: "EdDSA", wantKID: "internal"},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.alg, func(t *testing.T) {
+			key, err := s.ByAlgorithm(tt.alg, time.Now())
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantKID, key.KID)
+			assert.Equal(t, tt.alg, key.Signer.Algorithm())
+		})
+	}
+
+	_, err = s.ByAlgorithm("ES512", time.Now())
+	assert.Error(t, err)
+
+	_, err = s.ByAlgorithm("ES256", rotation.Add(-time.Minute))
+	assert.ErrorIs(t, err, helpers.ErrNoActiveSigningKey)
+}
+
+func TestNewKeyInvalidValidity(t *testing.T) {
+	// the validity is parsed first, the signer of the missing key file is never created
+	_, err := newKey(context.Background(), model.IssuerKey{
+		SigningKeyPath: "/nonexistent/private_ec256.pem",
+		NotBefore:      "tomorrow",
+	})
+	var parseErr *time.ParseError
+	assert.ErrorAs(t, err, &parseErr)
+}
@@ -20,9 +20,12 @@ type Client struct {
 	log        *logger.Log
 	tracer     *trace.Tracer
 	httpClient *http.Client
+	generator  *generator
+	wallet     *wallet
 
 	PDA1 *PDA1Service
 	EHIC *EHICService
+	ELM  *ELMService
 }
 
 // New creates a new instance of the public api
@@ -32,9 +35,17 @@ func New(ctx context.Context, cfg *model.Cfg, tracer *trace.Tracer, log *logger.
 		log:        log.New("apiv1"),
 		tracer:     tracer,
 		httpClient: &http.Client{},
+		generator:  newGenerator(cfg.MockAS.Seed),
 
 		PDA1: &PDA1Service{},
 		EHIC: &EHICService{},
+		ELM:  &ELMService{},
+	}
+
+	var err error
+	c.wallet, err = newWallet()
+	if err != nil {
+		return nil, err
 	}
 
 	c.PDA1 = &PDA1Service{
@@ -43,14 +54,17 @@ func New(ctx context.Context, cfg *model.Cfg, tracer *trace.Tracer, log *logger.
 	c.EHIC = &EHICService{
 		Client: c,
 	}
+	c.ELM = &ELMService{
+		Client: c,
+	}
 
-	c.log.Info("Started")
+	c.log.Info("Started", "seed", c.generator.getState().Seed)
 
 	return c, nil
 }
 
-func (c *Client) randomISO31661Alpha3EU() string {
-	return gofakeit.RandomString([]string{
+func (c *Client) randomISO31661Alpha3EU(f *gofakeit.Faker) string {
+	return f.RandomString([]string{
 		"AUT", "BEL", "BGR", "HRV", "CYP",
 		"CZE", "DNK", "EST", "FIN", "FRA",
 		"DEU", "GRC", "HUN", "IRL", "ITA",
@@ -60,8 +74,8 @@ func (c *Client) randomISO31661Alpha3EU() string {
 	})
 }
 
-func (c *Client) randomISO31661Alpha2EU() string {
-	return gofakeit.RandomString([]string{
+func (c *Client) randomISO31661Alpha2EU(f *gofakeit.Faker) string {
+	return f.RandomString([]string{
 		"AT", "BE", "BG", "HR", "CY",
 		"CZ", "DK", "EE", "FI", "FR",
 		"DE", "GR", "HU", "IE", "IT",
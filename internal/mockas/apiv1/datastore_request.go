@@ -86,6 +86,12 @@ func (c *Client) do(ctx context.Context, req *http.Request, value any) (*http.Re
 
 	c.log.Debug("do", "body", resp.Body, "status", resp.StatusCode)
 
+	if value != nil {
+		if err := json.NewDecoder(resp.Body).Decode(value); err != nil {
+			return resp, err
+		}
+	}
+
 	return resp, nil
 }
 
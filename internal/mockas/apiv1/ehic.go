@@ -14,40 +14,40 @@ type EHICService struct {
 	Client *Client
 }
 
-func (s *EHICService) random(ctx context.Context, person *gofakeit.PersonInfo) map[string]any {
+func (s *EHICService) random(ctx context.Context, f *gofakeit.Faker, person *gofakeit.PersonInfo) map[string]any {
 	doc := ehic.Document{
 		PID: eidas.Identification{
 			FirstName:   person.FirstName,
 			LastName:    person.LastName,
 			Gender:      person.Gender,
 			PINS:        []string{},
-			ExhibitorID: gofakeit.Numerify("##########"),
+			ExhibitorID: f.Numerify("##########"),
 		},
 		CardHolder: ehic.CardHolder{
 			FamilyName:       person.LastName,
 			GivenName:        person.FirstName,
-			BirthDate:        gofakeit.Date().String(),
-			ID:               gofakeit.UUID(),
-			CardholderStatus: gofakeit.RandomString([]string{"active", "inactive"}),
+			BirthDate:        f.Date().String(),
+			ID:               f.UUID(),
+			CardholderStatus: f.RandomString([]string{"active", "inactive"}),
 		},
 		CompetentInstitution: ehic.CompetentInstitution{
-			InstitutionName: gofakeit.Company(),
-			ID:              gofakeit.UUID(),
+			InstitutionName: f.Company(),
+			ID:              f.UUID(),
 		},
 		CardInformation: ehic.CardInformation{
-			ID:           gofakeit.UUID(),
-			IssuanceDate: gofakeit.Date().String(),
-			ValidSince:   gofakeit.Date().String(),
-			ExpiryDate:   gofakeit.Date().String(),
-			InvalidSince: gofakeit.Date().String(),
+			ID:           f.UUID(),
+			IssuanceDate: f.Date().String(),
+			ValidSince:   f.Date().String(),
+			ExpiryDate:   f.Date().String(),
+			InvalidSince: f.Date().String(),
 			Signature: ehic.Signature{
-				Issuer: gofakeit.Company(),
-				Seal:   gofakeit.UUID(),
+				Issuer: f.Company(),
+				Seal:   f.UUID(),
 			},
 		},
 		Signature: ehic.Signature{
-			Issuer: gofakeit.Company(),
-			Seal:   gofakeit.UUID(),
+			Issuer: f.Company(),
+			Seal:   f.UUID(),
 		},
 	}
 
@@ -0,0 +1,85 @@
+package apiv1
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+	"vc/pkg/education"
+
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+// ELMService holds the ELM document type
+type ELMService struct {
+	Client *Client
+}
+
+// elmQualification is a qualification mock learning achievements are awarded for
+type elmQualification struct {
+	title        string
+	eqfLevel     int
+	iscedfCode   string
+	creditPoints float64
+}
+
+var elmQualifications = []elmQualification{
+	{title: "Bachelor of Science in Computer Science", eqfLevel: 6, iscedfCode: "0613", creditPoints: 180},
+	{title: "Master of Science in Electrical Engineering", eqfLevel: 7, iscedfCode: "0713", creditPoints: 120},
+	{title: "Bachelor of Arts in History", eqfLevel: 6, iscedfCode: "0222", creditPoints: 180},
+	{title: "Master of Laws", eqfLevel: 7, iscedfCode: "0421", creditPoints: 90},
+	{title: "Doctor of Philosophy in Physics", eqfLevel: 8, iscedfCode: "0533", creditPoints: 240},
+	{title: "Vocational Certificate in Nursing", eqfLevel: 4, iscedfCode: "0913", creditPoints: 60},
+}
+
+func (s *ELMService) random(ctx context.Context, f *gofakeit.Faker, person *gofakeit.PersonInfo) map[string]any {
+	birth := f.DateRange(time.Date(1950, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2005, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	doc := education.Document{
+		Learner: education.Person{
+			GivenName:          person.FirstName,
+			FamilyName:         person.LastName,
+			DateOfBirth:        birth.Format(time.DateOnly),
+			NationalID:         f.Numerify("##########"),
+			CitizenshipCountry: s.Client.randomISO31661Alpha2EU(f),
+		},
+		IssuanceDate: time.Now().UTC().Format(time.DateOnly),
+	}
+
+	for i := f.Number(1, 3); i > 0; i-- {
+		qualification := elmQualifications[f.Number(0, len(elmQualifications)-1)]
+		awarded := birth.AddDate(f.Number(19, 40), f.Number(0, 11), 0)
+		if awarded.After(time.Now()) {
+			awarded = time.Now().UTC()
+		}
+
+		doc.LearningAchievements = append(doc.LearningAchievements, education.LearningAchievement{
+			ID:    "urn:uuid:" + f.UUID(),
+			Title: qualification.title,
+			AwardingBody: education.Organisation{
+				ID:        f.Numerify("##########"),
+				LegalName: f.Company() + " University",
+				Country:   s.Client.randomISO31661Alpha2EU(f),
+			},
+			AwardingDate: awarded.Format(time.DateOnly),
+			SpecifiedBy: education.Qualification{
+				Title:        qualification.title,
+				EQFLevel:     qualification.eqfLevel,
+				ISCEDFCode:   qualification.iscedfCode,
+				CreditPoints: qualification.creditPoints,
+			},
+			Grade: f.RandomString([]string{"A", "B", "C", "D", "E", "Pass"}),
+		})
+	}
+
+	d, err := json.Marshal(doc)
+	if err != nil {
+		panic(err)
+	}
+
+	var t map[string]any
+	if err := json.Unmarshal(d, &t); err != nil {
+		panic(err)
+	}
+
+	return t
+}
@@ -0,0 +1,63 @@
+package apiv1
+
+import (
+	"sync"
+
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+// GeneratorState is the state of the mock data generator, restoring it makes the following documents repeat
+type GeneratorState struct {
+	Seed int64 `json:"seed" validate:"required"`
+	Next int64 `json:"next" validate:"gte=0"`
+}
+
+// generator hands out fakers seeded from a base seed and a sequence number, so document n of a seed is always the same
+type generator struct {
+	mu    sync.Mutex
+	state GeneratorState
+}
+
+// newGenerator returns a generator for seed, a zero seed is replaced with a random one
+func newGenerator(seed int64) *generator {
+	for seed == 0 {
+		seed = gofakeit.Int64()
+	}
+
+	return &generator{
+		state: GeneratorState{Seed: seed},
+	}
+}
+
+// faker returns the faker of the next document
+func (g *generator) faker() *gofakeit.Faker {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// splitmix64 of the sequence number, a zero seed would make gofakeit use a random seed
+	z := uint64(g.state.Seed) + uint64(g.state.Next+1)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	z ^= z >> 31
+	if z == 0 {
+		z = 1
+	}
+
+	g.state.Next++
+
+	return gofakeit.New(int64(z))
+}
+
+func (g *generator) getState() GeneratorState {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.state
+}
+
+func (g *generator) setState(state GeneratorState) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.state = state
+}
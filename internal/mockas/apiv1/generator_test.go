@@ -0,0 +1,75 @@
+package apiv1
+
+import (
+	"context"
+	"testing"
+	"time"
+	"vc/pkg/logger"
+	"vc/pkg/schemaregistry"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockClient(t *testing.T) *Client {
+	c := &Client{
+		log: logger.NewSimple("testing_apiv1"),
+	}
+	c.PDA1 = &PDA1Service{Client: c}
+	c.EHIC = &EHICService{Client: c}
+	c.ELM = &ELMService{Client: c}
+
+	return c
+}
+
+func TestGenerator(t *testing.T) {
+	ctx := context.Background()
+	c := mockClient(t)
+	input := MockInputData{DocumentType: "EHIC", AuthenticSource: "SUNET"}
+
+	generate := func(g *generator) *uploadMock {
+		upload, err := c.mockOne(ctx, g, input)
+		assert.NoError(t, err)
+		return upload
+	}
+
+	g1, g2 := newGenerator(42), newGenerator(42)
+
+	first, second := generate(g1), generate(g1)
+	assert.NotEqual(t, first.Meta.DocumentID, second.Meta.DocumentID)
+
+	// same seed, same sequence
+	assert.Equal(t, first.DocumentData, generate(g2).DocumentData)
+	assert.Equal(t, second.Identities, generate(g2).Identities)
+
+	// restoring the state repeats the following documents
+	state := g1.getState()
+	third := generate(g1)
+	g1.setState(state)
+	assert.Equal(t, third.Meta.DocumentID, generate(g1).Meta.DocumentID)
+
+	// another seed, another sequence
+	assert.NotEqual(t, first.Meta.DocumentID, generate(newGenerator(43)).Meta.DocumentID)
+}
+
+func TestELMDocumentConformsToSchema(t *testing.T) {
+	ctx := context.Background()
+	c := mockClient(t)
+	registry := schemaregistry.New(time.Hour, schemaregistry.BuiltinSource())
+	g := newGenerator(42)
+
+	for i := 0; i < 10; i++ {
+		upload, err := c.mockOne(ctx, g, MockInputData{DocumentType: "ELM", AuthenticSource: "SUNET"})
+		assert.NoError(t, err)
+		assert.NoError(t, registry.Validate(ctx, "ELM", upload.DocumentDataVersion, upload.DocumentData))
+	}
+}
+
+func TestReadScenario(t *testing.T) {
+	scenario, err := ReadScenario("../../../developer_tools/scenario.yaml")
+	assert.NoError(t, err)
+
+	assert.Equal(t, int64(42), scenario.Seed)
+	assert.Equal(t, 10, scenario.Persons)
+	assert.Len(t, scenario.Documents, 2)
+	assert.Equal(t, 1, scenario.Documents[0].Revoked)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"vc/internal/gen/status/apiv1_status"
+	"vc/pkg/helpers"
 	"vc/pkg/model"
 )
 
@@ -22,7 +23,7 @@ func (c *Client) MockNext(ctx context.Context, inData *MockNextRequest) (*MockNe
 	ctx, span := c.tracer.Start(ctx, "apiv1:MockNext")
 	defer span.End()
 
-	mockUpload, err := c.mockOne(ctx, inData.MockInputData)
+	mockUpload, err := c.mockOne(ctx, c.requestGenerator(inData.Seed), inData.MockInputData)
 	if err != nil {
 		return nil, err
 	}
@@ -66,8 +67,9 @@ func (c *Client) MockBulk(ctx context.Context, inData *MockBulkRequest) (*MockBu
 		return nil, errors.New("n must be greater than 0")
 	}
 
+	g := c.requestGenerator(inData.Seed)
 	for i := 0; i < inData.N; i++ {
-		mockUpload, err := c.mockOne(ctx, inData.MockInputData)
+		mockUpload, err := c.mockOne(ctx, g, inData.MockInputData)
 		if err != nil {
 			return nil, err
 		}
@@ -88,6 +90,32 @@ func (c *Client) MockBulk(ctx context.Context, inData *MockBulkRequest) (*MockBu
 	}, nil
 }
 
+// requestGenerator returns a generator for the seed of a request, or the global generator if seed is zero
+func (c *Client) requestGenerator(seed int64) *generator {
+	if seed == 0 {
+		return c.generator
+	}
+	return newGenerator(seed)
+}
+
+// GetGeneratorState returns the state of the global mock data generator
+func (c *Client) GetGeneratorState(ctx context.Context) (*GeneratorState, error) {
+	state := c.generator.getState()
+	return &state, nil
+}
+
+// SetGeneratorState restores the state of the global mock data generator
+func (c *Client) SetGeneratorState(ctx context.Context, state *GeneratorState) (*GeneratorState, error) {
+	if err := helpers.CheckSimple(state); err != nil {
+		return nil, err
+	}
+
+	c.generator.setState(*state)
+	c.log.Info("Generator state restored", "seed", state.Seed, "next", state.Next)
+
+	return state, nil
+}
+
 // Health returns the status of the service
 func (c *Client) Health(ctx context.Context, req *apiv1_status.StatusRequest) (*apiv1_status.StatusReply, error) {
 	ctx, span := c.tracer.Start(ctx, "apiv1:Health")
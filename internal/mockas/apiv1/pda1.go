@@ -14,35 +14,35 @@ type PDA1Service struct {
 	Client *Client
 }
 
-func (s *PDA1Service) random(ctx context.Context, person *gofakeit.PersonInfo) map[string]any {
+func (s *PDA1Service) random(ctx context.Context, f *gofakeit.Faker, person *gofakeit.PersonInfo) map[string]any {
 	doc := pda1.Document{
 		PersonalDetails: pda1.Section1{
-			PersonalIdentificationNumber: gofakeit.Numerify("##########"),
-			Sex:                          gofakeit.RandomString([]string{"01", "02", "98", "99"}),
+			PersonalIdentificationNumber: f.Numerify("##########"),
+			Sex:                          f.RandomString([]string{"01", "02", "98", "99"}),
 			Surname:                      person.LastName,
 			Forenames:                    person.FirstName,
 			SurnameAtBirth:               person.LastName,
-			DateBirth:                    gofakeit.Date().String(),
-			Nationality:                  s.Client.randomISO31661Alpha2EU(),
+			DateBirth:                    f.Date().String(),
+			Nationality:                  s.Client.randomISO31661Alpha2EU(f),
 			PlaceBirth: pda1.BirthPlaceType{
-				Town:        gofakeit.City(),
-				Region:      gofakeit.TimeZoneRegion(),
-				CountryCode: s.Client.randomISO31661Alpha2EU(),
+				Town:        f.City(),
+				Region:      f.TimeZoneRegion(),
+				CountryCode: s.Client.randomISO31661Alpha2EU(f),
 			},
 			StateOfResidenceAddress: pda1.AddressType{
-				BuildingName: gofakeit.BuzzWord() + "building",
-				StreetNo:     gofakeit.StreetNumber(),
-				PostCode:     gofakeit.Zip(),
-				Town:         gofakeit.City(),
-				Region:       gofakeit.State(),
-				CountryCode:  s.Client.randomISO31661Alpha2EU(), // should be short version
+				BuildingName: f.BuzzWord() + "building",
+				StreetNo:     f.StreetNumber(),
+				PostCode:     f.Zip(),
+				Town:         f.City(),
+				Region:       f.State(),
+				CountryCode:  s.Client.randomISO31661Alpha2EU(f), // should be short version
 			},
 			StateOfStayAddress: pda1.AddressType{
-				CountryCode: s.Client.randomISO31661Alpha2EU(),
+				CountryCode: s.Client.randomISO31661Alpha2EU(f),
 			},
 		},
 		MemberStateLegislation: pda1.Section2{
-			MemberStateWhichLegislationApplies: s.Client.randomISO31661Alpha2EU(),
+			MemberStateWhichLegislationApplies: s.Client.randomISO31661Alpha2EU(f),
 			StartingDate:                       time.Now(),
 			EndingDate:                         time.Now().Add(time.Hour * 24 * 365 * 5),
 			CertificateForDurationActivity:     false,
@@ -68,33 +68,33 @@ func (s *PDA1Service) random(ctx context.Context, person *gofakeit.PersonInfo) m
 			Employee:                          false,
 			SelfEmployedActivity:              false,
 			EmployerSelfEmployedActivityCodes: []string{},
-			NameBusinessName:                  gofakeit.Company(),
+			NameBusinessName:                  f.Company(),
 			RegisteredAddress: pda1.AddressType{
 				BuildingName: "",
-				StreetNo:     gofakeit.StreetNumber(),
-				PostCode:     gofakeit.Zip(),
-				Town:         gofakeit.City(),
-				Region:       gofakeit.State(),
-				CountryCode:  s.Client.randomISO31661Alpha2EU(),
+				StreetNo:     f.StreetNumber(),
+				PostCode:     f.Zip(),
+				Town:         f.City(),
+				Region:       f.State(),
+				CountryCode:  s.Client.randomISO31661Alpha2EU(f),
 			},
 		},
 		ActivityEmploymentDetails: pda1.Section5{
 			WorkPlaceNames:            []pda1.WorkPlaceNameType{},
-			WorkPlaceNamesBlob:        gofakeit.Company(),
+			WorkPlaceNamesBlob:        f.Company(),
 			WorkPlaceAddresses:        []pda1.WorkPlaceAddressType{},
-			WorkPlaceAddressesBlob:    gofakeit.Address().Address,
+			WorkPlaceAddressesBlob:    f.Address().Address,
 			NoFixedAddress:            false,
 			NoFixedAddressDescription: "",
 		},
 		CompletingInstitution: pda1.Section6{
-			Name: gofakeit.Company(),
+			Name: f.Company(),
 			Address: pda1.AddressType{
-				CountryCode: s.Client.randomISO31661Alpha2EU(),
+				CountryCode: s.Client.randomISO31661Alpha2EU(f),
 			},
-			InstitutionID: gofakeit.Numerify("##########"),
-			OfficeFaxNo:   gofakeit.Phone(),
-			OfficePhoneNo: gofakeit.Phone(),
-			Email:         gofakeit.Email(),
+			InstitutionID: f.Numerify("##########"),
+			OfficeFaxNo:   f.Phone(),
+			OfficePhoneNo: f.Phone(),
+			Email:         f.Email(),
 			Date:          time.Now(),
 			Signature:     "",
 		},
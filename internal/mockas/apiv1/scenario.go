@@ -0,0 +1,125 @@
+package apiv1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+	"vc/pkg/helpers"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Scenario is a declarative description of the test data to load into apigw
+type Scenario struct {
+	// Seed makes the scenario reproducible, a random seed is used if zero
+	Seed            int64  `json:"seed" yaml:"seed"`
+	AuthenticSource string `json:"authentic_source" yaml:"authentic_source" validate:"required"`
+
+	// Persons is the number of persons to generate, each person gets all documents below
+	Persons int `json:"persons" yaml:"persons" validate:"required,gt=0"`
+
+	Documents []ScenarioDocument `json:"documents" yaml:"documents" validate:"required,min=1,dive"`
+}
+
+// ScenarioDocument describes the documents of one type generated for each person
+type ScenarioDocument struct {
+	DocumentType string `json:"document_type" yaml:"document_type" validate:"required,oneof=EHIC PDA1 ELM"`
+
+	// PerPerson is the number of documents of this type for each person
+	PerPerson int `json:"per_person" yaml:"per_person" validate:"required,gt=0"`
+
+	// Revoked is the number of each person's documents of this type uploaded as revoked
+	Revoked int `json:"revoked" yaml:"revoked" validate:"gte=0,ltefield=PerPerson"`
+
+	// CollectIDPrefix gives collect ids <prefix><person>-<document>, random collect ids are used if empty
+	CollectIDPrefix string `json:"collect_id_prefix" yaml:"collect_id_prefix"`
+}
+
+// LoadScenarioReply is the reply
+type LoadScenarioReply struct {
+	Seed        int64    `json:"seed"`
+	DocumentIDS []string `json:"document_ids"`
+	Revoked     int      `json:"revoked"`
+}
+
+// ReadScenario reads a scenario from a YAML file
+func ReadScenario(path string) (*Scenario, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	scenario := &Scenario{}
+	if err := yaml.Unmarshal(b, scenario); err != nil {
+		return nil, err
+	}
+
+	return scenario, nil
+}
+
+// LoadScenario generates the persons and documents of a scenario and uploads them to apigw
+func (c *Client) LoadScenario(ctx context.Context, scenario *Scenario) (*LoadScenarioReply, error) {
+	ctx, span := c.tracer.Start(ctx, "apiv1:LoadScenario")
+	defer span.End()
+
+	if err := helpers.CheckSimple(scenario); err != nil {
+		return nil, err
+	}
+
+	g := newGenerator(scenario.Seed)
+	reply := &LoadScenarioReply{
+		Seed:        g.getState().Seed,
+		DocumentIDS: []string{},
+	}
+
+	for p := 0; p < scenario.Persons; p++ {
+		f := g.faker()
+		person := f.Person()
+		identity := MockInputData{
+			AuthenticSource:         scenario.AuthenticSource,
+			AuthenticSourcePersonID: f.UUID(),
+			GivenName:               person.FirstName,
+			FamilyName:              person.LastName,
+			BirthDate:               f.Date().Format("2006-01-02"),
+		}
+
+		for _, doc := range scenario.Documents {
+			for d := 0; d < doc.PerPerson; d++ {
+				input := identity
+				input.DocumentType = doc.DocumentType
+				if doc.CollectIDPrefix != "" {
+					input.CollectID = fmt.Sprintf("%s%d-%d", doc.CollectIDPrefix, p, d)
+				}
+
+				mockUpload, err := c.mockOne(ctx, g, input)
+				if err != nil {
+					return nil, err
+				}
+
+				if d < doc.Revoked {
+					mockUpload.Meta.Revocation.Revoked = true
+					mockUpload.Meta.Revocation.RevokedAt = time.Now().Unix()
+					mockUpload.Meta.Revocation.Reason = "scenario"
+					reply.Revoked++
+				}
+
+				resp, err := c.uploader(ctx, mockUpload)
+				if err != nil {
+					c.log.Error(err, "failed to upload", "document_id", mockUpload.Meta.DocumentID)
+					return nil, err
+				}
+				if resp.StatusCode != 200 {
+					return nil, errors.New("upload failed")
+				}
+
+				reply.DocumentIDS = append(reply.DocumentIDS, mockUpload.Meta.DocumentID)
+			}
+		}
+	}
+
+	c.log.Info("Scenario loaded", "seed", reply.Seed, "documents", len(reply.DocumentIDS), "revoked", reply.Revoked)
+
+	return reply, nil
+}
@@ -5,8 +5,6 @@ import (
 	"time"
 	"vc/pkg/helpers"
 	"vc/pkg/model"
-
-	"github.com/brianvoe/gofakeit/v6"
 )
 
 // MockInputData is the input data for the mock function
@@ -20,6 +18,9 @@ type MockInputData struct {
 	BirthDate               string `json:"birth_date"`
 	CollectID               string `json:"collect_id"`
 	IdentitySchemaName      string `json:"identity_schema_name"`
+
+	// Seed makes the generated documents of this request deterministic, the global generator is used if zero
+	Seed int64 `json:"seed"`
 }
 
 type uploadMock struct {
@@ -30,12 +31,13 @@ type uploadMock struct {
 	DocumentDataVersion string                 `json:"document_data_version,omitempty" validate:"required,semver"`
 }
 
-func (c *Client) mockOne(ctx context.Context, data MockInputData) (*uploadMock, error) {
+func (c *Client) mockOne(ctx context.Context, g *generator, data MockInputData) (*uploadMock, error) {
 	c.log.Debug("mockOne")
-	person := gofakeit.Person()
+	f := g.faker()
+	person := f.Person()
 
 	if data.AuthenticSourcePersonID == "" {
-		data.AuthenticSourcePersonID = gofakeit.UUID()
+		data.AuthenticSourcePersonID = f.UUID()
 	}
 
 	if data.GivenName == "" {
@@ -47,15 +49,15 @@ func (c *Client) mockOne(ctx context.Context, data MockInputData) (*uploadMock,
 	}
 
 	if data.BirthDate == "" {
-		data.BirthDate = gofakeit.Date().Format("2006-01-02")
+		data.BirthDate = f.Date().Format("2006-01-02")
 	}
 
 	if data.CollectID == "" {
-		data.CollectID = gofakeit.UUID()
+		data.CollectID = f.UUID()
 	}
 
 	if data.DocumentID == "" {
-		data.DocumentID = gofakeit.UUID()
+		data.DocumentID = f.UUID()
 	}
 
 	if data.IdentitySchemaName == "" {
@@ -72,17 +74,17 @@ func (c *Client) mockOne(ctx context.Context, data MockInputData) (*uploadMock,
 			ID:         data.CollectID,
 			ValidUntil: time.Now().Add(10 * 24 * time.Hour).Unix(),
 		},
-		CredentialValidFrom: gofakeit.Date().Unix(),
-		CredentialValidTo:   gofakeit.Date().Unix(),
+		CredentialValidFrom: f.Date().Unix(),
+		CredentialValidTo:   f.Date().Unix(),
 		Revocation: &model.Revocation{
-			ID:      gofakeit.UUID(),
+			ID:      f.UUID(),
 			Revoked: false,
 			Reference: model.RevocationReference{
 				AuthenticSource: data.AuthenticSource,
 				DocumentType:    data.DocumentType,
 				DocumentID:      data.DocumentID,
 			},
-			//Reason: gofakeit.RandomString([]string{"lost", "stolen", "expired"}),
+			//Reason: f.RandomString([]string{"lost", "stolen", "expired"}),
 		},
 	}
 
@@ -116,9 +118,11 @@ func (c *Client) mockOne(ctx context.Context, data MockInputData) (*uploadMock,
 
 	switch data.DocumentType {
 	case "PDA1":
-		mockUpload.DocumentData = c.PDA1.random(ctx, person)
+		mockUpload.DocumentData = c.PDA1.random(ctx, f, person)
 	case "EHIC":
-		mockUpload.DocumentData = c.EHIC.random(ctx, person)
+		mockUpload.DocumentData = c.EHIC.random(ctx, f, person)
+	case "ELM":
+		mockUpload.DocumentData = c.ELM.random(ctx, f, person)
 	default:
 		return nil, helpers.ErrNoKnownDocumentType
 	}
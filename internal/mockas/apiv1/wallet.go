@@ -0,0 +1,336 @@
+package apiv1
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"vc/pkg/helpers"
+	"vc/pkg/model"
+	"vc/pkg/openid4vci"
+	"vc/pkg/sdjwt"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/lestrrat-go/jwx/jwk"
+)
+
+// WalletCredential is a credential held by the wallet simulator
+type WalletCredential struct {
+	ID              string `json:"id"`
+	AuthenticSource string `json:"authentic_source"`
+	DocumentType    string `json:"document_type"`
+	CredentialType  string `json:"credential_type"`
+
+	// Credential is the SD-JWT with all disclosures, <jwt>~<disclosure>~...~
+	Credential string `json:"credential"`
+
+	// NotificationID is set by apigw, for reporting what became of the credential
+	NotificationID string `json:"notification_id,omitempty"`
+	ReceivedAt     int64  `json:"received_at"`
+}
+
+// wallet keeps the received credentials in memory, in the order received, they are bound to the holder key
+type wallet struct {
+	mu          sync.Mutex
+	credentials []*WalletCredential
+	holderKey   *ecdsa.PrivateKey
+}
+
+func newWallet() (*wallet, error) {
+	holderKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &wallet{holderKey: holderKey}, nil
+}
+
+// proof returns a proof of possession of the holder key for the credential issuer audience
+func (w *wallet) proof(audience string) (*openid4vci.Proof, error) {
+	holderJWK, err := jwk.New(w.holderKey.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{"aud": audience, "iat": time.Now().Unix()})
+	token.Header["typ"] = "openid4vci-proof+jwt"
+	token.Header["jwk"] = holderJWK
+	signed, err := token.SignedString(w.holderKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &openid4vci.Proof{ProofType: openid4vci.ProofTypeJWT, JWT: signed}, nil
+}
+
+// keyBinding returns presentation with a key binding JWT for the verifier audience and nonce appended
+func (w *wallet) keyBinding(presentation, audience, nonce string) (string, error) {
+	sdHash := sha256.Sum256([]byte(presentation))
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud":     audience,
+		"nonce":   nonce,
+		"iat":     time.Now().Unix(),
+		"sd_hash": base64.RawURLEncoding.EncodeToString(sdHash[:]),
+	})
+	token.Header["typ"] = "kb+jwt"
+	signed, err := token.SignedString(w.holderKey)
+	if err != nil {
+		return "", err
+	}
+
+	return presentation + signed, nil
+}
+
+func (w *wallet) add(credential *WalletCredential) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.credentials = append(w.credentials, credential)
+}
+
+// get returns the credential id, or the last received credential if id is empty
+func (w *wallet) get(id string) (*WalletCredential, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for i := len(w.credentials) - 1; i >= 0; i-- {
+		if id == "" || w.credentials[i].ID == id {
+			return w.credentials[i], nil
+		}
+	}
+	return nil, helpers.ErrWalletCredentialNotFound
+}
+
+func (w *wallet) list() []*WalletCredential {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]*WalletCredential{}, w.credentials...)
+}
+
+// WalletCollectRequest is the request for WalletCollect, the fields are passed to the apigw credential endpoint
+type WalletCollectRequest struct {
+	AuthenticSource string          `json:"authentic_source" validate:"required"`
+	Identity        *model.Identity `json:"identity" validate:"required"`
+	DocumentType    string          `json:"document_type" validate:"required"`
+	CredentialType  string          `json:"credential_type" validate:"required"`
+	CollectID       string          `json:"collect_id" validate:"required"`
+}
+
+// credentialRequest is the apigw credential request, with a proof of possession of the wallet holder key
+type credentialRequest struct {
+	*WalletCollectRequest
+	Proof *openid4vci.Proof `json:"proof"`
+}
+
+// credentialReply is the apigw credential reply
+type credentialReply struct {
+	JWT            string   `json:"jwt"`
+	Disclosures    []string `json:"disclosures"`
+	NotificationID string   `json:"notificationID"`
+	TransactionID  string   `json:"transactionID"`
+}
+
+// WalletCollect collects a credential from apigw and stores it in the wallet
+func (c *Client) WalletCollect(ctx context.Context, req *WalletCollectRequest) (*WalletCredential, error) {
+	ctx, span := c.tracer.Start(ctx, "apiv1:WalletCollect")
+	defer span.End()
+
+	if err := helpers.CheckSimple(req); err != nil {
+		return nil, err
+	}
+
+	proof, err := c.wallet.proof(c.cfg.Issuer.JWTAttribute.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &credentialReply{}
+	if _, err := c.call(ctx, http.MethodPost, "/api/v1/credential", &credentialRequest{WalletCollectRequest: req, Proof: proof}, reply); err != nil {
+		return nil, err
+	}
+
+	if reply.JWT == "" {
+		if reply.TransactionID != "" {
+			return nil, errors.New("credential issuance is deferred, transaction id " + reply.TransactionID)
+		}
+		return nil, errors.New("apigw returned no credential")
+	}
+
+	credential := &WalletCredential{
+		ID:              uuid.NewString(),
+		AuthenticSource: req.AuthenticSource,
+		DocumentType:    req.DocumentType,
+		CredentialType:  req.CredentialType,
+		Credential:      strings.Join(append([]string{reply.JWT}, reply.Disclosures...), "~") + "~",
+		NotificationID:  reply.NotificationID,
+		ReceivedAt:      time.Now().Unix(),
+	}
+	c.wallet.add(credential)
+
+	c.log.Info("credential collected", "id", credential.ID, "document_type", credential.DocumentType)
+
+	return credential, nil
+}
+
+// WalletCredentialsReply is the reply for WalletCredentials
+type WalletCredentialsReply struct {
+	Credentials []*WalletCredential `json:"credentials"`
+}
+
+// WalletCredentials lists the credentials in the wallet, oldest first
+func (c *Client) WalletCredentials(ctx context.Context) (*WalletCredentialsReply, error) {
+	_, span := c.tracer.Start(ctx, "apiv1:WalletCredentials")
+	defer span.End()
+
+	return &WalletCredentialsReply{Credentials: c.wallet.list()}, nil
+}
+
+// WalletPresentRequest is the request for WalletPresent
+type WalletPresentRequest struct {
+	// RequestURI is the request_uri of a verifier session
+	RequestURI string `json:"request_uri" validate:"required,url"`
+
+	// CredentialID selects the credential to present, the last collected credential if empty
+	CredentialID string `json:"credential_id"`
+}
+
+// WalletPresentReply is the verifier session after the presentation
+type WalletPresentReply struct {
+	SessionID string `json:"session_id"`
+	Status    string `json:"status"`
+
+	// Result is the verification result of the verifier
+	Result map[string]any `json:"result,omitempty"`
+}
+
+// requestObject is the verifier presentation request
+type requestObject struct {
+	ClientID    string `json:"client_id"`
+	Nonce       string `json:"nonce"`
+	ResponseURI string `json:"response_uri"`
+
+	// DCQLQuery or PresentationDefinition limits the presented disclosures, all are presented without them
+	DCQLQuery              *sdjwt.DCQLQuery              `json:"dcql_query,omitempty"`
+	PresentationDefinition *sdjwt.PresentationDefinition `json:"presentation_definition,omitempty"`
+}
+
+// match returns the least disclosures of credential that satisfy the query, nil if there is no query
+func match(credential string, dcqlQuery *sdjwt.DCQLQuery, presentationDefinition *sdjwt.PresentationDefinition) (*sdjwt.QueryMatch, error) {
+	var (
+		queryMatch *sdjwt.QueryMatch
+		err        error
+	)
+	switch {
+	case dcqlQuery != nil:
+		queryMatch, err = sdjwt.MatchDCQL(credential, dcqlQuery)
+	case presentationDefinition != nil:
+		queryMatch, err = sdjwt.MatchPresentationDefinition(credential, presentationDefinition)
+	default:
+		return nil, nil
+	}
+
+	var queryErr *sdjwt.QueryError
+	if errors.As(err, &queryErr) {
+		details := make([]map[string]any, 0, len(queryErr.Reasons))
+		for _, reason := range queryErr.Reasons {
+			details = append(details, map[string]any{"message": reason})
+		}
+		return nil, helpers.NewErrorDetails("WALLET_QUERY_NOT_SATISFIED", details)
+	}
+
+	return queryMatch, err
+}
+
+// WalletPresent fetches the presentation request of a verifier session and responds with a credential from the wallet,
+// bound to the session by a key binding JWT
+func (c *Client) WalletPresent(ctx context.Context, req *WalletPresentRequest) (*WalletPresentReply, error) {
+	ctx, span := c.tracer.Start(ctx, "apiv1:WalletPresent")
+	defer span.End()
+
+	if err := helpers.CheckSimple(req); err != nil {
+		return nil, err
+	}
+
+	credential, err := c.wallet.get(req.CredentialID)
+	if err != nil {
+		return nil, err
+	}
+
+	request := &requestObject{}
+	if _, err := c.call(ctx, http.MethodGet, req.RequestURI, nil, request); err != nil {
+		return nil, err
+	}
+	if request.ResponseURI == "" {
+		return nil, errors.New("presentation request has no response_uri")
+	}
+
+	vpToken := credential.Credential
+	queryMatch, err := match(credential.Credential, request.DCQLQuery, request.PresentationDefinition)
+	if err != nil {
+		return nil, err
+	}
+	if queryMatch != nil {
+		issuerJWT, _, _ := strings.Cut(credential.Credential, "~")
+		vpToken = queryMatch.Presentation(issuerJWT)
+	}
+	if vpToken, err = c.wallet.keyBinding(vpToken, request.ClientID, request.Nonce); err != nil {
+		return nil, err
+	}
+
+	response := map[string]string{"vp_token": vpToken}
+	reply := &WalletPresentReply{}
+	if _, err := c.call(ctx, http.MethodPost, request.ResponseURI, response, reply); err != nil {
+		return nil, err
+	}
+
+	c.log.Info("credential presented", "id", credential.ID, "session_id", reply.SessionID, "status", reply.Status)
+
+	return reply, nil
+}
+
+// WalletMatchRequest is the request for WalletMatch, with either a DCQL query or a presentation definition
+type WalletMatchRequest struct {
+	// CredentialID selects the credential to match, the last collected credential if empty
+	CredentialID string `json:"credential_id"`
+
+	DCQLQuery              *sdjwt.DCQLQuery              `json:"dcql_query" validate:"required_without=PresentationDefinition"`
+	PresentationDefinition *sdjwt.PresentationDefinition `json:"presentation_definition" validate:"required_without=DCQLQuery"`
+}
+
+// WalletMatchReply is the reply for WalletMatch
+type WalletMatchReply struct {
+	sdjwt.QueryMatch
+
+	// Presentation is the credential with only the matched disclosures
+	Presentation string `json:"presentation"`
+}
+
+// WalletMatch returns the least set of disclosures of a credential in the wallet that satisfies a query, without
+// presenting it
+func (c *Client) WalletMatch(ctx context.Context, req *WalletMatchRequest) (*WalletMatchReply, error) {
+	_, span := c.tracer.Start(ctx, "apiv1:WalletMatch")
+	defer span.End()
+
+	if err := helpers.CheckSimple(req); err != nil {
+		return nil, err
+	}
+
+	credential, err := c.wallet.get(req.CredentialID)
+	if err != nil {
+		return nil, err
+	}
+
+	queryMatch, err := match(credential.Credential, req.DCQLQuery, req.PresentationDefinition)
+	if err != nil {
+		return nil, err
+	}
+
+	issuerJWT, _, _ := strings.Cut(credential.Credential, "~")
+	return &WalletMatchReply{QueryMatch: *queryMatch, Presentation: queryMatch.Presentation(issuerJWT)}, nil
+}
@@ -0,0 +1,164 @@
+package apiv1
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"vc/pkg/helpers"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+	"vc/pkg/openid4vci"
+	"vc/pkg/sdjwt"
+	"vc/pkg/trace"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWallet(t *testing.T) {
+	ctx := context.Background()
+	log := logger.NewSimple("testing_apiv1")
+
+	var (
+		vpToken string
+		proof   *openid4vci.Proof
+	)
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("POST /api/v1/credential", func(w http.ResponseWriter, r *http.Request) {
+		body := &credentialRequest{}
+		json.NewDecoder(r.Body).Decode(body)
+		proof = body.Proof
+		json.NewEncoder(w).Encode(map[string]any{"jwt": "header.payload.signature", "disclosures": []string{"d1", "d2"}, "notificationID": "n1"})
+	})
+	mux.HandleFunc("GET /verifier/api/v1/session/s1/request", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"client_id": "https://verifier.sunet.se", "response_uri": server.URL + "/verifier/api/v1/session/s1/response", "nonce": "nonce"})
+	})
+	mux.HandleFunc("POST /verifier/api/v1/session/s1/response", func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]string{}
+		json.NewDecoder(r.Body).Decode(&body)
+		vpToken = body["vp_token"]
+		json.NewEncoder(w).Encode(map[string]any{"session_id": "s1", "status": "completed", "result": map[string]any{"valid": true}})
+	})
+
+	tracer, err := trace.NewForTesting(ctx, "test", log)
+	assert.NoError(t, err)
+	cfg := &model.Cfg{
+		MockAS: model.MockAS{DatastoreURL: server.URL},
+		Issuer: model.Issuer{JWTAttribute: model.JWTAttribute{Issuer: "https://issuer.sunet.se"}},
+	}
+	c, err := New(ctx, cfg, tracer, log)
+	assert.NoError(t, err)
+
+	_, err = c.WalletPresent(ctx, &WalletPresentRequest{RequestURI: server.URL + "/verifier/api/v1/session/s1/request"})
+	assert.ErrorIs(t, err, helpers.ErrWalletCredentialNotFound)
+
+	credential, err := c.WalletCollect(ctx, &WalletCollectRequest{
+		AuthenticSource: "SUNET",
+		Identity:        &model.Identity{AuthenticSourcePersonID: "1", Schema: &model.IdentitySchema{Name: "SE"}},
+		DocumentType:    "EHIC",
+		CredentialType:  "SD-JWT",
+		CollectID:       "collect_id_1",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "header.payload.signature~d1~d2~", credential.Credential)
+	assert.Equal(t, "n1", credential.NotificationID)
+
+	holderJWK, err := proof.HolderKey(ctx, nil, "https://issuer.sunet.se")
+	assert.NoError(t, err, "the credential is requested with a proof of the holder key")
+	var holderKey any
+	assert.NoError(t, holderJWK.Raw(&holderKey))
+
+	list, err := c.WalletCredentials(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []*WalletCredential{credential}, list.Credentials)
+
+	reply, err := c.WalletPresent(ctx, &WalletPresentRequest{RequestURI: server.URL + "/verifier/api/v1/session/s1/request"})
+	assert.NoError(t, err)
+	assert.Equal(t, &WalletPresentReply{SessionID: "s1", Status: "completed", Result: map[string]any{"valid": true}}, reply)
+	presentation, kbJWT, _ := strings.Cut(vpToken, "~d1~d2~")
+	assert.Equal(t, "header.payload.signature", presentation)
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(kbJWT, claims, func(token *jwt.Token) (any, error) { return holderKey, nil })
+	assert.NoError(t, err, "the presentation has a key binding jwt of the holder key")
+	assert.Equal(t, "https://verifier.sunet.se", claims["aud"])
+	assert.Equal(t, "nonce", claims["nonce"])
+
+	_, err = c.WalletPresent(ctx, &WalletPresentRequest{RequestURI: server.URL + "/verifier/api/v1/session/s1/request", CredentialID: "unknown"})
+	assert.ErrorIs(t, err, helpers.ErrWalletCredentialNotFound)
+}
+
+func TestWalletMatch(t *testing.T) {
+	ctx := context.Background()
+	log := logger.NewSimple("testing_apiv1")
+
+	disclosure := base64.RawURLEncoding.EncodeToString([]byte(`["salt","given_name","Magnus"]`))
+	digest := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%x", sha256.Sum256([]byte(disclosure)))))
+	issuerJWT := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"ES256","typ":"dc+sd-jwt"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(`{"vct":"https://credential.sunet.se/pid","family_name":"Svensson","_sd":["`+digest+`"]}`)) + ".signature"
+
+	var vpToken string
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("POST /api/v1/credential", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"jwt": issuerJWT, "disclosures": []string{disclosure}})
+	})
+	mux.HandleFunc("GET /verifier/request", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"response_uri": server.URL + "/verifier/response",
+			"dcql_query":   map[string]any{"credentials": []any{map[string]any{"id": "pid", "format": "dc+sd-jwt", "claims": []any{map[string]any{"path": []any{"family_name"}}}}}},
+		})
+	})
+	mux.HandleFunc("POST /verifier/response", func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]string{}
+		json.NewDecoder(r.Body).Decode(&body)
+		vpToken = body["vp_token"]
+		json.NewEncoder(w).Encode(map[string]any{"session_id": "s1", "status": "completed"})
+	})
+
+	tracer, err := trace.NewForTesting(ctx, "test", log)
+	assert.NoError(t, err)
+	c, err := New(ctx, &model.Cfg{MockAS: model.MockAS{DatastoreURL: server.URL}}, tracer, log)
+	assert.NoError(t, err)
+
+	_, err = c.WalletCollect(ctx, &WalletCollectRequest{
+		AuthenticSource: "SUNET",
+		Identity:        &model.Identity{AuthenticSourcePersonID: "1", Schema: &model.IdentitySchema{Name: "SE"}},
+		DocumentType:    "PID",
+		CredentialType:  "SD-JWT",
+		CollectID:       "collect_id_1",
+	})
+	assert.NoError(t, err)
+
+	_, err = c.WalletPresent(ctx, &WalletPresentRequest{RequestURI: server.URL + "/verifier/request"})
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(vpToken, issuerJWT+"~ey"), "family_name is always visible, given_name is not requested")
+
+	reply, err := c.WalletMatch(ctx, &WalletMatchRequest{PresentationDefinition: &sdjwt.PresentationDefinition{
+		ID: "pd",
+		InputDescriptors: []sdjwt.InputDescriptor{{ID: "pid", Constraints: sdjwt.InputDescriptorConstraints{
+			Fields: []sdjwt.Field{{Path: []string{"$.given_name"}}},
+		}}},
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{disclosure}, reply.Disclosures)
+	assert.Equal(t, issuerJWT+"~"+disclosure+"~", reply.Presentation)
+
+	_, err = c.WalletMatch(ctx, &WalletMatchRequest{DCQLQuery: &sdjwt.DCQLQuery{Credentials: []sdjwt.DCQLCredentialQuery{
+		{ID: "ehic", Format: "dc+sd-jwt", Meta: sdjwt.DCQLMeta{VCTValues: []string{"https://credential.sunet.se/ehic"}}},
+	}}})
+	assert.Equal(t, "WALLET_QUERY_NOT_SATISFIED", helpers.NewProblem(err).Code)
+
+	_, err = c.WalletMatch(ctx, &WalletMatchRequest{})
+	assert.Error(t, err)
+}
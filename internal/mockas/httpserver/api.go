@@ -10,6 +10,14 @@ import (
 type Apiv1 interface {
 	MockNext(ctx context.Context, indata *apiv1.MockNextRequest) (*apiv1.MockNextReply, error)
 	MockBulk(ctx context.Context, inData *apiv1.MockBulkRequest) (*apiv1.MockBulkReply, error)
+	LoadScenario(ctx context.Context, scenario *apiv1.Scenario) (*apiv1.LoadScenarioReply, error)
+	GetGeneratorState(ctx context.Context) (*apiv1.GeneratorState, error)
+	SetGeneratorState(ctx context.Context, state *apiv1.GeneratorState) (*apiv1.GeneratorState, error)
+
+	WalletCollect(ctx context.Context, req *apiv1.WalletCollectRequest) (*apiv1.WalletCredential, error)
+	WalletCredentials(ctx context.Context) (*apiv1.WalletCredentialsReply, error)
+	WalletPresent(ctx context.Context, req *apiv1.WalletPresentRequest) (*apiv1.WalletPresentReply, error)
+	WalletMatch(ctx context.Context, req *apiv1.WalletMatchRequest) (*apiv1.WalletMatchReply, error)
 
 	Health(ctx context.Context, req *apiv1_status.StatusRequest) (*apiv1_status.StatusReply, error)
 }
@@ -40,7 +40,7 @@ func New(ctx context.Context, cfg *model.Cfg, apiv1 *apiv1.Client, tracer *trace
 		return nil, err
 	}
 
-	rgRoot, err := s.httpHelpers.Server.Default(ctx, s.server, s.gin, s.cfg.MockAS.APIServer.Addr)
+	rgRoot, err := s.httpHelpers.Server.Default(ctx, s.server, s.gin, s.cfg.MockAS.APIServer)
 	if err != nil {
 		return nil, err
 	}
@@ -51,6 +51,17 @@ func New(ctx context.Context, cfg *model.Cfg, apiv1 *apiv1.Client, tracer *trace
 	rgMock := rgAPIv1.Group("/mock")
 	s.httpHelpers.Server.RegEndpoint(ctx, rgMock, http.MethodPost, "/next", s.endpointMockNext)
 	s.httpHelpers.Server.RegEndpoint(ctx, rgMock, http.MethodPost, "/bulk", s.endpointMockBulk)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgMock, http.MethodPost, "/scenario", s.endpointLoadScenario)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgMock, http.MethodGet, "/generator", s.endpointGetGeneratorState)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgMock, http.MethodPut, "/generator", s.endpointSetGeneratorState)
+
+	if s.cfg.MockAS.Wallet.Enabled {
+		rgWallet := rgAPIv1.Group("/wallet")
+		s.httpHelpers.Server.RegEndpoint(ctx, rgWallet, http.MethodPost, "/collect", s.endpointWalletCollect)
+		s.httpHelpers.Server.RegEndpoint(ctx, rgWallet, http.MethodGet, "/credentials", s.endpointWalletCredentials)
+		s.httpHelpers.Server.RegEndpoint(ctx, rgWallet, http.MethodPost, "/present", s.endpointWalletPresent)
+		s.httpHelpers.Server.RegEndpoint(ctx, rgWallet, http.MethodPost, "/match", s.endpointWalletMatch)
+	}
 
 	// Run http server
 	go func() {
@@ -65,8 +76,17 @@ func New(ctx context.Context, cfg *model.Cfg, apiv1 *apiv1.Client, tracer *trace
 	return s, nil
 }
 
-// Close closing httpserver
+// AddHealthCheck adds a dependency check to the readiness probe
+func (s *Service) AddHealthCheck(name string, check httphelpers.HealthCheck) {
+	s.httpHelpers.Server.AddHealthCheck(name, check)
+}
+
+// Close drains in-flight requests and stops the httpserver
 func (s *Service) Close(ctx context.Context) error {
+	if err := s.httpHelpers.Server.Shutdown(ctx, s.server, s.cfg.MockAS.APIServer); err != nil {
+		return err
+	}
+
 	s.log.Info("Stopped")
 	return nil
 }
@@ -108,7 +108,7 @@ func (s *Service) connect(ctx context.Context) error {
 	//		return err
 	//	}
 
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(s.cfg.Common.Mongo.URI))
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(s.cfg.Common.Mongo.URI).SetMonitor(trace.NewMongoMonitor(nil)))
 	if err != nil {
 		return err
 	}
@@ -117,6 +117,11 @@ func (s *Service) connect(ctx context.Context) error {
 	return nil
 }
 
+// Ping checks that the database is reachable
+func (s *Service) Ping(ctx context.Context) error {
+	return s.dbClient.Ping(ctx, nil)
+}
+
 // Close closes db service
 func (s *Service) Close(ctx context.Context) error {
 	s.log.Info("Stopped")
@@ -105,3 +105,36 @@ func (c *VCDatastoreColl) Delete(ctx context.Context, doc *model.MetaData) error
 	return nil
 
 }
+
+// FindExpired returns the metadata of at most limit documents whose credential expired before the unix time before
+func (c *VCDatastoreColl) FindExpired(ctx context.Context, before, limit int64) ([]*model.MetaData, error) {
+	ctx, span := c.service.tracer.Start(ctx, "db:vc:datastore:findExpired")
+	defer span.End()
+
+	filter := bson.M{
+		"meta.valid_to": bson.M{"$gt": 0, "$lt": before},
+	}
+	opts := options.Find().SetProjection(bson.M{"meta": 1}).SetLimit(limit)
+
+	cursor, err := c.coll.Find(ctx, filter, opts)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	docs := []*model.CompleteDocument{}
+	if err := cursor.All(ctx, &docs); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	metas := make([]*model.MetaData, 0, len(docs))
+	for _, doc := range docs {
+		if doc.Meta == nil {
+			continue
+		}
+		metas = append(metas, doc.Meta)
+	}
+
+	return metas, nil
+}
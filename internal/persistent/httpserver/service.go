@@ -42,7 +42,7 @@ func New(ctx context.Context, cfg *model.Cfg, apiv1 *apiv1.Client, tracer *trace
 		return nil, err
 	}
 
-	rgRoot, err := s.httpHelpers.Server.Default(ctx, s.server, s.gin, s.cfg.Persistent.APIServer.Addr)
+	rgRoot, err := s.httpHelpers.Server.Default(ctx, s.server, s.gin, s.cfg.Persistent.APIServer)
 	if err != nil {
 		return nil, err
 	}
@@ -63,8 +63,17 @@ func New(ctx context.Context, cfg *model.Cfg, apiv1 *apiv1.Client, tracer *trace
 	return s, nil
 }
 
-// Close closing httpserver
+// AddHealthCheck adds a dependency check to the readiness probe
+func (s *Service) AddHealthCheck(name string, check httphelpers.HealthCheck) {
+	s.httpHelpers.Server.AddHealthCheck(name, check)
+}
+
+// Close drains in-flight requests and stops the httpserver
 func (s *Service) Close(ctx context.Context) error {
+	if err := s.httpHelpers.Server.Shutdown(ctx, s.server, s.cfg.Persistent.APIServer); err != nil {
+		return err
+	}
+
 	s.log.Info("Stopped")
 	return nil
 }
@@ -0,0 +1,57 @@
+package outbound
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"vc/internal/persistent/retention"
+	"vc/pkg/logger"
+	"vc/pkg/messagebroker/kafka"
+	"vc/pkg/model"
+	"vc/pkg/trace"
+
+	"github.com/IBM/sarama"
+)
+
+type kafkaMessageProducer struct {
+	client *kafka.SyncProducerClient
+}
+
+// New creates a new instance of a kafka event publisher used by persistent
+func New(ctx context.Context, cfg *model.Cfg, tracer *trace.Tracer, log *logger.Log) (retention.EventPublisher, error) {
+	saramaConfig := kafka.CommonProducerConfig(cfg)
+	client, err := kafka.NewSyncProducerClient(ctx, saramaConfig, cfg, tracer, log.New("kafka_message_producer_client"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &kafkaMessageProducer{client: client}, nil
+}
+
+// DocumentExpired publish a MetaData message of a purged document to a Kafka topic
+func (s *kafkaMessageProducer) DocumentExpired(meta *model.MetaData) error {
+	if meta == nil {
+		return errors.New("param meta is nil")
+	}
+
+	jsonMarshaled, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+
+	paramType := reflect.TypeOf(meta).Elem().Name()
+	headers := []sarama.RecordHeader{
+		{Key: []byte(kafka.TypeOfStructInMessageValue), Value: []byte(paramType)},
+	}
+
+	return s.client.PublishMessage(kafka.TopicDocumentExpired, meta.DocumentID, jsonMarshaled, headers)
+}
+
+// Close closes all resources used/started by the publisher
+func (s *kafkaMessageProducer) Close(ctx context.Context) error {
+	if s.client != nil {
+		return s.client.Close(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package retention
+
+import (
+	"context"
+	"vc/pkg/model"
+)
+
+// EventPublisher publishes deletion events for purged documents
+type EventPublisher interface {
+	DocumentExpired(meta *model.MetaData) error
+	Close(ctx context.Context) error
+}
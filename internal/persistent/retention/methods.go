@@ -0,0 +1,45 @@
+package retention
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Sweep deletes one batch of documents whose credential has expired, and returns the number of purged documents.
+// MongoDB TTL indexes can not be used since credential_valid_to is stored as unix time and not as a date.
+func (s *Service) Sweep(ctx context.Context) (int64, error) {
+	ctx, span := s.tracer.Start(ctx, "retention:sweep")
+	defer span.End()
+
+	before := time.Now().Unix() - s.cfg.Persistent.Retention.GracePeriod
+
+	expired, err := s.db.VCDatastoreColl.FindExpired(ctx, before, s.cfg.Persistent.Retention.BatchSize)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return 0, err
+	}
+
+	var purged int64
+	for _, meta := range expired {
+		if err := s.db.VCDatastoreColl.Delete(ctx, meta); err != nil {
+			s.log.Error(err, "purge failed", "document_id", meta.DocumentID, "authentic_source", meta.AuthenticSource)
+			s.failedCounter.Add(ctx, 1)
+			continue
+		}
+		purged++
+		s.purgedCounter.Add(ctx, 1)
+
+		if s.eventPublisher == nil {
+			continue
+		}
+		if err := s.eventPublisher.DocumentExpired(meta); err != nil {
+			s.log.Error(err, "publish document expired event failed", "document_id", meta.DocumentID)
+		}
+	}
+
+	s.log.Info("sweep done", "expired", len(expired), "purged", purged)
+
+	return purged, nil
+}
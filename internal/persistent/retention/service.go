@@ -0,0 +1,84 @@
+package retention
+
+import (
+	"context"
+	"sync"
+	"time"
+	"vc/internal/persistent/db"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+	"vc/pkg/trace"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Service purges expired documents from the datastore
+type Service struct {
+	cfg            *model.Cfg
+	log            *logger.Log
+	tracer         *trace.Tracer
+	db             *db.Service
+	eventPublisher EventPublisher
+	wg             *sync.WaitGroup
+	quitChan       chan struct{}
+	ticker         *time.Ticker
+
+	purgedCounter metric.Int64Counter
+	failedCounter metric.Int64Counter
+}
+
+// New creates a new retention service, eventPublisher may be nil
+func New(ctx context.Context, wg *sync.WaitGroup, db *db.Service, eventPublisher EventPublisher, tracer *trace.Tracer, cfg *model.Cfg, log *logger.Log) (*Service, error) {
+	s := &Service{
+		cfg:            cfg,
+		log:            log.New("retention"),
+		tracer:         tracer,
+		db:             db,
+		eventPublisher: eventPublisher,
+		wg:             wg,
+		quitChan:       make(chan struct{}),
+		ticker:         time.NewTicker(time.Duration(cfg.Persistent.Retention.Interval) * time.Second),
+	}
+
+	meter := otel.Meter("vc/persistent/retention")
+
+	var err error
+	s.purgedCounter, err = meter.Int64Counter("persistent.retention.purged_documents", metric.WithDescription("Number of expired documents purged"))
+	if err != nil {
+		return nil, err
+	}
+	s.failedCounter, err = meter.Int64Counter("persistent.retention.failed_purges", metric.WithDescription("Number of expired documents that could not be purged"))
+	if err != nil {
+		return nil, err
+	}
+
+	s.wg.Add(1)
+	go func() {
+		for {
+			select {
+			case <-s.ticker.C:
+				if _, err := s.Sweep(ctx); err != nil {
+					s.log.Error(err, "sweep failed")
+				}
+			case <-s.quitChan:
+				s.log.Info("Stop sweeping")
+				s.ticker.Stop()
+				s.wg.Done()
+				return
+			}
+		}
+	}()
+
+	s.log.Info("Started")
+
+	return s, nil
+}
+
+// Close closes the retention service
+func (s *Service) Close(ctx context.Context) error {
+	s.quitChan <- struct{}{}
+
+	s.log.Info("Stopped")
+	return nil
+}
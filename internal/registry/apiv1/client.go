@@ -2,28 +2,34 @@ package apiv1
 
 import (
 	"context"
+	"vc/internal/registry/statuslist"
 	"vc/internal/registry/tree"
+	"vc/internal/registry/watch"
 	"vc/pkg/logger"
 	"vc/pkg/model"
 )
 
 // Client holds the public api object
 type Client struct {
-	cfg  *model.Cfg
-	log  *logger.Log
-	tree *tree.Service
+	cfg        *model.Cfg
+	log        *logger.Log
+	tree       *tree.Service
+	statusList *statuslist.Service
+	watch      *watch.Service
 }
 
 //	@title		Registry API
 //	@version	0.1.0
-//	@BasePath	/api/v1
+//	@BasePath	/
 
 // New creates a new instance of the public api
-func New(ctx context.Context, cfg *model.Cfg, tree *tree.Service, log *logger.Log) (*Client, error) {
+func New(ctx context.Context, cfg *model.Cfg, tree *tree.Service, statusList *statuslist.Service, watch *watch.Service, log *logger.Log) (*Client, error) {
 	c := &Client{
-		cfg:  cfg,
-		log:  log.New("apiv1"),
-		tree: tree,
+		cfg:        cfg,
+		log:        log.New("apiv1"),
+		tree:       tree,
+		statusList: statusList,
+		watch:      watch,
 	}
 	c.log.Info("Started")
 
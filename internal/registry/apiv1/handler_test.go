@@ -1 +1,153 @@
 package apiv1
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"vc/internal/gen/registry/apiv1_registry"
+	"vc/internal/registry/db"
+	"vc/internal/registry/statuslist"
+	"vc/internal/registry/tree"
+	"vc/internal/registry/watch"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func mockSigningKey(t *testing.T) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "private_ec256.pem")
+	assert.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600))
+
+	return path
+}
+
+func mockClient(t *testing.T) (*Client, *db.Service) {
+	ctx := context.Background()
+	log := logger.NewSimple("testing_apiv1")
+
+	signingKeyPath := mockSigningKey(t)
+	cfg := &model.Cfg{
+		Registry: model.Registry{
+			SMT: model.SMT{
+				UpdatePeriodicity: 60,
+				InitLeaf:          "init",
+				SigningKeyPath:    signingKeyPath,
+			},
+			StatusList: model.StatusList{
+				SigningKeyPath: signingKeyPath,
+				Bits:           2,
+			},
+			DBPath: filepath.Join(t.TempDir(), "registry.db"),
+		},
+	}
+
+	dbService, err := db.New(ctx, cfg, log)
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	treeService, err := tree.New(ctx, &wg, dbService, cfg, log)
+	assert.NoError(t, err)
+	t.Cleanup(func() { treeService.Close(ctx) })
+
+	statusListService, err := statuslist.New(ctx, dbService, cfg, log)
+	assert.NoError(t, err)
+
+	watchService, err := watch.New(ctx, dbService, cfg, log)
+	assert.NoError(t, err)
+
+	client, err := New(ctx, cfg, treeService, statusListService, watchService, log)
+	assert.NoError(t, err)
+
+	return client, dbService
+}
+
+func TestRevoke(t *testing.T) {
+	ctx := context.Background()
+	client, dbService := mockClient(t)
+
+	_, err := client.Add(ctx, &apiv1_registry.AddRequest{Entity: "entity"})
+	assert.NoError(t, err)
+
+	reply, err := client.Revoke(ctx, &apiv1_registry.RevokeRequest{Entity: "entity"})
+	assert.NoError(t, err)
+	assert.True(t, reply.Status)
+
+	err = dbService.First(&model.Leaf{}, "value = ?", []byte("entity"))
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	entry, err := client.statusList.Entry(ctx, "entity")
+	assert.NoError(t, err)
+	assert.True(t, entry.Revoked)
+}
+
+// failingEventStore is a registry database where status events can not be stored
+type failingEventStore struct {
+	*db.Service
+}
+
+func (s failingEventStore) Insert(model any) error {
+	return errors.New("database is locked")
+}
+
+func TestRevokePublishFailure(t *testing.T) {
+	ctx := context.Background()
+	client, dbService := mockClient(t)
+
+	_, err := client.Add(ctx, &apiv1_registry.AddRequest{Entity: "entity"})
+	assert.NoError(t, err)
+
+	client.watch, err = watch.New(ctx, failingEventStore{dbService}, client.cfg, client.log)
+	assert.NoError(t, err)
+
+	reply, err := client.Revoke(ctx, &apiv1_registry.RevokeRequest{Entity: "entity"})
+	assert.NoError(t, err, "the revocation is committed before it is published")
+	assert.True(t, reply.Status)
+
+	entry, err := client.statusList.Entry(ctx, "entity")
+	assert.NoError(t, err)
+	assert.True(t, entry.Revoked)
+}
+
+func TestRevokeWithoutStatusListIndex(t *testing.T) {
+	ctx := context.Background()
+	client, dbService := mockClient(t)
+
+	// an entity added before the status list, it has a leaf but no index
+	assert.NoError(t, client.tree.Insert("entity"))
+
+	_, err := client.Revoke(ctx, &apiv1_registry.RevokeRequest{Entity: "entity"})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	assert.NoError(t, dbService.First(&model.Leaf{}, "value = ?", []byte("entity")), "the leaf is kept")
+}
+
+func TestAdd(t *testing.T) {
+	ctx := context.Background()
+	client, dbService := mockClient(t)
+
+	reply, err := client.Add(ctx, &apiv1_registry.AddRequest{Entity: "entity"})
+	assert.NoError(t, err)
+
+	// Allocate retries only on this error, an index collision
+	err = dbService.Insert(&model.StatusListEntry{Entity: "other", ListIndex: reply.StatusListIndex})
+	assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+
+	index, err := client.statusList.Allocate(ctx, "entity")
+	assert.NoError(t, err)
+	assert.Equal(t, reply.StatusListIndex, index, "an entity keeps its index")
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"vc/internal/gen/registry/apiv1_registry"
 	"vc/internal/gen/status/apiv1_status"
+	"vc/internal/registry/watch"
 
-	// swagger complains if this is not imported
-	_ "vc/pkg/helpers"
+	"vc/pkg/helpers"
+	"vc/pkg/merkleproof"
 	"vc/pkg/model"
+	"vc/pkg/statuslist"
 )
 
 // Add adds a new entity into the registry
@@ -17,17 +19,102 @@ func (c *Client) Add(ctx context.Context, req *apiv1_registry.AddRequest) (*apiv
 	}
 	c.log.Info("Hash added")
 
-	return nil, nil
+	index, err := c.statusList.Allocate(ctx, req.Entity)
+	if err != nil {
+		return nil, err
+	}
+
+	c.publish(ctx, req.Entity, watch.ActionAdd)
+
+	reply := &apiv1_registry.AddReply{
+		Status:          true,
+		StatusListIndex: index,
+	}
+
+	return reply, nil
 }
 
 // Revoke revokes an entity in the registry
 func (c *Client) Revoke(ctx context.Context, req *apiv1_registry.RevokeRequest) (*apiv1_registry.RevokeReply, error) {
+	// entities added before the status list have no index, validate before the leaf is removed so a failed revocation changes nothing
+	if _, err := c.statusList.Entry(ctx, req.Entity); err != nil {
+		return nil, err
+	}
+
 	if err := c.tree.Remove(req.Entity); err != nil {
 		return nil, err
 	}
 	c.log.Info("Hash revoked")
 
-	return nil, nil
+	if err := c.statusList.SetStatus(ctx, req.Entity, statuslist.PurposeRevocation, true); err != nil {
+		if rollbackErr := c.tree.Insert(req.Entity); rollbackErr != nil {
+			c.log.Error(rollbackErr, "Failed to restore leaf after failed revocation")
+		}
+		return nil, err
+	}
+
+	c.publish(ctx, req.Entity, watch.ActionRevoke)
+
+	reply := &apiv1_registry.RevokeReply{
+		Status: true,
+	}
+
+	return reply, nil
+}
+
+// Suspend suspends, or lifts the suspension of, an entity in the registry
+func (c *Client) Suspend(ctx context.Context, req *apiv1_registry.SuspendRequest) (*apiv1_registry.SuspendReply, error) {
+	if err := c.statusList.SetStatus(ctx, req.Entity, statuslist.PurposeSuspension, req.Suspended); err != nil {
+		return nil, err
+	}
+	c.log.Info("Suspension changed", "suspended", req.Suspended)
+
+	action := watch.ActionUnsuspend
+	if req.Suspended {
+		action = watch.ActionSuspend
+	}
+	c.publish(ctx, req.Entity, action)
+
+	reply := &apiv1_registry.SuspendReply{
+		Status: true,
+	}
+
+	return reply, nil
+}
+
+// publish records a status change of entity for watchers. The change is already committed when it is published, so a
+// failure is logged and not returned, watchers that miss the event still read the status list.
+func (c *Client) publish(ctx context.Context, entity, action string) {
+	entry, err := c.statusList.Entry(ctx, entity)
+	if err != nil {
+		c.log.Error(err, "Failed to read status entry for watchers", "action", action)
+		return
+	}
+
+	if err := c.watch.Publish(ctx, &model.StatusEvent{
+		Entity:    entity,
+		ListIndex: entry.ListIndex,
+		Action:    action,
+		Revoked:   entry.Revoked,
+		Suspended: entry.Suspended,
+	}); err != nil {
+		c.log.Error(err, "Failed to publish status event", "action", action)
+	}
+}
+
+// Watch sends the status events after req.ResumeToken, and then each new event, until ctx is done or send fails
+func (c *Client) Watch(ctx context.Context, req *apiv1_registry.WatchRequest, send func(*apiv1_registry.StatusEvent) error) error {
+	return c.watch.Watch(ctx, req.ResumeToken, func(event *model.StatusEvent) error {
+		return send(&apiv1_registry.StatusEvent{
+			ResumeToken:     watch.ResumeToken(event),
+			Entity:          event.Entity,
+			StatusListIndex: event.ListIndex,
+			Action:          event.Action,
+			Revoked:         event.Revoked,
+			Suspended:       event.Suspended,
+			Timestamp:       event.CreatedAt.Unix(),
+		})
+	})
 }
 
 // ValidateReply is the reply for registry
@@ -36,17 +123,6 @@ type ValidateReply struct {
 }
 
 // Validate validates an entity in the registry
-//
-//	@Summary		Validate entity
-//	@ID				registry-validate
-//	@Description	validates an entity in the registry
-//	@Tags			registry
-//	@Accept			json
-//	@Produce		json
-//	@Success		200	{object}	ValidateReply					"Success"
-//	@Failure		400	{object}	helpers.ErrorResponse			"Bad Request"
-//	@Param			req	body		apiv1_registry.ValidateRequest	true	" "
-//	@Router			/ladok/pdf/sign [post]
 func (c *Client) Validate(ctx context.Context, req *apiv1_registry.ValidateRequest) (*ValidateReply, error) {
 	valid, err := c.tree.Validate(req.Entity)
 	if err != nil {
@@ -61,6 +137,86 @@ func (c *Client) Validate(ctx context.Context, req *apiv1_registry.ValidateReque
 	return reply, nil
 }
 
+// InclusionProofRequest is the request for InclusionProof
+type InclusionProofRequest struct {
+	Entity string `json:"entity" validate:"required"`
+}
+
+// InclusionProofReply is the reply for InclusionProof
+type InclusionProofReply struct {
+	Data *merkleproof.InclusionProof `json:"data"`
+}
+
+// InclusionProof returns a proof of inclusion for an entity in the registry
+//
+//	@Summary		Inclusion proof
+//	@ID				registry-inclusion-proof
+//	@Description	Leaf index, audit path and signed tree head for an entity, verifiable offline
+//	@Tags			registry
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	InclusionProofReply		"Success"
+//	@Failure		400	{object}	helpers.Problem			"Bad Request"
+//	@Param			req	body		InclusionProofRequest	true	" "
+//	@Router			/inclusion_proof [post]
+func (c *Client) InclusionProof(ctx context.Context, req *InclusionProofRequest) (*InclusionProofReply, error) {
+	if err := helpers.CheckSimple(req); err != nil {
+		return nil, err
+	}
+
+	proof, err := c.tree.InclusionProof(req.Entity)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &InclusionProofReply{
+		Data: proof,
+	}
+
+	return reply, nil
+}
+
+// BitstringStatusListRequest is the request for BitstringStatusList
+type BitstringStatusListRequest struct {
+	Purpose string `json:"purpose" validate:"required,oneof=revocation suspension"`
+}
+
+// BitstringStatusList returns the W3C Bitstring Status List credential for a status purpose, and its vc+jwt representation
+//
+//	@Summary		Bitstring status list
+//	@ID				registry-bitstring-status-list
+//	@Description	Bitstring status list credential for a status purpose
+//	@Tags			registry
+//	@Accept			json
+//	@Produce		json
+//	@Produce		application/vc+jwt
+//	@Success		200		{object}	statuslist.BitstringStatusListCredential	"Success"
+//	@Failure		400		{object}	helpers.Problem								"Bad Request"
+//	@Param			purpose	path		string										true	"revocation or suspension"
+//	@Router			/statuslists/{purpose} [get]
+func (c *Client) BitstringStatusList(ctx context.Context, req *BitstringStatusListRequest) (*statuslist.BitstringStatusListCredential, string, error) {
+	if err := helpers.CheckSimple(req); err != nil {
+		return nil, "", err
+	}
+
+	return c.statusList.BitstringStatusListCredential(ctx, req.Purpose)
+}
+
+// TokenStatusList returns the IETF Token Status List in JWT and CWT format
+//
+//	@Summary		Token status list
+//	@ID				registry-token-status-list
+//	@Description	Token status list referenced from the status claim of SD-JWT VCs
+//	@Tags			registry
+//	@Produce		application/statuslist+jwt
+//	@Produce		application/statuslist+cwt
+//	@Success		200	{string}	string			"Success"
+//	@Failure		400	{object}	helpers.Problem	"Bad Request"
+//	@Router			/statuslists/token [get]
+func (c *Client) TokenStatusList(ctx context.Context) (string, []byte, error) {
+	return c.statusList.TokenStatusList(ctx)
+}
+
 // Status return status for each ladok instance
 func (c *Client) Status(ctx context.Context, req *apiv1_status.StatusRequest) (*apiv1_status.StatusReply, error) {
 	probes := model.Probes{}
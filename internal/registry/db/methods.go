@@ -1,5 +1,7 @@
 package db
 
+import "time"
+
 // Find finds a model, or error
 func (s *Service) Find(model any) error {
 	tx := s.db.Find(model)
@@ -26,3 +28,48 @@ func (s *Service) Insert(model any) error {
 	}
 	return nil
 }
+
+// First finds the first model matching the query, or error
+func (s *Service) First(model any, query string, args ...any) error {
+	tx := s.db.Where(query, args...).First(model)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
+
+// Update updates the columns of a model, or error
+func (s *Service) Update(model any, values map[string]any) error {
+	tx := s.db.Model(model).Updates(values)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
+
+// FindAfter finds at most limit models with an ID greater than id, in ID order, or error
+func (s *Service) FindAfter(model any, id uint, limit int) error {
+	tx := s.db.Where("id > ?", id).Order("id").Limit(limit).Find(model)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
+
+// FindDeletedSince finds the models deleted at or after since with an ID of at most maxID, or error
+func (s *Service) FindDeletedSince(model any, since time.Time, maxID uint) error {
+	tx := s.db.Unscoped().Where("deleted_at >= ? AND id <= ?", since, maxID).Find(model)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
+
+// FirstUnscoped finds the first model matching the query, deleted or not, or error
+func (s *Service) FirstUnscoped(model any, query string, args ...any) error {
+	tx := s.db.Unscoped().Where(query, args...).First(model)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
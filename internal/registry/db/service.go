@@ -33,17 +33,26 @@ func New(ctx context.Context, cfg *model.Cfg, log *logger.Log) (*Service, error)
 
 func (s *Service) startDB() error {
 	var err error
-	s.db, err = gorm.Open(sqlite.Open("/tmp/test.db"), &gorm.Config{})
+	s.db, err = gorm.Open(sqlite.Open(s.cfg.Registry.DBPath), &gorm.Config{TranslateError: true})
 	if err != nil {
 		return err
 	}
-	if err := s.db.AutoMigrate(&model.Leaf{}); err != nil {
+	if err := s.db.AutoMigrate(&model.Leaf{}, &model.StatusListEntry{}, &model.StatusEvent{}); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// Ping checks that the database is reachable
+func (s *Service) Ping(ctx context.Context) error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
 // Close closes the database connection
 func (s *Service) Close(ctx context.Context) error {
 	s.log.Info("Stopped")
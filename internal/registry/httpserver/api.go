@@ -5,11 +5,15 @@ import (
 	"vc/internal/gen/registry/apiv1_registry"
 	"vc/internal/gen/status/apiv1_status"
 	"vc/internal/registry/apiv1"
+	"vc/pkg/statuslist"
 )
 
 // Apiv1 interface
 type Apiv1 interface {
 	Validate(ctx context.Context, req *apiv1_registry.ValidateRequest) (*apiv1.ValidateReply, error)
+	InclusionProof(ctx context.Context, req *apiv1.InclusionProofRequest) (*apiv1.InclusionProofReply, error)
+	BitstringStatusList(ctx context.Context, req *apiv1.BitstringStatusListRequest) (*statuslist.BitstringStatusListCredential, string, error)
+	TokenStatusList(ctx context.Context) (string, []byte, error)
 
 	Status(ctx context.Context, req *apiv1_status.StatusRequest) (*apiv1_status.StatusReply, error)
 }
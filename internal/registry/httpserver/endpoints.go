@@ -2,8 +2,12 @@ package httpserver
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"vc/internal/gen/registry/apiv1_registry"
 	"vc/internal/gen/status/apiv1_status"
+	"vc/internal/registry/apiv1"
+	"vc/pkg/statuslist"
 
 	"github.com/gin-gonic/gin"
 )
@@ -20,6 +24,54 @@ func (s *Service) endpointValidate(ctx context.Context, c *gin.Context) (any, er
 	return reply, nil
 }
 
+func (s *Service) endpointInclusionProof(ctx context.Context, c *gin.Context) (any, error) {
+	request := &apiv1.InclusionProofRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		return nil, err
+	}
+	reply, err := s.apiv1.InclusionProof(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *Service) endpointBitstringStatusList(ctx context.Context, c *gin.Context) (any, error) {
+	request := &apiv1.BitstringStatusListRequest{
+		Purpose: c.Param("purpose"),
+	}
+	credential, signed, err := s.apiv1.BitstringStatusList(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Header("Cache-Control", fmt.Sprintf("max-age=%d", s.cfg.Registry.StatusList.TTL))
+
+	if c.NegotiateFormat(gin.MIMEJSON, "application/vc+jwt") == "application/vc+jwt" {
+		c.Data(http.StatusOK, "application/vc+jwt", []byte(signed))
+		return nil, nil
+	}
+
+	return credential, nil
+}
+
+func (s *Service) endpointTokenStatusList(ctx context.Context, c *gin.Context) (any, error) {
+	signedJWT, signedCWT, err := s.apiv1.TokenStatusList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Header("Cache-Control", fmt.Sprintf("max-age=%d", s.cfg.Registry.StatusList.TTL))
+
+	if c.NegotiateFormat(statuslist.MediaTypeStatusListJWT, statuslist.MediaTypeStatusListCWT) == statuslist.MediaTypeStatusListCWT {
+		c.Data(http.StatusOK, statuslist.MediaTypeStatusListCWT, signedCWT)
+		return nil, nil
+	}
+
+	c.Data(http.StatusOK, statuslist.MediaTypeStatusListJWT, []byte(signedJWT))
+	return nil, nil
+}
+
 func (s *Service) endpointHealth(ctx context.Context, c *gin.Context) (any, error) {
 	request := &apiv1_status.StatusRequest{}
 	reply, err := s.apiv1.Status(ctx, request)
@@ -9,6 +9,9 @@ import (
 	"vc/pkg/model"
 	"vc/pkg/trace"
 
+	// swagger docs
+	_ "vc/docs/registry"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -40,13 +43,21 @@ func New(ctx context.Context, cfg *model.Cfg, api *apiv1.Client, tracer *trace.T
 		return nil, err
 	}
 
-	rgRoot, err := s.httpHelpers.Server.Default(ctx, s.server, s.gin, s.cfg.Registry.APIServer.Addr)
+	rgRoot, err := s.httpHelpers.Server.Default(ctx, s.server, s.gin, s.cfg.Registry.APIServer)
 	if err != nil {
 		return nil, err
 	}
 
 	s.httpHelpers.Server.RegEndpoint(ctx, rgRoot, http.MethodGet, "health", s.endpointHealth)
 
+	if err := s.httpHelpers.Server.Docs(ctx, rgRoot); err != nil {
+		return nil, err
+	}
+
+	s.httpHelpers.Server.RegEndpoint(ctx, rgRoot, http.MethodPost, "inclusion_proof", s.endpointInclusionProof)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgRoot, http.MethodGet, "statuslists/token", s.endpointTokenStatusList)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgRoot, http.MethodGet, "statuslists/:purpose", s.endpointBitstringStatusList)
+
 	// Run http server
 	go func() {
 		err := s.httpHelpers.Server.ListenAndServe(ctx, s.server, s.cfg.Registry.APIServer)
@@ -60,8 +71,17 @@ func New(ctx context.Context, cfg *model.Cfg, api *apiv1.Client, tracer *trace.T
 	return s, nil
 }
 
-// Close closing httpserver
+// AddHealthCheck adds a dependency check to the readiness probe
+func (s *Service) AddHealthCheck(name string, check httphelpers.HealthCheck) {
+	s.httpHelpers.Server.AddHealthCheck(name, check)
+}
+
+// Close drains in-flight requests and stops the httpserver
 func (s *Service) Close(ctx context.Context) error {
+	if err := s.httpHelpers.Server.Shutdown(ctx, s.server, s.cfg.Registry.APIServer); err != nil {
+		return err
+	}
+
 	s.log.Info("Stopped")
 	return nil
 }
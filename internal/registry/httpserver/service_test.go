@@ -0,0 +1,36 @@
+package httpserver
+
+import (
+	"context"
+	"testing"
+	"vc/pkg/httphelpers"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+	"vc/pkg/openapi"
+	"vc/pkg/trace"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpecDrift(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	log := logger.NewSimple("testing_httpserver")
+
+	tracer, err := trace.NewForTesting(ctx, "test", log)
+	assert.NoError(t, err)
+
+	cfg := &model.Cfg{Registry: model.Registry{APIServer: model.APIServer{Addr: "127.0.0.1:0"}}}
+
+	s, err := New(ctx, cfg, nil, tracer, log)
+	assert.NoError(t, err)
+	defer s.Close(ctx)
+
+	spec, err := openapi.Registered()
+	assert.NoError(t, err)
+
+	drift, err := openapi.Drift(spec, s.gin.Routes(), httphelpers.InfraRoutes...)
+	assert.NoError(t, err)
+	assert.Empty(t, drift, "update the swag annotations and run make swagger")
+}
@@ -2,9 +2,16 @@ package rpcserver
 
 import (
 	"context"
+	"errors"
 
 	"vc/internal/gen/registry/apiv1_registry"
 	"vc/internal/gen/status/apiv1_status"
+	"vc/internal/registry/watch"
+	"vc/pkg/helpers"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Add adds an entity to the registry
@@ -17,12 +24,38 @@ func (s *Service) Revoke(ctx context.Context, reg *apiv1_registry.RevokeRequest)
 	return s.apiv1.Revoke(ctx, reg)
 }
 
+// Suspend suspends, or lifts the suspension of, an entity in the registry
+func (s *Service) Suspend(ctx context.Context, req *apiv1_registry.SuspendRequest) (*apiv1_registry.SuspendReply, error) {
+	return s.apiv1.Suspend(ctx, req)
+}
+
 // Validate validates an entity in the registry
 func (s *Service) Validate(ctx context.Context, req *apiv1_registry.ValidateRequest) (*apiv1_registry.ValidateReply, error) {
-	return s.apiv1.Validate(ctx, req)
+	reply, err := s.apiv1.Validate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return reply.Data, nil
 }
 
 // Status returns the status of the registry
 func (s *Service) Status(ctx context.Context, req *apiv1_status.StatusRequest) (*apiv1_status.StatusReply, error) {
 	return s.apiv1.Status(ctx, req)
 }
+
+// Watch streams status changes of the registry, a disconnected client resumes with the token of the last event it received
+func (s *Service) Watch(req *apiv1_registry.WatchRequest, stream grpc.ServerStreamingServer[apiv1_registry.StatusEvent]) error {
+	err := s.apiv1.Watch(stream.Context(), req, stream.Send)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, helpers.ErrInvalidResumeToken):
+		return status.Error(codes.InvalidArgument, "INVALID_RESUME_TOKEN")
+	case errors.Is(err, watch.ErrDisconnected):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	default:
+		return err
+	}
+}
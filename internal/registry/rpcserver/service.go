@@ -7,6 +7,7 @@ import (
 	"vc/internal/registry/apiv1"
 	"vc/pkg/logger"
 	"vc/pkg/model"
+	"vc/pkg/trace"
 
 	"google.golang.org/grpc"
 )
@@ -24,9 +25,10 @@ type Service struct {
 // New creates a new gRPC server service
 func New(ctx context.Context, apiv1 *apiv1.Client, cfg *model.Cfg, log *logger.Log) (*Service, error) {
 	s := &Service{
-		log:        log,
+		apiv1:      apiv1,
+		log:        log.New("rpcserver"),
 		cfg:        cfg,
-		grpcServer: grpc.NewServer(),
+		grpcServer: grpc.NewServer(trace.GRPCServerOption()),
 	}
 
 	var err error
@@ -49,6 +51,9 @@ func New(ctx context.Context, apiv1 *apiv1.Client, cfg *model.Cfg, log *logger.L
 
 // Close closes the service
 func (s *Service) Close(ctx context.Context) error {
+	// Stop, not GracefulStop, watch streams only end when the client disconnects
+	s.grpcServer.Stop()
+
 	s.log.Info("Stopped")
 	return nil
 }
@@ -0,0 +1,361 @@
+package statuslist
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+	"vc/pkg/model"
+	sl "vc/pkg/statuslist"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxAllocationAttempts is the number of random indexes tried before giving up
+	maxAllocationAttempts = 32
+)
+
+// CWT claim keys, RFC 8392 and the IETF Token Status List specification
+const (
+	cwtClaimSub        = 2
+	cwtClaimIat        = 6
+	cwtClaimStatusList = 65533
+	cwtClaimTTL        = 65534
+)
+
+// COSE header parameters and algorithms, RFC 9052 and RFC 9053
+const (
+	coseHeaderAlg = 1
+	coseHeaderTyp = 16
+	coseAlgES256  = -7
+	coseSign1Tag  = 18
+)
+
+var (
+	// ErrStatusListFull is returned when no free index could be found
+	ErrStatusListFull = errors.New("status list is full")
+)
+
+// size returns the configured status list size
+func (s *Service) size() int {
+	if s.cfg.Registry.StatusList.Size < sl.MinBitstringSize {
+		return sl.MinBitstringSize
+	}
+	return s.cfg.Registry.StatusList.Size
+}
+
+// URL returns the public URL of the status list for purpose
+func (s *Service) URL(purpose string) string {
+	return fmt.Sprintf("%s/%s", s.cfg.Registry.StatusList.BaseURL, purpose)
+}
+
+// Allocate assigns a random, unused status list index to entity. Random allocation prevents correlation of issuance order.
+func (s *Service) Allocate(ctx context.Context, entity string) (int64, error) {
+	existing := &model.StatusListEntry{}
+	if err := s.db.First(existing, "entity = ?", entity); err == nil {
+		return existing.ListIndex, nil
+	}
+
+	max := big.NewInt(int64(s.size()))
+	for i := 0; i < maxAllocationAttempts; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return 0, err
+		}
+
+		entry := &model.StatusListEntry{
+			Entity:    entity,
+			ListIndex: n.Int64(),
+		}
+		if err := s.db.Insert(entry); err != nil {
+			if !errors.Is(err, gorm.ErrDuplicatedKey) {
+				return 0, err
+			}
+			// a concurrent allocation for the same entity won the race
+			if err := s.db.First(existing, "entity = ?", entity); err == nil {
+				return existing.ListIndex, nil
+			}
+			s.log.Debug("index already allocated", "index", entry.ListIndex)
+			continue
+		}
+
+		s.log.Debug("allocated index", "index", entry.ListIndex)
+		return entry.ListIndex, nil
+	}
+
+	return 0, ErrStatusListFull
+}
+
+// Entry returns the status list entry of entity
+func (s *Service) Entry(ctx context.Context, entity string) (*model.StatusListEntry, error) {
+	entry := &model.StatusListEntry{}
+	if err := s.db.First(entry, "entity = ?", entity); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// SetStatus sets the status bit for purpose of entity
+func (s *Service) SetStatus(ctx context.Context, entity, purpose string, value bool) error {
+	var column string
+	switch purpose {
+	case sl.PurposeRevocation:
+		column = "revoked"
+	case sl.PurposeSuspension:
+		column = "suspended"
+	default:
+		return sl.ErrUnknownStatusPurpose
+	}
+
+	entry := &model.StatusListEntry{}
+	if err := s.db.First(entry, "entity = ?", entity); err != nil {
+		return err
+	}
+
+	if err := s.db.Update(entry, map[string]any{column: value}); err != nil {
+		return err
+	}
+
+	s.invalidate()
+
+	return nil
+}
+
+// bitstring builds the bitstring for purpose from the database
+func (s *Service) bitstring(purpose string) (*sl.Bitstring, error) {
+	entries := model.StatusListEntries{}
+	if err := s.db.Find(&entries); err != nil {
+		return nil, err
+	}
+
+	list := sl.NewBitstring(s.size())
+	for _, entry := range entries {
+		value := entry.Revoked
+		if purpose == sl.PurposeSuspension {
+			value = entry.Suspended
+		}
+		if !value {
+			continue
+		}
+		if err := list.Set(int(entry.ListIndex), true); err != nil {
+			return nil, err
+		}
+	}
+
+	return list, nil
+}
+
+// cached returns the cached status list for key, or builds and caches it
+func (s *Service) cached(key string, build func() (*cachedList, error)) (*cachedList, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c, ok := s.cache[key]; ok && time.Now().Before(c.expiresAt) {
+		return c, nil
+	}
+
+	c, err := build()
+	if err != nil {
+		return nil, err
+	}
+	c.expiresAt = time.Now().Add(time.Duration(s.cfg.Registry.StatusList.TTL) * time.Second)
+	s.cache[key] = c
+
+	return c, nil
+}
+
+// BitstringStatusListCredential returns the W3C Bitstring Status List credential for purpose, and its vc+jwt representation
+func (s *Service) BitstringStatusListCredential(ctx context.Context, purpose string) (*sl.BitstringStatusListCredential, string, error) {
+	if !sl.ValidPurpose(purpose) {
+		return nil, "", sl.ErrUnknownStatusPurpose
+	}
+
+	c, err := s.cached("bitstring:"+purpose, func() (*cachedList, error) {
+		list, err := s.bitstring(purpose)
+		if err != nil {
+			return nil, err
+		}
+
+		credential, err := sl.NewBitstringStatusListCredential(s.URL(purpose), s.cfg.Registry.StatusList.Issuer, purpose, list, s.cfg.Registry.StatusList.TTL*1000)
+		if err != nil {
+			return nil, err
+		}
+
+		signed, err := s.signVCJWT(credential)
+		if err != nil {
+			return nil, err
+		}
+
+		return &cachedList{credential: credential, signed: signed}, nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return c.credential.(*sl.BitstringStatusListCredential), c.signed, nil
+}
+
+// signVCJWT secures credential according to VC-JOSE-COSE, the credential is the JWT claims set
+func (s *Service) signVCJWT(credential *sl.BitstringStatusListCredential) (string, error) {
+	claims := jwt.MapClaims{
+		"@context":          credential.Context,
+		"id":                credential.ID,
+		"type":              credential.Type,
+		"issuer":            credential.Issuer,
+		"validFrom":         credential.ValidFrom,
+		"credentialSubject": credential.CredentialSubject,
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["typ"] = "vc+jwt"
+	token.Header["cty"] = "vc"
+
+	return token.SignedString(s.signingKey)
+}
+
+// tokenStatusList builds the token status list from the database
+func (s *Service) tokenStatusList() (*sl.TokenStatusList, error) {
+	entries := model.StatusListEntries{}
+	if err := s.db.Find(&entries); err != nil {
+		return nil, err
+	}
+
+	list, err := sl.NewTokenStatusList(s.size(), s.cfg.Registry.StatusList.Bits)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		status := sl.StatusValid
+		switch {
+		case entry.Revoked:
+			status = sl.StatusInvalid
+		case entry.Suspended && list.Bits() > 1:
+			status = sl.StatusSuspended
+		}
+		if status == sl.StatusValid {
+			continue
+		}
+		if err := list.Set(int(entry.ListIndex), status); err != nil {
+			return nil, err
+		}
+	}
+
+	return list, nil
+}
+
+// TokenStatusList returns the signed token status list in JWT and CWT format
+func (s *Service) TokenStatusList(ctx context.Context) (string, []byte, error) {
+	c, err := s.cached("token", func() (*cachedList, error) {
+		list, err := s.tokenStatusList()
+		if err != nil {
+			return nil, err
+		}
+
+		signedJWT, err := s.signStatusListJWT(list)
+		if err != nil {
+			return nil, err
+		}
+
+		signedCWT, err := s.signStatusListCWT(list)
+		if err != nil {
+			return nil, err
+		}
+
+		return &cachedList{signed: signedJWT, cwt: signedCWT}, nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return c.signed, c.cwt, nil
+}
+
+// signStatusListJWT creates a status list token in JWT format
+func (s *Service) signStatusListJWT(list *sl.TokenStatusList) (string, error) {
+	statusList, err := list.JWTClaim("")
+	if err != nil {
+		return "", err
+	}
+
+	claims := jwt.MapClaims{
+		"sub":         s.URL(sl.TokenPath),
+		"iat":         time.Now().Unix(),
+		"ttl":         s.cfg.Registry.StatusList.TTL,
+		"status_list": statusList,
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["typ"] = "statuslist+jwt"
+
+	return token.SignedString(s.signingKey)
+}
+
+// signStatusListCWT creates a status list token in CWT format, secured with COSE_Sign1
+func (s *Service) signStatusListCWT(list *sl.TokenStatusList) ([]byte, error) {
+	statusList, err := list.CWTClaim("")
+	if err != nil {
+		return nil, err
+	}
+
+	em, err := cbor.CoreDetEncOptions().EncMode()
+	if err != nil {
+		return nil, err
+	}
+
+	protected, err := em.Marshal(map[int]any{
+		coseHeaderAlg: coseAlgES256,
+		coseHeaderTyp: sl.MediaTypeStatusListCWT,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := em.Marshal(map[int]any{
+		cwtClaimSub:        s.URL(sl.TokenPath),
+		cwtClaimIat:        time.Now().Unix(),
+		cwtClaimTTL:        s.cfg.Registry.StatusList.TTL,
+		cwtClaimStatusList: statusList,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	toBeSigned, err := em.Marshal([]any{"Signature1", protected, []byte{}, payload})
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := signES256(s.signingKey, toBeSigned)
+	if err != nil {
+		return nil, err
+	}
+
+	return em.Marshal(cbor.Tag{
+		Number:  coseSign1Tag,
+		Content: []any{protected, map[int]any{}, payload, signature},
+	})
+}
+
+// signES256 returns the fixed size r||s ES256 signature of data
+func signES256(key *ecdsa.PrivateKey, data []byte) ([]byte, error) {
+	digest := sha256.Sum256(data)
+	r, sig, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	sig.FillBytes(signature[32:])
+
+	return signature, nil
+}
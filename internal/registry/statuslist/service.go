@@ -0,0 +1,84 @@
+package statuslist
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"os"
+	"sync"
+	"time"
+	"vc/internal/registry/db"
+	"vc/pkg/helpers"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Service publishes the registry state as status lists
+type Service struct {
+	cfg        *model.Cfg
+	log        *logger.Log
+	db         *db.Service
+	signingKey *ecdsa.PrivateKey
+
+	mu    sync.Mutex
+	cache map[string]*cachedList
+}
+
+type cachedList struct {
+	credential any
+	signed     string
+	cwt        []byte
+	expiresAt  time.Time
+}
+
+// New creates a new status list service
+func New(ctx context.Context, db *db.Service, cfg *model.Cfg, log *logger.Log) (*Service, error) {
+	s := &Service{
+		cfg:   cfg,
+		log:   log.New("statuslist"),
+		db:    db,
+		cache: map[string]*cachedList{},
+	}
+
+	if err := s.loadSigningKey(); err != nil {
+		return nil, err
+	}
+
+	s.log.Info("Started")
+
+	return s, nil
+}
+
+func (s *Service) loadSigningKey() error {
+	keyByte, err := os.ReadFile(s.cfg.Registry.StatusList.SigningKeyPath)
+	if err != nil {
+		s.log.Error(err, "Failed to read status list signing key, please create a ECDSA prime256v1 key and save it to the path")
+		return err
+	}
+
+	if keyByte == nil {
+		return helpers.ErrPrivateKeyMissing
+	}
+
+	s.signingKey, err = jwt.ParseECPrivateKeyFromPEM(keyByte)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// invalidate drops every cached status list, must be called after a status change
+func (s *Service) invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cache = map[string]*cachedList{}
+}
+
+// Close closes the status list service
+func (s *Service) Close(ctx context.Context) error {
+	s.log.Info("Stopped")
+	return nil
+}
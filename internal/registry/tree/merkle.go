@@ -0,0 +1,167 @@
+package tree
+
+import (
+	"errors"
+
+	"github.com/wealdtech/go-merkletree"
+	"github.com/wealdtech/go-merkletree/blake2b"
+)
+
+// errLeafNotFound is returned when a value is not a leaf of the tree
+var errLeafNotFound = errors.New("data not found")
+
+// merkleTree is a Merkle tree with the node layout and hashing of merkletree.MerkleTree, so its roots and proofs are
+// the same and verify with merkletree.VerifyProof. Unlike merkletree.MerkleTree it can be appended to in place.
+type merkleTree struct {
+	hash merkletree.HashType
+
+	// data are the leaf values in tree order
+	data [][]byte
+
+	// index is the first leaf index of each value
+	index map[string]int
+
+	// nodes is the root at 1, the children of node i at 2i and 2i+1 and the leaves from len(nodes)/2, padded with nil
+	nodes [][]byte
+}
+
+// newMerkleTree returns the tree of data
+func newMerkleTree(data [][]byte) *merkleTree {
+	t := &merkleTree{hash: blake2b.New()}
+
+	leafHashes := make([][]byte, len(data))
+	for i, value := range data {
+		leafHashes[i] = t.hash.Hash(value)
+	}
+	t.build(data, leafHashes)
+
+	return t
+}
+
+// build sets data and the nodes above leafHashes
+func (t *merkleTree) build(data, leafHashes [][]byte) {
+	width := capacity(len(data))
+
+	t.data = data
+	t.nodes = make([][]byte, 2*width)
+	copy(t.nodes[width:], leafHashes)
+	for i := width - 1; i > 0; i-- {
+		t.nodes[i] = t.hash.Hash(append(append([]byte{}, t.nodes[2*i]...), t.nodes[2*i+1]...))
+	}
+
+	t.index = make(map[string]int, len(data))
+	for i, value := range data {
+		if _, ok := t.index[string(value)]; !ok {
+			t.index[string(value)] = i
+		}
+	}
+}
+
+// capacity is the number of leaves of a tree of n values, the next power of 2
+func capacity(n int) int {
+	width := 1
+	for width < n {
+		width *= 2
+	}
+	return width
+}
+
+// leafHashes returns the hashes of the leaves with data
+func (t *merkleTree) leafHashes() [][]byte {
+	width := len(t.nodes) / 2
+	return t.nodes[width : width+len(t.data)]
+}
+
+// size returns the number of leaves
+func (t *merkleTree) size() int {
+	return len(t.data)
+}
+
+// root returns the root hash
+func (t *merkleTree) root() []byte {
+	return t.nodes[1]
+}
+
+// append adds values as leaves, only the paths to the root of the new leaves are hashed unless the tree grows past
+// its capacity
+func (t *merkleTree) append(values ...[]byte) {
+	if len(values) == 0 {
+		return
+	}
+
+	hashes := make([][]byte, len(values))
+	for i, value := range values {
+		hashes[i] = t.hash.Hash(value)
+	}
+
+	if len(t.data)+len(values) > len(t.nodes)/2 {
+		t.build(append(t.data, values...), append(append([][]byte{}, t.leafHashes()...), hashes...))
+		return
+	}
+
+	width := len(t.nodes) / 2
+	dirty := map[int]bool{}
+	for i, value := range values {
+		n := len(t.data)
+		t.data = append(t.data, value)
+		t.nodes[width+n] = hashes[i]
+		if _, ok := t.index[string(value)]; !ok {
+			t.index[string(value)] = n
+		}
+		if parent := (width + n) / 2; parent > 0 {
+			dirty[parent] = true
+		}
+	}
+
+	for len(dirty) > 0 {
+		parents := map[int]bool{}
+		for i := range dirty {
+			t.nodes[i] = t.hash.Hash(append(append([]byte{}, t.nodes[2*i]...), t.nodes[2*i+1]...))
+			if i > 1 {
+				parents[i/2] = true
+			}
+		}
+		dirty = parents
+	}
+}
+
+// remove removes every leaf with one of values, the leaves after them move down and the branches are hashed again
+func (t *merkleTree) remove(values ...[]byte) {
+	removed := map[string]bool{}
+	for _, value := range values {
+		if _, ok := t.index[string(value)]; ok {
+			removed[string(value)] = true
+		}
+	}
+	if len(removed) == 0 {
+		return
+	}
+
+	hashes := t.leafHashes()
+	data := make([][]byte, 0, len(t.data))
+	leafHashes := make([][]byte, 0, len(t.data))
+	for i, value := range t.data {
+		if removed[string(value)] {
+			continue
+		}
+		data = append(data, value)
+		leafHashes = append(leafHashes, hashes[i])
+	}
+
+	t.build(data, leafHashes)
+}
+
+// proof returns the proof of the first leaf with value, as merkletree.MerkleTree.GenerateProof
+func (t *merkleTree) proof(value []byte) (*merkletree.Proof, error) {
+	index, ok := t.index[string(value)]
+	if !ok {
+		return nil, errLeafNotFound
+	}
+
+	hashes := [][]byte{}
+	for i := index + len(t.nodes)/2; i > 1; i /= 2 {
+		hashes = append(hashes, t.nodes[i^1])
+	}
+
+	return &merkletree.Proof{Hashes: hashes, Index: uint64(index)}, nil
+}
@@ -0,0 +1,138 @@
+package tree
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wealdtech/go-merkletree"
+)
+
+func mockLeaves(from, to int) [][]byte {
+	data := [][]byte{}
+	for i := from; i < to; i++ {
+		data = append(data, []byte(fmt.Sprintf("leaf-%d", i)))
+	}
+	return data
+}
+
+func assertSameTree(t *testing.T, data [][]byte, got *merkleTree) {
+	t.Helper()
+
+	want, err := merkletree.New(data)
+	assert.NoError(t, err)
+
+	assert.Equal(t, want.Root(), got.root())
+	assert.Equal(t, len(data), got.size())
+
+	for _, value := range data {
+		wantProof, err := want.GenerateProof(value)
+		assert.NoError(t, err)
+
+		gotProof, err := got.proof(value)
+		assert.NoError(t, err)
+		assert.Equal(t, wantProof, gotProof)
+
+		ok, err := merkletree.VerifyProof(value, gotProof, got.root())
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	}
+}
+
+func TestMerkleTree(t *testing.T) {
+	tts := []struct {
+		name    string
+		initial [][]byte
+		append  [][]byte
+		remove  [][]byte
+		want    [][]byte
+	}{
+		{
+			name:    "single leaf",
+			initial: mockLeaves(0, 1),
+			want:    mockLeaves(0, 1),
+		},
+		{
+			name:    "append within capacity",
+			initial: mockLeaves(0, 5),
+			append:  mockLeaves(5, 8),
+			want:    mockLeaves(0, 8),
+		},
+		{
+			name:    "append past capacity",
+			initial: mockLeaves(0, 3),
+			append:  mockLeaves(3, 21),
+			want:    mockLeaves(0, 21),
+		},
+		{
+			name:   "append to empty",
+			append: mockLeaves(0, 1),
+			want:   mockLeaves(0, 1),
+		},
+		{
+			name:    "remove",
+			initial: mockLeaves(0, 9),
+			remove:  [][]byte{[]byte("leaf-0"), []byte("leaf-4"), []byte("missing")},
+			want:    append(mockLeaves(1, 4), mockLeaves(5, 9)...),
+		},
+		{
+			name:    "remove and append",
+			initial: mockLeaves(0, 4),
+			remove:  [][]byte{[]byte("leaf-3")},
+			append:  mockLeaves(4, 6),
+			want:    append(mockLeaves(0, 3), mockLeaves(4, 6)...),
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := newMerkleTree(tt.initial)
+			tree.remove(tt.remove...)
+			tree.append(tt.append...)
+
+			assertSameTree(t, tt.want, tree)
+		})
+	}
+}
+
+func TestMerkleTreeProofNotFound(t *testing.T) {
+	_, err := newMerkleTree(mockLeaves(0, 3)).proof([]byte("missing"))
+	assert.ErrorIs(t, err, errLeafNotFound)
+}
+
+func TestSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tree.snapshot")
+	data := mockLeaves(0, 13)
+	tree := newMerkleTree(data)
+
+	want := &snapshot{
+		Version:   snapshotVersion,
+		LastID:    13,
+		LastValue: data[12],
+		SyncedAt:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Data:      tree.data,
+		Root:      tree.root(),
+	}
+	assert.NoError(t, writeSnapshot(path, want))
+
+	got, restored, err := readSnapshot(path)
+	assert.NoError(t, err)
+	assert.Equal(t, want.LastID, got.LastID)
+	assert.Equal(t, want.LastValue, got.LastValue)
+	assert.True(t, want.SyncedAt.Equal(got.SyncedAt))
+	assertSameTree(t, data, restored)
+
+	restored.append(mockLeaves(13, 17)...)
+	assertSameTree(t, mockLeaves(0, 17), restored)
+
+	_, _, err = readSnapshot(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+
+	tampered := *want
+	tampered.Data = append(mockLeaves(0, 12), []byte("tampered"))
+	assert.NoError(t, writeSnapshot(path, &tampered))
+	_, _, err = readSnapshot(path)
+	assert.Error(t, err, "a snapshot whose leaves do not hash to its root is not restored")
+}
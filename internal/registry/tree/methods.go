@@ -1,8 +1,12 @@
 package tree
 
 import (
+	"encoding/base64"
+	"time"
+	"vc/pkg/merkleproof"
 	"vc/pkg/model"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/wealdtech/go-merkletree"
 )
 
@@ -18,9 +22,36 @@ func (s *Service) Insert(value string) error {
 
 // Validate validates an entity in the registry
 func (s *Service) Validate(value string) (bool, error) {
-	proof, err := s.smt.GenerateProof([]byte(value))
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	proof, err := s.tree.proof([]byte(value))
 	if err != nil {
 		return false, err
 	}
-	return merkletree.VerifyProof([]byte(value), proof, s.rootHash)
+	return merkletree.VerifyProof([]byte(value), proof, s.tree.root())
+}
+
+// InclusionProof returns the audit path of an entity in the registry, together with a signed tree head
+func (s *Service) InclusionProof(value string) (*merkleproof.InclusionProof, error) {
+	s.mu.RLock()
+	proof, err := s.tree.proof([]byte(value))
+	root, size := s.tree.root(), s.tree.size()
+	s.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	signedTreeHead, err := merkleproof.SignTreeHead(s.signingKey, &merkleproof.TreeHead{
+		TreeSize: uint64(size),
+		RootHash: base64.StdEncoding.EncodeToString(root),
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt: jwt.NewNumericDate(time.Now()),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return merkleproof.NewInclusionProof(proof, signedTreeHead), nil
 }
@@ -1,27 +1,47 @@
 package tree
 
 import (
+	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"errors"
+	"os"
 	"sync"
 	"time"
 	"vc/internal/registry/db"
+	"vc/pkg/helpers"
 	"vc/pkg/logger"
 	"vc/pkg/model"
 
-	"github.com/wealdtech/go-merkletree"
+	"github.com/golang-jwt/jwt/v5"
 )
 
+// syncBatchSize is the number of leaves read from the leaf table at a time
+const syncBatchSize = 10000
+
 // Service is the merkel tree client
 type Service struct {
-	smt      *merkletree.MerkleTree
 	log      *logger.Log
 	cfg      *model.Cfg
-	rootHash []byte
-	data     [][]byte
 	quitChan chan struct{}
 	ticker   *time.Ticker
 	db       *db.Service
 	wg       *sync.WaitGroup
+
+	signingKey *ecdsa.PrivateKey
+
+	mu   sync.RWMutex
+	tree *merkleTree
+
+	// initLeaf is true while the leaf table is empty and the tree only holds the configured init leaf
+	initLeaf bool
+
+	// lastID, lastValue and syncedAt are the offset in the leaf table the tree is synced to, only the update loop
+	// changes them
+	lastID     uint
+	lastValue  []byte
+	syncedAt   time.Time
+	snapshotAt time.Time
 }
 
 // New creates a new merkel tree client
@@ -35,23 +55,32 @@ func New(ctx context.Context, wg *sync.WaitGroup, db *db.Service, cfg *model.Cfg
 		ticker:   time.NewTicker(time.Duration(cfg.Registry.SMT.UpdatePeriodicity) * time.Second),
 	}
 
-	if err := s.load(); err != nil {
+	if err := s.loadSigningKey(); err != nil {
 		return nil, err
 	}
 
+	s.restore()
+
+	if err := s.sync(); err != nil {
+		return nil, err
+	}
+	s.snapshot(false)
+
 	s.wg.Add(1)
 	go func() {
 		for {
 			select {
 			case <-s.ticker.C:
 				s.log.Info("merkel tree update")
-				if err := s.load(); err != nil {
+				if err := s.sync(); err != nil {
 					s.log.Error(err, "merkel tree update failed")
-					s.quitChan <- struct{}{}
+					continue
 				}
+				s.snapshot(false)
 			case <-s.quitChan:
 				s.log.Info("Stop updating tree")
 				s.ticker.Stop()
+				s.snapshot(true)
 				s.wg.Done()
 				return
 			}
@@ -63,30 +92,130 @@ func New(ctx context.Context, wg *sync.WaitGroup, db *db.Service, cfg *model.Cfg
 	return s, nil
 }
 
-func (s *Service) load() error {
-	data := [][]byte{}
+// sync applies the leaves inserted and deleted since the last sync to the tree, reading the whole leaf table if the
+// tree is empty
+func (s *Service) sync() error {
+	syncedAt := time.Now()
 
-	leafs := &model.Leafs{}
-	err := s.db.Find(leafs)
-	if err != nil {
-		return err
+	var deleted [][]byte
+	if s.tree != nil && !s.initLeaf {
+		leafs := &model.Leafs{}
+		if err := s.db.FindDeletedSince(leafs, s.syncedAt, s.lastID); err != nil {
+			return err
+		}
+		deleted = leafs.Array()
 	}
 
-	if leafs.Empty() {
-		s.log.Info("DB is empty, using init data")
-		data = [][]byte{
-			[]byte(s.cfg.Registry.SMT.InitLeaf),
+	lastID, lastValue := s.lastID, s.lastValue
+	var inserted [][]byte
+	for {
+		leafs := model.Leafs{}
+		if err := s.db.FindAfter(&leafs, lastID, syncBatchSize); err != nil {
+			return err
 		}
+		if leafs.Empty() {
+			break
+		}
+		inserted = append(inserted, leafs.Array()...)
+		lastID, lastValue = leafs[len(leafs)-1].ID, leafs[len(leafs)-1].Value
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.initLeaf && len(inserted) > 0 {
+		s.tree, s.initLeaf = nil, false
+	}
+
+	if s.tree == nil {
+		s.tree = newMerkleTree(inserted)
+		s.log.Info("merkel tree built", "leaves", len(inserted))
 	} else {
-		data = leafs.Array()
+		s.tree.remove(deleted...)
+		s.tree.append(inserted...)
+	}
+
+	if s.tree.size() == 0 {
+		s.log.Info("DB is empty, using init data")
+		s.tree, s.initLeaf = newMerkleTree([][]byte{[]byte(s.cfg.Registry.SMT.InitLeaf)}), true
+	}
+
+	s.lastID, s.lastValue, s.syncedAt = lastID, lastValue, syncedAt
+	return nil
+}
+
+// restore loads the tree from the snapshot, if there is one and the leaf table still has the last leaf it was synced
+// to. A snapshot that can't be restored is logged and the tree is built from the leaf table instead.
+func (s *Service) restore() {
+	path := s.cfg.Registry.SMT.SnapshotPath
+	if path == "" {
+		return
+	}
+
+	snap, tree, err := readSnapshot(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			s.log.Error(err, "merkel tree snapshot not restored", "path", path)
+		}
+		return
+	}
+
+	leaf := &model.Leaf{}
+	if err := s.db.FirstUnscoped(leaf, "id = ?", snap.LastID); err != nil || !bytes.Equal(leaf.Value, snap.LastValue) {
+		s.log.Info("merkel tree snapshot does not match the leaf table, rebuilding", "path", path, "last_id", snap.LastID)
+		return
+	}
+
+	s.tree = tree
+	s.lastID, s.lastValue, s.syncedAt = snap.LastID, snap.LastValue, snap.SyncedAt
+	s.log.Info("merkel tree restored from snapshot", "path", path, "leaves", tree.size(), "last_id", snap.LastID)
+}
+
+// snapshot saves the tree if snapshots are enabled and the interval has passed since the last one, or always if force
+func (s *Service) snapshot(force bool) {
+	path := s.cfg.Registry.SMT.SnapshotPath
+	if path == "" || s.initLeaf {
+		return
+	}
+	if !force && time.Since(s.snapshotAt) < time.Duration(s.cfg.Registry.SMT.SnapshotInterval)*time.Second {
+		return
+	}
+
+	s.mu.RLock()
+	err := writeSnapshot(path, &snapshot{
+		Version:   snapshotVersion,
+		LastID:    s.lastID,
+		LastValue: s.lastValue,
+		SyncedAt:  s.syncedAt,
+		Data:      s.tree.data,
+		Root:      s.tree.root(),
+	})
+	s.mu.RUnlock()
+	if err != nil {
+		s.log.Error(err, "merkel tree snapshot failed", "path", path)
+		return
+	}
+
+	s.snapshotAt = time.Now()
+	s.log.Debug("merkel tree snapshot saved", "path", path, "leaves", len(s.tree.data))
+}
+
+func (s *Service) loadSigningKey() error {
+	keyByte, err := os.ReadFile(s.cfg.Registry.SMT.SigningKeyPath)
+	if err != nil {
+		s.log.Error(err, "Failed to read tree head signing key, please create a ECDSA prime256v1 key and save it to the path")
+		return err
+	}
+
+	if keyByte == nil {
+		return helpers.ErrPrivateKeyMissing
 	}
 
-	s.smt, err = merkletree.New(data)
+	s.signingKey, err = jwt.ParseECPrivateKeyFromPEM(keyByte)
 	if err != nil {
 		return err
 	}
 
-	s.rootHash = s.smt.Root()
 	return nil
 }
 
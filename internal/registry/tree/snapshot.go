@@ -0,0 +1,80 @@
+package tree
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// snapshotVersion is the version of the snapshot format, snapshots of other versions are not restored
+const snapshotVersion = 2
+
+// snapshot is the tree saved to disk and the offset in the leaf table it was synced to
+type snapshot struct {
+	Version int
+
+	// LastID is the highest leaf id in the tree and LastValue its value, to detect a leaf table that was replaced
+	LastID    uint
+	LastValue []byte
+
+	// SyncedAt is the time of the last sync, leaves deleted after it are removed on replay
+	SyncedAt time.Time
+
+	// Data are the leaf values and Root the root hash of the tree of them, a snapshot whose data does not hash to
+	// Root is corrupt
+	Data [][]byte
+	Root []byte
+}
+
+// writeSnapshot writes snap to path through a temporary file, so a crash never leaves a partial snapshot
+func writeSnapshot(path string, snap *snapshot) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := gob.NewEncoder(f).Encode(snap); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// readSnapshot reads the snapshot at path and returns its tree, rebuilt from the data and checked against the root
+func readSnapshot(path string) (*snapshot, *merkleTree, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	snap := &snapshot{}
+	if err := gob.NewDecoder(f).Decode(snap); err != nil {
+		return nil, nil, err
+	}
+	if snap.Version != snapshotVersion {
+		return nil, nil, fmt.Errorf("snapshot version %d, want %d", snap.Version, snapshotVersion)
+	}
+	if len(snap.Data) == 0 {
+		return nil, nil, fmt.Errorf("snapshot has no leaves")
+	}
+
+	t := newMerkleTree(snap.Data)
+	if !bytes.Equal(t.root(), snap.Root) {
+		return nil, nil, fmt.Errorf("snapshot root does not match its %d leaves", len(snap.Data))
+	}
+
+	return snap, t, nil
+}
@@ -0,0 +1,142 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"vc/pkg/helpers"
+	"vc/pkg/model"
+)
+
+const (
+	// ActionAdd is the action of an entity added to the registry
+	ActionAdd = "add"
+	// ActionRevoke is the action of a revoked entity
+	ActionRevoke = "revoke"
+	// ActionSuspend is the action of a suspended entity
+	ActionSuspend = "suspend"
+	// ActionUnsuspend is the action of an entity whose suspension is lifted
+	ActionUnsuspend = "unsuspend"
+
+	// watcherBuffer is the number of events a watcher may lag behind before it is disconnected
+	watcherBuffer = 256
+
+	// replayPageSize is the number of stored events read per query on replay
+	replayPageSize = 100
+)
+
+var (
+	// ErrDisconnected is returned when a watcher lags too far behind or the service is closed, the watcher should resume from its last token
+	ErrDisconnected = errors.New("watcher disconnected")
+)
+
+// ResumeToken returns the resume token of event
+func ResumeToken(event *model.StatusEvent) string {
+	return strconv.FormatUint(uint64(event.ID), 10)
+}
+
+// parseResumeToken returns the event ID of token, zero if token is empty
+func parseResumeToken(token string) (uint, error) {
+	if token == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseUint(token, 10, 64)
+	if err != nil {
+		return 0, helpers.ErrInvalidResumeToken
+	}
+
+	return uint(id), nil
+}
+
+// Publish stores event and sends it to all watchers
+func (s *Service) Publish(ctx context.Context, event *model.StatusEvent) error {
+	// the lock is held over the insert so that watchers receive events in ID order
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.db.Insert(event); err != nil {
+		return err
+	}
+
+	for ch := range s.watchers {
+		select {
+		case ch <- event:
+		default:
+			s.log.Info("Disconnecting lagging watcher")
+			delete(s.watchers, ch)
+			close(ch)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) subscribe() chan *model.StatusEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ch := make(chan *model.StatusEvent, watcherBuffer)
+	s.watchers[ch] = struct{}{}
+
+	return ch
+}
+
+func (s *Service) unsubscribe(ch chan *model.StatusEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.watchers[ch]; ok {
+		delete(s.watchers, ch)
+		close(ch)
+	}
+}
+
+// Watch calls fn for each event after resumeToken, and then for each new event, until ctx is done or fn returns an error.
+// An empty resumeToken starts with the next new event.
+func (s *Service) Watch(ctx context.Context, resumeToken string, fn func(*model.StatusEvent) error) error {
+	last, err := parseResumeToken(resumeToken)
+	if err != nil {
+		return err
+	}
+
+	// subscribe before the replay, events published meanwhile are skipped by ID
+	ch := s.subscribe()
+	defer s.unsubscribe(ch)
+
+	if resumeToken != "" {
+		for {
+			events := model.StatusEvents{}
+			if err := s.db.FindAfter(&events, last, replayPageSize); err != nil {
+				return err
+			}
+			for _, event := range events {
+				if err := fn(event); err != nil {
+					return err
+				}
+				last = event.ID
+			}
+			if len(events) < replayPageSize {
+				break
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-ch:
+			if !ok {
+				return ErrDisconnected
+			}
+			if event.ID <= last {
+				continue
+			}
+			if err := fn(event); err != nil {
+				return err
+			}
+			last = event.ID
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package watch
+
+import (
+	"context"
+	"sync"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+)
+
+// store persists status events, implemented by db.Service
+type store interface {
+	Insert(model any) error
+	FindAfter(model any, id uint, limit int) error
+}
+
+// Service stores the status changes of the registry and streams them to watchers
+type Service struct {
+	cfg *model.Cfg
+	log *logger.Log
+	db  store
+
+	mu       sync.Mutex
+	watchers map[chan *model.StatusEvent]struct{}
+}
+
+// New creates a new watch service
+func New(ctx context.Context, db store, cfg *model.Cfg, log *logger.Log) (*Service, error) {
+	s := &Service{
+		cfg:      cfg,
+		log:      log.New("watch"),
+		db:       db,
+		watchers: map[chan *model.StatusEvent]struct{}{},
+	}
+
+	s.log.Info("Started")
+
+	return s, nil
+}
+
+// Close disconnects all watchers
+func (s *Service) Close(ctx context.Context) error {
+	s.mu.Lock()
+	for ch := range s.watchers {
+		delete(s.watchers, ch)
+		close(ch)
+	}
+	s.mu.Unlock()
+
+	s.log.Info("Stopped")
+	return nil
+}
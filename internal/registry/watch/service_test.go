@@ -0,0 +1,159 @@
+package watch
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+	"vc/pkg/helpers"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memStore is an in memory store of status events
+type memStore struct {
+	mu     sync.Mutex
+	events model.StatusEvents
+}
+
+func (m *memStore) Insert(v any) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	event := v.(*model.StatusEvent)
+	event.ID = uint(len(m.events) + 1)
+	event.CreatedAt = time.Now()
+	m.events = append(m.events, event)
+	return nil
+}
+
+func (m *memStore) FindAfter(v any, id uint, limit int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	events := v.(*model.StatusEvents)
+	for _, event := range m.events {
+		if event.ID > id && len(*events) < limit {
+			*events = append(*events, event)
+		}
+	}
+	return nil
+}
+
+func mockService(t *testing.T) *Service {
+	s, err := New(context.TODO(), &memStore{}, &model.Cfg{}, logger.NewSimple("testing_watch"))
+	assert.NoError(t, err)
+	return s
+}
+
+func publish(t *testing.T, s *Service, n int) {
+	for i := 0; i < n; i++ {
+		assert.NoError(t, s.Publish(context.TODO(), &model.StatusEvent{Entity: "entity", Action: ActionRevoke}))
+	}
+}
+
+// collect watches until n events are received and returns their resume tokens
+func collect(t *testing.T, s *Service, resumeToken string, n int) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
+	defer cancel()
+
+	got := []string{}
+	err := s.Watch(ctx, resumeToken, func(event *model.StatusEvent) error {
+		got = append(got, ResumeToken(event))
+		if len(got) == n {
+			cancel()
+		}
+		return nil
+	})
+	if err == context.Canceled && len(got) == n {
+		err = nil
+	}
+	return got, err
+}
+
+func TestWatchResume(t *testing.T) {
+	tts := []struct {
+		name        string
+		resumeToken string
+		n           int
+		want        []string
+		wantErr     error
+	}{
+		{
+			name:        "replay from start",
+			resumeToken: "0",
+			n:           3,
+			want:        []string{"1", "2", "3"},
+		},
+		{
+			name:        "replay after token",
+			resumeToken: "2",
+			n:           1,
+			want:        []string{"3"},
+		},
+		{
+			name:        "invalid token",
+			resumeToken: "abc",
+			want:        []string{},
+			wantErr:     helpers.ErrInvalidResumeToken,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			s := mockService(t)
+			publish(t, s, 3)
+
+			got, err := collect(t, s, tt.resumeToken, tt.n)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWatchLive(t *testing.T) {
+	s := mockService(t)
+	publish(t, s, 2)
+
+	done := make(chan []string)
+	go func() {
+		got, err := collect(t, s, "1", 3)
+		assert.NoError(t, err)
+		done <- got
+	}()
+
+	// wait for the watcher before publishing, replayed and live events must not be duplicated
+	assert.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.watchers) == 1
+	}, time.Second, time.Millisecond)
+	publish(t, s, 2)
+
+	assert.Equal(t, []string{"2", "3", "4"}, <-done)
+}
+
+func TestWatchDisconnectLagging(t *testing.T) {
+	s := mockService(t)
+
+	block := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- s.Watch(context.TODO(), "", func(event *model.StatusEvent) error {
+			<-block
+			return nil
+		})
+	}()
+
+	assert.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.watchers) == 1
+	}, time.Second, time.Millisecond)
+	publish(t, s, watcherBuffer+2)
+	close(block)
+
+	assert.Equal(t, ErrDisconnected, <-done)
+}
@@ -33,6 +33,14 @@ func (c *APIGWClient) Status() (any, error) {
 	return reply, nil
 }
 
+func (c *APIGWClient) Statistics() (any, error) {
+	reply, err := c.DoGetJSON("/api/v1/statistics")
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
 func (c *APIGWClient) Upload(req *apiv1_apigw.UploadRequest) (any, error) {
 	reply, err := c.DoPostJSON("/api/v1/upload", req)
 	if err != nil {
@@ -41,7 +49,7 @@ func (c *APIGWClient) Upload(req *apiv1_apigw.UploadRequest) (any, error) {
 	return reply, nil
 }
 
-func (c *APIGWClient) Credential(req *CredentialRequest) (any, error) {
+func (c *APIGWClient) Credential(req *CredentialRequest) (*map[string]any, error) {
 	reply, err := c.DoPostJSON("/api/v1/credential", req)
 	if err != nil {
 		return nil, err
@@ -64,3 +72,35 @@ func (c *APIGWClient) Notification(request *NotificationRequest) (any, error) {
 	}
 	return reply, nil
 }
+
+func (c *APIGWClient) DocumentVersions(req *DocumentVersionsRequest) (any, error) {
+	reply, err := c.DoPostJSON("/api/v1/document/versions", req)
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (c *APIGWClient) DocumentDiff(req *DocumentDiffRequest) (any, error) {
+	reply, err := c.DoPostJSON("/api/v1/document/diff", req)
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (c *APIGWClient) IdentityReviewList(req *apiv1_apigw.IdentityReviewListRequest) (any, error) {
+	reply, err := c.DoPostJSON("/api/v1/identity/review/list", req)
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (c *APIGWClient) IdentityReviewDecision(req *apiv1_apigw.IdentityReviewDecisionRequest) (any, error) {
+	reply, err := c.DoPostJSON("/api/v1/identity/review/decision", req)
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
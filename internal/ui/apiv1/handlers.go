@@ -3,6 +3,8 @@ package apiv1
 import (
 	"context"
 	"errors"
+	"net/url"
+	"strconv"
 	"time"
 	apiv1_apigw "vc/internal/apigw/apiv1"
 	"vc/internal/gen/status/apiv1_status"
@@ -77,6 +79,10 @@ type CredentialRequest struct {
 	DocumentType    string          `json:"document_type" validate:"required"`
 	CredentialType  string          `json:"credential_type" validate:"required"`
 	CollectID       string          `json:"collect_id" validate:"required"`
+
+	// Locale, e.g. en-US, renders the issued credential with the verifier, no rendering if empty or the verifier
+	// is not configured
+	Locale string `json:"locale"`
 }
 
 func (c *Client) Credential(ctx context.Context, req *CredentialRequest) (any, error) {
@@ -84,9 +90,45 @@ func (c *Client) Credential(ctx context.Context, req *CredentialRequest) (any, e
 	if err != nil {
 		return nil, err
 	}
+
+	if req.Locale != "" && c.cfg.UI.Services.Verifier.BaseURL != "" {
+		c.renderCredential(reply, req.Locale)
+	}
+
 	return reply, nil
 }
 
+// renderCredential adds the rendering of the verifier to the credential reply of the apigw. A credential that can
+// not be rendered is still returned, without rendering.
+func (c *Client) renderCredential(reply *map[string]any, locale string) {
+	jwt, _ := (*reply)["jwt"].(string)
+	if jwt == "" {
+		// a dry run or deferred credential has nothing to render
+		return
+	}
+
+	credential := jwt + "~"
+	disclosures, _ := (*reply)["disclosures"].([]any)
+	for _, d := range disclosures {
+		if s, ok := d.(string); ok {
+			credential += s + "~"
+		}
+	}
+
+	verified, err := c.verifierClient.Verify(&VerifyRequest{Credential: credential, Locale: locale})
+	if err != nil {
+		c.log.Error(err, "could not render credential")
+		return
+	}
+	if valid, _ := (*verified)["valid"].(bool); !valid {
+		c.log.Info("credential not rendered", "reason", (*verified)["reason"])
+		return
+	}
+	if rendering, ok := (*verified)["rendering"]; ok {
+		(*reply)["rendering"] = rendering
+	}
+}
+
 type GetDocumentRequest struct {
 	AuthenticSource string `json:"authentic_source" validate:"required"`
 	DocumentType    string `json:"document_type" validate:"required"`
@@ -101,6 +143,36 @@ func (c *Client) GetDocument(ctx context.Context, req *GetDocumentRequest) (any,
 	return reply, nil
 }
 
+type DocumentVersionsRequest struct {
+	AuthenticSource string `json:"authentic_source" validate:"required"`
+	DocumentType    string `json:"document_type" validate:"required"`
+	DocumentID      string `json:"document_id" validate:"required"`
+}
+
+func (c *Client) DocumentVersions(ctx context.Context, req *DocumentVersionsRequest) (any, error) {
+	reply, err := c.apigwClient.DocumentVersions(req)
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+type DocumentDiffRequest struct {
+	AuthenticSource string `json:"authentic_source" validate:"required"`
+	DocumentType    string `json:"document_type" validate:"required"`
+	DocumentID      string `json:"document_id" validate:"required"`
+	FromVersion     string `json:"from_version" validate:"required"`
+	ToVersion       string `json:"to_version" validate:"required"`
+}
+
+func (c *Client) DocumentDiff(ctx context.Context, req *DocumentDiffRequest) (any, error) {
+	reply, err := c.apigwClient.DocumentDiff(req)
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
 type NotificationRequest struct {
 	AuthenticSource string `json:"authentic_source" validate:"required"`
 	DocumentType    string `json:"document_type" validate:"required"`
@@ -115,6 +187,47 @@ func (c *Client) Notification(ctx context.Context, request *NotificationRequest)
 	return reply, nil
 }
 
+type IdentityReviewListRequest struct {
+	AuthenticSource string `json:"authentic_source" validate:"required"`
+	Status          string `json:"status"`
+	Limit           int64  `json:"limit"`
+}
+
+// IdentityReviewList returns the identity reviews of an authentic source, the pending ones unless the request gives status
+func (c *Client) IdentityReviewList(ctx context.Context, req *IdentityReviewListRequest) (any, error) {
+	reply, err := c.apigwClient.IdentityReviewList(&apiv1_apigw.IdentityReviewListRequest{
+		AuthenticSource: req.AuthenticSource,
+		Status:          req.Status,
+		Limit:           req.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+type IdentityReviewDecisionRequest struct {
+	AuthenticSource string `json:"authentic_source" validate:"required"`
+	ReviewID        string `json:"review_id" validate:"required"`
+	Decision        string `json:"decision" validate:"required,oneof=approved rejected"`
+	Comment         string `json:"comment"`
+}
+
+// IdentityReviewDecision approves or rejects an identity review in the name of the logged in user
+func (c *Client) IdentityReviewDecision(ctx context.Context, req *IdentityReviewDecisionRequest) (any, error) {
+	reply, err := c.apigwClient.IdentityReviewDecision(&apiv1_apigw.IdentityReviewDecisionRequest{
+		AuthenticSource: req.AuthenticSource,
+		ReviewID:        req.ReviewID,
+		Decision:        req.Decision,
+		Reviewer:        c.cfg.UI.Username,
+		Comment:         req.Comment,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
 type MockNextRequest struct {
 	DocumentType            string `json:"document_type" validate:"required"`
 	AuthenticSource         string `json:"authentic_source" validate:"required"`
@@ -144,3 +257,66 @@ func (c *Client) StatusAPIGW(ctx context.Context, req *apiv1_status.StatusReques
 	}
 	return reply, nil
 }
+
+func (c *Client) StatisticsAPIGW(ctx context.Context) (any, error) {
+	reply, err := c.apigwClient.Statistics()
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// auditExportLimit is the number of entries of an audit CSV export when the request does not give limit
+const auditExportLimit = 10000
+
+// AuditRequest is the request for the verifier audit trail, passed on to the verifier
+type AuditRequest struct {
+	From           string `form:"from"`
+	To             string `form:"to"`
+	SessionsOnly   bool   `form:"sessions_only"`
+	Valid          string `form:"valid" validate:"omitempty,oneof=true false"`
+	CredentialType string `form:"credential_type"`
+	Offset         int64  `form:"offset"`
+	Limit          int64  `form:"limit"`
+}
+
+// query returns the query parameters of the verifier audit endpoint
+func (r *AuditRequest) query() url.Values {
+	query := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	set("from", r.From)
+	set("to", r.To)
+	set("valid", r.Valid)
+	set("credential_type", r.CredentialType)
+	if r.SessionsOnly {
+		query.Set("sessions_only", "true")
+	}
+	if r.Offset > 0 {
+		query.Set("offset", strconv.FormatInt(r.Offset, 10))
+	}
+	if r.Limit > 0 {
+		query.Set("limit", strconv.FormatInt(r.Limit, 10))
+	}
+	return query
+}
+
+// Audit returns a page of the verifier audit trail, attribute values are redacted by the verifier
+func (c *Client) Audit(ctx context.Context, req *AuditRequest) (any, error) {
+	reply, err := c.verifierClient.Audit(req)
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// AuditExport returns the verifier audit trail as CSV, at most 10000 entries unless the request gives limit
+func (c *Client) AuditExport(ctx context.Context, req *AuditRequest) ([]byte, error) {
+	if req.Limit == 0 {
+		req.Limit = auditExportLimit
+	}
+	return c.verifierClient.AuditCSV(req)
+}
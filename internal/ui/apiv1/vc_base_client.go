@@ -3,6 +3,7 @@ package apiv1
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"time"
@@ -94,6 +95,35 @@ func (c *VCBaseClient) DoGetJSON(endpoint string) (*map[string]any, error) {
 	return &jsonResp, nil
 }
 
+// DoGet returns the body of a GET of endpoint in the media type accept, an error unless the status is 2xx
+func (c *VCBaseClient) DoGet(endpoint, accept string) ([]byte, error) {
+	url := c.url(endpoint)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer c.closeBody(resp)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%s responded with status %d: %s", c.serviceName, resp.StatusCode, body)
+	}
+
+	return body, nil
+}
+
 func (c *VCBaseClient) url(path string) string {
 	return c.baseUrl + path
 }
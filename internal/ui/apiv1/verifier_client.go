@@ -0,0 +1,39 @@
+package apiv1
+
+import (
+	"vc/pkg/logger"
+	"vc/pkg/model"
+	"vc/pkg/trace"
+)
+
+type VerifierClient struct {
+	*VCBaseClient
+}
+
+func NewVerifierClient(cfg *model.Cfg, tracer *trace.Tracer, logger *logger.Log) *VerifierClient {
+	return &VerifierClient{
+		VCBaseClient: NewClient("Verifier", cfg.UI.Services.Verifier.BaseURL, tracer, logger),
+	}
+}
+
+func (c *VerifierClient) Audit(req *AuditRequest) (any, error) {
+	reply, err := c.DoGetJSON("/api/v1/audit?" + req.query().Encode())
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// VerifyRequest is the request to verify, and render, a credential with the verifier
+type VerifyRequest struct {
+	Credential string `json:"credential"`
+	Locale     string `json:"locale"`
+}
+
+func (c *VerifierClient) Verify(req *VerifyRequest) (*map[string]any, error) {
+	return c.DoPostJSON("/api/v1/verify", req)
+}
+
+func (c *VerifierClient) AuditCSV(req *AuditRequest) ([]byte, error) {
+	return c.DoGet("/api/v1/audit?"+req.query().Encode(), "text/csv")
+}
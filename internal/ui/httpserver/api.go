@@ -16,12 +16,21 @@ type Apiv1 interface {
 
 	// apigw
 	StatusAPIGW(ctx context.Context, request *apiv1_status.StatusRequest) (any, error)
+	StatisticsAPIGW(ctx context.Context) (any, error)
 	DocumentList(ctx context.Context, request *apiv1.DocumentListRequest) (any, error)
 	Upload(ctx context.Context, request *apigw_apiv1.UploadRequest) (any, error)
 	Credential(ctx context.Context, request *apiv1.CredentialRequest) (any, error)
 	GetDocument(ctx context.Context, request *apiv1.GetDocumentRequest) (any, error)
+	DocumentVersions(ctx context.Context, request *apiv1.DocumentVersionsRequest) (any, error)
+	DocumentDiff(ctx context.Context, request *apiv1.DocumentDiffRequest) (any, error)
 	Notification(ctx context.Context, reguest *apiv1.NotificationRequest) (any, error)
+	IdentityReviewList(ctx context.Context, request *apiv1.IdentityReviewListRequest) (any, error)
+	IdentityReviewDecision(ctx context.Context, request *apiv1.IdentityReviewDecisionRequest) (any, error)
 
 	// mockas
 	MockNext(ctx context.Context, request *apiv1.MockNextRequest) (any, error)
+
+	// verifier
+	Audit(ctx context.Context, request *apiv1.AuditRequest) (any, error)
+	AuditExport(ctx context.Context, request *apiv1.AuditRequest) ([]byte, error)
 }
@@ -3,6 +3,7 @@ package httpserver
 import (
 	"context"
 	"errors"
+	"net/http"
 	"time"
 	apiv1_apigw "vc/internal/apigw/apiv1"
 	"vc/internal/gen/status/apiv1_status"
@@ -146,6 +147,30 @@ func (s *Service) endpointGetDocument(ctx context.Context, c *gin.Context) (any,
 	return reply, nil
 }
 
+func (s *Service) endpointDocumentVersions(ctx context.Context, c *gin.Context) (any, error) {
+	request := &apiv1.DocumentVersionsRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		return nil, err
+	}
+	reply, err := s.apiv1.DocumentVersions(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *Service) endpointDocumentDiff(ctx context.Context, c *gin.Context) (any, error) {
+	request := &apiv1.DocumentDiffRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		return nil, err
+	}
+	reply, err := s.apiv1.DocumentDiff(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
 func (s *Service) endpointNotification(ctx context.Context, c *gin.Context) (any, error) {
 	request := &apiv1.NotificationRequest{}
 	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
@@ -158,6 +183,30 @@ func (s *Service) endpointNotification(ctx context.Context, c *gin.Context) (any
 	return reply, nil
 }
 
+func (s *Service) endpointIdentityReviewList(ctx context.Context, c *gin.Context) (any, error) {
+	request := &apiv1.IdentityReviewListRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		return nil, err
+	}
+	reply, err := s.apiv1.IdentityReviewList(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *Service) endpointIdentityReviewDecision(ctx context.Context, c *gin.Context) (any, error) {
+	request := &apiv1.IdentityReviewDecisionRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		return nil, err
+	}
+	reply, err := s.apiv1.IdentityReviewDecision(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
 func (s *Service) endpointMockNext(ctx context.Context, c *gin.Context) (any, error) {
 	request := &apiv1.MockNextRequest{}
 	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
@@ -170,3 +219,30 @@ func (s *Service) endpointMockNext(ctx context.Context, c *gin.Context) (any, er
 	}
 	return reply, nil
 }
+
+func (s *Service) endpointAudit(ctx context.Context, c *gin.Context) (any, error) {
+	request := &apiv1.AuditRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		return nil, err
+	}
+	reply, err := s.apiv1.Audit(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *Service) endpointAuditExport(ctx context.Context, c *gin.Context) (any, error) {
+	request := &apiv1.AuditRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		return nil, err
+	}
+	reply, err := s.apiv1.AuditExport(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="verifier-audit.csv"`)
+	c.Data(http.StatusOK, "text/csv", reply)
+	return nil, nil
+}
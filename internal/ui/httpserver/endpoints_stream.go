@@ -0,0 +1,45 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// statisticsStreamPath is served uncompressed since gzip buffers the events
+const statisticsStreamPath = "/secure/apigw/statistics/stream"
+
+// endpointAPIGWStatisticsStream pushes apigw statistics to the browser as server-sent events until the client disconnects
+func (s *Service) endpointAPIGWStatisticsStream(ctx context.Context, c *gin.Context) (any, error) {
+	// the stream outlives the server write timeout
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		s.log.Debug("statistics stream", "err", err)
+	}
+
+	ticker := time.NewTicker(time.Duration(s.cfg.UI.DashboardIntervalInSeconds) * time.Second)
+	defer ticker.Stop()
+
+	send := func() {
+		reply, err := s.apiv1.StatisticsAPIGW(ctx)
+		if err != nil {
+			c.SSEvent("error", gin.H{"error": err.Error()})
+		} else {
+			c.SSEvent("statistics", reply)
+		}
+		c.Writer.Flush()
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	send()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return nil, nil
+		case <-ticker.C:
+			send()
+		}
+	}
+}
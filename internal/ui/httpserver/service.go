@@ -66,10 +66,10 @@ func New(ctx context.Context, cfg *model.Cfg, apiv1 *apiv1.Client, tracer *trace
 	}
 
 	// extra middlewares (must be declared before Server.Default)
-	s.gin.Use(s.httpHelpers.Middleware.Gzip(ctx))
+	s.gin.Use(s.httpHelpers.Middleware.Gzip(ctx, statisticsStreamPath))
 	s.gin.Use(s.middlewareUserSession(ctx, s.cfg))
 
-	rgRoot, err := s.httpHelpers.Server.Default(ctx, s.server, s.gin, s.cfg.UI.APIServer.Addr)
+	rgRoot, err := s.httpHelpers.Server.Default(ctx, s.server, s.gin, s.cfg.UI.APIServer)
 	if err != nil {
 		return nil, err
 	}
@@ -93,11 +93,20 @@ func New(ctx context.Context, cfg *model.Cfg, apiv1 *apiv1.Client, tracer *trace
 	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIGW, http.MethodPost, "upload", s.endpointUpload)
 	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIGW, http.MethodPost, "credential", s.endpointCredential)
 	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIGW, http.MethodPost, "document", s.endpointGetDocument)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIGW, http.MethodPost, "document/versions", s.endpointDocumentVersions)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIGW, http.MethodPost, "document/diff", s.endpointDocumentDiff)
 	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIGW, http.MethodPost, "notification", s.endpointNotification)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIGW, http.MethodPost, "identity/review/list", s.endpointIdentityReviewList)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIGW, http.MethodPost, "identity/review/decision", s.endpointIdentityReviewDecision)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIGW, http.MethodGet, "statistics/stream", s.endpointAPIGWStatisticsStream)
 
 	rgMockAS := rgSecure.Group("mockas")
 	s.httpHelpers.Server.RegEndpoint(ctx, rgMockAS, http.MethodPost, "mock/next", s.endpointMockNext)
 
+	rgVerifier := rgSecure.Group("verifier")
+	s.httpHelpers.Server.RegEndpoint(ctx, rgVerifier, http.MethodGet, "audit", s.endpointAudit)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgVerifier, http.MethodGet, "audit/export", s.endpointAuditExport)
+
 	// Run http server
 	go func() {
 		err := s.httpHelpers.Server.ListenAndServe(ctx, s.server, s.cfg.UI.APIServer)
@@ -112,8 +121,17 @@ func New(ctx context.Context, cfg *model.Cfg, apiv1 *apiv1.Client, tracer *trace
 	return s, nil
 }
 
-// Close closing httpserver
+// AddHealthCheck adds a dependency check to the readiness probe
+func (s *Service) AddHealthCheck(name string, check httphelpers.HealthCheck) {
+	s.httpHelpers.Server.AddHealthCheck(name, check)
+}
+
+// Close drains in-flight requests and stops the httpserver
 func (s *Service) Close(ctx context.Context) error {
+	if err := s.httpHelpers.Server.Shutdown(ctx, s.server, s.cfg.UI.APIServer); err != nil {
+		return err
+	}
+
 	s.log.Info("Stopped")
 	return nil
 }
@@ -506,6 +506,74 @@ const addViewNotificationFormArticleToContainer = () => {
     document.getElementById(articleIdBasis.articleID).querySelector('input').focus();
 };
 
+/** Builds the rendering of a credential, its display name and the label and value of each disclosed claim
+ *
+ * @param rendering the rendering of the verifier
+ * @returns {HTMLElement} div
+ */
+const buildCredentialRendering = (rendering) => {
+    const div = document.createElement('div');
+    div.classList.add('box');
+    if (rendering.background_color) {
+        div.style.backgroundColor = rendering.background_color;
+    }
+    if (rendering.text_color) {
+        div.style.color = rendering.text_color;
+    }
+
+    if (rendering.logo && rendering.logo.uri) {
+        const img = document.createElement('img');
+        img.src = rendering.logo.uri;
+        img.alt = rendering.logo.alt_text || "";
+        img.style.maxHeight = "48px";
+        div.appendChild(img);
+    }
+
+    const nameP = document.createElement('p');
+    nameP.classList.add('title', 'is-5');
+    nameP.textContent = rendering.name || "";
+    div.appendChild(nameP);
+
+    if (rendering.description) {
+        const descriptionP = document.createElement('p');
+        descriptionP.textContent = rendering.description;
+        div.appendChild(descriptionP);
+    }
+
+    const rows = (rendering.claims || []).map(c => [
+        c.label, typeof c.value === 'object' ? JSON.stringify(c.value) : c.value
+    ]);
+    div.appendChild(buildTable(["Claim", "Value"], rows));
+    return div;
+};
+
+async function postAndDisplayCredential(requestBody) {
+    const url = new URL("/secure/apigw/credential", baseUrl);
+    console.debug("Call to postAndDisplayCredential: " + url);
+
+    const elements = addNewRequestResponseArticleToContainer("Credential");
+
+    const headers = {
+        'Accept': 'application/json', 'Content-Type': 'application/json; charset=utf-8',
+    };
+    const options = {
+        method: `POST`, headers: headers, body: JSON.stringify(requestBody),
+    };
+
+    updateTextContentInChildPreTagFor(elements.reqMetaDiv, `${JSON.stringify(options, null, 2)}`)
+
+    await doFetchAPICallAndHandleResult(url, options, elements);
+
+    const payload = elements.payloadDiv.querySelector('pre');
+    if (!payload || !payload.textContent) {
+        return;
+    }
+    const credential = JSON.parse(payload.textContent);
+    if (credential.rendering) {
+        elements.payloadDiv.prepend(buildCredentialRendering(credential.rendering));
+    }
+}
+
 const addCredentialFormArticleToContainer = () => {
     const buildFormElements = () => {
 
@@ -518,6 +586,7 @@ const addCredentialFormArticleToContainer = () => {
         const credentialTypeElement = createInputElement('credential type', 'SD-JWT');
         const authenticSourceElement = createInputElement('authentic source', 'SUNET');
         const collectIdElement = createInputElement('collect id');
+        const localeElement = createInputElement('locale of the rendering, e.g. en-US (empty for none)', 'en-US');
 
         const createButton = document.createElement('button');
         createButton.id = generateUUID();
@@ -540,15 +609,16 @@ const addCredentialFormArticleToContainer = () => {
                 document_type: documentTypeElement.value,
                 credential_type: credentialTypeElement.value,
                 collect_id: collectIdElement.value,
+                locale: localeElement.value,
             };
 
             disableElements([
                 authenticSourcePersonIdElement, familyNameElement, givenNameElement,
                 birthdateElement, schemaNameElement, documentTypeElement,
-                credentialTypeElement, authenticSourceElement, collectIdElement
+                credentialTypeElement, authenticSourceElement, collectIdElement, localeElement
             ]);
 
-            postAndDisplayInArticleContainerFor("/secure/apigw/credential", requestBody, "Credential");
+            postAndDisplayCredential(requestBody);
         };
 
         const lineElement = document.createElement('hr');
@@ -558,7 +628,7 @@ const addCredentialFormArticleToContainer = () => {
         return [
             authenticSourcePersonIdElement, orTextElement, familyNameElement, givenNameElement,
             birthdateElement, lineElement, collectIdElement, schemaNameElement, documentTypeElement,
-            credentialTypeElement, authenticSourceElement, createButton
+            credentialTypeElement, authenticSourceElement, localeElement, createButton
         ];
     };
 
@@ -644,6 +714,375 @@ const addLoginArticleToContainer = () => {
     getElementById("username-input").focus();
 };
 
+let dashboardEventSource = null;
+
+const closeDashboardEventSource = () => {
+    if (dashboardEventSource) {
+        dashboardEventSource.close();
+        dashboardEventSource = null;
+    }
+};
+
+const buildTable = (headers, rows) => {
+    const table = document.createElement('table');
+    table.classList.add('table', 'is-fullwidth', 'is-striped', 'is-narrow');
+
+    const headRow = table.createTHead().insertRow();
+    for (const header of headers) {
+        const th = document.createElement('th');
+        th.textContent = header;
+        headRow.appendChild(th);
+    }
+
+    const tbody = table.createTBody();
+    for (const row of rows) {
+        const tr = tbody.insertRow();
+        for (const cell of row) {
+            if (cell instanceof Node) {
+                tr.insertCell().appendChild(cell);
+            } else {
+                tr.insertCell().textContent = cell;
+            }
+        }
+    }
+    return table;
+};
+
+const renderDashboard = (elements, statistics) => {
+    const queueDepth = statistics.queue_depth !== undefined ? statistics.queue_depth : "n/a (kafka disabled)";
+    elements.queueDepthP.textContent = `Queue depth: ${queueDepth}`;
+    elements.updatedP.textContent = `Updated: ${new Date(statistics.timestamp * 1000).toLocaleString()}`;
+
+    const sourceRows = (statistics.authentic_sources || []).map(s => [
+        s.authentic_source, s.documents, s.revoked, s.credentials_issued, s.credentials_failed
+    ]);
+    elements.sourcesDiv.replaceChildren(buildTable(
+        ["Authentic source", "Documents", "Revoked", "Credentials issued", "Credentials failed"], sourceRows));
+
+    const errorRows = (statistics.recent_errors || []).map(e => [
+        new Date(e.time * 1000).toLocaleString(), e.authentic_source, e.document_type, e.error
+    ]);
+    elements.errorsDiv.replaceChildren(buildTable(
+        ["Time", "Authentic source", "Document type", "Error"], errorRows));
+};
+
+const addDashboardArticleToContainer = () => {
+    closeDashboardEventSource();
+
+    const elements = {
+        updatedP: document.createElement('p'),
+        queueDepthP: document.createElement('p'),
+        sourcesDiv: document.createElement('div'),
+        errorsHeader: document.createElement('h2'),
+        errorsDiv: document.createElement('div'),
+    };
+    elements.updatedP.textContent = "Waiting for statistics...";
+    elements.errorsHeader.classList.add('subtitle');
+    elements.errorsHeader.textContent = "Recent errors";
+
+    const articleIdBasis = generateArticleIDBasis();
+    const articleDiv = buildArticle(articleIdBasis.articleID, "Issuance dashboard", [
+        elements.updatedP, elements.queueDepthP, elements.sourcesDiv, elements.errorsHeader, elements.errorsDiv
+    ]);
+    getElementById('article-container').prepend(articleDiv);
+
+    const eventSource = new EventSource(new URL("/secure/apigw/statistics/stream", baseUrl));
+    dashboardEventSource = eventSource;
+
+    const isArticleRemoved = () => {
+        if (getElementById(articleIdBasis.articleID) === null) {
+            eventSource.close();
+            return true;
+        }
+        return false;
+    };
+
+    eventSource.addEventListener('statistics', (event) => {
+        if (isArticleRemoved()) {
+            return;
+        }
+        renderDashboard(elements, JSON.parse(event.data));
+    });
+    eventSource.addEventListener('error', (event) => {
+        if (isArticleRemoved()) {
+            return;
+        }
+        if (event.data) {
+            elements.updatedP.textContent = `Error: ${JSON.parse(event.data).error}`;
+        }
+    });
+};
+
+const verifierAuditLimit = 50;
+
+const buildVerifierAuditQuery = (filter, offset) => {
+    const query = new URLSearchParams();
+    for (const [key, element] of Object.entries(filter)) {
+        if (element.type === 'checkbox') {
+            if (element.checked) {
+                query.set(key, 'true');
+            }
+        } else if (validateHasValueAndNotEmpty(element)) {
+            query.set(key, element.value.trim());
+        }
+    }
+    if (offset > 0) {
+        query.set('offset', offset);
+    }
+    return query;
+};
+
+const renderVerifierAudit = (elements, reply) => {
+    const rows = (reply.entries || []).map(e => [
+        new Date(e.created_at).toLocaleString(),
+        e.session_id || "",
+        e.policy || "",
+        (e.transaction_data_types || []).join(" "),
+        e.issuer,
+        e.credential_type,
+        e.valid ? "valid" : "invalid",
+        e.reason || "",
+        (e.policy_failures || []).join(" "),
+        (e.schema_errors || []).join(" "),
+        e.latency_ms,
+    ]);
+    elements.tableDiv.replaceChildren(buildTable(
+        ["Time", "Session", "Policy", "Transaction data", "Issuer", "Credential type", "Outcome", "Reason",
+            "Failed policy rules", "Schema errors", "Latency (ms)"], rows));
+};
+
+const addVerifierAuditArticleToContainer = () => {
+    const validSelect = document.createElement('select');
+    for (const [value, text] of [['', 'valid and invalid'], ['true', 'valid'], ['false', 'invalid']]) {
+        const option = document.createElement('option');
+        option.value = value;
+        option.textContent = text;
+        validSelect.appendChild(option);
+    }
+    const validDiv = document.createElement('div');
+    validDiv.classList.add('select');
+    validDiv.appendChild(validSelect);
+
+    const sessionsOnlyCheckbox = document.createElement('input');
+    sessionsOnlyCheckbox.type = 'checkbox';
+    const sessionsOnlyLabel = document.createElement('label');
+    sessionsOnlyLabel.classList.add('checkbox');
+    sessionsOnlyLabel.append(sessionsOnlyCheckbox, ' Presentation sessions only');
+
+    const filter = {
+        from: createInputElement('from (YYYY-MM-DD)'),
+        to: createInputElement('to (YYYY-MM-DD)'),
+        credential_type: createInputElement('credential type (vct)'),
+        valid: validSelect,
+        sessions_only: sessionsOnlyCheckbox,
+    };
+
+    const buildButton = (text) => {
+        const button = document.createElement('button');
+        button.classList.add('button', 'is-link');
+        button.textContent = text;
+        return button;
+    };
+    const searchButton = buildButton('Search');
+    const previousButton = buildButton('Previous');
+    const nextButton = buildButton('Next');
+    previousButton.disabled = true;
+    nextButton.disabled = true;
+
+    const exportLink = document.createElement('a');
+    exportLink.classList.add('button', 'is-dark');
+    exportLink.textContent = 'Export CSV';
+
+    const buttonsDiv = document.createElement('div');
+    buttonsDiv.classList.add('buttons');
+    buttonsDiv.append(searchButton, previousButton, nextButton, exportLink);
+
+    const elements = {
+        statusP: document.createElement('p'),
+        tableDiv: document.createElement('div'),
+    };
+
+    let offset = 0;
+    const search = async () => {
+        const query = buildVerifierAuditQuery(filter, offset);
+        query.set('limit', verifierAuditLimit);
+        exportLink.href = new URL("/secure/verifier/audit/export?" + buildVerifierAuditQuery(filter, 0), baseUrl);
+
+        elements.statusP.textContent = "Loading...";
+        try {
+            const response = await fetch(new URL("/secure/verifier/audit?" + query, baseUrl), {
+                headers: {'Accept': 'application/json'},
+            });
+            if (response.status === 401) {
+                clearAllContentContainers();
+                hideSecureMenyItems();
+                return;
+            }
+            const jsonBody = await response.json();
+            if (!response.ok) {
+                throw new Error(`HTTP error! status: ${response.status}, body: ${JSON.stringify(jsonBody)}`);
+            }
+
+            const reply = jsonBody;
+            renderVerifierAudit(elements, reply);
+            elements.statusP.textContent = `Entries ${offset + 1}-${offset + (reply.entries || []).length}`;
+            previousButton.disabled = offset === 0;
+            nextButton.disabled = !reply.next_offset;
+            nextButton.onclick = () => {
+                offset = reply.next_offset;
+                search();
+            };
+        } catch (err) {
+            elements.statusP.textContent = `Error: ${err.message}`;
+        }
+    };
+
+    searchButton.onclick = () => {
+        offset = 0;
+        search();
+    };
+    previousButton.onclick = () => {
+        offset = Math.max(0, offset - verifierAuditLimit);
+        search();
+    };
+
+    const articleIdBasis = generateArticleIDBasis();
+    const articleDiv = buildArticle(articleIdBasis.articleID, "Verifier audit", [
+        filter.from, filter.to, filter.credential_type, validDiv, sessionsOnlyLabel, buttonsDiv,
+        elements.statusP, elements.tableDiv
+    ]);
+    getElementById('article-container').prepend(articleDiv);
+
+    search();
+};
+
+const postJSON = async (path, requestBody) => {
+    const response = await fetch(new URL(path, baseUrl), {
+        method: 'POST',
+        headers: {'Accept': 'application/json', 'Content-Type': 'application/json; charset=utf-8'},
+        body: JSON.stringify(requestBody),
+    });
+    if (response.status === 401) {
+        clearAllContentContainers();
+        hideSecureMenyItems();
+        return null;
+    }
+    const jsonBody = await response.json();
+    if (!response.ok) {
+        throw new Error(`HTTP error! status: ${response.status}, body: ${JSON.stringify(jsonBody)}`);
+    }
+    return jsonBody;
+};
+
+const formatIdentity = (identity) => {
+    if (!identity) {
+        return "";
+    }
+    return `${identity.given_name || ""} ${identity.family_name || ""}, born ${identity.birth_date || "?"}`;
+};
+
+const addIdentityReviewArticleToContainer = () => {
+    const statusSelect = document.createElement('select');
+    for (const [value, text] of [['pending', 'pending'], ['approved', 'approved'], ['rejected', 'rejected']]) {
+        const option = document.createElement('option');
+        option.value = value;
+        option.textContent = text;
+        statusSelect.appendChild(option);
+    }
+    const statusDiv = document.createElement('div');
+    statusDiv.classList.add('select');
+    statusDiv.appendChild(statusSelect);
+
+    const authenticSourceInput = createInputElement('authentic source');
+
+    const searchButton = document.createElement('button');
+    searchButton.classList.add('button', 'is-link');
+    searchButton.textContent = 'Search';
+
+    const elements = {
+        statusP: document.createElement('p'),
+        tableDiv: document.createElement('div'),
+    };
+
+    const decide = async (review, decision, commentInput) => {
+        elements.statusP.textContent = "Saving...";
+        try {
+            await postJSON("/secure/apigw/identity/review/decision", {
+                authentic_source: review.authentic_source,
+                review_id: review.review_id,
+                decision: decision,
+                comment: commentInput.value.trim(),
+            });
+            search();
+        } catch (err) {
+            elements.statusP.textContent = `Error: ${err.message}`;
+        }
+    };
+
+    const buildDecisionCell = (review) => {
+        if (review.status !== 'pending') {
+            return `${review.status} by ${review.reviewer} ${review.comment || ""}`;
+        }
+        const commentInput = createInputElement('comment');
+        const approveButton = document.createElement('button');
+        approveButton.classList.add('button', 'is-success', 'is-small');
+        approveButton.textContent = 'Approve';
+        approveButton.onclick = () => decide(review, 'approved', commentInput);
+        const rejectButton = document.createElement('button');
+        rejectButton.classList.add('button', 'is-danger', 'is-small');
+        rejectButton.textContent = 'Reject';
+        rejectButton.onclick = () => decide(review, 'rejected', commentInput);
+
+        const div = document.createElement('div');
+        const buttonsDiv = document.createElement('div');
+        buttonsDiv.classList.add('buttons');
+        buttonsDiv.append(approveButton, rejectButton);
+        div.append(commentInput, buttonsDiv);
+        return div;
+    };
+
+    const search = async () => {
+        if (!validateHasValueAndNotEmpty(authenticSourceInput)) {
+            elements.statusP.textContent = "Authentic source is required";
+            return;
+        }
+        elements.statusP.textContent = "Loading...";
+        try {
+            const reply = await postJSON("/secure/apigw/identity/review/list", {
+                authentic_source: authenticSourceInput.value.trim(),
+                status: statusSelect.value,
+            });
+            if (reply === null) {
+                return;
+            }
+            const reviews = reply.data || [];
+            const rows = reviews.map(r => [
+                new Date(r.created_at * 1000).toLocaleString(),
+                r.document_type,
+                r.document_id,
+                formatIdentity(r.identity),
+                r.authentic_source_person_id,
+                r.score.toFixed(2),
+                Object.entries(r.field_scores || {}).map(([k, v]) => `${k}: ${v.toFixed(2)}`).join(" "),
+                buildDecisionCell(r),
+            ]);
+            elements.tableDiv.replaceChildren(buildTable(
+                ["Time", "Document type", "Document ID", "Requested identity", "Matched person", "Score",
+                    "Field scores", "Decision"], rows));
+            elements.statusP.textContent = `Reviews: ${reviews.length}`;
+        } catch (err) {
+            elements.statusP.textContent = `Error: ${err.message}`;
+        }
+    };
+    searchButton.onclick = search;
+
+    const articleIdBasis = generateArticleIDBasis();
+    const articleDiv = buildArticle(articleIdBasis.articleID, "Identity review", [
+        authenticSourceInput, statusDiv, searchButton, elements.statusP, elements.tableDiv
+    ]);
+    getElementById('article-container').prepend(articleDiv);
+};
+
 async function doLogout() {
     const url = new URL("/secure/logout", baseUrl);
     console.debug("doLogout for url: " + url);
@@ -658,6 +1097,7 @@ async function doLogout() {
 
     //TODO(mk): add error handling
     await fetch(request);
+    closeDashboardEventSource();
     hideSecureMenyItems();
     clearAllContentContainers();
 }
@@ -0,0 +1,206 @@
+package apiv1
+
+import (
+	"context"
+	"strings"
+	"time"
+	"vc/internal/verifier/db"
+	"vc/pkg/helpers"
+	"vc/pkg/model"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// analyticsDays is the length of the period when a request does not give from
+	analyticsDays = 30
+
+	// analyticsIssuerLimit is the number of issuers returned when a request does not give limit
+	analyticsIssuerLimit = 10
+)
+
+// analytics reports whether verification outcomes are recorded, the database is also used by the mongo session store
+func (c *Client) analytics() bool {
+	return c.db != nil && c.cfg.Verifier.Analytics.Enabled
+}
+
+// recordVerification stores the outcome of a verification, session is nil unless the credential was presented in a
+// presentation session. Failures are logged and not returned.
+func (c *Client) recordVerification(ctx context.Context, req *VerifyCredentialRequest, reply *VerifyCredentialReply, latency time.Duration, session *Session) {
+	record := &model.VerificationRecord{
+		KID:       reply.KID,
+		Policy:    req.Policy,
+		Valid:     reply.Valid,
+		Reason:    reply.Reason,
+		LatencyMS: latency.Milliseconds(),
+		CreatedAt: time.Now(),
+	}
+	if reply.Policy != nil {
+		record.Policy = reply.Policy.Policy
+		for _, rule := range reply.Policy.Rules {
+			if !rule.Passed {
+				record.PolicyFailures = append(record.PolicyFailures, rule.Rule)
+			}
+		}
+	}
+	for _, violation := range reply.SchemaErrors {
+		record.SchemaErrors = append(record.SchemaErrors, violation.Path)
+	}
+	if session != nil {
+		record.SessionID = session.ID
+		for _, td := range session.TransactionData {
+			typ, _ := td.Data["type"].(string)
+			record.TransactionDataTypes = append(record.TransactionDataTypes, typ)
+		}
+	}
+
+	// the signature is already verified, or the failure recorded
+	signedJWT, _, _ := strings.Cut(req.Credential, "~")
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(signedJWT, claims); err == nil {
+		record.Issuer, _ = claims["iss"].(string)
+		record.CredentialType, _ = claims["vct"].(string)
+	}
+
+	if err := c.db.VCVerificationColl.Add(context.WithoutCancel(ctx), record); err != nil {
+		c.log.Error(err, "failed to record verification")
+	}
+}
+
+// AnalyticsRequest is the period of an analytics query, in whole UTC days
+type AnalyticsRequest struct {
+	// From is the first day, 30 days before to if empty
+	From string `form:"from" validate:"omitempty,datetime=2006-01-02"`
+
+	// To is the last day, today if empty
+	To string `form:"to" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// period returns the start of the first day and the end of the last day of the request
+func (r *AnalyticsRequest) period() (time.Time, time.Time, error) {
+	if err := helpers.CheckSimple(r); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if r.To != "" {
+		to, _ = time.Parse(time.DateOnly, r.To)
+	}
+
+	from := to.AddDate(0, 0, -analyticsDays+1)
+	if r.From != "" {
+		from, _ = time.Parse(time.DateOnly, r.From)
+	}
+
+	return from, to.AddDate(0, 0, 1), nil
+}
+
+// DailyAnalyticsReply is the reply for DailyAnalytics
+type DailyAnalyticsReply struct {
+	Days []*db.DailyStatistics `json:"days"`
+}
+
+// DailyAnalytics returns the number of verifications per day
+//
+//	@Summary		Daily analytics
+//	@ID				verifier-analytics-daily
+//	@Description	Number of valid and invalid verifications and the average latency per UTC day, days without verifications are left out
+//	@Tags			verifier
+//	@Produce		json
+//	@Success		200		{object}	DailyAnalyticsReply	"Success"
+//	@Failure		400		{object}	helpers.Problem		"Bad Request"
+//	@Param			from	query		string				false	"First day, YYYY-MM-DD, 30 days before to if empty"
+//	@Param			to		query		string				false	"Last day, YYYY-MM-DD, today if empty"
+//	@Router			/analytics/daily [get]
+func (c *Client) DailyAnalytics(ctx context.Context, req *AnalyticsRequest) (*DailyAnalyticsReply, error) {
+	from, to, err := req.period()
+	if err != nil {
+		return nil, err
+	}
+
+	days, err := c.db.VCVerificationColl.Daily(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DailyAnalyticsReply{Days: days}, nil
+}
+
+// FailureAnalyticsReply is the reply for FailureAnalytics
+type FailureAnalyticsReply struct {
+	Failures []*db.FailureStatistics `json:"failures"`
+}
+
+// FailureAnalytics returns the number of failed verifications per reason
+//
+//	@Summary		Failure analytics
+//	@ID				verifier-analytics-failures
+//	@Description	Number of failed verifications per reason, most frequent first
+//	@Tags			verifier
+//	@Produce		json
+//	@Success		200		{object}	FailureAnalyticsReply	"Success"
+//	@Failure		400		{object}	helpers.Problem			"Bad Request"
+//	@Param			from	query		string					false	"First day, YYYY-MM-DD, 30 days before to if empty"
+//	@Param			to		query		string					false	"Last day, YYYY-MM-DD, today if empty"
+//	@Router			/analytics/failures [get]
+func (c *Client) FailureAnalytics(ctx context.Context, req *AnalyticsRequest) (*FailureAnalyticsReply, error) {
+	from, to, err := req.period()
+	if err != nil {
+		return nil, err
+	}
+
+	failures, err := c.db.VCVerificationColl.Failures(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FailureAnalyticsReply{Failures: failures}, nil
+}
+
+// IssuerAnalyticsRequest is the request for IssuerAnalytics
+type IssuerAnalyticsRequest struct {
+	AnalyticsRequest
+
+	// Limit is the number of issuers, 10 if zero
+	Limit int64 `form:"limit" validate:"omitempty,gt=0,lte=100"`
+}
+
+// IssuerAnalyticsReply is the reply for IssuerAnalytics
+type IssuerAnalyticsReply struct {
+	Issuers []*db.IssuerStatistics `json:"issuers"`
+}
+
+// IssuerAnalytics returns the issuers with the most verified credentials
+//
+//	@Summary		Issuer analytics
+//	@ID				verifier-analytics-issuers
+//	@Description	Issuers ranked by the number of verified credentials, with the number of valid and invalid verifications
+//	@Tags			verifier
+//	@Produce		json
+//	@Success		200		{object}	IssuerAnalyticsReply	"Success"
+//	@Failure		400		{object}	helpers.Problem			"Bad Request"
+//	@Param			from	query		string					false	"First day, YYYY-MM-DD, 30 days before to if empty"
+//	@Param			to		query		string					false	"Last day, YYYY-MM-DD, today if empty"
+//	@Param			limit	query		int						false	"Number of issuers, at most 100, 10 if empty"
+//	@Router			/analytics/issuers [get]
+func (c *Client) IssuerAnalytics(ctx context.Context, req *IssuerAnalyticsRequest) (*IssuerAnalyticsReply, error) {
+	if err := helpers.CheckSimple(req); err != nil {
+		return nil, err
+	}
+
+	from, to, err := req.period()
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Limit == 0 {
+		req.Limit = analyticsIssuerLimit
+	}
+
+	issuers, err := c.db.VCVerificationColl.Issuers(ctx, from, to, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IssuerAnalyticsReply{Issuers: issuers}, nil
+}
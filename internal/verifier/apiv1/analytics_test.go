@@ -0,0 +1,61 @@
+package apiv1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyticsPeriod(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	date := func(s string) time.Time {
+		d, err := time.Parse(time.DateOnly, s)
+		assert.NoError(t, err)
+		return d
+	}
+
+	tts := []struct {
+		name     string
+		req      AnalyticsRequest
+		wantFrom time.Time
+		wantTo   time.Time
+		wantErr  bool
+	}{
+		{
+			name:     "default",
+			wantFrom: today.AddDate(0, 0, -29),
+			wantTo:   today.AddDate(0, 0, 1),
+		},
+		{
+			name:     "from and to",
+			req:      AnalyticsRequest{From: "2024-01-01", To: "2024-01-31"},
+			wantFrom: date("2024-01-01"),
+			wantTo:   date("2024-02-01"),
+		},
+		{
+			name:     "to only",
+			req:      AnalyticsRequest{To: "2024-03-31"},
+			wantFrom: date("2024-03-02"),
+			wantTo:   date("2024-04-01"),
+		},
+		{
+			name:    "not a date",
+			req:     AnalyticsRequest{From: "2024-01-01T00:00:00Z"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := tt.req.period()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantFrom, from)
+			assert.Equal(t, tt.wantTo, to)
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package apiv1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"vc/pkg/keyresolver"
+	"vc/pkg/statuslist"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lestrrat-go/jwx/jwk"
+)
+
+const (
+	// typeWalletAttestation is the typ of a wallet instance attestation, draft-ietf-oauth-attestation-based-client-auth
+	typeWalletAttestation = "oauth-client-attestation+jwt"
+
+	// typeWalletAttestationPoP is the typ of the proof of possession of the wallet instance key
+	typeWalletAttestationPoP = "oauth-client-attestation-pop+jwt"
+)
+
+var (
+	// errWalletAttestationMissing is the reason when a wallet attestation is required but not sent
+	errWalletAttestationMissing = errors.New("wallet attestation is required")
+
+	// errWalletAttestationRevoked is the reason when the wallet provider has revoked the attestation
+	errWalletAttestationRevoked = errors.New("wallet attestation is revoked")
+)
+
+// WalletAttestationResult is the outcome of the wallet attestation validation
+type WalletAttestationResult struct {
+	Valid  bool   `json:"valid"`
+	Reason string `json:"reason,omitempty"`
+
+	// Provider is the wallet provider, the iss of the attestation
+	Provider string `json:"provider,omitempty"`
+
+	// WalletName is the name of the wallet solution, if the attestation has one
+	WalletName string `json:"wallet_name,omitempty"`
+}
+
+// walletAttestationClaims are the attestation claims used here
+type walletAttestationClaims struct {
+	jwt.RegisteredClaims
+	WalletName string `json:"wallet_name,omitempty"`
+	CNF        struct {
+		JWK json.RawMessage `json:"jwk"`
+	} `json:"cnf"`
+	Status *statuslist.Status `json:"status,omitempty"`
+}
+
+// popClaims are the claims of the proof of possession
+type popClaims struct {
+	jwt.RegisteredClaims
+	Nonce string `json:"nonce"`
+}
+
+// verifyWalletAttestation validates a wallet attestation against the wallet provider trust anchors, and that the
+// proof of possession is signed with the attested wallet key for this verifier and nonce
+func (c *Client) verifyWalletAttestation(ctx context.Context, attestation, pop, nonce string) *WalletAttestationResult {
+	result := &WalletAttestationResult{}
+	if err := c.walletAttestation(ctx, attestation, pop, nonce, result); err != nil {
+		result.Reason = err.Error()
+		return result
+	}
+
+	result.Valid = true
+	return result
+}
+
+func (c *Client) walletAttestation(ctx context.Context, attestation, pop, nonce string, result *WalletAttestationResult) error {
+	if attestation == "" {
+		return errWalletAttestationMissing
+	}
+
+	claims := &walletAttestationClaims{}
+	if _, err := jwt.ParseWithClaims(attestation, claims, c.walletProviderKey(ctx, typeWalletAttestation), jwt.WithExpirationRequired()); err != nil {
+		return fmt.Errorf("wallet attestation: %w", err)
+	}
+	result.Provider = claims.Issuer
+	result.WalletName = claims.WalletName
+
+	// device key binding, the wallet proves possession of the attested key
+	walletKey, err := jwk.ParseKey(claims.CNF.JWK)
+	if err != nil {
+		return fmt.Errorf("wallet attestation cnf: %w", err)
+	}
+	var publicKey any
+	if err := walletKey.Raw(&publicKey); err != nil {
+		return err
+	}
+
+	proof := &popClaims{}
+	_, err = jwt.ParseWithClaims(pop, proof, func(token *jwt.Token) (any, error) {
+		if typ, _ := token.Header["typ"].(string); typ != typeWalletAttestationPoP {
+			return nil, fmt.Errorf("typ %q", typ)
+		}
+		return publicKey, nil
+	},
+		jwt.WithIssuer(claims.Subject),
+		jwt.WithAudience(c.cfg.Verifier.ExternalURL),
+		jwt.WithIssuedAt(),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return fmt.Errorf("wallet attestation pop: %w", err)
+	}
+	if proof.Nonce != nonce {
+		return fmt.Errorf("wallet attestation pop: nonce does not match")
+	}
+
+	if claims.Status != nil && claims.Status.StatusList != nil {
+		fetcher := statuslist.NewFetcher(c.httpClient, func(ctx context.Context) jwt.Keyfunc {
+			return c.walletProviderKey(ctx, "statuslist+jwt")
+		})
+		status, err := fetcher.FetchStatus(ctx, claims.Status.StatusList)
+		if err != nil {
+			return fmt.Errorf("wallet attestation status: %w", err)
+		}
+		if status != statuslist.StatusValid {
+			return errWalletAttestationRevoked
+		}
+	}
+
+	return nil
+}
+
+// walletProviderKey returns a jwt.Keyfunc resolving the x5c header through the wallet provider trust anchors
+func (c *Client) walletProviderKey(ctx context.Context, typ string) jwt.Keyfunc {
+	return func(token *jwt.Token) (any, error) {
+		if got, _ := token.Header["typ"].(string); got != typ {
+			return nil, fmt.Errorf("typ %q", got)
+		}
+
+		x5c, _ := token.Header["x5c"].([]any)
+		encoded := make([]string, 0, len(x5c))
+		for _, v := range x5c {
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("x5c is not a list of strings")
+			}
+			encoded = append(encoded, s)
+		}
+
+		chain, err := keyresolver.ParseX5C(encoded)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.walletProviders.ResolveChain(ctx, chain)
+	}
+}
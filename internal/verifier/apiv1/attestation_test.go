@@ -0,0 +1,142 @@
+package apiv1
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"vc/pkg/keyresolver"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+	"vc/pkg/statuslist"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockWalletProvider struct {
+	key  *ecdsa.PrivateKey
+	cert *x509.Certificate
+}
+
+func newMockWalletProvider(t *testing.T) *mockWalletProvider {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "wallet provider"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	return &mockWalletProvider{key: key, cert: cert}
+}
+
+func (p *mockWalletProvider) sign(t *testing.T, typ string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["typ"] = typ
+	token.Header["x5c"] = []string{base64.StdEncoding.EncodeToString(p.cert.Raw)}
+
+	signed, err := token.SignedString(p.key)
+	assert.NoError(t, err)
+	return signed
+}
+
+func TestVerifyWalletAttestation(t *testing.T) {
+	ctx := context.Background()
+	provider := newMockWalletProvider(t)
+	otherProvider := newMockWalletProvider(t)
+
+	walletKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	walletJWK, err := jwk.New(walletKey.Public())
+	assert.NoError(t, err)
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		list, err := statuslist.NewTokenStatusList(8, 1)
+		assert.NoError(t, err)
+		assert.NoError(t, list.Set(1, statuslist.StatusInvalid))
+		claim, err := list.JWTClaim("")
+		assert.NoError(t, err)
+
+		w.Write([]byte(provider.sign(t, "statuslist+jwt", jwt.MapClaims{
+			"sub":         server.URL + r.URL.Path,
+			"iat":         time.Now().Unix(),
+			"status_list": claim,
+		})))
+	}))
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(provider.cert)
+	client := &Client{
+		cfg:             &model.Cfg{Verifier: model.Verifier{ExternalURL: "https://verifier.sunet.se"}},
+		log:             logger.NewSimple("testing_apiv1"),
+		walletProviders: keyresolver.NewX509(roots),
+		httpClient:      server.Client(),
+	}
+
+	attestation := func(provider *mockWalletProvider, idx int) string {
+		return provider.sign(t, typeWalletAttestation, jwt.MapClaims{
+			"iss":         "https://wallet-provider.example.com",
+			"sub":         "wallet-client",
+			"exp":         time.Now().Add(time.Hour).Unix(),
+			"wallet_name": "Test wallet",
+			"cnf":         map[string]any{"jwk": walletJWK},
+			"status":      map[string]any{"status_list": map[string]any{"idx": idx, "uri": server.URL + "/statuslists/1"}},
+		})
+	}
+	pop := func(nonce string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+			"iss":   "wallet-client",
+			"aud":   "https://verifier.sunet.se",
+			"iat":   time.Now().Unix(),
+			"exp":   time.Now().Add(time.Minute).Unix(),
+			"nonce": nonce,
+		})
+		token.Header["typ"] = typeWalletAttestationPoP
+		signed, err := token.SignedString(walletKey)
+		assert.NoError(t, err)
+		return signed
+	}
+
+	tts := []struct {
+		name        string
+		attestation string
+		pop         string
+		wantValid   bool
+		wantReason  string
+	}{
+		{name: "valid", attestation: attestation(provider, 0), pop: pop("nonce"), wantValid: true},
+		{name: "missing", pop: pop("nonce"), wantReason: errWalletAttestationMissing.Error()},
+		{name: "untrusted provider", attestation: attestation(otherProvider, 0), pop: pop("nonce"), wantReason: keyresolver.ErrUntrustedChain.Error()},
+		{name: "wrong nonce", attestation: attestation(provider, 0), pop: pop("other"), wantReason: "nonce does not match"},
+		{name: "revoked", attestation: attestation(provider, 1), pop: pop("nonce"), wantReason: errWalletAttestationRevoked.Error()},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			result := client.verifyWalletAttestation(ctx, tt.attestation, tt.pop, "nonce")
+			assert.Equal(t, tt.wantValid, result.Valid, result.Reason)
+			assert.Contains(t, result.Reason, tt.wantReason)
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package apiv1
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"vc/internal/verifier/db"
+	"vc/pkg/helpers"
+	"vc/pkg/model"
+	"vc/pkg/sdjwt"
+)
+
+const (
+	// auditLimit is the number of entries returned when a request does not give limit
+	auditLimit = 100
+
+	// redacted replaces attribute values in the audit trail
+	redacted = "[redacted]"
+)
+
+// quotedValue matches the quoted values in a reason, e.g. the claim value in a message of the vct schema
+var quotedValue = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'`)
+
+// AuditRequest is the request for Audit
+type AuditRequest struct {
+	AnalyticsRequest
+
+	// SessionsOnly leaves out the verifications that were not part of a presentation session
+	SessionsOnly bool `form:"sessions_only"`
+
+	// Valid selects the valid or the invalid verifications, both if empty
+	Valid *bool `form:"valid"`
+
+	// CredentialType selects the verifications of one vct
+	CredentialType string `form:"credential_type"`
+
+	Offset int64 `form:"offset" validate:"omitempty,gte=0"`
+
+	// Limit is the number of entries, 100 if zero
+	Limit int64 `form:"limit" validate:"omitempty,gt=0,lte=10000"`
+}
+
+// AuditEntry is one verification in the audit trail. Attribute values are redacted, claims are only named.
+type AuditEntry struct {
+	CreatedAt time.Time `json:"created_at"`
+
+	// SessionID is empty for verifications that were not part of a presentation session
+	SessionID string `json:"session_id,omitempty"`
+
+	// Policy and TransactionDataTypes is what the relying party requested
+	Policy               string   `json:"policy,omitempty"`
+	TransactionDataTypes []string `json:"transaction_data_types,omitempty"`
+
+	// Issuer and CredentialType is what the wallet presented
+	Issuer         string `json:"issuer"`
+	CredentialType string `json:"credential_type"`
+	KID            string `json:"kid"`
+
+	Valid          bool     `json:"valid"`
+	Reason         string   `json:"reason,omitempty"`
+	PolicyFailures []string `json:"policy_failures,omitempty"`
+	SchemaErrors   []string `json:"schema_errors,omitempty"`
+	LatencyMS      int64    `json:"latency_ms"`
+}
+
+// newAuditEntry returns the audit entry of record, with the values in its reason redacted
+func newAuditEntry(record *model.VerificationRecord) *AuditEntry {
+	entry := &AuditEntry{
+		CreatedAt:            record.CreatedAt.UTC(),
+		SessionID:            record.SessionID,
+		Policy:               record.Policy,
+		TransactionDataTypes: record.TransactionDataTypes,
+		Issuer:               record.Issuer,
+		CredentialType:       record.CredentialType,
+		KID:                  record.KID,
+		Valid:                record.Valid,
+		Reason:               redactReason(record.Reason),
+		PolicyFailures:       record.PolicyFailures,
+		SchemaErrors:         record.SchemaErrors,
+		LatencyMS:            record.LatencyMS,
+	}
+
+	// the messages of the schema may hold values without quotes, the claims are in schema_errors
+	if len(record.SchemaErrors) > 0 {
+		entry.Reason = sdjwt.ErrSchemaValidation.Error()
+	}
+
+	return entry
+}
+
+// redactReason replaces the quoted values of reason
+func redactReason(reason string) string {
+	return quotedValue.ReplaceAllString(reason, redacted)
+}
+
+// AuditReply is the reply for Audit
+type AuditReply struct {
+	Entries []*AuditEntry `json:"entries"`
+
+	// NextOffset is the offset of the next page, zero on the last page
+	NextOffset int64 `json:"next_offset,omitempty"`
+}
+
+// auditColumns are the columns of the CSV export, in the order of WriteCSV
+var auditColumns = []string{
+	"created_at",
+	"session_id",
+	"policy",
+	"transaction_data_types",
+	"issuer",
+	"credential_type",
+	"kid",
+	"valid",
+	"reason",
+	"policy_failures",
+	"schema_errors",
+	"latency_ms",
+}
+
+// WriteCSV writes the entries as CSV with a header row, lists are separated by spaces
+func (r *AuditReply) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(auditColumns); err != nil {
+		return err
+	}
+
+	for _, entry := range r.Entries {
+		if err := writer.Write([]string{
+			entry.CreatedAt.Format(time.RFC3339),
+			entry.SessionID,
+			entry.Policy,
+			strings.Join(entry.TransactionDataTypes, " "),
+			entry.Issuer,
+			entry.CredentialType,
+			entry.KID,
+			strconv.FormatBool(entry.Valid),
+			entry.Reason,
+			strings.Join(entry.PolicyFailures, " "),
+			strings.Join(entry.SchemaErrors, " "),
+			strconv.FormatInt(entry.LatencyMS, 10),
+		}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// Audit returns the verifications of a period, newest first
+//
+//	@Summary		Audit
+//	@ID				verifier-audit
+//	@Description	The audit trail of verifications and presentation sessions, newest first. Each entry has what the relying party requested, what the wallet presented and the outcome with the failed policy rules. Values in failure reasons are redacted and claims are only named. With Accept: text/csv the page is returned as CSV.
+//	@Tags			verifier
+//	@Produce		json,text/csv
+//	@Success		200				{object}	AuditReply		"Success"
+//	@Failure		400				{object}	helpers.Problem	"Bad Request"
+//	@Param			from			query		string			false	"First day, YYYY-MM-DD, 30 days before to if empty"
+//	@Param			to				query		string			false	"Last day, YYYY-MM-DD, today if empty"
+//	@Param			sessions_only	query		bool			false	"Only verifications of presentation sessions"
+//	@Param			valid			query		bool			false	"Only valid or invalid verifications"
+//	@Param			credential_type	query		string			false	"Only verifications of this vct"
+//	@Param			offset			query		int				false	"Entries to skip"
+//	@Param			limit			query		int				false	"Number of entries, at most 10000, 100 if empty"
+//	@Router			/audit [get]
+func (c *Client) Audit(ctx context.Context, req *AuditRequest) (*AuditReply, error) {
+	if err := helpers.CheckSimple(req); err != nil {
+		return nil, err
+	}
+
+	from, to, err := req.period()
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Limit == 0 {
+		req.Limit = auditLimit
+	}
+
+	// one more record tells if there is a next page
+	records, err := c.db.VCVerificationColl.List(ctx, &db.AuditFilter{
+		From:           from,
+		To:             to,
+		SessionsOnly:   req.SessionsOnly,
+		Valid:          req.Valid,
+		CredentialType: req.CredentialType,
+	}, req.Offset, req.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &AuditReply{Entries: make([]*AuditEntry, 0, len(records))}
+	if int64(len(records)) > req.Limit {
+		records = records[:req.Limit]
+		reply.NextOffset = req.Offset + req.Limit
+	}
+	for _, record := range records {
+		reply.Entries = append(reply.Entries, newAuditEntry(record))
+	}
+
+	return reply, nil
+}
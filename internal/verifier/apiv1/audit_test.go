@@ -0,0 +1,68 @@
+package apiv1
+
+import (
+	"bytes"
+	"testing"
+	"time"
+	"vc/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAuditEntry(t *testing.T) {
+	tts := []struct {
+		name       string
+		record     *model.VerificationRecord
+		wantReason string
+	}{
+		{
+			name:       "valid",
+			record:     &model.VerificationRecord{Valid: true},
+			wantReason: "",
+		},
+		{
+			name:       "double quoted value",
+			record:     &model.VerificationRecord{Reason: `claim family_name "Andersson" is not allowed`},
+			wantReason: "claim family_name [redacted] is not allowed",
+		},
+		{
+			name:       "single quoted value with escaped quote",
+			record:     &model.VerificationRecord{Reason: `value 'O\'Brien' and 'x'`},
+			wantReason: "value [redacted] and [redacted]",
+		},
+		{
+			name:       "schema errors",
+			record:     &model.VerificationRecord{Reason: "/birth_date: 1970-01-01 is not valid", SchemaErrors: []string{"/birth_date"}},
+			wantReason: "claims do not conform to vctm schema",
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := newAuditEntry(tt.record)
+			assert.Equal(t, tt.wantReason, entry.Reason)
+			assert.NotContains(t, entry.Reason, "1970-01-01")
+		})
+	}
+}
+
+func TestAuditReplyWriteCSV(t *testing.T) {
+	reply := &AuditReply{Entries: []*AuditEntry{
+		{
+			CreatedAt:            time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+			SessionID:            "s1",
+			TransactionDataTypes: []string{"payment", "qes"},
+			Issuer:               "https://issuer.example.com",
+			CredentialType:       "urn:eudi:pid:1",
+			KID:                  "k1",
+			Reason:               "policy, rejected",
+			PolicyFailures:       []string{"issuer", "age"},
+			LatencyMS:            12,
+		},
+	}}
+
+	var buf bytes.Buffer
+	assert.NoError(t, reply.WriteCSV(&buf))
+	assert.Equal(t, "created_at,session_id,policy,transaction_data_types,issuer,credential_type,kid,valid,reason,policy_failures,schema_errors,latency_ms\n"+
+		`2025-01-02T03:04:05Z,s1,,payment qes,https://issuer.example.com,urn:eudi:pid:1,k1,false,"policy, rejected",issuer age,,12`+"\n", buf.String())
+}
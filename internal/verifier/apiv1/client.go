@@ -2,21 +2,122 @@ package apiv1
 
 import (
 	"context"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+	"vc/internal/verifier/db"
+	"vc/internal/verifier/policy"
+	"vc/internal/verifier/trust"
+	"vc/pkg/keyresolver"
 	"vc/pkg/logger"
 	"vc/pkg/model"
+	"vc/pkg/sdjwt"
+	"vc/pkg/statuslist"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lestrrat-go/jwx/jwk"
 )
 
+//	@title		Verifier API
+//	@version	0.1.0
+//	@BasePath	/api/v1
+
 // Client holds the public api object
 type Client struct {
-	cfg *model.Cfg
-	log *logger.Log
+	cfg        *model.Cfg
+	log        *logger.Log
+	db         *db.Service
+	issuerJWKS *jwk.AutoRefresh
+	sessions   sessionStore
+	vctm       *sdjwt.VCTMResolver
+	trust      *trust.Service
+	policies   *policy.Engine
+
+	// federation authenticates issuers by OpenID Federation, nil if disabled
+	federation *federation
+
+	sessionMetrics *sessionMetrics
+
+	walletProviders *keyresolver.X509
+	httpClient      *http.Client
+
+	// credentialStatus checks the status claim of credentials, nil if status checks are disabled
+	credentialStatus sdjwt.StatusFetcher
+
+	// jwksRefreshMu guards jwksRefreshedAt, the time of the latest JWKS refresh forced by an unknown kid
+	jwksRefreshMu   sync.Mutex
+	jwksRefreshedAt time.Time
+
+	// webhookClient posts session results to the allow listed webhooks
+	webhookClient *http.Client
 }
 
-// New creates a new instance of the public api
-func New(ctx context.Context, cfg *model.Cfg, log *logger.Log) (*Client, error) {
+// New creates a new instance of the public api, db and trust may be nil
+func New(ctx context.Context, db *db.Service, trust *trust.Service, cfg *model.Cfg, log *logger.Log) (*Client, error) {
 	c := &Client{
-		cfg: cfg,
-		log: log.New("apiv1"),
+		cfg:           cfg,
+		log:           log.New("apiv1"),
+		db:            db,
+		trust:         trust,
+		httpClient:    &http.Client{Timeout: 10 * time.Second},
+		webhookClient: newWebhookClient(),
+	}
+
+	var err error
+	c.sessions, err = newSessionStore(ctx, &cfg.Verifier.Session, db)
+	if err != nil {
+		return nil, err
+	}
+
+	c.sessionMetrics, err = newSessionMetrics()
+	if err != nil {
+		return nil, err
+	}
+
+	c.policies, err = policy.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Verifier.IssuerJWKSURL != "" {
+		c.issuerJWKS = jwk.NewAutoRefresh(ctx)
+		c.issuerJWKS.Configure(cfg.Verifier.IssuerJWKSURL, jwk.WithMinRefreshInterval(15*time.Minute))
+	}
+
+	if cfg.Verifier.Federation.Enabled {
+		c.federation, err = newFederation(&cfg.Verifier.Federation, c.httpClient)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Verifier.VCTM.Enabled {
+		c.vctm = sdjwt.NewVCTMResolver(nil, time.Duration(cfg.Verifier.VCTM.CacheTTL)*time.Second, cfg.Verifier.VCTM.AllowList)
+	}
+
+	if cfg.Verifier.WalletAttestation.Enabled {
+		roots, err := keyresolver.LoadTrustAnchors(cfg.Verifier.WalletAttestation.TrustAnchors)
+		if err != nil {
+			return nil, err
+		}
+		c.walletProviders = keyresolver.NewX509(roots)
+	}
+
+	if cfg.Verifier.StatusList.Enabled {
+		keyByte, err := os.ReadFile(cfg.Verifier.StatusList.PublicKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		publicKey, err := jwt.ParseECPublicKeyFromPEM(keyByte)
+		if err != nil {
+			return nil, err
+		}
+		c.credentialStatus = statuslist.NewFetcher(c.httpClient, func(ctx context.Context) jwt.Keyfunc {
+			return func(token *jwt.Token) (any, error) {
+				return publicKey, nil
+			}
+		})
 	}
 
 	c.log.Info("Started")
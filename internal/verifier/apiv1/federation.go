@@ -0,0 +1,146 @@
+package apiv1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+	"vc/pkg/helpers"
+	"vc/pkg/model"
+	"vc/pkg/openidfederation"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lestrrat-go/jwx/jwk"
+)
+
+// federation authenticates credential issuers by their OpenID Federation trust chain
+type federation struct {
+	resolver           *openidfederation.Resolver
+	httpClient         *http.Client
+	requiredTrustMarks []string
+
+	mu      sync.Mutex
+	issuers map[string]*federatedIssuer
+}
+
+// federatedIssuer is the keys of a resolved issuer, valid until its trust chain expires
+type federatedIssuer struct {
+	keys      jwk.Set
+	expiresAt time.Time
+}
+
+func newFederation(cfg *model.VerifierFederation, httpClient *http.Client) (*federation, error) {
+	trustAnchors := map[string]jwk.Set{}
+	for _, trustAnchor := range cfg.TrustAnchors {
+		keys, err := jwk.ReadFile(trustAnchor.JWKSPath)
+		if err != nil {
+			return nil, err
+		}
+		trustAnchors[trustAnchor.EntityID] = keys
+	}
+
+	return &federation{
+		resolver:           openidfederation.NewResolver(httpClient, trustAnchors),
+		httpClient:         httpClient,
+		requiredTrustMarks: cfg.RequiredTrustMarks,
+		issuers:            map[string]*federatedIssuer{},
+	}, nil
+}
+
+// keys returns the signing keys of issuer, resolved again if refresh is set or the trust chain has expired
+func (f *federation) keys(ctx context.Context, issuer string, refresh bool) (jwk.Set, error) {
+	f.mu.Lock()
+	cached, ok := f.issuers[issuer]
+	f.mu.Unlock()
+	if ok && !refresh && time.Now().Before(cached.expiresAt) {
+		return cached.keys, nil
+	}
+
+	chain, err := f.resolver.Resolve(ctx, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range f.requiredTrustMarks {
+		if !chain.HasTrustMark(id) {
+			return nil, fmt.Errorf("issuer %s does not have trust mark %s", issuer, id)
+		}
+	}
+
+	keys, err := f.metadataKeys(ctx, chain.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("issuer %s: %w", issuer, err)
+	}
+
+	f.mu.Lock()
+	f.issuers[issuer] = &federatedIssuer{keys: keys, expiresAt: chain.ExpiresAt}
+	f.mu.Unlock()
+
+	return keys, nil
+}
+
+// metadataKeys returns the jwks of the openid_credential_issuer metadata, or the keys at its jwks_uri
+func (f *federation) metadataKeys(ctx context.Context, metadata map[string]any) (jwk.Set, error) {
+	issuerMetadata, ok := metadata["openid_credential_issuer"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("no openid_credential_issuer metadata")
+	}
+
+	if jwks, ok := issuerMetadata["jwks"]; ok {
+		b, err := json.Marshal(jwks)
+		if err != nil {
+			return nil, err
+		}
+		return jwk.Parse(b)
+	}
+
+	jwksURI, ok := issuerMetadata["jwks_uri"].(string)
+	if !ok {
+		return nil, fmt.Errorf("no jwks or jwks_uri in openid_credential_issuer metadata")
+	}
+
+	return jwk.Fetch(ctx, jwksURI, jwk.WithHTTPClient(f.httpClient))
+}
+
+// federatedIssuerKey returns the key of the token picked by its kid among the keys of its federated iss.
+// An unknown kid resolves the issuer again, to pick up keys published after the last resolve.
+func (c *Client) federatedIssuerKey(ctx context.Context, token *jwt.Token) (any, error) {
+	issuer, err := token.Claims.GetIssuer()
+	if err != nil || issuer == "" {
+		return nil, fmt.Errorf("credential has no iss")
+	}
+
+	kid, ok := token.Header["kid"].(string)
+	if !ok || kid == "" {
+		return nil, helpers.ErrUnknownKeyID
+	}
+
+	set, err := c.federation.keys(ctx, issuer, false)
+	if err != nil {
+		return nil, err
+	}
+
+	key, ok := set.LookupKeyID(kid)
+	if !ok {
+		set, err = c.federation.keys(ctx, issuer, true)
+		if err != nil {
+			return nil, err
+		}
+		if key, ok = set.LookupKeyID(kid); !ok {
+			return nil, helpers.ErrUnknownKeyID
+		}
+	}
+
+	if alg := key.Algorithm(); alg != "" && alg != token.Method.Alg() {
+		return nil, fmt.Errorf("token alg %s does not match key alg %s", token.Method.Alg(), alg)
+	}
+
+	var publicKey any
+	if err := key.Raw(&publicKey); err != nil {
+		return nil, err
+	}
+
+	return publicKey, nil
+}
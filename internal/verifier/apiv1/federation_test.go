@@ -0,0 +1,120 @@
+package apiv1
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+	"vc/pkg/openidfederation"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyCredentialFederation(t *testing.T) {
+	ctx := context.Background()
+
+	issuerFederationKeys := jwk.NewSet()
+	issuerFederationKey := mockIssuerKey(t, issuerFederationKeys, "federation")
+	anchorKeys := jwk.NewSet()
+	anchorKey := mockIssuerKey(t, anchorKeys, "anchor")
+	credentialKeys := jwk.NewSet()
+	credentialKey := mockIssuerKey(t, credentialKeys, "credential")
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	server := httptest.NewServer(nil)
+	defer server.Close()
+	issuerID := server.URL + "/issuer"
+	anchorID := server.URL + "/anchor"
+	trustMarkID := anchorID + "/trust-marks/issuer"
+
+	var jwks any
+	b, err := json.Marshal(credentialKeys)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(b, &jwks))
+
+	trustMark, err := (&openidfederation.TrustMark{Issuer: anchorID, Subject: issuerID, ID: trustMarkID, IssuedAt: time.Now().Unix()}).Sign(jwt.SigningMethodES256, anchorKey, "anchor")
+	assert.NoError(t, err)
+
+	issuerConfiguration, err := openidfederation.NewEntityConfiguration(issuerID, issuerFederationKeys, []string{anchorID}, map[string]any{
+		"openid_credential_issuer": map[string]any{"credential_issuer": issuerID, "jwks": jwks},
+	}, time.Hour)
+	assert.NoError(t, err)
+	issuerConfiguration.TrustMarks = []openidfederation.TrustMarkRef{{ID: trustMarkID, TrustMark: trustMark}}
+
+	anchorConfiguration, err := openidfederation.NewEntityConfiguration(anchorID, anchorKeys, nil, map[string]any{
+		"federation_entity": map[string]any{"federation_fetch_endpoint": anchorID + "/fetch"},
+	}, time.Hour)
+	assert.NoError(t, err)
+	anchorConfiguration.TrustMarkIssuers = map[string][]string{trustMarkID: {anchorID}}
+
+	subordinate, err := openidfederation.NewEntityConfiguration(issuerID, issuerFederationKeys, nil, nil, time.Hour)
+	assert.NoError(t, err)
+	subordinate.Issuer = anchorID
+
+	serve := func(statement *openidfederation.EntityStatement, key *ecdsa.PrivateKey, kid string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			signed, err := statement.Sign(jwt.SigningMethodES256, key, kid)
+			assert.NoError(t, err)
+			w.Write([]byte(signed))
+		}
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/issuer"+openidfederation.WellKnownPath, serve(issuerConfiguration, issuerFederationKey, "federation"))
+	mux.HandleFunc("/anchor"+openidfederation.WellKnownPath, serve(anchorConfiguration, anchorKey, "anchor"))
+	mux.HandleFunc("/anchor/fetch", serve(subordinate, anchorKey, "anchor"))
+	server.Config.Handler = mux
+
+	anchorJWKSPath := filepath.Join(t.TempDir(), "trust_anchor_jwks.json")
+	b, err = json.Marshal(anchorKeys)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(anchorJWKSPath, b, 0600))
+
+	credential := func(key *ecdsa.PrivateKey, iss string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{"iss": iss})
+		token.Header["kid"] = "credential"
+		signed, err := token.SignedString(key)
+		assert.NoError(t, err)
+		return signed + "~"
+	}
+
+	tts := []struct {
+		name               string
+		credential         string
+		requiredTrustMarks []string
+		wantValid          bool
+	}{
+		{name: "issuer in the federation", credential: credential(credentialKey, issuerID), wantValid: true},
+		{name: "with trust mark", credential: credential(credentialKey, issuerID), requiredTrustMarks: []string{trustMarkID}, wantValid: true},
+		{name: "without trust mark", credential: credential(credentialKey, issuerID), requiredTrustMarks: []string{anchorID + "/trust-marks/other"}},
+		{name: "wrong key", credential: credential(otherKey, issuerID)},
+		{name: "issuer outside the federation", credential: credential(credentialKey, server.URL+"/other")},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &model.Cfg{Verifier: model.Verifier{Federation: model.VerifierFederation{
+				Enabled:            true,
+				TrustAnchors:       []model.TrustAnchor{{EntityID: anchorID, JWKSPath: anchorJWKSPath}},
+				RequiredTrustMarks: tt.requiredTrustMarks,
+			}}}
+			client, err := New(ctx, nil, nil, cfg, logger.NewSimple("testing_apiv1"))
+			assert.NoError(t, err)
+
+			reply, err := client.VerifyCredential(ctx, &VerifyCredentialRequest{Credential: tt.credential})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantValid, reply.Valid, reply.Reason)
+		})
+	}
+}
@@ -2,8 +2,19 @@ package apiv1
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
 	"vc/internal/gen/status/apiv1_status"
+	"vc/internal/verifier/policy"
+	"vc/pkg/helpers"
 	"vc/pkg/model"
+	"vc/pkg/sdjwt"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/skip2/go-qrcode"
 )
 
 // Status return status for each ladok instance
@@ -14,3 +25,420 @@ func (c *Client) Status(ctx context.Context, req *apiv1_status.StatusRequest) (*
 
 	return status, nil
 }
+
+// VerifyCredentialRequest is the request for VerifyCredential
+type VerifyCredentialRequest struct {
+	// Credential is an SD-JWT, disclosures are used to validate the disclosed claims against the vct schema
+	Credential string `json:"credential" validate:"required"`
+
+	// Policy is the name of the verification policy to apply, the default policy if empty
+	Policy string `json:"policy"`
+
+	// Locale, e.g. sv-SE, renders the disclosed claims of a valid credential with the display metadata of its vct
+	// type metadata, no rendering if empty or type metadata resolution is disabled
+	Locale string `json:"locale"`
+}
+
+// VerifyCredentialReply is the reply for VerifyCredential
+type VerifyCredentialReply struct {
+	Valid  bool   `json:"valid"`
+	KID    string `json:"kid,omitempty"`
+	Reason string `json:"reason,omitempty"`
+
+	// VCTName is the name from the vct type metadata, set when type metadata resolution is enabled
+	VCTName string `json:"vct_name,omitempty"`
+
+	// SchemaErrors lists the disclosed claims that do not conform to the vct schema
+	SchemaErrors []sdjwt.SchemaViolation `json:"schema_errors,omitempty"`
+
+	// WalletAttestation is set in session results when wallet attestation validation is enabled
+	WalletAttestation *WalletAttestationResult `json:"wallet_attestation,omitempty"`
+
+	// Policy is the outcome of the verification policy, set when a policy applies and the credential is otherwise valid
+	Policy *policy.Result `json:"policy,omitempty"`
+
+	// TransactionDataHashes are the hashes of the session transaction data the wallet confirmed in the key binding JWT
+	TransactionDataHashes []string `json:"transaction_data_hashes,omitempty"`
+
+	// Rendering is the disclosed claims labeled for the requested locale, set when the credential is valid
+	Rendering *sdjwt.Rendering `json:"rendering,omitempty"`
+}
+
+// VerifyCredential verifies the issuer signature of a credential
+//
+//	@Summary		Verify credential
+//	@ID				verifier-verify-credential
+//	@Description	Verifies the issuer signature of a credential with the issuer key matching the kid in its header, checks that the disclosure salts encode at least verifier.sd_jwt.min_salt_bits bits, checks the status list entry of the credential when enabled, then evaluates the verification policy
+//	@Tags			verifier
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	VerifyCredentialReply	"Success"
+//	@Failure		400	{object}	helpers.Problem			"Bad Request"
+//	@Param			req	body		VerifyCredentialRequest	true	" "
+//	@Router			/verify [post]
+func (c *Client) VerifyCredential(ctx context.Context, req *VerifyCredentialRequest) (*VerifyCredentialReply, error) {
+	start := time.Now()
+
+	reply, err := c.verifyCredential(ctx, req)
+	if err == nil && c.analytics() {
+		c.recordVerification(ctx, req, reply, time.Since(start), nil)
+	}
+
+	return reply, err
+}
+
+func (c *Client) verifyCredential(ctx context.Context, req *VerifyCredentialRequest) (*VerifyCredentialReply, error) {
+	if err := helpers.CheckSimple(req); err != nil {
+		return nil, err
+	}
+	if _, err := c.policies.Lookup(req.Policy); err != nil {
+		return nil, err
+	}
+
+	signedJWT, rest, _ := strings.Cut(req.Credential, "~")
+
+	token, err := jwt.Parse(signedJWT, c.issuerKey(ctx))
+	if errors.Is(err, helpers.ErrUnknownKeyID) {
+		return nil, helpers.ErrUnknownKeyID
+	}
+
+	reply := &VerifyCredentialReply{}
+	if token != nil {
+		reply.KID, _ = token.Header["kid"].(string)
+	}
+	if err != nil {
+		c.log.Debug("credential not valid", "kid", reply.KID, "err", err)
+		reply.Reason = err.Error()
+		return reply, nil
+	}
+
+	reply.Valid = token.Valid
+
+	if reply.Valid {
+		if err := sdjwt.CheckSalts(splitDisclosures(rest), c.cfg.Verifier.SDJWT.MinSaltBits); err != nil {
+			c.log.Debug("disclosure salt not valid", "kid", reply.KID, "err", err)
+			reply.Valid = false
+			reply.Reason = err.Error()
+			return reply, nil
+		}
+	}
+
+	if c.credentialStatus != nil && reply.Valid {
+		claims, _ := token.Claims.(jwt.MapClaims)
+		if err := sdjwt.CheckStatus(ctx, claims, c.credentialStatus); err != nil {
+			c.log.Debug("credential status not valid", "kid", reply.KID, "err", err)
+			reply.Valid = false
+			reply.Reason = err.Error()
+			return reply, nil
+		}
+	}
+
+	var vctm *sdjwt.VCTM
+	if c.vctm != nil && reply.Valid {
+		claims, _ := token.Claims.(jwt.MapClaims)
+		vct, _ := claims["vct"].(string)
+		integrity, _ := claims["vct#integrity"].(string)
+
+		vctm, err = c.vctm.Resolve(ctx, vct, integrity)
+		if err != nil {
+			c.log.Debug("vct not valid", "vct", vct, "err", err)
+			reply.Valid = false
+			reply.Reason = err.Error()
+			return reply, nil
+		}
+		reply.VCTName = vctm.Name
+
+		if len(vctm.Schema) > 0 {
+			disclosed, err := disclosedClaims(claims, rest)
+			if err != nil {
+				reply.Valid = false
+				reply.Reason = err.Error()
+				return reply, nil
+			}
+			if err := vctm.ValidateClaims(disclosed); err != nil {
+				reply.Valid = false
+				reply.Reason = err.Error()
+				var schemaErr *sdjwt.SchemaError
+				if errors.As(err, &schemaErr) {
+					reply.SchemaErrors = schemaErr.Violations
+				}
+				return reply, nil
+			}
+		}
+	}
+
+	if reply.Valid {
+		reply.Policy, err = c.evaluatePolicy(req.Policy, token, rest)
+		if err != nil {
+			reply.Valid = false
+			reply.Reason = err.Error()
+			return reply, nil
+		}
+		if reply.Policy != nil && !reply.Policy.Passed {
+			reply.Valid = false
+			reply.Reason = "policy " + reply.Policy.Policy + ": " + reply.Policy.Failed()
+		}
+	}
+
+	if reply.Valid && vctm != nil && req.Locale != "" {
+		claims, _ := token.Claims.(jwt.MapClaims)
+		disclosed, err := disclosedClaims(claims, rest)
+		if err != nil {
+			return nil, err
+		}
+		reply.Rendering = vctm.Render(disclosed, req.Locale)
+	}
+
+	return reply, nil
+}
+
+// CreateSessionRequest is the request for CreateSession
+type CreateSessionRequest struct {
+	// WebhookURL is called with the session when the wallet has responded, optional. It must start with a prefix
+	// in verifier.session.webhook_allow_list.
+	WebhookURL string `json:"webhook_url" validate:"omitempty,url"`
+
+	// Policy is the name of the verification policy to apply to the response, the default policy if empty
+	Policy string `json:"policy"`
+
+	// TransactionData are transactions the wallet must confirm with the presentation, e.g. a payment.
+	// Each object has a type and the OpenID4VP transaction data fields of that type.
+	TransactionData []map[string]any `json:"transaction_data"`
+}
+
+// CreateSessionReply is the reply for CreateSession
+type CreateSessionReply struct {
+	SessionID  string    `json:"session_id"`
+	RequestURI string    `json:"request_uri"`
+	QR         *model.QR `json:"qr"`
+	ExpiresAt  int64     `json:"expires_at"`
+}
+
+// CreateSession creates a presentation session
+//
+//	@Summary		Create session
+//	@ID				verifier-create-session
+//	@Description	Creates a presentation session, the wallet fetches the request from request_uri. Poll the session status or register a webhook to get the result. Transaction data binds the presentation to transactions the wallet confirms in the key binding JWT.
+//	@Tags			verifier
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	CreateSessionReply		"Success"
+//	@Failure		400	{object}	helpers.Problem			"Bad Request"
+//	@Param			req	body		CreateSessionRequest	true	" "
+//	@Router			/session [post]
+func (c *Client) CreateSession(ctx context.Context, req *CreateSessionRequest) (*CreateSessionReply, error) {
+	if err := helpers.CheckSimple(req); err != nil {
+		return nil, err
+	}
+	if _, err := c.policies.Lookup(req.Policy); err != nil {
+		return nil, err
+	}
+
+	if req.WebhookURL != "" && !c.webhookAllowed(req.WebhookURL) {
+		return nil, helpers.NewErrorDetails("VALIDATION_ERROR", []map[string]any{
+			{"field": "webhook_url", "message": "not in the webhook allow list"},
+		})
+	}
+
+	transactionData, err := newTransactionData(req.TransactionData)
+	if err != nil {
+		return nil, err
+	}
+
+	session := newSession(time.Duration(c.cfg.Verifier.Session.TTL)*time.Second, req.WebhookURL, req.Policy, transactionData)
+	if err := c.sessions.add(ctx, session); err != nil {
+		return nil, err
+	}
+
+	requestURI, err := url.JoinPath(c.cfg.Verifier.ExternalURL, "api/v1/session", session.ID, "request")
+	if err != nil {
+		return nil, err
+	}
+
+	deepLink := "openid4vp://?" + url.Values{"request_uri": {requestURI}}.Encode()
+	qrPNG, err := qrcode.Encode(deepLink, qrcode.Medium, 256)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &CreateSessionReply{
+		SessionID:  session.ID,
+		RequestURI: requestURI,
+		QR: &model.QR{
+			Base64Image: base64.StdEncoding.EncodeToString(qrPNG),
+			DeepLink:    deepLink,
+		},
+		ExpiresAt: session.ExpiresAt,
+	}
+
+	return reply, nil
+}
+
+// SessionRequest is the request for session endpoints
+type SessionRequest struct {
+	SessionID string `uri:"session_id" validate:"required"`
+}
+
+// SessionStatus returns the status of a presentation session
+//
+//	@Summary		Session status
+//	@ID				verifier-session-status
+//	@Description	Returns the status of a presentation session, and the verification result when the wallet has responded
+//	@Tags			verifier
+//	@Produce		json
+//	@Success		200			{object}	Session			"Success"
+//	@Failure		404			{object}	helpers.Problem	"Not Found"
+//	@Param			session_id	path		string			true	"session id"
+//	@Router			/session/{session_id} [get]
+func (c *Client) SessionStatus(ctx context.Context, req *SessionRequest) (*Session, error) {
+	return c.sessions.get(ctx, req.SessionID)
+}
+
+// SessionRequestObjectReply is the reply for SessionRequestObject
+type SessionRequestObjectReply struct {
+	// ClientID identifies the verifier, the wallet sets it as audience of the key binding JWT
+	ClientID     string `json:"client_id"`
+	ResponseType string `json:"response_type"`
+	ResponseMode string `json:"response_mode"`
+	ResponseURI  string `json:"response_uri"`
+	Nonce        string `json:"nonce"`
+	State        string `json:"state"`
+
+	// TransactionData are the base64url encoded transaction data objects, the wallet echoes their hashes in the key binding JWT
+	TransactionData []string `json:"transaction_data,omitempty"`
+}
+
+// SessionRequestObject returns the presentation request for the wallet
+//
+//	@Summary		Session request object
+//	@ID				verifier-session-request-object
+//	@Description	Returns the presentation request the wallet fetches from request_uri
+//	@Tags			verifier
+//	@Produce		json
+//	@Success		200			{object}	SessionRequestObjectReply	"Success"
+//	@Failure		404			{object}	helpers.Problem				"Not Found"
+//	@Param			session_id	path		string						true	"session id"
+//	@Router			/session/{session_id}/request [get]
+func (c *Client) SessionRequestObject(ctx context.Context, req *SessionRequest) (*SessionRequestObjectReply, error) {
+	session, err := c.sessions.get(ctx, req.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	responseURI, err := url.JoinPath(c.cfg.Verifier.ExternalURL, "api/v1/session", session.ID, "response")
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &SessionRequestObjectReply{
+		ClientID:     c.clientID(),
+		ResponseType: "vp_token",
+		ResponseMode: "direct_post",
+		ResponseURI:  responseURI,
+		Nonce:        session.Nonce,
+		State:        session.ID,
+	}
+	for _, td := range session.TransactionData {
+		reply.TransactionData = append(reply.TransactionData, td.Encoded)
+	}
+
+	return reply, nil
+}
+
+// SessionResponseRequest is the wallet response to a presentation session
+type SessionResponseRequest struct {
+	SessionID string `uri:"session_id" validate:"required"`
+	VPToken   string `json:"vp_token" form:"vp_token" validate:"required"`
+
+	// WalletAttestation is the wallet instance attestation, validated when enabled
+	WalletAttestation string `json:"wallet_attestation" form:"wallet_attestation"`
+
+	// WalletAttestationPoP proves possession of the attested wallet key, with the session nonce
+	WalletAttestationPoP string `json:"wallet_attestation_pop" form:"wallet_attestation_pop"`
+}
+
+// SessionResponse verifies the wallet response and completes the session
+//
+//	@Summary		Session response
+//	@ID				verifier-session-response
+//	@Description	Receives the wallet response, verifies the credential, its key binding JWT against the session nonce, the verifier client_id and the credential cnf key, and the wallet attestation, then fires the session webhook
+//	@Tags			verifier
+//	@Accept			json,x-www-form-urlencoded
+//	@Produce		json
+//	@Success		200			{object}	Session					"Success"
+//	@Failure		404			{object}	helpers.Problem			"Not Found"
+//	@Failure		409			{object}	helpers.Problem			"Conflict"
+//	@Param			session_id	path		string					true	"session id"
+//	@Param			req			body		SessionResponseRequest	true	" "
+//	@Router			/session/{session_id}/response [post]
+func (c *Client) SessionResponse(ctx context.Context, req *SessionResponseRequest) (*Session, error) {
+	if err := helpers.CheckSimple(req); err != nil {
+		return nil, err
+	}
+
+	pending, err := c.sessions.get(ctx, req.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if pending.Status != SessionStatusPending {
+		c.sessionMetrics.replayed(ctx, "completed")
+		return nil, helpers.ErrSessionCompleted
+	}
+	// the nonce is consumed before the presentation is verified, a replayed response is rejected on every replica
+	if err := c.sessions.consumeNonce(ctx, pending); err != nil {
+		if errors.Is(err, helpers.ErrSessionCompleted) {
+			c.sessionMetrics.replayed(ctx, "nonce")
+		}
+		return nil, err
+	}
+
+	start := time.Now()
+	verifyRequest := &VerifyCredentialRequest{Credential: req.VPToken, Policy: pending.Policy}
+	result, err := c.verifyCredential(ctx, verifyRequest)
+	if err != nil {
+		result = &VerifyCredentialReply{Reason: err.Error()}
+	}
+
+	if result.Valid {
+		// the presentation must be bound to this session, otherwise a credential presented elsewhere could be replayed here
+		claims, err := verifyKeyBinding(req.VPToken, pending.Nonce, c.clientID())
+		if errors.Is(err, errHolderKeyMissing) && !c.cfg.Verifier.SDJWT.RequireKeyBinding && len(pending.TransactionData) == 0 {
+			// a credential issued without proof of possession has no holder key to bind the presentation to
+			err = nil
+		}
+		if err == nil && len(pending.TransactionData) > 0 {
+			result.TransactionDataHashes, err = confirmTransactionData(claims, pending.TransactionData)
+		}
+		if err != nil {
+			result.Valid = false
+			result.Reason = err.Error()
+		}
+	}
+
+	if c.walletProviders != nil && (req.WalletAttestation != "" || c.cfg.Verifier.WalletAttestation.Required) {
+		result.WalletAttestation = c.verifyWalletAttestation(ctx, req.WalletAttestation, req.WalletAttestationPoP, pending.Nonce)
+		if !result.WalletAttestation.Valid && c.cfg.Verifier.WalletAttestation.Required && result.Valid {
+			result.Valid = false
+			result.Reason = result.WalletAttestation.Reason
+		}
+	}
+
+	if c.analytics() {
+		c.recordVerification(ctx, verifyRequest, result, time.Since(start), pending)
+	}
+
+	session, err := c.sessions.complete(ctx, req.SessionID, result)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.webhookURL != "" {
+		go func() {
+			if err := c.sendWebhook(context.Background(), session); err != nil {
+				c.log.Error(err, "webhook failed", "session_id", session.ID)
+			}
+		}()
+	}
+
+	return session, nil
+}
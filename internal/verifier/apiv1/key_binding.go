@@ -0,0 +1,89 @@
+package apiv1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lestrrat-go/jwx/jwk"
+)
+
+// typeKeyBinding is the typ of the key binding JWT of an SD-JWT presentation
+const typeKeyBinding = "kb+jwt"
+
+var (
+	// errKeyBindingMissing is the reason when a session response has no key binding JWT
+	errKeyBindingMissing = errors.New("key binding jwt is required")
+
+	// errHolderKeyMissing is the reason when the credential of a session response has no cnf, it is not bound to a
+	// holder key
+	errHolderKeyMissing = errors.New("credential has no holder key")
+)
+
+// keyBindingClaims are the claims of the key binding JWT used here
+type keyBindingClaims struct {
+	jwt.RegisteredClaims
+	Nonce                    string   `json:"nonce"`
+	SDHash                   string   `json:"sd_hash"`
+	TransactionDataHashes    []string `json:"transaction_data_hashes"`
+	TransactionDataHashesAlg string   `json:"transaction_data_hashes_alg"`
+}
+
+// verifyKeyBinding verifies that the key binding JWT of vpToken is signed with the holder key in the cnf claim of the
+// credential, and is bound to the presentation, the session nonce and the verifier as audience. The issuer signature of
+// the credential must already be verified. errHolderKeyMissing is returned for a credential without cnf.
+func verifyKeyBinding(vpToken, nonce, audience string) (*keyBindingClaims, error) {
+	credential, _, _ := strings.Cut(vpToken, "~")
+	credentialClaims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(credential, credentialClaims); err != nil {
+		return nil, err
+	}
+
+	cnf, _ := credentialClaims["cnf"].(map[string]any)
+	if cnf["jwk"] == nil {
+		return nil, errHolderKeyMissing
+	}
+
+	i := strings.LastIndex(vpToken, "~")
+	if i < 0 || i == len(vpToken)-1 {
+		return nil, errKeyBindingMissing
+	}
+	presentation, kbJWT := vpToken[:i+1], vpToken[i+1:]
+
+	cnfJWK, err := json.Marshal(cnf["jwk"])
+	if err != nil {
+		return nil, err
+	}
+	holderKey, err := jwk.ParseKey(cnfJWK)
+	if err != nil {
+		return nil, fmt.Errorf("credential cnf: %w", err)
+	}
+	var publicKey any
+	if err := holderKey.Raw(&publicKey); err != nil {
+		return nil, err
+	}
+
+	claims := &keyBindingClaims{}
+	_, err = jwt.ParseWithClaims(kbJWT, claims, func(token *jwt.Token) (any, error) {
+		if typ, _ := token.Header["typ"].(string); typ != typeKeyBinding {
+			return nil, fmt.Errorf("typ %q", typ)
+		}
+		return publicKey, nil
+	}, jwt.WithIssuedAt(), jwt.WithAudience(audience))
+	if err != nil {
+		return nil, fmt.Errorf("key binding jwt: %w", err)
+	}
+	if claims.IssuedAt == nil {
+		return nil, fmt.Errorf("key binding jwt: iat is required")
+	}
+	if claims.Nonce != nonce {
+		return nil, fmt.Errorf("key binding jwt: nonce does not match")
+	}
+	if claims.SDHash != hashBase64URL(presentation) {
+		return nil, fmt.Errorf("key binding jwt: sd_hash does not match")
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,134 @@
+package apiv1
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockHolderCredential returns a credential bound to holderKey by its cnf claim, without key binding JWT
+func mockHolderCredential(t *testing.T, issuerKey *ecdsa.PrivateKey, kid string, holderKey *ecdsa.PrivateKey) string {
+	holderJWK, err := jwk.New(holderKey.Public())
+	assert.NoError(t, err)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": "https://issuer.sunet.se",
+		"cnf": map[string]any{"jwk": holderJWK},
+	})
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(issuerKey)
+	assert.NoError(t, err)
+
+	return signed + "~"
+}
+
+// mockKeyBinding appends a key binding JWT to presentation
+func mockKeyBinding(t *testing.T, presentation string, holderKey *ecdsa.PrivateKey, claims jwt.MapClaims) string {
+	if _, ok := claims["iat"]; !ok {
+		claims["iat"] = time.Now().Unix()
+	}
+	if _, ok := claims["sd_hash"]; !ok {
+		claims["sd_hash"] = hashBase64URL(presentation)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["typ"] = typeKeyBinding
+
+	signed, err := token.SignedString(holderKey)
+	assert.NoError(t, err)
+
+	return presentation + signed
+}
+
+func TestVerifyKeyBinding(t *testing.T) {
+	issuerKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	holderKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	const (
+		nonce    = "nonce-1"
+		audience = "https://verifier.sunet.se"
+	)
+	presentation := mockHolderCredential(t, issuerKey, "kid-1", holderKey)
+
+	tts := []struct {
+		name    string
+		vpToken string
+		wantErr bool
+	}{
+		{
+			name:    "bound",
+			vpToken: mockKeyBinding(t, presentation, holderKey, jwt.MapClaims{"aud": audience, "nonce": nonce}),
+		},
+		{
+			name:    "no key binding",
+			vpToken: presentation,
+			wantErr: true,
+		},
+		{
+			name:    "other nonce",
+			vpToken: mockKeyBinding(t, presentation, holderKey, jwt.MapClaims{"aud": audience, "nonce": "other"}),
+			wantErr: true,
+		},
+		{
+			name:    "other audience",
+			vpToken: mockKeyBinding(t, presentation, holderKey, jwt.MapClaims{"aud": "https://other.example.com", "nonce": nonce}),
+			wantErr: true,
+		},
+		{
+			name:    "no audience",
+			vpToken: mockKeyBinding(t, presentation, holderKey, jwt.MapClaims{"nonce": nonce}),
+			wantErr: true,
+		},
+		{
+			name:    "other sd_hash",
+			vpToken: mockKeyBinding(t, presentation, holderKey, jwt.MapClaims{"aud": audience, "nonce": nonce, "sd_hash": "other"}),
+			wantErr: true,
+		},
+		{
+			name:    "not signed by holder",
+			vpToken: mockKeyBinding(t, presentation, otherKey, jwt.MapClaims{"aud": audience, "nonce": nonce}),
+			wantErr: true,
+		},
+		{
+			name:    "issued in the future",
+			vpToken: mockKeyBinding(t, presentation, holderKey, jwt.MapClaims{"aud": audience, "nonce": nonce, "iat": time.Now().Add(time.Hour).Unix()}),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := verifyKeyBinding(tt.vpToken, nonce, audience)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, nonce, claims.Nonce)
+		})
+	}
+}
+
+func TestVerifyKeyBindingMissing(t *testing.T) {
+	issuerKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	holderKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	_, err = verifyKeyBinding(mockHolderCredential(t, issuerKey, "kid-1", holderKey), "nonce", "https://verifier.sunet.se")
+	assert.ErrorIs(t, err, errKeyBindingMissing)
+
+	_, err = verifyKeyBinding(mockCredential(t, issuerKey, "kid-1"), "nonce", "https://verifier.sunet.se")
+	assert.ErrorIs(t, err, errHolderKeyMissing)
+}
@@ -0,0 +1,111 @@
+package apiv1
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"vc/pkg/helpers"
+	"vc/pkg/keyresolver"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lestrrat-go/jwx/jwk"
+)
+
+// jwksRefreshCooldown is the minimum time between JWKS refreshes forced by unknown kids
+const jwksRefreshCooldown = time.Minute
+
+// issuerKey returns a jwt.Keyfunc picking the issuer public key by the kid in the token header.
+// An unknown kid refreshes the JWKS, to pick up keys published after the last refresh, at most once per
+// jwksRefreshCooldown so tokens with made up kids can not make the verifier fetch the JWKS on every request.
+// With trusted lists enabled, an x5c header is resolved through them and a JWKS key must be a trusted one.
+// With federation enabled, the keys are those of the iss of the token, authenticated by its trust chain.
+func (c *Client) issuerKey(ctx context.Context) jwt.Keyfunc {
+	return func(token *jwt.Token) (any, error) {
+		if x5c, ok := token.Header["x5c"].([]any); ok && c.trust != nil {
+			return c.trustedChainKey(ctx, x5c)
+		}
+
+		if c.federation != nil {
+			return c.federatedIssuerKey(ctx, token)
+		}
+
+		if c.issuerJWKS == nil {
+			return nil, fmt.Errorf("issuer_jwks_url is not configured")
+		}
+
+		kid, ok := token.Header["kid"].(string)
+		if !ok || kid == "" {
+			return nil, helpers.ErrUnknownKeyID
+		}
+
+		set, err := c.issuerJWKS.Fetch(ctx, c.cfg.Verifier.IssuerJWKSURL)
+		if err != nil {
+			return nil, err
+		}
+
+		key, ok := set.LookupKeyID(kid)
+		if !ok {
+			set, err = c.refreshIssuerJWKS(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if key, ok = set.LookupKeyID(kid); !ok {
+				return nil, helpers.ErrUnknownKeyID
+			}
+		}
+
+		if alg := key.Algorithm(); alg != "" && alg != token.Method.Alg() {
+			return nil, fmt.Errorf("token alg %s does not match key alg %s", token.Method.Alg(), alg)
+		}
+
+		var publicKey any
+		if err := key.Raw(&publicKey); err != nil {
+			return nil, err
+		}
+
+		if c.trust != nil {
+			if chain := key.X509CertChain(); len(chain) > 0 {
+				if _, err := c.trust.ResolveChain(ctx, chain); err != nil {
+					return nil, err
+				}
+			} else if err := c.trust.TrustedKey(publicKey); err != nil {
+				return nil, err
+			}
+		}
+
+		return publicKey, nil
+	}
+}
+
+// refreshIssuerJWKS fetches the issuer JWKS now, or returns helpers.ErrUnknownKeyID if it was forced to less than
+// jwksRefreshCooldown ago
+func (c *Client) refreshIssuerJWKS(ctx context.Context) (jwk.Set, error) {
+	c.jwksRefreshMu.Lock()
+	defer c.jwksRefreshMu.Unlock()
+
+	if time.Since(c.jwksRefreshedAt) < jwksRefreshCooldown {
+		return nil, helpers.ErrUnknownKeyID
+	}
+	c.jwksRefreshedAt = time.Now()
+
+	return c.issuerJWKS.Refresh(ctx, c.cfg.Verifier.IssuerJWKSURL)
+}
+
+// trustedChainKey resolves the public key of an x5c header through the trusted lists
+func (c *Client) trustedChainKey(ctx context.Context, x5c []any) (any, error) {
+	encoded := make([]string, 0, len(x5c))
+	for _, v := range x5c {
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("x5c is not a list of strings")
+		}
+		encoded = append(encoded, s)
+	}
+
+	chain, err := keyresolver.ParseX5C(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.trust.ResolveChain(ctx, chain)
+}
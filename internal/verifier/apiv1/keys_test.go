@@ -0,0 +1,251 @@
+package apiv1
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+	"vc/pkg/helpers"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+	"vc/pkg/sdjwt"
+	"vc/pkg/statuslist"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockIssuerKey(t *testing.T, set jwk.Set, kid string) *ecdsa.PrivateKey {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	key, err := jwk.New(privateKey.Public())
+	assert.NoError(t, err)
+	assert.NoError(t, key.Set(jwk.KeyIDKey, kid))
+	assert.NoError(t, key.Set(jwk.AlgorithmKey, "ES256"))
+	set.Add(key)
+
+	return privateKey
+}
+
+func mockCredential(t *testing.T, privateKey *ecdsa.PrivateKey, kid string) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{"iss": "https://issuer.sunet.se"})
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(privateKey)
+	assert.NoError(t, err)
+
+	return signed + "~"
+}
+
+func TestVerifyCredential(t *testing.T) {
+	ctx := context.Background()
+	set := jwk.NewSet()
+
+	oldKey := mockIssuerKey(t, set, "old")
+	newKey := mockIssuerKey(t, set, "new")
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(set)
+	}))
+	defer server.Close()
+
+	client, err := New(ctx, nil, nil, &model.Cfg{Verifier: model.Verifier{IssuerJWKSURL: server.URL}}, logger.NewSimple("testing_apiv1"))
+	assert.NoError(t, err)
+
+	tts := []struct {
+		name       string
+		credential string
+		wantValid  bool
+		wantErr    error
+	}{
+		{name: "old key", credential: mockCredential(t, oldKey, "old"), wantValid: true},
+		{name: "new key", credential: mockCredential(t, newKey, "new"), wantValid: true},
+		{name: "wrong key for kid", credential: mockCredential(t, otherKey, "new"), wantValid: false},
+		{name: "unknown kid", credential: mockCredential(t, otherKey, "other"), wantErr: helpers.ErrUnknownKeyID},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			reply, err := client.VerifyCredential(ctx, &VerifyCredentialRequest{Credential: tt.credential})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantValid, reply.Valid)
+		})
+	}
+}
+
+func TestIssuerJWKSRefreshCooldown(t *testing.T) {
+	ctx := context.Background()
+	set := jwk.NewSet()
+	key := mockIssuerKey(t, set, "kid")
+
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		json.NewEncoder(w).Encode(set)
+	}))
+	defer server.Close()
+
+	client, err := New(ctx, nil, nil, &model.Cfg{Verifier: model.Verifier{IssuerJWKSURL: server.URL}}, logger.NewSimple("testing_apiv1"))
+	assert.NoError(t, err)
+
+	_, err = client.VerifyCredential(ctx, &VerifyCredentialRequest{Credential: mockCredential(t, key, "kid")})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, fetches.Load())
+
+	_, err = client.VerifyCredential(ctx, &VerifyCredentialRequest{Credential: mockCredential(t, key, "unknown-1")})
+	assert.ErrorIs(t, err, helpers.ErrUnknownKeyID)
+	assert.EqualValues(t, 2, fetches.Load(), "an unknown kid refreshes the JWKS")
+
+	_, err = client.VerifyCredential(ctx, &VerifyCredentialRequest{Credential: mockCredential(t, key, "unknown-2")})
+	assert.ErrorIs(t, err, helpers.ErrUnknownKeyID)
+	assert.EqualValues(t, 2, fetches.Load(), "no refresh during the cooldown")
+
+	newKey := mockIssuerKey(t, set, "new")
+	client.jwksRefreshedAt = time.Now().Add(-jwksRefreshCooldown)
+
+	reply, err := client.VerifyCredential(ctx, &VerifyCredentialRequest{Credential: mockCredential(t, newKey, "new")})
+	assert.NoError(t, err)
+	assert.True(t, reply.Valid)
+	assert.EqualValues(t, 3, fetches.Load(), "a key published later is picked up after the cooldown")
+}
+
+// mockStatusFetcher returns the status at the index of the reference
+type mockStatusFetcher []uint8
+
+func (m mockStatusFetcher) FetchStatus(ctx context.Context, ref *statuslist.StatusReference) (uint8, error) {
+	return m[ref.Idx], nil
+}
+
+func TestVerifyCredentialStatus(t *testing.T) {
+	ctx := context.Background()
+	set := jwk.NewSet()
+	key := mockIssuerKey(t, set, "kid")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(set)
+	}))
+	defer server.Close()
+
+	client, err := New(ctx, nil, nil, &model.Cfg{Verifier: model.Verifier{IssuerJWKSURL: server.URL}}, logger.NewSimple("testing_apiv1"))
+	assert.NoError(t, err)
+	client.credentialStatus = mockStatusFetcher{statuslist.StatusValid, statuslist.StatusInvalid}
+
+	credential := func(idx int64) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+			"iss":    "https://issuer.sunet.se",
+			"status": map[string]any{"status_list": map[string]any{"idx": idx, "uri": "https://registry.sunet.se/statuslists/token"}},
+		})
+		token.Header["kid"] = "kid"
+		signed, err := token.SignedString(key)
+		assert.NoError(t, err)
+		return signed + "~"
+	}
+
+	reply, err := client.VerifyCredential(ctx, &VerifyCredentialRequest{Credential: credential(0)})
+	assert.NoError(t, err)
+	assert.True(t, reply.Valid)
+
+	reply, err = client.VerifyCredential(ctx, &VerifyCredentialRequest{Credential: credential(1)})
+	assert.NoError(t, err)
+	assert.False(t, reply.Valid)
+	assert.Equal(t, sdjwt.ErrCredentialRevoked.Error(), reply.Reason)
+
+	reply, err = client.VerifyCredential(ctx, &VerifyCredentialRequest{Credential: mockCredential(t, key, "kid")})
+	assert.NoError(t, err)
+	assert.True(t, reply.Valid, "credentials without a status claim pass")
+}
+
+func TestVerifyCredentialRendering(t *testing.T) {
+	ctx := context.Background()
+	set := jwk.NewSet()
+	key := mockIssuerKey(t, set, "kid")
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	vct := server.URL + "/pid"
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(set)
+	})
+	mux.HandleFunc("/pid", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"vct":     vct,
+			"display": []any{map[string]any{"lang": "en-US", "name": "Person ID"}, map[string]any{"lang": "sv-SE", "name": "Personlig identitet"}},
+			"claims": []any{
+				map[string]any{"path": []any{"given_name"}, "display": []any{map[string]any{"lang": "en-US", "label": "Given name"}, map[string]any{"lang": "sv-SE", "label": "Förnamn"}}},
+				map[string]any{"path": []any{"family_name"}, "display": []any{map[string]any{"lang": "en-US", "label": "Family name"}}},
+			},
+		})
+	})
+
+	client, err := New(ctx, nil, nil, &model.Cfg{Verifier: model.Verifier{IssuerJWKSURL: server.URL + "/jwks"}}, logger.NewSimple("testing_apiv1"))
+	assert.NoError(t, err)
+	client.vctm = sdjwt.NewVCTMResolver(nil, time.Minute, []string{server.URL})
+
+	disclosure := base64.RawURLEncoding.EncodeToString([]byte(`["bDZaZ1RjN1FpY3JhTEtJcGpzSWpRdw","given_name","Magnus"]`))
+	// the digest of the repo's sd-jwt issuer, the hex encoded sha-256 hash base64url encoded
+	digest := fmt.Sprintf("%x", sha256.Sum256([]byte(disclosure)))
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss":     "https://issuer.sunet.se",
+		"vct":     vct,
+		"_sd":     []any{base64.RawURLEncoding.EncodeToString([]byte(digest))},
+		"_sd_alg": "sha-256",
+	})
+	token.Header["kid"] = "kid"
+	signed, err := token.SignedString(key)
+	assert.NoError(t, err)
+	credential := signed + "~" + disclosure + "~"
+
+	tts := []struct {
+		name          string
+		credential    string
+		locale        string
+		wantRendering *sdjwt.Rendering
+	}{
+		{
+			name:       "disclosed claims of the locale",
+			credential: credential,
+			locale:     "sv-SE",
+			wantRendering: &sdjwt.Rendering{
+				Lang:   "sv-SE",
+				Name:   "Personlig identitet",
+				Claims: []sdjwt.RenderedClaim{{Path: []any{"given_name"}, Label: "Förnamn", Value: "Magnus"}},
+			},
+		},
+		{
+			name:       "without locale",
+			credential: credential,
+		},
+		{
+			name:       "invalid credential",
+			credential: mockCredential(t, mockIssuerKey(t, jwk.NewSet(), "kid"), "kid"),
+			locale:     "sv-SE",
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			reply, err := client.VerifyCredential(ctx, &VerifyCredentialRequest{Credential: tt.credential, Locale: tt.locale})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantRendering, reply.Rendering)
+		})
+	}
+}
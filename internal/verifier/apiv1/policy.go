@@ -0,0 +1,44 @@
+package apiv1
+
+import (
+	"strings"
+	"vc/internal/verifier/policy"
+	"vc/pkg/sdjwt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// splitDisclosures returns the disclosures in rest, the part of the SD-JWT after the JWT
+func splitDisclosures(rest string) []string {
+	// the last element after ~ is the key binding JWT, or empty
+	disclosures := strings.Split(rest, "~")
+	return disclosures[:len(disclosures)-1]
+}
+
+// disclosedClaims returns the claims of an issuer signed JWT with the disclosures in rest, the part of the SD-JWT
+// after the JWT
+func disclosedClaims(claims jwt.MapClaims, rest string) (map[string]any, error) {
+	return sdjwt.DisclosedClaims(claims, splitDisclosures(rest))
+}
+
+// evaluatePolicy evaluates the policy name, or the default policy, against a verified credential. The result is nil
+// if no policy applies.
+func (c *Client) evaluatePolicy(name string, token *jwt.Token, rest string) (*policy.Result, error) {
+	claims, _ := token.Claims.(jwt.MapClaims)
+
+	disclosed, err := disclosedClaims(claims, rest)
+	if err != nil {
+		return nil, err
+	}
+
+	credential := &policy.Credential{
+		Algorithm: token.Method.Alg(),
+		Claims:    disclosed,
+	}
+	credential.Issuer, _ = claims.GetIssuer()
+	if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+		credential.IssuedAt = iat.Unix()
+	}
+
+	return c.policies.Evaluate(name, credential)
+}
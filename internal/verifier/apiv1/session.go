@@ -0,0 +1,316 @@
+package apiv1
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"vc/internal/verifier/db"
+	"vc/pkg/helpers"
+	"vc/pkg/model"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Session status
+const (
+	SessionStatusPending  = "pending"
+	SessionStatusVerified = "verified"
+	SessionStatusFailed   = "failed"
+)
+
+// webhookSignatureHeader carries the hex encoded HMAC-SHA256 of the webhook body
+const webhookSignatureHeader = "X-Webhook-Signature"
+
+// Session is a presentation session between a relying party and a wallet
+type Session struct {
+	ID        string                 `json:"session_id"`
+	Status    string                 `json:"status"`
+	Nonce     string                 `json:"nonce"`
+	CreatedAt int64                  `json:"created_at"`
+	ExpiresAt int64                  `json:"expires_at"`
+	Result    *VerifyCredentialReply `json:"result,omitempty"`
+
+	// Policy is the verification policy applied to the response, the default policy if empty
+	Policy string `json:"policy,omitempty"`
+
+	// TransactionData are the transactions the presentation is bound to, confirmed hashes are in the result
+	TransactionData []*TransactionData `json:"transaction_data,omitempty"`
+
+	webhookURL string
+}
+
+// sessionStore keeps sessions until they expire. The nonce of a session can be consumed once, which is atomic over
+// the replicas sharing the store.
+type sessionStore interface {
+	// add stores a new pending session
+	add(ctx context.Context, session *Session) error
+
+	// get returns a copy of the session, or ErrSessionNotFound if it does not exist or has expired
+	get(ctx context.Context, id string) (*Session, error)
+
+	// consumeNonce marks the nonce of the session used, ErrSessionCompleted if it already was
+	consumeNonce(ctx context.Context, session *Session) error
+
+	// complete stores the verification result of a pending session
+	complete(ctx context.Context, id string, result *VerifyCredentialReply) (*Session, error)
+}
+
+// newSessionStore creates the session store of cfg, expired sessions in the memory store are purged every
+// cfg.SweepInterval seconds until ctx is done
+func newSessionStore(ctx context.Context, cfg *model.VerifierSession, db *db.Service) (sessionStore, error) {
+	if cfg.Store == "mongo" {
+		if db == nil {
+			return nil, errors.New("the mongo session store requires the database")
+		}
+		return newMongoSessionStore(db.VCSessionColl), nil
+	}
+
+	store := newMemorySessionStore()
+	if cfg.SweepInterval > 0 {
+		go store.run(ctx, time.Duration(cfg.SweepInterval)*time.Second)
+	}
+
+	return store, nil
+}
+
+// newSession creates a pending session that expires after ttl
+func newSession(ttl time.Duration, webhookURL, policy string, transactionData []*TransactionData) *Session {
+	now := time.Now()
+	return &Session{
+		ID:              uuid.NewString(),
+		Status:          SessionStatusPending,
+		Nonce:           uuid.NewString(),
+		CreatedAt:       now.Unix(),
+		ExpiresAt:       now.Add(ttl).Unix(),
+		Policy:          policy,
+		TransactionData: transactionData,
+		webhookURL:      webhookURL,
+	}
+}
+
+// completeSession sets the result and final status of session, ErrSessionCompleted if it is not pending
+func completeSession(session *Session, result *VerifyCredentialReply) error {
+	if session.Status != SessionStatusPending {
+		return helpers.ErrSessionCompleted
+	}
+
+	session.Result = result
+	session.Status = SessionStatusFailed
+	if result.Valid {
+		session.Status = SessionStatusVerified
+	}
+	return nil
+}
+
+// memorySessionStore keeps sessions in memory, for a single replica
+type memorySessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]*Session
+
+	// consumed holds the ids of the sessions whose nonce is used
+	consumed map[string]bool
+}
+
+func newMemorySessionStore() *memorySessionStore {
+	return &memorySessionStore{
+		sessions: map[string]*Session{},
+		consumed: map[string]bool{},
+	}
+}
+
+// run sweeps the store every interval until ctx is done
+func (s *memorySessionStore) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.sweep()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sweep purges the expired sessions, sessions that are never looked up again would otherwise be kept forever
+func (s *memorySessionStore) sweep() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().Unix()
+	for id, session := range s.sessions {
+		if now >= session.ExpiresAt {
+			delete(s.sessions, id)
+			delete(s.consumed, id)
+		}
+	}
+}
+
+func (s *memorySessionStore) add(ctx context.Context, session *Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := *session
+	s.sessions[session.ID] = &c
+
+	return nil
+}
+
+func (s *memorySessionStore) get(ctx context.Context, id string) (*Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, err := s.lookup(id)
+	if err != nil {
+		return nil, err
+	}
+
+	c := *session
+	return &c, nil
+}
+
+func (s *memorySessionStore) consumeNonce(ctx context.Context, session *Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.lookup(session.ID); err != nil {
+		return err
+	}
+	if s.consumed[session.ID] {
+		return helpers.ErrSessionCompleted
+	}
+	s.consumed[session.ID] = true
+
+	return nil
+}
+
+func (s *memorySessionStore) complete(ctx context.Context, id string, result *VerifyCredentialReply) (*Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, err := s.lookup(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := completeSession(session, result); err != nil {
+		return nil, err
+	}
+
+	c := *session
+	return &c, nil
+}
+
+func (s *memorySessionStore) lookup(id string) (*Session, error) {
+	session, ok := s.sessions[id]
+	if !ok {
+		return nil, helpers.ErrSessionNotFound
+	}
+	if time.Now().Unix() >= session.ExpiresAt {
+		delete(s.sessions, id)
+		delete(s.consumed, id)
+		return nil, helpers.ErrSessionNotFound
+	}
+
+	return session, nil
+}
+
+// sessionMetrics counts the rejected replays of session responses
+type sessionMetrics struct {
+	replays metric.Int64Counter
+}
+
+func newSessionMetrics() (*sessionMetrics, error) {
+	meter := otel.Meter("vc/verifier")
+
+	m := &sessionMetrics{}
+	var err error
+	m.replays, err = meter.Int64Counter("verifier.session.replays", metric.WithDescription("Number of rejected session responses by reason, nonce already consumed or session completed"))
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (m *sessionMetrics) replayed(ctx context.Context, reason string) {
+	m.replays.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", reason)))
+}
+
+// signWebhook returns the hex encoded HMAC-SHA256 of body
+func signWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// clientID returns the client_id of the verifier
+func (c *Client) clientID() string {
+	if c.cfg.Verifier.ClientID != "" {
+		return c.cfg.Verifier.ClientID
+	}
+	return c.cfg.Verifier.ExternalURL
+}
+
+// webhookAllowed reports whether webhookURL starts with a prefix in the webhook allow list, the verifier only posts
+// session results to relying parties it is configured for
+func (c *Client) webhookAllowed(webhookURL string) bool {
+	for _, prefix := range c.cfg.Verifier.Session.WebhookAllowList {
+		if strings.HasPrefix(webhookURL, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// newWebhookClient returns the client posting session webhooks, it does not follow redirects away from the allow
+// listed url
+func newWebhookClient() *http.Client {
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// sendWebhook posts the completed session to its webhook url
+func (c *Client) sendWebhook(ctx context.Context, session *Session) error {
+	body, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, session.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.cfg.Verifier.Session.WebhookSecret != "" {
+		req.Header.Set(webhookSignatureHeader, signWebhook(c.cfg.Verifier.Session.WebhookSecret, body))
+	}
+
+	resp, err := c.webhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
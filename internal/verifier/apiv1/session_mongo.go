@@ -0,0 +1,82 @@
+package apiv1
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+	"vc/internal/verifier/db"
+)
+
+// mongoSession is a session as stored in mongo, with its webhook url
+type mongoSession struct {
+	Session
+	WebhookURL string `json:"webhook_url,omitempty"`
+}
+
+// mongoSessionStore keeps sessions in mongo, shared by the replicas of the verifier. Sessions are removed by a TTL
+// index once they expire.
+type mongoSessionStore struct {
+	coll *db.VCSessionColl
+}
+
+func newMongoSessionStore(coll *db.VCSessionColl) *mongoSessionStore {
+	return &mongoSessionStore{coll: coll}
+}
+
+func (s *mongoSessionStore) add(ctx context.Context, session *Session) error {
+	data, err := encodeMongoSession(session)
+	if err != nil {
+		return err
+	}
+
+	return s.coll.Add(ctx, &db.SessionDocument{
+		ID:        session.ID,
+		Data:      data,
+		ExpiresAt: time.Unix(session.ExpiresAt, 0),
+	})
+}
+
+func (s *mongoSessionStore) get(ctx context.Context, id string) (*Session, error) {
+	doc, err := s.coll.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := &mongoSession{}
+	if err := json.Unmarshal(doc.Data, stored); err != nil {
+		return nil, err
+	}
+	stored.Session.webhookURL = stored.WebhookURL
+
+	return &stored.Session, nil
+}
+
+// consumeNonce marks the nonce used, only the first of concurrent responses to a session does
+func (s *mongoSessionStore) consumeNonce(ctx context.Context, session *Session) error {
+	return s.coll.ConsumeNonce(ctx, session.ID)
+}
+
+// complete stores the result, the session is no longer pending once its nonce is consumed so responses do not race
+func (s *mongoSessionStore) complete(ctx context.Context, id string, result *VerifyCredentialReply) (*Session, error) {
+	session, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := completeSession(session, result); err != nil {
+		return nil, err
+	}
+
+	data, err := encodeMongoSession(session)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.coll.Update(ctx, id, data); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func encodeMongoSession(session *Session) ([]byte, error) {
+	return json.Marshal(&mongoSession{Session: *session, WebhookURL: session.webhookURL})
+}
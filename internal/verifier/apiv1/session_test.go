@@ -0,0 +1,154 @@
+package apiv1
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"vc/pkg/helpers"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionStore(t *testing.T) {
+	ctx := context.Background()
+
+	stores := map[string]sessionStore{
+		"memory": newMemorySessionStore(),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			session := newSession(time.Minute, "https://rp.example.com/webhook", "", nil)
+			assert.NoError(t, store.add(ctx, session))
+
+			got, err := store.get(ctx, session.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, SessionStatusPending, got.Status)
+			assert.Equal(t, session.Nonce, got.Nonce)
+			assert.Equal(t, "https://rp.example.com/webhook", got.webhookURL)
+
+			assert.NoError(t, store.consumeNonce(ctx, got))
+			assert.ErrorIs(t, store.consumeNonce(ctx, got), helpers.ErrSessionCompleted)
+
+			got, err = store.complete(ctx, session.ID, &VerifyCredentialReply{Valid: true})
+			assert.NoError(t, err)
+			assert.Equal(t, SessionStatusVerified, got.Status)
+
+			_, err = store.complete(ctx, session.ID, &VerifyCredentialReply{Valid: true})
+			assert.ErrorIs(t, err, helpers.ErrSessionCompleted)
+
+			_, err = store.get(ctx, "unknown")
+			assert.ErrorIs(t, err, helpers.ErrSessionNotFound)
+
+			expired := newSession(0, "", "", nil)
+			assert.NoError(t, store.add(ctx, expired))
+			_, err = store.get(ctx, expired.ID)
+			assert.ErrorIs(t, err, helpers.ErrSessionNotFound)
+		})
+	}
+}
+
+func TestMemorySessionStoreSweep(t *testing.T) {
+	ctx := context.Background()
+	store := newMemorySessionStore()
+
+	live := newSession(time.Minute, "", "", nil)
+	assert.NoError(t, store.add(ctx, live))
+	expired := newSession(0, "", "", nil)
+	assert.NoError(t, store.add(ctx, expired))
+	assert.NoError(t, store.consumeNonce(ctx, live))
+
+	store.sweep()
+
+	assert.Len(t, store.sessions, 1)
+	assert.Contains(t, store.sessions, live.ID)
+	assert.True(t, store.consumed[live.ID])
+}
+
+func TestSessionResponseWebhook(t *testing.T) {
+	ctx := context.Background()
+	set := jwk.NewSet()
+	issuerKey := mockIssuerKey(t, set, "kid-1")
+	holderKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(set)
+	}))
+	defer jwksServer.Close()
+
+	type webhook struct {
+		body      []byte
+		signature string
+	}
+	webhooks := make(chan webhook, 1)
+	webhookServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		webhooks <- webhook{body: body, signature: r.Header.Get(webhookSignatureHeader)}
+	}))
+	defer webhookServer.Close()
+
+	cfg := &model.Cfg{Verifier: model.Verifier{
+		IssuerJWKSURL: jwksServer.URL,
+		ExternalURL:   "https://verifier.sunet.se",
+		Session: model.VerifierSession{
+			TTL:              60,
+			WebhookSecret:    "secret",
+			WebhookAllowList: []string{webhookServer.URL + "/"},
+		},
+	}}
+	client, err := New(ctx, nil, nil, cfg, logger.NewSimple("testing_apiv1"))
+	assert.NoError(t, err)
+
+	_, err = client.CreateSession(ctx, &CreateSessionRequest{WebhookURL: "http://169.254.169.254/latest/meta-data"})
+	assert.Equal(t, "VALIDATION_ERROR", helpers.NewProblem(err).Code, "a webhook outside the allow list is refused")
+
+	created, err := client.CreateSession(ctx, &CreateSessionRequest{WebhookURL: webhookServer.URL + "/rp"})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://verifier.sunet.se/api/v1/session/"+created.SessionID+"/request", created.RequestURI)
+
+	requestObject, err := client.SessionRequestObject(ctx, &SessionRequest{SessionID: created.SessionID})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://verifier.sunet.se", requestObject.ClientID)
+
+	vpToken := mockKeyBinding(t, mockHolderCredential(t, issuerKey, "kid-1", holderKey), holderKey, jwt.MapClaims{
+		"aud":   requestObject.ClientID,
+		"nonce": requestObject.Nonce,
+	})
+
+	session, err := client.SessionResponse(ctx, &SessionResponseRequest{
+		SessionID: created.SessionID,
+		VPToken:   vpToken,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, SessionStatusVerified, session.Status, session.Result.Reason)
+
+	_, err = client.SessionResponse(ctx, &SessionResponseRequest{
+		SessionID: created.SessionID,
+		VPToken:   vpToken,
+	})
+	assert.ErrorIs(t, err, helpers.ErrSessionCompleted, "a replayed response is rejected")
+
+	select {
+	case got := <-webhooks:
+		assert.Equal(t, signWebhook("secret", got.body), got.signature)
+
+		payload := &Session{}
+		assert.NoError(t, json.Unmarshal(got.body, payload))
+		assert.Equal(t, created.SessionID, payload.ID)
+		assert.Equal(t, SessionStatusVerified, payload.Status)
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook not called")
+	}
+}
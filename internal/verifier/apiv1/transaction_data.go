@@ -0,0 +1,83 @@
+package apiv1
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"vc/pkg/helpers"
+)
+
+// transactionDataHashAlg is the hash algorithm of transaction data hashes, the OpenID4VP default
+const transactionDataHashAlg = "sha-256"
+
+// errTransactionDataNotConfirmed is the reason when the key binding JWT does not echo every transaction data hash
+var errTransactionDataNotConfirmed = errors.New("transaction data is not confirmed by the key binding jwt")
+
+// TransactionData is a transaction the wallet confirms with the presentation, e.g. a payment, OpenID4VP section 5.1
+type TransactionData struct {
+	// Data is the transaction data object, with type and credential_ids
+	Data map[string]any `json:"data"`
+
+	// Encoded is the base64url encoded JSON of Data, as sent in the transaction_data request parameter
+	Encoded string `json:"encoded"`
+
+	// Hash is the base64url encoded sha-256 hash of Encoded, the wallet echoes it in transaction_data_hashes of the key binding JWT
+	Hash string `json:"hash"`
+}
+
+// newTransactionData encodes and hashes the transaction data objects of a session request
+func newTransactionData(objects []map[string]any) ([]*TransactionData, error) {
+	transactionData := make([]*TransactionData, 0, len(objects))
+	for i, data := range objects {
+		if typ, _ := data["type"].(string); typ == "" {
+			return nil, helpers.NewErrorDetails("VALIDATION_ERROR", []map[string]any{
+				{"field": fmt.Sprintf("transaction_data[%d].type", i), "message": "type is required"},
+			})
+		}
+		if algs, ok := data["transaction_data_hashes_alg"].([]any); ok && !slices.Contains(algs, any(transactionDataHashAlg)) {
+			return nil, helpers.NewErrorDetails("VALIDATION_ERROR", []map[string]any{
+				{"field": fmt.Sprintf("transaction_data[%d].transaction_data_hashes_alg", i), "message": "must include " + transactionDataHashAlg},
+			})
+		}
+
+		b, err := json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+		encoded := base64.RawURLEncoding.EncodeToString(b)
+
+		transactionData = append(transactionData, &TransactionData{
+			Data:    data,
+			Encoded: encoded,
+			Hash:    hashBase64URL(encoded),
+		})
+	}
+
+	return transactionData, nil
+}
+
+func hashBase64URL(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// confirmTransactionData checks that the verified key binding claims echo the hash of every transaction data object,
+// the confirmed hashes are returned
+func confirmTransactionData(claims *keyBindingClaims, transactionData []*TransactionData) ([]string, error) {
+	if claims.TransactionDataHashesAlg != "" && claims.TransactionDataHashesAlg != transactionDataHashAlg {
+		return nil, fmt.Errorf("key binding jwt: transaction_data_hashes_alg %q", claims.TransactionDataHashesAlg)
+	}
+
+	hashes := make([]string, 0, len(transactionData))
+	for _, td := range transactionData {
+		if !slices.Contains(claims.TransactionDataHashes, td.Hash) {
+			return nil, errTransactionDataNotConfirmed
+		}
+		hashes = append(hashes, td.Hash)
+	}
+
+	return hashes, nil
+}
@@ -0,0 +1,149 @@
+package apiv1
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"vc/pkg/helpers"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTransactionData(t *testing.T) {
+	tts := []struct {
+		name    string
+		objects []map[string]any
+		wantErr bool
+	}{
+		{
+			name:    "payment",
+			objects: []map[string]any{{"type": "payment_data", "credential_ids": []any{"pid"}, "amount": "10.00"}},
+		},
+		{
+			name:    "missing type",
+			objects: []map[string]any{{"credential_ids": []any{"pid"}}},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported hash alg",
+			objects: []map[string]any{{"type": "payment_data", "transaction_data_hashes_alg": []any{"sha-512"}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newTransactionData(tt.objects)
+			if tt.wantErr {
+				assert.Equal(t, "VALIDATION_ERROR", helpers.NewProblem(err).Code)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, got, len(tt.objects))
+			assert.Equal(t, hashBase64URL(got[0].Encoded), got[0].Hash)
+		})
+	}
+}
+
+func TestSessionTransactionData(t *testing.T) {
+	ctx := context.Background()
+	set := jwk.NewSet()
+	issuerKey := mockIssuerKey(t, set, "kid-1")
+	holderKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(set)
+	}))
+	defer jwksServer.Close()
+
+	cfg := &model.Cfg{Verifier: model.Verifier{
+		IssuerJWKSURL: jwksServer.URL,
+		ExternalURL:   "https://verifier.sunet.se",
+		Session:       model.VerifierSession{TTL: 60},
+	}}
+	client, err := New(ctx, nil, nil, cfg, logger.NewSimple("testing_apiv1"))
+	assert.NoError(t, err)
+
+	presentation := mockHolderCredential(t, issuerKey, "kid-1", holderKey)
+
+	tts := []struct {
+		name      string
+		vpToken   func(clientID, nonce, hash string) string
+		wantValid bool
+	}{
+		{
+			name: "confirmed",
+			vpToken: func(clientID, nonce, hash string) string {
+				return mockKeyBinding(t, presentation, holderKey, jwt.MapClaims{"aud": clientID, "nonce": nonce, "transaction_data_hashes": []string{hash}})
+			},
+			wantValid: true,
+		},
+		{
+			name: "no key binding",
+			vpToken: func(clientID, nonce, hash string) string {
+				return presentation
+			},
+		},
+		{
+			name: "hash missing",
+			vpToken: func(clientID, nonce, hash string) string {
+				return mockKeyBinding(t, presentation, holderKey, jwt.MapClaims{"aud": clientID, "nonce": nonce, "transaction_data_hashes": []string{"other"}})
+			},
+		},
+		{
+			name: "other nonce",
+			vpToken: func(clientID, nonce, hash string) string {
+				return mockKeyBinding(t, presentation, holderKey, jwt.MapClaims{"aud": clientID, "nonce": "other", "transaction_data_hashes": []string{hash}})
+			},
+		},
+		{
+			name: "other sd_hash",
+			vpToken: func(clientID, nonce, hash string) string {
+				return mockKeyBinding(t, presentation, holderKey, jwt.MapClaims{"aud": clientID, "nonce": nonce, "sd_hash": "other", "transaction_data_hashes": []string{hash}})
+			},
+		},
+		{
+			name: "not signed by holder",
+			vpToken: func(clientID, nonce, hash string) string {
+				return mockKeyBinding(t, presentation, otherKey, jwt.MapClaims{"aud": clientID, "nonce": nonce, "transaction_data_hashes": []string{hash}})
+			},
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			created, err := client.CreateSession(ctx, &CreateSessionRequest{
+				TransactionData: []map[string]any{{"type": "payment_data", "credential_ids": []any{"pid"}, "amount": "10.00"}},
+			})
+			assert.NoError(t, err)
+
+			requestObject, err := client.SessionRequestObject(ctx, &SessionRequest{SessionID: created.SessionID})
+			assert.NoError(t, err)
+			assert.Len(t, requestObject.TransactionData, 1)
+			hash := hashBase64URL(requestObject.TransactionData[0])
+
+			session, err := client.SessionResponse(ctx, &SessionResponseRequest{
+				SessionID: created.SessionID,
+				VPToken:   tt.vpToken(requestObject.ClientID, requestObject.Nonce, hash),
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantValid, session.Result.Valid, session.Result.Reason)
+			if tt.wantValid {
+				assert.Equal(t, []string{hash}, session.Result.TransactionDataHashes)
+				assert.Equal(t, "10.00", session.TransactionData[0].Data["amount"])
+			}
+		})
+	}
+}
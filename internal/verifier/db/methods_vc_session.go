@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+	"vc/pkg/helpers"
+	"vc/pkg/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// VCSessionColl is the collection of presentation sessions, shared by the replicas of the verifier. Sessions are
+// removed by a TTL index once they expire.
+type VCSessionColl struct {
+	Service *Service
+	Coll    *mongo.Collection
+	log     *logger.Log
+}
+
+// SessionDocument is a stored presentation session
+type SessionDocument struct {
+	ID string `bson:"_id"`
+
+	// Data is the session as encoded by the api
+	Data []byte `bson:"data"`
+
+	// NonceConsumed is set by the first response to the session
+	NonceConsumed bool `bson:"nonce_consumed"`
+
+	ExpiresAt time.Time `bson:"expires_at"`
+}
+
+func (c *VCSessionColl) createIndex(ctx context.Context) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:session:createIndex")
+	defer span.End()
+
+	indexTTL := mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetName("expires_at_ttl").SetExpireAfterSeconds(0),
+	}
+
+	_, err := c.Coll.Indexes().CreateOne(ctx, indexTTL)
+	return err
+}
+
+// live matches the session id if it has not expired, the TTL monitor only removes expired documents once a minute
+func live(id string) bson.M {
+	return bson.M{
+		"_id":        bson.M{"$eq": id},
+		"expires_at": bson.M{"$gt": time.Now()},
+	}
+}
+
+// Add stores a new session
+func (c *VCSessionColl) Add(ctx context.Context, doc *SessionDocument) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:session:add")
+	defer span.End()
+
+	_, err := c.Coll.InsertOne(ctx, doc)
+	return err
+}
+
+// Get returns the session id, ErrSessionNotFound if it does not exist or has expired
+func (c *VCSessionColl) Get(ctx context.Context, id string) (*SessionDocument, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:session:get")
+	defer span.End()
+
+	res := &SessionDocument{}
+	if err := c.Coll.FindOne(ctx, live(id)).Decode(res); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, helpers.ErrSessionNotFound
+		}
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ConsumeNonce marks the nonce of session id used, only the first of concurrent responses to a session matches.
+// ErrSessionCompleted is returned if the nonce already was used.
+func (c *VCSessionColl) ConsumeNonce(ctx context.Context, id string) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:session:consume_nonce")
+	defer span.End()
+
+	filter := live(id)
+	filter["nonce_consumed"] = bson.M{"$eq": false}
+
+	res, err := c.Coll.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"nonce_consumed": true}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		if _, err := c.Get(ctx, id); err != nil {
+			return err
+		}
+		return helpers.ErrSessionCompleted
+	}
+
+	return nil
+}
+
+// Update stores the session data of session id, ErrSessionNotFound if it does not exist or has expired
+func (c *VCSessionColl) Update(ctx context.Context, id string, data []byte) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:session:update")
+	defer span.End()
+
+	res, err := c.Coll.UpdateOne(ctx, live(id), bson.M{"$set": bson.M{"data": data}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return helpers.ErrSessionNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,241 @@
+package db
+
+import (
+	"context"
+	"time"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// VCVerificationColl is the verification outcome collection
+type VCVerificationColl struct {
+	Service *Service
+	Coll    *mongo.Collection
+	log     *logger.Log
+}
+
+func (c *VCVerificationColl) createIndex(ctx context.Context) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:verification:createIndex")
+	defer span.End()
+
+	indexTTL := mongo.IndexModel{
+		Keys:    bson.D{{Key: "created_at", Value: 1}},
+		Options: options.Index().SetName("created_at_ttl").SetExpireAfterSeconds(int32(c.Service.cfg.Verifier.Analytics.Retention)),
+	}
+
+	indexIssuer := mongo.IndexModel{
+		Keys:    bson.D{{Key: "issuer", Value: 1}, {Key: "created_at", Value: 1}},
+		Options: options.Index().SetName("issuer_created_at"),
+	}
+
+	_, err := c.Coll.Indexes().CreateMany(ctx, []mongo.IndexModel{indexTTL, indexIssuer})
+	return err
+}
+
+// Add adds a verification record
+func (c *VCVerificationColl) Add(ctx context.Context, record *model.VerificationRecord) error {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:verification:add")
+	defer span.End()
+
+	_, err := c.Coll.InsertOne(ctx, record)
+	return err
+}
+
+// DailyStatistics is the number of verifications of one UTC day
+type DailyStatistics struct {
+	Day          string  `json:"day" bson:"day"`
+	Total        int64   `json:"total" bson:"total"`
+	Valid        int64   `json:"valid" bson:"valid"`
+	Invalid      int64   `json:"invalid" bson:"invalid"`
+	AvgLatencyMS float64 `json:"avg_latency_ms" bson:"avg_latency_ms"`
+}
+
+// FailureStatistics is the number of failed verifications with the same reason
+type FailureStatistics struct {
+	Reason string `json:"reason" bson:"reason"`
+	Count  int64  `json:"count" bson:"count"`
+}
+
+// IssuerStatistics is the number of verifications of credentials from one issuer
+type IssuerStatistics struct {
+	Issuer  string `json:"issuer" bson:"issuer"`
+	Total   int64  `json:"total" bson:"total"`
+	Valid   int64  `json:"valid" bson:"valid"`
+	Invalid int64  `json:"invalid" bson:"invalid"`
+}
+
+// period matches the records created in [from, to)
+func period(from, to time.Time) bson.D {
+	return bson.D{{Key: "$match", Value: bson.M{
+		"created_at": bson.M{"$gte": from, "$lt": to},
+	}}}
+}
+
+// countValid sums the valid and invalid records of a group
+func countValid() bson.M {
+	return bson.M{
+		"total":   bson.M{"$sum": 1},
+		"valid":   bson.M{"$sum": bson.M{"$cond": bson.A{"$valid", 1, 0}}},
+		"invalid": bson.M{"$sum": bson.M{"$cond": bson.A{"$valid", 0, 1}}},
+	}
+}
+
+// aggregate runs pipeline and decodes all results into res
+func (c *VCVerificationColl) aggregate(ctx context.Context, pipeline mongo.Pipeline, res any) error {
+	cursor, err := c.Coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+
+	return cursor.All(ctx, res)
+}
+
+// Daily returns the number of verifications per UTC day in [from, to), oldest first
+func (c *VCVerificationColl) Daily(ctx context.Context, from, to time.Time) ([]*DailyStatistics, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:verification:daily")
+	defer span.End()
+
+	group := countValid()
+	group["_id"] = bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$created_at"}}
+	group["avg_latency_ms"] = bson.M{"$avg": "$latency_ms"}
+
+	pipeline := mongo.Pipeline{
+		period(from, to),
+		{{Key: "$group", Value: group}},
+		{{Key: "$project", Value: bson.M{
+			"_id":            0,
+			"day":            "$_id",
+			"total":          1,
+			"valid":          1,
+			"invalid":        1,
+			"avg_latency_ms": 1,
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "day", Value: 1}}}},
+	}
+
+	res := []*DailyStatistics{}
+	if err := c.aggregate(ctx, pipeline, &res); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Failures returns the number of failed verifications per reason in [from, to), most frequent first
+func (c *VCVerificationColl) Failures(ctx context.Context, from, to time.Time) ([]*FailureStatistics, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:verification:failures")
+	defer span.End()
+
+	pipeline := mongo.Pipeline{
+		period(from, to),
+		{{Key: "$match", Value: bson.M{"valid": false}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$reason",
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":    0,
+			"reason": "$_id",
+			"count":  1,
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "reason", Value: 1}}}},
+	}
+
+	res := []*FailureStatistics{}
+	if err := c.aggregate(ctx, pipeline, &res); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Issuers returns the number of verifications per issuer in [from, to), the limit issuers with most verifications first
+func (c *VCVerificationColl) Issuers(ctx context.Context, from, to time.Time, limit int64) ([]*IssuerStatistics, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:verification:issuers")
+	defer span.End()
+
+	group := countValid()
+	group["_id"] = "$issuer"
+
+	pipeline := mongo.Pipeline{
+		period(from, to),
+		{{Key: "$group", Value: group}},
+		{{Key: "$project", Value: bson.M{
+			"_id":     0,
+			"issuer":  "$_id",
+			"total":   1,
+			"valid":   1,
+			"invalid": 1,
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "total", Value: -1}, {Key: "issuer", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+
+	res := []*IssuerStatistics{}
+	if err := c.aggregate(ctx, pipeline, &res); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// AuditFilter selects the verification records of an audit listing
+type AuditFilter struct {
+	// From and To is the period [from, to) the records were created in
+	From time.Time
+	To   time.Time
+
+	// SessionsOnly leaves out the verifications that were not part of a presentation session
+	SessionsOnly bool
+
+	// Valid selects the valid or the invalid verifications, both if nil
+	Valid *bool
+
+	// CredentialType selects the verifications of one vct, all if empty
+	CredentialType string
+}
+
+// List returns at most limit records matching filter after skipping offset, newest first
+func (c *VCVerificationColl) List(ctx context.Context, filter *AuditFilter, offset, limit int64) ([]*model.VerificationRecord, error) {
+	ctx, span := c.Service.tracer.Start(ctx, "db:vc:verification:list")
+	defer span.End()
+
+	query := bson.M{"created_at": bson.M{"$gte": filter.From, "$lt": filter.To}}
+	if filter.SessionsOnly {
+		query["session_id"] = bson.M{"$exists": true}
+	}
+	if filter.Valid != nil {
+		query["valid"] = bson.M{"$eq": *filter.Valid}
+	}
+	if filter.CredentialType != "" {
+		query["credential_type"] = bson.M{"$eq": filter.CredentialType}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(offset).
+		SetLimit(limit).
+		SetProjection(bson.M{"_id": 0})
+
+	cursor, err := c.Coll.Find(ctx, query, opts)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	res := []*model.VerificationRecord{}
+	if err := cursor.All(ctx, &res); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return res, nil
+}
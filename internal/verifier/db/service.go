@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"vc/pkg/logger"
+	"vc/pkg/model"
+	"vc/pkg/trace"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Service is the database service
+type Service struct {
+	dbClient *mongo.Client
+	cfg      *model.Cfg
+	log      *logger.Log
+	tracer   *trace.Tracer
+
+	VCVerificationColl *VCVerificationColl
+	VCSessionColl      *VCSessionColl
+}
+
+// New creates a new database service
+func New(ctx context.Context, cfg *model.Cfg, tracer *trace.Tracer, log *logger.Log) (*Service, error) {
+	service := &Service{
+		log:    log.New("db"),
+		cfg:    cfg,
+		tracer: tracer,
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+
+	if err := service.connect(ctx); err != nil {
+		return nil, err
+	}
+
+	service.VCVerificationColl = &VCVerificationColl{
+		Service: service,
+		Coll:    service.dbClient.Database("vc").Collection("verification"),
+		log:     log.New("VCVerificationColl"),
+	}
+	if err := service.VCVerificationColl.createIndex(ctx); err != nil {
+		return nil, err
+	}
+
+	service.VCSessionColl = &VCSessionColl{
+		Service: service,
+		Coll:    service.dbClient.Database("vc").Collection("session"),
+		log:     log.New("VCSessionColl"),
+	}
+	if err := service.VCSessionColl.createIndex(ctx); err != nil {
+		return nil, err
+	}
+
+	service.log.Info("Started")
+
+	return service, nil
+}
+
+// connect connects to the database
+func (s *Service) connect(ctx context.Context) error {
+	ctx, span := s.tracer.Start(ctx, "verifier:db:connect")
+	defer span.End()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(s.cfg.Common.Mongo.URI).SetMonitor(trace.NewMongoMonitor(nil)))
+	if err != nil {
+		return err
+	}
+	s.dbClient = client
+
+	return nil
+}
+
+// Ping checks that the database is reachable
+func (s *Service) Ping(ctx context.Context) error {
+	return s.dbClient.Ping(ctx, nil)
+}
+
+// Close closes the database connection
+func (s *Service) Close(ctx context.Context) error {
+	return s.dbClient.Disconnect(ctx)
+}
@@ -3,9 +3,25 @@ package httpserver
 import (
 	"context"
 	"vc/internal/gen/status/apiv1_status"
+	"vc/internal/verifier/apiv1"
 )
 
 // Apiv1 interface
 type Apiv1 interface {
 	Status(ctx context.Context, req *apiv1_status.StatusRequest) (*apiv1_status.StatusReply, error)
+	VerifyCredential(ctx context.Context, req *apiv1.VerifyCredentialRequest) (*apiv1.VerifyCredentialReply, error)
+
+	// presentation sessions
+	CreateSession(ctx context.Context, req *apiv1.CreateSessionRequest) (*apiv1.CreateSessionReply, error)
+	SessionStatus(ctx context.Context, req *apiv1.SessionRequest) (*apiv1.Session, error)
+	SessionRequestObject(ctx context.Context, req *apiv1.SessionRequest) (*apiv1.SessionRequestObjectReply, error)
+	SessionResponse(ctx context.Context, req *apiv1.SessionResponseRequest) (*apiv1.Session, error)
+
+	// analytics
+	DailyAnalytics(ctx context.Context, req *apiv1.AnalyticsRequest) (*apiv1.DailyAnalyticsReply, error)
+	FailureAnalytics(ctx context.Context, req *apiv1.AnalyticsRequest) (*apiv1.FailureAnalyticsReply, error)
+	IssuerAnalytics(ctx context.Context, req *apiv1.IssuerAnalyticsRequest) (*apiv1.IssuerAnalyticsReply, error)
+
+	// audit
+	Audit(ctx context.Context, req *apiv1.AuditRequest) (*apiv1.AuditReply, error)
 }
@@ -1,13 +1,19 @@
 package httpserver
 
 import (
+	"bytes"
 	"context"
+	"net/http"
 
 	"vc/internal/gen/status/apiv1_status"
+	"vc/internal/verifier/apiv1"
 
 	"github.com/gin-gonic/gin"
 )
 
+// auditMIMECSV is the media type of the CSV export of the audit trail
+const auditMIMECSV = "text/csv"
+
 func (s *Service) endpointHealth(ctx context.Context, c *gin.Context) (any, error) {
 	request := &apiv1_status.StatusRequest{}
 	reply, err := s.apiv1.Status(ctx, request)
@@ -16,3 +22,126 @@ func (s *Service) endpointHealth(ctx context.Context, c *gin.Context) (any, erro
 	}
 	return reply, nil
 }
+
+func (s *Service) endpointVerifyCredential(ctx context.Context, c *gin.Context) (any, error) {
+	request := &apiv1.VerifyCredentialRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		return nil, err
+	}
+	reply, err := s.apiv1.VerifyCredential(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *Service) endpointCreateSession(ctx context.Context, c *gin.Context) (any, error) {
+	request := &apiv1.CreateSessionRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		return nil, err
+	}
+	reply, err := s.apiv1.CreateSession(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *Service) endpointSessionStatus(ctx context.Context, c *gin.Context) (any, error) {
+	request := &apiv1.SessionRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		return nil, err
+	}
+	reply, err := s.apiv1.SessionStatus(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *Service) endpointSessionRequestObject(ctx context.Context, c *gin.Context) (any, error) {
+	request := &apiv1.SessionRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		return nil, err
+	}
+	reply, err := s.apiv1.SessionRequestObject(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *Service) endpointSessionResponse(ctx context.Context, c *gin.Context) (any, error) {
+	request := &apiv1.SessionResponseRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		return nil, err
+	}
+	// wallets post direct_post responses as application/x-www-form-urlencoded
+	if request.VPToken == "" {
+		request.VPToken = c.PostForm("vp_token")
+	}
+	reply, err := s.apiv1.SessionResponse(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *Service) endpointDailyAnalytics(ctx context.Context, c *gin.Context) (any, error) {
+	request := &apiv1.AnalyticsRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		return nil, err
+	}
+	reply, err := s.apiv1.DailyAnalytics(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *Service) endpointFailureAnalytics(ctx context.Context, c *gin.Context) (any, error) {
+	request := &apiv1.AnalyticsRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		return nil, err
+	}
+	reply, err := s.apiv1.FailureAnalytics(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *Service) endpointIssuerAnalytics(ctx context.Context, c *gin.Context) (any, error) {
+	request := &apiv1.IssuerAnalyticsRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		return nil, err
+	}
+	reply, err := s.apiv1.IssuerAnalytics(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *Service) endpointAudit(ctx context.Context, c *gin.Context) (any, error) {
+	request := &apiv1.AuditRequest{}
+	if err := s.httpHelpers.Binding.Request(ctx, c, request); err != nil {
+		return nil, err
+	}
+	reply, err := s.apiv1.Audit(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.NegotiateFormat(gin.MIMEJSON, auditMIMECSV) == auditMIMECSV {
+		body := &bytes.Buffer{}
+		if err := reply.WriteCSV(body); err != nil {
+			return nil, err
+		}
+		c.Header("Content-Disposition", `attachment; filename="verifier-audit.csv"`)
+		c.Data(http.StatusOK, auditMIMECSV, body.Bytes())
+		return nil, nil
+	}
+
+	return reply, nil
+}
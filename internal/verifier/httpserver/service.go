@@ -2,6 +2,7 @@ package httpserver
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"vc/internal/verifier/apiv1"
 	"vc/pkg/httphelpers"
@@ -9,6 +10,9 @@ import (
 	"vc/pkg/model"
 	"vc/pkg/trace"
 
+	// swagger docs
+	_ "vc/docs/verifier"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -40,13 +44,37 @@ func New(ctx context.Context, cfg *model.Cfg, apiv1 *apiv1.Client, tracer *trace
 		return nil, err
 	}
 
-	rgRoot, err := s.httpHelpers.Server.Default(ctx, s.server, s.gin, s.cfg.Verifier.APIServer.Addr)
+	rgRoot, err := s.httpHelpers.Server.Default(ctx, s.server, s.gin, s.cfg.Verifier.APIServer)
 	if err != nil {
 		return nil, err
 	}
 
 	s.httpHelpers.Server.RegEndpoint(ctx, rgRoot, http.MethodGet, "health", s.endpointHealth)
 
+	if err := s.httpHelpers.Server.Docs(ctx, rgRoot); err != nil {
+		return nil, err
+	}
+
+	rgAPIv1 := rgRoot.Group("api/v1")
+	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPost, "verify", s.endpointVerifyCredential)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPost, "session", s.endpointCreateSession)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodGet, "session/:session_id", s.endpointSessionStatus)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodGet, "session/:session_id/request", s.endpointSessionRequestObject)
+	s.httpHelpers.Server.RegEndpoint(ctx, rgAPIv1, http.MethodPost, "session/:session_id/response", s.endpointSessionResponse)
+
+	if s.cfg.Verifier.Analytics.Enabled {
+		// analytics and the audit trail, with what wallets presented, are only for the operator while the session
+		// endpoints stay open to wallets
+		rgOperator, err := s.operatorGroup(ctx, rgAPIv1)
+		if err != nil {
+			return nil, err
+		}
+		s.httpHelpers.Server.RegEndpoint(ctx, rgOperator, http.MethodGet, "analytics/daily", s.endpointDailyAnalytics)
+		s.httpHelpers.Server.RegEndpoint(ctx, rgOperator, http.MethodGet, "analytics/failures", s.endpointFailureAnalytics)
+		s.httpHelpers.Server.RegEndpoint(ctx, rgOperator, http.MethodGet, "analytics/issuers", s.endpointIssuerAnalytics)
+		s.httpHelpers.Server.RegEndpoint(ctx, rgOperator, http.MethodGet, "audit", s.endpointAudit)
+	}
+
 	// Run http server
 	go func() {
 		err := s.httpHelpers.Server.ListenAndServe(ctx, s.server, s.cfg.Verifier.APIServer)
@@ -60,8 +88,36 @@ func New(ctx context.Context, cfg *model.Cfg, apiv1 *apiv1.Client, tracer *trace
 	return s, nil
 }
 
-// Close closing httpserver
+// operatorGroup returns a group of rg authenticated with the basic auth or auth of the api server, one of them must be
+// enabled
+func (s *Service) operatorGroup(ctx context.Context, rg *gin.RouterGroup) (*gin.RouterGroup, error) {
+	cfg := s.cfg.Verifier.APIServer
+	if !cfg.BasicAuth.Enabled && !cfg.Auth.Enabled {
+		return nil, errors.New("verifier analytics requires api_server basic_auth or auth to be enabled")
+	}
+
+	rgOperator := rg.Group("")
+	if cfg.BasicAuth.Enabled {
+		rgOperator.Use(s.httpHelpers.Middleware.BasicAuth(ctx, cfg.BasicAuth.Users))
+	}
+	if cfg.Auth.Enabled {
+		rgOperator.Use(s.httpHelpers.Auth.Middleware(ctx, cfg.Auth))
+	}
+
+	return rgOperator, nil
+}
+
+// AddHealthCheck adds a dependency check to the readiness probe
+func (s *Service) AddHealthCheck(name string, check httphelpers.HealthCheck) {
+	s.httpHelpers.Server.AddHealthCheck(name, check)
+}
+
+// Close drains in-flight requests and stops the httpserver
 func (s *Service) Close(ctx context.Context) error {
-	s.log.Info("Stopping")
+	if err := s.httpHelpers.Server.Shutdown(ctx, s.server, s.cfg.Verifier.APIServer); err != nil {
+		return err
+	}
+
+	s.log.Info("Stopped")
 	return nil
 }
@@ -0,0 +1,71 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"vc/pkg/httphelpers"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+	"vc/pkg/openapi"
+	"vc/pkg/trace"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpecDrift(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	log := logger.NewSimple("testing_httpserver")
+
+	tracer, err := trace.NewForTesting(ctx, "test", log)
+	assert.NoError(t, err)
+
+	cfg := &model.Cfg{Verifier: model.Verifier{APIServer: model.APIServer{Addr: "127.0.0.1:0"}}}
+	// register the optional routes as well
+	cfg.Verifier.Analytics.Enabled = true
+	cfg.Verifier.APIServer.BasicAuth = model.BasicAuth{Enabled: true, Users: map[string]string{"operator": "secret"}}
+
+	s, err := New(ctx, cfg, nil, tracer, log)
+	assert.NoError(t, err)
+	defer s.Close(ctx)
+
+	spec, err := openapi.Registered()
+	assert.NoError(t, err)
+
+	drift, err := openapi.Drift(spec, s.gin.Routes(), httphelpers.InfraRoutes...)
+	assert.NoError(t, err)
+	assert.Empty(t, drift, "update the swag annotations and run make swagger")
+}
+
+func TestOperatorEndpointsAuthenticated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	log := logger.NewSimple("testing_httpserver")
+
+	tracer, err := trace.NewForTesting(ctx, "test", log)
+	assert.NoError(t, err)
+
+	cfg := &model.Cfg{Verifier: model.Verifier{APIServer: model.APIServer{Addr: "127.0.0.1:0"}}}
+	cfg.Verifier.Analytics.Enabled = true
+
+	_, err = New(ctx, cfg, nil, tracer, log)
+	assert.Error(t, err, "operator endpoints without authentication")
+
+	cfg.Verifier.APIServer.BasicAuth = model.BasicAuth{Enabled: true, Users: map[string]string{"operator": "secret"}}
+	s, err := New(ctx, cfg, nil, tracer, log)
+	assert.NoError(t, err)
+	defer s.Close(ctx)
+
+	for _, path := range []string{"/api/v1/analytics/daily", "/api/v1/analytics/failures", "/api/v1/analytics/issuers", "/api/v1/audit"} {
+		for _, accept := range []string{"application/json", "text/csv"} {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			req.Header.Set("Accept", accept)
+			rec := httptest.NewRecorder()
+			s.gin.ServeHTTP(rec, req)
+			assert.Equal(t, http.StatusUnauthorized, rec.Code, path)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package policy
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Policy rules
+const (
+	RuleAllowedIssuers    = "allowed_issuers"
+	RuleMaxCredentialAge  = "max_credential_age"
+	RuleRequiredClaims    = "required_claims"
+	RuleAllowedAlgorithms = "allowed_algorithms"
+)
+
+// Credential is what a policy is evaluated against, taken from a verified credential
+type Credential struct {
+	Issuer    string
+	IssuedAt  int64
+	Algorithm string
+
+	// Claims are the disclosed claims
+	Claims map[string]any
+}
+
+// Result is the outcome of a policy evaluation
+type Result struct {
+	Policy string `json:"policy"`
+	Passed bool   `json:"passed"`
+
+	// Rules holds the outcome of each evaluated rule
+	Rules []RuleResult `json:"rules"`
+}
+
+// RuleResult is the outcome of one rule
+type RuleResult struct {
+	Rule   string `json:"rule"`
+	Passed bool   `json:"passed"`
+
+	// Reason is set when the rule failed
+	Reason string `json:"reason,omitempty"`
+}
+
+// Failed returns the reasons of the failed rules, joined
+func (r *Result) Failed() string {
+	reasons := []string{}
+	for _, rule := range r.Rules {
+		if !rule.Passed {
+			reasons = append(reasons, rule.Reason)
+		}
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// Evaluate evaluates every configured rule of p against credential at time now
+func (p *Policy) Evaluate(credential *Credential, now time.Time) *Result {
+	result := &Result{
+		Policy: p.Name,
+		Passed: true,
+		Rules:  []RuleResult{},
+	}
+
+	add := func(rule, reason string) {
+		result.Rules = append(result.Rules, RuleResult{Rule: rule, Passed: reason == "", Reason: reason})
+		if reason != "" {
+			result.Passed = false
+		}
+	}
+
+	if len(p.AllowedIssuers) > 0 {
+		reason := ""
+		if !slices.Contains(p.AllowedIssuers, credential.Issuer) {
+			reason = fmt.Sprintf("issuer %q is not allowed", credential.Issuer)
+		}
+		add(RuleAllowedIssuers, reason)
+	}
+
+	if p.MaxCredentialAge > 0 {
+		reason := ""
+		switch {
+		case credential.IssuedAt == 0:
+			reason = "credential has no iat"
+		case now.Unix()-credential.IssuedAt > p.MaxCredentialAge:
+			reason = fmt.Sprintf("credential is older than %d seconds", p.MaxCredentialAge)
+		}
+		add(RuleMaxCredentialAge, reason)
+	}
+
+	if len(p.RequiredClaims) > 0 {
+		missing := []string{}
+		for _, claim := range p.RequiredClaims {
+			if !hasClaim(credential.Claims, claim) {
+				missing = append(missing, claim)
+			}
+		}
+		reason := ""
+		if len(missing) > 0 {
+			reason = "required claims not disclosed: " + strings.Join(missing, ", ")
+		}
+		add(RuleRequiredClaims, reason)
+	}
+
+	if len(p.AllowedAlgorithms) > 0 {
+		reason := ""
+		if !slices.Contains(p.AllowedAlgorithms, credential.Algorithm) {
+			reason = fmt.Sprintf("algorithm %q is not allowed", credential.Algorithm)
+		}
+		add(RuleAllowedAlgorithms, reason)
+	}
+
+	return result
+}
+
+// hasClaim reports if the dot separated path is present in claims
+func hasClaim(claims map[string]any, path string) bool {
+	var v any = claims
+	for _, name := range strings.Split(path, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return false
+		}
+		if v, ok = m[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
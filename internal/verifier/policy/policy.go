@@ -0,0 +1,113 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+	"time"
+	"vc/pkg/helpers"
+	"vc/pkg/model"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Policy is the acceptance policy of a relying party, evaluated after the credential signature is verified.
+// Rules left empty are not evaluated.
+type Policy struct {
+	// Name selects the policy in verification requests
+	Name string `json:"name" yaml:"name" validate:"required"`
+
+	// AllowedIssuers are the accepted iss values of the credential
+	AllowedIssuers []string `json:"allowed_issuers" yaml:"allowed_issuers"`
+
+	// MaxCredentialAge is the maximum number of seconds since the credential was issued, from its iat
+	MaxCredentialAge int64 `json:"max_credential_age" yaml:"max_credential_age" validate:"gte=0"`
+
+	// RequiredClaims must be disclosed, nested claims are given as a dot separated path, e.g. address.country
+	RequiredClaims []string `json:"required_claims" yaml:"required_claims"`
+
+	// AllowedAlgorithms are the accepted cryptosuites, the JWS alg of the credential, e.g. ES256
+	AllowedAlgorithms []string `json:"allowed_algorithms" yaml:"allowed_algorithms"`
+}
+
+// ReadPolicy reads a policy from a YAML or JSON file
+func ReadPolicy(path string) (*Policy, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON is a subset of YAML
+	policy := &Policy{}
+	if err := yaml.UnmarshalStrict(b, policy); err != nil {
+		return nil, fmt.Errorf("policy %s: %w", path, err)
+	}
+
+	if err := helpers.CheckSimple(policy); err != nil {
+		return nil, fmt.Errorf("policy %s: %w", path, err)
+	}
+
+	return policy, nil
+}
+
+// Engine holds the configured policies
+type Engine struct {
+	policies      map[string]*Policy
+	defaultPolicy string
+	now           func() time.Time
+}
+
+// New reads the policies of the verifier configuration
+func New(cfg *model.Cfg) (*Engine, error) {
+	e := &Engine{
+		policies:      map[string]*Policy{},
+		defaultPolicy: cfg.Verifier.Policy.Default,
+		now:           time.Now,
+	}
+
+	for _, path := range cfg.Verifier.Policy.Paths {
+		policy, err := ReadPolicy(path)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := e.policies[policy.Name]; ok {
+			return nil, fmt.Errorf("policy %s: duplicate policy name %q", path, policy.Name)
+		}
+		e.policies[policy.Name] = policy
+	}
+
+	if e.defaultPolicy != "" {
+		if _, ok := e.policies[e.defaultPolicy]; !ok {
+			return nil, fmt.Errorf("default policy %q is not configured", e.defaultPolicy)
+		}
+	}
+
+	return e, nil
+}
+
+// Lookup returns the policy name, or the default policy if name is empty. Without a default policy an empty name gives
+// nil, ErrUnknownPolicy is returned for a name that is not configured.
+func (e *Engine) Lookup(name string) (*Policy, error) {
+	if name == "" {
+		name = e.defaultPolicy
+		if name == "" {
+			return nil, nil
+		}
+	}
+
+	policy, ok := e.policies[name]
+	if !ok {
+		return nil, helpers.ErrUnknownPolicy
+	}
+
+	return policy, nil
+}
+
+// Evaluate evaluates the policy name against a verified credential, the result is nil if no policy applies
+func (e *Engine) Evaluate(name string, credential *Credential) (*Result, error) {
+	policy, err := e.Lookup(name)
+	if err != nil || policy == nil {
+		return nil, err
+	}
+
+	return policy.Evaluate(credential, e.now()), nil
+}
@@ -0,0 +1,163 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"vc/pkg/helpers"
+	"vc/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const mockPolicyYAML = `name: ehic
+allowed_issuers: ["https://issuer.sunet.se"]
+max_credential_age: 3600
+required_claims: ["given_name", "address.country"]
+allowed_algorithms: ["ES256"]
+`
+
+const mockPolicyJSON = `{"name": "any_issuer", "required_claims": ["given_name"]}`
+
+func mockConfig(t *testing.T, defaultPolicy string) *model.Cfg {
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "ehic.yaml")
+	jsonPath := filepath.Join(dir, "any_issuer.json")
+	assert.NoError(t, os.WriteFile(yamlPath, []byte(mockPolicyYAML), 0600))
+	assert.NoError(t, os.WriteFile(jsonPath, []byte(mockPolicyJSON), 0600))
+
+	return &model.Cfg{
+		Verifier: model.Verifier{
+			Policy: model.VerifierPolicy{
+				Paths:   []string{yamlPath, jsonPath},
+				Default: defaultPolicy,
+			},
+		},
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	valid := func() *Credential {
+		return &Credential{
+			Issuer:    "https://issuer.sunet.se",
+			IssuedAt:  now.Unix() - 60,
+			Algorithm: "ES256",
+			Claims: map[string]any{
+				"given_name": "Magnus",
+				"address":    map[string]any{"country": "SE"},
+			},
+		}
+	}
+
+	tts := []struct {
+		name     string
+		modify   func(c *Credential)
+		passed   bool
+		failures []RuleResult
+	}{
+		{
+			name:   "passes",
+			modify: func(c *Credential) {},
+			passed: true,
+		},
+		{
+			name:     "issuer not allowed",
+			modify:   func(c *Credential) { c.Issuer = "https://evil.example.com" },
+			failures: []RuleResult{{Rule: RuleAllowedIssuers, Reason: `issuer "https://evil.example.com" is not allowed`}},
+		},
+		{
+			name:     "too old",
+			modify:   func(c *Credential) { c.IssuedAt = now.Unix() - 3601 },
+			failures: []RuleResult{{Rule: RuleMaxCredentialAge, Reason: "credential is older than 3600 seconds"}},
+		},
+		{
+			name:     "no iat",
+			modify:   func(c *Credential) { c.IssuedAt = 0 },
+			failures: []RuleResult{{Rule: RuleMaxCredentialAge, Reason: "credential has no iat"}},
+		},
+		{
+			name:     "nested claim missing",
+			modify:   func(c *Credential) { c.Claims["address"] = map[string]any{"street": "Tulegatan 11"} },
+			failures: []RuleResult{{Rule: RuleRequiredClaims, Reason: "required claims not disclosed: address.country"}},
+		},
+		{
+			name: "several rules fail",
+			modify: func(c *Credential) {
+				c.Algorithm = "HS256"
+				delete(c.Claims, "given_name")
+			},
+			failures: []RuleResult{
+				{Rule: RuleRequiredClaims, Reason: "required claims not disclosed: given_name"},
+				{Rule: RuleAllowedAlgorithms, Reason: `algorithm "HS256" is not allowed`},
+			},
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := ReadPolicy(mockConfig(t, "").Verifier.Policy.Paths[0])
+			assert.NoError(t, err)
+
+			credential := valid()
+			tt.modify(credential)
+
+			got := policy.Evaluate(credential, now)
+			assert.Equal(t, "ehic", got.Policy)
+			assert.Equal(t, tt.passed, got.Passed)
+			assert.Len(t, got.Rules, 4)
+
+			failures := []RuleResult{}
+			for _, rule := range got.Rules {
+				if !rule.Passed {
+					failures = append(failures, rule)
+				}
+			}
+			if tt.failures == nil {
+				tt.failures = []RuleResult{}
+			}
+			assert.Equal(t, tt.failures, failures)
+		})
+	}
+}
+
+func TestEngine(t *testing.T) {
+	engine, err := New(mockConfig(t, "any_issuer"))
+	assert.NoError(t, err)
+
+	credential := &Credential{Issuer: "https://other.example.com", Claims: map[string]any{"given_name": "Magnus"}}
+
+	got, err := engine.Evaluate("", credential)
+	assert.NoError(t, err)
+	assert.Equal(t, &Result{
+		Policy: "any_issuer",
+		Passed: true,
+		Rules:  []RuleResult{{Rule: RuleRequiredClaims, Passed: true}},
+	}, got)
+
+	got, err = engine.Evaluate("ehic", credential)
+	assert.NoError(t, err)
+	assert.False(t, got.Passed)
+
+	_, err = engine.Evaluate("unknown", credential)
+	assert.ErrorIs(t, err, helpers.ErrUnknownPolicy)
+
+	noDefault, err := New(mockConfig(t, ""))
+	assert.NoError(t, err)
+	got, err = noDefault.Evaluate("", credential)
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	_, err = New(mockConfig(t, "unknown"))
+	assert.Error(t, err)
+}
+
+func TestReadPolicyUnknownField(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("name: strict\nallowed_issuer: [\"https://issuer.sunet.se\"]\n"), 0600))
+
+	_, err := ReadPolicy(path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,133 @@
+package trust
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"vc/pkg/keyresolver"
+)
+
+// maxListSize is the maximum size of a downloaded trusted list, the EU LOTL is well below 1 MB
+const maxListSize = 16 << 20
+
+// Refresh downloads the list of trusted lists and the trusted lists it points to.
+// A trusted list that fails to load is skipped, the previous services are kept if the list of trusted lists fails.
+func (s *Service) Refresh(ctx context.Context) error {
+	lotl, err := s.fetch(ctx, s.cfg.Verifier.Trust.LOTLURL, s.lotlSigners)
+	if err != nil {
+		return err
+	}
+
+	// the configured list may also be a trusted list with services of its own
+	services, err := lotl.services()
+	if err != nil {
+		return err
+	}
+
+	for _, pointer := range lotl.pointers() {
+		var signers []*x509.Certificate
+		for _, identity := range pointer.Identities {
+			certs, err := identity.certificates()
+			if err != nil {
+				return err
+			}
+			signers = append(signers, certs...)
+		}
+
+		tl, err := s.fetch(ctx, pointer.Location, signers)
+		if err != nil {
+			s.log.Error(err, "load trusted list", "location", pointer.Location)
+			continue
+		}
+
+		tlServices, err := tl.services()
+		if err != nil {
+			s.log.Error(err, "parse trusted list services", "location", pointer.Location)
+			continue
+		}
+		services = append(services, tlServices...)
+	}
+
+	roots := x509.NewCertPool()
+	for _, service := range services {
+		roots.AddCert(service.Certificate)
+	}
+
+	s.mu.Lock()
+	s.services = services
+	s.resolver = keyresolver.NewX509(roots)
+	s.mu.Unlock()
+
+	s.log.Info("trusted lists loaded", "lists", len(lotl.pointers()), "services", len(services))
+
+	return nil
+}
+
+func (s *Service) fetch(ctx context.Context, url string, signers []*x509.Certificate) (*trustServiceStatusList, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch trusted list %s: %s", url, resp.Status)
+	}
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxListSize))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseTSL(b, signers, s.now())
+}
+
+// Services returns the trusted services with a granted status
+func (s *Service) Services() []TrustedService {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.services
+}
+
+// ResolveChain implements keyresolver.ChainResolver, the chain must lead to the certificate of a trusted service
+func (s *Service) ResolveChain(ctx context.Context, chain []*x509.Certificate) (crypto.PublicKey, error) {
+	s.mu.RLock()
+	resolver := s.resolver
+	s.mu.RUnlock()
+
+	if resolver == nil {
+		return nil, ErrNotTrusted
+	}
+
+	publicKey, err := resolver.ResolveChain(ctx, chain)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNotTrusted, err)
+	}
+
+	return publicKey, nil
+}
+
+// TrustedKey returns nil if publicKey is the key of a trusted service certificate
+func (s *Service) TrustedKey(publicKey crypto.PublicKey) error {
+	key, ok := publicKey.(interface{ Equal(crypto.PublicKey) bool })
+	if !ok {
+		return ErrNotTrusted
+	}
+
+	for _, service := range s.Services() {
+		if key.Equal(service.Certificate.PublicKey) {
+			return nil
+		}
+	}
+
+	return ErrNotTrusted
+}
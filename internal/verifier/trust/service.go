@@ -0,0 +1,82 @@
+package trust
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+	"sync"
+	"time"
+	"vc/pkg/keyresolver"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+)
+
+// Service keeps the trusted services of the ETSI trusted lists, refreshed in the background
+type Service struct {
+	cfg         *model.Cfg
+	log         *logger.Log
+	httpClient  *http.Client
+	lotlSigners []*x509.Certificate
+	wg          *sync.WaitGroup
+	quitChan    chan struct{}
+	ticker      *time.Ticker
+	now         func() time.Time
+
+	mu       sync.RWMutex
+	services []TrustedService
+	resolver *keyresolver.X509
+}
+
+// New creates a new trust service and loads the trusted lists, a failed first load is retried on the next refresh
+func New(ctx context.Context, wg *sync.WaitGroup, cfg *model.Cfg, log *logger.Log) (*Service, error) {
+	s := &Service{
+		cfg:        cfg,
+		log:        log.New("trust"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		wg:         wg,
+		quitChan:   make(chan struct{}),
+		ticker:     time.NewTicker(time.Duration(cfg.Verifier.Trust.RefreshInterval) * time.Second),
+		now:        time.Now,
+	}
+
+	for _, path := range cfg.Verifier.Trust.LOTLSigningCertificates {
+		certs, err := keyresolver.LoadCertificates(path)
+		if err != nil {
+			return nil, err
+		}
+		s.lotlSigners = append(s.lotlSigners, certs...)
+	}
+
+	if err := s.Refresh(ctx); err != nil {
+		s.log.Error(err, "load trusted lists")
+	}
+
+	s.wg.Add(1)
+	go func() {
+		for {
+			select {
+			case <-s.ticker.C:
+				if err := s.Refresh(ctx); err != nil {
+					s.log.Error(err, "refresh trusted lists")
+				}
+			case <-s.quitChan:
+				s.log.Info("Stop refreshing")
+				s.ticker.Stop()
+				s.wg.Done()
+				return
+			}
+		}
+	}()
+
+	s.log.Info("Started")
+
+	return s, nil
+}
+
+// Close closes the trust service
+func (s *Service) Close(ctx context.Context) error {
+	s.quitChan <- struct{}{}
+
+	s.log.Info("Stopped")
+	return nil
+}
@@ -0,0 +1,243 @@
+package trust
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+
+	"github.com/beevik/etree"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/stretchr/testify/assert"
+)
+
+const mockTL = `<TrustServiceStatusList xmlns="http://uri.etsi.org/02231/v2#">
+	<SchemeInformation>
+		<SchemeTerritory>SE</SchemeTerritory>
+		<NextUpdate><dateTime>%s</dateTime></NextUpdate>
+	</SchemeInformation>
+	<TrustServiceProviderList>
+		<TrustServiceProvider>
+			<TSPInformation><TSPName><Name xml:lang="en">SUNET</Name></TSPName></TSPInformation>
+			<TSPServices>
+				<TSPService><ServiceInformation>
+					<ServiceTypeIdentifier>http://uri.etsi.org/Svc/Svctype/EAA/Pub-EAA</ServiceTypeIdentifier>
+					<ServiceName><Name xml:lang="en">PID provider</Name></ServiceName>
+					<ServiceDigitalIdentity><DigitalId><X509Certificate>%s</X509Certificate></DigitalId></ServiceDigitalIdentity>
+					<ServiceStatus>http://uri.etsi.org/TrstSvc/TrustedList/Svcstatus/granted</ServiceStatus>
+				</ServiceInformation></TSPService>
+				<TSPService><ServiceInformation>
+					<ServiceTypeIdentifier>http://uri.etsi.org/Svc/Svctype/EAA/Pub-EAA</ServiceTypeIdentifier>
+					<ServiceName><Name xml:lang="en">Old PID provider</Name></ServiceName>
+					<ServiceDigitalIdentity><DigitalId><X509Certificate>%s</X509Certificate></DigitalId></ServiceDigitalIdentity>
+					<ServiceStatus>http://uri.etsi.org/TrstSvc/TrustedList/Svcstatus/withdrawn</ServiceStatus>
+				</ServiceInformation></TSPService>
+			</TSPServices>
+		</TrustServiceProvider>
+	</TrustServiceProviderList>
+</TrustServiceStatusList>`
+
+const mockLOTL = `<TrustServiceStatusList xmlns="http://uri.etsi.org/02231/v2#">
+	<SchemeInformation>
+		<SchemeTerritory>EU</SchemeTerritory>
+		<NextUpdate><dateTime>%s</dateTime></NextUpdate>
+		<PointersToOtherTSL>
+			<OtherTSLPointer>
+				%s
+				<TSLLocation>%s</TSLLocation>
+				<AdditionalInformation><OtherInformation><MimeType>application/vnd.etsi.tsl+xml</MimeType></OtherInformation></AdditionalInformation>
+			</OtherTSLPointer>
+			<OtherTSLPointer>
+				<TSLLocation>%s/tl-se.pdf</TSLLocation>
+				<AdditionalInformation><OtherInformation><MimeType>application/pdf</MimeType></OtherInformation></AdditionalInformation>
+			</OtherTSLPointer>
+		</PointersToOtherTSL>
+	</SchemeInformation>
+</TrustServiceStatusList>`
+
+func mockCertificate(t *testing.T) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "issuer"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	return cert
+}
+
+func sign(t *testing.T, keyStore dsig.X509KeyStore, document string) []byte {
+	doc := etree.NewDocument()
+	assert.NoError(t, doc.ReadFromString(document))
+
+	signed, err := dsig.NewDefaultSigningContext(keyStore).SignEnveloped(doc.Root())
+	assert.NoError(t, err)
+	doc.SetRoot(signed)
+
+	b, err := doc.WriteToBytes()
+	assert.NoError(t, err)
+
+	return b
+}
+
+func signerCertificate(t *testing.T, keyStore dsig.X509KeyStore) *x509.Certificate {
+	_, der, err := keyStore.GetKeyPair()
+	assert.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	return cert
+}
+
+func TestRefresh(t *testing.T) {
+	lotlSigner := dsig.RandomKeyStoreForTest()
+	tlSigner := dsig.RandomKeyStoreForTest()
+	granted := mockCertificate(t)
+	withdrawn := mockCertificate(t)
+
+	encode := func(cert *x509.Certificate) string {
+		return base64.StdEncoding.EncodeToString(cert.Raw)
+	}
+	nextUpdate := time.Now().Add(time.Hour).Format(time.RFC3339)
+	identities := fmt.Sprintf(`<ServiceDigitalIdentities><ServiceDigitalIdentity><DigitalId><X509Certificate>%s</X509Certificate></DigitalId></ServiceDigitalIdentity></ServiceDigitalIdentities>`, encode(signerCertificate(t, tlSigner)))
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/lotl.xml":
+			w.Write(sign(t, lotlSigner, fmt.Sprintf(mockLOTL, nextUpdate, identities, server.URL+"/tl-se.xml", server.URL)))
+		case "/lotl-unsigned-tl.xml":
+			w.Write(sign(t, lotlSigner, fmt.Sprintf(mockLOTL, nextUpdate, identities, server.URL+"/tl-unsigned.xml", server.URL)))
+		case "/lotl-no-signers.xml":
+			w.Write(sign(t, lotlSigner, fmt.Sprintf(mockLOTL, nextUpdate, "", server.URL+"/tl-unsigned.xml", server.URL)))
+		case "/tl-se.xml":
+			w.Write(sign(t, tlSigner, fmt.Sprintf(mockTL, nextUpdate, encode(granted), encode(withdrawn))))
+		case "/tl-unsigned.xml":
+			fmt.Fprintf(w, mockTL, nextUpdate, encode(granted), encode(withdrawn))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tts := []struct {
+		name         string
+		lotl         string
+		lotlSigner   *x509.Certificate
+		now          time.Time
+		wantErr      bool
+		wantServices int
+	}{
+		{
+			name:         "granted service loaded",
+			lotl:         "/lotl.xml",
+			lotlSigner:   signerCertificate(t, lotlSigner),
+			now:          time.Now(),
+			wantServices: 1,
+		},
+		{
+			name:       "wrong lotl signer",
+			lotl:       "/lotl.xml",
+			lotlSigner: signerCertificate(t, tlSigner),
+			now:        time.Now(),
+			wantErr:    true,
+		},
+		{
+			name:    "no lotl signer",
+			lotl:    "/lotl.xml",
+			now:     time.Now(),
+			wantErr: true,
+		},
+		{
+			name:       "expired",
+			lotl:       "/lotl.xml",
+			lotlSigner: signerCertificate(t, lotlSigner),
+			now:        time.Now().Add(2 * time.Hour),
+			wantErr:    true,
+		},
+		{
+			name:         "unsigned trusted list skipped",
+			lotl:         "/lotl-unsigned-tl.xml",
+			lotlSigner:   signerCertificate(t, lotlSigner),
+			now:          time.Now(),
+			wantServices: 0,
+		},
+		{
+			name:         "trusted list without signer certificates skipped",
+			lotl:         "/lotl-no-signers.xml",
+			lotlSigner:   signerCertificate(t, lotlSigner),
+			now:          time.Now(),
+			wantServices: 0,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			var lotlSigners []*x509.Certificate
+			if tt.lotlSigner != nil {
+				lotlSigners = append(lotlSigners, tt.lotlSigner)
+			}
+			s := &Service{
+				cfg:         &model.Cfg{Verifier: model.Verifier{Trust: model.VerifierTrust{LOTLURL: server.URL + tt.lotl}}},
+				log:         logger.NewSimple("testing_trust"),
+				httpClient:  server.Client(),
+				lotlSigners: lotlSigners,
+				now:         func() time.Time { return tt.now },
+			}
+
+			err := s.Refresh(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, s.Services(), tt.wantServices)
+			if tt.wantServices == 0 {
+				_, err = s.ResolveChain(context.Background(), []*x509.Certificate{granted})
+				assert.True(t, errors.Is(err, ErrNotTrusted), "got %v", err)
+				return
+			}
+
+			_, err = s.ResolveChain(context.Background(), []*x509.Certificate{granted})
+			assert.NoError(t, err)
+
+			_, err = s.ResolveChain(context.Background(), []*x509.Certificate{withdrawn})
+			assert.True(t, errors.Is(err, ErrNotTrusted), "got %v", err)
+
+			assert.NoError(t, s.TrustedKey(granted.PublicKey))
+			assert.True(t, errors.Is(s.TrustedKey(withdrawn.PublicKey), ErrNotTrusted))
+		})
+	}
+}
+
+func TestParseTSLUnsigned(t *testing.T) {
+	tl := fmt.Sprintf(mockTL, time.Now().Add(time.Hour).Format(time.RFC3339), base64.StdEncoding.EncodeToString(mockCertificate(t).Raw), "")
+
+	_, err := parseTSL([]byte(tl), nil, time.Now())
+	assert.True(t, errors.Is(err, ErrNoSigners), "got %v", err)
+
+	_, err = parseTSL([]byte(tl), []*x509.Certificate{mockCertificate(t)}, time.Now())
+	assert.Error(t, err)
+}
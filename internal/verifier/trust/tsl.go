@@ -0,0 +1,202 @@
+package trust
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	dsig "github.com/russellhaering/goxmldsig"
+)
+
+var (
+	// ErrListExpired is returned when the next update of a trusted list has passed
+	ErrListExpired = errors.New("trusted list has expired")
+
+	// ErrNotTrusted is returned when an issuer key is not listed with a granted status
+	ErrNotTrusted = errors.New("issuer is not in a trusted list")
+
+	// ErrNoSigners is returned for a trusted list without certificates its signature can be validated with
+	ErrNoSigners = errors.New("trusted list has no signing certificates")
+)
+
+const (
+	// mimeTypeTSL is the mime type of the XML form of a trusted list, pointers to the PDF form are ignored
+	mimeTypeTSL = "application/vnd.etsi.tsl+xml"
+)
+
+// grantedStatuses are the service statuses a credential issuer is trusted with, the last two are from before eIDAS
+var grantedStatuses = map[string]bool{
+	"http://uri.etsi.org/TrstSvc/TrustedList/Svcstatus/granted":                   true,
+	"http://uri.etsi.org/TrstSvc/TrustedList/Svcstatus/recognisedatnationallevel": true,
+	"http://uri.etsi.org/TrstSvc/TrustedList/Svcstatus/accredited":                true,
+	"http://uri.etsi.org/TrstSvc/TrustedList/Svcstatus/undersupervision":          true,
+}
+
+// trustServiceStatusList is the part of an ETSI TS 119 612 trusted list used here
+type trustServiceStatusList struct {
+	XMLName           xml.Name          `xml:"TrustServiceStatusList"`
+	SchemeInformation schemeInformation `xml:"SchemeInformation"`
+	Providers         []trustProvider   `xml:"TrustServiceProviderList>TrustServiceProvider"`
+}
+
+type schemeInformation struct {
+	Territory  string            `xml:"SchemeTerritory"`
+	NextUpdate string            `xml:"NextUpdate>dateTime"`
+	Pointers   []otherTSLPointer `xml:"PointersToOtherTSL>OtherTSLPointer"`
+}
+
+type otherTSLPointer struct {
+	Location   string            `xml:"TSLLocation"`
+	MimeTypes  []string          `xml:"AdditionalInformation>OtherInformation>MimeType"`
+	Identities []digitalIdentity `xml:"ServiceDigitalIdentities>ServiceDigitalIdentity"`
+}
+
+type digitalIdentity struct {
+	X509Certificates []string `xml:"DigitalId>X509Certificate"`
+}
+
+type trustProvider struct {
+	Names    []string     `xml:"TSPInformation>TSPName>Name"`
+	Services []tspService `xml:"TSPServices>TSPService"`
+}
+
+type tspService struct {
+	Type     string          `xml:"ServiceInformation>ServiceTypeIdentifier"`
+	Names    []string        `xml:"ServiceInformation>ServiceName>Name"`
+	Identity digitalIdentity `xml:"ServiceInformation>ServiceDigitalIdentity"`
+	Status   string          `xml:"ServiceInformation>ServiceStatus"`
+}
+
+// TrustedService is a trust service of a trusted list
+type TrustedService struct {
+	Territory   string
+	Provider    string
+	Name        string
+	Type        string
+	Status      string
+	Certificate *x509.Certificate
+}
+
+// parseTSL validates the enveloped signature of a trusted list with signers and parses it. A list is never trusted
+// unsigned, the signers are pinned in the configuration for the list of trusted lists and in its pointers for the
+// lists it points to.
+func parseTSL(b []byte, signers []*x509.Certificate, now time.Time) (*trustServiceStatusList, error) {
+	if len(signers) == 0 {
+		return nil, ErrNoSigners
+	}
+
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(b); err != nil {
+		return nil, err
+	}
+	if doc.Root() == nil {
+		return nil, errors.New("empty trusted list")
+	}
+
+	validationContext := dsig.NewDefaultValidationContext(&dsig.MemoryX509CertificateStore{Roots: signers})
+	validated, err := validationContext.Validate(doc.Root())
+	if err != nil {
+		return nil, fmt.Errorf("trusted list signature: %w", err)
+	}
+
+	// only what the signature covers is parsed
+	signed := etree.NewDocument()
+	signed.SetRoot(validated)
+	if b, err = signed.WriteToBytes(); err != nil {
+		return nil, err
+	}
+
+	tsl := &trustServiceStatusList{}
+	if err := xml.Unmarshal(b, tsl); err != nil {
+		return nil, err
+	}
+
+	nextUpdate, err := time.Parse(time.RFC3339, strings.TrimSpace(tsl.SchemeInformation.NextUpdate))
+	if err != nil {
+		return nil, fmt.Errorf("trusted list next update: %w", err)
+	}
+	if now.After(nextUpdate) {
+		return nil, fmt.Errorf("%w: %s", ErrListExpired, nextUpdate)
+	}
+
+	return tsl, nil
+}
+
+// pointers returns the XML trusted lists the list points to
+func (t *trustServiceStatusList) pointers() []otherTSLPointer {
+	var pointers []otherTSLPointer
+	for _, pointer := range t.SchemeInformation.Pointers {
+		isXML := strings.HasSuffix(strings.ToLower(pointer.Location), ".xml")
+		if len(pointer.MimeTypes) > 0 {
+			isXML = slices.ContainsFunc(pointer.MimeTypes, func(mimeType string) bool {
+				return strings.TrimSpace(mimeType) == mimeTypeTSL
+			})
+		}
+		if isXML {
+			pointers = append(pointers, pointer)
+		}
+	}
+
+	return pointers
+}
+
+// services returns the services of the list with a granted status
+func (t *trustServiceStatusList) services() ([]TrustedService, error) {
+	var services []TrustedService
+	for _, provider := range t.Providers {
+		for _, service := range provider.Services {
+			status := strings.TrimSpace(service.Status)
+			if !grantedStatuses[status] {
+				continue
+			}
+
+			certs, err := service.Identity.certificates()
+			if err != nil {
+				return nil, err
+			}
+			for _, cert := range certs {
+				services = append(services, TrustedService{
+					Territory:   t.SchemeInformation.Territory,
+					Provider:    first(provider.Names),
+					Name:        first(service.Names),
+					Type:        strings.TrimSpace(service.Type),
+					Status:      status,
+					Certificate: cert,
+				})
+			}
+		}
+	}
+
+	return services, nil
+}
+
+// certificates parses the base64 DER certificates of a digital identity
+func (d digitalIdentity) certificates() ([]*x509.Certificate, error) {
+	certs := make([]*x509.Certificate, 0, len(d.X509Certificates))
+	for _, s := range d.X509Certificates {
+		der, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(s), ""))
+		if err != nil {
+			return nil, err
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+
+	return certs, nil
+}
+
+func first(s []string) string {
+	if len(s) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(s[0])
+}
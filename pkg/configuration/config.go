@@ -8,6 +8,7 @@ import (
 	"vc/pkg/helpers"
 	"vc/pkg/logger"
 	"vc/pkg/model"
+	"vc/pkg/tenant"
 
 	"github.com/kelseyhightower/envconfig"
 	"gopkg.in/yaml.v2"
@@ -24,13 +25,26 @@ func New(ctx context.Context) (*model.Cfg, error) {
 	log := logger.NewSimple("Configuration")
 	log.Info("Read environmental variable")
 
+	configPath, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+
+	return load(ctx, configPath, log)
+}
+
+// configPath returns the config file path from the VC_CONFIG_YAML environment variable
+func configPath() (string, error) {
 	env := envVars{}
 	if err := envconfig.Process("", &env); err != nil {
-		return nil, err
+		return "", err
 	}
 
-	configPath := env.ConfigYAML
+	return env.ConfigYAML, nil
+}
 
+// load parses and validates the config file at configPath
+func load(ctx context.Context, configPath string, log *logger.Log) (*model.Cfg, error) {
 	cfg := &model.Cfg{}
 
 	if err := defaults.Set(cfg); err != nil {
@@ -55,9 +69,34 @@ func New(ctx context.Context) (*model.Cfg, error) {
 		return nil, err
 	}
 
+	if err := resolveSecrets(ctx, cfg); err != nil {
+		return nil, err
+	}
+
 	if err := helpers.Check(ctx, cfg, cfg, log); err != nil {
 		return nil, err
 	}
 
+	if err := checkTenants(cfg); err != nil {
+		return nil, err
+	}
+
+	if _, err := NewProfiles(cfg); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
+
+// checkTenants validates the tenants, apigw tells the tenants apart by the authenticated client so auth has to be enabled
+func checkTenants(cfg *model.Cfg) error {
+	if _, err := tenant.New(cfg.Common.Tenants); err != nil {
+		return err
+	}
+
+	if len(cfg.Common.Tenants) > 0 && !cfg.APIGW.APIServer.Auth.Enabled {
+		return errors.New("tenants require apigw.api_server.auth.enabled")
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package configuration
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"vc/pkg/helpers"
+	"vc/pkg/model"
+)
+
+// Profile is the issuance profile of one credential type
+type Profile struct {
+	model.CredentialProfile
+
+	// CredentialType is the name the profile is configured with
+	CredentialType string
+}
+
+// Supports reports if credentials of the profile can be issued in format
+func (p *Profile) Supports(format string) bool {
+	return slices.Contains(p.Formats, format)
+}
+
+// Profiles is the registry of the credential profiles of the config
+type Profiles struct {
+	byCredentialType map[string]*Profile
+	byDocumentType   map[string]*Profile
+}
+
+// NewProfiles creates the registry of the credential profiles of cfg, an error is returned if the claims of a profile collide
+func NewProfiles(cfg *model.Cfg) (*Profiles, error) {
+	p := &Profiles{
+		byCredentialType: map[string]*Profile{},
+		byDocumentType:   map[string]*Profile{},
+	}
+
+	credentialTypes := make([]string, 0, len(cfg.CredentialProfiles))
+	for credentialType := range cfg.CredentialProfiles {
+		credentialTypes = append(credentialTypes, credentialType)
+	}
+	sort.Strings(credentialTypes)
+
+	for _, credentialType := range credentialTypes {
+		profile := &Profile{
+			CredentialProfile: cfg.CredentialProfiles[credentialType],
+			CredentialType:    credentialType,
+		}
+		if profile.DocumentType == "" {
+			profile.DocumentType = credentialType
+		}
+		if len(profile.Formats) == 0 {
+			profile.Formats = []string{model.CredentialFormatSDJWT}
+		}
+		if err := checkClaims(profile.Claims); err != nil {
+			return nil, fmt.Errorf("credential_profiles.%s: %w", credentialType, err)
+		}
+
+		p.byCredentialType[credentialType] = profile
+		// the first credential type in order is the profile of a document type
+		if _, ok := p.byDocumentType[profile.DocumentType]; !ok {
+			p.byDocumentType[profile.DocumentType] = profile
+		}
+	}
+
+	return p, nil
+}
+
+// checkClaims checks that no claim is mapped twice, or is both a value and the parent of other claims
+func checkClaims(claims []model.ClaimMapping) error {
+	names := map[string]bool{}
+	for _, claim := range claims {
+		if names[claim.Claim] {
+			return fmt.Errorf("claim %q is mapped twice", claim.Claim)
+		}
+		names[claim.Claim] = true
+	}
+
+	for name := range names {
+		parts := strings.Split(name, ".")
+		for i := 1; i < len(parts); i++ {
+			if parent := strings.Join(parts[:i], "."); names[parent] {
+				return fmt.Errorf("claim %q is the parent of %q", parent, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Resolve returns the profile of credentialType, or the profile of documentType if credentialType is empty.
+// ErrUnknownCredentialType is returned if there is no such profile, or it is not made from documentType.
+// Without configured profiles nil is returned, and the issuer configuration applies to every credential.
+func (p *Profiles) Resolve(credentialType, documentType string) (*Profile, error) {
+	if len(p.byCredentialType) == 0 {
+		return nil, nil
+	}
+
+	if credentialType == "" {
+		profile, ok := p.byDocumentType[documentType]
+		if !ok {
+			return nil, helpers.ErrUnknownCredentialType
+		}
+		return profile, nil
+	}
+
+	profile, ok := p.byCredentialType[credentialType]
+	if !ok || (documentType != "" && profile.DocumentType != documentType) {
+		return nil, helpers.ErrUnknownCredentialType
+	}
+
+	return profile, nil
+}
+
+// All returns the profiles ordered by credential type
+func (p *Profiles) All() []*Profile {
+	profiles := make([]*Profile, 0, len(p.byCredentialType))
+	for _, profile := range p.byCredentialType {
+		profiles = append(profiles, profile)
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].CredentialType < profiles[j].CredentialType
+	})
+
+	return profiles
+}
@@ -0,0 +1,148 @@
+package configuration
+
+import (
+	"testing"
+	"vc/pkg/helpers"
+	"vc/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var mockProfilesCfg = &model.Cfg{
+	CredentialProfiles: map[string]model.CredentialProfile{
+		"EHIC": {},
+		"EHIC_CWT": {
+			DocumentType: "EHIC",
+			Formats:      []string{model.CredentialFormatCWT},
+		},
+		"diploma": {
+			DocumentType: "ELM",
+			VCT:          "https://credential.sunet.se/diploma",
+			Claims: []model.ClaimMapping{
+				{Claim: "student.name", Path: "/credentialSubject/name", SelectiveDisclosure: true},
+				{Claim: "title", Path: "/title"},
+			},
+		},
+	},
+}
+
+func TestResolveProfile(t *testing.T) {
+	profiles, err := NewProfiles(mockProfilesCfg)
+	assert.NoError(t, err)
+
+	tts := []struct {
+		name               string
+		credentialType     string
+		documentType       string
+		wantCredentialType string
+		wantFormats        []string
+		wantErr            error
+	}{
+		{
+			name:               "credential type",
+			credentialType:     "diploma",
+			documentType:       "ELM",
+			wantCredentialType: "diploma",
+			wantFormats:        []string{model.CredentialFormatSDJWT},
+		},
+		{
+			name:               "document type defaults to credential type",
+			credentialType:     "EHIC",
+			documentType:       "EHIC",
+			wantCredentialType: "EHIC",
+			wantFormats:        []string{model.CredentialFormatSDJWT},
+		},
+		{
+			name:               "same document type, other profile",
+			credentialType:     "EHIC_CWT",
+			documentType:       "EHIC",
+			wantCredentialType: "EHIC_CWT",
+			wantFormats:        []string{model.CredentialFormatCWT},
+		},
+		{
+			name:               "by document type, first credential type",
+			documentType:       "EHIC",
+			wantCredentialType: "EHIC",
+			wantFormats:        []string{model.CredentialFormatSDJWT},
+		},
+		{
+			name:           "other document type",
+			credentialType: "diploma",
+			documentType:   "PDA1",
+			wantErr:        helpers.ErrUnknownCredentialType,
+		},
+		{
+			name:           "unknown credential type",
+			credentialType: "SD-JWT",
+			documentType:   "EHIC",
+			wantErr:        helpers.ErrUnknownCredentialType,
+		},
+		{
+			name:         "unknown document type",
+			documentType: "PDA1",
+			wantErr:      helpers.ErrUnknownCredentialType,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := profiles.Resolve(tt.credentialType, tt.documentType)
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr != nil {
+				return
+			}
+			assert.Equal(t, tt.wantCredentialType, got.CredentialType)
+			assert.Equal(t, tt.wantFormats, got.Formats)
+		})
+	}
+}
+
+func TestResolveNoProfiles(t *testing.T) {
+	profiles, err := NewProfiles(&model.Cfg{})
+	assert.NoError(t, err)
+
+	got, err := profiles.Resolve("SD-JWT", "EHIC")
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestNewProfilesClaims(t *testing.T) {
+	tts := []struct {
+		name    string
+		claims  []model.ClaimMapping
+		wantErr bool
+	}{
+		{
+			name: "nested",
+			claims: []model.ClaimMapping{
+				{Claim: "a.b", Path: "/b"},
+				{Claim: "a.c", Path: "/c"},
+			},
+		},
+		{
+			name: "mapped twice",
+			claims: []model.ClaimMapping{
+				{Claim: "a", Path: "/a"},
+				{Claim: "a", Path: "/b"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "value and parent",
+			claims: []model.ClaimMapping{
+				{Claim: "a", Path: "/a"},
+				{Claim: "a.b", Path: "/b"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewProfiles(&model.Cfg{CredentialProfiles: map[string]model.CredentialProfile{
+				"test": {Claims: tt.claims},
+			}})
+			assert.Equal(t, tt.wantErr, err != nil, err)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package configuration
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+)
+
+// watchInterval is how often the config file modification time is checked
+const watchInterval = 10 * time.Second
+
+// Subscriber is notified with the new configuration after a successful reload
+type Subscriber func(ctx context.Context, cfg *model.Cfg) error
+
+type subscription struct {
+	name string
+	fn   Subscriber
+}
+
+// Watcher re-reads the configuration file on SIGHUP or when the file changes.
+// Only configurations that pass validation are passed on to the subscribers, an invalid file keeps the current configuration.
+type Watcher struct {
+	mu          sync.Mutex
+	cfg         *model.Cfg
+	path        string
+	modTime     time.Time
+	subscribers []subscription
+	log         *logger.Log
+}
+
+// NewWatcher starts watching the config file from VC_CONFIG_YAML, cfg is the configuration read at startup
+func NewWatcher(ctx context.Context, cfg *model.Cfg, log *logger.Log) (*Watcher, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{
+		cfg:  cfg,
+		path: path,
+		log:  log.New("configuration_watcher"),
+	}
+	if info, err := os.Stat(path); err == nil {
+		w.modTime = info.ModTime()
+	}
+
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+	ticker := time.NewTicker(watchInterval)
+
+	go func() {
+		for {
+			select {
+			case <-hupChan:
+				w.log.Info("SIGHUP, reloading configuration")
+				w.reload(ctx)
+			case <-ticker.C:
+				if w.changed() {
+					w.log.Info("Configuration file changed, reloading")
+					w.reload(ctx)
+				}
+			case <-ctx.Done():
+				signal.Stop(hupChan)
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return w, nil
+}
+
+// Subscribe registers fn to be notified on reload, subscribers are notified in the order they subscribed
+func (w *Watcher) Subscribe(name string, fn Subscriber) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.subscribers = append(w.subscribers, subscription{name: name, fn: fn})
+}
+
+// Current returns the latest valid configuration
+func (w *Watcher) Current() *model.Cfg {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.cfg
+}
+
+// changed reports if the config file modification time has changed since the last check
+func (w *Watcher) changed() bool {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return false
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if info.ModTime().Equal(w.modTime) {
+		return false
+	}
+	w.modTime = info.ModTime()
+
+	return true
+}
+
+// reload reads and validates the config file and notifies the subscribers
+func (w *Watcher) reload(ctx context.Context) {
+	cfg, err := load(ctx, w.path, w.log)
+	if err != nil {
+		w.log.Error(err, "Invalid configuration, keeping the current one")
+		return
+	}
+
+	w.notify(ctx, cfg)
+}
+
+func (w *Watcher) notify(ctx context.Context, cfg *model.Cfg) {
+	w.mu.Lock()
+	w.cfg = cfg
+	subscribers := append([]subscription{}, w.subscribers...)
+	w.mu.Unlock()
+
+	for _, s := range subscribers {
+		if err := s.fn(ctx, cfg); err != nil {
+			w.log.Error(err, "Subscriber failed to apply configuration", "subscriber", s.name)
+		}
+	}
+}
+
+// LogLevels is a Subscriber applying common.log.levels to log
+func LogLevels(log *logger.Log) Subscriber {
+	return func(ctx context.Context, cfg *model.Cfg) error {
+		return log.SetLevels(cfg.Common.Log.Levels)
+	}
+}
+
+// LogRedaction is a Subscriber applying common.log.redaction to log
+func LogRedaction(log *logger.Log) Subscriber {
+	return func(ctx context.Context, cfg *model.Cfg) error {
+		return log.SetRedaction(cfg.Common.Log.Redaction.Fields, cfg.Common.Log.Redaction.Allow)
+	}
+}
@@ -0,0 +1,72 @@
+package configuration
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatcherReload(t *testing.T) {
+	tts := []struct {
+		name       string
+		content    string
+		wantNotify bool
+	}{
+		{
+			name:       "invalid yaml",
+			content:    "common: [",
+			wantNotify: false,
+		},
+		{
+			name:       "missing required fields",
+			content:    "common:\n  production: true\n",
+			wantNotify: false,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			assert.NoError(t, os.WriteFile(path, []byte(tt.content), 0600))
+
+			current := &model.Cfg{}
+			w := &Watcher{cfg: current, path: path, log: logger.NewSimple("test")}
+
+			notified := false
+			w.Subscribe("test", func(ctx context.Context, cfg *model.Cfg) error {
+				notified = true
+				return nil
+			})
+
+			w.reload(context.Background())
+			assert.Equal(t, tt.wantNotify, notified)
+			assert.Same(t, current, w.Current())
+		})
+	}
+}
+
+func TestWatcherNotify(t *testing.T) {
+	w := &Watcher{cfg: &model.Cfg{}, log: logger.NewSimple("test")}
+
+	var order []string
+	w.Subscribe("first", func(ctx context.Context, cfg *model.Cfg) error {
+		order = append(order, "first")
+		return errors.New("failed")
+	})
+	w.Subscribe("second", func(ctx context.Context, cfg *model.Cfg) error {
+		order = append(order, "second")
+		return nil
+	})
+
+	next := &model.Cfg{}
+	w.notify(context.Background(), next)
+
+	assert.Equal(t, []string{"first", "second"}, order)
+	assert.Same(t, next, w.Current())
+}
@@ -0,0 +1,195 @@
+package configuration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+const (
+	secretPrefixFile  = "file://"
+	secretPrefixVault = "vault:"
+)
+
+type vaultEnvVars struct {
+	Addr  string `envconfig:"VAULT_ADDR" required:"true"`
+	Token string `envconfig:"VAULT_TOKEN" required:"true"`
+}
+
+// secretResolver replaces secret references in string config values with the secret itself
+type secretResolver struct {
+	ctx        context.Context
+	httpClient *http.Client
+	vault      *vaultEnvVars
+	// cache holds one read per vault secret path, a secret often holds several keys
+	cache map[string]map[string]any
+}
+
+// resolveSecrets walks v, a pointer to a struct, and resolves every string value that is a secret reference.
+// file:///run/secrets/x is replaced by the content of the file, vault:kv/path#key by the key of the KV v2 secret kv/path.
+func resolveSecrets(ctx context.Context, v any) error {
+	r := &secretResolver{
+		ctx:        ctx,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		cache:      map[string]map[string]any{},
+	}
+
+	return r.walk(reflect.ValueOf(v), "")
+}
+
+func (r *secretResolver) walk(v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return r.walk(v.Elem(), path)
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Type().Field(i).IsExported() {
+				continue
+			}
+			if err := r.walk(v.Field(i), path+"."+v.Type().Field(i).Name); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := r.walk(v.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		if v.Type().Elem().Kind() != reflect.String {
+			for _, key := range v.MapKeys() {
+				// map values are not addressable, walk a copy and store it back
+				elem := reflect.New(v.Type().Elem()).Elem()
+				elem.Set(v.MapIndex(key))
+				if err := r.walk(elem, fmt.Sprintf("%s[%v]", path, key)); err != nil {
+					return err
+				}
+				v.SetMapIndex(key, elem)
+			}
+			return nil
+		}
+		for _, key := range v.MapKeys() {
+			resolved, ok, err := r.resolve(v.MapIndex(key).String())
+			if err != nil {
+				return fmt.Errorf("%s[%v]: %w", path, key, err)
+			}
+			if ok {
+				v.SetMapIndex(key, reflect.ValueOf(resolved).Convert(v.Type().Elem()))
+			}
+		}
+
+	case reflect.String:
+		resolved, ok, err := r.resolve(v.String())
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if ok && v.CanSet() {
+			v.SetString(resolved)
+		}
+	}
+
+	return nil
+}
+
+// resolve returns the secret referenced by value, ok is false if value is not a reference
+func (r *secretResolver) resolve(value string) (string, bool, error) {
+	switch {
+	case strings.HasPrefix(value, secretPrefixFile):
+		b, err := os.ReadFile(filepath.Clean(strings.TrimPrefix(value, secretPrefixFile)))
+		if err != nil {
+			return "", false, err
+		}
+		return strings.TrimRight(string(b), "\r\n"), true, nil
+
+	case strings.HasPrefix(value, secretPrefixVault):
+		secret, err := r.vaultSecret(strings.TrimPrefix(value, secretPrefixVault))
+		if err != nil {
+			return "", false, err
+		}
+		return secret, true, nil
+	}
+
+	return "", false, nil
+}
+
+// vaultSecret reads ref, on the form mount/path#key, from a Vault KV v2 secrets engine
+func (r *secretResolver) vaultSecret(ref string) (string, error) {
+	secretPath, key, found := strings.Cut(ref, "#")
+	if !found || key == "" {
+		return "", fmt.Errorf("vault reference %q has no #key", ref)
+	}
+	mount, path, found := strings.Cut(secretPath, "/")
+	if !found || path == "" {
+		return "", fmt.Errorf("vault reference %q has no secret path", ref)
+	}
+
+	data, ok := r.cache[secretPath]
+	if !ok {
+		var err error
+		data, err = r.vaultRead(mount, path)
+		if err != nil {
+			return "", err
+		}
+		r.cache[secretPath] = data
+	}
+
+	value, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("vault secret %q has no key %q", secretPath, key)
+	}
+
+	return fmt.Sprint(value), nil
+}
+
+func (r *secretResolver) vaultRead(mount, path string) (map[string]any, error) {
+	if r.vault == nil {
+		env := &vaultEnvVars{}
+		if err := envconfig.Process("", env); err != nil {
+			return nil, err
+		}
+		r.vault = env
+	}
+
+	url := fmt.Sprintf("%s/v1/%s/data/%s", strings.TrimSuffix(r.vault.Addr, "/"), mount, path)
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", r.vault.Token)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault read %s/%s: %s", mount, path, resp.Status)
+	}
+
+	reply := struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		return nil, err
+	}
+
+	return reply.Data.Data, nil
+}
@@ -0,0 +1,90 @@
+package configuration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSecretCfg struct {
+	Password string
+	URIs     []string
+	Levels   map[string]string
+	Nested   *struct{ Token string }
+}
+
+func TestResolveSecrets(t *testing.T) {
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "test-token" || r.URL.Path != "/v1/kv/data/vc/mongo" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"data":{"data":{"password":"vault-secret"}}}`))
+	}))
+	defer vault.Close()
+	t.Setenv("VAULT_ADDR", vault.URL)
+	t.Setenv("VAULT_TOKEN", "test-token")
+
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	assert.NoError(t, os.WriteFile(secretFile, []byte("file-secret\n"), 0600))
+
+	tts := []struct {
+		name    string
+		cfg     *mockSecretCfg
+		want    *mockSecretCfg
+		wantErr bool
+	}{
+		{
+			name: "plain values",
+			cfg:  &mockSecretCfg{Password: "plain", URIs: []string{"mongodb://mongo"}},
+			want: &mockSecretCfg{Password: "plain", URIs: []string{"mongodb://mongo"}},
+		},
+		{
+			name: "file and vault references",
+			cfg: &mockSecretCfg{
+				Password: "vault:kv/vc/mongo#password",
+				URIs:     []string{"file://" + secretFile},
+				Levels:   map[string]string{"a": "file://" + secretFile},
+				Nested:   &struct{ Token string }{Token: "vault:kv/vc/mongo#password"},
+			},
+			want: &mockSecretCfg{
+				Password: "vault-secret",
+				URIs:     []string{"file-secret"},
+				Levels:   map[string]string{"a": "file-secret"},
+				Nested:   &struct{ Token string }{Token: "vault-secret"},
+			},
+		},
+		{
+			name:    "missing vault key",
+			cfg:     &mockSecretCfg{Password: "vault:kv/vc/mongo#username"},
+			wantErr: true,
+		},
+		{
+			name:    "vault reference without key",
+			cfg:     &mockSecretCfg{Password: "vault:kv/vc/mongo"},
+			wantErr: true,
+		},
+		{
+			name:    "missing file",
+			cfg:     &mockSecretCfg{Password: "file:///nonexistent/secret"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			err := resolveSecrets(context.Background(), tt.cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, tt.cfg)
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package cose
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+	"time"
+	"vc/pkg/signing"
+	"vc/pkg/statuslist"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockSigners(t *testing.T) map[string]*signing.Signer {
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	assert.NoError(t, err)
+	p521, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	assert.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	signers := map[string]*signing.Signer{}
+	for name, key := range map[string]crypto.Signer{"ES256": p256, "ES384": p384, "ES512": p521, "EdDSA": edKey} {
+		signers[name], err = signing.NewSoftware(key)
+		assert.NoError(t, err)
+	}
+	return signers
+}
+
+func TestKeyRoundTrip(t *testing.T) {
+	for name, signer := range mockSigners(t) {
+		t.Run(name, func(t *testing.T) {
+			key, err := NewKey(signer.Public(), "kid-1")
+			assert.NoError(t, err)
+			assert.Equal(t, algorithms[name], key.Alg)
+
+			b, err := cbor.Marshal(key)
+			assert.NoError(t, err)
+
+			decoded := &Key{}
+			assert.NoError(t, cbor.Unmarshal(b, decoded))
+			assert.Equal(t, []byte("kid-1"), decoded.KID)
+
+			publicKey, err := decoded.PublicKey()
+			assert.NoError(t, err)
+			assert.True(t, publicKey.(interface{ Equal(crypto.PublicKey) bool }).Equal(signer.Public()))
+		})
+	}
+
+	_, err := (&Key{KeyType: KeyTypeEC2, Curve: CurveP256, X: []byte{1}, Y: []byte{2}}).PublicKey()
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestSign1(t *testing.T) {
+	signers := mockSigners(t)
+	for name, signer := range signers {
+		t.Run(name, func(t *testing.T) {
+			data, err := SignSign1(signer, "kid-1", []byte("payload"))
+			assert.NoError(t, err)
+
+			msg, err := VerifySign1(data, signer.Public())
+			assert.NoError(t, err)
+			assert.Equal(t, algorithms[name], msg.Alg)
+			assert.Equal(t, "kid-1", msg.KID)
+			assert.Equal(t, []byte("payload"), msg.Payload)
+
+			other := signers["ES256"]
+			if name == "ES256" {
+				other = signers["EdDSA"]
+			}
+			_, err = VerifySign1(data, other.Public())
+			assert.ErrorIs(t, err, ErrInvalidSignature)
+		})
+	}
+
+	_, err := ParseSign1([]byte{0x01})
+	assert.ErrorIs(t, err, ErrInvalidMessage)
+}
+
+func TestSign1Detached(t *testing.T) {
+	signer := mockSigners(t)["ES256"]
+
+	tts := []struct {
+		name    string
+		x5chain [][]byte
+	}{
+		{name: "no x5chain"},
+		{name: "single certificate", x5chain: [][]byte{[]byte("leaf")}},
+		{name: "chain", x5chain: [][]byte{[]byte("leaf"), []byte("intermediate")}},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := SignSign1Detached(signer, tt.x5chain, []byte("payload"))
+			assert.NoError(t, err)
+
+			msg, err := ParseSign1(data)
+			assert.NoError(t, err)
+			assert.Nil(t, msg.Payload)
+			assert.Equal(t, tt.x5chain, msg.X5Chain)
+
+			assert.NoError(t, msg.VerifyDetached(signer.Public(), []byte("payload")))
+			assert.ErrorIs(t, msg.VerifyDetached(signer.Public(), []byte("other")), ErrInvalidSignature)
+		})
+	}
+}
+
+func TestCWT(t *testing.T) {
+	signer := mockSigners(t)["ES256"]
+	now := time.Now()
+
+	holder, err := NewKey(signer.Public(), "")
+	assert.NoError(t, err)
+
+	data, err := SignCWT(signer, "kid-1", &Claims{
+		Issuer:       "https://issuer.example.com",
+		IssuedAt:     now.Unix(),
+		NotBefore:    now.Unix(),
+		ExpiresAt:    now.Add(time.Hour).Unix(),
+		Confirmation: &Confirmation{Key: holder},
+		Status:       &statuslist.Status{StatusList: &statuslist.StatusReference{Idx: 7, URI: "https://registry.example.com/statuslists/1"}},
+		Private: map[string]any{
+			"vct":        "urn:eu.europa.ec.eudi:ehic:1",
+			"given_name": "Alice",
+		},
+	})
+	assert.NoError(t, err)
+
+	claims, err := VerifyCWT(data, signer.Public(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://issuer.example.com", claims.Issuer)
+	assert.Equal(t, "Alice", claims.Private["given_name"])
+	assert.Equal(t, "urn:eu.europa.ec.eudi:ehic:1", claims.Private["vct"])
+	assert.Equal(t, int64(7), claims.Status.StatusList.Idx)
+	assert.Equal(t, holder.X, claims.Confirmation.Key.X)
+
+	_, err = VerifyCWT(data, signer.Public(), now.Add(2*time.Hour))
+	assert.ErrorIs(t, err, ErrTokenExpired)
+
+	_, err = VerifyCWT(data, signer.Public(), now.Add(-time.Hour))
+	assert.ErrorIs(t, err, ErrTokenNotYetValid)
+
+	// the optional CWT tag is accepted
+	tagged, err := cbor.Marshal(cbor.RawTag{Number: tagCWT, Content: data})
+	assert.NoError(t, err)
+	_, err = VerifyCWT(tagged, signer.Public(), now)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,140 @@
+package cose
+
+import (
+	"crypto"
+	"errors"
+	"time"
+	"vc/pkg/signing"
+	"vc/pkg/statuslist"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// tagCWT is the optional CBOR tag of a CWT, RFC 8392 section 6
+const tagCWT = 61
+
+var (
+	// ErrTokenExpired is returned when the exp claim of a CWT is in the past
+	ErrTokenExpired = errors.New("cwt expired")
+
+	// ErrTokenNotYetValid is returned when the nbf claim of a CWT is in the future
+	ErrTokenNotYetValid = errors.New("cwt not yet valid")
+)
+
+// Confirmation is the cnf claim binding the token to a holder key, RFC 8747
+type Confirmation struct {
+	Key *Key `cbor:"1,keyasint,omitempty"`
+}
+
+// Claims are the registered CWT claims, RFC 8392, and the status claim.
+// Private claims, like the credential data, are kept by name in Private.
+type Claims struct {
+	Issuer       string             `cbor:"1,keyasint,omitempty"`
+	Subject      string             `cbor:"2,keyasint,omitempty"`
+	Audience     string             `cbor:"3,keyasint,omitempty"`
+	ExpiresAt    int64              `cbor:"4,keyasint,omitempty"`
+	NotBefore    int64              `cbor:"5,keyasint,omitempty"`
+	IssuedAt     int64              `cbor:"6,keyasint,omitempty"`
+	CWTID        []byte             `cbor:"7,keyasint,omitempty"`
+	Confirmation *Confirmation      `cbor:"8,keyasint,omitempty"`
+	Status       *statuslist.Status `cbor:"65535,keyasint,omitempty"`
+
+	Private map[string]any `cbor:"-"`
+}
+
+// registeredClaims has the cbor encoding of Claims without the private claims
+type registeredClaims Claims
+
+// MarshalCBOR encodes the registered and the private claims in one map
+func (c *Claims) MarshalCBOR() ([]byte, error) {
+	b, err := encMode.Marshal((*registeredClaims)(c))
+	if err != nil {
+		return nil, err
+	}
+	if len(c.Private) == 0 {
+		return b, nil
+	}
+
+	claims := map[any]any{}
+	if err := cbor.Unmarshal(b, &claims); err != nil {
+		return nil, err
+	}
+	for name, value := range c.Private {
+		claims[name] = value
+	}
+
+	return encMode.Marshal(claims)
+}
+
+// UnmarshalCBOR decodes the registered claims, claims with a text key are put in Private
+func (c *Claims) UnmarshalCBOR(b []byte) error {
+	if err := cbor.Unmarshal(b, (*registeredClaims)(c)); err != nil {
+		return err
+	}
+
+	claims := map[any]any{}
+	if err := cbor.Unmarshal(b, &claims); err != nil {
+		return err
+	}
+	for key, value := range claims {
+		if name, ok := key.(string); ok {
+			if c.Private == nil {
+				c.Private = map[string]any{}
+			}
+			c.Private[name] = value
+		}
+	}
+
+	return nil
+}
+
+// SignCWT signs claims as a CWT, a COSE_Sign1 message with the claims as payload
+func SignCWT(signer *signing.Signer, kid string, claims *Claims) ([]byte, error) {
+	payload, err := encMode.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return SignSign1(signer, kid, payload)
+}
+
+// ParseCWT returns the COSE_Sign1 message and the claims of a CWT without verifying it
+func ParseCWT(data []byte) (*Sign1, *Claims, error) {
+	var raw cbor.RawTag
+	if err := cbor.Unmarshal(data, &raw); err == nil && raw.Number == tagCWT {
+		data = raw.Content
+	}
+
+	msg, err := ParseSign1(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	claims := &Claims{}
+	if err := cbor.Unmarshal(msg.Payload, claims); err != nil {
+		return nil, nil, ErrInvalidMessage
+	}
+
+	return msg, claims, nil
+}
+
+// VerifyCWT verifies the signature of a CWT with publicKey and its validity at now
+func VerifyCWT(data []byte, publicKey crypto.PublicKey, now time.Time) (*Claims, error) {
+	msg, claims, err := ParseCWT(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := msg.Verify(publicKey); err != nil {
+		return nil, err
+	}
+
+	if claims.ExpiresAt != 0 && !now.Before(time.Unix(claims.ExpiresAt, 0)) {
+		return nil, ErrTokenExpired
+	}
+	if claims.NotBefore != 0 && now.Before(time.Unix(claims.NotBefore, 0)) {
+		return nil, ErrTokenNotYetValid
+	}
+
+	return claims, nil
+}
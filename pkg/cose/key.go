@@ -0,0 +1,105 @@
+package cose
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+)
+
+// COSE key types and curves, RFC 9053
+const (
+	KeyTypeOKP = 1
+	KeyTypeEC2 = 2
+
+	CurveP256    = 1
+	CurveP384    = 2
+	CurveP521    = 3
+	CurveEd25519 = 6
+)
+
+var (
+	// ErrUnsupportedKey is returned for keys and algorithms COSE signing is not supported for
+	ErrUnsupportedKey = errors.New("unsupported cose key")
+
+	// ErrInvalidKey is returned when a COSE_Key does not hold a valid public key
+	ErrInvalidKey = errors.New("invalid cose key")
+)
+
+// Key is a public COSE_Key, RFC 9052 section 7
+type Key struct {
+	KeyType int64  `cbor:"1,keyasint"`
+	KID     []byte `cbor:"2,keyasint,omitempty"`
+	Alg     int64  `cbor:"3,keyasint,omitempty"`
+	Curve   int64  `cbor:"-1,keyasint"`
+	X       []byte `cbor:"-2,keyasint"`
+	Y       []byte `cbor:"-3,keyasint,omitempty"`
+}
+
+// NewKey returns the COSE_Key of an ECDSA or Ed25519 public key
+func NewKey(publicKey crypto.PublicKey, kid string) (*Key, error) {
+	key := &Key{}
+	if kid != "" {
+		key.KID = []byte(kid)
+	}
+
+	switch pub := publicKey.(type) {
+	case ed25519.PublicKey:
+		key.KeyType, key.Curve, key.Alg = KeyTypeOKP, CurveEd25519, AlgEdDSA
+		key.X = pub
+	case *ecdsa.PublicKey:
+		key.KeyType = KeyTypeEC2
+		switch pub.Curve {
+		case elliptic.P256():
+			key.Curve, key.Alg = CurveP256, AlgES256
+		case elliptic.P384():
+			key.Curve, key.Alg = CurveP384, AlgES384
+		case elliptic.P521():
+			key.Curve, key.Alg = CurveP521, AlgES512
+		default:
+			return nil, ErrUnsupportedKey
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		key.X = pub.X.FillBytes(make([]byte, size))
+		key.Y = pub.Y.FillBytes(make([]byte, size))
+	default:
+		return nil, ErrUnsupportedKey
+	}
+
+	return key, nil
+}
+
+// PublicKey returns the public key of the COSE_Key
+func (k *Key) PublicKey() (crypto.PublicKey, error) {
+	switch k.KeyType {
+	case KeyTypeOKP:
+		if k.Curve != CurveEd25519 {
+			return nil, ErrUnsupportedKey
+		}
+		if len(k.X) != ed25519.PublicKeySize {
+			return nil, ErrInvalidKey
+		}
+		return ed25519.PublicKey(k.X), nil
+	case KeyTypeEC2:
+		var curve elliptic.Curve
+		switch k.Curve {
+		case CurveP256:
+			curve = elliptic.P256()
+		case CurveP384:
+			curve = elliptic.P384()
+		case CurveP521:
+			curve = elliptic.P521()
+		default:
+			return nil, ErrUnsupportedKey
+		}
+		pub := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(k.X), Y: new(big.Int).SetBytes(k.Y)}
+		if !curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, ErrInvalidKey
+		}
+		return pub, nil
+	default:
+		return nil, ErrUnsupportedKey
+	}
+}
@@ -0,0 +1,267 @@
+package cose
+
+import (
+	"crypto"
+	"errors"
+	"vc/pkg/signing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// COSE algorithms, RFC 9053 and RFC 8812
+const (
+	AlgES256 = -7
+	AlgEdDSA = -8
+	AlgES384 = -35
+	AlgES512 = -36
+	AlgRS256 = -257
+)
+
+// COSE header labels
+const (
+	headerAlg     = 1
+	headerKID     = 4
+	headerX5Chain = 33
+)
+
+// tagSign1 is the CBOR tag of a COSE_Sign1 message
+const tagSign1 = 18
+
+var (
+	// ErrInvalidMessage is returned when the data is not a COSE_Sign1 message
+	ErrInvalidMessage = errors.New("invalid cose_sign1 message")
+
+	// ErrInvalidSignature is returned when the signature does not verify with the key
+	ErrInvalidSignature = errors.New("invalid cose signature")
+)
+
+// algorithms maps the JOSE algorithm name of a signing key to its COSE algorithm
+var algorithms = map[string]int64{
+	"ES256": AlgES256,
+	"ES384": AlgES384,
+	"ES512": AlgES512,
+	"EdDSA": AlgEdDSA,
+	"RS256": AlgRS256,
+}
+
+// joseName returns the JOSE algorithm name of a COSE algorithm
+func joseName(alg int64) (string, bool) {
+	for name, a := range algorithms {
+		if a == alg {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+var encMode, _ = cbor.CoreDetEncOptions().EncMode()
+
+// header is a COSE header map with the labels used here
+type header struct {
+	Alg     int64           `cbor:"1,keyasint,omitempty"`
+	KID     []byte          `cbor:"4,keyasint,omitempty"`
+	X5Chain cbor.RawMessage `cbor:"33,keyasint,omitempty"`
+}
+
+// sign1 is the untagged COSE_Sign1 array
+type sign1 struct {
+	_           struct{} `cbor:",toarray"`
+	Protected   []byte
+	Unprotected header
+	Payload     []byte
+	Signature   []byte
+}
+
+// Sign1 is a verified or parsed COSE_Sign1 message, RFC 9052 section 4.2
+type Sign1 struct {
+	Alg     int64
+	KID     string
+	Payload []byte
+
+	// X5Chain holds the DER certificates of the x5chain header, leaf first, RFC 9360
+	X5Chain [][]byte
+
+	protected []byte
+	signature []byte
+}
+
+// sigStructure returns the Sig_structure the signature is calculated over, without external aad
+func sigStructure(protected, payload []byte) ([]byte, error) {
+	return encMode.Marshal([]any{"Signature1", protected, []byte{}, payload})
+}
+
+// SignSign1 signs payload as a tagged COSE_Sign1 message, kid is put in the unprotected header
+func SignSign1(signer *signing.Signer, kid string, payload []byte) ([]byte, error) {
+	unprotected := header{}
+	if kid != "" {
+		unprotected.KID = []byte(kid)
+	}
+
+	return sign(signer, unprotected, payload, false)
+}
+
+// SignSign1Detached signs payload as a tagged COSE_Sign1 message with a nil payload, x5chain holds DER certificates, leaf first, and is put in the unprotected header
+func SignSign1Detached(signer *signing.Signer, x5chain [][]byte, payload []byte) ([]byte, error) {
+	unprotected := header{}
+	switch len(x5chain) {
+	case 0:
+	case 1:
+		// a single certificate is encoded as a bstr, not an array
+		b, err := encMode.Marshal(x5chain[0])
+		if err != nil {
+			return nil, err
+		}
+		unprotected.X5Chain = b
+	default:
+		b, err := encMode.Marshal(x5chain)
+		if err != nil {
+			return nil, err
+		}
+		unprotected.X5Chain = b
+	}
+
+	return sign(signer, unprotected, payload, true)
+}
+
+func sign(signer *signing.Signer, unprotected header, payload []byte, detached bool) ([]byte, error) {
+	alg, ok := algorithms[signer.Algorithm()]
+	if !ok {
+		return nil, ErrUnsupportedKey
+	}
+
+	protected, err := encMode.Marshal(header{Alg: alg})
+	if err != nil {
+		return nil, err
+	}
+
+	toBeSigned, err := sigStructure(protected, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	// the JWS signing method hashes the data and encodes ECDSA signatures as r || s, the same as COSE
+	signature, err := signer.SigningMethod().Sign(string(toBeSigned), signer)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := sign1{
+		Protected:   protected,
+		Unprotected: unprotected,
+		Payload:     payload,
+		Signature:   signature,
+	}
+	if detached {
+		msg.Payload = nil
+	}
+
+	return encMode.Marshal(cbor.Tag{Number: tagSign1, Content: msg})
+}
+
+// ParseSign1 parses a tagged or untagged COSE_Sign1 message without verifying its signature
+func ParseSign1(data []byte) (*Sign1, error) {
+	var raw cbor.RawTag
+	if err := cbor.Unmarshal(data, &raw); err == nil {
+		if raw.Number != tagSign1 {
+			return nil, ErrInvalidMessage
+		}
+		data = raw.Content
+	}
+
+	msg := sign1{}
+	if err := cbor.Unmarshal(data, &msg); err != nil {
+		return nil, ErrInvalidMessage
+	}
+
+	protected := header{}
+	if len(msg.Protected) > 0 {
+		if err := cbor.Unmarshal(msg.Protected, &protected); err != nil {
+			return nil, ErrInvalidMessage
+		}
+	}
+
+	kid := protected.KID
+	if kid == nil {
+		kid = msg.Unprotected.KID
+	}
+
+	rawX5Chain := protected.X5Chain
+	if rawX5Chain == nil {
+		rawX5Chain = msg.Unprotected.X5Chain
+	}
+	x5chain, err := parseX5Chain(rawX5Chain)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Sign1{
+		Alg:       protected.Alg,
+		KID:       string(kid),
+		Payload:   msg.Payload,
+		X5Chain:   x5chain,
+		protected: msg.Protected,
+		signature: msg.Signature,
+	}, nil
+}
+
+// parseX5Chain parses an x5chain header, a bstr for a single certificate or an array of bstr
+func parseX5Chain(raw cbor.RawMessage) ([][]byte, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	var cert []byte
+	if err := cbor.Unmarshal(raw, &cert); err == nil {
+		return [][]byte{cert}, nil
+	}
+
+	var chain [][]byte
+	if err := cbor.Unmarshal(raw, &chain); err != nil {
+		return nil, ErrInvalidMessage
+	}
+
+	return chain, nil
+}
+
+// Verify verifies the signature of the message with publicKey
+func (s *Sign1) Verify(publicKey crypto.PublicKey) error {
+	return s.verify(publicKey, s.Payload)
+}
+
+// VerifyDetached verifies the signature of a message with a detached payload with publicKey
+func (s *Sign1) VerifyDetached(publicKey crypto.PublicKey, payload []byte) error {
+	return s.verify(publicKey, payload)
+}
+
+func (s *Sign1) verify(publicKey crypto.PublicKey, payload []byte) error {
+	name, ok := joseName(s.Alg)
+	if !ok {
+		return ErrUnsupportedKey
+	}
+
+	toBeSigned, err := sigStructure(s.protected, payload)
+	if err != nil {
+		return err
+	}
+
+	if err := jwt.GetSigningMethod(name).Verify(string(toBeSigned), s.signature, publicKey); err != nil {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// VerifySign1 parses a COSE_Sign1 message and verifies it with publicKey
+func VerifySign1(data []byte, publicKey crypto.PublicKey) (*Sign1, error) {
+	msg, err := ParseSign1(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := msg.Verify(publicKey); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
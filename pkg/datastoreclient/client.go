@@ -122,6 +122,8 @@ func checkResponse(r *http.Response) error {
 		return ErrInvalidRequest
 	case 401:
 		return ErrNotAllowedRequest
+	case 404:
+		return ErrNotFound
 	}
 
 	return ErrInvalidRequest
@@ -6,6 +6,9 @@ var (
 	// ErrInvalidRequest is returned when the request is invalid
 	ErrInvalidRequest = errors.New("Invalid request")
 
+	// ErrNotFound is returned when the requested resource does not exist
+	ErrNotFound = errors.New("Not found")
+
 	// ErrNotAllowedRequest is returned when the request is not allowed
 	ErrNotAllowedRequest = errors.New("Not allowed request")
 )
@@ -0,0 +1,23 @@
+package documenttype
+
+import (
+	"context"
+)
+
+func init() {
+	Register(&ehic{Base{Name: "EHIC"}})
+}
+
+// ehic is the European health insurance card, the card must be valid before it expires
+type ehic struct {
+	Base
+}
+
+// Validate implements Module
+func (m *ehic) Validate(ctx context.Context, data map[string]any) error {
+	v := violations{}
+	v.notBefore(data, dateLayout, "/cardInformation/issuanceDate", "/cardInformation/expiryDate")
+	v.notBefore(data, dateLayout, "/cardInformation/validSince", "/cardInformation/expiryDate")
+
+	return v.err()
+}
@@ -0,0 +1,5 @@
+package documenttype
+
+func init() {
+	Register(Base{Name: "ELM"})
+}
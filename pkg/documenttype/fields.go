@@ -0,0 +1,118 @@
+package documenttype
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// dateLayout is the layout of full-date values, e.g. birth dates
+	dateLayout = time.DateOnly
+)
+
+// lookup returns the value at pointer in data, a JSON pointer like /cardInformation/expiryDate or
+// /driving_privileges/0/issue_date
+func lookup(data map[string]any, pointer string) (any, bool) {
+	var value any = data
+	for _, name := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		switch v := value.(type) {
+		case map[string]any:
+			var ok bool
+			value, ok = v[name]
+			if !ok {
+				return nil, false
+			}
+		case []any:
+			i, err := strconv.Atoi(name)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// timeAt returns the time at pointer parsed with layout, false if it is missing or malformed, which the schema
+// reports
+func timeAt(data map[string]any, pointer, layout string) (time.Time, bool) {
+	value, ok := lookup(data, pointer)
+	if !ok {
+		return time.Time{}, false
+	}
+	s, ok := value.(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
+
+// violations collects the violations of one document_data
+type violations []Violation
+
+func (v *violations) add(pointer, format string, args ...any) {
+	*v = append(*v, Violation{Path: pointer, Message: fmt.Sprintf(format, args...)})
+}
+
+// notBefore adds a violation if the time at to is before the time at from
+func (v *violations) notBefore(data map[string]any, layout, from, to string) {
+	fromTime, ok := timeAt(data, from, layout)
+	if !ok {
+		return
+	}
+	toTime, ok := timeAt(data, to, layout)
+	if !ok {
+		return
+	}
+	if toTime.Before(fromTime) {
+		v.add(to, "is before %s", from)
+	}
+}
+
+// notFuture adds a violation if the date at pointer is after now
+func (v *violations) notFuture(data map[string]any, pointer string, now time.Time) {
+	t, ok := timeAt(data, pointer, dateLayout)
+	if !ok {
+		return
+	}
+	if t.After(now) {
+		v.add(pointer, "is in the future")
+	}
+}
+
+func (v violations) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: v}
+}
+
+// setAge sets age_in_years, age_birth_year and age_over_NN for each of overs in data, from the birth date at
+// pointer. Nothing is set without a birth date.
+func setAge(data map[string]any, pointer string, now time.Time, overs ...int) {
+	birth, ok := timeAt(data, pointer, dateLayout)
+	if !ok {
+		return
+	}
+
+	age := now.Year() - birth.Year()
+	if now.Month() < birth.Month() || now.Month() == birth.Month() && now.Day() < birth.Day() {
+		age--
+	}
+
+	data["age_in_years"] = age
+	data["age_birth_year"] = birth.Year()
+	for _, over := range overs {
+		data[fmt.Sprintf("age_over_%d", over)] = age >= over
+	}
+}
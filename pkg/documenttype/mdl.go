@@ -0,0 +1,37 @@
+package documenttype
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+func init() {
+	Register(&mdl{Base{Name: "mDL"}})
+}
+
+// mdl is the mobile driving licence of ISO/IEC 18013-5
+type mdl struct {
+	Base
+}
+
+// Validate implements Module, the licence and each driving privilege must be issued before they expire
+func (m *mdl) Validate(ctx context.Context, data map[string]any) error {
+	v := violations{}
+	v.notFuture(data, "/birth_date", time.Now())
+	v.notBefore(data, dateLayout, "/issue_date", "/expiry_date")
+
+	privileges, _ := data["driving_privileges"].([]any)
+	for i := range privileges {
+		pointer := fmt.Sprintf("/driving_privileges/%d", i)
+		v.notBefore(data, dateLayout, pointer+"/issue_date", pointer+"/expiry_date")
+	}
+
+	return v.err()
+}
+
+// Enrich adds the optional age data elements age_over_18, age_over_21, age_in_years and age_birth_year
+func (m *mdl) Enrich(ctx context.Context, data map[string]any, now time.Time) error {
+	setAge(data, "/birth_date", now, 18, 21)
+	return nil
+}
@@ -0,0 +1,23 @@
+package documenttype
+
+import (
+	"context"
+	"time"
+)
+
+func init() {
+	Register(&pda1{Base{Name: "PDA1"}})
+}
+
+// pda1 is the portable document A1, the period the legislation applies ends after it starts
+type pda1 struct {
+	Base
+}
+
+// Validate implements Module
+func (m *pda1) Validate(ctx context.Context, data map[string]any) error {
+	v := violations{}
+	v.notBefore(data, time.RFC3339, "/memberStateLegislation/startingDate", "/memberStateLegislation/endingDate")
+
+	return v.err()
+}
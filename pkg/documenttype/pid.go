@@ -0,0 +1,30 @@
+package documenttype
+
+import (
+	"context"
+	"time"
+)
+
+func init() {
+	Register(&pid{Base{Name: "PID"}})
+}
+
+// pid is the person identification data of the EUDI wallet PID rulebook
+type pid struct {
+	Base
+}
+
+// Validate implements Module
+func (m *pid) Validate(ctx context.Context, data map[string]any) error {
+	v := violations{}
+	v.notFuture(data, "/birth_date", time.Now())
+	v.notBefore(data, dateLayout, "/issuance_date", "/expiry_date")
+
+	return v.err()
+}
+
+// Enrich adds the age attributes of the rulebook, age_over_18, age_in_years and age_birth_year
+func (m *pid) Enrich(ctx context.Context, data map[string]any, now time.Time) error {
+	setAge(data, "/birth_date", now, 18)
+	return nil
+}
@@ -0,0 +1,124 @@
+package documenttype
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Module is the handling of one document_type in the apigw, modules are registered at compile time with Register,
+// usually from the init function of the package that implements them
+type Module interface {
+	// DocumentType is the document_type the module handles, e.g. EHIC
+	DocumentType() string
+
+	// Validate checks document_data on upload and import, after schema validation. Invalid data is reported with a
+	// *ValidationError.
+	Validate(ctx context.Context, data map[string]any) error
+
+	// Enrich adds values derived from document_data before a credential is issued from it, e.g. age_over_18 from the
+	// birth date. The stored document is not changed.
+	Enrich(ctx context.Context, data map[string]any, now time.Time) error
+
+	// MapToCredential returns the document_data the issuer makes the credential from
+	MapToCredential(ctx context.Context, data map[string]any) (map[string]any, error)
+}
+
+// Base implements Module without validation or enrichment, the document_data is issued as it is. Modules embed it
+// and implement the methods they need.
+type Base struct {
+	Name string
+}
+
+// DocumentType implements Module
+func (b Base) DocumentType() string {
+	return b.Name
+}
+
+// Validate implements Module
+func (b Base) Validate(ctx context.Context, data map[string]any) error {
+	return nil
+}
+
+// Enrich implements Module
+func (b Base) Enrich(ctx context.Context, data map[string]any, now time.Time) error {
+	return nil
+}
+
+// MapToCredential implements Module
+func (b Base) MapToCredential(ctx context.Context, data map[string]any) (map[string]any, error) {
+	return data, nil
+}
+
+var (
+	mu      sync.RWMutex
+	modules = map[string]Module{}
+)
+
+// Register makes module available by its document_type, it panics if the document_type is empty or already
+// registered
+func Register(module Module) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	name := module.DocumentType()
+	if name == "" {
+		panic("documenttype: Register of a module without document_type")
+	}
+	if _, ok := modules[name]; ok {
+		panic("documenttype: Register called twice for " + name)
+	}
+	modules[name] = module
+}
+
+// Lookup returns the module of documentType
+func Lookup(documentType string) (Module, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	module, ok := modules[documentType]
+	return module, ok
+}
+
+// Registered reports if documentType has a module
+func Registered(documentType string) bool {
+	_, ok := Lookup(documentType)
+	return ok
+}
+
+// DocumentTypes returns the registered document types, sorted
+func DocumentTypes() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(modules))
+	for name := range modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Violation is one value of document_data a module does not accept
+type Violation struct {
+	// Path is a JSON pointer to the value, e.g. /cardInformation/expiryDate
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned by Validate when document_data is not accepted
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = fmt.Sprintf("%s: %s", v.Path, v.Message)
+	}
+	return "document_data is not valid: " + strings.Join(messages, "; ")
+}
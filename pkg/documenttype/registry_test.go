@@ -0,0 +1,166 @@
+package documenttype
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocumentTypes(t *testing.T) {
+	assert.Equal(t, []string{"EHIC", "ELM", "PDA1", "PID", "mDL"}, DocumentTypes())
+	assert.True(t, Registered("PID"))
+	assert.False(t, Registered("pid"))
+}
+
+func TestRegister(t *testing.T) {
+	Register(Base{Name: "SE-ID"})
+	t.Cleanup(func() {
+		mu.Lock()
+		delete(modules, "SE-ID")
+		mu.Unlock()
+	})
+
+	module, ok := Lookup("SE-ID")
+	assert.True(t, ok)
+	assert.Equal(t, "SE-ID", module.DocumentType())
+
+	assert.Panics(t, func() { Register(Base{Name: "SE-ID"}) })
+	assert.Panics(t, func() { Register(Base{}) })
+}
+
+func TestValidate(t *testing.T) {
+	tts := []struct {
+		name         string
+		documentType string
+		data         map[string]any
+		want         []Violation
+	}{
+		{
+			name:         "EHIC valid",
+			documentType: "EHIC",
+			data: map[string]any{
+				"cardInformation": map[string]any{"issuanceDate": "2024-01-01", "validSince": "2024-01-01", "expiryDate": "2029-01-01"},
+			},
+		},
+		{
+			name:         "EHIC expires before it is valid",
+			documentType: "EHIC",
+			data: map[string]any{
+				"cardInformation": map[string]any{"issuanceDate": "2024-01-01", "validSince": "2030-01-01", "expiryDate": "2029-01-01"},
+			},
+			want: []Violation{{Path: "/cardInformation/expiryDate", Message: "is before /cardInformation/validSince"}},
+		},
+		{
+			name:         "PDA1 ends before it starts",
+			documentType: "PDA1",
+			data: map[string]any{
+				"memberStateLegislation": map[string]any{"startingDate": "2024-06-01T00:00:00Z", "endingDate": "2024-01-01T00:00:00Z"},
+			},
+			want: []Violation{{Path: "/memberStateLegislation/endingDate", Message: "is before /memberStateLegislation/startingDate"}},
+		},
+		{
+			name:         "PID born in the future",
+			documentType: "PID",
+			data:         map[string]any{"birth_date": "2999-01-01", "issuance_date": "2024-01-01", "expiry_date": "2034-01-01"},
+			want:         []Violation{{Path: "/birth_date", Message: "is in the future"}},
+		},
+		{
+			name:         "PID malformed dates are left to the schema",
+			documentType: "PID",
+			data:         map[string]any{"birth_date": "1 jan 1970", "expiry_date": 2034},
+		},
+		{
+			name:         "mDL driving privilege expires before it is issued",
+			documentType: "mDL",
+			data: map[string]any{
+				"birth_date":  "1970-01-01",
+				"issue_date":  "2024-01-01",
+				"expiry_date": "2034-01-01",
+				"driving_privileges": []any{
+					map[string]any{"vehicle_category_code": "A", "issue_date": "2024-01-01", "expiry_date": "2034-01-01"},
+					map[string]any{"vehicle_category_code": "B", "issue_date": "2024-01-01", "expiry_date": "2020-01-01"},
+				},
+			},
+			want: []Violation{{Path: "/driving_privileges/1/expiry_date", Message: "is before /driving_privileges/1/issue_date"}},
+		},
+		{
+			name:         "ELM is not validated",
+			documentType: "ELM",
+			data:         map[string]any{},
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			module, ok := Lookup(tt.documentType)
+			assert.True(t, ok)
+
+			err := module.Validate(context.Background(), tt.data)
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			var validationErr *ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.want, validationErr.Violations)
+		})
+	}
+}
+
+func TestEnrich(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	tts := []struct {
+		name         string
+		documentType string
+		birthDate    string
+		want         map[string]any
+	}{
+		{
+			name:         "PID adult",
+			documentType: "PID",
+			birthDate:    "1970-01-01",
+			want:         map[string]any{"age_in_years": 55, "age_birth_year": 1970, "age_over_18": true},
+		},
+		{
+			name:         "PID birthday tomorrow",
+			documentType: "PID",
+			birthDate:    "2007-06-16",
+			want:         map[string]any{"age_in_years": 17, "age_birth_year": 2007, "age_over_18": false},
+		},
+		{
+			name:         "mDL birthday today",
+			documentType: "mDL",
+			birthDate:    "2004-06-15",
+			want:         map[string]any{"age_in_years": 21, "age_birth_year": 2004, "age_over_18": true, "age_over_21": true},
+		},
+		{
+			name:         "mDL without birth date",
+			documentType: "mDL",
+			want:         map[string]any{},
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			module, ok := Lookup(tt.documentType)
+			assert.True(t, ok)
+
+			data := map[string]any{}
+			if tt.birthDate != "" {
+				data["birth_date"] = tt.birthDate
+				tt.want["birth_date"] = tt.birthDate
+			}
+
+			assert.NoError(t, module.Enrich(context.Background(), data, now))
+			assert.Equal(t, tt.want, data)
+
+			credentialData, err := module.MapToCredential(context.Background(), data)
+			assert.NoError(t, err)
+			assert.Equal(t, data, credentialData)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package education
+
+// Document is a learning credential modelled on the European Learning Model (ELM) 3, the learner and the learning achievements awarded to them
+type Document struct {
+	Learner              Person                `json:"learner" bson:"learner" validate:"required"`
+	LearningAchievements []LearningAchievement `json:"learningAchievements" bson:"learningAchievements" validate:"required,min=1,dive"`
+	IssuanceDate         string                `json:"issuanceDate" bson:"issuanceDate" validate:"required,datetime=2006-01-02"`
+}
+
+// Person is the ELM person the learning achievements are awarded to
+type Person struct {
+	GivenName          string `json:"givenName" bson:"givenName" validate:"required"`
+	FamilyName         string `json:"familyName" bson:"familyName" validate:"required"`
+	DateOfBirth        string `json:"dateOfBirth" bson:"dateOfBirth" validate:"required,datetime=2006-01-02"`
+	NationalID         string `json:"nationalID,omitempty" bson:"nationalID"`
+	CitizenshipCountry string `json:"citizenshipCountry,omitempty" bson:"citizenshipCountry" validate:"omitempty,iso3166_1_alpha2"`
+}
+
+// LearningAchievement is the ELM learning achievement, the acquisition of knowledge, skills or competences awarded by an organisation
+type LearningAchievement struct {
+	ID           string        `json:"id" bson:"id" validate:"required"`
+	Title        string        `json:"title" bson:"title" validate:"required"`
+	Description  string        `json:"description,omitempty" bson:"description"`
+	AwardingBody Organisation  `json:"awardingBody" bson:"awardingBody" validate:"required"`
+	AwardingDate string        `json:"awardingDate" bson:"awardingDate" validate:"required,datetime=2006-01-02"`
+	SpecifiedBy  Qualification `json:"specifiedBy" bson:"specifiedBy"`
+	Grade        string        `json:"grade,omitempty" bson:"grade"`
+}
+
+// Organisation is the ELM organisation, e.g. the university awarding a degree
+type Organisation struct {
+	ID        string `json:"id" bson:"id" validate:"required"`
+	LegalName string `json:"legalName" bson:"legalName" validate:"required"`
+	Country   string `json:"country" bson:"country" validate:"required,iso3166_1_alpha2"`
+}
+
+// Qualification is the ELM qualification, the specification of a learning achievement
+type Qualification struct {
+	Title string `json:"title,omitempty" bson:"title"`
+
+	// EQFLevel is the European Qualifications Framework level, 1 to 8
+	EQFLevel int `json:"eqfLevel,omitempty" bson:"eqfLevel" validate:"omitempty,min=1,max=8"`
+
+	// ISCEDFCode is the ISCED-F 2013 field of education and training, e.g. 0613
+	ISCEDFCode string `json:"iscedfCode,omitempty" bson:"iscedfCode"`
+
+	// CreditPoints is the workload in ECTS credits
+	CreditPoints float64 `json:"creditPoints,omitempty" bson:"creditPoints" validate:"omitempty,gt=0"`
+}
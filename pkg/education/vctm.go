@@ -0,0 +1,13 @@
+package education
+
+import (
+	_ "embed"
+)
+
+// VCT is the credential type of learning credentials
+const VCT = "https://credential.sunet.se/elm"
+
+// TypeMetadata is the SD-JWT VC type metadata of learning credentials, with display labels and the claim schema, to be published at VCT
+//
+//go:embed vctm.json
+var TypeMetadata []byte
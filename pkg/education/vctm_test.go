@@ -0,0 +1,40 @@
+package education
+
+import (
+	"encoding/json"
+	"testing"
+	"vc/pkg/sdjwt"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeMetadata(t *testing.T) {
+	vctm := &sdjwt.VCTM{}
+	assert.NoError(t, json.Unmarshal(TypeMetadata, vctm))
+	assert.Equal(t, VCT, vctm.VCT)
+
+	claims := map[string]any{
+		"iss": "https://issuer.sunet.se",
+		"vct": VCT,
+		"learner": map[string]any{
+			"givenName":   "Magnus",
+			"familyName":  "Svensson",
+			"dateOfBirth": "1990-01-01",
+		},
+		"learningAchievements": []any{
+			map[string]any{
+				"id":           "urn:uuid:1",
+				"title":        "Master of Science in Computer Science",
+				"awardingBody": map[string]any{"id": "SE:KTH", "legalName": "KTH Royal Institute of Technology", "country": "SE"},
+				"awardingDate": "2015-06-12",
+				"specifiedBy":  map[string]any{"eqfLevel": 7, "creditPoints": 120},
+			},
+		},
+		"issuanceDate": "2024-01-01",
+	}
+	assert.NoError(t, vctm.ValidateClaims(claims))
+
+	claims["learningAchievements"] = []any{}
+	err := vctm.ValidateClaims(claims)
+	assert.ErrorIs(t, err, sdjwt.ErrSchemaValidation)
+}
@@ -0,0 +1,278 @@
+package fieldcrypt
+
+import (
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"vc/pkg/model"
+)
+
+// prefix marks an encrypted field value
+const prefix = "enc:"
+
+var (
+	// ErrInvalidKey is returned when a key is not 32 bytes
+	ErrInvalidKey = errors.New("key must be 32 bytes")
+
+	// ErrUnknownKey is returned when a data encryption key is wrapped by a key that is not in the keyring
+	ErrUnknownKey = errors.New("unknown key encryption key")
+
+	// ErrCiphertext is returned when a value can not be decrypted
+	ErrCiphertext = errors.New("malformed or tampered ciphertext")
+)
+
+// Encryptor encrypts the designated identity fields with a data encryption key per identity, wrapped by a
+// KeyProvider. Encrypted fields are looked up by a blind index, an HMAC of the plaintext.
+type Encryptor struct {
+	kek      KeyProvider
+	indexKey []byte
+	fields   map[string]bool
+}
+
+// New creates an Encryptor of fields, by json name, e.g. birth_date
+func New(kek KeyProvider, indexKey []byte, fields []string) *Encryptor {
+	e := &Encryptor{
+		kek:      kek,
+		indexKey: indexKey,
+		fields:   map[string]bool{},
+	}
+	for _, field := range fields {
+		e.fields[field] = true
+	}
+
+	return e
+}
+
+// Encrypted reports if field is encrypted
+func (e *Encryptor) Encrypted(field string) bool {
+	return e != nil && e.fields[field]
+}
+
+// KeyID returns the id of the current key encryption key
+func (e *Encryptor) KeyID() string {
+	return e.kek.KeyID()
+}
+
+// BlindIndex returns the blind index of value in field
+func (e *Encryptor) BlindIndex(field, value string) string {
+	mac := hmac.New(sha256.New, e.indexKey)
+	mac.Write([]byte(field))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// newDEK returns a new data encryption key and the key wrapped with the key encryption key
+func (e *Encryptor) newDEK() (cipher.AEAD, *model.WrappedKey, error) {
+	dek := make([]byte, 32)
+	if _, err := rand.Read(dek); err != nil {
+		return nil, nil, err
+	}
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	wrapped, err := e.kek.Wrap(dek)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return aead, wrapped, nil
+}
+
+// unwrapDEK returns the data encryption key of wrapped
+func (e *Encryptor) unwrapDEK(wrapped *model.WrappedKey) (cipher.AEAD, error) {
+	dek, err := e.kek.Unwrap(wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	return newAEAD(dek)
+}
+
+// encryptValue returns value encrypted and bound to field
+func encryptValue(aead cipher.AEAD, field, value string) (string, error) {
+	ciphertext, err := seal(aead, []byte(value), []byte(field))
+	if err != nil {
+		return "", err
+	}
+
+	return prefix + base64.RawStdEncoding.EncodeToString(ciphertext), nil
+}
+
+// decryptValue returns value decrypted, value itself if it is not encrypted
+func decryptValue(aead cipher.AEAD, field, value string) (string, error) {
+	if !strings.HasPrefix(value, prefix) {
+		return value, nil
+	}
+
+	ciphertext, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(value, prefix))
+	if err != nil {
+		return "", ErrCiphertext
+	}
+	plaintext, err := open(aead, ciphertext, []byte(field))
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// EncryptIdentity returns a copy of identity with the designated fields encrypted under a new data encryption key
+func (e *Encryptor) EncryptIdentity(identity model.Identity) (model.Identity, error) {
+	aead, wrapped, err := e.newDEK()
+	if err != nil {
+		return identity, err
+	}
+	identity.EncryptionKey = wrapped
+	identity.BlindIndex = map[string]string{}
+
+	for field, value := range identityFields(&identity) {
+		if !e.fields[field] || *value == "" || strings.HasPrefix(*value, prefix) {
+			continue
+		}
+
+		identity.BlindIndex[field] = e.BlindIndex(field, *value)
+
+		encrypted, err := encryptValue(aead, field, *value)
+		if err != nil {
+			return identity, err
+		}
+		*value = encrypted
+	}
+
+	return identity, nil
+}
+
+// DecryptIdentity returns a copy of identity with the encrypted fields decrypted, identities stored before encryption
+// was enabled are returned as is
+func (e *Encryptor) DecryptIdentity(identity model.Identity) (model.Identity, error) {
+	if identity.EncryptionKey == nil {
+		return identity, nil
+	}
+
+	aead, err := e.unwrapDEK(identity.EncryptionKey)
+	if err != nil {
+		return identity, err
+	}
+
+	for field, value := range identityFields(&identity) {
+		decrypted, err := decryptValue(aead, field, *value)
+		if err != nil {
+			return identity, err
+		}
+		*value = decrypted
+	}
+
+	identity.EncryptionKey = nil
+	identity.BlindIndex = nil
+
+	return identity, nil
+}
+
+// EncryptValues returns a copy of values encrypted under a new data encryption key and the wrapped key, the values are
+// bound to field, e.g. disclosures
+func (e *Encryptor) EncryptValues(field string, values []string) ([]string, *model.WrappedKey, error) {
+	aead, wrapped, err := e.newDEK()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encrypted := make([]string, 0, len(values))
+	for _, value := range values {
+		v, err := encryptValue(aead, field, value)
+		if err != nil {
+			return nil, nil, err
+		}
+		encrypted = append(encrypted, v)
+	}
+
+	return encrypted, wrapped, nil
+}
+
+// DecryptValues returns a copy of the values of field encrypted by EncryptValues decrypted with wrapped, values stored
+// before encryption was enabled have no wrapped key and are returned as is
+func (e *Encryptor) DecryptValues(field string, values []string, wrapped *model.WrappedKey) ([]string, error) {
+	if wrapped == nil {
+		return values, nil
+	}
+
+	aead, err := e.unwrapDEK(wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	decrypted := make([]string, 0, len(values))
+	for _, value := range values {
+		v, err := decryptValue(aead, field, value)
+		if err != nil {
+			return nil, err
+		}
+		decrypted = append(decrypted, v)
+	}
+
+	return decrypted, nil
+}
+
+// Rewrap wraps the data encryption key of identity with the current key encryption key, and reports if it changed
+func (e *Encryptor) Rewrap(identity *model.Identity) (bool, error) {
+	rewrapped, changed, err := e.RewrapKey(identity.EncryptionKey)
+	if err != nil || !changed {
+		return false, err
+	}
+	identity.EncryptionKey = rewrapped
+
+	return true, nil
+}
+
+// RewrapKey returns the data encryption key of wrapped wrapped with the current key encryption key, and reports if it
+// changed
+func (e *Encryptor) RewrapKey(wrapped *model.WrappedKey) (*model.WrappedKey, bool, error) {
+	if wrapped == nil || wrapped.KeyID == e.kek.KeyID() {
+		return wrapped, false, nil
+	}
+
+	dek, err := e.kek.Unwrap(wrapped)
+	if err != nil {
+		return nil, false, err
+	}
+
+	rewrapped, err := e.kek.Wrap(dek)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return rewrapped, true, nil
+}
+
+// identityFields returns the encryptable fields of identity by json name
+func identityFields(identity *model.Identity) map[string]*string {
+	return map[string]*string{
+		"authentic_source_person_id": &identity.AuthenticSourcePersonID,
+		"family_name":                &identity.FamilyName,
+		"given_name":                 &identity.GivenName,
+		"birth_date":                 &identity.BirthDate,
+		"family_name_at_birth":       &identity.FamilyNameAtBirth,
+		"given_name_at_birth":        &identity.GivenNameAtBirth,
+		"birth_place":                &identity.BirthPlace,
+		"gender":                     &identity.Gender,
+		"birth_country":              &identity.BirthCountry,
+		"birth_state":                &identity.BirthState,
+		"birth_city":                 &identity.BirthCity,
+		"resident_address":           &identity.ResidentAddress,
+		"resident_country":           &identity.ResidentCountry,
+		"resident_state":             &identity.ResidentState,
+		"resident_city":              &identity.ResidentCity,
+		"resident_postal_code":       &identity.ResidentPostalCode,
+		"resident_street":            &identity.ResidentStreet,
+		"resident_house_number":      &identity.ResidentHouseNumber,
+		"nationality":                &identity.Nationality,
+	}
+}
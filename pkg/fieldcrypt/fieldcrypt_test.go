@@ -0,0 +1,135 @@
+package fieldcrypt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"vc/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, 32)
+}
+
+func TestEncryptIdentity(t *testing.T) {
+	keyring, err := NewLocalKeyring(mockKey(1))
+	assert.NoError(t, err)
+
+	e := New(keyring, mockKey(9), []string{"family_name", "birth_date"})
+
+	identity := model.Identity{
+		AuthenticSourcePersonID: "10",
+		FamilyName:              "Svensson",
+		GivenName:               "Magnus",
+		BirthDate:               "1970-01-01",
+	}
+
+	encrypted, err := e.EncryptIdentity(identity)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encrypted.FamilyName, prefix))
+	assert.True(t, strings.HasPrefix(encrypted.BirthDate, prefix))
+	assert.Equal(t, "Magnus", encrypted.GivenName)
+	assert.Equal(t, e.BlindIndex("family_name", "Svensson"), encrypted.BlindIndex["family_name"])
+	assert.Equal(t, "Svensson", identity.FamilyName, "the identity passed in is not modified")
+
+	decrypted, err := e.DecryptIdentity(encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, identity, decrypted)
+
+	tampered := encrypted
+	tampered.FamilyName, tampered.BirthDate = encrypted.BirthDate, encrypted.FamilyName
+	_, err = e.DecryptIdentity(tampered)
+	assert.ErrorIs(t, err, ErrCiphertext)
+}
+
+func TestRewrap(t *testing.T) {
+	oldKeyring, err := NewLocalKeyring(mockKey(1))
+	assert.NoError(t, err)
+	encrypted, err := New(oldKeyring, mockKey(9), []string{"family_name"}).EncryptIdentity(model.Identity{FamilyName: "Svensson"})
+	assert.NoError(t, err)
+
+	tts := []struct {
+		name        string
+		previous    [][]byte
+		wantRewrap  bool
+		wantErr     error
+		wantDecrypt bool
+	}{
+		{
+			name:        "previous key kept",
+			previous:    [][]byte{mockKey(1)},
+			wantRewrap:  true,
+			wantDecrypt: true,
+		},
+		{
+			name:    "previous key removed",
+			wantErr: ErrUnknownKey,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			keyring, err := NewLocalKeyring(mockKey(2), tt.previous...)
+			assert.NoError(t, err)
+			e := New(keyring, mockKey(9), []string{"family_name"})
+
+			identity := encrypted
+			rewrapped, err := e.Rewrap(&identity)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantRewrap, rewrapped)
+			if !tt.wantDecrypt {
+				return
+			}
+			assert.Equal(t, keyring.KeyID(), identity.EncryptionKey.KeyID)
+
+			// the new keyring alone unwraps the rewrapped key
+			current, err := NewLocalKeyring(mockKey(2))
+			assert.NoError(t, err)
+			decrypted, err := New(current, mockKey(9), []string{"family_name"}).DecryptIdentity(identity)
+			assert.NoError(t, err)
+			assert.Equal(t, "Svensson", decrypted.FamilyName)
+		})
+	}
+}
+
+func TestEncryptValues(t *testing.T) {
+	keyring, err := NewLocalKeyring(mockKey(1))
+	assert.NoError(t, err)
+	e := New(keyring, mockKey(9), nil)
+
+	disclosures := []string{"WyJzYWx0IiwgImdpdmVuX25hbWUiLCAiTWFnbnVzIl0", "WyJzYWx0IiwgImJpcnRoX2RhdGUiLCAiMTk3MC0wMS0wMSJd"}
+
+	encrypted, key, err := e.EncryptValues("disclosures", disclosures)
+	assert.NoError(t, err)
+	assert.Len(t, encrypted, len(disclosures))
+	for i, value := range encrypted {
+		assert.True(t, strings.HasPrefix(value, prefix))
+		assert.NotContains(t, value, disclosures[i])
+	}
+
+	decrypted, err := e.DecryptValues("disclosures", encrypted, key)
+	assert.NoError(t, err)
+	assert.Equal(t, disclosures, decrypted)
+
+	_, err = e.DecryptValues("other", encrypted, key)
+	assert.ErrorIs(t, err, ErrCiphertext, "values are bound to their field")
+
+	plaintext, err := e.DecryptValues("disclosures", disclosures, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, disclosures, plaintext, "values stored before encryption are read as is")
+
+	rotated, err := NewLocalKeyring(mockKey(2), mockKey(1))
+	assert.NoError(t, err)
+	rewrapped, changed, err := New(rotated, mockKey(9), nil).RewrapKey(key)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, rotated.KeyID(), rewrapped.KeyID)
+
+	current, err := NewLocalKeyring(mockKey(2))
+	assert.NoError(t, err)
+	decrypted, err = New(current, mockKey(9), nil).DecryptValues("disclosures", encrypted, rewrapped)
+	assert.NoError(t, err)
+	assert.Equal(t, disclosures, decrypted)
+}
@@ -0,0 +1,131 @@
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"strings"
+	"vc/pkg/model"
+)
+
+// KeyProvider wraps and unwraps data encryption keys with a key encryption key, a local key file or a KMS
+type KeyProvider interface {
+	// KeyID is the id of the key encryption key new data encryption keys are wrapped with
+	KeyID() string
+	Wrap(dek []byte) (*model.WrappedKey, error)
+	Unwrap(wrapped *model.WrappedKey) ([]byte, error)
+}
+
+// LocalKeyring is a KeyProvider of AES-256 key files, the first key wraps and all keys unwrap
+type LocalKeyring struct {
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+// LoadKey reads a base64 encoded 32 byte key from path
+func LoadKey(path string) ([]byte, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(b)))
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != 32 {
+		return nil, ErrInvalidKey
+	}
+
+	return key, nil
+}
+
+// NewLocalKeyring creates a keyring of the current key and previous keys, which are kept to unwrap until rotated away
+func NewLocalKeyring(current []byte, previous ...[]byte) (*LocalKeyring, error) {
+	k := &LocalKeyring{keys: map[string]cipher.AEAD{}}
+
+	for i, key := range append([][]byte{current}, previous...) {
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		id := keyID(key)
+		k.keys[id] = aead
+		if i == 0 {
+			k.current = id
+		}
+	}
+
+	return k, nil
+}
+
+// KeyID returns the id of the current key
+func (k *LocalKeyring) KeyID() string {
+	return k.current
+}
+
+// Wrap encrypts dek with the current key
+func (k *LocalKeyring) Wrap(dek []byte) (*model.WrappedKey, error) {
+	ciphertext, err := seal(k.keys[k.current], dek, []byte(k.current))
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.WrappedKey{KeyID: k.current, Key: ciphertext}, nil
+}
+
+// Unwrap decrypts a data encryption key wrapped by any key of the keyring
+func (k *LocalKeyring) Unwrap(wrapped *model.WrappedKey) ([]byte, error) {
+	aead, ok := k.keys[wrapped.KeyID]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	return open(aead, wrapped.Key, []byte(wrapped.KeyID))
+}
+
+// keyID is the first 8 bytes of the SHA-256 of key, hex encoded
+func keyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, ErrInvalidKey
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// seal returns the nonce followed by the ciphertext of plaintext
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(aead cipher.AEAD, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, ErrCiphertext
+	}
+
+	plaintext, err := aead.Open(nil, ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():], additionalData)
+	if err != nil {
+		return nil, ErrCiphertext
+	}
+
+	return plaintext, nil
+}
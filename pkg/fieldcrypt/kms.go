@@ -0,0 +1,112 @@
+package fieldcrypt
+
+import (
+	"context"
+	"errors"
+	"time"
+	"vc/pkg/model"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+)
+
+// kmsTimeout bounds a request to AWS KMS
+const kmsTimeout = 10 * time.Second
+
+// kmsEncryptionContext is bound to the wrapped data encryption keys, KMS only unwraps them with the same context
+var kmsEncryptionContext = map[string]string{"purpose": "vc_identity_dek"}
+
+// kmsClient is the part of the AWS KMS client a KMS key provider uses
+type kmsClient interface {
+	Encrypt(ctx context.Context, params *kms.EncryptInput, optFns ...func(*kms.Options)) (*kms.EncryptOutput, error)
+	Decrypt(ctx context.Context, params *kms.DecryptInput, optFns ...func(*kms.Options)) (*kms.DecryptOutput, error)
+}
+
+// KMS is a KeyProvider of symmetric AWS KMS keys, the current key wraps and all keys unwrap. The key encryption keys
+// never leave KMS.
+type KMS struct {
+	client  kmsClient
+	current string
+	keys    map[string]bool
+}
+
+// NewKMS creates a KMS key provider of cfg, the default credential chain is used if cfg has no credentials file
+func NewKMS(ctx context.Context, cfg model.FieldEncryptionKMS) (*KMS, error) {
+	if cfg.KeyID == "" {
+		return nil, errors.New("awskms field encryption requires kms.key_id")
+	}
+
+	var configOptions []func(*config.LoadOptions) error
+	if cfg.Region != "" {
+		configOptions = append(configOptions, config.WithRegion(cfg.Region))
+	}
+	if cfg.CredentialsFile != "" {
+		configOptions = append(configOptions, config.WithSharedConfigFiles([]string{cfg.CredentialsFile}))
+	}
+
+	awsCfg, err := config.LoadDefaultConfig(ctx, configOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	client := kms.NewFromConfig(awsCfg, func(o *kms.Options) {
+		if cfg.Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.Endpoint)
+		}
+	})
+
+	return newKMS(client, cfg.KeyID, cfg.PreviousKeyIDs...), nil
+}
+
+func newKMS(client kmsClient, current string, previous ...string) *KMS {
+	k := &KMS{client: client, current: current, keys: map[string]bool{current: true}}
+	for _, keyID := range previous {
+		k.keys[keyID] = true
+	}
+
+	return k
+}
+
+// KeyID returns the id of the current key
+func (k *KMS) KeyID() string {
+	return k.current
+}
+
+// Wrap encrypts dek with the current key
+func (k *KMS) Wrap(dek []byte) (*model.WrappedKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), kmsTimeout)
+	defer cancel()
+
+	out, err := k.client.Encrypt(ctx, &kms.EncryptInput{
+		KeyId:             aws.String(k.current),
+		Plaintext:         dek,
+		EncryptionContext: kmsEncryptionContext,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.WrappedKey{KeyID: k.current, Key: out.CiphertextBlob}, nil
+}
+
+// Unwrap decrypts a data encryption key wrapped by the current or a previous key
+func (k *KMS) Unwrap(wrapped *model.WrappedKey) ([]byte, error) {
+	if !k.keys[wrapped.KeyID] {
+		return nil, ErrUnknownKey
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), kmsTimeout)
+	defer cancel()
+
+	out, err := k.client.Decrypt(ctx, &kms.DecryptInput{
+		KeyId:             aws.String(wrapped.KeyID),
+		CiphertextBlob:    wrapped.Key,
+		EncryptionContext: kmsEncryptionContext,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out.Plaintext, nil
+}
@@ -0,0 +1,156 @@
+package fieldcrypt
+
+import (
+	"context"
+	"crypto/cipher"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"vc/pkg/model"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockKMS is an AWS KMS of AES-256 keys by key id, the encryption context is bound as additional data
+type mockKMS map[string][]byte
+
+func (m mockKMS) aead(keyID *string) (cipher.AEAD, error) {
+	key, ok := m[aws.ToString(keyID)]
+	if !ok {
+		return nil, errors.New("NotFoundException")
+	}
+	return newAEAD(key)
+}
+
+func (m mockKMS) Encrypt(ctx context.Context, params *kms.EncryptInput, optFns ...func(*kms.Options)) (*kms.EncryptOutput, error) {
+	aead, err := m.aead(params.KeyId)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := seal(aead, params.Plaintext, []byte(params.EncryptionContext["purpose"]))
+	if err != nil {
+		return nil, err
+	}
+	return &kms.EncryptOutput{KeyId: params.KeyId, CiphertextBlob: ciphertext}, nil
+}
+
+func (m mockKMS) Decrypt(ctx context.Context, params *kms.DecryptInput, optFns ...func(*kms.Options)) (*kms.DecryptOutput, error) {
+	aead, err := m.aead(params.KeyId)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := open(aead, params.CiphertextBlob, []byte(params.EncryptionContext["purpose"]))
+	if err != nil {
+		return nil, err
+	}
+	return &kms.DecryptOutput{KeyId: params.KeyId, Plaintext: plaintext}, nil
+}
+
+func TestKeyProviders(t *testing.T) {
+	keyring, err := NewLocalKeyring(mockKey(1))
+	assert.NoError(t, err)
+
+	tts := []struct {
+		name     string
+		provider KeyProvider
+	}{
+		{
+			name:     "local keyring",
+			provider: keyring,
+		},
+		{
+			name:     "kms",
+			provider: newKMS(mockKMS{"key-1": mockKey(1)}, "key-1"),
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(tt.provider, mockKey(9), []string{"family_name"})
+
+			identity := model.Identity{FamilyName: "Svensson", GivenName: "Magnus"}
+			encrypted, err := e.EncryptIdentity(identity)
+			assert.NoError(t, err)
+			assert.NotEqual(t, identity.FamilyName, encrypted.FamilyName)
+			assert.Equal(t, tt.provider.KeyID(), encrypted.EncryptionKey.KeyID)
+
+			decrypted, err := e.DecryptIdentity(encrypted)
+			assert.NoError(t, err)
+			assert.Equal(t, identity, decrypted)
+
+			_, err = tt.provider.Unwrap(&model.WrappedKey{KeyID: "unknown", Key: encrypted.EncryptionKey.Key})
+			assert.ErrorIs(t, err, ErrUnknownKey)
+		})
+	}
+}
+
+func TestKMSRewrap(t *testing.T) {
+	m := mockKMS{"key-1": mockKey(1), "key-2": mockKey(2)}
+
+	encrypted, err := New(newKMS(m, "key-1"), mockKey(9), []string{"family_name"}).EncryptIdentity(model.Identity{FamilyName: "Svensson"})
+	assert.NoError(t, err)
+
+	_, err = New(newKMS(m, "key-2"), mockKey(9), []string{"family_name"}).Rewrap(&encrypted)
+	assert.ErrorIs(t, err, ErrUnknownKey, "key-1 is no longer configured")
+
+	rewrapped, err := New(newKMS(m, "key-2", "key-1"), mockKey(9), []string{"family_name"}).Rewrap(&encrypted)
+	assert.NoError(t, err)
+	assert.True(t, rewrapped)
+	assert.Equal(t, "key-2", encrypted.EncryptionKey.KeyID)
+
+	decrypted, err := New(newKMS(m, "key-2"), mockKey(9), []string{"family_name"}).DecryptIdentity(encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, "Svensson", decrypted.FamilyName)
+}
+
+func TestNewKMS(t *testing.T) {
+	m := mockKMS{"alias/vc": mockKey(1)}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			out any
+			err error
+		)
+		switch r.Header.Get("X-Amz-Target") {
+		case "TrentService.Encrypt":
+			in := &kms.EncryptInput{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(in))
+			out, err = m.Encrypt(r.Context(), in)
+		case "TrentService.Decrypt":
+			in := &kms.DecryptInput{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(in))
+			out, err = m.Decrypt(r.Context(), in)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		json.NewEncoder(w).Encode(out)
+	}))
+	defer server.Close()
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "access")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	_, err := NewKMS(context.Background(), model.FieldEncryptionKMS{Region: "eu-north-1"})
+	assert.Error(t, err, "key_id is required")
+
+	provider, err := NewKMS(context.Background(), model.FieldEncryptionKMS{KeyID: "alias/vc", Region: "eu-north-1", Endpoint: server.URL})
+	assert.NoError(t, err)
+
+	wrapped, err := provider.Wrap(mockKey(7))
+	assert.NoError(t, err)
+	assert.Equal(t, "alias/vc", wrapped.KeyID)
+
+	dek, err := provider.Unwrap(wrapped)
+	assert.NoError(t, err)
+	assert.Equal(t, mockKey(7), dek)
+}
@@ -41,6 +41,111 @@ var (
 	// ErrNoKnownDocumentType error for no known document type
 	ErrNoKnownDocumentType = NewError("ERR_NO_KNOWN_DOCUMENT_TYPE")
 
+	// ErrNoDocumentSchema is returned when schemas are required and there is none for the document_type and document_data_version
+	ErrNoDocumentSchema = NewError("NO_DOCUMENT_SCHEMA")
+
+	// ErrPreconditionFailed is returned when If-Match does not match the document revision
+	ErrPreconditionFailed = NewError("PRECONDITION_FAILED")
+
+	// ErrPreconditionRequired is returned when If-Match is missing on an update or delete
+	ErrPreconditionRequired = NewError("PRECONDITION_REQUIRED")
+
+	// ErrNotAuthenticated is returned when a request lacks a valid API key or client certificate
+	ErrNotAuthenticated = NewError("NOT_AUTHENTICATED")
+
+	// ErrNotAuthorized is returned when a client acts on documents of another authentic source
+	ErrNotAuthorized = NewError("NOT_AUTHORIZED")
+
+	// ErrTooManyRequests is returned when a client exceeds its rate limit
+	ErrTooManyRequests = NewError("TOO_MANY_REQUESTS")
+
+	// ErrRequestEntityTooLarge is returned when a request body exceeds the size limit
+	ErrRequestEntityTooLarge = NewError("REQUEST_ENTITY_TOO_LARGE")
+
+	// ErrNotAcceptable is returned when the Accept header can not be satisfied
+	ErrNotAcceptable = NewError("NOT_ACCEPTABLE")
+
+	// ErrNoActiveSigningKey is returned when no signing key is valid at the time of signing
+	ErrNoActiveSigningKey = NewError("NO_ACTIVE_SIGNING_KEY")
+
+	// ErrUnknownKeyID is returned when a token is signed with a kid that is not in the issuer JWKS
+	ErrUnknownKeyID = NewError("UNKNOWN_KEY_ID")
+
+	// ErrNoConsentFound is returned when no active consent matches
+	ErrNoConsentFound = NewError("NO_CONSENT_FOUND")
+
+	// ErrSessionNotFound is returned when a verification session does not exist or has expired
+	ErrSessionNotFound = NewError("SESSION_NOT_FOUND")
+
+	// ErrSessionCompleted is returned when a wallet responds to a verification session that already has a response
+	ErrSessionCompleted = NewError("SESSION_COMPLETED")
+
+	// ErrInvalidNotificationID is returned when a credential notification references an unknown notification_id
+	ErrInvalidNotificationID = NewError("INVALID_NOTIFICATION_ID")
+
+	// ErrInvalidProof is returned when the proof of possession of the holder key of a credential request does not verify
+	ErrInvalidProof = NewError("INVALID_PROOF")
+
+	// ErrIssuancePending is returned when a deferred credential is not yet issued
+	ErrIssuancePending = NewError("ISSUANCE_PENDING")
+
+	// ErrInvalidTransactionID is returned when a deferred credential request references an unknown transaction_id
+	ErrInvalidTransactionID = NewError("INVALID_TRANSACTION_ID")
+
+	// ErrUnknownPolicy is returned when a verification names a policy that is not configured
+	ErrUnknownPolicy = NewError("UNKNOWN_POLICY")
+
+	// ErrInvalidIdempotencyKey is returned when the Idempotency-Key header is longer than 255 characters
+	ErrInvalidIdempotencyKey = NewError("INVALID_IDEMPOTENCY_KEY")
+
+	// ErrIdempotencyKeyInProgress is returned when a request with the same Idempotency-Key is still being processed
+	ErrIdempotencyKeyInProgress = NewError("IDEMPOTENCY_KEY_IN_PROGRESS")
+
+	// ErrIdempotencyKeyReused is returned when an Idempotency-Key is reused with a different request
+	ErrIdempotencyKeyReused = NewError("IDEMPOTENCY_KEY_REUSED")
+
+	// ErrInvalidUploadSignature is returned when the JWS of a signed upload does not verify with a key of its authentic source
+	ErrInvalidUploadSignature = NewError("INVALID_UPLOAD_SIGNATURE")
+
+	// ErrUploadSignatureRequired is returned when signed uploads are required and an upload is not signed
+	ErrUploadSignatureRequired = NewError("UPLOAD_SIGNATURE_REQUIRED")
+
+	// ErrUploadReplayed is returned when the nonce of a signed upload has been used before
+	ErrUploadReplayed = NewError("UPLOAD_REPLAYED")
+
+	// ErrCollectIDExpired is returned when the collect code of a document is used after its valid_until
+	ErrCollectIDExpired = NewError("COLLECT_ID_EXPIRED")
+
+	// ErrCollectIDConsumed is returned when the collect code of a document has been used the maximum number of times
+	ErrCollectIDConsumed = NewError("COLLECT_ID_CONSUMED")
+
+	// ErrIdentityReviewPending is returned when the identity of a collect request is queued for manual review
+	ErrIdentityReviewPending = NewError("IDENTITY_REVIEW_PENDING")
+
+	// ErrIdentityReviewRejected is returned when a reviewer decided the identity of a collect request does not match
+	ErrIdentityReviewRejected = NewError("IDENTITY_REVIEW_REJECTED")
+
+	// ErrIdentityReviewDecided is returned when a decision is made on an identity review that is already decided
+	ErrIdentityReviewDecided = NewError("IDENTITY_REVIEW_DECIDED")
+
+	// ErrCredentialRefreshed is returned when a credential is refreshed that was already refreshed by a newer one
+	ErrCredentialRefreshed = NewError("CREDENTIAL_REFRESHED")
+
+	// ErrUnknownCredentialType is returned when no credential profile is configured for the credential type and document type
+	ErrUnknownCredentialType = NewError("UNKNOWN_CREDENTIAL_TYPE")
+
+	// ErrUnsupportedCredentialFormat is returned when the credential profile does not allow the requested format
+	ErrUnsupportedCredentialFormat = NewError("UNSUPPORTED_CREDENTIAL_FORMAT")
+
+	// ErrInvalidResumeToken is returned when the resume token of a registry watch is malformed
+	ErrInvalidResumeToken = NewError("INVALID_RESUME_TOKEN")
+
+	// ErrWalletCredentialNotFound is returned when the mockas wallet simulator holds no matching credential
+	ErrWalletCredentialNotFound = NewError("WALLET_CREDENTIAL_NOT_FOUND")
+
+	// ErrServiceUnavailable is returned by the readiness probe while the service is shutting down
+	ErrServiceUnavailable = NewError("SERVICE_UNAVAILABLE")
+
 	// ErrInternalServerError error for internal server error
 	ErrInternalServerError = NewError("INTERNAL_SERVER_ERROR")
 )
@@ -0,0 +1,122 @@
+package helpers
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	// MIMEProblemJSON is the media type of a problem details object, RFC 7807
+	MIMEProblemJSON = "application/problem+json"
+
+	// problemTypeBase is the base URI of the problem types, documented in docs/errors.md
+	problemTypeBase = "https://github.com/dc4eu/vc/blob/main/docs/errors.md#"
+)
+
+// problemStatus is the registry of stable error codes and their HTTP status. Codes not listed are 500 Internal Server
+// Error, so unexpected failures are not taken for client errors, e.g. by stored idempotent replies.
+var problemStatus = map[string]int{
+	"VALIDATION_ERROR":              http.StatusBadRequest,
+	"JSON_TYPE_ERROR":               http.StatusBadRequest,
+	"JSON_SYNTAX_ERROR":             http.StatusBadRequest,
+	"NO_DOCUMENT_FOUND":             http.StatusNotFound,
+	"NO_IDENTITY_FOUND":             http.StatusNotFound,
+	"NO_CONSENT_FOUND":              http.StatusNotFound,
+	"DOCUMENT_ALREADY_EXISTS":       http.StatusConflict,
+	"DUPLICATE_KEY":                 http.StatusConflict,
+	"SESSION_NOT_FOUND":             http.StatusNotFound,
+	"SESSION_COMPLETED":             http.StatusConflict,
+	"WALLET_CREDENTIAL_NOT_FOUND":   http.StatusNotFound,
+	"DOCUMENT_IS_REVOKED":           http.StatusGone,
+	"COLLECT_ID_EXPIRED":            http.StatusGone,
+	"COLLECT_ID_CONSUMED":           http.StatusConflict,
+	"CREDENTIAL_REFRESHED":          http.StatusConflict,
+	"IDENTITY_REVIEW_PENDING":       http.StatusConflict,
+	"IDENTITY_REVIEW_DECIDED":       http.StatusConflict,
+	"IDENTITY_REVIEW_REJECTED":      http.StatusForbidden,
+	"NOT_AUTHENTICATED":             http.StatusUnauthorized,
+	"NOT_AUTHORIZED":                http.StatusForbidden,
+	"NOT_ACCEPTABLE":                http.StatusNotAcceptable,
+	"PRECONDITION_FAILED":           http.StatusPreconditionFailed,
+	"REQUEST_ENTITY_TOO_LARGE":      http.StatusRequestEntityTooLarge,
+	"PRECONDITION_REQUIRED":         http.StatusPreconditionRequired,
+	"TOO_MANY_REQUESTS":             http.StatusTooManyRequests,
+	"INTERNAL_SERVER_ERROR":         http.StatusInternalServerError,
+	"ERR_PRIVATE_KEY_MISSING":       http.StatusInternalServerError,
+	"NO_ACTIVE_SIGNING_KEY":         http.StatusInternalServerError,
+	"SERVICE_UNAVAILABLE":           http.StatusServiceUnavailable,
+	"UNKNOWN_KEY_ID":                http.StatusBadRequest,
+	"ERR_NO_KNOWN_DOCUMENT_TYPE":    http.StatusBadRequest,
+	"NO_TRANSACTION_ID":             http.StatusBadRequest,
+	"NO_DOCUMENT_DATA":              http.StatusBadRequest,
+	"NO_REVOCATION_ID":              http.StatusBadRequest,
+	"UNKNOWN_CREDENTIAL_TYPE":       http.StatusBadRequest,
+	"UNSUPPORTED_CREDENTIAL_FORMAT": http.StatusBadRequest,
+	"SCHEMA_VALIDATION_ERROR":       http.StatusBadRequest,
+	"DOCUMENT_VALIDATION_ERROR":     http.StatusBadRequest,
+	"NO_DOCUMENT_SCHEMA":            http.StatusBadRequest,
+	"INVALID_NOTIFICATION_ID":       http.StatusBadRequest,
+	"INVALID_PROOF":                 http.StatusBadRequest,
+	"ISSUANCE_PENDING":              http.StatusBadRequest,
+	"INVALID_TRANSACTION_ID":        http.StatusBadRequest,
+	"UNKNOWN_POLICY":                http.StatusBadRequest,
+	"INVALID_IDEMPOTENCY_KEY":       http.StatusBadRequest,
+	"INVALID_RESUME_TOKEN":          http.StatusBadRequest,
+	"IDEMPOTENCY_KEY_IN_PROGRESS":   http.StatusConflict,
+	"IDEMPOTENCY_KEY_REUSED":        http.StatusUnprocessableEntity,
+	"WALLET_QUERY_NOT_SATISFIED":    http.StatusUnprocessableEntity,
+	"INVALID_UPLOAD_SIGNATURE":      http.StatusUnauthorized,
+	"UPLOAD_SIGNATURE_REQUIRED":     http.StatusUnauthorized,
+	"UPLOAD_REPLAYED":               http.StatusConflict,
+}
+
+// Problem is a problem details object according to RFC 7807, with the extension members code, trace_id and errors
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	// Code is a stable, machine-readable error code
+	Code string `json:"code"`
+
+	// TraceID of the request, for correlation with logs and traces
+	TraceID string `json:"trace_id,omitempty"`
+
+	// Errors holds field-level errors, e.g. from validation
+	Errors []map[string]any `json:"errors,omitempty"`
+}
+
+// NewProblem creates a problem details object from err
+func NewProblem(err error) *Problem {
+	e := NewErrorFromError(err)
+	if inner, ok := e.Err.(*Error); ok {
+		e = inner
+	}
+
+	code := strings.ToUpper(e.Title)
+	status, ok := problemStatus[code]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+
+	problem := &Problem{
+		Type:   problemTypeBase + strings.ToLower(code),
+		Title:  http.StatusText(status),
+		Status: status,
+		Code:   code,
+	}
+
+	switch details := e.Err.(type) {
+	case nil:
+	case []map[string]any:
+		problem.Errors = details
+	case string:
+		problem.Detail = details
+	case error:
+		problem.Detail = details.Error()
+	}
+
+	return problem
+}
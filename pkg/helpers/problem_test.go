@@ -0,0 +1,78 @@
+package helpers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestNewProblem(t *testing.T) {
+	tts := []struct {
+		name string
+		have error
+		want *Problem
+	}{
+		{
+			name: "registered code",
+			have: ErrPreconditionFailed,
+			want: &Problem{
+				Type:   problemTypeBase + "precondition_failed",
+				Title:  "Precondition Failed",
+				Status: http.StatusPreconditionFailed,
+				Code:   "PRECONDITION_FAILED",
+			},
+		},
+		{
+			name: "database not found",
+			have: mongo.ErrNoDocuments,
+			want: &Problem{
+				Type:   problemTypeBase + "no_document_found",
+				Title:  "Not Found",
+				Status: http.StatusNotFound,
+				Code:   "NO_DOCUMENT_FOUND",
+			},
+		},
+		{
+			name: "unregistered error",
+			have: errors.New("boom"),
+			want: &Problem{
+				Type:   problemTypeBase + "internal_server_error",
+				Title:  "Internal Server Error",
+				Status: http.StatusInternalServerError,
+				Detail: "boom",
+				Code:   "INTERNAL_SERVER_ERROR",
+			},
+		},
+		{
+			name: "unregistered code",
+			have: NewErrorDetails("database_error", "connection reset"),
+			want: &Problem{
+				Type:   problemTypeBase + "database_error",
+				Title:  "Internal Server Error",
+				Status: http.StatusInternalServerError,
+				Detail: "connection reset",
+				Code:   "DATABASE_ERROR",
+			},
+		},
+		{
+			name: "field errors",
+			have: NewErrorDetails("validation_error", []map[string]any{{"field": "document_id"}}),
+			want: &Problem{
+				Type:   problemTypeBase + "validation_error",
+				Title:  "Bad Request",
+				Status: http.StatusBadRequest,
+				Code:   "VALIDATION_ERROR",
+				Errors: []map[string]any{{"field": "document_id"}},
+			},
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewProblem(tt.have))
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"reflect"
 	"strings"
+	"vc/pkg/documenttype"
 	"vc/pkg/logger"
 	"vc/pkg/model"
 	"vc/pkg/trace"
@@ -25,6 +26,13 @@ func NewValidator() (*validator.Validate, error) {
 		return name
 	})
 
+	// document_type accepts the document types with a registered module
+	if err := validate.RegisterValidation("document_type", func(fl validator.FieldLevel) bool {
+		return documenttype.Registered(fl.Field().String())
+	}); err != nil {
+		return nil, err
+	}
+
 	return validate, nil
 }
 
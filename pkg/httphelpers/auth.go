@@ -0,0 +1,100 @@
+package httphelpers
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"vc/pkg/helpers"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+	"vc/pkg/tenant"
+
+	"github.com/gin-gonic/gin"
+)
+
+// authenticSourceKey is the gin context key of the authenticated authentic source
+const authenticSourceKey = "authentic_source"
+
+type authHandler struct {
+	client *Client
+	log    *logger.Log
+}
+
+// Middleware authenticates the client by TLS client certificate or X-API-Key header, of the configuration or of a tenant.
+// It stores the authentic source of the client in the gin context and its tenant in the request context.
+func (a *authHandler) Middleware(ctx context.Context, cfg model.Authentication) gin.HandlerFunc {
+	ctx, span := a.client.tracer.Start(ctx, "httphelpers:auth:Middleware")
+	defer span.End()
+
+	return func(c *gin.Context) {
+		authenticSource, ok := a.authenticate(c, cfg)
+		if !ok {
+			a.log.Info("authentication failed", "url", c.Request.URL.Path, "req_id", c.GetString("req_id"))
+			a.client.Rendering.Problem(c.Request.Context(), c, helpers.ErrNotAuthenticated)
+			return
+		}
+
+		c.Set(authenticSourceKey, authenticSource)
+		c.Request = c.Request.WithContext(tenant.NewContext(c.Request.Context(), a.client.tenants.ByAuthenticSource(authenticSource)))
+		c.Next()
+	}
+}
+
+// authenticate returns the authentic source of the client certificate or X-API-Key header, false if neither authenticates
+func (a *authHandler) authenticate(c *gin.Context, cfg model.Authentication) (string, bool) {
+	if authenticSource, ok := a.clientCertificate(c, cfg); ok {
+		return authenticSource, true
+	}
+	return a.apiKey(c, cfg)
+}
+
+// clientCertificate returns the authentic source of a verified TLS client certificate
+func (a *authHandler) clientCertificate(c *gin.Context, cfg model.Authentication) (string, bool) {
+	if c.Request.TLS == nil || len(c.Request.TLS.VerifiedChains) == 0 {
+		return "", false
+	}
+
+	sum := sha256.Sum256(c.Request.TLS.PeerCertificates[0].Raw)
+	fingerprint := hex.EncodeToString(sum[:])
+
+	if authenticSource, ok := cfg.ClientCertificates[fingerprint]; ok {
+		return authenticSource, true
+	}
+	authenticSource, ok := a.client.tenants.ClientCertificates()[fingerprint]
+
+	return authenticSource, ok
+}
+
+// apiKey returns the authentic source of the X-API-Key header
+func (a *authHandler) apiKey(c *gin.Context, cfg model.Authentication) (string, bool) {
+	key := c.GetHeader("X-API-Key")
+	if key == "" {
+		return "", false
+	}
+
+	for _, apiKeys := range []map[string]string{cfg.APIKeys, a.client.tenants.APIKeys()} {
+		for k, authenticSource := range apiKeys {
+			if subtle.ConstantTimeCompare([]byte(k), []byte(key)) == 1 {
+				return authenticSource, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// Authorize returns ErrNotAuthorized if the authenticated client does not act for authenticSource, unauthenticated routes are always authorized
+func (a *authHandler) Authorize(c *gin.Context, authenticSource string) error {
+	v, ok := c.Get(authenticSourceKey)
+	if !ok {
+		return nil
+	}
+
+	if v.(string) != authenticSource {
+		a.log.Info("not authorized", "client", v, "authentic_source", authenticSource, "req_id", c.GetString("req_id"))
+		return helpers.ErrNotAuthorized
+	}
+
+	return nil
+}
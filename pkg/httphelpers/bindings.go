@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 	"vc/pkg/helpers"
 	"vc/pkg/logger"
 
@@ -102,3 +104,21 @@ func (b *bindingHandler) Validator() (*DefaultValidator, error) {
 
 	return &DefaultValidator{Validate: validate}, nil
 }
+
+// IfMatch returns the document revision from the If-Match header, nil means any revision ("*")
+func (b *bindingHandler) IfMatch(ctx context.Context, c *gin.Context) (*int64, error) {
+	ifMatch := c.GetHeader("If-Match")
+	switch ifMatch {
+	case "":
+		return nil, helpers.ErrPreconditionRequired
+	case "*":
+		return nil, nil
+	}
+
+	revision, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`), 10, 64)
+	if err != nil {
+		return nil, helpers.ErrPreconditionFailed
+	}
+
+	return &revision, nil
+}
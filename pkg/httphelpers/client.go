@@ -4,6 +4,7 @@ import (
 	"context"
 	"vc/pkg/logger"
 	"vc/pkg/model"
+	"vc/pkg/tenant"
 	"vc/pkg/trace"
 )
 
@@ -13,6 +14,10 @@ type Client struct {
 	log    *logger.Log
 	cfg    *model.Cfg
 
+	// tenants of the authenticated clients
+	tenants *tenant.Tenants
+
+	Auth       *authHandler
 	Binding    *bindingHandler
 	Middleware *middlewareHandler
 	Rendering  *renderingHandler
@@ -29,6 +34,13 @@ func New(ctx context.Context, tracer *trace.Tracer, cfg *model.Cfg, log *logger.
 		cfg:    cfg,
 	}
 
+	var err error
+	c.tenants, err = tenant.New(cfg.Common.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Auth = &authHandler{client: c, log: log}
 	c.Binding = &bindingHandler{client: c, log: log}
 	c.Middleware = &middlewareHandler{client: c, log: log}
 	c.Rendering = &renderingHandler{client: c, log: log}
@@ -0,0 +1,184 @@
+package httphelpers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+	"vc/pkg/helpers"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// Health check statuses
+const (
+	HealthStatusOK       = "ok"
+	HealthStatusFail     = "fail"
+	HealthStatusReady    = "ready"
+	HealthStatusNotReady = "not_ready"
+)
+
+// defaultHealthCheckTimeout is used when the API server has no health check timeout configured
+const defaultHealthCheckTimeout = 2 * time.Second
+
+// HealthCheck checks that a dependency of the service is reachable
+type HealthCheck func(ctx context.Context) error
+
+// DependencyHealth is the outcome of one health check
+type DependencyHealth struct {
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// Readiness is the reply of the readiness probe
+type Readiness struct {
+	Status       string                       `json:"status"`
+	Dependencies map[string]*DependencyHealth `json:"dependencies"`
+}
+
+// AddHealthCheck adds a dependency check to the readiness probe
+func (s *serverHandler) AddHealthCheck(name string, check HealthCheck) {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+
+	if s.healthChecks == nil {
+		s.healthChecks = map[string]HealthCheck{}
+	}
+	s.healthChecks[name] = check
+}
+
+// live is the liveness probe, it responds 200 as long as the server handles requests
+func (s *serverHandler) live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "alive"})
+}
+
+// ready is the readiness probe, it responds 503 once shutdown has started or if a dependency check fails
+func (s *serverHandler) ready(c *gin.Context) {
+	if s.draining.Load() {
+		s.client.Rendering.Problem(c.Request.Context(), c, helpers.ErrServiceUnavailable)
+		return
+	}
+
+	readiness := s.readiness(c.Request.Context())
+	if readiness.Status != HealthStatusReady {
+		c.JSON(http.StatusServiceUnavailable, readiness)
+		return
+	}
+	c.JSON(http.StatusOK, readiness)
+}
+
+// readiness runs the health checks concurrently, each bound by the health check timeout
+func (s *serverHandler) readiness(ctx context.Context) *Readiness {
+	s.healthMu.RLock()
+	checks := make(map[string]HealthCheck, len(s.healthChecks))
+	for name, check := range s.healthChecks {
+		checks[name] = check
+	}
+	s.healthMu.RUnlock()
+
+	timeout := s.healthTimeout
+	if timeout <= 0 {
+		timeout = defaultHealthCheckTimeout
+	}
+
+	readiness := &Readiness{
+		Status:       HealthStatusReady,
+		Dependencies: make(map[string]*DependencyHealth, len(checks)),
+	}
+
+	mu := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			start := time.Now()
+			err := runHealthCheck(ctx, timeout, check)
+			dependency := &DependencyHealth{
+				Status:     HealthStatusOK,
+				DurationMS: time.Since(start).Milliseconds(),
+			}
+			if err != nil {
+				s.log.Info("health check failed", "dependency", name, "error", err)
+				dependency.Status = HealthStatusFail
+				dependency.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			readiness.Dependencies[name] = dependency
+			if err != nil {
+				readiness.Status = HealthStatusNotReady
+			}
+		}()
+	}
+	wg.Wait()
+
+	return readiness
+}
+
+// runHealthCheck runs check with a timeout, also for checks that do not honour their ctx
+func runHealthCheck(ctx context.Context, timeout time.Duration, check HealthCheck) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("timeout after %s", timeout)
+	}
+}
+
+// HTTPHealthCheck checks that url responds without an error status
+func HTTPHealthCheck(url string) HealthCheck {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= http.StatusBadRequest {
+			return fmt.Errorf("%s responded %d", url, resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+// GRPCHealthCheck checks that a connection to the gRPC server at addr can be established
+func GRPCHealthCheck(addr string, opts ...grpc.DialOption) HealthCheck {
+	return func(ctx context.Context) error {
+		conn, err := grpc.NewClient(addr, opts...)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		conn.Connect()
+		for {
+			state := conn.GetState()
+			if state == connectivity.Ready {
+				return nil
+			}
+			if !conn.WaitForStateChange(ctx, state) {
+				return fmt.Errorf("%s: %s", addr, state)
+			}
+		}
+	}
+}
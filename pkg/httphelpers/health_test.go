@@ -0,0 +1,116 @@
+package httphelpers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadiness(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health/live" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer downstream.Close()
+
+	tts := []struct {
+		name         string
+		checks       map[string]HealthCheck
+		code         int
+		status       string
+		dependencies map[string]string
+	}{
+		{
+			name:   "no dependencies",
+			code:   http.StatusOK,
+			status: HealthStatusReady,
+		},
+		{
+			name: "dependencies ok",
+			checks: map[string]HealthCheck{
+				"mongodb": func(context.Context) error { return nil },
+				"apigw":   HTTPHealthCheck(downstream.URL + "/health/live"),
+			},
+			code:         http.StatusOK,
+			status:       HealthStatusReady,
+			dependencies: map[string]string{"mongodb": HealthStatusOK, "apigw": HealthStatusOK},
+		},
+		{
+			name: "dependency failing",
+			checks: map[string]HealthCheck{
+				"mongodb": func(context.Context) error { return nil },
+				"kafka":   func(context.Context) error { return errors.New("no brokers") },
+				"apigw":   HTTPHealthCheck(downstream.URL + "/missing"),
+			},
+			code:         http.StatusServiceUnavailable,
+			status:       HealthStatusNotReady,
+			dependencies: map[string]string{"mongodb": HealthStatusOK, "kafka": HealthStatusFail, "apigw": HealthStatusFail},
+		},
+		{
+			name: "dependency timing out",
+			checks: map[string]HealthCheck{
+				"issuer": func(context.Context) error { time.Sleep(time.Second); return nil },
+			},
+			code:         http.StatusServiceUnavailable,
+			status:       HealthStatusNotReady,
+			dependencies: map[string]string{"issuer": HealthStatusFail},
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := New(ctx, nil, &model.Cfg{}, logger.NewSimple("test"))
+			assert.NoError(t, err)
+			client.Server.healthTimeout = 100 * time.Millisecond
+			for name, check := range tt.checks {
+				client.Server.AddHealthCheck(name, check)
+			}
+
+			engine := gin.New()
+			engine.GET("/health/live", client.Server.live)
+			engine.GET("/health/ready", client.Server.ready)
+
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/live", nil))
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			rec = httptest.NewRecorder()
+			engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+			assert.Equal(t, tt.code, rec.Code)
+
+			readiness := &Readiness{}
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), readiness))
+			assert.Equal(t, tt.status, readiness.Status)
+
+			dependencies := map[string]string{}
+			for name, dependency := range readiness.Dependencies {
+				dependencies[name] = dependency.Status
+				if dependency.Status == HealthStatusFail {
+					assert.NotEmpty(t, dependency.Error)
+				}
+			}
+			if tt.dependencies == nil {
+				tt.dependencies = map[string]string{}
+			}
+			assert.Equal(t, tt.dependencies, dependencies)
+
+			client.Server.draining.Store(true)
+			rec = httptest.NewRecorder()
+			engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+			assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package httphelpers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+	"vc/pkg/helpers"
+	"vc/pkg/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxIdempotencyKeyLength is the longest accepted Idempotency-Key header
+const maxIdempotencyKeyLength = 255
+
+// idempotencyReplayedHeaders are the response headers stored with a response and set again when it is replayed
+var idempotencyReplayedHeaders = []string{"Content-Type", "ETag", "Location"}
+
+// IdempotencyStore keeps the responses of requests made with an Idempotency-Key header
+type IdempotencyStore interface {
+	// ReserveIdempotencyKey stores record, pending, if its key is not already stored. The stored record is returned
+	// if it is, or nil if record was stored.
+	ReserveIdempotencyKey(ctx context.Context, record *model.IdempotencyRecord) (*model.IdempotencyRecord, error)
+
+	// CompleteIdempotencyKey stores the response of a reserved record
+	CompleteIdempotencyKey(ctx context.Context, record *model.IdempotencyRecord) error
+
+	// ReleaseIdempotencyKey deletes a reserved record, so the request can be retried with the same key
+	ReleaseIdempotencyKey(ctx context.Context, record *model.IdempotencyRecord) error
+}
+
+// idempotencyWriter keeps a copy of the response body
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *idempotencyWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency middleware stores the response of requests to routes, given as method and route e.g. "POST /api/v1/upload",
+// made with an Idempotency-Key header. A retry with the same key and body gets the stored response, a retry with
+// another body is rejected. Server errors are not stored, the request can then be retried with the same key.
+// Keys are scoped by the authenticated client, it has to run after the auth middleware.
+func (m *middlewareHandler) Idempotency(ctx context.Context, store IdempotencyStore, routes []string) gin.HandlerFunc {
+	ctx, span := m.client.tracer.Start(ctx, "httphelpers:middleware:Idempotency")
+	defer span.End()
+
+	idempotent := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		idempotent[route] = true
+	}
+
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		route := c.Request.Method + " " + c.FullPath()
+		if key == "" || !idempotent[route] {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+
+		if len(key) > maxIdempotencyKeyLength {
+			m.client.Rendering.Problem(ctx, c, helpers.ErrInvalidIdempotencyKey)
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			var maxBytesError *http.MaxBytesError
+			if errors.As(err, &maxBytesError) {
+				err = helpers.ErrRequestEntityTooLarge
+			}
+			m.client.Rendering.Problem(ctx, c, err)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		requestHash := sha256.Sum256(body)
+
+		record := &model.IdempotencyRecord{
+			Client:      c.GetString(authenticSourceKey),
+			Route:       route,
+			Key:         key,
+			RequestHash: hex.EncodeToString(requestHash[:]),
+			Status:      model.IdempotencyPending,
+			CreatedAt:   time.Now(),
+		}
+
+		stored, err := store.ReserveIdempotencyKey(ctx, record)
+		if err != nil {
+			m.log.Error(err, "reserve idempotency key")
+			m.client.Rendering.Problem(ctx, c, err)
+			return
+		}
+
+		if stored != nil {
+			switch {
+			case stored.RequestHash != record.RequestHash:
+				m.client.Rendering.Problem(ctx, c, helpers.ErrIdempotencyKeyReused)
+			case stored.Status != model.IdempotencyCompleted:
+				m.client.Rendering.Problem(ctx, c, helpers.ErrIdempotencyKeyInProgress)
+			default:
+				for k, v := range stored.Headers {
+					c.Header(k, v)
+				}
+				c.Header("Idempotent-Replayed", "true")
+				c.Status(stored.StatusCode)
+				c.Writer.Write(stored.Body)
+				c.Abort()
+			}
+			return
+		}
+
+		writer := &idempotencyWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		// the request is done, the outcome is stored even if the client has gone
+		ctx = context.WithoutCancel(ctx)
+
+		if c.Writer.Status() >= http.StatusInternalServerError {
+			if err := store.ReleaseIdempotencyKey(ctx, record); err != nil {
+				m.log.Error(err, "release idempotency key")
+			}
+			return
+		}
+
+		record.StatusCode = c.Writer.Status()
+		record.Headers = map[string]string{}
+		for _, header := range idempotencyReplayedHeaders {
+			if v := c.Writer.Header().Get(header); v != "" {
+				record.Headers[header] = v
+			}
+		}
+		record.Body = writer.body.Bytes()
+		if err := store.CompleteIdempotencyKey(ctx, record); err != nil {
+			m.log.Error(err, "complete idempotency key")
+		}
+	}
+}
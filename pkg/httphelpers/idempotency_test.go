@@ -0,0 +1,119 @@
+package httphelpers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+	"vc/pkg/trace"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockIdempotencyStore is an in memory IdempotencyStore
+type mockIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]*model.IdempotencyRecord
+}
+
+func (s *mockIdempotencyStore) id(record *model.IdempotencyRecord) string {
+	return record.Client + "|" + record.Route + "|" + record.Key
+}
+
+func (s *mockIdempotencyStore) ReserveIdempotencyKey(ctx context.Context, record *model.IdempotencyRecord) (*model.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if stored, ok := s.records[s.id(record)]; ok {
+		return stored, nil
+	}
+	stored := *record
+	s.records[s.id(record)] = &stored
+	return nil, nil
+}
+
+func (s *mockIdempotencyStore) CompleteIdempotencyKey(ctx context.Context, record *model.IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := *record
+	stored.Status = model.IdempotencyCompleted
+	s.records[s.id(record)] = &stored
+	return nil
+}
+
+func (s *mockIdempotencyStore) ReleaseIdempotencyKey(ctx context.Context, record *model.IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, s.id(record))
+	return nil
+}
+
+func TestIdempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	tracer, err := trace.NewForTesting(ctx, "test", logger.NewSimple("test"))
+	assert.NoError(t, err)
+	client, err := New(ctx, tracer, &model.Cfg{}, logger.NewSimple("test"))
+	assert.NoError(t, err)
+
+	store := &mockIdempotencyStore{records: map[string]*model.IdempotencyRecord{}}
+	calls := 0
+	status := http.StatusOK
+
+	engine := gin.New()
+	engine.Use(client.Middleware.Idempotency(ctx, store, []string{"POST /upload"}))
+	engine.POST("/upload", func(c *gin.Context) {
+		calls++
+		c.Header("ETag", `"1"`)
+		c.JSON(status, gin.H{"calls": calls})
+	})
+	engine.POST("/document", func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusOK, gin.H{"calls": calls})
+	})
+
+	do := func(path, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := do("/upload", "key-1", `{"document_id": "1"}`)
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.JSONEq(t, `{"calls": 1}`, first.Body.String())
+
+	replayed := do("/upload", "key-1", `{"document_id": "1"}`)
+	assert.Equal(t, http.StatusOK, replayed.Code)
+	assert.JSONEq(t, `{"calls": 1}`, replayed.Body.String())
+	assert.Equal(t, "true", replayed.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, `"1"`, replayed.Header().Get("ETag"))
+	assert.Equal(t, 1, calls)
+
+	reused := do("/upload", "key-1", `{"document_id": "2"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, reused.Code)
+	assert.Equal(t, 1, calls)
+
+	assert.Equal(t, http.StatusOK, do("/upload", "", `{"document_id": "1"}`).Code)
+	assert.Equal(t, http.StatusOK, do("/document", "key-1", `{"document_id": "1"}`).Code)
+	assert.Equal(t, 3, calls, "requests without a key or to other routes are not stored")
+
+	status = http.StatusInternalServerError
+	assert.Equal(t, http.StatusInternalServerError, do("/upload", "key-2", `{}`).Code)
+	status = http.StatusOK
+	assert.Equal(t, http.StatusOK, do("/upload", "key-2", `{}`).Code)
+	assert.Equal(t, 5, calls, "server errors are released for retry")
+
+	store.records["|POST /upload|key-3"] = &model.IdempotencyRecord{RequestHash: "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a", Status: model.IdempotencyPending}
+	assert.Equal(t, http.StatusConflict, do("/upload", "key-3", `{}`).Code)
+
+	assert.Equal(t, http.StatusBadRequest, do("/upload", strings.Repeat("k", 256), `{}`).Code)
+}
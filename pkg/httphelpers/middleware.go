@@ -3,12 +3,21 @@ package httphelpers
 import (
 	"context"
 	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync/atomic"
 	"time"
 	"vc/pkg/helpers"
 	"vc/pkg/logger"
+	"vc/pkg/model"
+	"vc/pkg/tenant"
 
 	"github.com/gin-gonic/gin"
 	"github.com/lithammer/shortuuid/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 
 	"github.com/gin-contrib/gzip"
 )
@@ -16,6 +25,10 @@ import (
 type middlewareHandler struct {
 	client *Client
 	log    *logger.Log
+
+	// limiter and maxBodySize are kept to allow the limits to be changed at runtime
+	limiter     *rateLimiter
+	maxBodySize atomic.Int64
 }
 
 // Duration middleware to calculate the duration of the request and set it in the gin context
@@ -31,6 +44,43 @@ func (m *middlewareHandler) Duration(ctx context.Context) gin.HandlerFunc {
 	}
 }
 
+// durationBuckets are the bucket boundaries in seconds of http.server.duration
+var durationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Metrics middleware counts requests and records their duration by route, method, status, tenant and the authentic
+// source of the authenticated client. The authentic source of unauthenticated requests is empty, labels from request
+// bodies could grow without bound.
+func (m *middlewareHandler) Metrics(ctx context.Context) (gin.HandlerFunc, error) {
+	ctx, span := m.client.tracer.Start(ctx, "httphelpers:middleware:Metrics")
+	defer span.End()
+
+	meter := otel.Meter("vc/httphelpers")
+
+	requestCounter, err := meter.Int64Counter("http.server.requests", metric.WithDescription("Number of handled requests"))
+	if err != nil {
+		return nil, err
+	}
+	durationHistogram, err := meter.Float64Histogram("http.server.duration", metric.WithDescription("Duration of handled requests"), metric.WithUnit("s"), metric.WithExplicitBucketBoundaries(durationBuckets...))
+	if err != nil {
+		return nil, err
+	}
+
+	return func(c *gin.Context) {
+		t := time.Now()
+		c.Next()
+
+		attrs := metric.WithAttributes(
+			attribute.String("route", c.FullPath()),
+			attribute.String("method", c.Request.Method),
+			attribute.Int("status", c.Writer.Status()),
+			attribute.String("tenant", tenant.FromContext(c.Request.Context())),
+			attribute.String("authentic_source", c.GetString(authenticSourceKey)),
+		)
+		requestCounter.Add(c.Request.Context(), 1, attrs)
+		durationHistogram.Record(c.Request.Context(), time.Since(t).Seconds(), attrs)
+	}, nil
+}
+
 // RequestID middleware to set a unique request ID in the gin context and header
 func (m *middlewareHandler) RequestID(ctx context.Context) gin.HandlerFunc {
 	ctx, span := m.client.tracer.Start(ctx, "httphelpers:middleware:RequestID")
@@ -52,7 +102,7 @@ func (m *middlewareHandler) Logger(ctx context.Context) gin.HandlerFunc {
 	log := m.log.New("http")
 	return func(c *gin.Context) {
 		c.Next()
-		log.Info("request", "status", c.Writer.Status(), "url", c.Request.URL.String(), "method", c.Request.Method, "req_id", c.GetString("req_id"))
+		log.WithContext(c.Request.Context()).Info("request", "status", c.Writer.Status(), "url", c.Request.URL.String(), "method", c.Request.Method, "req_id", c.GetString("req_id"))
 	}
 }
 
@@ -80,7 +130,7 @@ func (m *middlewareHandler) Crash(ctx context.Context) gin.HandlerFunc {
 			if r := recover(); r != nil {
 				status := c.Writer.Status()
 				log.Trace("crash", "error", r, "status", status, "url", c.Request.URL.Path, "method", c.Request.Method)
-				m.client.Rendering.Content(ctx, c, 500, gin.H{"data": nil, "error": helpers.NewError("internal_server_error")})
+				m.client.Rendering.Problem(c.Request.Context(), c, helpers.ErrInternalServerError)
 			}
 		}()
 		c.Next()
@@ -118,7 +168,74 @@ func (m *middlewareHandler) BasicAuth(ctx context.Context, users map[string]stri
 	}
 }
 
-// Gzip middleware sets the compression level
-func (m *middlewareHandler) Gzip(ctx context.Context) gin.HandlerFunc {
-	return gzip.Gzip(gzip.DefaultCompression)
+// Gzip middleware sets the compression level, excludedPaths are served uncompressed, e.g. event streams
+func (m *middlewareHandler) Gzip(ctx context.Context, excludedPaths ...string) gin.HandlerFunc {
+	return gzip.Gzip(gzip.DefaultCompression, gzip.WithExcludedPaths(excludedPaths))
+}
+
+// RateLimit middleware limits each client with a token bucket and responds 429 with Retry-After. Clients that auth
+// authenticates are limited by authentic source, others, including those presenting an unknown X-API-Key, by IP address.
+func (m *middlewareHandler) RateLimit(ctx context.Context, cfg model.RateLimit, auth model.Authentication) (gin.HandlerFunc, error) {
+	ctx, span := m.client.tracer.Start(ctx, "httphelpers:middleware:RateLimit")
+	defer span.End()
+
+	limitedCounter, err := otel.Meter("vc/httphelpers").Int64Counter("http.server.rate_limited_requests", metric.WithDescription("Number of requests rejected by the rate limit"))
+	if err != nil {
+		return nil, err
+	}
+
+	limiter := newRateLimiter(cfg.RequestsPerSecond, cfg.Burst)
+	m.limiter = limiter
+
+	return func(c *gin.Context) {
+		key := "ip:" + c.ClientIP()
+		if auth.Enabled {
+			if authenticSource, ok := m.client.Auth.authenticate(c, auth); ok {
+				key = "client:" + authenticSource
+			}
+		}
+
+		ok, retryAfter := limiter.allow(key, time.Now())
+		if !ok {
+			limitedCounter.Add(c.Request.Context(), 1, metric.WithAttributes(attribute.String("route", c.FullPath())))
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			m.client.Rendering.Problem(c.Request.Context(), c, helpers.ErrTooManyRequests)
+			return
+		}
+		c.Next()
+	}, nil
+}
+
+// Allow takes a token of the RateLimit middleware for key, "client:<authentic source>" or "ip:<address>", so the
+// clients of other servers share the limits of the HTTP API. Everything is allowed if the rate limit is disabled.
+func (m *middlewareHandler) Allow(key string) (bool, time.Duration) {
+	if m.limiter == nil {
+		return true, 0
+	}
+	return m.limiter.allow(key, time.Now())
+}
+
+// MaxBodySize middleware limits the request body to maxSize bytes and responds 413 if the body is larger
+func (m *middlewareHandler) MaxBodySize(ctx context.Context, maxSize int64) (gin.HandlerFunc, error) {
+	ctx, span := m.client.tracer.Start(ctx, "httphelpers:middleware:MaxBodySize")
+	defer span.End()
+
+	oversizeCounter, err := otel.Meter("vc/httphelpers").Int64Counter("http.server.oversize_requests", metric.WithDescription("Number of requests rejected by the body size limit"))
+	if err != nil {
+		return nil, err
+	}
+
+	m.maxBodySize.Store(maxSize)
+
+	return func(c *gin.Context) {
+		maxSize := m.maxBodySize.Load()
+		if c.Request.ContentLength > maxSize {
+			oversizeCounter.Add(c.Request.Context(), 1, metric.WithAttributes(attribute.String("route", c.FullPath())))
+			m.client.Rendering.Problem(c.Request.Context(), c, helpers.ErrRequestEntityTooLarge)
+			return
+		}
+		// bodies without a declared length fail to bind once maxSize bytes have been read
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize)
+		c.Next()
+	}, nil
 }
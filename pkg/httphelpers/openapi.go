@@ -0,0 +1,40 @@
+package httphelpers
+
+import (
+	"context"
+	"net/http"
+	"vc/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+)
+
+// InfraRoutes are the routes of every service that are left out of the specs, the probes, the docs and the health endpoint
+var InfraRoutes = []string{
+	"GET /ready",
+	"GET /health",
+	"GET /health/live",
+	"GET /health/ready",
+	"GET /openapi.json",
+	"GET /swagger/*any",
+}
+
+// Docs serves the swag doc of the service as OpenAPI 3.1 at /openapi.json, and Swagger UI at /swagger/index.html.
+// The docs package of the service, e.g. vc/docs/apigw, has to be imported.
+func (s *serverHandler) Docs(ctx context.Context, rg *gin.RouterGroup) error {
+	spec, err := openapi.Registered()
+	if err != nil {
+		s.log.Error(err, "openapi")
+		return err
+	}
+
+	rg.GET("openapi.json", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json", spec)
+	})
+
+	// the bundled Swagger UI does not render OpenAPI 3.1, it shows the Swagger 2.0 doc at /swagger/doc.json
+	rg.GET("swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package httphelpers
+
+import (
+	"container/list"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// staleBucketAge is how long an idle client bucket is kept
+	staleBucketAge = 10 * time.Minute
+
+	// maxBuckets bounds the number of client buckets, the least recently seen bucket is evicted when it is reached
+	maxBuckets = 100000
+)
+
+// tokenBucket is a token bucket for one client
+type tokenBucket struct {
+	key      string
+	tokens   float64
+	lastSeen time.Time
+}
+
+// rateLimiter holds a token bucket per client key, ordered by when the client was last seen
+type rateLimiter struct {
+	mu         sync.Mutex
+	rate       float64
+	burst      float64
+	maxBuckets int
+	buckets    map[string]*list.Element
+	order      *list.List
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		rate:       rate,
+		burst:      float64(burst),
+		maxBuckets: maxBuckets,
+		buckets:    map[string]*list.Element{},
+		order:      list.New(),
+	}
+}
+
+// allow takes a token for key, if none is available it returns the time until the next token
+func (r *rateLimiter) allow(key string, now time.Time) (bool, time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sweep(now)
+
+	var b *tokenBucket
+	if e, ok := r.buckets[key]; ok {
+		r.order.MoveToFront(e)
+		b = e.Value.(*tokenBucket)
+	} else {
+		if r.order.Len() >= r.maxBuckets {
+			r.remove(r.order.Back())
+		}
+		b = &tokenBucket{key: key, tokens: r.burst, lastSeen: now}
+		r.buckets[key] = r.order.PushFront(b)
+	}
+
+	b.tokens = math.Min(r.burst, b.tokens+now.Sub(b.lastSeen).Seconds()*r.rate)
+	b.lastSeen = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	return false, time.Duration((1 - b.tokens) / r.rate * float64(time.Second))
+}
+
+// setLimits changes rate and burst, buckets already holding more than burst tokens are capped
+func (r *rateLimiter) setLimits(rate float64, burst int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rate = rate
+	r.burst = float64(burst)
+	for _, e := range r.buckets {
+		b := e.Value.(*tokenBucket)
+		b.tokens = math.Min(r.burst, b.tokens)
+	}
+}
+
+// sweep removes the idle buckets from the back of the order, must be called with mu held
+func (r *rateLimiter) sweep(now time.Time) {
+	for e := r.order.Back(); e != nil && now.Sub(e.Value.(*tokenBucket).lastSeen) > staleBucketAge; e = r.order.Back() {
+		r.remove(e)
+	}
+}
+
+// remove removes the bucket of e, must be called with mu held
+func (r *rateLimiter) remove(e *list.Element) {
+	r.order.Remove(e)
+	delete(r.buckets, e.Value.(*tokenBucket).key)
+}
+
+// len returns the number of client buckets
+func (r *rateLimiter) len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.order.Len()
+}
@@ -0,0 +1,158 @@
+package httphelpers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+	"vc/pkg/trace"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	tts := []struct {
+		name      string
+		requests  int
+		elapsed   time.Duration
+		wantAllow bool
+	}{
+		{
+			name:      "within burst",
+			requests:  2,
+			wantAllow: true,
+		},
+		{
+			name:      "burst exhausted",
+			requests:  3,
+			wantAllow: false,
+		},
+		{
+			name:      "refilled",
+			requests:  3,
+			elapsed:   time.Second,
+			wantAllow: true,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRateLimiter(1, 3)
+			now := time.Now()
+
+			for i := 0; i < tt.requests; i++ {
+				ok, _ := r.allow("client", now)
+				assert.True(t, ok)
+			}
+
+			ok, retryAfter := r.allow("client", now.Add(tt.elapsed))
+			assert.Equal(t, tt.wantAllow, ok)
+			if !ok {
+				assert.Equal(t, time.Second, retryAfter)
+			}
+
+			other, _ := r.allow("other", now)
+			assert.True(t, other)
+		})
+	}
+}
+
+func TestRateLimiterSetLimits(t *testing.T) {
+	r := newRateLimiter(1, 10)
+	now := time.Now()
+
+	ok, _ := r.allow("client", now)
+	assert.True(t, ok)
+
+	r.setLimits(1, 1)
+
+	ok, _ = r.allow("client", now)
+	assert.True(t, ok)
+
+	ok, retryAfter := r.allow("client", now)
+	assert.False(t, ok)
+	assert.Equal(t, time.Second, retryAfter)
+}
+
+func TestRateLimiterEviction(t *testing.T) {
+	r := newRateLimiter(1, 1)
+	r.maxBuckets = 2
+	now := time.Now()
+
+	r.allow("a", now)
+	r.allow("b", now)
+	r.allow("a", now)
+	r.allow("c", now)
+	assert.Equal(t, 2, r.len())
+	assert.Contains(t, r.buckets, "a", "recently seen bucket is kept")
+	assert.NotContains(t, r.buckets, "b", "least recently seen bucket is evicted")
+
+	r.allow("d", now.Add(staleBucketAge+time.Second))
+	assert.Equal(t, 1, r.len(), "idle buckets are swept")
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	tracer, err := trace.NewForTesting(ctx, "test", logger.NewSimple("test"))
+	assert.NoError(t, err)
+	client, err := New(ctx, tracer, &model.Cfg{}, logger.NewSimple("test"))
+	assert.NoError(t, err)
+
+	auth := model.Authentication{
+		Enabled: true,
+		APIKeys: map[string]string{"secret": "SUNET"},
+	}
+	rateLimit, err := client.Middleware.RateLimit(ctx, model.RateLimit{Enabled: true, RequestsPerSecond: 1, Burst: 2}, auth)
+	assert.NoError(t, err)
+
+	engine := gin.New()
+	engine.Use(rateLimit)
+	engine.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	do := func(apiKey string) int {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, http.StatusOK, do(fmt.Sprintf("random-%d", i)))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, do("random-2"), "unknown api keys share the bucket of the ip address")
+	assert.Equal(t, http.StatusTooManyRequests, do(""))
+
+	assert.Equal(t, http.StatusOK, do("secret"), "authenticated clients have their own bucket")
+	assert.Equal(t, 2, client.Middleware.limiter.len())
+
+	ok, _ := client.Middleware.Allow("client:SUNET")
+	assert.True(t, ok)
+	ok, retryAfter := client.Middleware.Allow("client:SUNET")
+	assert.False(t, ok, "other servers share the buckets of the middleware")
+	assert.Greater(t, retryAfter, time.Duration(0))
+	assert.Equal(t, http.StatusTooManyRequests, do("secret"))
+}
+
+func TestAllowDisabled(t *testing.T) {
+	ctx := context.Background()
+
+	tracer, err := trace.NewForTesting(ctx, "test", logger.NewSimple("test"))
+	assert.NoError(t, err)
+	client, err := New(ctx, tracer, &model.Cfg{}, logger.NewSimple("test"))
+	assert.NoError(t, err)
+
+	for i := 0; i < 100; i++ {
+		ok, _ := client.Middleware.Allow("ip:192.0.2.1")
+		assert.True(t, ok)
+	}
+}
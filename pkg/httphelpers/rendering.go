@@ -2,10 +2,16 @@ package httphelpers
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
 	"vc/pkg/helpers"
 	"vc/pkg/logger"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type renderingHandler struct {
@@ -24,6 +30,53 @@ func (r *renderingHandler) Content(ctx context.Context, c *gin.Context, code int
 	case "*/*": // curl
 		c.JSON(code, data)
 	default:
-		c.JSON(406, gin.H{"error": helpers.NewErrorDetails("not_acceptable", "Accept header is invalid. It should be \"application/json\".")})
+		r.Problem(ctx, c, helpers.NewErrorDetails("NOT_ACCEPTABLE", "Accept header is invalid. It should be \"application/json\"."))
 	}
 }
+
+// Problem renders err as an RFC 7807 problem details object and aborts the request
+func (r *renderingHandler) Problem(ctx context.Context, c *gin.Context, err error) {
+	problem := helpers.NewProblem(err)
+	problem.Instance = c.Request.URL.Path
+
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		problem.TraceID = spanContext.TraceID().String()
+	}
+
+	c.Header("Content-Type", helpers.MIMEProblemJSON)
+	c.AbortWithStatusJSON(problem.Status, problem)
+}
+
+// ETag sets the ETag header to the document revision
+func (r *renderingHandler) ETag(c *gin.Context, revision int64) {
+	c.Header("ETag", fmt.Sprintf("%q", strconv.FormatInt(revision, 10)))
+}
+
+// NotModified sets the ETag and, unless lastModified is 0, the Last-Modified header of a response. It sends 304 Not
+// Modified and returns true if If-None-Match, or without it If-Modified-Since, shows that the client is up to date.
+func (r *renderingHandler) NotModified(c *gin.Context, etag string, lastModified int64) bool {
+	quoted := strconv.Quote(etag)
+	c.Header("ETag", quoted)
+	if lastModified > 0 {
+		c.Header("Last-Modified", time.Unix(lastModified, 0).UTC().Format(http.TimeFormat))
+	}
+
+	notModified := false
+	if ifNoneMatch := c.GetHeader("If-None-Match"); ifNoneMatch != "" {
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == quoted {
+				notModified = true
+				break
+			}
+		}
+	} else if ifModifiedSince := c.GetHeader("If-Modified-Since"); ifModifiedSince != "" && lastModified > 0 {
+		since, err := http.ParseTime(ifModifiedSince)
+		notModified = err == nil && lastModified <= since.Unix()
+	}
+
+	if notModified {
+		c.AbortWithStatus(http.StatusNotModified)
+	}
+	return notModified
+}
@@ -0,0 +1,71 @@
+package httphelpers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+	"vc/pkg/trace"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotModified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	tracer, err := trace.NewForTesting(ctx, "test", logger.NewSimple("test"))
+	assert.NoError(t, err)
+	client, err := New(ctx, tracer, &model.Cfg{}, logger.NewSimple("test"))
+	assert.NoError(t, err)
+
+	lastModified := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	engine := gin.New()
+	engine.POST("/document/list", func(c *gin.Context) {
+		if client.Rendering.NotModified(c, "abc", lastModified.Unix()) {
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": []string{}})
+	})
+
+	tts := []struct {
+		name    string
+		headers map[string]string
+		want    int
+	}{
+		{name: "unconditional", want: http.StatusOK},
+		{name: "etag matches", headers: map[string]string{"If-None-Match": `"abc"`}, want: http.StatusNotModified},
+		{name: "weak etag matches", headers: map[string]string{"If-None-Match": `"xyz", W/"abc"`}, want: http.StatusNotModified},
+		{name: "etag differs", headers: map[string]string{"If-None-Match": `"xyz"`}, want: http.StatusOK},
+		{name: "not modified since", headers: map[string]string{"If-Modified-Since": lastModified.Format(http.TimeFormat)}, want: http.StatusNotModified},
+		{name: "modified since", headers: map[string]string{"If-Modified-Since": lastModified.Add(-time.Second).Format(http.TimeFormat)}, want: http.StatusOK},
+		{
+			name:    "etag takes precedence",
+			headers: map[string]string{"If-None-Match": `"xyz"`, "If-Modified-Since": lastModified.Format(http.TimeFormat)},
+			want:    http.StatusOK,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/document/list", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.want, w.Code)
+			assert.Equal(t, `"abc"`, w.Header().Get("ETag"))
+			assert.Equal(t, "Wed, 01 May 2024 12:00:00 GMT", w.Header().Get("Last-Modified"))
+			if tt.want == http.StatusNotModified {
+				assert.Empty(t, w.Body.String())
+			}
+		})
+	}
+}
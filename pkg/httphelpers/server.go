@@ -2,12 +2,16 @@ package httphelpers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 	"vc/pkg/helpers"
 	"vc/pkg/logger"
 	"vc/pkg/model"
+	"vc/pkg/tenant"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
@@ -16,6 +20,13 @@ import (
 type serverHandler struct {
 	log    *logger.Log
 	client *Client
+
+	// draining is set when shutdown starts, the readiness probe then responds 503
+	draining atomic.Bool
+
+	healthMu      sync.RWMutex
+	healthChecks  map[string]HealthCheck
+	healthTimeout time.Duration
 }
 
 // ListenAndServe starts the HTTP server with TLS or without based on the APIServer.TLS configuration
@@ -23,13 +34,20 @@ func (s *serverHandler) ListenAndServe(ctx context.Context, server *http.Server,
 	if apiConfig.TLS.Enabled {
 		server.TLSConfig = s.client.TLS.Standard(ctx)
 
+		if apiConfig.TLS.ClientCAFilePath != "" {
+			if err := s.client.TLS.ClientAuth(ctx, server.TLSConfig, apiConfig.TLS.ClientCAFilePath); err != nil {
+				s.log.Error(err, "client_ca")
+				return err
+			}
+		}
+
 		err := server.ListenAndServeTLS(apiConfig.TLS.CertFilePath, apiConfig.TLS.KeyFilePath)
-		if err != nil {
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			s.log.Error(err, "listen_and_server_tls")
 			return err
 		}
 	} else {
-		if err := server.ListenAndServe(); err != nil {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			s.log.Error(err, "listen_and_server")
 			return err
 		}
@@ -38,17 +56,42 @@ func (s *serverHandler) ListenAndServe(ctx context.Context, server *http.Server,
 	return nil
 }
 
+// Shutdown drains the server: the readiness probe responds 503 for the drain delay, then the server stops accepting connections
+// and waits up to the shutdown timeout for in-flight requests before closing the remaining connections.
+func (s *serverHandler) Shutdown(ctx context.Context, server *http.Server, apiConfig model.APIServer) error {
+	s.draining.Store(true)
+	s.log.Info("Draining", "drain_delay", apiConfig.Shutdown.DrainDelay, "timeout", apiConfig.Shutdown.Timeout)
+
+	time.Sleep(time.Duration(apiConfig.Shutdown.DrainDelay) * time.Second)
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(apiConfig.Shutdown.Timeout)*time.Second)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		s.log.Error(err, "shutdown timeout, closing remaining connections")
+		return server.Close()
+	}
+
+	return nil
+}
+
 // RegEndpoint registers an endpoint with the gin router
 func (s *serverHandler) RegEndpoint(ctx context.Context, rg *gin.RouterGroup, method, path string, handler func(context.Context, *gin.Context) (any, error)) {
 	rg.Handle(method, path, func(c *gin.Context) {
 		k := fmt.Sprintf("api_endpoint %s:%s%s", method, rg.BasePath(), path)
+		ctx := tenant.NewContext(ctx, tenant.FromContext(c.Request.Context()))
 		ctx, span := s.client.tracer.Start(ctx, k)
 		defer span.End()
 
 		res, err := handler(ctx, c)
 		if err != nil {
-			s.log.Debug("RegEndpoint", "err", err)
-			s.client.Rendering.Content(ctx, c, 400, gin.H{"error": helpers.NewErrorFromError(err)})
+			s.log.WithContext(ctx).Debug("RegEndpoint", "err", err)
+			s.client.Rendering.Problem(ctx, c, err)
+			return
+		}
+
+		// the handler has already rendered its own content, e.g. a non-JSON media type
+		if c.Writer.Written() {
 			return
 		}
 
@@ -67,7 +110,7 @@ func (s *serverHandler) SetGinProductionMode() {
 }
 
 // Default sets the default server configuration
-func (s *serverHandler) Default(ctx context.Context, serverHTTP *http.Server, serverGin *gin.Engine, APIAddr string) (*gin.RouterGroup, error) {
+func (s *serverHandler) Default(ctx context.Context, serverHTTP *http.Server, serverGin *gin.Engine, apiConfig model.APIServer) (*gin.RouterGroup, error) {
 	s.SetGinProductionMode()
 
 	var err error
@@ -77,7 +120,7 @@ func (s *serverHandler) Default(ctx context.Context, serverHTTP *http.Server, se
 	}
 
 	serverHTTP.Handler = serverGin
-	serverHTTP.Addr = APIAddr
+	serverHTTP.Addr = apiConfig.Addr
 	serverHTTP.ReadTimeout = 5 * time.Second
 	serverHTTP.WriteTimeout = 30 * time.Second
 	serverHTTP.IdleTimeout = 90 * time.Second
@@ -88,13 +131,65 @@ func (s *serverHandler) Default(ctx context.Context, serverHTTP *http.Server, se
 	serverGin.Use(s.client.Middleware.Duration(ctx))
 	serverGin.Use(s.client.Middleware.Logger(ctx))
 	serverGin.Use(s.client.Middleware.Crash(ctx))
+
+	metrics, err := s.client.Middleware.Metrics(ctx)
+	if err != nil {
+		return nil, err
+	}
+	serverGin.Use(metrics)
+
+	if apiConfig.RateLimit.Enabled {
+		rateLimit, err := s.client.Middleware.RateLimit(ctx, apiConfig.RateLimit, apiConfig.Auth)
+		if err != nil {
+			return nil, err
+		}
+		serverGin.Use(rateLimit)
+	}
+
+	if apiConfig.MaxRequestBodySize > 0 {
+		maxBodySize, err := s.client.Middleware.MaxBodySize(ctx, apiConfig.MaxRequestBodySize)
+		if err != nil {
+			return nil, err
+		}
+		serverGin.Use(maxBodySize)
+	}
+
 	problem404, err := helpers.Problem404()
 	if err != nil {
 		return nil, err
 	}
 	serverGin.NoRoute(func(c *gin.Context) { c.JSON(http.StatusNotFound, problem404) })
 
+	s.healthTimeout = time.Duration(apiConfig.HealthCheck.Timeout) * time.Second
+	serverGin.GET("/ready", s.ready)
+	serverGin.GET("/health/live", s.live)
+	serverGin.GET("/health/ready", s.ready)
+
+	if s.client.tracer.Prometheus != nil {
+		serverGin.GET("/metrics", gin.WrapH(s.client.tracer.Prometheus))
+	}
+
 	rgRoot := serverGin.Group("/")
 
 	return rgRoot, nil
 }
+
+// UpdateLimits applies changed rate limit and body size limits to a running server.
+// Enabling or disabling a limit changes the middleware chain and requires a restart.
+func (s *serverHandler) UpdateLimits(ctx context.Context, apiConfig model.APIServer) {
+	m := s.client.Middleware
+
+	switch {
+	case m.limiter == nil && apiConfig.RateLimit.Enabled, m.limiter != nil && !apiConfig.RateLimit.Enabled:
+		s.log.Info("Rate limit enabled flag changed, restart required")
+	case m.limiter != nil:
+		m.limiter.setLimits(apiConfig.RateLimit.RequestsPerSecond, apiConfig.RateLimit.Burst)
+	}
+
+	switch {
+	case (m.maxBodySize.Load() > 0) != (apiConfig.MaxRequestBodySize > 0):
+		s.log.Info("Max request body size enabled state changed, restart required")
+	case apiConfig.MaxRequestBodySize > 0:
+		m.maxBodySize.Store(apiConfig.MaxRequestBodySize)
+	}
+}
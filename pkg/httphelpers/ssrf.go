@@ -0,0 +1,90 @@
+package httphelpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrNonPublicAddress is returned for outbound requests to loopback, link-local, private or otherwise
+// non-public addresses
+var ErrNonPublicAddress = errors.New("address is not public")
+
+// PublicIP reports whether ip is a public unicast address
+func PublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified())
+}
+
+// AllowedURL reports whether rawURL starts with a prefix in allowList, nothing is allowed if allowList is empty
+func AllowedURL(rawURL string, allowList []string) bool {
+	for _, prefix := range allowList {
+		if strings.HasPrefix(rawURL, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckPublicURL resolves the host of rawURL and returns ErrNonPublicAddress unless every address it resolves to is
+// public
+func CheckPublicURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if !PublicIP(addr.IP) {
+			return fmt.Errorf("%s: %w", addr.IP, ErrNonPublicAddress)
+		}
+	}
+
+	return nil
+}
+
+// NewPublicClient returns an HTTP client that only connects to public addresses. The address is checked when the
+// connection is made, after DNS resolution, so a host can not be rebound to an internal address after it was
+// checked. Redirects are not followed.
+func NewPublicClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !PublicIP(ip) {
+				return fmt.Errorf("%s: %w", host, ErrNonPublicAddress)
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
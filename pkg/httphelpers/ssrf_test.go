@@ -0,0 +1,101 @@
+package httphelpers
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublicIP(t *testing.T) {
+	tts := []struct {
+		name string
+		ip   string
+		want bool
+	}{
+		{name: "public v4", ip: "93.184.216.34", want: true},
+		{name: "public v6", ip: "2606:2800:220:1:248:1893:25c8:1946", want: true},
+		{name: "loopback v4", ip: "127.0.0.1"},
+		{name: "loopback v6", ip: "::1"},
+		{name: "private 10/8", ip: "10.1.2.3"},
+		{name: "private 172.16/12", ip: "172.20.0.1"},
+		{name: "private 192.168/16", ip: "192.168.1.1"},
+		{name: "unique local v6", ip: "fd00::1"},
+		{name: "link-local metadata", ip: "169.254.169.254"},
+		{name: "link-local v6", ip: "fe80::1"},
+		{name: "unspecified", ip: "0.0.0.0"},
+		{name: "multicast", ip: "224.0.0.1"},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, PublicIP(net.ParseIP(tt.ip)))
+		})
+	}
+}
+
+func TestAllowedURL(t *testing.T) {
+	tts := []struct {
+		name      string
+		url       string
+		allowList []string
+		want      bool
+	}{
+		{
+			name:      "prefix",
+			url:       "https://source.example.com/hooks/vc",
+			allowList: []string{"https://other.example.com/", "https://source.example.com/"},
+			want:      true,
+		},
+		{
+			name:      "host suffix",
+			url:       "https://source.example.com.attacker.net/",
+			allowList: []string{"https://source.example.com/"},
+		},
+		{
+			name: "empty allow list",
+			url:  "https://source.example.com/",
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, AllowedURL(tt.url, tt.allowList))
+		})
+	}
+}
+
+func TestCheckPublicURL(t *testing.T) {
+	tts := []struct {
+		name    string
+		url     string
+		wantErr error
+	}{
+		{name: "loopback", url: "http://127.0.0.1:8080/", wantErr: ErrNonPublicAddress},
+		{name: "localhost", url: "http://localhost/", wantErr: ErrNonPublicAddress},
+		{name: "metadata", url: "http://169.254.169.254/latest/meta-data", wantErr: ErrNonPublicAddress},
+		{name: "private v6", url: "http://[fd00::1]/", wantErr: ErrNonPublicAddress},
+		{name: "public", url: "https://93.184.216.34/"},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckPublicURL(context.Background(), tt.url)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestNewPublicClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	_, err := NewPublicClient(time.Second).Get(server.URL)
+	assert.ErrorIs(t, err, ErrNonPublicAddress)
+}
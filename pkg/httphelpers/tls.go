@@ -3,6 +3,9 @@ package httphelpers
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
 	"vc/pkg/logger"
 )
 
@@ -21,3 +24,22 @@ func (t *tlsHandler) Standard(ctx context.Context) *tls.Config {
 
 	return tlsConfig
 }
+
+// ClientAuth makes tlsConfig request client certificates and verify them against the CAs in caFilePath.
+// Clients without a certificate are still accepted, to allow API key authentication.
+func (t *tlsHandler) ClientAuth(ctx context.Context, tlsConfig *tls.Config, caFilePath string) error {
+	caPEM, err := os.ReadFile(caFilePath)
+	if err != nil {
+		return err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return errors.New("no certificates found in client CA file")
+	}
+
+	tlsConfig.ClientCAs = pool
+	tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+
+	return nil
+}
@@ -0,0 +1,216 @@
+// Package identitymatch scores how well the identity of a collect request matches an identity of a document, so small
+// differences in spelling, script or birth date do not stop a holder from collecting their document.
+package identitymatch
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"time"
+	"vc/pkg/model"
+)
+
+// Decisions of a match
+const (
+	// DecisionApprove means the identities match well enough to collect the document
+	DecisionApprove = "approve"
+
+	// DecisionReview means the identities may match and a person has to decide
+	DecisionReview = "review"
+
+	// DecisionReject means the identities do not match
+	DecisionReject = "reject"
+)
+
+// Fields of the score
+const (
+	FieldFamilyName = "family_name"
+	FieldGivenName  = "given_name"
+	FieldBirthDate  = "birth_date"
+)
+
+// Result is the score of one identity against another
+type Result struct {
+	// Score is the weighted mean of the field scores, from 0 to 1
+	Score float64 `json:"score"`
+
+	// Fields are the scores of family_name, given_name and birth_date
+	Fields map[string]float64 `json:"fields"`
+
+	// Decision is approve, review or reject
+	Decision string `json:"decision"`
+}
+
+// Matcher scores identities with the weights, thresholds and birth date tolerance of its configuration
+type Matcher struct {
+	cfg model.IdentityMatching
+}
+
+// New creates a matcher from cfg
+func New(cfg model.IdentityMatching) *Matcher {
+	return &Matcher{cfg: cfg}
+}
+
+// Match scores got against want
+func (m *Matcher) Match(want, got *model.Identity) *Result {
+	fields := map[string]float64{
+		FieldFamilyName: nameScore(want.FamilyName, got.FamilyName),
+		FieldGivenName:  nameScore(want.GivenName, got.GivenName),
+		FieldBirthDate:  birthDateScore(want.BirthDate, got.BirthDate, m.cfg.BirthDateTolerance),
+	}
+	weights := map[string]float64{
+		FieldFamilyName: m.cfg.FamilyNameWeight,
+		FieldGivenName:  m.cfg.GivenNameWeight,
+		FieldBirthDate:  m.cfg.BirthDateWeight,
+	}
+
+	var sum, total float64
+	for field, weight := range weights {
+		sum += weight * fields[field]
+		total += weight
+	}
+
+	result := &Result{Fields: fields}
+	if total > 0 {
+		// rounded so a score does not land just below a threshold by floating point error
+		result.Score = math.Round(sum/total*1e4) / 1e4
+	}
+
+	switch {
+	case result.Score >= m.cfg.ApproveScore:
+		result.Decision = DecisionApprove
+	case result.Score >= m.cfg.ReviewScore:
+		result.Decision = DecisionReview
+	default:
+		result.Decision = DecisionReject
+	}
+
+	return result
+}
+
+// Best returns the index of the identity of candidates matching want best and its result, -1 and nil if there are no
+// candidates
+func (m *Matcher) Best(want *model.Identity, candidates []model.Identity) (int, *Result) {
+	best, bestResult := -1, (*Result)(nil)
+	for i := range candidates {
+		result := m.Match(want, &candidates[i])
+		if bestResult == nil || result.Score > bestResult.Score {
+			best, bestResult = i, result
+		}
+	}
+
+	return best, bestResult
+}
+
+// nameScore scores two names from 0 to 1, the best of the similarity of the names as written, with the names in any
+// order and with the names written together. A name that is all of the names of the other, like a missing middle name, scores 0.9.
+func nameScore(a, b string) float64 {
+	a, b = Normalize(a), Normalize(b)
+	if a == b {
+		return 1
+	}
+	if a == "" || b == "" {
+		return 0
+	}
+
+	tokensA, tokensB := strings.Fields(a), strings.Fields(b)
+	slices.Sort(tokensA)
+	slices.Sort(tokensB)
+
+	score := max(
+		jaroWinkler(a, b),
+		jaroWinkler(strings.Join(tokensA, " "), strings.Join(tokensB, " ")),
+		jaroWinkler(strings.ReplaceAll(a, " ", ""), strings.ReplaceAll(b, " ", "")),
+	)
+	if subset(tokensA, tokensB) || subset(tokensB, tokensA) {
+		score = max(score, 0.9)
+	}
+
+	return score
+}
+
+// subset reports if every token of a is in b, a and b sorted
+func subset(a, b []string) bool {
+	for _, token := range a {
+		if _, ok := slices.BinarySearch(b, token); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// birthDateScore scores two birth dates, 1 if equal, 0.8 if day and month are swapped and down to 0.5 the further
+// apart they are within toleranceDays. Dates that are not YYYY-MM-DD score 1 only if equal.
+func birthDateScore(a, b string, toleranceDays int) float64 {
+	if a == b {
+		return 1
+	}
+
+	dateA, errA := time.Parse(time.DateOnly, a)
+	dateB, errB := time.Parse(time.DateOnly, b)
+	if errA != nil || errB != nil {
+		return 0
+	}
+
+	if dateA.Year() == dateB.Year() && int(dateA.Month()) == dateB.Day() && dateA.Day() == int(dateB.Month()) {
+		return 0.8
+	}
+
+	days := math.Abs(dateA.Sub(dateB).Hours() / 24)
+	if days > float64(toleranceDays) {
+		return 0
+	}
+
+	return 1 - 0.5*days/float64(toleranceDays)
+}
+
+// jaroWinkler returns the Jaro-Winkler similarity of a and b, from 0 to 1
+func jaroWinkler(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+
+	window := max(len(ra), len(rb))/2 - 1
+	window = max(window, 0)
+
+	matchedA := make([]bool, len(ra))
+	matchedB := make([]bool, len(rb))
+	matches := 0
+	for i := range ra {
+		for j := max(0, i-window); j < min(len(rb), i+window+1); j++ {
+			if !matchedB[j] && ra[i] == rb[j] {
+				matchedA[i], matchedB[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions, j := 0, 0
+	for i := range ra {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[j] {
+			j++
+		}
+		if ra[i] != rb[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < min(4, len(ra), len(rb)) && ra[prefix] == rb[prefix] {
+		prefix++
+	}
+
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
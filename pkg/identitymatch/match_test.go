@@ -0,0 +1,162 @@
+package identitymatch
+
+import (
+	"testing"
+	"vc/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockMatcher(birthDateTolerance int) *Matcher {
+	return New(model.IdentityMatching{
+		Enabled:            true,
+		ApproveScore:       0.92,
+		ReviewScore:        0.75,
+		BirthDateTolerance: birthDateTolerance,
+		FamilyNameWeight:   0.4,
+		GivenNameWeight:    0.35,
+		BirthDateWeight:    0.25,
+	})
+}
+
+func TestNormalize(t *testing.T) {
+	tts := []struct {
+		have string
+		want string
+	}{
+		{have: "Svensson", want: "svensson"},
+		{have: "  Anna-Maria  O'Neil ", want: "anna maria o neil"},
+		{have: "Ærø-Müller, Jörg", want: "aero muller jorg"},
+		{have: "Straße", want: "strasse"},
+		{have: "Łukasz Wałęsa", want: "lukasz walesa"},
+		{have: "Жуков", want: "zhukov"},
+		{have: "Щербакова Юлия", want: "shcherbakova yuliya"},
+		{have: "Παπαδόπουλος", want: "papadopoulos"},
+		{have: "王", want: "王"},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.have, func(t *testing.T) {
+			assert.Equal(t, tt.want, Normalize(tt.have))
+		})
+	}
+}
+
+func TestNameScore(t *testing.T) {
+	tts := []struct {
+		name string
+		a, b string
+		min  float64
+		max  float64
+	}{
+		{name: "equal", a: "Svensson", b: "Svensson", min: 1, max: 1},
+		{name: "case and diacritics", a: "JÖRG", b: "jorg", min: 1, max: 1},
+		{name: "transliterated", a: "Жуков", b: "Zhukov", min: 1, max: 1},
+		{name: "typo", a: "Svensson", b: "Svenson", min: 0.95, max: 0.99},
+		{name: "order", a: "Anna Maria", b: "Maria Anna", min: 1, max: 1},
+		{name: "hyphen and space", a: "van der Berg", b: "Vanderberg", min: 1, max: 1},
+		{name: "missing middle name", a: "Anna Maria", b: "Anna", min: 0.9, max: 0.95},
+		{name: "different", a: "Svensson", b: "Karlsson", min: 0, max: 0.8},
+		{name: "empty", a: "Svensson", b: "", min: 0, max: 0},
+		{name: "other script", a: "王", b: "李", min: 0, max: 0},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nameScore(tt.a, tt.b)
+			assert.GreaterOrEqual(t, got, tt.min)
+			assert.LessOrEqual(t, got, tt.max)
+			assert.Equal(t, got, nameScore(tt.b, tt.a))
+		})
+	}
+}
+
+func TestBirthDateScore(t *testing.T) {
+	tts := []struct {
+		name      string
+		a, b      string
+		tolerance int
+		want      float64
+	}{
+		{name: "equal", a: "1970-01-02", b: "1970-01-02", want: 1},
+		{name: "swapped day and month", a: "1970-03-04", b: "1970-04-03", want: 0.8},
+		{name: "one day without tolerance", a: "1970-01-02", b: "1970-01-03", want: 0},
+		{name: "one day", a: "1970-01-02", b: "1970-01-03", tolerance: 2, want: 0.75},
+		{name: "at tolerance", a: "1970-01-02", b: "1969-12-31", tolerance: 2, want: 0.5},
+		{name: "past tolerance", a: "1970-01-02", b: "1969-12-30", tolerance: 2, want: 0},
+		{name: "not a date", a: "1970", b: "1971", tolerance: 2, want: 0},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, birthDateScore(tt.a, tt.b, tt.tolerance))
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	want := &model.Identity{FamilyName: "Müller", GivenName: "Anna Maria", BirthDate: "1970-01-02"}
+
+	tts := []struct {
+		name         string
+		got          *model.Identity
+		tolerance    int
+		wantDecision string
+	}{
+		{
+			name:         "equal",
+			got:          &model.Identity{FamilyName: "Müller", GivenName: "Anna Maria", BirthDate: "1970-01-02"},
+			wantDecision: DecisionApprove,
+		},
+		{
+			name:         "normalized",
+			got:          &model.Identity{FamilyName: "MULLER", GivenName: "Maria Anna", BirthDate: "1970-01-02"},
+			wantDecision: DecisionApprove,
+		},
+		{
+			name:         "birth date within tolerance",
+			got:          &model.Identity{FamilyName: "Muller", GivenName: "Anna Maria", BirthDate: "1970-01-03"},
+			tolerance:    3,
+			wantDecision: DecisionApprove,
+		},
+		{
+			name:         "birth date differs",
+			got:          &model.Identity{FamilyName: "Muller", GivenName: "Anna Maria", BirthDate: "1971-05-06"},
+			wantDecision: DecisionReview,
+		},
+		{
+			name:         "missing middle name and swapped birth date",
+			got:          &model.Identity{FamilyName: "Muller", GivenName: "Anna", BirthDate: "1970-02-01"},
+			wantDecision: DecisionReview,
+		},
+		{
+			name:         "other person",
+			got:          &model.Identity{FamilyName: "Svensson", GivenName: "Magnus", BirthDate: "1980-03-04"},
+			wantDecision: DecisionReject,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			result := mockMatcher(tt.tolerance).Match(want, tt.got)
+			assert.Equal(t, tt.wantDecision, result.Decision, "score %v fields %v", result.Score, result.Fields)
+			assert.Len(t, result.Fields, 3)
+		})
+	}
+}
+
+func TestBest(t *testing.T) {
+	m := mockMatcher(0)
+	want := &model.Identity{FamilyName: "Svensson", GivenName: "Magnus", BirthDate: "1970-01-02"}
+
+	i, result := m.Best(want, []model.Identity{
+		{FamilyName: "Karlsson", GivenName: "Anna", BirthDate: "1980-01-02"},
+		{FamilyName: "Svenson", GivenName: "Magnus", BirthDate: "1970-01-02"},
+	})
+	assert.Equal(t, 1, i)
+	assert.Equal(t, DecisionApprove, result.Decision)
+
+	i, result = m.Best(want, nil)
+	assert.Equal(t, -1, i)
+	assert.Nil(t, result)
+}
@@ -0,0 +1,60 @@
+package identitymatch
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// transliterations maps the letters that do not decompose to a latin letter and a diacritic, after lower casing
+var transliterations = map[rune]string{
+	// latin
+	'ß': "ss", 'æ': "ae", 'ø': "o", 'œ': "oe", 'ł': "l", 'đ': "d", 'ð': "d", 'þ': "th", 'ı': "i", 'ŀ': "l", 'ħ': "h",
+
+	// cyrillic, ISO 9 simplified to ascii
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ж': "zh", 'з': "z", 'и': "i", 'к': "k", 'л': "l",
+	'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya", 'і': "i", 'є': "ie",
+	'ґ': "g", 'ј': "j", 'љ': "lj", 'њ': "nj", 'ћ': "c", 'џ': "dz", 'ђ': "dj",
+
+	// greek, ELOT 743, ου is u
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th", 'ι': "i", 'κ': "k", 'λ': "l",
+	'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y", 'φ': "f",
+	'χ': "ch", 'ψ': "ps", 'ω': "o",
+}
+
+// Normalize folds name for comparison: lower case, diacritics removed, cyrillic, greek and special latin letters
+// transliterated and anything but letters and digits a single space. Example: "Ærø-Müller, Jörg" is "aero muller jorg".
+func Normalize(name string) string {
+	var b strings.Builder
+	space, prev := false, rune(0)
+	for _, r := range norm.NFD.String(strings.ToLower(name)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// the diacritic of a decomposed letter
+			continue
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			latin, ok := transliterations[r]
+			if !ok {
+				latin = string(r)
+			}
+			if r == 'υ' && prev == 'ο' {
+				latin = "u"
+			}
+			prev = r
+			if latin == "" {
+				continue
+			}
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			b.WriteString(latin)
+		default:
+			space, prev = true, 0
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,125 @@
+package jsondiff
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Operations of a change
+const (
+	OpAdded   = "added"
+	OpRemoved = "removed"
+	OpChanged = "changed"
+)
+
+// Change is one difference between two JSON documents
+type Change struct {
+	// required: true
+	// example: changed
+	Op string `json:"op"`
+
+	// Path is the JSON pointer, RFC 6901, of the changed value
+	// required: true
+	// example: /person/given_name
+	Path string `json:"path"`
+
+	// From is the value before the change, left out when the value is added
+	From any `json:"from,omitempty"`
+
+	// To is the value after the change, left out when the value is removed
+	To any `json:"to,omitempty"`
+}
+
+// Diff returns the changes from one JSON document to another, ordered by path. Objects are compared member by member
+// and arrays element by element, any other values are compared as a whole. Both documents are normalized by a JSON
+// round trip, so any value that marshals to JSON can be compared.
+func Diff(from, to any) ([]*Change, error) {
+	normFrom, err := normalize(from)
+	if err != nil {
+		return nil, err
+	}
+	normTo, err := normalize(to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []*Change{}
+	diff("", normFrom, normTo, &changes)
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes, nil
+}
+
+func normalize(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var res any
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func diff(path string, from, to any, changes *[]*Change) {
+	switch f := from.(type) {
+	case map[string]any:
+		if t, ok := to.(map[string]any); ok {
+			diffObject(path, f, t, changes)
+			return
+		}
+	case []any:
+		if t, ok := to.([]any); ok {
+			diffArray(path, f, t, changes)
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		*changes = append(*changes, &Change{Op: OpChanged, Path: path, From: from, To: to})
+	}
+}
+
+func diffObject(path string, from, to map[string]any, changes *[]*Change) {
+	for key, f := range from {
+		t, ok := to[key]
+		if !ok {
+			*changes = append(*changes, &Change{Op: OpRemoved, Path: pointer(path, key), From: f})
+			continue
+		}
+		diff(pointer(path, key), f, t, changes)
+	}
+
+	for key, t := range to {
+		if _, ok := from[key]; !ok {
+			*changes = append(*changes, &Change{Op: OpAdded, Path: pointer(path, key), To: t})
+		}
+	}
+}
+
+func diffArray(path string, from, to []any, changes *[]*Change) {
+	for i := 0; i < len(from) || i < len(to); i++ {
+		p := pointer(path, strconv.Itoa(i))
+		switch {
+		case i >= len(to):
+			*changes = append(*changes, &Change{Op: OpRemoved, Path: p, From: from[i]})
+		case i >= len(from):
+			*changes = append(*changes, &Change{Op: OpAdded, Path: p, To: to[i]})
+		default:
+			diff(p, from[i], to[i], changes)
+		}
+	}
+}
+
+// pointer appends token to the JSON pointer path, escaped by RFC 6901
+func pointer(path, token string) string {
+	return path + "/" + strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,84 @@
+package jsondiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	tts := []struct {
+		name string
+		from any
+		to   any
+		want []*Change
+	}{
+		{
+			name: "equal",
+			from: map[string]any{"a": 1, "b": []any{"x"}},
+			to:   map[string]any{"a": 1, "b": []any{"x"}},
+			want: []*Change{},
+		},
+		{
+			name: "members",
+			from: map[string]any{"given_name": "Alice", "family_name": "Smith", "birth_date": "1990-01-01"},
+			to:   map[string]any{"given_name": "Alice", "family_name": "Jones", "nationality": "SE"},
+			want: []*Change{
+				{Op: OpRemoved, Path: "/birth_date", From: "1990-01-01"},
+				{Op: OpChanged, Path: "/family_name", From: "Smith", To: "Jones"},
+				{Op: OpAdded, Path: "/nationality", To: "SE"},
+			},
+		},
+		{
+			name: "nested",
+			from: map[string]any{"person": map[string]any{"name": "Alice"}},
+			to:   map[string]any{"person": map[string]any{"name": "Bob"}},
+			want: []*Change{
+				{Op: OpChanged, Path: "/person/name", From: "Alice", To: "Bob"},
+			},
+		},
+		{
+			name: "arrays",
+			from: map[string]any{"places": []any{"Stockholm", "Oslo"}},
+			to:   map[string]any{"places": []any{"Stockholm", "Bergen", "Turku"}},
+			want: []*Change{
+				{Op: OpChanged, Path: "/places/1", From: "Oslo", To: "Bergen"},
+				{Op: OpAdded, Path: "/places/2", To: "Turku"},
+			},
+		},
+		{
+			name: "type change",
+			from: map[string]any{"address": "Main street 1"},
+			to:   map[string]any{"address": map[string]any{"street": "Main street 1"}},
+			want: []*Change{
+				{Op: OpChanged, Path: "/address", From: "Main street 1", To: map[string]any{"street": "Main street 1"}},
+			},
+		},
+		{
+			name: "escaped path",
+			from: map[string]any{"a/b": 1, "c~d": 1},
+			to:   map[string]any{"a/b": 2, "c~d": 2},
+			want: []*Change{
+				{Op: OpChanged, Path: "/a~1b", From: float64(1), To: float64(2)},
+				{Op: OpChanged, Path: "/c~0d", From: float64(1), To: float64(2)},
+			},
+		},
+		{
+			name: "normalized",
+			from: map[string]any{"n": 1, "list": []string{"x"}},
+			to:   map[string]any{"n": 1.0, "list": []any{"x"}},
+			want: []*Change{},
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Diff(tt.from, tt.to)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := Diff(map[string]any{"f": func() {}}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,136 @@
+package keyresolver
+
+import (
+	"container/list"
+	"context"
+	"crypto"
+	"errors"
+	"sync"
+	"time"
+)
+
+type cacheEntry struct {
+	id        string
+	key       crypto.PublicKey
+	err       error
+	expiresAt time.Time
+}
+
+// Cache wraps a resolver with a least recently used cache of its results, so verifying many credentials of the same
+// issuer does not resolve the issuer key every time. Failed resolutions are cached too, for a shorter time, so an
+// unknown identifier does not reach the wrapped resolver on every request.
+type Cache struct {
+	resolver    Resolver
+	size        int
+	ttl         time.Duration
+	negativeTTL time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+
+	// lru holds the entries, the most recently used first
+	lru *list.List
+}
+
+// NewCache creates a cache of at most size results of resolver, keys are cached for ttl and failures for negativeTTL.
+// Failures are not cached if negativeTTL is zero.
+func NewCache(resolver Resolver, size int, ttl, negativeTTL time.Duration) *Cache {
+	return &Cache{
+		resolver:    resolver,
+		size:        size,
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		entries:     map[string]*list.Element{},
+		lru:         list.New(),
+	}
+}
+
+// Resolve implements Resolver
+func (c *Cache) Resolve(ctx context.Context, id string) (crypto.PublicKey, error) {
+	if entry, ok := c.get(id); ok {
+		return entry.key, entry.err
+	}
+
+	key, err := c.resolver.Resolve(ctx, id)
+	switch {
+	case err == nil:
+		c.add(&cacheEntry{id: id, key: key, expiresAt: time.Now().Add(c.ttl)})
+	case c.negativeTTL > 0 && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded):
+		// a cancelled request says nothing about the identifier
+		c.add(&cacheEntry{id: id, err: err, expiresAt: time.Now().Add(c.negativeTTL)})
+	}
+
+	return key, err
+}
+
+// Invalidate removes the cached result of id, e.g. when the key of an issuer is known to have been rotated
+func (c *Cache) Invalidate(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[id]; ok {
+		c.remove(element)
+	}
+}
+
+// Purge removes every cached result
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = map[string]*list.Element{}
+	c.lru.Init()
+}
+
+// Len returns the number of cached results, expired results included until they are evicted
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.Len()
+}
+
+func (c *Cache) get(id string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[id]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*cacheEntry)
+	if !time.Now().Before(entry.expiresAt) {
+		c.remove(element)
+		return nil, false
+	}
+	c.lru.MoveToFront(element)
+
+	return entry, true
+}
+
+// add stores entry, the least recently used entry is evicted when the cache is full
+func (c *Cache) add(entry *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.size <= 0 {
+		return
+	}
+
+	if element, ok := c.entries[entry.id]; ok {
+		element.Value = entry
+		c.lru.MoveToFront(element)
+		return
+	}
+
+	c.entries[entry.id] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *Cache) remove(element *list.Element) {
+	delete(c.entries, element.Value.(*cacheEntry).id)
+	c.lru.Remove(element)
+}
@@ -0,0 +1,107 @@
+package keyresolver
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingResolver counts the resolutions reaching the wrapped resolver
+type countingResolver struct {
+	Resolver
+	calls map[string]int
+}
+
+func (r *countingResolver) Resolve(ctx context.Context, id string) (crypto.PublicKey, error) {
+	r.calls[id]++
+	return r.Resolver.Resolve(ctx, id)
+}
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+
+	keys := Static{}
+	for _, id := range []string{"did:example:a", "did:example:b", "did:example:c"} {
+		publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+		assert.NoError(t, err)
+		keys[id] = publicKey
+	}
+
+	t.Run("hit", func(t *testing.T) {
+		resolver := &countingResolver{Resolver: keys, calls: map[string]int{}}
+		cache := NewCache(resolver, 10, time.Minute, time.Minute)
+
+		for range 3 {
+			key, err := cache.Resolve(ctx, "did:example:a")
+			assert.NoError(t, err)
+			assert.Equal(t, keys["did:example:a"], key)
+		}
+		assert.Equal(t, 1, resolver.calls["did:example:a"])
+	})
+
+	t.Run("negative", func(t *testing.T) {
+		resolver := &countingResolver{Resolver: keys, calls: map[string]int{}}
+		cache := NewCache(resolver, 10, time.Minute, time.Minute)
+
+		for range 3 {
+			_, err := cache.Resolve(ctx, "did:example:unknown")
+			assert.ErrorIs(t, err, ErrKeyNotFound)
+		}
+		assert.Equal(t, 1, resolver.calls["did:example:unknown"])
+	})
+
+	t.Run("negative disabled", func(t *testing.T) {
+		resolver := &countingResolver{Resolver: keys, calls: map[string]int{}}
+		cache := NewCache(resolver, 10, time.Minute, 0)
+
+		for range 2 {
+			_, err := cache.Resolve(ctx, "did:example:unknown")
+			assert.ErrorIs(t, err, ErrKeyNotFound)
+		}
+		assert.Equal(t, 2, resolver.calls["did:example:unknown"])
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		resolver := &countingResolver{Resolver: keys, calls: map[string]int{}}
+		cache := NewCache(resolver, 10, 0, 0)
+
+		for range 2 {
+			_, err := cache.Resolve(ctx, "did:example:a")
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 2, resolver.calls["did:example:a"])
+	})
+
+	t.Run("least recently used evicted", func(t *testing.T) {
+		resolver := &countingResolver{Resolver: keys, calls: map[string]int{}}
+		cache := NewCache(resolver, 2, time.Minute, time.Minute)
+
+		for _, id := range []string{"did:example:a", "did:example:b", "did:example:a", "did:example:c", "did:example:a", "did:example:b"} {
+			_, err := cache.Resolve(ctx, id)
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 2, cache.Len())
+		assert.Equal(t, 1, resolver.calls["did:example:a"])
+		assert.Equal(t, 2, resolver.calls["did:example:b"], "b is evicted by c")
+	})
+
+	t.Run("invalidate", func(t *testing.T) {
+		resolver := &countingResolver{Resolver: keys, calls: map[string]int{}}
+		cache := NewCache(resolver, 10, time.Minute, time.Minute)
+
+		_, err := cache.Resolve(ctx, "did:example:a")
+		assert.NoError(t, err)
+		cache.Invalidate("did:example:a")
+		_, err = cache.Resolve(ctx, "did:example:a")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, resolver.calls["did:example:a"])
+
+		cache.Purge()
+		assert.Equal(t, 0, cache.Len())
+	})
+}
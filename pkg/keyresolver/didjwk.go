@@ -0,0 +1,58 @@
+package keyresolver
+
+import (
+	"context"
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/lestrrat-go/jwx/jwk"
+)
+
+// DIDJWK resolves did:jwk identifiers, the public key is a base64url encoded JWK in the identifier itself
+type DIDJWK struct{}
+
+// Resolve implements Resolver
+func (DIDJWK) Resolve(ctx context.Context, id string) (crypto.PublicKey, error) {
+	specificID, err := splitDIDURL(id, "jwk")
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(specificID)
+	if err != nil {
+		return nil, ErrInvalidIdentifier
+	}
+
+	key, err := jwk.ParseKey(b)
+	if err != nil {
+		return nil, ErrInvalidIdentifier
+	}
+
+	// a did:jwk must not carry private key material
+	if _, ok := key.(interface{ D() []byte }); ok {
+		return nil, ErrInvalidIdentifier
+	}
+
+	var publicKey any
+	if err := key.Raw(&publicKey); err != nil {
+		return nil, ErrUnsupportedKeyType
+	}
+
+	return publicKey, nil
+}
+
+// EncodeDIDJWK returns the did:jwk identifier of publicKey
+func EncodeDIDJWK(publicKey crypto.PublicKey) (string, error) {
+	key, err := jwk.New(publicKey)
+	if err != nil {
+		return "", ErrUnsupportedKeyType
+	}
+
+	b, err := json.Marshal(key)
+	if err != nil {
+		return "", err
+	}
+
+	return "did:jwk:" + base64.RawURLEncoding.EncodeToString(b), nil
+}
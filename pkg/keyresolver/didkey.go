@@ -0,0 +1,111 @@
+package keyresolver
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"encoding/binary"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// multicodec codes of the supported public key types
+const (
+	multicodecSecp256k1 = 0xe7
+	multicodecEd25519   = 0xed
+	multicodecP256      = 0x1200
+	multicodecP384      = 0x1201
+)
+
+// DIDKey resolves did:key identifiers, the public key is decoded from the identifier itself
+type DIDKey struct{}
+
+// Resolve implements Resolver
+func (DIDKey) Resolve(ctx context.Context, id string) (crypto.PublicKey, error) {
+	specificID, err := splitDIDURL(id, "key")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeMultikey(specificID)
+}
+
+// decodeMultikey decodes a multibase base58btc encoded multicodec public key, as in did:key and publicKeyMultibase
+func decodeMultikey(multikey string) (crypto.PublicKey, error) {
+	// multibase prefix z is base58btc, the only encoding allowed by did:key
+	if multikey == "" || multikey[0] != 'z' {
+		return nil, ErrInvalidIdentifier
+	}
+	b, err := base58Decode(multikey[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	code, n := binary.Uvarint(b)
+	if n <= 0 {
+		return nil, ErrInvalidIdentifier
+	}
+	keyBytes := b[n:]
+
+	switch code {
+	case multicodecEd25519:
+		if len(keyBytes) != ed25519.PublicKeySize {
+			return nil, ErrInvalidIdentifier
+		}
+		return ed25519.PublicKey(keyBytes), nil
+	case multicodecP256:
+		return unmarshalCompressed(elliptic.P256(), keyBytes)
+	case multicodecP384:
+		return unmarshalCompressed(elliptic.P384(), keyBytes)
+	case multicodecSecp256k1:
+		publicKey, err := secp256k1.ParsePubKey(keyBytes)
+		if err != nil {
+			return nil, ErrInvalidIdentifier
+		}
+		return publicKey.ToECDSA(), nil
+	default:
+		return nil, ErrUnsupportedKeyType
+	}
+}
+
+func unmarshalCompressed(curve elliptic.Curve, b []byte) (*ecdsa.PublicKey, error) {
+	x, y := elliptic.UnmarshalCompressed(curve, b)
+	if x == nil {
+		return nil, ErrInvalidIdentifier
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+// EncodeDIDKey returns the did:key identifier of publicKey
+func EncodeDIDKey(publicKey crypto.PublicKey) (string, error) {
+	var (
+		code     uint64
+		keyBytes []byte
+	)
+
+	switch pub := publicKey.(type) {
+	case ed25519.PublicKey:
+		code, keyBytes = multicodecEd25519, pub
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P256():
+			code = multicodecP256
+		case elliptic.P384():
+			code = multicodecP384
+		case secp256k1.S256():
+			code = multicodecSecp256k1
+		default:
+			return "", ErrUnsupportedKeyType
+		}
+		keyBytes = elliptic.MarshalCompressed(pub.Curve, pub.X, pub.Y)
+	default:
+		return "", ErrUnsupportedKeyType
+	}
+
+	b := binary.AppendUvarint(nil, code)
+	b = append(b, keyBytes...)
+
+	return "did:key:z" + base58Encode(b), nil
+}
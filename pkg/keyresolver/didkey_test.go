@@ -0,0 +1,119 @@
+package keyresolver
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDIDKeyResolve(t *testing.T) {
+	tts := []struct {
+		name    string
+		id      string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "Ed25519",
+			id:   "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK#z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK",
+			want: "ed25519",
+		},
+		{
+			name: "P-256",
+			id:   "did:key:zDnaerDaTF5BXEavCrfRZEk316dpbLsfPDZ3WJ5hRTPFU2169",
+			want: "P-256",
+		},
+		{
+			name: "P-384",
+			id:   "did:key:z82Lm1MpAkeJcix9K8TMiLd5NMAhnwkjjCBeWHXyu3U4oT2MVJJKXkcVBgjGhnLBn2Kaau9",
+			want: "P-384",
+		},
+		{
+			name: "secp256k1",
+			id:   "did:key:zQ3shokFTS3brHcDQrn82RUDfCZESWL1ZdCEJwekUDPQiYBme",
+			want: "secp256k1",
+		},
+		{
+			name:    "other method",
+			id:      "did:web:example.com",
+			wantErr: ErrUnsupportedMethod,
+		},
+		{
+			name:    "not base58btc",
+			id:      "did:key:m6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK",
+			wantErr: ErrInvalidIdentifier,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := DIDKey{}.Resolve(context.Background(), tt.id)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, keyName(key))
+		})
+	}
+}
+
+func keyName(key crypto.PublicKey) string {
+	switch k := key.(type) {
+	case ed25519.PublicKey:
+		return "ed25519"
+	case *ecdsa.PublicKey:
+		if k.Curve == secp256k1.S256() {
+			return "secp256k1"
+		}
+		return k.Curve.Params().Name
+	}
+	return ""
+}
+
+func TestDIDKeyRoundTrip(t *testing.T) {
+	edKey, _, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	assert.NoError(t, err)
+	k256, err := secp256k1.GeneratePrivateKey()
+	assert.NoError(t, err)
+
+	for _, publicKey := range []crypto.PublicKey{edKey, &p256.PublicKey, &p384.PublicKey, k256.PubKey().ToECDSA()} {
+		id, err := EncodeDIDKey(publicKey)
+		assert.NoError(t, err)
+
+		key, err := DIDKey{}.Resolve(context.Background(), id)
+		assert.NoError(t, err)
+		assert.True(t, key.(interface{ Equal(crypto.PublicKey) bool }).Equal(publicKey), id)
+
+		_, err = DIDJWK{}.Resolve(context.Background(), id)
+		assert.ErrorIs(t, err, ErrUnsupportedMethod)
+	}
+}
+
+func TestDIDJWKRoundTrip(t *testing.T) {
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	id, err := EncodeDIDJWK(&p256.PublicKey)
+	assert.NoError(t, err)
+
+	key, err := DIDJWK{}.Resolve(context.Background(), id+"#0")
+	assert.NoError(t, err)
+	assert.True(t, p256.PublicKey.Equal(key))
+
+	// spec example from https://github.com/quartzjer/did-jwk
+	key, err = DIDJWK{}.Resolve(context.Background(), "did:jwk:eyJjcnYiOiJQLTI1NiIsImt0eSI6IkVDIiwieCI6ImFjYklRaXVNczNpOF91c3pFakoydHBUdFJNNEVVM3l6OTFQSDZDZEgyVjAiLCJ5IjoiX0tjeUxqOXZXTXB0bm1LdG00NkdxRHo4d2Y3NEk1TEtncmwyR3pIM25TRSJ9")
+	assert.NoError(t, err)
+	assert.Equal(t, "P-256", keyName(key))
+}
@@ -0,0 +1,226 @@
+package keyresolver
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lestrrat-go/jwx/jwk"
+)
+
+// maxDIDDocumentSize is the largest did.json document fetched
+const maxDIDDocumentSize = 1 << 20
+
+// ErrDIDDocument is returned when the DID document can not be fetched or does not match the DID
+var ErrDIDDocument = errors.New("invalid did document")
+
+// DIDDocument is the part of a DID document needed to resolve its keys
+type DIDDocument struct {
+	ID                 string               `json:"id"`
+	VerificationMethod []VerificationMethod `json:"verificationMethod"`
+}
+
+// VerificationMethod is a public key in a DID document, as publicKeyJwk or publicKeyMultibase
+type VerificationMethod struct {
+	ID                 string          `json:"id"`
+	Type               string          `json:"type"`
+	Controller         string          `json:"controller"`
+	PublicKeyJWK       json.RawMessage `json:"publicKeyJwk,omitempty"`
+	PublicKeyMultibase string          `json:"publicKeyMultibase,omitempty"`
+}
+
+// publicKey returns the public key of the verification method
+func (m *VerificationMethod) publicKey() (crypto.PublicKey, error) {
+	switch {
+	case len(m.PublicKeyJWK) > 0:
+		key, err := jwk.ParseKey(m.PublicKeyJWK)
+		if err != nil {
+			return nil, ErrInvalidIdentifier
+		}
+		var publicKey any
+		if err := key.Raw(&publicKey); err != nil {
+			return nil, ErrUnsupportedKeyType
+		}
+		return publicKey, nil
+	case m.PublicKeyMultibase != "":
+		return decodeMultikey(m.PublicKeyMultibase)
+	default:
+		return nil, ErrUnsupportedKeyType
+	}
+}
+
+// Key returns the public key of the verification method id, relative ids like #key-1 are resolved against the document.
+// Without fragment the first verification method is used.
+func (d *DIDDocument) Key(id string) (crypto.PublicKey, error) {
+	_, fragment, ok := strings.Cut(id, "#")
+	if !ok {
+		if len(d.VerificationMethod) == 0 {
+			return nil, ErrKeyNotFound
+		}
+		return d.VerificationMethod[0].publicKey()
+	}
+
+	for i := range d.VerificationMethod {
+		method := &d.VerificationMethod[i]
+		if method.ID == "#"+fragment || method.ID == d.ID+"#"+fragment {
+			return method.publicKey()
+		}
+	}
+
+	return nil, ErrKeyNotFound
+}
+
+// DIDWebURL returns the URL of the DID document of a did:web identifier.
+// The domain may carry a percent encoded port, colons after the domain are path separators, without a path the document is in /.well-known.
+func DIDWebURL(id string) (string, error) {
+	specificID, err := splitDIDURL(id, "web")
+	if err != nil {
+		return "", err
+	}
+
+	parts := strings.Split(specificID, ":")
+	host, err := url.PathUnescape(parts[0])
+	if err != nil || host == "" || strings.ContainsAny(host, "/?#@") {
+		return "", ErrInvalidIdentifier
+	}
+
+	path := []string{".well-known"}
+	if len(parts) > 1 {
+		path = path[:0]
+		for _, part := range parts[1:] {
+			segment, err := url.PathUnescape(part)
+			if err != nil || segment == "" || segment == "." || segment == ".." || strings.Contains(segment, "/") {
+				return "", ErrInvalidIdentifier
+			}
+			path = append(path, url.PathEscape(segment))
+		}
+	}
+
+	return "https://" + host + "/" + strings.Join(path, "/") + "/did.json", nil
+}
+
+type didWebCacheEntry struct {
+	doc       *DIDDocument
+	expiresAt time.Time
+}
+
+// DIDWeb resolves did:web identifiers by fetching the DID document over https and caches the documents
+type DIDWeb struct {
+	httpClient *http.Client
+	ttl        time.Duration
+
+	mu    sync.Mutex
+	cache map[string]*didWebCacheEntry
+}
+
+// NewDIDWeb creates a did:web resolver caching documents for ttl, the default client validates the server certificate against the system roots
+func NewDIDWeb(httpClient *http.Client, ttl time.Duration) *DIDWeb {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &DIDWeb{
+		httpClient: httpClient,
+		ttl:        ttl,
+		cache:      map[string]*didWebCacheEntry{},
+	}
+}
+
+// Resolve implements Resolver
+func (r *DIDWeb) Resolve(ctx context.Context, id string) (crypto.PublicKey, error) {
+	doc, err := r.Document(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.Key(id)
+}
+
+// Document returns the DID document of the did:web identifier id, from the cache if not expired
+func (r *DIDWeb) Document(ctx context.Context, id string) (*DIDDocument, error) {
+	did, _, _ := strings.Cut(id, "#")
+
+	r.mu.Lock()
+	entry, ok := r.cache[did]
+	r.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.doc, nil
+	}
+
+	doc, err := r.fetch(ctx, did)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.cache[did] = &didWebCacheEntry{doc: doc, expiresAt: time.Now().Add(r.ttl)}
+	r.mu.Unlock()
+
+	return doc, nil
+}
+
+func (r *DIDWeb) fetch(ctx context.Context, did string) (*DIDDocument, error) {
+	u, err := DIDWebURL(did)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/did+json, application/json")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: fetch %s: %s", ErrDIDDocument, u, resp.Status)
+	}
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxDIDDocumentSize))
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &DIDDocument{}
+	if err := json.Unmarshal(b, doc); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrDIDDocument, err)
+	}
+	if doc.ID != did {
+		return nil, fmt.Errorf("%w: id %q does not match %q", ErrDIDDocument, doc.ID, did)
+	}
+
+	return doc, nil
+}
+
+// Chain tries the resolvers in order and returns the first key resolved, a failing resolver falls back to the next one.
+// This lets a resolver fetching keys itself, like DIDWeb, be chained before a resolver that depends on an external service.
+type Chain []Resolver
+
+// Resolve implements Resolver, the error of the last resolver supporting the method is returned if none resolves id
+func (c Chain) Resolve(ctx context.Context, id string) (crypto.PublicKey, error) {
+	err := ErrUnsupportedMethod
+	for _, resolver := range c {
+		key, resolveErr := resolver.Resolve(ctx, id)
+		if resolveErr == nil {
+			return key, nil
+		}
+		if !errors.Is(resolveErr, ErrUnsupportedMethod) {
+			err = resolveErr
+		}
+	}
+
+	return nil, err
+}
@@ -0,0 +1,130 @@
+package keyresolver
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDIDWebURL(t *testing.T) {
+	tts := []struct {
+		name    string
+		id      string
+		want    string
+		wantErr error
+	}{
+		{name: "domain", id: "did:web:w3c-ccg.github.io", want: "https://w3c-ccg.github.io/.well-known/did.json"},
+		{name: "path", id: "did:web:w3c-ccg.github.io:user:alice#key-1", want: "https://w3c-ccg.github.io/user/alice/did.json"},
+		{name: "port", id: "did:web:example.com%3A3000:user:alice", want: "https://example.com:3000/user/alice/did.json"},
+		{name: "other method", id: "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK", wantErr: ErrUnsupportedMethod},
+		{name: "path traversal", id: "did:web:example.com:..:admin", wantErr: ErrInvalidIdentifier},
+		{name: "userinfo", id: "did:web:user%40example.com", wantErr: ErrInvalidIdentifier},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DIDWebURL(tt.id)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDIDWebResolve(t *testing.T) {
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	edKey, _, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	jwkKey, err := jwk.New(&p256.PublicKey)
+	assert.NoError(t, err)
+	jwkJSON, err := json.Marshal(jwkKey)
+	assert.NoError(t, err)
+
+	didKey, err := EncodeDIDKey(edKey)
+	assert.NoError(t, err)
+
+	var did string
+	fetches := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		if r.URL.Path != "/issuers/sunet/did.json" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(&DIDDocument{
+			ID: did,
+			VerificationMethod: []VerificationMethod{
+				{ID: did + "#key-1", Type: "JsonWebKey2020", Controller: did, PublicKeyJWK: jwkJSON},
+				{ID: "#key-2", Type: "Multikey", Controller: did, PublicKeyMultibase: strings.TrimPrefix(didKey, "did:key:")},
+			},
+		})
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+	did = "did:web:" + strings.ReplaceAll(u.Host, ":", "%3A") + ":issuers:sunet"
+
+	r := NewDIDWeb(server.Client(), time.Hour)
+
+	key, err := r.Resolve(context.Background(), did+"#key-1")
+	assert.NoError(t, err)
+	assert.True(t, p256.PublicKey.Equal(key))
+
+	key, err = r.Resolve(context.Background(), did+"#key-2")
+	assert.NoError(t, err)
+	assert.True(t, edKey.Equal(key))
+
+	key, err = r.Resolve(context.Background(), did)
+	assert.NoError(t, err)
+	assert.True(t, p256.PublicKey.Equal(key))
+
+	_, err = r.Resolve(context.Background(), did+"#key-3")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	assert.Equal(t, 1, fetches, "document is cached")
+
+	_, err = r.Resolve(context.Background(), "did:web:"+strings.ReplaceAll(u.Host, ":", "%3A")+":issuers:other")
+	assert.ErrorIs(t, err, ErrDIDDocument)
+
+	// the default client does not trust the test server certificate
+	_, err = NewDIDWeb(nil, time.Hour).Resolve(context.Background(), did)
+	assert.Error(t, err)
+}
+
+func TestChain(t *testing.T) {
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	didKey, err := EncodeDIDKey(&p256.PublicKey)
+	assert.NoError(t, err)
+
+	chain := Chain{DIDJWK{}, Static{}, DIDKey{}}
+
+	key, err := chain.Resolve(context.Background(), didKey)
+	assert.NoError(t, err)
+	assert.True(t, p256.PublicKey.Equal(key))
+
+	_, err = Chain{DIDJWK{}, Static{}}.Resolve(context.Background(), "did:web:example.com")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	_, err = Chain{DIDJWK{}}.Resolve(context.Background(), "did:web:example.com")
+	assert.ErrorIs(t, err, ErrUnsupportedMethod)
+}
@@ -0,0 +1,54 @@
+package keyresolver
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrKeyNotFound is returned when no key is known for the identifier
+	ErrKeyNotFound = errors.New("key not found")
+
+	// ErrUnsupportedMethod is returned when the resolver does not handle the identifier
+	ErrUnsupportedMethod = errors.New("unsupported identifier method")
+
+	// ErrInvalidIdentifier is returned when the identifier can not be decoded
+	ErrInvalidIdentifier = errors.New("invalid identifier")
+
+	// ErrUnsupportedKeyType is returned when the identifier holds a key type that is not supported
+	ErrUnsupportedKeyType = errors.New("unsupported key type")
+)
+
+// Resolver resolves a key identifier, a DID or a DID URL with a fragment, to a public key
+type Resolver interface {
+	Resolve(ctx context.Context, id string) (crypto.PublicKey, error)
+}
+
+// Static resolves identifiers from a fixed set of keys
+type Static map[string]crypto.PublicKey
+
+// Resolve implements Resolver
+func (s Static) Resolve(ctx context.Context, id string) (crypto.PublicKey, error) {
+	key, ok := s[id]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return key, nil
+}
+
+// splitDIDURL returns the method specific identifier of a DID or DID URL with the given method, without fragment
+func splitDIDURL(id, method string) (string, error) {
+	prefix := "did:" + method + ":"
+	if !strings.HasPrefix(id, prefix) {
+		return "", ErrUnsupportedMethod
+	}
+
+	specificID, _, _ := strings.Cut(strings.TrimPrefix(id, prefix), "#")
+	if specificID == "" {
+		return "", ErrInvalidIdentifier
+	}
+
+	return specificID, nil
+}
@@ -0,0 +1,177 @@
+package keyresolver
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+var (
+	// ErrNoTrustAnchors is returned when a trust anchor store holds no certificates
+	ErrNoTrustAnchors = errors.New("no trust anchors")
+
+	// ErrUntrustedChain is returned when a certificate chain does not validate against the trust anchors
+	ErrUntrustedChain = errors.New("untrusted certificate chain")
+
+	// ErrMissingKeyUsage is returned when the leaf certificate lacks a required key usage
+	ErrMissingKeyUsage = errors.New("missing key usage")
+)
+
+var (
+	// OIDExtKeyUsageMDLDS is the extended key usage of ISO/IEC 18013-5 mdoc document signer certificates
+	OIDExtKeyUsageMDLDS = asn1.ObjectIdentifier{1, 0, 18013, 5, 1, 2}
+
+	// OIDExtKeyUsageMDLReaderAuth is the extended key usage of ISO/IEC 18013-5 mdoc reader authentication certificates
+	OIDExtKeyUsageMDLReaderAuth = asn1.ObjectIdentifier{1, 0, 18013, 5, 1, 6}
+)
+
+// ChainResolver resolves the public key of a certificate chain, leaf first
+type ChainResolver interface {
+	ResolveChain(ctx context.Context, chain []*x509.Certificate) (crypto.PublicKey, error)
+}
+
+// X509 validates certificate chains against a trust anchor store and resolves the leaf public key
+type X509 struct {
+	roots       *x509.CertPool
+	requiredEKU []asn1.ObjectIdentifier
+	now         func() time.Time
+}
+
+// NewX509 creates a chain resolver trusting roots, the leaf must carry every extended key usage in requiredEKU
+func NewX509(roots *x509.CertPool, requiredEKU ...asn1.ObjectIdentifier) *X509 {
+	return &X509{
+		roots:       roots,
+		requiredEKU: requiredEKU,
+		now:         time.Now,
+	}
+}
+
+// ResolveChain implements ChainResolver
+func (r *X509) ResolveChain(ctx context.Context, chain []*x509.Certificate) (crypto.PublicKey, error) {
+	if len(chain) == 0 {
+		return nil, ErrUntrustedChain
+	}
+	leaf := chain[0]
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	// extended key usages are checked below, x509 only knows the standard ones
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         r.roots,
+		Intermediates: intermediates,
+		CurrentTime:   r.now(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUntrustedChain, err)
+	}
+
+	if leaf.KeyUsage != 0 && leaf.KeyUsage&x509.KeyUsageDigitalSignature == 0 {
+		return nil, fmt.Errorf("%w: digitalSignature", ErrMissingKeyUsage)
+	}
+
+	for _, eku := range r.requiredEKU {
+		if !slices.ContainsFunc(leaf.UnknownExtKeyUsage, eku.Equal) {
+			return nil, fmt.Errorf("%w: %s", ErrMissingKeyUsage, eku)
+		}
+	}
+
+	return leaf.PublicKey, nil
+}
+
+// ParseX5C parses the base64 DER certificates of a JWS x5c header or a COSE x5chain, leaf first
+func ParseX5C(x5c []string) ([]*x509.Certificate, error) {
+	chain := make([]*x509.Certificate, 0, len(x5c))
+	for _, s := range x5c {
+		der, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, err
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, cert)
+	}
+
+	return chain, nil
+}
+
+// LoadTrustAnchors reads PEM certificates from path, a directory of PEM files or a single PEM bundle such as an IACA list
+func LoadTrustAnchors(path string) (*x509.CertPool, error) {
+	certs, err := LoadCertificates(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	for _, cert := range certs {
+		pool.AddCert(cert)
+	}
+
+	return pool, nil
+}
+
+// LoadCertificates reads PEM certificates from path, a directory of PEM files or a single PEM bundle
+func LoadCertificates(path string) ([]*x509.Certificate, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		files = files[:0]
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			files = append(files, filepath.Join(path, entry.Name()))
+		}
+	}
+
+	var certs []*x509.Certificate
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		for {
+			var block *pem.Block
+			block, b = pem.Decode(b)
+			if block == nil {
+				break
+			}
+			if block.Type != "CERTIFICATE" {
+				continue
+			}
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+			certs = append(certs, cert)
+		}
+	}
+
+	if len(certs) == 0 {
+		return nil, ErrNoTrustAnchors
+	}
+
+	return certs, nil
+}
@@ -0,0 +1,108 @@
+package keyresolver
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockCertificate(t *testing.T, template *x509.Certificate, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	assert.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	return cert, key
+}
+
+func mockIACA(t *testing.T, name string) (*x509.Certificate, *ecdsa.PrivateKey) {
+	return mockCertificate(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil, nil)
+}
+
+func mockDS(t *testing.T, iaca *x509.Certificate, iacaKey *ecdsa.PrivateKey, notAfter time.Time, eku ...asn1.ObjectIdentifier) *x509.Certificate {
+	cert, _ := mockCertificate(t, &x509.Certificate{
+		SerialNumber:       big.NewInt(2),
+		Subject:            pkix.Name{CommonName: "document signer"},
+		NotBefore:          time.Now().Add(-time.Hour),
+		NotAfter:           notAfter,
+		KeyUsage:           x509.KeyUsageDigitalSignature,
+		UnknownExtKeyUsage: eku,
+	}, iaca, iacaKey)
+
+	return cert
+}
+
+func TestX509ResolveChain(t *testing.T) {
+	iaca, iacaKey := mockIACA(t, "IACA")
+	otherIACA, otherIACAKey := mockIACA(t, "other IACA")
+
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "iaca.pem"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: iaca.Raw}), 0600))
+
+	roots, err := LoadTrustAnchors(dir)
+	assert.NoError(t, err)
+
+	resolver := NewX509(roots, OIDExtKeyUsageMDLDS)
+
+	valid := time.Now().Add(time.Hour)
+
+	tts := []struct {
+		name    string
+		leaf    *x509.Certificate
+		wantErr error
+	}{
+		{name: "trusted", leaf: mockDS(t, iaca, iacaKey, valid, OIDExtKeyUsageMDLDS)},
+		{name: "untrusted issuer", leaf: mockDS(t, otherIACA, otherIACAKey, valid, OIDExtKeyUsageMDLDS), wantErr: ErrUntrustedChain},
+		{name: "expired", leaf: mockDS(t, iaca, iacaKey, time.Now().Add(-time.Minute), OIDExtKeyUsageMDLDS), wantErr: ErrUntrustedChain},
+		{name: "missing eku", leaf: mockDS(t, iaca, iacaKey, valid), wantErr: ErrMissingKeyUsage},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			chain, err := ParseX5C([]string{base64.StdEncoding.EncodeToString(tt.leaf.Raw)})
+			assert.NoError(t, err)
+
+			publicKey, err := resolver.ResolveChain(context.Background(), chain)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.leaf.PublicKey, publicKey)
+		})
+	}
+}
+
+func TestLoadTrustAnchorsEmpty(t *testing.T) {
+	_, err := LoadTrustAnchors(t.TempDir())
+	assert.ErrorIs(t, err, ErrNoTrustAnchors)
+}
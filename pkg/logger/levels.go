@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// minLevel is the most verbose level that can be enabled, logr V(n) maps to zap level -n
+const minLevel = zapcore.Level(-2)
+
+var (
+	// ErrUnknownLevel is returned when a log level name is not known
+	ErrUnknownLevel = errors.New("unknown log level, use error, info, debug or trace")
+
+	// ErrLevelsNotSupported is returned when the logger does not support runtime levels
+	ErrLevelsNotSupported = errors.New("logger does not support runtime log levels")
+)
+
+// ParseLevel parses error, info, debug or trace into a zap level
+func ParseLevel(level string) (zapcore.Level, error) {
+	switch strings.ToLower(level) {
+	case "error":
+		return zapcore.ErrorLevel, nil
+	case "info":
+		return zapcore.InfoLevel, nil
+	case "debug":
+		return zapcore.DebugLevel, nil
+	case "trace":
+		return minLevel, nil
+	default:
+		return 0, ErrUnknownLevel
+	}
+}
+
+// levels holds the default level and the levels of named loggers
+type levels struct {
+	mu       sync.RWMutex
+	fallback zapcore.Level
+	named    map[string]zapcore.Level
+}
+
+func newLevels(fallback zapcore.Level) *levels {
+	return &levels{
+		fallback: fallback,
+		named:    map[string]zapcore.Level{},
+	}
+}
+
+// set sets the level of name, an empty name sets the default level
+func (l *levels) set(name string, level zapcore.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if name == "" {
+		l.fallback = level
+		return
+	}
+	l.named[name] = level
+}
+
+// replace replaces all named levels
+func (l *levels) replace(named map[string]zapcore.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.named = named
+}
+
+// levelFor returns the level of the longest configured name that is name or a parent of it
+func (l *levels) levelFor(name string) zapcore.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for {
+		if level, ok := l.named[name]; ok {
+			return level
+		}
+		i := strings.LastIndex(name, ".")
+		if i < 0 {
+			return l.fallback
+		}
+		name = name[:i]
+	}
+}
+
+// levelCore filters log entries by the level of their named logger
+type levelCore struct {
+	zapcore.Core
+	levels *levels
+}
+
+// Enabled is the fast path check, any level that might be enabled for some logger passes
+func (c *levelCore) Enabled(level zapcore.Level) bool {
+	return level >= minLevel
+}
+
+// With adds structured context to the core
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), levels: c.levels}
+}
+
+// Check determines whether the entry should be logged by its named logger level
+func (c *levelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if entry.Level < c.levels.levelFor(entry.LoggerName) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
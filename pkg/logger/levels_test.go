@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLevelFor(t *testing.T) {
+	l := newLevels(zapcore.InfoLevel)
+	l.set("apigw.httpserver", zapcore.DebugLevel)
+	l.set("apigw.httpserver.http", zapcore.ErrorLevel)
+
+	tts := []struct {
+		name string
+		have string
+		want zapcore.Level
+	}{
+		{
+			name: "default",
+			have: "apigw.db",
+			want: zapcore.InfoLevel,
+		},
+		{
+			name: "exact",
+			have: "apigw.httpserver",
+			want: zapcore.DebugLevel,
+		},
+		{
+			name: "inherited from parent",
+			have: "apigw.httpserver.middleware",
+			want: zapcore.DebugLevel,
+		},
+		{
+			name: "longest name wins",
+			have: "apigw.httpserver.http",
+			want: zapcore.ErrorLevel,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, l.levelFor(tt.have))
+		})
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	_, err := ParseLevel("verbose")
+	assert.Equal(t, ErrUnknownLevel, err)
+
+	level, err := ParseLevel("trace")
+	assert.NoError(t, err)
+	assert.Equal(t, minLevel, level)
+}
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"fmt"
 	"io/fs"
 	"os"
@@ -8,17 +9,27 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/go-logr/zapr"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
+// Output formats
+const (
+	FormatConsole = "console"
+	FormatJSON    = "json"
+)
+
 // Log for portability
 type Log struct {
 	logr.Logger
+	levels    *levels
+	redaction *redaction
 }
 
-// New creates a default logger based on what kind of environment is used.
-func New(name, logPath string, production bool) (*Log, error) {
+// New creates a default logger based on what kind of environment is used. format is console or json, an empty
+// format is json in production and console otherwise. DefaultRedactedFields are masked in its output.
+func New(name, logPath, format string, production bool) (*Log, error) {
 
 	var zc zap.Config
 
@@ -30,6 +41,22 @@ func New(name, logPath string, production bool) (*Log, error) {
 		zc.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
 	}
 
+	switch format {
+	case "":
+	case FormatJSON:
+		zc.Encoding = FormatJSON
+		zc.EncoderConfig = zap.NewProductionEncoderConfig()
+		zc.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	case FormatConsole:
+		zc.Encoding = FormatConsole
+		zc.EncoderConfig = zap.NewDevelopmentEncoderConfig()
+		if !production {
+			zc.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		}
+	default:
+		return nil, fmt.Errorf("unknown log format %q, use console or json", format)
+	}
+
 	zc.DisableCaller = true
 	zc.DisableStacktrace = true
 
@@ -43,14 +70,22 @@ func New(name, logPath string, production bool) (*Log, error) {
 		}
 	}
 
-	z, err := zc.Build()
+	// the level is decided per named logger by levelCore, the underlying core lets everything through
+	lvls := newLevels(zc.Level.Level())
+	zc.Level = zap.NewAtomicLevelAt(minLevel)
+
+	redaction := newRedaction(production)
+
+	z, err := zc.Build(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &levelCore{Core: &redactCore{Core: core, redaction: redaction}, levels: lvls}
+	}))
 	if err != nil {
 		return nil, err
 	}
 
 	log := zapr.NewLogger(z)
 
-	return &Log{Logger: log.WithName(name)}, nil
+	return &Log{Logger: log.WithName(name), levels: lvls, redaction: redaction}, nil
 }
 
 // NewSimple creates a simple logger for barbaric purposes
@@ -60,7 +95,72 @@ func NewSimple(name string) *Log {
 
 // New creates a sub-logger of the original one
 func (l *Log) New(path string) *Log {
-	return &Log{Logger: l.WithName(path)}
+	return &Log{Logger: l.WithName(path), levels: l.levels, redaction: l.redaction}
+}
+
+// WithContext returns a logger that adds the trace_id and span_id of the span in ctx, for correlation with traces
+func (l *Log) WithContext(ctx context.Context) *Log {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return l
+	}
+
+	return &Log{Logger: l.WithValues("trace_id", sc.TraceID().String(), "span_id", sc.SpanID().String()), levels: l.levels, redaction: l.redaction}
+}
+
+// SetLevel sets the level of the named logger and its sub-loggers at runtime, an empty name sets the default level.
+// Names are dot separated, e.g. "apigw.httpserver".
+func (l *Log) SetLevel(name, level string) error {
+	if l.levels == nil {
+		return ErrLevelsNotSupported
+	}
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	l.levels.set(name, lvl)
+	l.Info("log level changed", "logger", name, "level", level)
+
+	return nil
+}
+
+// SetLevels replaces all named logger levels at runtime, loggers not in levels use the default level
+func (l *Log) SetLevels(levels map[string]string) error {
+	if l.levels == nil {
+		return ErrLevelsNotSupported
+	}
+
+	named := make(map[string]zapcore.Level, len(levels))
+	for name, level := range levels {
+		lvl, err := ParseLevel(level)
+		if err != nil {
+			return err
+		}
+		named[name] = lvl
+	}
+
+	l.levels.replace(named)
+	l.Info("log levels changed", "levels", levels)
+
+	return nil
+}
+
+// SetRedaction replaces the field names masked in log output at runtime with DefaultRedactedFields and fields, except
+// the allow-listed ones. Allow-listing is for debug environments and fails in production. Values added with
+// WithValues before the change keep their redaction.
+func (l *Log) SetRedaction(fields, allow []string) error {
+	if l.redaction == nil {
+		return ErrRedactionNotSupported
+	}
+	if err := l.redaction.set(fields, allow); err != nil {
+		return err
+	}
+	if len(allow) > 0 {
+		l.Info("log redaction allow-listed, personal data is logged in clear", "fields", allow)
+	}
+
+	return nil
 }
 
 // Info log
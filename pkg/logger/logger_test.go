@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-logr/zapr"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWithContext(t *testing.T) {
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x01, 0x02},
+		SpanID:  trace.SpanID{0x03},
+	})
+
+	tts := []struct {
+		name        string
+		ctx         context.Context
+		wantTraceID string
+		wantSpanID  string
+	}{
+		{
+			name: "no span",
+			ctx:  context.Background(),
+		},
+		{
+			name:        "span",
+			ctx:         trace.ContextWithSpanContext(context.Background(), spanContext),
+			wantTraceID: "01020000000000000000000000000000",
+			wantSpanID:  "0300000000000000",
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			core := zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(buf), minLevel)
+			log := &Log{Logger: zapr.NewLogger(zap.New(core))}
+
+			log.WithContext(tt.ctx).Info("test")
+
+			got := map[string]any{}
+			assert.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+
+			if tt.wantTraceID == "" {
+				assert.NotContains(t, got, "trace_id")
+				return
+			}
+			assert.Equal(t, tt.wantTraceID, got["trace_id"])
+			assert.Equal(t, tt.wantSpanID, got["span_id"])
+		})
+	}
+}
+
+func TestNewFormat(t *testing.T) {
+	_, err := New("test", "", "xml", true)
+	assert.Error(t, err)
+
+	_, err = New("test", "", FormatJSON, false)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,202 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// RedactedValue replaces the value of a redacted field in log output
+const RedactedValue = "[REDACTED]"
+
+// DefaultRedactedFields are the personal data field names masked in log output unless allow-listed
+var DefaultRedactedFields = []string{
+	"authentic_source_person_id",
+	"family_name",
+	"given_name",
+	"birth_date",
+	"family_name_at_birth",
+	"given_name_at_birth",
+	"birth_place",
+	"birth_country",
+	"birth_state",
+	"birth_city",
+	"gender",
+	"resident_address",
+	"resident_country",
+	"resident_state",
+	"resident_city",
+	"resident_postal_code",
+	"resident_street",
+	"resident_house_number",
+	"nationality",
+	"email_address",
+	"mobile_phone_number",
+	"document_number",
+	"personal_administrative_number",
+	"social_security_pin",
+}
+
+var (
+	// ErrAllowInProduction is returned when redacted fields are allow-listed in production
+	ErrAllowInProduction = errors.New("redacted log fields can not be allow-listed in production")
+
+	// ErrRedactionNotSupported is returned when the logger does not support runtime redaction
+	ErrRedactionNotSupported = errors.New("logger does not support runtime log redaction")
+)
+
+// redaction holds the field names masked in log output
+type redaction struct {
+	mu         sync.RWMutex
+	production bool
+	names      map[string]bool
+}
+
+func newRedaction(production bool) *redaction {
+	r := &redaction{production: production}
+	r.names = redactedFields(nil, nil)
+
+	return r
+}
+
+// redactedFields returns the default fields and fields, except allow, by lower case name
+func redactedFields(fields, allow []string) map[string]bool {
+	redacted := map[string]bool{}
+	for _, name := range append(append([]string{}, DefaultRedactedFields...), fields...) {
+		redacted[strings.ToLower(name)] = true
+	}
+	for _, name := range allow {
+		delete(redacted, strings.ToLower(name))
+	}
+
+	return redacted
+}
+
+// set replaces the redacted fields
+func (r *redaction) set(fields, allow []string) error {
+	if r.production && len(allow) > 0 {
+		return ErrAllowInProduction
+	}
+
+	redacted := redactedFields(fields, allow)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.names = redacted
+
+	return nil
+}
+
+// redacted reports if the field name is masked
+func (r *redaction) redacted(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.names[strings.ToLower(name)]
+}
+
+// field returns f with its value masked if its key is redacted, or with the redacted fields of its value masked if it
+// is a struct, map or JSON text
+func (r *redaction) field(f zapcore.Field) zapcore.Field {
+	if r.redacted(f.Key) {
+		return zap.String(f.Key, RedactedValue)
+	}
+
+	switch f.Type {
+	case zapcore.ReflectType:
+		b, err := json.Marshal(f.Interface)
+		if err != nil {
+			return f
+		}
+		if v, ok := r.json(b); ok {
+			return zap.Any(f.Key, v)
+		}
+	case zapcore.StringType:
+		s := strings.TrimSpace(f.String)
+		if !strings.HasPrefix(s, "{") && !strings.HasPrefix(s, "[") {
+			return f
+		}
+		if v, ok := r.json([]byte(s)); ok {
+			if b, err := json.Marshal(v); err == nil {
+				return zap.String(f.Key, string(b))
+			}
+		}
+	}
+
+	return f
+}
+
+// json returns the JSON value b with its redacted fields masked, ok is false if b is not JSON or has no redacted fields
+func (r *redaction) json(b []byte) (any, bool) {
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, false
+	}
+
+	return r.value(v)
+}
+
+// value masks the redacted fields of the objects in v in place, and reports if any was masked
+func (r *redaction) value(v any) (any, bool) {
+	masked := false
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if r.redacted(key) {
+				v[key] = RedactedValue
+				masked = true
+				continue
+			}
+			if _, ok := r.value(value); ok {
+				masked = true
+			}
+		}
+	case []any:
+		for _, value := range v {
+			if _, ok := r.value(value); ok {
+				masked = true
+			}
+		}
+	}
+
+	return v, masked
+}
+
+// fields returns fields with redacted values masked, fields is not modified
+func (r *redaction) fields(fields []zapcore.Field) []zapcore.Field {
+	redacted := make([]zapcore.Field, len(fields))
+	for i, f := range fields {
+		redacted[i] = r.field(f)
+	}
+
+	return redacted
+}
+
+// redactCore masks the redacted fields of log entries before they are encoded
+type redactCore struct {
+	zapcore.Core
+	redaction *redaction
+}
+
+// With adds structured context to the core, the redaction of the fields is decided when they are added
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{Core: c.Core.With(c.redaction.fields(fields)), redaction: c.redaction}
+}
+
+// Check adds the core itself, so entries are written with redacted fields
+func (c *redactCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+// Write writes the entry with redacted fields
+func (c *redactCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(entry, c.redaction.fields(fields))
+}
@@ -0,0 +1,114 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-logr/zapr"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type identity struct {
+	Schema     string `json:"schema"`
+	FamilyName string `json:"family_name"`
+	BirthDate  string `json:"birth_date"`
+}
+
+func newRedactedLog(r *redaction, buf *bytes.Buffer) *Log {
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(buf), minLevel)
+	return &Log{Logger: zapr.NewLogger(zap.New(&redactCore{Core: core, redaction: r})), redaction: r}
+}
+
+func TestRedaction(t *testing.T) {
+	tts := []struct {
+		name   string
+		fields []string
+		allow  []string
+		args   []any
+		want   map[string]any
+	}{
+		{
+			name: "field",
+			args: []any{"family_name", "Svensson", "document_type", "PDA1"},
+			want: map[string]any{"family_name": RedactedValue, "document_type": "PDA1"},
+		},
+		{
+			name: "case insensitive",
+			args: []any{"Birth_Date", "1970-01-01"},
+			want: map[string]any{"Birth_Date": RedactedValue},
+		},
+		{
+			name: "struct",
+			args: []any{"identity", &identity{Schema: "SE", FamilyName: "Svensson", BirthDate: "1970-01-01"}},
+			want: map[string]any{"identity": map[string]any{"schema": "SE", "family_name": RedactedValue, "birth_date": RedactedValue}},
+		},
+		{
+			name: "nested map",
+			args: []any{"document", map[string]any{"identities": []any{map[string]any{"given_name": "Magnus", "schema": "SE"}}}},
+			want: map[string]any{"document": map[string]any{"identities": []any{map[string]any{"given_name": RedactedValue, "schema": "SE"}}}},
+		},
+		{
+			name: "json string",
+			args: []any{"body", `{"document_number":"123","type":"passport"}`},
+			want: map[string]any{"body": `{"document_number":"[REDACTED]","type":"passport"}`},
+		},
+		{
+			name: "text string",
+			args: []any{"body", "{not json"},
+			want: map[string]any{"body": "{not json"},
+		},
+		{
+			name:   "configured field",
+			fields: []string{"email"},
+			args:   []any{"email", "magnus@example.com"},
+			want:   map[string]any{"email": RedactedValue},
+		},
+		{
+			name:  "allow-listed field",
+			allow: []string{"birth_date"},
+			args:  []any{"birth_date", "1970-01-01", "family_name", "Svensson"},
+			want:  map[string]any{"birth_date": "1970-01-01", "family_name": RedactedValue},
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRedaction(false)
+			assert.NoError(t, r.set(tt.fields, tt.allow))
+
+			buf := &bytes.Buffer{}
+			newRedactedLog(r, buf).Info("test", tt.args...)
+
+			got := map[string]any{}
+			assert.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+			for key, want := range tt.want {
+				assert.Equal(t, want, got[key], key)
+			}
+		})
+	}
+}
+
+func TestRedactionWithValues(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := newRedactedLog(newRedaction(false), buf)
+
+	log.Logger = log.WithValues("given_name", "Magnus")
+	log.Info("test")
+
+	got := map[string]any{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, RedactedValue, got["given_name"])
+}
+
+func TestSetRedaction(t *testing.T) {
+	log, err := New("test", "", FormatJSON, true)
+	assert.NoError(t, err)
+
+	assert.Equal(t, ErrAllowInProduction, log.SetRedaction(nil, []string{"birth_date"}))
+	assert.NoError(t, log.SetRedaction([]string{"email"}, nil))
+
+	assert.Equal(t, ErrRedactionNotSupported, NewSimple("test").SetRedaction(nil, nil))
+}
@@ -0,0 +1,175 @@
+package mdoc
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+const (
+	// tagTDate is the CBOR tag of a date/time string, RFC 8949 section 3.4.1
+	tagTDate = 0
+
+	// tagFullDate is the CBOR tag of a full-date string, RFC 8943
+	tagFullDate = 1004
+
+	// fullDateLayout is the RFC 3339 full-date
+	fullDateLayout = "2006-01-02"
+)
+
+var (
+	// ErrInvalidDate is returned when a date is not a tagged full-date or tdate string
+	ErrInvalidDate = errors.New("invalid date")
+)
+
+// FullDate is a calendar date, e.g. birth_date or expiry_date, ISO/IEC 18013-5 section 7.2.1.
+// It is encoded as #6.1004(tstr) in CBOR and as a "2006-01-02" string in JSON. The time is midnight UTC.
+type FullDate struct {
+	time.Time
+}
+
+// NewFullDate returns the date of t, in the location of t
+func NewFullDate(t time.Time) FullDate {
+	return FullDate{time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)}
+}
+
+// ParseFullDate parses a "2006-01-02" full-date
+func ParseFullDate(s string) (FullDate, error) {
+	t, err := time.Parse(fullDateLayout, s)
+	if err != nil {
+		return FullDate{}, ErrInvalidDate
+	}
+	return FullDate{t}, nil
+}
+
+// String returns the full-date
+func (d FullDate) String() string {
+	return d.Format(fullDateLayout)
+}
+
+// MarshalCBOR implements cbor.Marshaler
+func (d FullDate) MarshalCBOR() ([]byte, error) {
+	return encMode.Marshal(cbor.Tag{Number: tagFullDate, Content: d.String()})
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler
+func (d *FullDate) UnmarshalCBOR(data []byte) error {
+	s, err := unmarshalTaggedString(data, tagFullDate)
+	if err != nil {
+		return err
+	}
+
+	date, err := ParseFullDate(s)
+	if err != nil {
+		return err
+	}
+	*d = date
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (d FullDate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (d *FullDate) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return ErrInvalidDate
+	}
+
+	date, err := ParseFullDate(s)
+	if err != nil {
+		return err
+	}
+	*d = date
+
+	return nil
+}
+
+// TDate is a point in time, e.g. signed or validUntil of the validity info, ISO/IEC 18013-5 section 9.1.2.4.
+// It is encoded as #6.0(tstr) in CBOR and as an RFC 3339 string in JSON, in UTC without fractional seconds as the
+// standard requires.
+type TDate struct {
+	time.Time
+}
+
+// NewTDate returns t in UTC, truncated to whole seconds
+func NewTDate(t time.Time) TDate {
+	return TDate{t.UTC().Truncate(time.Second)}
+}
+
+// ParseTDate parses an RFC 3339 date-time
+func ParseTDate(s string) (TDate, error) {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return TDate{}, ErrInvalidDate
+	}
+	return NewTDate(t), nil
+}
+
+// String returns the RFC 3339 date-time
+func (d TDate) String() string {
+	return d.UTC().Truncate(time.Second).Format(time.RFC3339)
+}
+
+// MarshalCBOR implements cbor.Marshaler
+func (d TDate) MarshalCBOR() ([]byte, error) {
+	return encMode.Marshal(cbor.Tag{Number: tagTDate, Content: d.String()})
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler
+func (d *TDate) UnmarshalCBOR(data []byte) error {
+	s, err := unmarshalTaggedString(data, tagTDate)
+	if err != nil {
+		return err
+	}
+
+	date, err := ParseTDate(s)
+	if err != nil {
+		return err
+	}
+	*d = date
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (d TDate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (d *TDate) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return ErrInvalidDate
+	}
+
+	date, err := ParseTDate(s)
+	if err != nil {
+		return err
+	}
+	*d = date
+
+	return nil
+}
+
+// unmarshalTaggedString returns the text string content of a CBOR tag number
+func unmarshalTaggedString(data []byte, number uint64) (string, error) {
+	var tag cbor.RawTag
+	if err := cbor.Unmarshal(data, &tag); err != nil || tag.Number != number {
+		return "", ErrInvalidDate
+	}
+
+	var s string
+	if err := cbor.Unmarshal(tag.Content, &s); err != nil {
+		return "", ErrInvalidDate
+	}
+
+	return s, nil
+}
@@ -0,0 +1,87 @@
+package mdoc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockDataElements struct {
+	BirthDate  FullDate `json:"birth_date" cbor:"birth_date"`
+	ValidUntil TDate    `json:"valid_until" cbor:"valid_until"`
+}
+
+func TestDateRoundTrip(t *testing.T) {
+	birthDate, err := ParseFullDate("1970-01-31")
+	assert.NoError(t, err)
+	validUntil, err := ParseTDate("2030-06-01T12:30:00+02:00")
+	assert.NoError(t, err)
+
+	elements := &mockDataElements{BirthDate: birthDate, ValidUntil: validUntil}
+
+	fromJSON := &mockDataElements{}
+	b, err := json.Marshal(elements)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"birth_date":"1970-01-31","valid_until":"2030-06-01T10:30:00Z"}`, string(b))
+	assert.NoError(t, json.Unmarshal(b, fromJSON))
+
+	c, err := encMode.Marshal(fromJSON)
+	assert.NoError(t, err)
+	fromCBOR := &mockDataElements{}
+	assert.NoError(t, cbor.Unmarshal(c, fromCBOR))
+
+	b2, err := json.Marshal(fromCBOR)
+	assert.NoError(t, err)
+	assert.Equal(t, string(b), string(b2))
+	assert.True(t, elements.BirthDate.Equal(fromCBOR.BirthDate.Time))
+	assert.True(t, elements.ValidUntil.Equal(fromCBOR.ValidUntil.Time))
+}
+
+func TestDateCBOR(t *testing.T) {
+	tts := []struct {
+		name string
+		v    any
+		want string
+	}{
+		{
+			name: "full-date",
+			v:    NewFullDate(time.Date(1970, 1, 31, 23, 59, 0, 0, time.FixedZone("CET", 3600))),
+			// 1004("1970-01-31")
+			want: "d903ec6a313937302d30312d3331",
+		},
+		{
+			name: "tdate",
+			v:    NewTDate(time.Date(2030, 6, 1, 10, 30, 0, 500, time.UTC)),
+			// 0("2030-06-01T10:30:00Z")
+			want: "c074323033302d30362d30315431303a33303a30305a",
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := encMode.Marshal(tt.v)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, hex.EncodeToString(got))
+		})
+	}
+}
+
+func TestDateInvalid(t *testing.T) {
+	untagged, err := encMode.Marshal("1970-01-31")
+	assert.NoError(t, err)
+	wrongTag, err := encMode.Marshal(cbor.Tag{Number: tagTDate, Content: "1970-01-31"})
+	assert.NoError(t, err)
+
+	var date FullDate
+	assert.ErrorIs(t, cbor.Unmarshal(untagged, &date), ErrInvalidDate)
+	assert.ErrorIs(t, cbor.Unmarshal(wrongTag, &date), ErrInvalidDate)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`"31/01/1970"`), &date), ErrInvalidDate)
+
+	var tdate TDate
+	assert.ErrorIs(t, cbor.Unmarshal(untagged, &tdate), ErrInvalidDate)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`1`), &tdate), ErrInvalidDate)
+}
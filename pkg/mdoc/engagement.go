@@ -0,0 +1,191 @@
+package mdoc
+
+import (
+	"crypto"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"vc/pkg/cose"
+)
+
+const (
+	// EngagementVersion is the version of device and reader engagement, ISO/IEC 18013-5 section 8.2.1.1
+	EngagementVersion = "1.0"
+
+	// CipherSuite1 is the cipher suite of session encryption, the only one ISO/IEC 18013-5 defines
+	CipherSuite1 = 1
+
+	// qrCodeScheme prefixes the device engagement in the QR code, ISO/IEC 18013-5 section 8.2.2.3
+	qrCodeScheme = "mdoc:"
+)
+
+// Device retrieval method types, ISO/IEC 18013-5 section 8.2.1.1
+const (
+	RetrievalNFC       = 1
+	RetrievalBLE       = 2
+	RetrievalWiFiAware = 3
+)
+
+// BLE and NFC retrieval options, ISO/IEC 18013-5 section 8.2.2.2
+const (
+	BLEPeripheralServerMode = 0
+	BLECentralClientMode    = 1
+	BLEPeripheralServerUUID = 10
+	BLECentralClientUUID    = 11
+
+	NFCMaxCommandDataLength  = 0
+	NFCMaxResponseDataLength = 1
+)
+
+var (
+	// ErrInvalidQRCode is returned when a QR code does not hold an mdoc: device engagement
+	ErrInvalidQRCode = errors.New("invalid device engagement qr code")
+
+	// ErrInvalidEngagement is returned when an engagement has an unsupported version or cipher suite
+	ErrInvalidEngagement = errors.New("invalid engagement")
+)
+
+// Security holds the ephemeral key of the engaging party, EDeviceKeyBytes or EReaderKeyBytes as #6.24(bstr .cbor COSE_Key)
+type Security struct {
+	_           struct{} `cbor:",toarray"`
+	CipherSuite int
+	KeyBytes    EncodedCBOR
+}
+
+// newSecurity returns the security of the ephemeral key publicKey
+func newSecurity(publicKey crypto.PublicKey) (*Security, error) {
+	key, err := cose.NewKey(publicKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	keyBytes, err := NewEncodedCBOR(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Security{CipherSuite: CipherSuite1, KeyBytes: keyBytes}, nil
+}
+
+// Key returns the ephemeral public key
+func (s *Security) Key() (crypto.PublicKey, error) {
+	key := &cose.Key{}
+	if err := s.KeyBytes.Decode(key); err != nil {
+		return nil, err
+	}
+
+	return key.PublicKey()
+}
+
+// DeviceRetrievalMethod is a transfer method the mdoc offers, Options are the options of its type
+type DeviceRetrievalMethod struct {
+	_       struct{} `cbor:",toarray"`
+	Type    uint
+	Version uint
+	Options map[int]any
+}
+
+// NewBLERetrieval returns BLE retrieval in mdoc peripheral server mode with the service uuid
+func NewBLERetrieval(uuid []byte) *DeviceRetrievalMethod {
+	return &DeviceRetrievalMethod{
+		Type:    RetrievalBLE,
+		Version: 1,
+		Options: map[int]any{
+			BLEPeripheralServerMode: true,
+			BLECentralClientMode:    false,
+			BLEPeripheralServerUUID: uuid,
+		},
+	}
+}
+
+// NewNFCRetrieval returns NFC retrieval with the maximum command and response data field lengths
+func NewNFCRetrieval(maxCommand, maxResponse uint) *DeviceRetrievalMethod {
+	return &DeviceRetrievalMethod{
+		Type:    RetrievalNFC,
+		Version: 1,
+		Options: map[int]any{
+			NFCMaxCommandDataLength:  maxCommand,
+			NFCMaxResponseDataLength: maxResponse,
+		},
+	}
+}
+
+// DeviceEngagement is what the mdoc shows the reader to start a proximity session, ISO/IEC 18013-5 section 8.2.1.1
+type DeviceEngagement struct {
+	Version                string                   `cbor:"0,keyasint"`
+	Security               *Security                `cbor:"1,keyasint"`
+	DeviceRetrievalMethods []*DeviceRetrievalMethod `cbor:"2,keyasint,omitempty"`
+}
+
+// NewDeviceEngagement returns the device engagement of the ephemeral device key eDeviceKey offering methods
+func NewDeviceEngagement(eDeviceKey crypto.PublicKey, methods ...*DeviceRetrievalMethod) (*DeviceEngagement, error) {
+	security, err := newSecurity(eDeviceKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DeviceEngagement{
+		Version:                EngagementVersion,
+		Security:               security,
+		DeviceRetrievalMethods: methods,
+	}, nil
+}
+
+// Bytes returns the DeviceEngagementBytes of the session transcript
+func (d *DeviceEngagement) Bytes() (EncodedCBOR, error) {
+	return NewEncodedCBOR(d)
+}
+
+// QRCode returns the mdoc: URI of the QR code handover of deviceEngagement, ISO/IEC 18013-5 section 8.2.2.3
+func QRCode(deviceEngagement EncodedCBOR) string {
+	return qrCodeScheme + base64.RawURLEncoding.EncodeToString(deviceEngagement)
+}
+
+// ParseQRCode returns the device engagement of an mdoc: URI, and its bytes as the reader puts them in the session transcript
+func ParseQRCode(uri string) (*DeviceEngagement, EncodedCBOR, error) {
+	encoded, ok := strings.CutPrefix(uri, qrCodeScheme)
+	if !ok {
+		return nil, nil, ErrInvalidQRCode
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, nil, ErrInvalidQRCode
+	}
+	deviceEngagementBytes := EncodedCBOR(b)
+
+	deviceEngagement := &DeviceEngagement{}
+	if err := deviceEngagementBytes.Decode(deviceEngagement); err != nil {
+		return nil, nil, ErrInvalidQRCode
+	}
+	if deviceEngagement.Version != EngagementVersion || deviceEngagement.Security == nil || deviceEngagement.Security.CipherSuite != CipherSuite1 {
+		return nil, nil, ErrInvalidEngagement
+	}
+
+	return deviceEngagement, deviceEngagementBytes, nil
+}
+
+// ReaderEngagement is what the reader shows the mdoc when the reader engages first, it carries the ephemeral reader key
+type ReaderEngagement struct {
+	Version  string    `cbor:"0,keyasint"`
+	Security *Security `cbor:"1,keyasint"`
+}
+
+// NewReaderEngagement returns the reader engagement of the ephemeral reader key eReaderKey
+func NewReaderEngagement(eReaderKey crypto.PublicKey) (*ReaderEngagement, error) {
+	security, err := newSecurity(eReaderKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReaderEngagement{Version: EngagementVersion, Security: security}, nil
+}
+
+// NewQRSessionTranscript returns the session transcript of a proximity session engaged by QR code, the handover is null.
+// eReaderKey is the EReaderKeyBytes of the session establishment message.
+func NewQRSessionTranscript(deviceEngagement, eReaderKey EncodedCBOR) *SessionTranscript {
+	return &SessionTranscript{
+		DeviceEngagementBytes: &deviceEngagement,
+		EReaderKeyBytes:       &eReaderKey,
+	}
+}
@@ -0,0 +1,105 @@
+package mdoc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"strings"
+	"testing"
+	"vc/pkg/cose"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeviceEngagementQRCode(t *testing.T) {
+	eDeviceKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	uuid := []byte{0x45, 0xef, 0xef, 0x74, 0x2b, 0x2c, 0x4e, 0x54, 0x9c, 0x1a, 0x2d, 0x0a, 0x1c, 0x9c, 0x2f, 0x01}
+	deviceEngagement, err := NewDeviceEngagement(&eDeviceKey.PublicKey, NewBLERetrieval(uuid), NewNFCRetrieval(255, 256))
+	assert.NoError(t, err)
+
+	deviceEngagementBytes, err := deviceEngagement.Bytes()
+	assert.NoError(t, err)
+
+	qrCode := QRCode(deviceEngagementBytes)
+	assert.True(t, strings.HasPrefix(qrCode, "mdoc:"))
+
+	got, gotBytes, err := ParseQRCode(qrCode)
+	assert.NoError(t, err)
+	assert.Equal(t, deviceEngagementBytes, gotBytes)
+	assert.Equal(t, EngagementVersion, got.Version)
+
+	key, err := got.Security.Key()
+	assert.NoError(t, err)
+	assert.True(t, eDeviceKey.PublicKey.Equal(key))
+
+	assert.Len(t, got.DeviceRetrievalMethods, 2)
+	ble := got.DeviceRetrievalMethods[0]
+	assert.Equal(t, uint(RetrievalBLE), ble.Type)
+	assert.Equal(t, true, ble.Options[BLEPeripheralServerMode])
+	assert.Equal(t, uuid, ble.Options[BLEPeripheralServerUUID])
+	nfc := got.DeviceRetrievalMethods[1]
+	assert.Equal(t, uint(RetrievalNFC), nfc.Type)
+	assert.Equal(t, uint64(256), nfc.Options[NFCMaxResponseDataLength])
+}
+
+func TestParseQRCode(t *testing.T) {
+	eDeviceKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	security, err := newSecurity(&eDeviceKey.PublicKey)
+	assert.NoError(t, err)
+
+	otherVersion, err := NewEncodedCBOR(&DeviceEngagement{Version: "2.0", Security: security})
+	assert.NoError(t, err)
+
+	tts := []struct {
+		name    string
+		qrCode  string
+		wantErr error
+	}{
+		{name: "other scheme", qrCode: "openid4vp://?request_uri=x", wantErr: ErrInvalidQRCode},
+		{name: "not base64url", qrCode: "mdoc:!!", wantErr: ErrInvalidQRCode},
+		{name: "not cbor", qrCode: "mdoc:AAAA", wantErr: ErrInvalidQRCode},
+		{name: "other version", qrCode: QRCode(otherVersion), wantErr: ErrInvalidEngagement},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ParseQRCode(tt.qrCode)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestQRSessionTranscript(t *testing.T) {
+	eDeviceKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	eReaderKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	deviceEngagement, err := NewDeviceEngagement(&eDeviceKey.PublicKey)
+	assert.NoError(t, err)
+	deviceEngagementBytes, err := deviceEngagement.Bytes()
+	assert.NoError(t, err)
+
+	readerEngagement, err := NewReaderEngagement(&eReaderKey.PublicKey)
+	assert.NoError(t, err)
+
+	b, err := NewQRSessionTranscript(deviceEngagementBytes, readerEngagement.Security.KeyBytes).Bytes()
+	assert.NoError(t, err)
+
+	var transcript []cbor.RawMessage
+	assert.NoError(t, cbor.Unmarshal(b, &transcript))
+	assert.Len(t, transcript, 3)
+	assert.Equal(t, cbor.RawMessage{0xf6}, transcript[2], "the QR handover is null")
+
+	var readerKeyBytes EncodedCBOR
+	assert.NoError(t, cbor.Unmarshal(transcript[1], &readerKeyBytes))
+	readerKey := &cose.Key{}
+	assert.NoError(t, readerKeyBytes.Decode(readerKey))
+	publicKey, err := readerKey.PublicKey()
+	assert.NoError(t, err)
+	assert.True(t, eReaderKey.PublicKey.Equal(publicKey))
+}
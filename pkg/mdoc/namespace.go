@@ -0,0 +1,366 @@
+package mdoc
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+const (
+	// DocTypeMDL is the document type of the mobile driving licence, ISO/IEC 18013-5 section 7.1
+	DocTypeMDL = "org.iso.18013.5.1.mDL"
+
+	// NameSpaceMDL is the name space of the mDL data elements, ISO/IEC 18013-5 section 7.2.1
+	NameSpaceMDL = "org.iso.18013.5.1"
+
+	// NameSpaceAAMVA is the domestic name space of the AAMVA mDL implementation guidelines
+	NameSpaceAAMVA = "org.iso.18013.5.1.aamva"
+)
+
+var (
+	// ErrNameSpaceRegistered is returned when a name space is registered twice
+	ErrNameSpaceRegistered = errors.New("name space already registered")
+
+	// ErrUnknownNameSpace is returned when a name space is not registered for the document type
+	ErrUnknownNameSpace = errors.New("unknown name space")
+
+	// ErrUnknownElement is returned when a data element is not defined in its name space
+	ErrUnknownElement = errors.New("unknown data element")
+
+	// ErrMissingElement is returned when a mandatory data element is missing
+	ErrMissingElement = errors.New("missing data element")
+
+	// ErrInvalidElement is returned when the value of a data element does not satisfy its definition
+	ErrInvalidElement = errors.New("invalid data element")
+)
+
+// ElementValidator checks the value of a data element as decoded from CBOR, or as set by the issuer
+type ElementValidator func(value any) error
+
+// ElementDefinition is a data element of a name space
+type ElementDefinition struct {
+	Identifier string
+
+	// Mandatory elements must be present in the issuer signed items of the name space
+	Mandatory bool
+
+	// Validate checks the value, any value is valid if nil
+	Validate ElementValidator
+}
+
+// NameSpace is a name space of the data elements of a document type, e.g. a domestic extension of the mDL
+type NameSpace struct {
+	Name     string
+	DocType  string
+	Elements []*ElementDefinition
+}
+
+// element returns the definition of identifier
+func (n *NameSpace) element(identifier string) (*ElementDefinition, bool) {
+	for _, element := range n.Elements {
+		if element.Identifier == identifier {
+			return element, true
+		}
+	}
+	return nil, false
+}
+
+// Registry holds the name spaces requests and issuer signed items are validated against
+type Registry struct {
+	mu         sync.RWMutex
+	nameSpaces map[string]*NameSpace
+}
+
+// NewRegistry creates a registry of nameSpaces
+func NewRegistry(nameSpaces ...*NameSpace) (*Registry, error) {
+	r := &Registry{nameSpaces: map[string]*NameSpace{}}
+	for _, nameSpace := range nameSpaces {
+		if err := r.Register(nameSpace); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// NewDefaultRegistry creates a registry of the mDL name space and the AAMVA domestic name space, further domestic
+// name spaces can be registered on it
+func NewDefaultRegistry() *Registry {
+	r, _ := NewRegistry(mdlNameSpace(), aamvaNameSpace())
+	return r
+}
+
+// Register adds nameSpace, ErrNameSpaceRegistered if a name space with the same name is registered
+func (r *Registry) Register(nameSpace *NameSpace) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.nameSpaces[nameSpace.Name]; ok {
+		return fmt.Errorf("%w: %s", ErrNameSpaceRegistered, nameSpace.Name)
+	}
+	r.nameSpaces[nameSpace.Name] = nameSpace
+
+	return nil
+}
+
+// NameSpace returns the registered name space name
+func (r *Registry) NameSpace(name string) (*NameSpace, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	nameSpace, ok := r.nameSpaces[name]
+	return nameSpace, ok
+}
+
+// NameSpaces returns the names of the name spaces registered for docType, sorted
+func (r *Registry) NameSpaces(docType string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := []string{}
+	for name, nameSpace := range r.nameSpaces {
+		if nameSpace.DocType == docType {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// lookup returns the name space name of docType
+func (r *Registry) lookup(docType, name string) (*NameSpace, error) {
+	nameSpace, ok := r.NameSpace(name)
+	if !ok || nameSpace.DocType != docType {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownNameSpace, name)
+	}
+	return nameSpace, nil
+}
+
+// ValidateItemsRequest checks that every requested data element is defined in a name space registered for the document type
+func (r *Registry) ValidateItemsRequest(items *ItemsRequest) error {
+	for name, elements := range items.NameSpaces {
+		nameSpace, err := r.lookup(items.DocType, name)
+		if err != nil {
+			return err
+		}
+		for identifier := range elements {
+			if _, ok := nameSpace.element(identifier); !ok {
+				return fmt.Errorf("%w: %s %s", ErrUnknownElement, name, identifier)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateElements checks the issuer signed data elements of a name space of docType, every element must be defined and
+// valid, and the mandatory elements present
+func (r *Registry) ValidateElements(docType, name string, elements map[string]any) error {
+	nameSpace, err := r.lookup(docType, name)
+	if err != nil {
+		return err
+	}
+
+	for identifier, value := range elements {
+		definition, ok := nameSpace.element(identifier)
+		if !ok {
+			return fmt.Errorf("%w: %s %s", ErrUnknownElement, name, identifier)
+		}
+		if definition.Validate == nil {
+			continue
+		}
+		if err := definition.Validate(value); err != nil {
+			return fmt.Errorf("%w: %s %s: %w", ErrInvalidElement, name, identifier, err)
+		}
+	}
+
+	for _, definition := range nameSpace.Elements {
+		if _, ok := elements[definition.Identifier]; definition.Mandatory && !ok {
+			return fmt.Errorf("%w: %s %s", ErrMissingElement, name, definition.Identifier)
+		}
+	}
+
+	return nil
+}
+
+// ValidateString accepts text strings
+func ValidateString(value any) error {
+	if _, ok := value.(string); !ok {
+		return fmt.Errorf("%T is not a tstr", value)
+	}
+	return nil
+}
+
+// ValidateBool accepts booleans
+func ValidateBool(value any) error {
+	if _, ok := value.(bool); !ok {
+		return fmt.Errorf("%T is not a bool", value)
+	}
+	return nil
+}
+
+// ValidateBytes accepts byte strings
+func ValidateBytes(value any) error {
+	if _, ok := value.([]byte); !ok {
+		return fmt.Errorf("%T is not a bstr", value)
+	}
+	return nil
+}
+
+// ValidateUint accepts unsigned integers
+func ValidateUint(value any) error {
+	if _, ok := asUint(value); !ok {
+		return fmt.Errorf("%T is not a uint", value)
+	}
+	return nil
+}
+
+// ValidateUintOneOf returns a validator accepting the unsigned integers in values, e.g. the ISO/IEC 5218 sex codes
+func ValidateUintOneOf(values ...uint64) ElementValidator {
+	return func(value any) error {
+		v, ok := asUint(value)
+		if !ok || !slices.Contains(values, v) {
+			return fmt.Errorf("%v is not one of %v", value, values)
+		}
+		return nil
+	}
+}
+
+// ValidateStringOneOf returns a validator accepting the text strings in values
+func ValidateStringOneOf(values ...string) ElementValidator {
+	return func(value any) error {
+		v, ok := value.(string)
+		if !ok || !slices.Contains(values, v) {
+			return fmt.Errorf("%v is not one of %v", value, values)
+		}
+		return nil
+	}
+}
+
+// ValidateFullDate accepts FullDate and full-date tagged strings
+func ValidateFullDate(value any) error {
+	switch v := value.(type) {
+	case FullDate, *FullDate:
+		return nil
+	case cbor.Tag:
+		if s, ok := v.Content.(string); ok && v.Number == tagFullDate {
+			_, err := ParseFullDate(s)
+			return err
+		}
+	}
+	return fmt.Errorf("%T is not a full-date", value)
+}
+
+// ValidateDate accepts full-dates and tdates, e.g. issue_date and expiry_date. A tdate decodes to time.Time.
+func ValidateDate(value any) error {
+	switch value.(type) {
+	case TDate, *TDate, time.Time:
+		return nil
+	}
+	return ValidateFullDate(value)
+}
+
+// ValidateArray accepts arrays, e.g. driving_privileges
+func ValidateArray(value any) error {
+	if _, ok := value.([]any); !ok {
+		return fmt.Errorf("%T is not an array", value)
+	}
+	return nil
+}
+
+// asUint returns value as uint64 if it is a non negative integer
+func asUint(value any) (uint64, bool) {
+	switch v := value.(type) {
+	case uint64:
+		return v, true
+	case uint:
+		return uint64(v), true
+	case uint8:
+		return uint64(v), true
+	case int64:
+		return uint64(v), v >= 0
+	case int:
+		return uint64(v), v >= 0
+	}
+	return 0, false
+}
+
+// mdlNameSpace returns the data elements of ISO/IEC 18013-5 table 5
+func mdlNameSpace() *NameSpace {
+	return &NameSpace{
+		Name:    NameSpaceMDL,
+		DocType: DocTypeMDL,
+		Elements: []*ElementDefinition{
+			{Identifier: "family_name", Mandatory: true, Validate: ValidateString},
+			{Identifier: "given_name", Mandatory: true, Validate: ValidateString},
+			{Identifier: "birth_date", Mandatory: true, Validate: ValidateFullDate},
+			{Identifier: "issue_date", Mandatory: true, Validate: ValidateDate},
+			{Identifier: "expiry_date", Mandatory: true, Validate: ValidateDate},
+			{Identifier: "issuing_country", Mandatory: true, Validate: ValidateString},
+			{Identifier: "issuing_authority", Mandatory: true, Validate: ValidateString},
+			{Identifier: "document_number", Mandatory: true, Validate: ValidateString},
+			{Identifier: "portrait", Mandatory: true, Validate: ValidateBytes},
+			{Identifier: "driving_privileges", Mandatory: true, Validate: ValidateArray},
+			{Identifier: "un_distinguishing_sign", Mandatory: true, Validate: ValidateString},
+			{Identifier: "administrative_number", Validate: ValidateString},
+			{Identifier: "sex", Validate: ValidateUintOneOf(0, 1, 2, 9)},
+			{Identifier: "height", Validate: ValidateUint},
+			{Identifier: "weight", Validate: ValidateUint},
+			{Identifier: "eye_colour", Validate: ValidateString},
+			{Identifier: "hair_colour", Validate: ValidateString},
+			{Identifier: "birth_place", Validate: ValidateString},
+			{Identifier: "resident_address", Validate: ValidateString},
+			{Identifier: "portrait_capture_date", Validate: ValidateDate},
+			{Identifier: "age_in_years", Validate: ValidateUint},
+			{Identifier: "age_birth_year", Validate: ValidateUint},
+			{Identifier: "age_over_18", Validate: ValidateBool},
+			{Identifier: "age_over_21", Validate: ValidateBool},
+			{Identifier: "issuing_jurisdiction", Validate: ValidateString},
+			{Identifier: "nationality", Validate: ValidateString},
+			{Identifier: "resident_city", Validate: ValidateString},
+			{Identifier: "resident_state", Validate: ValidateString},
+			{Identifier: "resident_postal_code", Validate: ValidateString},
+			{Identifier: "resident_country", Validate: ValidateString},
+			{Identifier: "family_name_national_character", Validate: ValidateString},
+			{Identifier: "given_name_national_character", Validate: ValidateString},
+			{Identifier: "signature_usual_mark", Validate: ValidateBytes},
+		},
+	}
+}
+
+// aamvaNameSpace returns the domestic data elements of the AAMVA mDL implementation guidelines
+func aamvaNameSpace() *NameSpace {
+	truncation := ValidateStringOneOf("T", "N", "U")
+
+	return &NameSpace{
+		Name:    NameSpaceAAMVA,
+		DocType: DocTypeMDL,
+		Elements: []*ElementDefinition{
+			{Identifier: "domestic_driving_privileges", Validate: ValidateArray},
+			{Identifier: "name_suffix", Validate: ValidateString},
+			{Identifier: "organ_donor", Validate: ValidateUintOneOf(1)},
+			{Identifier: "veteran", Validate: ValidateUintOneOf(1)},
+			{Identifier: "family_name_truncation", Mandatory: true, Validate: truncation},
+			{Identifier: "given_name_truncation", Mandatory: true, Validate: truncation},
+			{Identifier: "aka_family_name", Validate: ValidateString},
+			{Identifier: "aka_given_name", Validate: ValidateString},
+			{Identifier: "aka_suffix", Validate: ValidateString},
+			{Identifier: "weight_range", Validate: ValidateUint},
+			{Identifier: "race_ethnicity", Validate: ValidateString},
+			{Identifier: "DHS_compliance", Mandatory: true, Validate: ValidateStringOneOf("F", "N")},
+			{Identifier: "DHS_temporary_lawful_status", Validate: ValidateUintOneOf(1)},
+			{Identifier: "EDL_credential", Validate: ValidateUint},
+			{Identifier: "resident_county", Validate: ValidateString},
+			{Identifier: "hazmat_endorsement_expiration_date", Validate: ValidateFullDate},
+			{Identifier: "CDL_indicator", Validate: ValidateUintOneOf(1)},
+			{Identifier: "DHS_compliance_text", Validate: ValidateString},
+			{Identifier: "sex", Mandatory: true, Validate: ValidateUintOneOf(1, 2, 9)},
+		},
+	}
+}
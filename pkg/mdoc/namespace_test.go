@@ -0,0 +1,169 @@
+package mdoc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockMDLElements returns the mandatory mDL data elements, as decoded from CBOR
+func mockMDLElements(t *testing.T) map[string]any {
+	b, err := encMode.Marshal(map[string]any{
+		"family_name":            "Andersson",
+		"given_name":             "Anna",
+		"birth_date":             NewFullDate(time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)),
+		"issue_date":             NewFullDate(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+		"expiry_date":            NewTDate(time.Date(2034, 1, 1, 0, 0, 0, 0, time.UTC)),
+		"issuing_country":        "SE",
+		"issuing_authority":      "Transportstyrelsen",
+		"document_number":        "123456789",
+		"portrait":               []byte{0xff, 0xd8},
+		"driving_privileges":     []any{map[string]any{"vehicle_category_code": "B"}},
+		"un_distinguishing_sign": "S",
+		"sex":                    2,
+	})
+	assert.NoError(t, err)
+
+	elements := map[string]any{}
+	assert.NoError(t, cbor.Unmarshal(b, &elements))
+
+	return elements
+}
+
+func TestRegistryValidateElements(t *testing.T) {
+	registry := NewDefaultRegistry()
+
+	tts := []struct {
+		name      string
+		docType   string
+		nameSpace string
+		elements  func(elements map[string]any) map[string]any
+		wantErr   error
+	}{
+		{
+			name:      "mdl",
+			docType:   DocTypeMDL,
+			nameSpace: NameSpaceMDL,
+			elements:  func(elements map[string]any) map[string]any { return elements },
+		},
+		{
+			name:      "missing mandatory",
+			docType:   DocTypeMDL,
+			nameSpace: NameSpaceMDL,
+			elements: func(elements map[string]any) map[string]any {
+				delete(elements, "portrait")
+				return elements
+			},
+			wantErr: ErrMissingElement,
+		},
+		{
+			name:      "unknown element",
+			docType:   DocTypeMDL,
+			nameSpace: NameSpaceMDL,
+			elements: func(elements map[string]any) map[string]any {
+				elements["favourite_colour"] = "blue"
+				return elements
+			},
+			wantErr: ErrUnknownElement,
+		},
+		{
+			name:      "birth_date not a full-date",
+			docType:   DocTypeMDL,
+			nameSpace: NameSpaceMDL,
+			elements: func(elements map[string]any) map[string]any {
+				elements["birth_date"] = "1990-05-17"
+				return elements
+			},
+			wantErr: ErrInvalidElement,
+		},
+		{
+			name:      "sex not ISO 5218",
+			docType:   DocTypeMDL,
+			nameSpace: NameSpaceMDL,
+			elements: func(elements map[string]any) map[string]any {
+				elements["sex"] = uint64(3)
+				return elements
+			},
+			wantErr: ErrInvalidElement,
+		},
+		{
+			name:      "aamva",
+			docType:   DocTypeMDL,
+			nameSpace: NameSpaceAAMVA,
+			elements: func(map[string]any) map[string]any {
+				return map[string]any{
+					"family_name_truncation": "N",
+					"given_name_truncation":  "N",
+					"DHS_compliance":         "F",
+					"sex":                    uint64(2),
+					"organ_donor":            uint64(1),
+				}
+			},
+		},
+		{
+			name:      "aamva invalid truncation",
+			docType:   DocTypeMDL,
+			nameSpace: NameSpaceAAMVA,
+			elements: func(map[string]any) map[string]any {
+				return map[string]any{
+					"family_name_truncation": "X",
+					"given_name_truncation":  "N",
+					"DHS_compliance":         "F",
+					"sex":                    uint64(2),
+				}
+			},
+			wantErr: ErrInvalidElement,
+		},
+		{
+			name:      "name space of other doc type",
+			docType:   "eu.europa.ec.eudi.pid.1",
+			nameSpace: NameSpaceMDL,
+			elements:  func(elements map[string]any) map[string]any { return elements },
+			wantErr:   ErrUnknownNameSpace,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			err := registry.ValidateElements(tt.docType, tt.nameSpace, tt.elements(mockMDLElements(t)))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRegistryRegister(t *testing.T) {
+	registry := NewDefaultRegistry()
+
+	domestic := &NameSpace{
+		Name:    "org.iso.18013.5.1.SE",
+		DocType: DocTypeMDL,
+		Elements: []*ElementDefinition{
+			{Identifier: "personnummer", Mandatory: true, Validate: ValidateString},
+		},
+	}
+	assert.NoError(t, registry.Register(domestic))
+	assert.ErrorIs(t, registry.Register(domestic), ErrNameSpaceRegistered)
+	assert.Equal(t, []string{NameSpaceMDL, "org.iso.18013.5.1.SE", NameSpaceAAMVA}, registry.NameSpaces(DocTypeMDL))
+
+	items := &ItemsRequest{
+		DocType: DocTypeMDL,
+		NameSpaces: map[string]map[string]bool{
+			NameSpaceMDL:           {"family_name": false, "portrait": false},
+			"org.iso.18013.5.1.SE": {"personnummer": true},
+		},
+	}
+	assert.NoError(t, registry.ValidateItemsRequest(items))
+
+	items.NameSpaces["org.iso.18013.5.1.SE"]["skattetabell"] = false
+	assert.ErrorIs(t, registry.ValidateItemsRequest(items), ErrUnknownElement)
+
+	delete(items.NameSpaces["org.iso.18013.5.1.SE"], "skattetabell")
+	items.NameSpaces["org.iso.18013.5.1.NO"] = map[string]bool{"fodselsnummer": false}
+	assert.ErrorIs(t, registry.ValidateItemsRequest(items), ErrUnknownNameSpace)
+}
@@ -0,0 +1,219 @@
+package mdoc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Sex codes of ISO/IEC 5218, the values of the mDL sex element
+const (
+	SexNotKnown      = 0
+	SexMale          = 1
+	SexFemale        = 2
+	SexNotApplicable = 9
+)
+
+// ErrInvalidClaim is returned when a PID claim can not be mapped to an mDL data element
+var ErrInvalidClaim = errors.New("invalid pid claim")
+
+// MDL holds the data elements of the mDL name space that the PID also carries, ISO/IEC 18013-5 table 5
+type MDL struct {
+	FamilyName         string   `cbor:"family_name" json:"family_name"`
+	GivenName          string   `cbor:"given_name" json:"given_name"`
+	BirthDate          FullDate `cbor:"birth_date" json:"birth_date"`
+	BirthPlace         string   `cbor:"birth_place,omitempty" json:"birth_place,omitempty"`
+	Sex                *uint    `cbor:"sex,omitempty" json:"sex,omitempty"`
+	Nationality        string   `cbor:"nationality,omitempty" json:"nationality,omitempty"`
+	ResidentAddress    string   `cbor:"resident_address,omitempty" json:"resident_address,omitempty"`
+	ResidentCity       string   `cbor:"resident_city,omitempty" json:"resident_city,omitempty"`
+	ResidentState      string   `cbor:"resident_state,omitempty" json:"resident_state,omitempty"`
+	ResidentPostalCode string   `cbor:"resident_postal_code,omitempty" json:"resident_postal_code,omitempty"`
+	ResidentCountry    string   `cbor:"resident_country,omitempty" json:"resident_country,omitempty"`
+	IssueDate          FullDate `cbor:"issue_date" json:"issue_date"`
+	ExpiryDate         FullDate `cbor:"expiry_date" json:"expiry_date"`
+	IssuingCountry     string   `cbor:"issuing_country" json:"issuing_country"`
+	IssuingAuthority   string   `cbor:"issuing_authority" json:"issuing_authority"`
+	DocumentNumber     string   `cbor:"document_number" json:"document_number"`
+	AgeOver18          *bool    `cbor:"age_over_18,omitempty" json:"age_over_18,omitempty"`
+}
+
+// PIDClaims returns the ARF 1.8 PID claims of the mDL, as issued in an SD-JWT PID. Dates become "2006-01-02" strings,
+// the nationality a nationalities array and the resident elements an address object.
+func (m *MDL) PIDClaims() map[string]any {
+	claims := map[string]any{
+		"family_name":       m.FamilyName,
+		"given_name":        m.GivenName,
+		"birthdate":         m.BirthDate.String(),
+		"date_of_issuance":  m.IssueDate.String(),
+		"date_of_expiry":    m.ExpiryDate.String(),
+		"issuing_country":   m.IssuingCountry,
+		"issuing_authority": m.IssuingAuthority,
+		"document_number":   m.DocumentNumber,
+	}
+
+	if m.BirthPlace != "" {
+		claims["place_of_birth"] = map[string]any{"locality": m.BirthPlace}
+	}
+	if m.Sex != nil {
+		claims["sex"] = int(*m.Sex)
+	}
+	if m.Nationality != "" {
+		claims["nationalities"] = []string{m.Nationality}
+	}
+	if m.AgeOver18 != nil {
+		claims["age_equal_or_over"] = map[string]any{"18": *m.AgeOver18}
+	}
+
+	address := map[string]any{}
+	for claim, value := range map[string]string{
+		"street_address": m.ResidentAddress,
+		"locality":       m.ResidentCity,
+		"region":         m.ResidentState,
+		"postal_code":    m.ResidentPostalCode,
+		"country":        m.ResidentCountry,
+	} {
+		if value != "" {
+			address[claim] = value
+		}
+	}
+	if len(address) > 0 {
+		claims["address"] = address
+	}
+
+	return claims
+}
+
+// MDLFromPID returns the mDL data elements of ARF 1.8 PID claims, e.g. as decoded from JSON. The mDL holds a single
+// nationality, the first of nationalities, and ISO/IEC 5218 sex codes, the PID codes without an ISO/IEC 5218
+// counterpart become SexNotKnown.
+func MDLFromPID(claims map[string]any) (*MDL, error) {
+	m := &MDL{}
+
+	var err error
+	for claim, field := range map[string]*string{
+		"family_name":       &m.FamilyName,
+		"given_name":        &m.GivenName,
+		"issuing_country":   &m.IssuingCountry,
+		"issuing_authority": &m.IssuingAuthority,
+		"document_number":   &m.DocumentNumber,
+	} {
+		if *field, err = stringClaim(claims, claim); err != nil {
+			return nil, err
+		}
+	}
+
+	for claim, field := range map[string]*FullDate{
+		"birthdate":        &m.BirthDate,
+		"date_of_issuance": &m.IssueDate,
+		"date_of_expiry":   &m.ExpiryDate,
+	} {
+		s, err := stringClaim(claims, claim)
+		if err != nil {
+			return nil, err
+		}
+		if s == "" {
+			continue
+		}
+		if *field, err = ParseFullDate(s); err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidClaim, claim, err)
+		}
+	}
+
+	if v, ok := claims["sex"]; ok {
+		code, ok := asUint(jsonNumber(v))
+		if !ok {
+			return nil, fmt.Errorf("%w: sex", ErrInvalidClaim)
+		}
+		sex := uint(SexNotKnown)
+		switch code {
+		case SexMale, SexFemale, SexNotApplicable:
+			sex = uint(code)
+		}
+		m.Sex = &sex
+	}
+
+	if v, ok := claims["nationalities"]; ok {
+		nationalities, ok := stringList(v)
+		if !ok {
+			return nil, fmt.Errorf("%w: nationalities", ErrInvalidClaim)
+		}
+		if len(nationalities) > 0 {
+			m.Nationality = nationalities[0]
+		}
+	}
+
+	if v, ok := claims["place_of_birth"]; ok {
+		placeOfBirth, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%w: place_of_birth", ErrInvalidClaim)
+		}
+		parts := []string{}
+		for _, claim := range []string{"locality", "region", "country"} {
+			if s, _ := placeOfBirth[claim].(string); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		m.BirthPlace = strings.Join(parts, ", ")
+	}
+
+	if v, ok := claims["address"]; ok {
+		address, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%w: address", ErrInvalidClaim)
+		}
+		m.ResidentAddress, _ = address["street_address"].(string)
+		m.ResidentCity, _ = address["locality"].(string)
+		m.ResidentState, _ = address["region"].(string)
+		m.ResidentPostalCode, _ = address["postal_code"].(string)
+		m.ResidentCountry, _ = address["country"].(string)
+	}
+
+	if v, ok := claims["age_equal_or_over"].(map[string]any); ok {
+		if over18, ok := v["18"].(bool); ok {
+			m.AgeOver18 = &over18
+		}
+	}
+
+	return m, nil
+}
+
+// stringClaim returns the string claim, empty if missing
+func stringClaim(claims map[string]any, claim string) (string, error) {
+	v, ok := claims[claim]
+	if !ok {
+		return "", nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrInvalidClaim, claim)
+	}
+	return s, nil
+}
+
+// stringList returns a []string or []any of strings
+func stringList(v any) ([]string, bool) {
+	switch list := v.(type) {
+	case []string:
+		return list, true
+	case []any:
+		s := make([]string, 0, len(list))
+		for _, item := range list {
+			str, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			s = append(s, str)
+		}
+		return s, true
+	}
+	return nil, false
+}
+
+// jsonNumber returns whole float64 numbers, as decoded from JSON, as int64
+func jsonNumber(v any) any {
+	if f, ok := v.(float64); ok && f == float64(int64(f)) {
+		return int64(f)
+	}
+	return v
+}
@@ -0,0 +1,120 @@
+package mdoc
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockMDL() *MDL {
+	sex := uint(SexFemale)
+	over18 := true
+
+	return &MDL{
+		FamilyName:         "Andersson",
+		GivenName:          "Anna",
+		BirthDate:          NewFullDate(time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)),
+		BirthPlace:         "Uppsala",
+		Sex:                &sex,
+		Nationality:        "SE",
+		ResidentAddress:    "Storgatan 1",
+		ResidentCity:       "Stockholm",
+		ResidentPostalCode: "111 22",
+		ResidentCountry:    "SE",
+		IssueDate:          NewFullDate(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+		ExpiryDate:         NewFullDate(time.Date(2034, 1, 1, 0, 0, 0, 0, time.UTC)),
+		IssuingCountry:     "SE",
+		IssuingAuthority:   "Transportstyrelsen",
+		DocumentNumber:     "123456789",
+		AgeOver18:          &over18,
+	}
+}
+
+func TestPIDClaimsRoundTrip(t *testing.T) {
+	mdl := mockMDL()
+
+	claims := mdl.PIDClaims()
+	assert.Equal(t, "1990-05-17", claims["birthdate"])
+	assert.Equal(t, []string{"SE"}, claims["nationalities"])
+	assert.Equal(t, SexFemale, claims["sex"])
+	assert.Equal(t, map[string]any{"locality": "Uppsala"}, claims["place_of_birth"])
+	assert.Equal(t, "Stockholm", claims["address"].(map[string]any)["locality"])
+
+	// as the claims arrive in an SD-JWT
+	b, err := json.Marshal(claims)
+	assert.NoError(t, err)
+	decoded := map[string]any{}
+	assert.NoError(t, json.Unmarshal(b, &decoded))
+
+	got, err := MDLFromPID(decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, mdl, got)
+}
+
+func TestMDLFromPID(t *testing.T) {
+	tts := []struct {
+		name    string
+		claims  map[string]any
+		want    func(m *MDL)
+		wantErr error
+	}{
+		{
+			name:   "several nationalities",
+			claims: map[string]any{"nationalities": []any{"SE", "FI"}},
+			want:   func(m *MDL) { m.Nationality = "SE" },
+		},
+		{
+			name:   "sex without ISO 5218 code",
+			claims: map[string]any{"sex": float64(5)},
+			want: func(m *MDL) {
+				sex := uint(SexNotKnown)
+				m.Sex = &sex
+			},
+		},
+		{
+			name:   "sex not applicable",
+			claims: map[string]any{"sex": float64(9)},
+			want: func(m *MDL) {
+				sex := uint(SexNotApplicable)
+				m.Sex = &sex
+			},
+		},
+		{
+			name:   "place of birth",
+			claims: map[string]any{"place_of_birth": map[string]any{"locality": "Uppsala", "country": "SE"}},
+			want:   func(m *MDL) { m.BirthPlace = "Uppsala, SE" },
+		},
+		{
+			name:    "birthdate not a full-date",
+			claims:  map[string]any{"birthdate": "17/05/1990"},
+			wantErr: ErrInvalidClaim,
+		},
+		{
+			name:    "family_name not a string",
+			claims:  map[string]any{"family_name": 1},
+			wantErr: ErrInvalidClaim,
+		},
+		{
+			name:    "sex not a number",
+			claims:  map[string]any{"sex": "female"},
+			wantErr: ErrInvalidClaim,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MDLFromPID(tt.claims)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			want := &MDL{}
+			tt.want(want)
+			assert.Equal(t, want, got)
+		})
+	}
+}
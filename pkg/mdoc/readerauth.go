@@ -0,0 +1,118 @@
+package mdoc
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"vc/pkg/cose"
+	"vc/pkg/keyresolver"
+	"vc/pkg/signing"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// readerAuthenticationContext is the first element of the ReaderAuthentication array
+const readerAuthenticationContext = "ReaderAuthentication"
+
+var (
+	// ErrNoReaderAuth is returned when a doc request carries no reader authentication
+	ErrNoReaderAuth = errors.New("no reader authentication")
+
+	// ErrNoReaderCertificate is returned when the reader authentication has no x5chain header
+	ErrNoReaderCertificate = errors.New("no reader certificate")
+)
+
+// ReaderAuthenticationBytes returns #6.24(bstr .cbor ReaderAuthentication), the detached payload of ReaderAuth.
+// sessionTranscript is the encoded SessionTranscript of the session, ISO/IEC 18013-5 section 9.1.4.
+func ReaderAuthenticationBytes(sessionTranscript []byte, itemsRequest EncodedCBOR) ([]byte, error) {
+	readerAuthentication, err := NewEncodedCBOR([]any{
+		readerAuthenticationContext,
+		cbor.RawMessage(sessionTranscript),
+		itemsRequest,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return encMode.Marshal(readerAuthentication)
+}
+
+// NewReaderAuthResolver returns a chain resolver for reader certificates issued under roots
+func NewReaderAuthResolver(roots *x509.CertPool) *keyresolver.X509 {
+	return keyresolver.NewX509(roots, keyresolver.OIDExtKeyUsageMDLReaderAuth)
+}
+
+// NewDocRequest creates a doc request for items. If signer is not nil the request carries reader authentication,
+// signed with signer and the reader certificate chain, leaf first, in the x5chain header.
+func NewDocRequest(items *ItemsRequest, sessionTranscript []byte, signer *signing.Signer, chain []*x509.Certificate) (*DocRequest, error) {
+	itemsRequest, err := NewEncodedCBOR(items)
+	if err != nil {
+		return nil, err
+	}
+
+	docRequest := &DocRequest{
+		ItemsRequest: itemsRequest,
+	}
+	if signer == nil {
+		return docRequest, nil
+	}
+
+	payload, err := ReaderAuthenticationBytes(sessionTranscript, itemsRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	x5chain := make([][]byte, 0, len(chain))
+	for _, cert := range chain {
+		x5chain = append(x5chain, cert.Raw)
+	}
+
+	docRequest.ReaderAuth, err = cose.SignSign1Detached(signer, x5chain, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return docRequest, nil
+}
+
+// VerifyReaderAuth verifies the reader authentication of the doc request over sessionTranscript and returns the reader certificate.
+// The certificate chain of the x5chain header is validated by resolver, see NewReaderAuthResolver.
+func (d *DocRequest) VerifyReaderAuth(ctx context.Context, sessionTranscript []byte, resolver keyresolver.ChainResolver) (*x509.Certificate, error) {
+	if len(d.ReaderAuth) == 0 {
+		return nil, ErrNoReaderAuth
+	}
+
+	msg, err := cose.ParseSign1(d.ReaderAuth)
+	if err != nil {
+		return nil, err
+	}
+	if len(msg.X5Chain) == 0 {
+		return nil, ErrNoReaderCertificate
+	}
+
+	chain := make([]*x509.Certificate, 0, len(msg.X5Chain))
+	for _, der := range msg.X5Chain {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrNoReaderCertificate, err)
+		}
+		chain = append(chain, cert)
+	}
+
+	publicKey, err := resolver.ResolveChain(ctx, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := ReaderAuthenticationBytes(sessionTranscript, d.ItemsRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := msg.VerifyDetached(publicKey, payload); err != nil {
+		return nil, err
+	}
+
+	return chain[0], nil
+}
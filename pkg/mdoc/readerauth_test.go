@@ -0,0 +1,199 @@
+package mdoc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+	"time"
+	"vc/pkg/cose"
+	"vc/pkg/keyresolver"
+	"vc/pkg/signing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockCertificate(t *testing.T, template *x509.Certificate, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	assert.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	return cert, key
+}
+
+func mockReaderCA(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey) {
+	return mockCertificate(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "reader CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil, nil)
+}
+
+func mockReader(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey, eku ...asn1.ObjectIdentifier) (*x509.Certificate, *signing.Signer) {
+	cert, key := mockCertificate(t, &x509.Certificate{
+		SerialNumber:       big.NewInt(2),
+		Subject:            pkix.Name{CommonName: "reader"},
+		NotBefore:          time.Now().Add(-time.Hour),
+		NotAfter:           time.Now().Add(time.Hour),
+		KeyUsage:           x509.KeyUsageDigitalSignature,
+		UnknownExtKeyUsage: eku,
+	}, ca, caKey)
+
+	signer, err := signing.NewSoftware(key)
+	assert.NoError(t, err)
+
+	return cert, signer
+}
+
+func mockSessionTranscript(t *testing.T, nonce string) []byte {
+	b, err := OID4VPSessionTranscript("https://verifier.sunet.se", "https://verifier.sunet.se/response", nonce, "mdoc nonce")
+	assert.NoError(t, err)
+	return b
+}
+
+var mockItems = &ItemsRequest{
+	DocType: "org.iso.18013.5.1.mDL",
+	NameSpaces: map[string]map[string]bool{
+		"org.iso.18013.5.1": {"family_name": false, "birth_date": true},
+	},
+}
+
+func TestReaderAuth(t *testing.T) {
+	ca, caKey := mockReaderCA(t)
+	otherCA, otherCAKey := mockReaderCA(t)
+	reader, signer := mockReader(t, ca, caKey, keyresolver.OIDExtKeyUsageMDLReaderAuth)
+	otherReader, otherSigner := mockReader(t, otherCA, otherCAKey, keyresolver.OIDExtKeyUsageMDLReaderAuth)
+	noEKUReader, noEKUSigner := mockReader(t, ca, caKey)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	resolver := NewReaderAuthResolver(roots)
+
+	tts := []struct {
+		name           string
+		signer         *signing.Signer
+		chain          []*x509.Certificate
+		verifyNonce    string
+		tamperItems    bool
+		wantErr        error
+		wantReaderCert *x509.Certificate
+	}{
+		{
+			name:           "valid",
+			signer:         signer,
+			chain:          []*x509.Certificate{reader},
+			verifyNonce:    "nonce",
+			wantReaderCert: reader,
+		},
+		{
+			name:           "valid with chain",
+			signer:         signer,
+			chain:          []*x509.Certificate{reader, ca},
+			verifyNonce:    "nonce",
+			wantReaderCert: reader,
+		},
+		{
+			name:        "other session",
+			signer:      signer,
+			chain:       []*x509.Certificate{reader},
+			verifyNonce: "other nonce",
+			wantErr:     cose.ErrInvalidSignature,
+		},
+		{
+			name:        "tampered items request",
+			signer:      signer,
+			chain:       []*x509.Certificate{reader},
+			verifyNonce: "nonce",
+			tamperItems: true,
+			wantErr:     cose.ErrInvalidSignature,
+		},
+		{
+			name:        "untrusted reader",
+			signer:      otherSigner,
+			chain:       []*x509.Certificate{otherReader},
+			verifyNonce: "nonce",
+			wantErr:     keyresolver.ErrUntrustedChain,
+		},
+		{
+			name:        "reader without reader auth key usage",
+			signer:      noEKUSigner,
+			chain:       []*x509.Certificate{noEKUReader},
+			verifyNonce: "nonce",
+			wantErr:     keyresolver.ErrMissingKeyUsage,
+		},
+		{
+			name:        "no certificate",
+			signer:      signer,
+			verifyNonce: "nonce",
+			wantErr:     ErrNoReaderCertificate,
+		},
+		{
+			name:        "no reader auth",
+			verifyNonce: "nonce",
+			wantErr:     ErrNoReaderAuth,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			docRequest, err := NewDocRequest(mockItems, mockSessionTranscript(t, "nonce"), tt.signer, tt.chain)
+			assert.NoError(t, err)
+
+			// round trip the device request as a reader would send it
+			b, err := cbor.Marshal(&DeviceRequest{Version: DeviceRequestVersion, DocRequests: []*DocRequest{docRequest}})
+			assert.NoError(t, err)
+			deviceRequest := &DeviceRequest{}
+			assert.NoError(t, cbor.Unmarshal(b, deviceRequest))
+			docRequest = deviceRequest.DocRequests[0]
+
+			items, err := docRequest.Items()
+			assert.NoError(t, err)
+			assert.Equal(t, mockItems.DocType, items.DocType)
+
+			if tt.tamperItems {
+				items.NameSpaces["org.iso.18013.5.1"]["portrait"] = false
+				docRequest.ItemsRequest, err = NewEncodedCBOR(items)
+				assert.NoError(t, err)
+			}
+
+			cert, err := docRequest.VerifyReaderAuth(context.TODO(), mockSessionTranscript(t, tt.verifyNonce), resolver)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantReaderCert, cert)
+		})
+	}
+}
+
+func TestEncodedCBOR(t *testing.T) {
+	e, err := NewEncodedCBOR(mockItems)
+	assert.NoError(t, err)
+
+	b, err := cbor.Marshal(e)
+	assert.NoError(t, err)
+	assert.Equal(t, byte(0xd8), b[0], "tag 24")
+	assert.Equal(t, byte(tagEncodedCBOR), b[1])
+
+	decoded := EncodedCBOR{}
+	assert.NoError(t, cbor.Unmarshal(b, &decoded))
+	assert.Equal(t, e, decoded)
+
+	assert.ErrorIs(t, cbor.Unmarshal([]byte{0x43, 1, 2, 3}, &decoded), ErrInvalidEncodedCBOR)
+}
@@ -0,0 +1,89 @@
+package mdoc
+
+import (
+	"errors"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// tagEncodedCBOR is the CBOR tag of embedded CBOR data, RFC 8949 section 3.4.5.1
+const tagEncodedCBOR = 24
+
+// DeviceRequestVersion is the version of a device request, ISO/IEC 18013-5 section 8.3.2.1.2.1
+const DeviceRequestVersion = "1.0"
+
+var (
+	// ErrInvalidEncodedCBOR is returned when data is not tag 24 wrapped CBOR
+	ErrInvalidEncodedCBOR = errors.New("invalid encoded cbor")
+)
+
+var encMode, _ = cbor.CoreDetEncOptions().EncMode()
+
+// EncodedCBOR is the encoding of a CBOR data item, encoded as #6.24(bstr), e.g. ItemsRequestBytes
+type EncodedCBOR []byte
+
+// NewEncodedCBOR encodes v and wraps it as EncodedCBOR
+func NewEncodedCBOR(v any) (EncodedCBOR, error) {
+	b, err := encMode.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return EncodedCBOR(b), nil
+}
+
+// MarshalCBOR implements cbor.Marshaler
+func (e EncodedCBOR) MarshalCBOR() ([]byte, error) {
+	return encMode.Marshal(cbor.Tag{Number: tagEncodedCBOR, Content: []byte(e)})
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler
+func (e *EncodedCBOR) UnmarshalCBOR(data []byte) error {
+	var tag cbor.RawTag
+	if err := cbor.Unmarshal(data, &tag); err != nil || tag.Number != tagEncodedCBOR {
+		return ErrInvalidEncodedCBOR
+	}
+
+	var b []byte
+	if err := cbor.Unmarshal(tag.Content, &b); err != nil {
+		return ErrInvalidEncodedCBOR
+	}
+	*e = b
+
+	return nil
+}
+
+// Decode decodes the embedded data item into v
+func (e EncodedCBOR) Decode(v any) error {
+	return cbor.Unmarshal(e, v)
+}
+
+// ItemsRequest is the request for data elements of one document type, ISO/IEC 18013-5 section 8.3.2.1.2.1.
+// NameSpaces maps each name space to the requested data elements and whether the reader intends to retain them.
+type ItemsRequest struct {
+	DocType     string                     `cbor:"docType"`
+	NameSpaces  map[string]map[string]bool `cbor:"nameSpaces"`
+	RequestInfo map[string]any             `cbor:"requestInfo,omitempty"`
+}
+
+// DocRequest is the request for one document, ReaderAuth is a COSE_Sign1 message if the reader authenticates
+type DocRequest struct {
+	ItemsRequest EncodedCBOR     `cbor:"itemsRequest"`
+	ReaderAuth   cbor.RawMessage `cbor:"readerAuth,omitempty"`
+}
+
+// DeviceRequest is the request of a reader to the mdoc
+type DeviceRequest struct {
+	Version     string        `cbor:"version"`
+	DocRequests []*DocRequest `cbor:"docRequests"`
+}
+
+// Items decodes the items request of the document request
+func (d *DocRequest) Items() (*ItemsRequest, error) {
+	items := &ItemsRequest{}
+	if err := d.ItemsRequest.Decode(items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
@@ -0,0 +1,116 @@
+package mdoc
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"errors"
+	"vc/pkg/cose"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// deviceAuthenticationContext is the first element of the DeviceAuthentication array
+const deviceAuthenticationContext = "DeviceAuthentication"
+
+// ErrNoDeviceSignature is returned when the device authentication carries no device signature
+var ErrNoDeviceSignature = errors.New("no device signature")
+
+// SessionTranscript binds reader and device authentication to a session, ISO/IEC 18013-5 section 9.1.5.1.
+// DeviceEngagementBytes and EReaderKeyBytes are nil, encoded as null, when the session has no proximity engagement.
+type SessionTranscript struct {
+	_                     struct{} `cbor:",toarray"`
+	DeviceEngagementBytes *EncodedCBOR
+	EReaderKeyBytes       *EncodedCBOR
+	Handover              any
+}
+
+// Bytes returns the encoded session transcript, as passed to ReaderAuthenticationBytes and DeviceAuthenticationBytes
+func (s *SessionTranscript) Bytes() ([]byte, error) {
+	return encMode.Marshal(s)
+}
+
+// OID4VPHandover is the handover of an OpenID4VP presentation over the internet, ISO/IEC 18013-7 annex B.4.4.
+// The hashes bind the session to the verifier client_id and response_uri with the nonce the mdoc generated, the apu of
+// the encrypted response.
+type OID4VPHandover struct {
+	_               struct{} `cbor:",toarray"`
+	ClientIDHash    []byte
+	ResponseURIHash []byte
+	Nonce           string
+}
+
+// NewOID4VPHandover returns the handover of the OpenID4VP request of clientID and responseURI with nonce
+func NewOID4VPHandover(clientID, responseURI, nonce, mdocGeneratedNonce string) (*OID4VPHandover, error) {
+	clientIDHash, err := handoverHash(clientID, mdocGeneratedNonce)
+	if err != nil {
+		return nil, err
+	}
+	responseURIHash, err := handoverHash(responseURI, mdocGeneratedNonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OID4VPHandover{
+		ClientIDHash:    clientIDHash,
+		ResponseURIHash: responseURIHash,
+		Nonce:           nonce,
+	}, nil
+}
+
+// handoverHash returns the SHA-256 of the encoded [value, mdocGeneratedNonce]
+func handoverHash(value, mdocGeneratedNonce string) ([]byte, error) {
+	b, err := encMode.Marshal([]string{value, mdocGeneratedNonce})
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(b)
+	return sum[:], nil
+}
+
+// OID4VPSessionTranscript returns the encoded session transcript of an OpenID4VP presentation, without device
+// engagement and reader key
+func OID4VPSessionTranscript(clientID, responseURI, nonce, mdocGeneratedNonce string) ([]byte, error) {
+	handover, err := NewOID4VPHandover(clientID, responseURI, nonce, mdocGeneratedNonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return (&SessionTranscript{Handover: handover}).Bytes()
+}
+
+// DeviceAuthenticationBytes returns #6.24(bstr .cbor DeviceAuthentication), the detached payload of the device
+// signature, ISO/IEC 18013-5 section 9.1.3.4. deviceNameSpaces is the encoded DeviceNameSpaces of the document.
+func DeviceAuthenticationBytes(sessionTranscript []byte, docType string, deviceNameSpaces EncodedCBOR) ([]byte, error) {
+	deviceAuthentication, err := NewEncodedCBOR([]any{
+		deviceAuthenticationContext,
+		cbor.RawMessage(sessionTranscript),
+		docType,
+		deviceNameSpaces,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return encMode.Marshal(deviceAuthentication)
+}
+
+// VerifyDeviceSignature verifies the COSE_Sign1 device signature of a document over sessionTranscript with deviceKey,
+// the key of the device key info in the mobile security object
+func VerifyDeviceSignature(deviceSignature []byte, deviceKey crypto.PublicKey, sessionTranscript []byte, docType string, deviceNameSpaces EncodedCBOR) error {
+	if len(deviceSignature) == 0 {
+		return ErrNoDeviceSignature
+	}
+
+	msg, err := cose.ParseSign1(deviceSignature)
+	if err != nil {
+		return err
+	}
+
+	payload, err := DeviceAuthenticationBytes(sessionTranscript, docType, deviceNameSpaces)
+	if err != nil {
+		return err
+	}
+
+	return msg.VerifyDetached(deviceKey, payload)
+}
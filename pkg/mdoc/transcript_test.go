@@ -0,0 +1,113 @@
+package mdoc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"testing"
+	"vc/pkg/cose"
+	"vc/pkg/signing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOID4VPSessionTranscript(t *testing.T) {
+	b, err := OID4VPSessionTranscript("https://verifier.sunet.se", "https://verifier.sunet.se/response", "nonce", "mdoc nonce")
+	assert.NoError(t, err)
+
+	var transcript []cbor.RawMessage
+	assert.NoError(t, cbor.Unmarshal(b, &transcript))
+	assert.Len(t, transcript, 3)
+	assert.Equal(t, cbor.RawMessage{0xf6}, transcript[0], "device engagement is null")
+	assert.Equal(t, cbor.RawMessage{0xf6}, transcript[1], "reader key is null")
+
+	var handover []any
+	assert.NoError(t, cbor.Unmarshal(transcript[2], &handover))
+	assert.Len(t, handover, 3)
+
+	clientID, err := cbor.Marshal([]string{"https://verifier.sunet.se", "mdoc nonce"})
+	assert.NoError(t, err)
+	clientIDHash := sha256.Sum256(clientID)
+	assert.Equal(t, clientIDHash[:], handover[0])
+	assert.Equal(t, "nonce", handover[2])
+
+	other, err := OID4VPSessionTranscript("https://other.example.com", "https://verifier.sunet.se/response", "nonce", "mdoc nonce")
+	assert.NoError(t, err)
+	assert.NotEqual(t, b, other, "the transcript is bound to the client_id")
+}
+
+func TestVerifyDeviceSignature(t *testing.T) {
+	deviceKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	signer, err := signing.NewSoftware(deviceKey)
+	assert.NoError(t, err)
+
+	deviceNameSpaces, err := NewEncodedCBOR(map[string]any{})
+	assert.NoError(t, err)
+
+	transcript := mockSessionTranscript(t, "nonce")
+	payload, err := DeviceAuthenticationBytes(transcript, DocTypeMDL, deviceNameSpaces)
+	assert.NoError(t, err)
+	deviceSignature, err := cose.SignSign1Detached(signer, nil, payload)
+	assert.NoError(t, err)
+
+	tts := []struct {
+		name            string
+		deviceSignature []byte
+		deviceKey       *ecdsa.PublicKey
+		transcript      []byte
+		docType         string
+		wantErr         error
+	}{
+		{
+			name:            "valid",
+			deviceSignature: deviceSignature,
+			deviceKey:       &deviceKey.PublicKey,
+			transcript:      transcript,
+			docType:         DocTypeMDL,
+		},
+		{
+			name:            "other session",
+			deviceSignature: deviceSignature,
+			deviceKey:       &deviceKey.PublicKey,
+			transcript:      mockSessionTranscript(t, "other nonce"),
+			docType:         DocTypeMDL,
+			wantErr:         cose.ErrInvalidSignature,
+		},
+		{
+			name:            "other doc type",
+			deviceSignature: deviceSignature,
+			deviceKey:       &deviceKey.PublicKey,
+			transcript:      transcript,
+			docType:         "eu.europa.ec.eudi.pid.1",
+			wantErr:         cose.ErrInvalidSignature,
+		},
+		{
+			name:            "other device key",
+			deviceSignature: deviceSignature,
+			deviceKey:       &otherKey.PublicKey,
+			transcript:      transcript,
+			docType:         DocTypeMDL,
+			wantErr:         cose.ErrInvalidSignature,
+		},
+		{
+			name:       "no device signature",
+			deviceKey:  &deviceKey.PublicKey,
+			transcript: transcript,
+			docType:    DocTypeMDL,
+			wantErr:    ErrNoDeviceSignature,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyDeviceSignature(tt.deviceSignature, tt.deviceKey, tt.transcript, tt.docType, deviceNameSpaces)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
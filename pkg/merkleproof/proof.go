@@ -0,0 +1,108 @@
+package merkleproof
+
+import (
+	"crypto/ecdsa"
+	"encoding/base64"
+	"errors"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/wealdtech/go-merkletree"
+)
+
+var (
+	// ErrInvalidTreeHead is returned when the signed tree head can not be verified
+	ErrInvalidTreeHead = errors.New("invalid signed tree head")
+
+	// ErrNotIncluded is returned when the audit path does not lead to the signed root hash
+	ErrNotIncluded = errors.New("entity is not included in the tree")
+
+	// ErrLeafIndexOutOfRange is returned when the leaf index is outside the signed tree
+	ErrLeafIndexOutOfRange = errors.New("leaf index out of range")
+)
+
+// TreeHead is the claims set of a signed tree head
+type TreeHead struct {
+	TreeSize uint64 `json:"tree_size"`
+	RootHash string `json:"root_hash"`
+	jwt.RegisteredClaims
+}
+
+// InclusionProof proves that an entity is a leaf of the registry tree
+type InclusionProof struct {
+	LeafIndex      uint64   `json:"leaf_index"`
+	AuditPath      []string `json:"audit_path"`
+	SignedTreeHead string   `json:"signed_tree_head"`
+}
+
+// NewInclusionProof creates an inclusion proof from a merkle tree proof and a signed tree head
+func NewInclusionProof(proof *merkletree.Proof, signedTreeHead string) *InclusionProof {
+	auditPath := make([]string, 0, len(proof.Hashes))
+	for _, hash := range proof.Hashes {
+		auditPath = append(auditPath, base64.StdEncoding.EncodeToString(hash))
+	}
+
+	return &InclusionProof{
+		LeafIndex:      proof.Index,
+		AuditPath:      auditPath,
+		SignedTreeHead: signedTreeHead,
+	}
+}
+
+// SignTreeHead signs a tree head with an ES256 key
+func SignTreeHead(key *ecdsa.PrivateKey, treeHead *TreeHead) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, treeHead)
+	token.Header["typ"] = "sth+jwt"
+
+	return token.SignedString(key)
+}
+
+// ParseTreeHead verifies the signed tree head with the registry public key and returns its claims
+func ParseTreeHead(signedTreeHead string, publicKey *ecdsa.PublicKey) (*TreeHead, error) {
+	treeHead := &TreeHead{}
+	_, err := jwt.ParseWithClaims(signedTreeHead, treeHead, func(token *jwt.Token) (any, error) {
+		return publicKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodES256.Alg()}))
+	if err != nil {
+		return nil, errors.Join(ErrInvalidTreeHead, err)
+	}
+
+	return treeHead, nil
+}
+
+// Verify verifies offline that entity is included in the tree signed by the registry public key
+func (p *InclusionProof) Verify(entity string, publicKey *ecdsa.PublicKey) error {
+	treeHead, err := ParseTreeHead(p.SignedTreeHead, publicKey)
+	if err != nil {
+		return err
+	}
+
+	if p.LeafIndex >= treeHead.TreeSize {
+		return ErrLeafIndexOutOfRange
+	}
+
+	root, err := base64.StdEncoding.DecodeString(treeHead.RootHash)
+	if err != nil {
+		return errors.Join(ErrInvalidTreeHead, err)
+	}
+
+	proof := &merkletree.Proof{
+		Index: p.LeafIndex,
+	}
+	for _, hash := range p.AuditPath {
+		h, err := base64.StdEncoding.DecodeString(hash)
+		if err != nil {
+			return err
+		}
+		proof.Hashes = append(proof.Hashes, h)
+	}
+
+	ok, err := merkletree.VerifyProof([]byte(entity), proof, root)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNotIncluded
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package merkleproof
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wealdtech/go-merkletree"
+)
+
+func mockTree(t *testing.T) (*merkletree.MerkleTree, [][]byte) {
+	data := [][]byte{
+		[]byte("entity-1"),
+		[]byte("entity-2"),
+		[]byte("entity-3"),
+		[]byte("entity-4"),
+		[]byte("entity-5"),
+	}
+	tree, err := merkletree.New(data)
+	assert.NoError(t, err)
+
+	return tree, data
+}
+
+func TestVerify(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	tree, data := mockTree(t)
+
+	signedTreeHead, err := SignTreeHead(key, &TreeHead{
+		TreeSize: uint64(len(data)),
+		RootHash: base64.StdEncoding.EncodeToString(tree.Root()),
+	})
+	assert.NoError(t, err)
+
+	tts := []struct {
+		name      string
+		leaf      string
+		entity    string
+		publicKey *ecdsa.PublicKey
+		wantErr   error
+	}{
+		{
+			name:      "included",
+			leaf:      "entity-3",
+			entity:    "entity-3",
+			publicKey: &key.PublicKey,
+		},
+		{
+			name:      "other entity",
+			leaf:      "entity-3",
+			entity:    "entity-4",
+			publicKey: &key.PublicKey,
+			wantErr:   ErrNotIncluded,
+		},
+		{
+			name:      "wrong key",
+			leaf:      "entity-3",
+			entity:    "entity-3",
+			publicKey: &otherKey.PublicKey,
+			wantErr:   ErrInvalidTreeHead,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			proof, err := tree.GenerateProof([]byte(tt.leaf))
+			assert.NoError(t, err)
+
+			err = NewInclusionProof(proof, signedTreeHead).Verify(tt.entity, tt.publicKey)
+			assert.True(t, errors.Is(err, tt.wantErr), "got %v", err)
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package kafka
+
+import (
+	"context"
+	"sync"
+	"vc/pkg/logger"
+	"vc/pkg/model"
+
+	"github.com/IBM/sarama"
+)
+
+// AdminClient is a long lived client for cluster metadata and consumer group offsets. It connects on first use, so a
+// service starts while the brokers are unreachable, and reconnects on the next call after a failed connect.
+type AdminClient struct {
+	cfg *model.Cfg
+	log *logger.Log
+
+	mu     sync.Mutex
+	client sarama.Client
+	admin  sarama.ClusterAdmin
+}
+
+// NewAdminClient creates an admin client for the brokers of cfg
+func NewAdminClient(ctx context.Context, cfg *model.Cfg, log *logger.Log) *AdminClient {
+	return &AdminClient{
+		cfg: cfg,
+		log: log.New("kafka_admin"),
+	}
+}
+
+// connect returns the client and cluster admin, connecting them if needed
+func (a *AdminClient) connect() (sarama.Client, sarama.ClusterAdmin, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.client != nil {
+		return a.client, a.admin, nil
+	}
+
+	saramaConfig := commonConsumerConfig(a.cfg)
+	// health probes must fail fast, the next call retries
+	saramaConfig.Metadata.Retry.Max = 0
+
+	client, err := sarama.NewClient(a.cfg.Common.Kafka.Brokers, saramaConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	admin, err := sarama.NewClusterAdminFromClient(client)
+	if err != nil {
+		client.Close()
+		return nil, nil, err
+	}
+
+	a.client, a.admin = client, admin
+
+	return a.client, a.admin, nil
+}
+
+// Ping checks that the brokers are reachable by refreshing the cluster metadata, it returns when ctx is done
+func (a *AdminClient) Ping(ctx context.Context) error {
+	done := make(chan error, 1)
+	go func() {
+		client, _, err := a.connect()
+		if err != nil {
+			done <- err
+			return
+		}
+		done <- client.RefreshMetadata()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Lag returns the number of messages in topic not yet committed by the consumer group
+func (a *AdminClient) Lag(group, topic string) (int64, error) {
+	client, admin, err := a.connect()
+	if err != nil {
+		return 0, err
+	}
+
+	partitions, err := client.Partitions(topic)
+	if err != nil {
+		return 0, err
+	}
+
+	offsets, err := admin.ListConsumerGroupOffsets(group, map[string][]int32{topic: partitions})
+	if err != nil {
+		return 0, err
+	}
+
+	var lag int64
+	for _, partition := range partitions {
+		newest, err := client.GetOffset(topic, partition, sarama.OffsetNewest)
+		if err != nil {
+			return 0, err
+		}
+
+		committed := int64(-1)
+		if block := offsets.GetBlock(topic, partition); block != nil {
+			committed = block.Offset
+		}
+		if committed < 0 {
+			// nothing committed yet, the group starts from the oldest offset
+			committed, err = client.GetOffset(topic, partition, sarama.OffsetOldest)
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		if newest > committed {
+			lag += newest - committed
+		}
+	}
+
+	return lag, nil
+}
+
+// Close closes the admin client, closing the cluster admin closes the client as well
+func (a *AdminClient) Close(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.admin != nil {
+		if err := a.admin.Close(); err != nil {
+			return err
+		}
+		a.client, a.admin = nil, nil
+	}
+
+	a.log.Info("Stopped")
+	return nil
+}
@@ -11,12 +11,19 @@ import (
 	"vc/pkg/model"
 
 	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 )
 
 const (
-	TopicMockNext              = "topic_mock_next"
-	TopicUpload                = "topic_upload"
-	TypeOfStructInMessageValue = "type_of_struct_in_value"
+	TopicMockNext               = "topic_mock_next"
+	TopicUpload                 = "topic_upload"
+	TopicDocumentExpired        = "topic_document_expired"
+	TopicCredentialNotification = "topic_credential_notification"
+	TypeOfStructInMessageValue  = "type_of_struct_in_value"
+
+	ConsumerGroupUploadAPIGW = "topic_upload_consumer_group_apigw"
 )
 
 // HandlerConfig struct to define the Kafka topic and consumer group for a specific message handler
@@ -56,18 +63,33 @@ func commonConsumerConfig(cfg *model.Cfg) *sarama.Config {
 	//TODO(mk): set cfg from file - is now hardcoded
 	saramaConfig := sarama.NewConfig()
 	saramaConfig.Consumer.Offsets.Initial = sarama.OffsetOldest
-	saramaConfig.Consumer.Group.Rebalance.GroupStrategies = []sarama.BalanceStrategy{sarama.NewBalanceStrategyRange()}
+	saramaConfig.Consumer.Group.Rebalance.GroupStrategies = []sarama.BalanceStrategy{balanceStrategy(cfg.Common.Kafka.RebalanceStrategy)}
 	saramaConfig.Net.SASL.Enable = false
 	//TODO(mk): enable and configure security when consuming from Kafka
 	return saramaConfig
 }
 
+// balanceStrategy returns the consumer group rebalance strategy by name, sticky keeps partitions on their current member when possible
+func balanceStrategy(name string) sarama.BalanceStrategy {
+	switch name {
+	case "range":
+		return sarama.NewBalanceStrategyRange()
+	case "roundrobin":
+		return sarama.NewBalanceStrategyRoundRobin()
+	default:
+		return sarama.NewBalanceStrategySticky()
+	}
+}
+
 // Start starts the actual event consuming from specified kafka topics
 func (c *MessageConsumerClient) Start(ctx context.Context, handlerFactory func(string) sarama.ConsumerGroupHandler, handlerConfigs []HandlerConfig) error {
 	if err := c.SaramaConfig.Validate(); err != nil {
 		return err
 	}
 
+	var cancelCtx context.Context
+	cancelCtx, c.cancel = context.WithCancel(ctx)
+
 	for _, handlerConfig := range handlerConfigs {
 		consumerGroup, err := sarama.NewConsumerGroup(c.brokers, handlerConfig.ConsumerGroup, c.SaramaConfig)
 		if err != nil {
@@ -76,16 +98,15 @@ func (c *MessageConsumerClient) Start(ctx context.Context, handlerFactory func(s
 		}
 		c.log.Info("Started consumer group", "group", handlerConfig.ConsumerGroup)
 
-		var cancelCtx context.Context
-		cancelCtx, c.cancel = context.WithCancel(ctx)
-
 		c.wg.Add(1)
-		go func(group sarama.ConsumerGroup, topic string) {
+		go func(group sarama.ConsumerGroup, topic, groupName string) {
 			defer c.wg.Done()
+			defer group.Close()
+			// Consume returns on every rebalance, and is called again to rejoin the group with the new assignment
 			for {
 				handler := handlerFactory(topic)
 				if err := group.Consume(cancelCtx, []string{topic}, handler); err != nil {
-					c.log.Error(err, "Error on consumer group", "group", handlerConfig.ConsumerGroup)
+					c.log.Error(err, "Error on consumer group", "group", groupName)
 					//TODO(mk): use more advanced backoff algorithm?
 					time.Sleep(1 * time.Second)
 				}
@@ -94,7 +115,7 @@ func (c *MessageConsumerClient) Start(ctx context.Context, handlerFactory func(s
 					return
 				}
 			}
-		}(consumerGroup, handlerConfig.Topic)
+		}(consumerGroup, handlerConfig.Topic, handlerConfig.ConsumerGroup)
 	}
 	return nil
 }
@@ -118,8 +139,17 @@ type ConsumerGroupHandler struct {
 	Log      *logger.Log
 }
 
-func (cgh *ConsumerGroupHandler) Setup(_ sarama.ConsumerGroupSession) error   { return nil }
-func (cgh *ConsumerGroupHandler) Cleanup(_ sarama.ConsumerGroupSession) error { return nil }
+// Setup is run at the start of a consumer group session, after a rebalance
+func (cgh *ConsumerGroupHandler) Setup(session sarama.ConsumerGroupSession) error {
+	cgh.Log.Info("Partitions assigned", "member_id", session.MemberID(), "generation_id", session.GenerationID(), "claims", session.Claims())
+	return nil
+}
+
+// Cleanup is run at the end of a consumer group session, before a rebalance
+func (cgh *ConsumerGroupHandler) Cleanup(session sarama.ConsumerGroupSession) error {
+	cgh.Log.Info("Partitions revoked", "member_id", session.MemberID(), "generation_id", session.GenerationID())
+	return nil
+}
 
 func (cgh *ConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
 	if cgh.Handlers == nil {
@@ -137,6 +167,16 @@ func (cgh *ConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSessio
 
 	handlerType := reflect.TypeOf(handler).String()
 
+	meter := otel.Meter("vc/kafka")
+	consumedCounter, err := meter.Int64Counter("kafka.consumer.messages", metric.WithDescription("Number of consumed messages"))
+	if err != nil {
+		return err
+	}
+	lagGauge, err := meter.Int64Gauge("kafka.consumer.lag", metric.WithDescription("Number of messages left to consume in the partition"))
+	if err != nil {
+		return err
+	}
+
 	for message := range claim.Messages() {
 		var errMessage string
 
@@ -157,6 +197,10 @@ func (cgh *ConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSessio
 		}
 		cgh.Log.Debug("Consumed message", "info", info)
 
+		attrs := metric.WithAttributes(attribute.String("topic", claim.Topic()), attribute.Int("partition", int(message.Partition)))
+		consumedCounter.Add(session.Context(), 1, attrs, metric.WithAttributes(attribute.Bool("error", errMessage != "")))
+		lagGauge.Record(session.Context(), claim.HighWaterMarkOffset()-message.Offset-1, attrs)
+
 		session.MarkMessage(message, info)
 	}
 	return nil
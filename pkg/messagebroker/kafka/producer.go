@@ -55,6 +55,8 @@ func CommonProducerConfig(cfg *model.Cfg) *sarama.Config {
 	saramaConfig.Producer.Idempotent = true
 	saramaConfig.Net.MaxOpenRequests = 1
 	saramaConfig.Producer.Retry.Max = 3
+	// messages are keyed by document_id, hashing keeps all events of a document in one partition and in order
+	saramaConfig.Producer.Partitioner = sarama.NewHashPartitioner
 	saramaConfig.Net.SASL.Enable = false
 	//TODO(mk): enable and configure security when publishing to Kafka
 	return saramaConfig
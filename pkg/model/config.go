@@ -10,6 +10,43 @@ type APIServer struct {
 	PublicKeys map[string]string `yaml:"public_keys"`
 	TLS        TLS               `yaml:"tls" validate:"omitempty"`
 	BasicAuth  BasicAuth         `yaml:"basic_auth"`
+	Auth       Authentication    `yaml:"auth"`
+	RateLimit  RateLimit         `yaml:"rate_limit"`
+
+	// MaxRequestBodySize is the maximum request body size in bytes, 0 means no limit
+	MaxRequestBodySize int64 `yaml:"max_request_body_size" default:"10485760"`
+
+	// Shutdown holds the graceful shutdown configuration
+	Shutdown Shutdown `yaml:"shutdown"`
+
+	// HealthCheck holds the readiness probe configuration
+	HealthCheck HealthCheck `yaml:"health_check"`
+}
+
+// HealthCheck holds the configuration of the dependency checks of the readiness probe, /health/ready
+type HealthCheck struct {
+	// Timeout is the time in seconds each dependency check is given
+	Timeout int64 `yaml:"timeout" default:"2"`
+}
+
+// Shutdown holds the graceful shutdown configuration of an API server
+type Shutdown struct {
+	// DrainDelay is the time in seconds the readiness probe responds 503 before the server stops accepting connections, to let load balancers catch up
+	DrainDelay int64 `yaml:"drain_delay" default:"5"`
+
+	// Timeout is the time in seconds in-flight requests are given to finish
+	Timeout int64 `yaml:"timeout" default:"30"`
+}
+
+// RateLimit holds the per client rate limit configuration, clients are identified by authentic source once authenticated, else by IP address
+type RateLimit struct {
+	Enabled bool `yaml:"enabled"`
+
+	// RequestsPerSecond is the sustained number of requests a client may make
+	RequestsPerSecond float64 `yaml:"requests_per_second" default:"10"`
+
+	// Burst is the number of requests a client may make at once
+	Burst int `yaml:"burst" default:"20"`
 }
 
 // TLS holds the tls configuration
@@ -17,17 +54,27 @@ type TLS struct {
 	Enabled      bool   `yaml:"enabled"`
 	CertFilePath string `yaml:"cert_file_path" validate:"required"`
 	KeyFilePath  string `yaml:"key_file_path" validate:"required"`
+
+	// ClientCAFilePath to a PEM bundle of CAs, if set client certificates are requested and verified against it
+	ClientCAFilePath string `yaml:"client_ca_file_path"`
 }
 
 // Mongo holds the database configuration
 type Mongo struct {
 	URI string `yaml:"uri" validate:"required"`
+
+	// Transactions runs related writes in multi-document transactions, one of auto, enabled or disabled. auto enables
+	// them when MongoDB runs as a replica set or sharded cluster.
+	Transactions string `yaml:"transactions" default:"auto" validate:"omitempty,oneof=auto enabled disabled"`
 }
 
 // Kafka holds the kafka configuration that is common for the entire system
 type Kafka struct {
 	Enabled bool     `yaml:"enabled"`
 	Brokers []string `yaml:"brokers"`
+
+	// RebalanceStrategy assigns partitions to consumer group members, one of range, roundrobin or sticky
+	RebalanceStrategy string `yaml:"rebalance_strategy" default:"sticky" validate:"omitempty,oneof=range roundrobin sticky"`
 }
 
 // KeyValue holds the key/value configuration
@@ -42,6 +89,24 @@ type KeyValue struct {
 type Log struct {
 	Level      string `yaml:"level"`
 	FolderPath string `yaml:"folder_path"`
+
+	// Format is console or json, defaults to json in production and console otherwise
+	Format string `yaml:"format" validate:"omitempty,oneof=console json"`
+
+	// Levels sets the level, error, info, debug or trace, of named loggers, e.g. "apigw.httpserver": debug. Reloaded on SIGHUP.
+	Levels map[string]string `yaml:"levels"`
+
+	Redaction LogRedaction `yaml:"redaction"`
+}
+
+// LogRedaction holds the personal data field names masked in log output, on top of logger.DefaultRedactedFields.
+// Reloaded on SIGHUP.
+type LogRedaction struct {
+	// Fields are additional JSON field names to mask, e.g. "email"
+	Fields []string `yaml:"fields"`
+
+	// Allow are field names logged in clear, for debug environments only, not allowed in production
+	Allow []string `yaml:"allow"`
 }
 
 // Common holds the common configuration
@@ -55,18 +120,51 @@ type Common struct {
 	KeyValue   KeyValue `yaml:"key_value" validate:"omitempty"`
 	QR         QRCfg    `yaml:"qr" validate:"omitempty"`
 	Kafka      Kafka    `yaml:"kafka" validate:"required"`
+
+	// Tenants share the deployment with isolated data, every authentic source not in a tenant belongs to the default tenant
+	Tenants []Tenant `yaml:"tenants" validate:"omitempty,dive"`
+}
+
+// Tenant is a group of authentic sources with its own API credentials, apigw database and issuer signing key
+type Tenant struct {
+	// Name identifies the tenant in metrics, its apigw data is kept in the mongo database vc_<name>
+	Name string `yaml:"name" validate:"required,alphanum,max=32"`
+
+	AuthenticSources []string `yaml:"authentic_sources" validate:"required,min=1"`
+
+	// APIKeys maps a static API key, sent in the X-API-Key header, to one of the authentic sources of the tenant
+	APIKeys map[string]string `yaml:"api_keys"`
+
+	// ClientCertificates maps the hex encoded SHA-256 fingerprint of a TLS client certificate to one of the authentic sources of the tenant
+	ClientCertificates map[string]string `yaml:"client_certificates"`
+
+	// SigningKID is the kid of the issuer key the credentials of the tenant are signed with, the active key if empty
+	SigningKID string `yaml:"signing_kid"`
 }
 
 // SMT Spares Merkel Tree configuration
 type SMT struct {
 	UpdatePeriodicity int    `yaml:"update_periodicity" validate:"required"`
 	InitLeaf          string `yaml:"init_leaf" validate:"required"`
+
+	// SigningKeyPath to an ECDSA prime256v1 key in PEM format, used to sign tree heads
+	SigningKeyPath string `yaml:"signing_key_path"`
+
+	// SnapshotPath is the file the tree is saved to, so a restart only replays the leaves changed since. Empty disables
+	// snapshots and the tree is built from the whole leaf table on start.
+	SnapshotPath string `yaml:"snapshot_path"`
+
+	// SnapshotInterval is the least number of seconds between snapshots, a snapshot is also saved on shutdown
+	SnapshotInterval int `yaml:"snapshot_interval" default:"300"`
 }
 
 // GRPCServer holds the rpc configuration
 type GRPCServer struct {
 	Addr     string `yaml:"addr" validate:"required"`
 	Insecure bool   `yaml:"insecure"`
+
+	// TLS serves the apigw gRPC API over TLS, with a client CA file client certificates are verified and authenticate the client
+	TLS TLS `yaml:"tls" validate:"omitempty"`
 }
 
 // PDF holds the pdf configuration (special Ladok case)
@@ -115,17 +213,160 @@ type JWTAttribute struct {
 	// VerifiableCredentialType URL example: https://credential.sunet.se/identity_credential
 	VerifiableCredentialType string `yaml:"verifiable_credential_type" validate:"required"`
 
+	// DocumentTypeVCT overrides VerifiableCredentialType per document type, example: ELM: https://credential.sunet.se/elm
+	DocumentTypeVCT map[string]string `yaml:"document_type_vct" validate:"omitempty,dive,url"`
+
 	// Status status of the Verifiable Credential
 	Status string `yaml:"status"`
 }
 
+// VCTM holds the SD-JWT VC type metadata resolution configuration
+type VCTM struct {
+	// Enabled fetches the type metadata of the vct, the issuer adds vct#integrity and the verifier checks it
+	Enabled bool `yaml:"enabled"`
+
+	// AllowList holds the vct URL prefixes metadata may be fetched from, if empty any https vct is allowed
+	AllowList []string `yaml:"allow_list"`
+
+	// CacheTTL is the time in seconds fetched metadata is cached
+	CacheTTL int64 `yaml:"cache_ttl" default:"3600"`
+}
+
 // Issuer holds the issuer configuration
 type Issuer struct {
 	APIServer      APIServer    `yaml:"api_server" validate:"required"`
 	Identifier     string       `yaml:"identifier" validate:"required"`
 	GRPCServer     GRPCServer   `yaml:"grpc_server" validate:"required"`
-	SigningKeyPath string       `yaml:"signing_key_path" validate:"required"`
+	SigningKeyPath string       `yaml:"signing_key_path"`
+	Signing        Signing      `yaml:"signing"`
 	JWTAttribute   JWTAttribute `yaml:"jwt_attribute" validate:"required"`
+
+	// Keys are the signing keys used for key rotation, if empty signing_key_path and signing are used as the only key
+	Keys []IssuerKey `yaml:"keys" validate:"omitempty,dive"`
+
+	AuditLog AuditLog `yaml:"audit_log"`
+
+	VCTM VCTM `yaml:"vctm"`
+
+	StatusList IssuerStatusList `yaml:"status_list"`
+
+	Batch IssuerBatch `yaml:"batch"`
+}
+
+// IssuerBatch holds the configuration of batch issuance
+type IssuerBatch struct {
+	// Workers is the number of credentials of a batch made concurrently, each signs with its own HSM session when
+	// signing is pkcs11
+	Workers int `yaml:"workers" default:"8" validate:"omitempty,gt=0"`
+}
+
+// IssuerStatusList holds the configuration of the status claim in issued credentials
+type IssuerStatusList struct {
+	// Enabled allocates a registry status list index for each credential and adds it as the status claim, the
+	// uri is the token status list under registry.status_list.base_url
+	Enabled bool `yaml:"enabled"`
+}
+
+// AuditLog holds the issuer audit log configuration
+type AuditLog struct {
+	// Sinks receive every audit entry, any of webhook, syslog, jsonl and http
+	Sinks []string `yaml:"sinks" default:"[\"webhook\"]" validate:"dive,oneof=webhook syslog jsonl http"`
+
+	Syslog AuditLogSyslog `yaml:"syslog"`
+	JSONL  AuditLogJSONL  `yaml:"jsonl"`
+	HTTP   AuditLogHTTP   `yaml:"http"`
+}
+
+// AuditLogSyslog holds the RFC 5424 syslog sink configuration
+type AuditLogSyslog struct {
+	// Network is udp or tcp, tcp uses octet counting framing
+	Network string `yaml:"network" default:"udp" validate:"oneof=udp tcp"`
+	Addr    string `yaml:"addr"`
+	AppName string `yaml:"app_name" default:"vc-issuer"`
+}
+
+// AuditLogJSONL holds the JSON Lines file sink configuration, the files are also the source of the export API
+type AuditLogJSONL struct {
+	Path string `yaml:"path"`
+
+	// MaxSize in bytes before the file is rotated
+	MaxSize int64 `yaml:"max_size" default:"104857600"`
+
+	// MaxFiles is the number of rotated files kept
+	MaxFiles int `yaml:"max_files" default:"10"`
+}
+
+// AuditLogHTTP holds the HTTP push sink configuration
+type AuditLogHTTP struct {
+	URL string `yaml:"url" validate:"omitempty,url"`
+}
+
+// IssuerKey is a signing key with a validity window
+type IssuerKey struct {
+	// KID is the key id published in the JWKS and set in the header of issued credentials
+	KID string `yaml:"kid" validate:"required"`
+
+	// SigningKeyPath to an ECDSA prime256v1 key in PEM format, used when signing.type is software
+	SigningKeyPath string `yaml:"signing_key_path"`
+
+	Signing Signing `yaml:"signing"`
+
+	// NotBefore in RFC 3339 format, the key is not used for signing before this time
+	NotBefore string `yaml:"not_before" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+
+	// NotAfter in RFC 3339 format, the key is not used for signing after this time but stays in the JWKS
+	NotAfter string `yaml:"not_after" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+// Signing holds the configuration of the key used to sign credentials
+type Signing struct {
+	// Type is one of software, pkcs11, awskms or cloudkms, software reads the key from signing_key_path
+	Type string `yaml:"type" default:"software"`
+
+	// ModulePath to the PKCS#11 module, example: /usr/lib/softhsm/libsofthsm2.so
+	ModulePath string `yaml:"module_path"`
+
+	// TokenLabel of the PKCS#11 token, mutually exclusive with slot
+	TokenLabel string `yaml:"token_label"`
+
+	// Slot id of the PKCS#11 token
+	Slot string `yaml:"slot"`
+
+	// Pin to log in to the PKCS#11 token
+	Pin string `yaml:"pin"`
+
+	// KeyLabel of the PKCS#11 key object
+	KeyLabel string `yaml:"key_label"`
+
+	// KeyID of the AWS KMS key, or resource name of the GCP KMS key version
+	KeyID string `yaml:"key_id"`
+
+	// Region of the AWS KMS key
+	Region string `yaml:"region"`
+
+	// CredentialsFile for AWS KMS or GCP KMS, the default credential chain is used if empty
+	CredentialsFile string `yaml:"credentials_file"`
+}
+
+// StatusList holds the status list configuration
+type StatusList struct {
+	// BaseURL is the public URL where status lists are published, example: https://registry.sunet.se/statuslists
+	BaseURL string `yaml:"base_url"`
+
+	// Issuer of the status list credentials, example: https://registry.sunet.se
+	Issuer string `yaml:"issuer"`
+
+	// SigningKeyPath to an ECDSA prime256v1 key in PEM format, used to sign status lists
+	SigningKeyPath string `yaml:"signing_key_path"`
+
+	// Size is the number of entries aggregated in each status list, never less than 131072
+	Size int `yaml:"size" default:"131072"`
+
+	// Bits is the number of bits per entry in token status lists, 1 bit can not express suspension
+	Bits int `yaml:"bits" default:"2" validate:"omitempty,oneof=1 2 4 8"`
+
+	// TTL is the number of seconds a published status list may be cached
+	TTL int64 `yaml:"ttl" default:"300"`
 }
 
 // Registry holds the registry configuration
@@ -133,23 +374,181 @@ type Registry struct {
 	APIServer  APIServer  `yaml:"api_server" validate:"required"`
 	SMT        SMT        `yaml:"smt" validate:"required"`
 	GRPCServer GRPCServer `yaml:"grpc_server" validate:"required"`
+	StatusList StatusList `yaml:"status_list" validate:"omitempty"`
+
+	// DBPath is the path of the sqlite database holding the tree leaves, status list entries and status events
+	DBPath string `yaml:"db_path" default:"/tmp/test.db"`
+}
+
+// Retention holds the document retention configuration
+type Retention struct {
+	// Enabled turns on the sweeper that purges documents whose credential_valid_to has passed
+	Enabled bool `yaml:"enabled"`
+
+	// Interval is the number of seconds between sweeps
+	Interval int `yaml:"interval" default:"3600"`
+
+	// GracePeriod is the number of seconds a document is kept after it expired
+	GracePeriod int64 `yaml:"grace_period" default:"0"`
+
+	// BatchSize is the maximum number of documents purged in one sweep
+	BatchSize int64 `yaml:"batch_size" default:"1000"`
 }
 
 // Persistent holds the persistent storage configuration
 type Persistent struct {
 	APIServer APIServer `yaml:"api_server" validate:"required"`
+	Retention Retention `yaml:"retention" validate:"omitempty"`
 }
 
 // MockAS holds the mock as configuration
 type MockAS struct {
 	APIServer    APIServer `yaml:"api_server" validate:"required"`
 	DatastoreURL string    `yaml:"datastore_url" validate:"required"`
+
+	// Seed makes the generated mock data deterministic, a random seed is used if zero
+	Seed int64 `yaml:"seed"`
+
+	Wallet MockASWallet `yaml:"wallet"`
+}
+
+// MockASWallet holds the wallet simulator configuration
+type MockASWallet struct {
+	// Enabled adds the wallet endpoints, which collect credentials from apigw at datastore_url and present them to a
+	// verifier session
+	Enabled bool `yaml:"enabled"`
 }
 
 // Verifier holds the verifier configuration
 type Verifier struct {
 	APIServer  APIServer  `yaml:"api_server" validate:"required"`
 	GRPCServer GRPCServer `yaml:"grpc_server" validate:"required"`
+
+	// IssuerJWKSURL is where the issuer publishes its signing keys, example: http://vc_dev_issuer:8080/.well-known/jwks.json
+	IssuerJWKSURL string `yaml:"issuer_jwks_url"`
+
+	// ExternalURL is the public base url of the verifier, used in request uris, example: https://verifier.sunet.se
+	ExternalURL string `yaml:"external_url"`
+
+	// ClientID identifies the verifier in presentation requests and is the audience of key binding JWTs, external_url if empty
+	ClientID string `yaml:"client_id"`
+
+	Session VerifierSession `yaml:"session"`
+
+	VCTM VCTM `yaml:"vctm"`
+
+	Trust VerifierTrust `yaml:"trust"`
+
+	Federation VerifierFederation `yaml:"federation"`
+
+	WalletAttestation VerifierWalletAttestation `yaml:"wallet_attestation"`
+
+	StatusList VerifierStatusList `yaml:"status_list"`
+
+	Analytics VerifierAnalytics `yaml:"analytics"`
+
+	Policy VerifierPolicy `yaml:"policy"`
+
+	SDJWT VerifierSDJWT `yaml:"sd_jwt"`
+}
+
+// VerifierSDJWT holds the SD-JWT verification configuration
+type VerifierSDJWT struct {
+	// MinSaltBits is the least number of bits a disclosure salt encodes, 6 per base64url character, credentials with a
+	// shorter salt are not valid. It is a minimum length policy, the entropy of a salt can not be told from its encoding.
+	MinSaltBits int `yaml:"min_salt_bits" default:"128" validate:"gte=0"`
+
+	// RequireKeyBinding rejects credentials without cnf claim. A credential bound to a holder key must always be
+	// presented with a key binding JWT, one issued without proof of possession has no key to bind the presentation to.
+	RequireKeyBinding bool `yaml:"require_key_binding"`
+}
+
+// VerifierPolicy holds the verification policy configuration
+type VerifierPolicy struct {
+	// Paths are YAML or JSON policy documents, one policy in each
+	Paths []string `yaml:"paths"`
+
+	// Default is the policy applied when a verification names none, no policy is applied if empty
+	Default string `yaml:"default"`
+}
+
+// VerifierWalletAttestation holds the wallet instance attestation configuration
+type VerifierWalletAttestation struct {
+	// Enabled validates wallet attestations sent along with the vp_token
+	Enabled bool `yaml:"enabled"`
+
+	// Required fails presentations without a valid wallet attestation
+	Required bool `yaml:"required"`
+
+	// TrustAnchors is a PEM file or a directory of PEM files with the wallet provider certificates
+	TrustAnchors string `yaml:"trust_anchors" validate:"required_if=Enabled true"`
+}
+
+// VerifierStatusList holds the credential status check configuration
+type VerifierStatusList struct {
+	// Enabled fetches the status list referenced by the status claim of a credential, revoked and suspended
+	// credentials are not valid
+	Enabled bool `yaml:"enabled"`
+
+	// PublicKeyPath to the ECDSA public key in PEM format the registry signs its token status list with
+	PublicKeyPath string `yaml:"public_key_path" validate:"required_if=Enabled true"`
+}
+
+// VerifierAnalytics holds the verification analytics configuration
+type VerifierAnalytics struct {
+	// Enabled stores the outcome of each verification in MongoDB at common.mongo.uri and adds the analytics and audit
+	// endpoints, they require api_server basic_auth or auth
+	Enabled bool `yaml:"enabled"`
+
+	// Retention is the number of seconds verification records are kept
+	Retention int64 `yaml:"retention" default:"7776000" validate:"omitempty,gt=0"`
+}
+
+// VerifierTrust holds the ETSI TS 119 612 trusted list configuration
+type VerifierTrust struct {
+	// Enabled only accepts credentials whose issuer key is listed with a granted status in a trusted list
+	Enabled bool `yaml:"enabled"`
+
+	// LOTLURL is the list of trusted lists, example: https://ec.europa.eu/tools/lotl/eu-lotl.xml
+	LOTLURL string `yaml:"lotl_url" validate:"required_if=Enabled true"`
+
+	// LOTLSigningCertificates are PEM files with the certificates the list of trusted lists may be signed with
+	LOTLSigningCertificates []string `yaml:"lotl_signing_certificates" validate:"required_if=Enabled true"`
+
+	// RefreshInterval is the number of seconds between downloads of the trusted lists
+	RefreshInterval int `yaml:"refresh_interval" default:"86400"`
+}
+
+// VerifierFederation holds the OpenID Federation configuration credential issuers are authenticated with
+type VerifierFederation struct {
+	// Enabled resolves the trust chain of the iss of a credential, the issuer keys are taken from its
+	// openid_credential_issuer metadata instead of issuer_jwks_url
+	Enabled bool `yaml:"enabled"`
+
+	// TrustAnchors are the trust anchors a trust chain of an issuer may end in
+	TrustAnchors []TrustAnchor `yaml:"trust_anchors" validate:"required_if=Enabled true,dive"`
+
+	// RequiredTrustMarks are the trust mark ids an issuer must have, example: https://dc4eu.eu/trust-marks/pid-provider
+	RequiredTrustMarks []string `yaml:"required_trust_marks"`
+}
+
+// VerifierSession holds the presentation session configuration
+type VerifierSession struct {
+	// TTL is the lifetime of a session in seconds
+	TTL int `yaml:"ttl" default:"300"`
+
+	// WebhookSecret signs webhook payloads with HMAC-SHA256, the signature is sent in the X-Webhook-Signature header
+	WebhookSecret string `yaml:"webhook_secret"`
+
+	// WebhookAllowList holds the URL prefixes a session webhook may be registered with, webhooks are refused if empty
+	WebhookAllowList []string `yaml:"webhook_allow_list"`
+
+	// SweepInterval is the number of seconds between purges of expired sessions from the memory store
+	SweepInterval int `yaml:"sweep_interval" default:"60" validate:"gt=0"`
+
+	// Store keeps sessions and their nonces, memory for a single replica or mongo, at common.mongo.uri, to share them
+	// between replicas
+	Store string `yaml:"store" default:"memory" validate:"oneof=memory mongo"`
 }
 
 // Datastore holds the datastore configuration
@@ -164,16 +563,303 @@ type BasicAuth struct {
 	Enabled bool              `yaml:"enabled"`
 }
 
+// Authentication holds the client authentication configuration, every client acts for one authentic source
+type Authentication struct {
+	Enabled bool `yaml:"enabled"`
+
+	// APIKeys maps a static API key, sent in the X-API-Key header, to an authentic source
+	APIKeys map[string]string `yaml:"api_keys"`
+
+	// ClientCertificates maps the hex encoded SHA-256 fingerprint of a TLS client certificate to an authentic source
+	ClientCertificates map[string]string `yaml:"client_certificates"`
+}
+
 // APIGW holds the datastore configuration
 type APIGW struct {
 	APIServer APIServer `yaml:"api_server" validate:"required"`
+
+	// GRPCServer exposes upload, identity, collect and revoke over gRPC, authenticated with the api_server auth api keys and
+	// client certificates and limited by the api_server rate limit, disabled if not set
+	GRPCServer *GRPCServer `yaml:"grpc_server" validate:"omitempty"`
+
+	TrustModel TrustModel `yaml:"trust_model" validate:"omitempty"`
+
+	Deferred Deferred `yaml:"deferred" validate:"omitempty"`
+
+	Erasure Erasure `yaml:"erasure" validate:"omitempty"`
+
+	Webhook Webhook `yaml:"webhook" validate:"omitempty"`
+
+	FieldEncryption FieldEncryption `yaml:"field_encryption" validate:"omitempty"`
+
+	Idempotency Idempotency `yaml:"idempotency" validate:"omitempty"`
+
+	DocumentSchema DocumentSchema `yaml:"document_schema" validate:"omitempty"`
+
+	SignedUpload SignedUpload `yaml:"signed_upload" validate:"omitempty"`
+
+	CollectCode CollectCode `yaml:"collect_code" validate:"omitempty"`
+
+	ObjectStore ObjectStore `yaml:"object_store" validate:"omitempty"`
+
+	IdentityMatching IdentityMatching `yaml:"identity_matching" validate:"omitempty"`
+}
+
+// IdentityMatching holds the scoring of the identity of a collect request against the identities of the document, for
+// requests without authentic_source_person_id
+type IdentityMatching struct {
+	// Enabled scores family name, given name and birth date instead of requiring them to be equal
+	Enabled bool `yaml:"enabled"`
+
+	// ApproveScore is the least score, from 0 to 1, the document is collected at without review
+	ApproveScore float64 `yaml:"approve_score" default:"0.92" validate:"omitempty,gt=0,lte=1"`
+
+	// ReviewScore is the least score the collect request is queued for manual review at, lower scores are no match
+	ReviewScore float64 `yaml:"review_score" default:"0.75" validate:"omitempty,gt=0,ltefield=ApproveScore"`
+
+	// BirthDateTolerance is the number of days birth dates may be apart at a reduced score, 0 requires equal dates
+	// except for swapped day and month
+	BirthDateTolerance int `yaml:"birth_date_tolerance" validate:"omitempty,gte=0"`
+
+	// FamilyNameWeight, GivenNameWeight and BirthDateWeight are the weights of the fields in the score
+	FamilyNameWeight float64 `yaml:"family_name_weight" default:"0.4" validate:"omitempty,gte=0"`
+	GivenNameWeight  float64 `yaml:"given_name_weight" default:"0.35" validate:"omitempty,gte=0"`
+	BirthDateWeight  float64 `yaml:"birth_date_weight" default:"0.25" validate:"omitempty,gte=0"`
+}
+
+// ObjectStore holds the S3 compatible object storage, e.g. AWS S3 or MinIO, of the binary attachments of document data
+type ObjectStore struct {
+	// Enabled moves base64 data uris in uploaded document data to the object storage, stored documents keep a reference
+	Enabled bool `yaml:"enabled"`
+
+	// Endpoint of the S3 API, example: https://s3.eu-north-1.amazonaws.com or http://minio:9000
+	Endpoint string `yaml:"endpoint" validate:"required_if=Enabled true"`
+
+	Region string `yaml:"region" default:"us-east-1"`
+	Bucket string `yaml:"bucket" validate:"required_if=Enabled true"`
+
+	AccessKeyID     string `yaml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key"`
+
+	// PathStyle addresses the bucket in the url path instead of the host name, as MinIO expects
+	PathStyle bool `yaml:"path_style"`
+
+	// MinSize is the least number of bytes of an attachment moved to the object storage, smaller ones stay embedded
+	MinSize int `yaml:"min_size" default:"4096" validate:"omitempty,gte=0"`
+
+	// PresignTTL is the number of seconds the presigned download urls of attachments in API responses are valid
+	PresignTTL int64 `yaml:"presign_ttl" default:"900" validate:"omitempty,gt=0"`
+}
+
+// CollectCode holds the limits of collect codes, the credential offers of uploaded documents
+type CollectCode struct {
+	// TTL is the number of seconds a collect code is valid after upload when the upload sets no valid_until, 0 never expires
+	TTL int64 `yaml:"ttl" validate:"omitempty,gte=0"`
+
+	// MaxUses is the number of credentials that can be issued with one collect code, 0 is unlimited
+	MaxUses int64 `yaml:"max_uses" validate:"omitempty,gte=0"`
+}
+
+// SignedUpload holds the configuration of uploads signed as JWS by the authentic sources
+type SignedUpload struct {
+	// Enabled verifies uploads sent as compact JWS, Content-Type application/jose, or with a detached JWS in the X-JWS-Signature header
+	Enabled bool `yaml:"enabled"`
+
+	// Required rejects uploads that are not signed
+	Required bool `yaml:"required"`
+
+	// JWKSPaths maps an authentic source to the JWKS file with the public keys its uploads are signed with
+	JWKSPaths map[string]string `yaml:"jwks_paths" validate:"required_if=Enabled true"`
+
+	// MaxAge is the number of seconds after its iat a signed upload is accepted, the nonce is remembered as long
+	MaxAge int64 `yaml:"max_age" default:"300" validate:"omitempty,gt=0"`
+
+	// Leeway is the number of seconds of clock skew allowed when checking iat and exp
+	Leeway int64 `yaml:"leeway" default:"30" validate:"omitempty,gte=0"`
+}
+
+// DocumentSchema holds the configuration of the validation of document_data against a JSON schema per document_type and document_data_version
+type DocumentSchema struct {
+	// Enabled validates uploaded and imported document_data, schemas are looked up in Dir, then URL, then the built-in EHIC, PDA1, ELM, PID and mDL schemas
+	Enabled bool `yaml:"enabled"`
+
+	// Dir holds schemas as <document_type>/<document_data_version>.json
+	Dir string `yaml:"dir"`
+
+	// URL is the base URL schemas are fetched from as <url>/<document_type>/<document_data_version>.json
+	URL string `yaml:"url" validate:"omitempty,url"`
+
+	// Required rejects documents without a schema for their document_type and document_data_version, else they are stored unvalidated
+	Required bool `yaml:"required"`
+
+	// CacheTTL is the time in seconds a schema, or the lack of one, is cached
+	CacheTTL int64 `yaml:"cache_ttl" default:"3600" validate:"omitempty,gt=0"`
+}
+
+// Idempotency holds the configuration of the Idempotency-Key header of the mutating endpoints
+type Idempotency struct {
+	// Enabled stores the responses of requests with an Idempotency-Key header and returns them for retries of the same key
+	Enabled bool `yaml:"enabled"`
+
+	// TTL is the number of seconds a response is kept, changing it requires dropping the created_at_ttl index
+	TTL int64 `yaml:"ttl" default:"86400" validate:"omitempty,gt=0"`
+}
+
+// FieldEncryption holds the configuration of the encryption at rest of identity fields
+type FieldEncryption struct {
+	// Enabled encrypts Fields of identities, and the disclosures of issued credentials, written from now on, what is
+	// already stored is read as is
+	Enabled bool `yaml:"enabled"`
+
+	// Fields are the identity fields to encrypt, by json name, e.g. birth_date
+	Fields []string `yaml:"fields" validate:"required_if=Enabled true,dive,oneof=authentic_source_person_id family_name given_name birth_date family_name_at_birth given_name_at_birth birth_place gender birth_country birth_state birth_city resident_address resident_country resident_state resident_city resident_postal_code resident_street resident_house_number nationality"`
+
+	// KEKType is local, the key files of kek_path and previous_kek_paths, or awskms, the AWS KMS keys of kms
+	KEKType string `yaml:"kek_type" default:"local" validate:"omitempty,oneof=local awskms"`
+
+	// KEKPath to the base64 encoded 32 byte key encryption key that wraps new data encryption keys
+	KEKPath string `yaml:"kek_path" validate:"required_if=Enabled true KEKType local"`
+
+	// PreviousKEKPaths are rotated out key encryption keys, kept to unwrap until the admin rotate endpoint has rewrapped all keys
+	PreviousKEKPaths []string `yaml:"previous_kek_paths"`
+
+	// KMS holds the key encryption keys of kek_type awskms
+	KMS FieldEncryptionKMS `yaml:"kms"`
+
+	// BlindIndexKeyPath to the base64 encoded 32 byte key of the blind indexes encrypted fields are looked up by, it can not be rotated
+	BlindIndexKeyPath string `yaml:"blind_index_key_path" validate:"required_if=Enabled true"`
+}
+
+// FieldEncryptionKMS holds the AWS KMS key encryption keys of the identity field encryption
+type FieldEncryptionKMS struct {
+	// KeyID of the symmetric AWS KMS key that wraps new data encryption keys, a key id, key ARN or alias
+	KeyID string `yaml:"key_id"`
+
+	// PreviousKeyIDs are rotated out keys, kept to unwrap until the admin rotate endpoint has rewrapped all keys
+	PreviousKeyIDs []string `yaml:"previous_key_ids"`
+
+	// Region of the keys
+	Region string `yaml:"region"`
+
+	// CredentialsFile is an AWS shared config file, the default credential chain is used if empty
+	CredentialsFile string `yaml:"credentials_file"`
+
+	// Endpoint overrides the KMS endpoint, example: a VPC endpoint
+	Endpoint string `yaml:"endpoint"`
+}
+
+// Webhook holds the webhook delivery configuration
+type Webhook struct {
+	// Enabled turns on the webhook subscription API and event delivery
+	Enabled bool `yaml:"enabled"`
+
+	// AllowList holds the URL prefixes a webhook may be subscribed with, subscriptions are refused if empty.
+	// Webhooks resolving to loopback, link-local or private addresses are refused regardless.
+	AllowList []string `yaml:"allow_list"`
+
+	// Interval is the number of seconds between delivery runs
+	Interval int `yaml:"interval" default:"10"`
+
+	// Timeout is the number of seconds to wait for a webhook response
+	Timeout int `yaml:"timeout" default:"5"`
+
+	// MaxAttempts is the number of delivery attempts before an event is given up
+	MaxAttempts int `yaml:"max_attempts" default:"8"`
+
+	// Backoff is the number of seconds before the first retry, doubled for every further retry
+	Backoff int `yaml:"backoff" default:"30"`
+
+	// BatchSize is the maximum number of deliveries tried in one run
+	BatchSize int64 `yaml:"batch_size" default:"100"`
+}
+
+// Erasure holds the configuration of the worker running the data subject erasure jobs
+type Erasure struct {
+	// Interval is the number of seconds between runs of the pending erasure jobs
+	Interval int `yaml:"interval" default:"10" validate:"omitempty,gt=0"`
+
+	// MaxAttempts is the number of runs of a job before it fails
+	MaxAttempts int `yaml:"max_attempts" default:"5" validate:"omitempty,gt=0"`
+}
+
+// Deferred holds the deferred credential issuance configuration
+type Deferred struct {
+	// Enabled lets the credential endpoint answer with a transaction_id when the document is not yet available
+	Enabled bool `yaml:"enabled"`
+
+	// Interval is the number of seconds between document collection retries
+	Interval int `yaml:"interval" default:"60"`
+
+	// MaxAttempts is the number of retries before the transaction fails
+	MaxAttempts int `yaml:"max_attempts" default:"60"`
+
+	// BatchSize is the maximum number of transactions retried at each interval
+	BatchSize int64 `yaml:"batch_size" default:"100"`
+}
+
+// TrustModel holds the trust framework the credential issuer takes part in
+type TrustModel struct {
+	// Type is openid_federation, or empty for none
+	Type string `yaml:"type" validate:"omitempty,oneof=openid_federation"`
+
+	// EntityID is the federation entity identifier, the external base url of the apigw, example: https://issuer.sunet.se
+	EntityID string `yaml:"entity_id" validate:"required_if=Type openid_federation"`
+
+	// SigningKeyPath to an ECDSA prime256v1 key in PEM format, used to sign entity statements
+	SigningKeyPath string `yaml:"signing_key_path" validate:"required_if=Type openid_federation"`
+
+	// AuthorityHints are the entity identifiers of the immediate superiors
+	AuthorityHints []string `yaml:"authority_hints"`
+
+	// TrustAnchors are the trust anchors a trust chain of the issuer may end in
+	TrustAnchors []TrustAnchor `yaml:"trust_anchors" validate:"dive"`
+
+	// OrganizationName is published in the federation_entity metadata
+	OrganizationName string `yaml:"organization_name"`
+
+	// TTL is the lifetime of the entity configuration in seconds
+	TTL int64 `yaml:"ttl" default:"86400"`
+}
+
+// TrustAnchor holds an OpenID Federation trust anchor
+type TrustAnchor struct {
+	EntityID string `yaml:"entity_id" validate:"required"`
+
+	// JWKSPath is a JWKS file with the federation keys of the trust anchor
+	JWKSPath string `yaml:"jwks_path" validate:"required"`
 }
 
 // OTEL holds the opentelemetry configuration
 type OTEL struct {
-	Addr    string `yaml:"addr" validate:"required"`
-	Type    string `yaml:"type" validate:"required"`
-	Timeout int64  `yaml:"timeout" default:"10"`
+	Addr    string      `yaml:"addr" validate:"required"`
+	Type    string      `yaml:"type" validate:"required"`
+	Timeout int64       `yaml:"timeout" default:"10"`
+	Sampler Sampler     `yaml:"sampler"`
+	Metrics OTELMetrics `yaml:"metrics"`
+}
+
+// Sampler holds the trace sampling configuration
+type Sampler struct {
+	// Type is one of always, ratio or ratelimit, the sampler respects the sampling decision of a parent span
+	Type string `yaml:"type" default:"always"`
+
+	// Ratio is the fraction of root traces to sample when type is ratio
+	Ratio float64 `yaml:"ratio" default:"1"`
+
+	// RateLimit is the maximum number of root traces sampled per second when type is ratelimit
+	RateLimit float64 `yaml:"rate_limit" default:"100"`
+}
+
+// OTELMetrics holds the opentelemetry metrics exporter configuration, metrics are exported to the tracing addr
+type OTELMetrics struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Interval in seconds between exports
+	Interval int64 `yaml:"interval" default:"60"`
+
+	// Prometheus serves the metrics on GET /metrics of the http server of each service, without authentication like
+	// the health probes. It does not need enabled, the otlp export.
+	Prometheus bool `yaml:"prometheus"`
 }
 
 // UI holds the user-interface configuration
@@ -184,6 +870,7 @@ type UI struct {
 	SessionCookieAuthenticationKey    string    `yaml:"session_cookie_authentication_key" validate:"required"`
 	SessionStoreEncryptionKey         string    `yaml:"session_store_encryption_key" validate:"required"`
 	SessionInactivityTimeoutInSeconds int       `yaml:"session_inactivity_timeout_in_seconds" validate:"required"`
+	DashboardIntervalInSeconds        int       `yaml:"dashboard_interval_in_seconds" default:"5" validate:"gt=0"`
 	Services                          struct {
 		APIGW struct {
 			BaseURL string `yaml:"base_url"`
@@ -191,6 +878,10 @@ type UI struct {
 		MockAS struct {
 			BaseURL string `yaml:"base_url"`
 		} `yaml:"mockas"`
+		// Verifier serves the audit trail, it needs verifier.analytics.enabled
+		Verifier struct {
+			BaseURL string `yaml:"base_url"`
+		} `yaml:"verifier"`
 	} `yaml:"services"`
 }
 
@@ -199,6 +890,54 @@ type CredentialType struct {
 	Profile string `yaml:"profile" validate:"required"`
 }
 
+// credential formats of a credential profile
+const (
+	CredentialFormatSDJWT = "vc+sd-jwt"
+	CredentialFormatCWT   = "cwt"
+)
+
+// CredentialProfile holds how credentials of one credential type are issued
+type CredentialProfile struct {
+	// DocumentType is the document type the credential is made from, the credential type if not set
+	DocumentType string `yaml:"document_type"`
+
+	// Formats the credential can be issued in, vc+sd-jwt and cwt, vc+sd-jwt if not set
+	Formats []string `yaml:"formats" validate:"dive,oneof=vc+sd-jwt cwt"`
+
+	// SigningKID is the issuer key the credentials are signed with, unless the tenant has a signing_kid
+	SigningKID string `yaml:"signing_kid"`
+
+	// SigningAlg selects the active issuer key with this JOSE algorithm when signing_kid is not set, EdDSA for Ed25519
+	SigningAlg string `yaml:"signing_alg" validate:"omitempty,oneof=ES256 ES384 ES512 EdDSA"`
+
+	// VCT of the credential, issuer.jwt_attribute.verifiable_credential_type if not set
+	VCT string `yaml:"vct" validate:"omitempty,url"`
+
+	// VCTM resolves the type metadata of the vct, issuer.vctm.enabled if not set
+	VCTM *bool `yaml:"vctm"`
+
+	// ValidDuration of the credential in seconds, issuer.jwt_attribute.valid_duration if 0
+	ValidDuration int64 `yaml:"valid_duration" validate:"gte=0"`
+
+	// StatusList adds a status list entry to the credential, issuer.status_list.enabled if not set
+	StatusList *bool `yaml:"status_list"`
+
+	// Claims maps document_data to the claims of the credential, the built-in mapping of the document type is used if empty
+	Claims []ClaimMapping `yaml:"claims" validate:"omitempty,dive"`
+}
+
+// ClaimMapping maps one value of document_data to a claim of the credential
+type ClaimMapping struct {
+	// Claim is the name of the claim, dot separated for nested claims, example: cardHolder.birthDate
+	Claim string `yaml:"claim" validate:"required"`
+
+	// Path is the JSON pointer to the value in document_data, example: /cardHolder/birthDate
+	Path string `yaml:"path" validate:"required,startswith=/"`
+
+	// SelectiveDisclosure makes the claim selectively disclosable
+	SelectiveDisclosure bool `yaml:"selective_disclosure"`
+}
+
 // NotificationEndpoint holds the configuration for the notification endpoint
 type NotificationEndpoint struct {
 	URL string `yaml:"url" validate:"required"`
@@ -241,6 +980,9 @@ type Cfg struct {
 	Persistent       Persistent                 `yaml:"persistent" validate:"omitempty"`
 	MockAS           MockAS                     `yaml:"mock_as" validate:"omitempty"`
 	UI               UI                         `yaml:"ui" validate:"omitempty"`
+
+	// CredentialProfiles are the issuance profiles by credential type, shared by apigw and issuer
+	CredentialProfiles map[string]CredentialProfile `yaml:"credential_profiles" validate:"omitempty,dive"`
 }
 
 func (cfg *Cfg) IsAsyncEnabled(log *logger.Log) bool {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/base64"
 	"net/url"
+	"time"
 
 	"github.com/skip2/go-qrcode"
 )
@@ -19,6 +20,68 @@ type CompleteDocument struct {
 	// example: "1.0.0"
 	DocumentDataVersion string `json:"document_data_version,omitempty" bson:"document_data_version" validate:"required,semver"`
 	QR                  *QR    `json:"qr,omitempty" bson:"qr"`
+
+	// StatusHistory holds the status changes of the document, oldest first
+	StatusHistory []DocumentStatus `json:"status_history,omitempty" bson:"status_history,omitempty"`
+}
+
+// Document statuses recorded in the status history
+const (
+	DocumentStatusRevoked = "revoked"
+)
+
+// DocumentStatus is one status change of a document
+type DocumentStatus struct {
+	// required: true
+	// example: revoked
+	Status string `json:"status" bson:"status"`
+
+	// required: false
+	// example: key_compromise
+	ReasonCode string `json:"reason_code,omitempty" bson:"reason_code,omitempty"`
+
+	// required: false
+	// example: lost or stolen
+	Reason string `json:"reason,omitempty" bson:"reason,omitempty"`
+
+	// Actor is who changed the status
+	// required: true
+	// example: SUNET
+	Actor string `json:"actor" bson:"actor"`
+
+	// Timestamp is when the status takes effect
+	// required: true
+	// example: 509567558
+	// format: int64
+	Timestamp int64 `json:"timestamp" bson:"timestamp"`
+}
+
+// DocumentVersion is the document data of one document_data_version of a document, kept when the document is re-uploaded
+type DocumentVersion struct {
+	// required: true
+	// example: SUNET
+	AuthenticSource string `json:"authentic_source" bson:"authentic_source"`
+
+	// required: true
+	// example: PDA1
+	DocumentType string `json:"document_type" bson:"document_type"`
+
+	// required: true
+	// example: 5e7a981c-c03f-11ee-b116-9b12c59362b9
+	DocumentID string `json:"document_id" bson:"document_id"`
+
+	// required: true
+	// example: "1.0.0"
+	DocumentDataVersion string `json:"document_data_version" bson:"document_data_version"`
+
+	// DocumentData is left out when versions are listed
+	DocumentData map[string]any `json:"document_data,omitempty" bson:"document_data"`
+
+	// UploadedAt is when the version was uploaded
+	// required: true
+	// example: 509567558
+	// format: int64
+	UploadedAt int64 `json:"uploaded_at" bson:"uploaded_at"`
 }
 
 // DocumentList is a generic type for document list
@@ -84,6 +147,72 @@ type Consent struct {
 	CreatedAt int64 `json:"created_at,omitempty" bson:"created_at" validate:"required"`
 }
 
+// Consent scopes
+const (
+	ConsentScopeIssuance = "issuance"
+	ConsentScopeSharing  = "sharing"
+)
+
+// DocumentConsent is a holder approval for issuance or sharing of a document
+type DocumentConsent struct {
+	// required: true
+	// example: 0b8f3c5e-8e0a-4d7b-9f2a-6a1f2c3d4e5f
+	ConsentID string `json:"consent_id" bson:"consent_id" validate:"required"`
+
+	// required: true
+	// example: SUNET
+	AuthenticSource string `json:"authentic_source" bson:"authentic_source" validate:"required"`
+
+	// required: true
+	// example: PDA1
+	DocumentType string `json:"document_type" bson:"document_type" validate:"required"`
+
+	// required: true
+	// example: 5e7a981c-c03f-11ee-b116-9b12c59362b9
+	DocumentID string `json:"document_id" bson:"document_id" validate:"required"`
+
+	// ApprovedBy is the holder who gave the consent, e.g. the authentic source person id
+	// required: true
+	// example: 65636cbc-c03f-11ee-8dc4-67135cc9bd8a
+	ApprovedBy string `json:"approved_by" bson:"approved_by" validate:"required"`
+
+	// Scope is what the consent covers, issuance and/or sharing
+	// required: true
+	// example: ["issuance"]
+	Scope []string `json:"scope" bson:"scope" validate:"required,min=1,dive,oneof=issuance sharing"`
+
+	// required: true
+	// example: 509567558
+	// format: int64
+	CreatedAt int64 `json:"created_at" bson:"created_at" validate:"required"`
+
+	// WithdrawnAt is zero while the consent is active
+	// required: false
+	// example: 509567558
+	// format: int64
+	WithdrawnAt int64 `json:"withdrawn_at,omitempty" bson:"withdrawn_at"`
+}
+
+// HasScope reports if the consent covers scope
+func (c *DocumentConsent) HasScope(scope string) bool {
+	for _, s := range c.Scope {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// ConsentAuditEntry is an immutable record of a change to a document consent
+type ConsentAuditEntry struct {
+	ConsentID string `json:"consent_id" bson:"consent_id"`
+
+	// Action is created or withdrawn
+	Action     string `json:"action" bson:"action"`
+	ApprovedBy string `json:"approved_by" bson:"approved_by"`
+	Timestamp  int64  `json:"timestamp" bson:"timestamp"`
+}
+
 // Collect is a generic type for collect
 type Collect struct {
 	// required: false
@@ -94,6 +223,9 @@ type Collect struct {
 	// example: 509567558
 	// format: int64
 	ValidUntil int64 `json:"valid_until,omitempty" bson:"valid_until"`
+
+	// Uses is the number of credentials issued with the collect code, maintained by the apigw
+	Uses int64 `json:"-" bson:"uses,omitempty"`
 }
 
 // MetaData is a generic type for metadata
@@ -108,7 +240,7 @@ type MetaData struct {
 
 	// required: true
 	// example: PDA1
-	DocumentType string `json:"document_type,omitempty" bson:"document_type" validate:"required,oneof=PDA1 EHIC"`
+	DocumentType string `json:"document_type,omitempty" bson:"document_type" validate:"required,document_type"`
 
 	// required: true
 	// example: 5e7a981c-c03f-11ee-b116-9b12c59362b9
@@ -133,6 +265,40 @@ type MetaData struct {
 	// example: 509567558
 	// format: int64
 	CredentialValidTo int64 `json:"credential_valid_to,omitempty" bson:"valid_to"`
+
+	// Revision is incremented on every update of the document, and is returned as ETag
+	// required: false
+	// example: 1
+	// format: int64
+	Revision int64 `json:"revision,omitempty" bson:"revision"`
+
+	// ModifiedAt is the unix time the document was last created, updated or revoked, set by apigw and ignored if sent by the client
+	// required: false
+	// example: 509567558
+	// format: int64
+	ModifiedAt int64 `json:"modified_at,omitempty" bson:"modified_at"`
+
+	// SignedBy is the key a signed upload was verified with, set by apigw and ignored if sent by the client
+	// required: false
+	SignedBy *UploadSigner `json:"signed_by,omitempty" bson:"signed_by,omitempty"`
+}
+
+// UploadSigner identifies the authentic source key a document upload was signed with, for non-repudiation
+type UploadSigner struct {
+	// AuthenticSource the key is registered for
+	AuthenticSource string `json:"authentic_source" bson:"authentic_source"`
+
+	// KID of the key in the JWKS of the authentic source
+	KID string `json:"kid" bson:"kid"`
+
+	// Thumbprint is the base64url encoded RFC 7638 SHA-256 thumbprint of the key
+	Thumbprint string `json:"thumbprint" bson:"thumbprint"`
+
+	// SignedAt is the iat of the signature, unix time
+	SignedAt int64 `json:"signed_at" bson:"signed_at"`
+
+	// JWS is the verified compact JWS with its payload, the request body as sent
+	JWS string `json:"jws" bson:"jws"`
 }
 
 // RevocationReference refer to a document
@@ -167,6 +333,22 @@ type Revocation struct {
 	// required: false
 	// example: lost or stolen
 	Reason string `json:"reason,omitempty" bson:"reason"`
+
+	// ReasonCode is the reason for revocation, as a CRL reason code
+	// required: false
+	// example: key_compromise
+	ReasonCode string `json:"reason_code,omitempty" bson:"reason_code,omitempty" validate:"omitempty,oneof=unspecified key_compromise affiliation_changed superseded cessation_of_operation privilege_withdrawn"`
+}
+
+// IsRevoked reports if the revocation is in effect at now
+func (r *Revocation) IsRevoked(now int64) bool {
+	if r == nil {
+		return false
+	}
+	if r.RevokedAt != 0 {
+		return r.RevokedAt <= now
+	}
+	return r.Revoked
 }
 
 // IdentitySchema is a collection of fields representing an identity schema
@@ -260,6 +442,18 @@ type Identity struct {
 	// required: false
 	// example: swedish
 	Nationality string `json:"nationality,omitempty" bson:"nationality"`
+
+	// EncryptionKey is the wrapped data encryption key of the encrypted fields, set only at rest
+	EncryptionKey *WrappedKey `json:"-" bson:"encryption_key,omitempty"`
+
+	// BlindIndex holds the HMAC of the encrypted fields by field name, set only at rest
+	BlindIndex map[string]string `json:"-" bson:"blind_index,omitempty"`
+}
+
+// WrappedKey is a data encryption key encrypted by a key encryption key
+type WrappedKey struct {
+	KeyID string `json:"key_id" bson:"key_id"`
+	Key   []byte `json:"key" bson:"key"`
 }
 
 // DocumentDisplay is a collection of fields representing display of document
@@ -288,3 +482,484 @@ type QR struct {
 	// example: "https://example.com"
 	DeepLink string `json:"deep_link,omitempty" bson:"deep_link" validate:"required"`
 }
+
+// Credential notification events, OpenID4VCI notification endpoint
+const (
+	CredentialNotificationAccepted = "credential_accepted"
+	CredentialNotificationFailure  = "credential_failure"
+	CredentialNotificationDeleted  = "credential_deleted"
+)
+
+// CredentialNotification correlates the notification_id of an issued credential with its document, and holds the
+// events the wallet has reported for it
+type CredentialNotification struct {
+	// required: true
+	// example: 3fbc6a6e-0d51-4a0b-9d5e-1c9f4f0e7d2a
+	NotificationID string `json:"notification_id" bson:"notification_id"`
+
+	// required: true
+	// example: SUNET
+	AuthenticSource string `json:"authentic_source" bson:"authentic_source"`
+
+	// required: true
+	// example: PDA1
+	DocumentType string `json:"document_type" bson:"document_type"`
+
+	// required: true
+	// example: 5e7a981c-c03f-11ee-b116-9b12c59362b9
+	DocumentID string `json:"document_id" bson:"document_id"`
+
+	// required: true
+	// example: 509567558
+	// format: int64
+	IssuedAt int64 `json:"issued_at" bson:"issued_at"`
+
+	// Events are the wallet reported events, oldest first
+	Events []CredentialNotificationEvent `json:"events" bson:"events"`
+
+	// CredentialType is the credential type the credential was requested as, reused when it is refreshed
+	// example: urn:eudi:pda1:1
+	CredentialType string `json:"credential_type,omitempty" bson:"credential_type,omitempty"`
+
+	// Disclosures of the issued credential, a refresh reuses the salts of the claims that did not change
+	Disclosures []string `json:"-" bson:"disclosures,omitempty"`
+
+	// DisclosuresKey wraps the data encryption key of the disclosures, nil if they were stored without field encryption
+	DisclosuresKey *WrappedKey `json:"-" bson:"disclosures_key,omitempty"`
+
+	// RefreshOf is the notification_id of the credential this one refreshes
+	// example: 3fbc6a6e-0d51-4a0b-9d5e-1c9f4f0e7d2a
+	RefreshOf string `json:"refresh_of,omitempty" bson:"refresh_of,omitempty"`
+
+	// RefreshTrigger tells why the credential was refreshed, wallet_request or expected_update
+	// example: wallet_request
+	RefreshTrigger string `json:"refresh_trigger,omitempty" bson:"refresh_trigger,omitempty"`
+
+	// RefreshedBy is the notification_id of the credential that replaced this one
+	RefreshedBy string `json:"refreshed_by,omitempty" bson:"refreshed_by,omitempty"`
+
+	// HolderJWK is the holder key the credential is bound to, a refresh binds the new credential to the same key
+	HolderJWK string `json:"-" bson:"holder_jwk,omitempty"`
+}
+
+// CredentialNotificationEvent is an event reported by the wallet
+type CredentialNotificationEvent struct {
+	// Event is one of credential_accepted, credential_failure or credential_deleted
+	// required: true
+	// example: credential_accepted
+	Event string `json:"event" bson:"event"`
+
+	// required: false
+	// example: Could not store the credential
+	EventDescription string `json:"event_description,omitempty" bson:"event_description,omitempty"`
+
+	// required: true
+	// example: 509567558
+	// format: int64
+	Timestamp int64 `json:"timestamp" bson:"timestamp"`
+}
+
+// Deferred credential statuses
+const (
+	DeferredStatusPending = "pending"
+	DeferredStatusIssued  = "issued"
+	DeferredStatusFailed  = "failed"
+)
+
+// DeferredCredential is a credential request waiting for the authentic source to provide the document
+type DeferredCredential struct {
+	// required: true
+	// example: 8c4c1d35-5b5e-4a4e-8d3b-2f5f4e1a9c7e
+	TransactionID string `json:"transaction_id" bson:"transaction_id"`
+
+	// required: true
+	// example: SUNET
+	AuthenticSource string `json:"authentic_source" bson:"authentic_source"`
+
+	// required: true
+	// example: PDA1
+	DocumentType string `json:"document_type" bson:"document_type"`
+
+	// required: true
+	// example: sdjwt
+	CredentialType string `json:"credential_type" bson:"credential_type"`
+
+	// required: true
+	// example: 98fe67fc-c03f-11ee-bbee-4345224d414f
+	CollectID string `json:"collect_id" bson:"collect_id"`
+
+	// required: true
+	Identity *Identity `json:"identity" bson:"identity"`
+
+	// HolderJWK is the holder key of the verified proof of the request, the credential is bound to it once issued
+	HolderJWK string `json:"-" bson:"holder_jwk,omitempty"`
+
+	// Status is one of pending, issued or failed
+	// required: true
+	// example: pending
+	Status string `json:"status" bson:"status"`
+
+	// Attempts is the number of failed document collection retries
+	// required: true
+	// example: 3
+	Attempts int `json:"attempts" bson:"attempts"`
+
+	// required: true
+	// example: 509567558
+	// format: int64
+	CreatedAt int64 `json:"created_at" bson:"created_at"`
+
+	// required: false
+	// example: 509567558
+	// format: int64
+	UpdatedAt int64 `json:"updated_at" bson:"updated_at"`
+
+	// Credential is set once the status is issued
+	// required: false
+	Credential *DeferredCredentialResult `json:"credential,omitempty" bson:"credential,omitempty"`
+}
+
+// DeferredCredentialResult is the issued credential of a deferred credential request
+type DeferredCredentialResult struct {
+	JWT            string   `json:"jwt" bson:"jwt"`
+	Disclosures    []string `json:"disclosures" bson:"disclosures"`
+	NotificationID string   `json:"notification_id,omitempty" bson:"notification_id,omitempty"`
+
+	// DisclosuresKey wraps the data encryption key of the disclosures, nil if they were stored without field encryption
+	DisclosuresKey *WrappedKey `json:"-" bson:"disclosures_key,omitempty"`
+}
+
+// Identity review statuses
+const (
+	IdentityReviewPending  = "pending"
+	IdentityReviewApproved = "approved"
+	IdentityReviewRejected = "rejected"
+)
+
+// IdentityReview is a collect request whose identity matches an identity of the document too loosely to collect the
+// document without a person deciding
+type IdentityReview struct {
+	// required: true
+	// example: 0b7d6a0e-8f3c-4d0a-9a53-3c1f9a4d2e61
+	ReviewID string `json:"review_id" bson:"review_id"`
+
+	// required: true
+	// example: SUNET
+	AuthenticSource string `json:"authentic_source" bson:"authentic_source"`
+
+	// required: true
+	// example: PDA1
+	DocumentType string `json:"document_type" bson:"document_type"`
+
+	// required: true
+	// example: 7a00fe1a-3e1a-11ef-9272-fb906803d1b8
+	DocumentID string `json:"document_id" bson:"document_id"`
+
+	// required: true
+	// example: 98fe67fc-c03f-11ee-bbee-4345224d414f
+	CollectID string `json:"collect_id" bson:"collect_id"`
+
+	// RequestHash identifies the normalized identity of the collect request, repeated requests share a review
+	RequestHash string `json:"-" bson:"request_hash"`
+
+	// Identity is the identity of the collect request
+	// required: true
+	Identity *Identity `json:"identity" bson:"identity"`
+
+	// AuthenticSourcePersonID of the best matching identity of the document
+	// required: false
+	// example: 65636cbc-c03f-11ee-8dc4-67135cc9bd8a
+	AuthenticSourcePersonID string `json:"authentic_source_person_id,omitempty" bson:"authentic_source_person_id,omitempty"`
+
+	// Score of the best matching identity, from 0 to 1
+	// required: true
+	// example: 0.84
+	Score float64 `json:"score" bson:"score"`
+
+	// FieldScores are the scores of family_name, given_name and birth_date
+	// required: true
+	FieldScores map[string]float64 `json:"field_scores" bson:"field_scores"`
+
+	// Status is one of pending, approved or rejected
+	// required: true
+	// example: pending
+	Status string `json:"status" bson:"status"`
+
+	// required: false
+	// example: admin
+	Reviewer string `json:"reviewer,omitempty" bson:"reviewer,omitempty"`
+
+	// required: false
+	Comment string `json:"comment,omitempty" bson:"comment,omitempty"`
+
+	// required: true
+	// example: 509567558
+	// format: int64
+	CreatedAt int64 `json:"created_at" bson:"created_at"`
+
+	// required: false
+	// example: 509567558
+	// format: int64
+	DecidedAt int64 `json:"decided_at,omitempty" bson:"decided_at,omitempty"`
+}
+
+// Webhook events
+const (
+	WebhookEventDocumentUploaded    = "document_uploaded"
+	WebhookEventIdentityAdded       = "identity_added"
+	WebhookEventCredentialIssued    = "credential_issued"
+	WebhookEventCredentialRefreshed = "credential_refreshed"
+	WebhookEventDocumentRevoked     = "document_revoked"
+)
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// WebhookSubscription is an external endpoint called on document lifecycle events of one authentic source
+type WebhookSubscription struct {
+	// required: true
+	// example: 0f3c3c5e-7c37-4d0e-a3a7-2f1f5b8f7f9a
+	ID string `json:"id" bson:"id"`
+
+	// required: true
+	// example: SUNET
+	AuthenticSource string `json:"authentic_source" bson:"authentic_source"`
+
+	// required: true
+	// example: https://hooks.example.com/vc
+	URL string `json:"url" bson:"url"`
+
+	// Secret is the HMAC-SHA256 key of the X-Webhook-Signature header, it is never returned
+	Secret string `json:"-" bson:"secret"`
+
+	// Events the subscription is called on, all events if empty
+	// required: false
+	// example: ["document_uploaded","document_revoked"]
+	Events []string `json:"events" bson:"events"`
+
+	// required: true
+	// example: 509567558
+	// format: int64
+	CreatedAt int64 `json:"created_at" bson:"created_at"`
+}
+
+// WebhookEvent is the JSON body posted to a webhook
+type WebhookEvent struct {
+	// ID is the delivery id, also sent as X-Webhook-Delivery
+	// required: true
+	// example: 6d5b7c1e-3e2f-4c4b-9d0a-8e1f2a3b4c5d
+	ID string `json:"id" bson:"id"`
+
+	// required: true
+	// example: document_uploaded
+	Event string `json:"event" bson:"event"`
+
+	// required: true
+	// example: 509567558
+	// format: int64
+	Timestamp int64 `json:"timestamp" bson:"timestamp"`
+
+	// required: true
+	// example: SUNET
+	AuthenticSource string `json:"authentic_source" bson:"authentic_source"`
+
+	// required: true
+	// example: PDA1
+	DocumentType string `json:"document_type" bson:"document_type"`
+
+	// required: true
+	// example: 5e7a981c-c03f-11ee-b116-9b12c59362b9
+	DocumentID string `json:"document_id" bson:"document_id"`
+
+	// Data holds event specific fields
+	// required: false
+	Data map[string]any `json:"data,omitempty" bson:"data,omitempty"`
+}
+
+// WebhookDelivery is one event queued for, or delivered to, a webhook subscription
+type WebhookDelivery struct {
+	// required: true
+	// example: 0f3c3c5e-7c37-4d0e-a3a7-2f1f5b8f7f9a
+	SubscriptionID string `json:"subscription_id" bson:"subscription_id"`
+
+	// required: true
+	Event WebhookEvent `json:"event" bson:"event"`
+
+	// Status is one of pending, delivered or failed
+	// required: true
+	// example: delivered
+	Status string `json:"status" bson:"status"`
+
+	// required: true
+	// example: 1
+	Attempts int `json:"attempts" bson:"attempts"`
+
+	// NextAttemptAt is when a pending delivery is tried next
+	// required: false
+	// example: 509567558
+	// format: int64
+	NextAttemptAt int64 `json:"next_attempt_at,omitempty" bson:"next_attempt_at"`
+
+	// LastError of the latest failed attempt
+	// required: false
+	// example: webhook responded with status 503
+	LastError string `json:"last_error,omitempty" bson:"last_error,omitempty"`
+
+	// required: false
+	// example: 509567558
+	// format: int64
+	DeliveredAt int64 `json:"delivered_at,omitempty" bson:"delivered_at,omitempty"`
+}
+
+// Erasure job statuses
+const (
+	ErasureJobPending   = "pending"
+	ErasureJobRunning   = "running"
+	ErasureJobCompleted = "completed"
+	ErasureJobFailed    = "failed"
+)
+
+// ErasureJob is a data subject erasure across the collections of the apigw, run by the erasure worker. It holds the
+// person id, encrypted like stored identities, only until the job has ended.
+type ErasureJob struct {
+	// required: true
+	// example: 2b1d8f5c-9a3e-4f7b-8c6d-1e2f3a4b5c6d
+	JobID string `json:"job_id" bson:"job_id"`
+
+	// required: true
+	// example: SUNET
+	AuthenticSource string `json:"authentic_source" bson:"authentic_source"`
+
+	// SubjectHash is the SHA-256 of the authentic source person id, as kept in the consent audit
+	// required: true
+	// example: sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08
+	SubjectHash string `json:"subject_hash" bson:"subject_hash"`
+
+	// Status is one of pending, running, completed or failed
+	// required: true
+	// example: completed
+	Status string `json:"status" bson:"status"`
+
+	// required: true
+	Erased ErasureCounts `json:"erased" bson:"erased"`
+
+	// Error of the last failed attempt, a failed job can be submitted again since erasure is idempotent
+	// required: false
+	// example: context deadline exceeded
+	Error string `json:"error,omitempty" bson:"error,omitempty"`
+
+	// Attempts is the number of times the job has been run
+	// required: true
+	// example: 1
+	Attempts int `json:"attempts" bson:"attempts"`
+
+	// Subject holds the authentic source person id of a pending or running job
+	Subject *Identity `json:"-" bson:"subject,omitempty"`
+
+	// required: true
+	// example: 509567558
+	// format: int64
+	CreatedAt int64 `json:"created_at" bson:"created_at"`
+
+	// required: false
+	// example: 509567558
+	// format: int64
+	CompletedAt int64 `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+
+	// UpdatedAt is when the job last made progress, a running job without progress is resumed by another worker
+	UpdatedAt int64 `json:"-" bson:"updated_at"`
+}
+
+// ErasureCounts is what an erasure job has removed or anonymized so far
+type ErasureCounts struct {
+	// Documents deleted, the subject was their only identity
+	Documents int64 `json:"documents" bson:"documents"`
+
+	// Identities removed from documents shared with other identities
+	Identities int64 `json:"identities" bson:"identities"`
+
+	DocumentConsents        int64 `json:"document_consents" bson:"document_consents"`
+	CredentialNotifications int64 `json:"credential_notifications" bson:"credential_notifications"`
+	DeferredCredentials     int64 `json:"deferred_credentials" bson:"deferred_credentials"`
+	Consents                int64 `json:"consents" bson:"consents"`
+
+	// ConsentAuditEntries pseudonymized, the entries are kept
+	ConsentAuditEntries int64 `json:"consent_audit_entries" bson:"consent_audit_entries"`
+}
+
+// Add adds the counts of other to c
+func (c *ErasureCounts) Add(other ErasureCounts) {
+	c.Documents += other.Documents
+	c.Identities += other.Identities
+	c.DocumentConsents += other.DocumentConsents
+	c.CredentialNotifications += other.CredentialNotifications
+	c.DeferredCredentials += other.DeferredCredentials
+	c.Consents += other.Consents
+	c.ConsentAuditEntries += other.ConsentAuditEntries
+}
+
+// Idempotency record statuses
+const (
+	IdempotencyPending   = "pending"
+	IdempotencyCompleted = "completed"
+)
+
+// IdempotencyRecord is the stored response of a request made with an Idempotency-Key header
+type IdempotencyRecord struct {
+	// Client is the authentic source of the authenticated client, empty if the route is not authenticated
+	Client string `json:"client" bson:"client"`
+
+	// Route is the method and route of the request, e.g. POST /api/v1/upload
+	Route string `json:"route" bson:"route"`
+
+	Key string `json:"key" bson:"key"`
+
+	// RequestHash is the hex encoded sha256 of the request body, a key reused with another body is rejected
+	RequestHash string `json:"request_hash" bson:"request_hash"`
+
+	Status     string            `json:"status" bson:"status"`
+	StatusCode int               `json:"status_code" bson:"status_code"`
+	Headers    map[string]string `json:"headers" bson:"headers"`
+	Body       []byte            `json:"body" bson:"body"`
+
+	// CreatedAt is a date for the TTL index of the collection
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
+
+// VerificationRecord is the outcome of one credential verification, kept for analytics and the audit trail
+type VerificationRecord struct {
+	// Issuer is the iss of the credential, empty if the credential could not be parsed
+	Issuer string `json:"issuer" bson:"issuer"`
+
+	// CredentialType is the vct of the credential
+	CredentialType string `json:"credential_type" bson:"credential_type"`
+
+	KID    string `json:"kid" bson:"kid"`
+	Policy string `json:"policy,omitempty" bson:"policy,omitempty"`
+	Valid  bool   `json:"valid" bson:"valid"`
+
+	// Reason is set when the credential is not valid
+	Reason string `json:"reason,omitempty" bson:"reason,omitempty"`
+
+	// LatencyMS is the time the verification took in milliseconds
+	LatencyMS int64 `json:"latency_ms" bson:"latency_ms"`
+
+	// SessionID is set when the credential was presented in a presentation session
+	SessionID string `json:"session_id,omitempty" bson:"session_id,omitempty"`
+
+	// TransactionDataTypes are the types of the transaction data the session asked the wallet to confirm
+	TransactionDataTypes []string `json:"transaction_data_types,omitempty" bson:"transaction_data_types,omitempty"`
+
+	// PolicyFailures are the rules of the verification policy the credential failed
+	PolicyFailures []string `json:"policy_failures,omitempty" bson:"policy_failures,omitempty"`
+
+	// SchemaErrors are the paths of the disclosed claims that do not conform to the vct schema, without their values
+	SchemaErrors []string `json:"schema_errors,omitempty" bson:"schema_errors,omitempty"`
+
+	// CreatedAt is a date for the TTL index of the collection
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
@@ -28,3 +28,43 @@ func TestGenericUploadTestv1JSON(t *testing.T) {
 		})
 	}
 }
+
+func TestRevocationIsRevoked(t *testing.T) {
+	tts := []struct {
+		name string
+		have *Revocation
+		want bool
+	}{
+		{
+			name: "nil",
+			have: nil,
+			want: false,
+		},
+		{
+			name: "not revoked",
+			have: &Revocation{ID: "1"},
+			want: false,
+		},
+		{
+			name: "revoked",
+			have: &Revocation{ID: "1", Revoked: true},
+			want: true,
+		},
+		{
+			name: "revoked in the past",
+			have: &Revocation{ID: "1", Revoked: true, RevokedAt: 100},
+			want: true,
+		},
+		{
+			name: "revoked in the future",
+			have: &Revocation{ID: "1", Revoked: true, RevokedAt: 300},
+			want: false,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.have.IsRevoked(200))
+		})
+	}
+}
@@ -24,3 +24,28 @@ func (l Leafs) Array() [][]byte {
 	}
 	return data
 }
+
+// StatusListEntry is the database model of an entity's position in the status lists
+type StatusListEntry struct {
+	gorm.Model
+	Entity    string `gorm:"uniqueIndex"`
+	ListIndex int64  `gorm:"uniqueIndex"`
+	Revoked   bool
+	Suspended bool
+}
+
+// StatusListEntries is the database model of status list entries
+type StatusListEntries []*StatusListEntry
+
+// StatusEvent is the database model of a status change of an entity, the ID orders the events and is the resume token of watchers
+type StatusEvent struct {
+	gorm.Model
+	Entity    string `gorm:"index"`
+	ListIndex int64
+	Action    string
+	Revoked   bool
+	Suspended bool
+}
+
+// StatusEvents is the database model of status events
+type StatusEvents []*StatusEvent
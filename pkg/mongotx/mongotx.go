@@ -0,0 +1,140 @@
+package mongotx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// Modes of the common.mongo.transactions config
+const (
+	// ModeAuto enables transactions when the deployment is a replica set or a sharded cluster
+	ModeAuto = "auto"
+
+	// ModeEnabled always runs in transactions, starting one fails on a standalone MongoDB
+	ModeEnabled = "enabled"
+
+	// ModeDisabled never runs in transactions
+	ModeDisabled = "disabled"
+)
+
+const (
+	// timeout bounds a transaction including the retried commits
+	timeout = 30 * time.Second
+
+	// labelUnknownCommitResult is the error label of a commit that may or may not have been applied, it is safe to retry
+	labelUnknownCommitResult = "UnknownTransactionCommitResult"
+)
+
+// session is the part of a mongo.Session a transaction uses
+type session interface {
+	StartTransaction(opts ...*options.TransactionOptions) error
+	AbortTransaction(ctx context.Context) error
+	CommitTransaction(ctx context.Context) error
+	EndSession(ctx context.Context)
+}
+
+// Runner runs functions in multi-document transactions
+type Runner struct {
+	enabled bool
+
+	// newSession returns a new session and ctx with the session attached
+	newSession func(ctx context.Context) (session, context.Context, error)
+}
+
+// New returns a runner of transactions on client in mode, in ModeAuto the deployment is asked if it supports them
+func New(ctx context.Context, client *mongo.Client, mode string) (*Runner, error) {
+	r := &Runner{
+		newSession: func(ctx context.Context) (session, context.Context, error) {
+			s, err := client.StartSession()
+			if err != nil {
+				return nil, nil, err
+			}
+			return s, mongo.NewSessionContext(ctx, s), nil
+		},
+	}
+
+	switch mode {
+	case ModeEnabled:
+		r.enabled = true
+	case ModeDisabled:
+	case ModeAuto, "":
+		var err error
+		if r.enabled, err = supported(ctx, client); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown transaction mode %q", mode)
+	}
+
+	return r, nil
+}
+
+// supported returns true if the deployment of client is a replica set or a sharded cluster, a standalone MongoDB has
+// no transactions
+func supported(ctx context.Context, client *mongo.Client) (bool, error) {
+	reply := struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}{}
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&reply); err != nil {
+		return false, err
+	}
+
+	return reply.SetName != "" || reply.Msg == "isdbgrid", nil
+}
+
+// Enabled returns true if Run runs fn in a transaction
+func (r *Runner) Enabled() bool {
+	return r.enabled
+}
+
+// Run runs fn in a transaction, every write made with the ctx passed to fn either fully commits or rolls back. fn runs
+// once, it is never retried, but only a commit with an unknown result is. Side effects that must not happen on rollback,
+// e.g. webhooks or metrics, belong after Run returns. If transactions are disabled fn is run without a transaction.
+func (r *Runner) Run(ctx context.Context, fn func(ctx context.Context) error) error {
+	if !r.enabled {
+		return fn(ctx)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	s, sessCtx, err := r.newSession(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.EndSession(ctx)
+
+	opts := options.Transaction().SetReadConcern(readconcern.Snapshot()).SetWriteConcern(writeconcern.Majority())
+	if err := s.StartTransaction(opts); err != nil {
+		return err
+	}
+
+	if err := fn(sessCtx); err != nil {
+		if abortErr := s.AbortTransaction(ctx); abortErr != nil {
+			return errors.Join(err, abortErr)
+		}
+		return err
+	}
+
+	for {
+		err := s.CommitTransaction(sessCtx)
+		if err == nil || !unknownCommitResult(err) || ctx.Err() != nil {
+			return err
+		}
+	}
+}
+
+// unknownCommitResult returns true if err is a commit error that may be retried
+func unknownCommitResult(err error) bool {
+	var serverErr mongo.ServerError
+	return errors.As(err, &serverErr) && serverErr.HasErrorLabel(labelUnknownCommitResult)
+}
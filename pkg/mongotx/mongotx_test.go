@@ -0,0 +1,114 @@
+package mongotx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mockSession records the transaction calls, commitErrs are returned by the successive commits
+type mockSession struct {
+	started, aborted, commits, ended int
+	commitErrs                       []error
+}
+
+func (m *mockSession) StartTransaction(opts ...*options.TransactionOptions) error {
+	m.started++
+	return nil
+}
+
+func (m *mockSession) AbortTransaction(ctx context.Context) error {
+	m.aborted++
+	return nil
+}
+
+func (m *mockSession) CommitTransaction(ctx context.Context) error {
+	m.commits++
+	if len(m.commitErrs) == 0 {
+		return nil
+	}
+	err := m.commitErrs[0]
+	m.commitErrs = m.commitErrs[1:]
+	return err
+}
+
+func (m *mockSession) EndSession(ctx context.Context) {
+	m.ended++
+}
+
+func mockRunner(enabled bool, s *mockSession) *Runner {
+	return &Runner{
+		enabled: enabled,
+		newSession: func(ctx context.Context) (session, context.Context, error) {
+			return s, ctx, nil
+		},
+	}
+}
+
+func TestRun(t *testing.T) {
+	errFn := errors.New("fn failed")
+	unknownCommit := &mongo.CommandError{Code: 50, Labels: []string{labelUnknownCommitResult}}
+	transient := &mongo.CommandError{Code: 112, Labels: []string{"TransientTransactionError"}}
+
+	tts := []struct {
+		name        string
+		enabled     bool
+		fnErr       error
+		commitErrs  []error
+		wantErr     error
+		wantSession mockSession
+	}{
+		{
+			name:        "commit",
+			enabled:     true,
+			wantSession: mockSession{started: 1, commits: 1, ended: 1},
+		},
+		{
+			name:        "rollback on error",
+			enabled:     true,
+			fnErr:       errFn,
+			wantErr:     errFn,
+			wantSession: mockSession{started: 1, aborted: 1, ended: 1},
+		},
+		{
+			name:        "unknown commit result is retried",
+			enabled:     true,
+			commitErrs:  []error{unknownCommit, unknownCommit},
+			wantSession: mockSession{started: 1, commits: 3, ended: 1},
+		},
+		{
+			name:        "other commit errors are not retried",
+			enabled:     true,
+			commitErrs:  []error{transient},
+			wantErr:     transient,
+			wantSession: mockSession{started: 1, commits: 1, ended: 1},
+		},
+		{
+			name:    "disabled",
+			enabled: false,
+			fnErr:   errFn,
+			wantErr: errFn,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &mockSession{commitErrs: tt.commitErrs}
+
+			calls := 0
+			err := mockRunner(tt.enabled, s).Run(context.Background(), func(ctx context.Context) error {
+				calls++
+				return tt.fnErr
+			})
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, 1, calls, "fn is never retried")
+
+			s.commitErrs = nil
+			assert.Equal(t, tt.wantSession, *s)
+		})
+	}
+}
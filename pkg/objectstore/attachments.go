@@ -0,0 +1,201 @@
+package objectstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// RefPrefix starts the references that replace attachments in document data, followed by the object key
+const RefPrefix = "object:"
+
+var (
+	// ErrNotFound is returned when there is no object with a key
+	ErrNotFound = errors.New("object not found")
+
+	// ErrInvalidKey is returned for a key that is not a content addressed key returned by Key
+	ErrInvalidKey = errors.New("invalid object key")
+
+	// ErrReference is returned by CheckReferences when document data holds a reference
+	ErrReference = errors.New("document data holds an object reference")
+)
+
+// keyPattern matches the keys returned by Key, nothing else may be used to build an object url
+var keyPattern = regexp.MustCompile(`^sha256/[0-9a-f]{64}$`)
+
+// Store keeps attachments as objects
+type Store interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) ([]byte, string, error)
+	PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// Key returns the content addressed key of data, storing the same attachment twice keeps one object
+func Key(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256/" + hex.EncodeToString(sum[:])
+}
+
+// ValidKey returns true if key is a content addressed key returned by Key
+func ValidKey(key string) bool {
+	return keyPattern.MatchString(key)
+}
+
+// parseRef returns the key of the reference s, ok is false if s is not a reference
+func parseRef(s string) (string, bool, error) {
+	key, ok := strings.CutPrefix(s, RefPrefix)
+	if !ok {
+		return "", false, nil
+	}
+	if !ValidKey(key) {
+		return "", true, fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return key, true, nil
+}
+
+// CheckReferences returns ErrReference if any string in documentData is a reference. References are only created by
+// Extract, document data from clients must not hold them or it could read attachments of other documents.
+func CheckReferences(documentData any) error {
+	return replaceStrings(documentData, func(s string) (string, error) {
+		if strings.HasPrefix(s, RefPrefix) {
+			return "", ErrReference
+		}
+		return s, nil
+	})
+}
+
+// parseDataURI returns the content type and data of a base64 encoded RFC 2397 data uri
+func parseDataURI(s string) (string, []byte, bool) {
+	rest, ok := strings.CutPrefix(s, "data:")
+	if !ok {
+		return "", nil, false
+	}
+	header, encoded, ok := strings.Cut(rest, ",")
+	if !ok {
+		return "", nil, false
+	}
+	contentType, ok := strings.CutSuffix(header, ";base64")
+	if !ok {
+		return "", nil, false
+	}
+	if contentType == "" {
+		contentType = "text/plain;charset=US-ASCII"
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", nil, false
+	}
+
+	return contentType, data, true
+}
+
+// Extract moves the base64 data uris in documentData of at least minSize bytes to store, and replaces them in place
+// with references. It returns the number of attachments moved.
+func Extract(ctx context.Context, store Store, documentData any, minSize int) (int, error) {
+	moved := 0
+	err := replaceStrings(documentData, func(s string) (string, error) {
+		contentType, data, ok := parseDataURI(s)
+		if !ok || len(data) < minSize {
+			return s, nil
+		}
+
+		key := Key(data)
+		if err := store.Put(ctx, key, data, contentType); err != nil {
+			return "", err
+		}
+		moved++
+
+		return RefPrefix + key, nil
+	})
+
+	return moved, err
+}
+
+// Inline replaces the references in documentData in place with the data uris of their attachments
+func Inline(ctx context.Context, store Store, documentData any) error {
+	return replaceStrings(documentData, func(s string) (string, error) {
+		key, ok, err := parseRef(s)
+		if err != nil || !ok {
+			return s, err
+		}
+
+		data, contentType, err := store.Get(ctx, key)
+		if err != nil {
+			return "", err
+		}
+
+		return "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+	})
+}
+
+// Presign replaces the references in documentData in place with urls their attachments can be downloaded from
+// during ttl
+func Presign(ctx context.Context, store Store, documentData any, ttl time.Duration) error {
+	return replaceStrings(documentData, func(s string) (string, error) {
+		key, ok, err := parseRef(s)
+		if err != nil || !ok {
+			return s, err
+		}
+
+		return store.PresignGet(ctx, key, ttl)
+	})
+}
+
+// replaceStrings replaces the strings in the maps and slices of v with the result of fn. Maps and slices of any type
+// are handled, e.g. the primitive.A arrays of documents decoded from MongoDB.
+func replaceStrings(v any, fn func(string) (string, error)) error {
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String || rv.Type().Elem().Kind() != reflect.Interface {
+			return nil
+		}
+		iter := rv.MapRange()
+		for iter.Next() {
+			replaced, changed, err := replaceValue(iter.Value().Interface(), fn)
+			if err != nil {
+				return err
+			}
+			if changed {
+				rv.SetMapIndex(iter.Key(), reflect.ValueOf(replaced))
+			}
+		}
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() != reflect.Interface {
+			return nil
+		}
+		for i := 0; i < rv.Len(); i++ {
+			replaced, changed, err := replaceValue(rv.Index(i).Interface(), fn)
+			if err != nil {
+				return err
+			}
+			if changed {
+				rv.Index(i).Set(reflect.ValueOf(replaced))
+			}
+		}
+	}
+
+	return nil
+}
+
+func replaceValue(v any, fn func(string) (string, error)) (string, bool, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", false, replaceStrings(v, fn)
+	}
+
+	replaced, err := fn(s)
+	if err != nil {
+		return "", false, err
+	}
+	return replaced, replaced != s, nil
+}
@@ -0,0 +1,122 @@
+package objectstore
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryStore map[string]memoryObject
+
+type memoryObject struct {
+	data        []byte
+	contentType string
+}
+
+func (m memoryStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	m[key] = memoryObject{data: data, contentType: contentType}
+	return nil
+}
+
+func (m memoryStore) Get(ctx context.Context, key string) ([]byte, string, error) {
+	object, ok := m[key]
+	if !ok {
+		return nil, "", ErrNotFound
+	}
+	return object.data, object.contentType, nil
+}
+
+func (m memoryStore) PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return "https://objects.example.com/" + key + "?ttl=" + ttl.String(), nil
+}
+
+func TestExtractInline(t *testing.T) {
+	ctx := context.Background()
+	portrait := "data:image/jpeg;base64," + base64.StdEncoding.EncodeToString([]byte(strings.Repeat("p", 64)))
+	small := "data:image/png;base64," + base64.StdEncoding.EncodeToString([]byte("s"))
+
+	documentData := map[string]any{
+		"given_name": "Magnus",
+		"portrait":   portrait,
+		"icon":       small,
+		"signatures": primitive.A{
+			map[string]any{"image": portrait},
+			"data:,not base64",
+		},
+	}
+
+	store := memoryStore{}
+	n, err := Extract(ctx, store, documentData, 16)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Len(t, store, 1, "the same attachment is stored once")
+
+	ref := RefPrefix + Key([]byte(strings.Repeat("p", 64)))
+	assert.Equal(t, ref, documentData["portrait"])
+	assert.Equal(t, ref, documentData["signatures"].(primitive.A)[0].(map[string]any)["image"])
+	assert.Equal(t, small, documentData["icon"])
+	assert.Equal(t, "data:,not base64", documentData["signatures"].(primitive.A)[1])
+	assert.Equal(t, "Magnus", documentData["given_name"])
+
+	assert.NoError(t, Presign(ctx, store, documentData, time.Minute))
+	assert.Equal(t, "https://objects.example.com/"+Key([]byte(strings.Repeat("p", 64)))+"?ttl=1m0s", documentData["portrait"])
+
+	documentData["portrait"] = ref
+	assert.NoError(t, Inline(ctx, store, documentData))
+	assert.Equal(t, portrait, documentData["portrait"])
+}
+
+func TestInlineNotFound(t *testing.T) {
+	documentData := map[string]any{"portrait": RefPrefix + Key([]byte("unknown"))}
+	assert.ErrorIs(t, Inline(context.Background(), memoryStore{}, documentData), ErrNotFound)
+}
+
+func TestInvalidReference(t *testing.T) {
+	ctx := context.Background()
+	store := memoryStore{Key([]byte("portrait")): {data: []byte("portrait"), contentType: "image/jpeg"}}
+
+	for _, ref := range []string{
+		RefPrefix + "sha256/unknown",
+		RefPrefix + "sha256/" + strings.Repeat("A", 64),
+		RefPrefix + "../other-bucket/" + Key([]byte("portrait")),
+		RefPrefix,
+	} {
+		documentData := map[string]any{"portrait": ref}
+		assert.ErrorIs(t, Inline(ctx, store, documentData), ErrInvalidKey, ref)
+		assert.ErrorIs(t, Presign(ctx, store, documentData, time.Minute), ErrInvalidKey, ref)
+	}
+}
+
+func TestCheckReferences(t *testing.T) {
+	tts := []struct {
+		name         string
+		documentData any
+		wantErr      error
+	}{
+		{
+			name:         "no references",
+			documentData: map[string]any{"given_name": "Magnus", "portrait": "data:image/jpeg;base64,cA=="},
+		},
+		{
+			name:         "reference",
+			documentData: map[string]any{"portrait": RefPrefix + Key([]byte("portrait"))},
+			wantErr:      ErrReference,
+		},
+		{
+			name:         "nested reference",
+			documentData: map[string]any{"signatures": primitive.A{map[string]any{"image": RefPrefix + "sha256/x"}}},
+			wantErr:      ErrReference,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, CheckReferences(tt.documentData), tt.wantErr)
+		})
+	}
+}
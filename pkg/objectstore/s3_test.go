@@ -0,0 +1,89 @@
+package objectstore
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+	"vc/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestS3(t *testing.T) {
+	objects := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=access/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			objects[r.URL.Path] = string(body)
+		case http.MethodGet:
+			body, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", "image/jpeg")
+			_, _ = io.WriteString(w, body)
+		}
+	}))
+	defer server.Close()
+
+	s3, err := NewS3(&model.ObjectStore{
+		Endpoint:        server.URL,
+		Region:          "us-east-1",
+		Bucket:          "attachments",
+		AccessKeyID:     "access",
+		SecretAccessKey: "secret",
+		PathStyle:       true,
+	})
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	key := Key([]byte("portrait"))
+	assert.NoError(t, s3.Put(ctx, key, []byte("portrait"), "image/jpeg"))
+	assert.Contains(t, objects, "/attachments/"+key)
+
+	data, contentType, err := s3.Get(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, "portrait", string(data))
+	assert.Equal(t, "image/jpeg", contentType)
+
+	_, _, err = s3.Get(ctx, Key([]byte("unknown")))
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	for _, invalid := range []string{"sha256/unknown", "sha256/../../other/" + strings.Repeat("0", 64), "../other/key", ""} {
+		_, _, err = s3.Get(ctx, invalid)
+		assert.ErrorIs(t, err, ErrInvalidKey, invalid)
+		_, err = s3.PresignGet(ctx, invalid, time.Minute)
+		assert.ErrorIs(t, err, ErrInvalidKey, invalid)
+	}
+
+	presigned, err := s3.PresignGet(ctx, key, 15*time.Minute)
+	assert.NoError(t, err)
+	u, err := url.Parse(presigned)
+	assert.NoError(t, err)
+	assert.Equal(t, "/attachments/"+key, u.Path)
+	assert.Equal(t, "900", u.Query().Get("X-Amz-Expires"))
+	assert.NotEmpty(t, u.Query().Get("X-Amz-Signature"))
+}
+
+func TestS3VirtualHosted(t *testing.T) {
+	s3, err := NewS3(&model.ObjectStore{Endpoint: "https://s3.eu-north-1.amazonaws.com", Bucket: "attachments"})
+	assert.NoError(t, err)
+	key := Key([]byte("portrait"))
+	u, err := s3.objectURL(key)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://attachments.s3.eu-north-1.amazonaws.com/"+key, u.String())
+
+	_, err = NewS3(&model.ObjectStore{Endpoint: "s3.eu-north-1.amazonaws.com", Bucket: "attachments"})
+	assert.Error(t, err)
+}
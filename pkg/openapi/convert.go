@@ -0,0 +1,349 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/swaggo/swag"
+)
+
+// Version is the OpenAPI version of converted specs
+const Version = "3.1.0"
+
+// operationMethods are the path item keys of operations, in spec order
+var operationMethods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+// schemaKeys are the Swagger 2.0 parameter keys that describe its value, they become the schema of the parameter
+var schemaKeys = []string{
+	"type", "format", "items", "enum", "default", "maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum",
+	"maxLength", "minLength", "pattern", "maxItems", "minItems", "uniqueItems", "multipleOf",
+}
+
+// Registered returns the swag doc registered by the imported docs package, e.g. vc/docs/apigw, as OpenAPI 3.1
+func Registered() ([]byte, error) {
+	doc, err := swag.ReadDoc()
+	if err != nil {
+		return nil, err
+	}
+
+	return Convert([]byte(doc))
+}
+
+// Convert converts a Swagger 2.0 spec in JSON to OpenAPI 3.1
+func Convert(swagger []byte) ([]byte, error) {
+	src := map[string]any{}
+	if err := json.Unmarshal(swagger, &src); err != nil {
+		return nil, err
+	}
+	if v, _ := src["swagger"].(string); v != "2.0" {
+		return nil, fmt.Errorf("unsupported swagger version %q", v)
+	}
+
+	dst := map[string]any{
+		"openapi": Version,
+		"info":    src["info"],
+		"servers": []any{map[string]any{"url": serverURL(src)}},
+	}
+	for _, key := range []string{"tags", "security", "externalDocs"} {
+		if v, ok := src[key]; ok {
+			dst[key] = v
+		}
+	}
+
+	consumes := stringList(src["consumes"], "application/json")
+	produces := stringList(src["produces"], "application/json")
+
+	paths := map[string]any{}
+	srcPaths, _ := src["paths"].(map[string]any)
+	for path, v := range srcPaths {
+		item, _ := v.(map[string]any)
+		paths[path] = convertPathItem(item, consumes, produces)
+	}
+	dst["paths"] = paths
+
+	components := map[string]any{}
+	if definitions, ok := src["definitions"].(map[string]any); ok {
+		schemas := map[string]any{}
+		for name, schema := range definitions {
+			schemas[name] = convertSchema(schema)
+		}
+		components["schemas"] = schemas
+	}
+	if securityDefinitions, ok := src["securityDefinitions"].(map[string]any); ok {
+		schemes := map[string]any{}
+		for name, v := range securityDefinitions {
+			scheme, _ := v.(map[string]any)
+			schemes[name] = convertSecurityScheme(scheme)
+		}
+		components["securitySchemes"] = schemes
+	}
+	if len(components) > 0 {
+		dst["components"] = components
+	}
+
+	return json.Marshal(dst)
+}
+
+// serverURL is the URL of the only server, relative to where the spec is served from if the spec has no host
+func serverURL(src map[string]any) string {
+	basePath, _ := src["basePath"].(string)
+	if basePath == "" {
+		basePath = "/"
+	}
+
+	host, _ := src["host"].(string)
+	if host == "" {
+		return basePath
+	}
+
+	scheme := "https"
+	if schemes := stringList(src["schemes"]); len(schemes) > 0 {
+		scheme = schemes[0]
+	}
+	return scheme + "://" + host + basePath
+}
+
+func convertPathItem(item map[string]any, consumes, produces []string) map[string]any {
+	// parameters of the path item apply to all its operations
+	shared, _ := item["parameters"].([]any)
+
+	dst := map[string]any{}
+	for _, method := range operationMethods {
+		op, ok := item[method].(map[string]any)
+		if !ok {
+			continue
+		}
+		dst[method] = convertOperation(op, shared, consumes, produces)
+	}
+
+	return dst
+}
+
+func convertOperation(op map[string]any, shared []any, consumes, produces []string) map[string]any {
+	consumes = stringList(op["consumes"], consumes...)
+	produces = stringList(op["produces"], produces...)
+
+	dst := map[string]any{}
+	for _, key := range []string{"tags", "summary", "description", "operationId", "deprecated", "security", "externalDocs"} {
+		if v, ok := op[key]; ok {
+			dst[key] = v
+		}
+	}
+
+	params, _ := op["parameters"].([]any)
+	parameters := []any{}
+	form := map[string]any{"type": "object", "properties": map[string]any{}}
+	formRequired := []any{}
+	hasFile := false
+
+	for _, v := range append(append([]any{}, shared...), params...) {
+		param, _ := v.(map[string]any)
+		required, _ := param["required"].(bool)
+
+		switch param["in"] {
+		case "body":
+			body := map[string]any{
+				"content":  mediaTypes(consumes, convertSchema(param["schema"])),
+				"required": required,
+			}
+			if description, ok := param["description"].(string); ok && strings.TrimSpace(description) != "" {
+				body["description"] = description
+			}
+			dst["requestBody"] = body
+
+		case "formData":
+			schema := parameterSchema(param)
+			if schema["type"] == "file" {
+				hasFile = true
+				schema = map[string]any{"type": "string", "contentMediaType": "application/octet-stream"}
+			}
+			if description, ok := param["description"]; ok {
+				schema["description"] = description
+			}
+			form["properties"].(map[string]any)[param["name"].(string)] = schema
+			if required {
+				formRequired = append(formRequired, param["name"])
+			}
+
+		default:
+			dstParam := map[string]any{
+				"name":   param["name"],
+				"in":     param["in"],
+				"schema": parameterSchema(param),
+			}
+			if description, ok := param["description"]; ok {
+				dstParam["description"] = description
+			}
+			// path parameters are always required
+			if required || param["in"] == "path" {
+				dstParam["required"] = true
+			}
+			parameters = append(parameters, dstParam)
+		}
+	}
+
+	if len(parameters) > 0 {
+		dst["parameters"] = parameters
+	}
+
+	if len(form["properties"].(map[string]any)) > 0 {
+		if len(formRequired) > 0 {
+			form["required"] = formRequired
+		}
+		formTypes := []string{}
+		for _, t := range consumes {
+			if t == "multipart/form-data" || t == "application/x-www-form-urlencoded" {
+				formTypes = append(formTypes, t)
+			}
+		}
+		if len(formTypes) == 0 || hasFile {
+			formTypes = []string{"multipart/form-data"}
+		}
+		dst["requestBody"] = map[string]any{"content": mediaTypes(formTypes, form)}
+	}
+
+	responses := map[string]any{}
+	srcResponses, _ := op["responses"].(map[string]any)
+	for code, v := range srcResponses {
+		response, _ := v.(map[string]any)
+		responses[code] = convertResponse(response, produces)
+	}
+	dst["responses"] = responses
+
+	return dst
+}
+
+func convertResponse(response map[string]any, produces []string) map[string]any {
+	description, _ := response["description"].(string)
+	dst := map[string]any{"description": description}
+
+	if schema, ok := response["schema"]; ok {
+		dst["content"] = mediaTypes(produces, convertSchema(schema))
+	}
+
+	if headers, ok := response["headers"].(map[string]any); ok {
+		dstHeaders := map[string]any{}
+		for name, v := range headers {
+			header, _ := v.(map[string]any)
+			dstHeader := map[string]any{"schema": parameterSchema(header)}
+			if description, ok := header["description"]; ok {
+				dstHeader["description"] = description
+			}
+			dstHeaders[name] = dstHeader
+		}
+		dst["headers"] = dstHeaders
+	}
+
+	return dst
+}
+
+// convertSchema rewrites the definition references and nullable extension of a Swagger 2.0 schema
+func convertSchema(schema any) any {
+	switch s := schema.(type) {
+	case map[string]any:
+		dst := map[string]any{}
+		for k, v := range s {
+			switch k {
+			case "$ref":
+				ref, _ := v.(string)
+				dst[k] = strings.Replace(ref, "#/definitions/", "#/components/schemas/", 1)
+			case "x-nullable":
+			default:
+				dst[k] = convertSchema(v)
+			}
+		}
+		if nullable, _ := s["x-nullable"].(bool); nullable {
+			if t, ok := dst["type"].(string); ok {
+				dst["type"] = []any{t, "null"}
+			}
+		}
+		return dst
+	case []any:
+		dst := make([]any, len(s))
+		for i, v := range s {
+			dst[i] = convertSchema(v)
+		}
+		return dst
+	default:
+		return s
+	}
+}
+
+// parameterSchema returns the schema of a non-body parameter or a header
+func parameterSchema(param map[string]any) map[string]any {
+	schema := map[string]any{}
+	for _, key := range schemaKeys {
+		if v, ok := param[key]; ok {
+			schema[key] = convertSchema(v)
+		}
+	}
+	if items, ok := schema["items"].(map[string]any); ok {
+		schema["items"] = parameterSchema(items)
+	}
+
+	return schema
+}
+
+func convertSecurityScheme(scheme map[string]any) map[string]any {
+	dst := map[string]any{}
+	if description, ok := scheme["description"]; ok {
+		dst["description"] = description
+	}
+
+	switch scheme["type"] {
+	case "basic":
+		dst["type"] = "http"
+		dst["scheme"] = "basic"
+	case "apiKey":
+		dst["type"] = "apiKey"
+		dst["name"] = scheme["name"]
+		dst["in"] = scheme["in"]
+	case "oauth2":
+		flow := map[string]any{"scopes": scheme["scopes"]}
+		if flow["scopes"] == nil {
+			flow["scopes"] = map[string]any{}
+		}
+		for _, key := range []string{"authorizationUrl", "tokenUrl"} {
+			if v, ok := scheme[key]; ok {
+				flow[key] = v
+			}
+		}
+		flows := map[string]string{
+			"implicit":    "implicit",
+			"password":    "password",
+			"application": "clientCredentials",
+			"accessCode":  "authorizationCode",
+		}
+		flowName, _ := scheme["flow"].(string)
+		dst["type"] = "oauth2"
+		dst["flows"] = map[string]any{flows[flowName]: flow}
+	}
+
+	return dst
+}
+
+// mediaTypes returns a content map with schema for each of the media types
+func mediaTypes(types []string, schema any) map[string]any {
+	content := map[string]any{}
+	for _, t := range types {
+		content[t] = map[string]any{"schema": schema}
+	}
+	return content
+}
+
+// stringList returns v as strings, or fallback if v is empty
+func stringList(v any, fallback ...string) []string {
+	list, _ := v.([]any)
+	if len(list) == 0 {
+		return fallback
+	}
+
+	strs := make([]string, 0, len(list))
+	for _, s := range list {
+		if str, ok := s.(string); ok {
+			strs = append(strs, str)
+		}
+	}
+	return strs
+}
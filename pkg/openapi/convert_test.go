@@ -0,0 +1,163 @@
+package openapi
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const mockSwagger = `{
+	"swagger": "2.0",
+	"info": {"title": "Test API", "version": "0.1.0"},
+	"basePath": "/api/v1",
+	"paths": {
+		"/document/{id}": {
+			"post": {
+				"consumes": ["application/json"],
+				"produces": ["application/json"],
+				"tags": ["test"],
+				"operationId": "test-document",
+				"parameters": [
+					{"type": "string", "name": "id", "in": "path"},
+					{"type": "integer", "name": "limit", "in": "query", "description": "Limit"},
+					{"type": "array", "items": {"type": "string"}, "name": "field", "in": "query"},
+					{"description": " ", "name": "req", "in": "body", "required": true, "schema": {"$ref": "#/definitions/apiv1.Request"}}
+				],
+				"responses": {
+					"200": {"description": "Success", "schema": {"$ref": "#/definitions/apiv1.Reply"}},
+					"400": {"description": "Bad Request", "headers": {"Retry-After": {"type": "integer", "description": "Seconds"}}}
+				}
+			}
+		},
+		"/upload": {
+			"put": {
+				"consumes": ["multipart/form-data"],
+				"parameters": [{"type": "file", "name": "file", "in": "formData", "required": true}],
+				"responses": {"204": {"description": "No Content"}}
+			}
+		}
+	},
+	"definitions": {
+		"apiv1.Request": {"type": "object", "properties": {"reply": {"$ref": "#/definitions/apiv1.Reply"}}},
+		"apiv1.Reply": {"type": "object", "properties": {"note": {"type": "string", "x-nullable": true}}}
+	},
+	"securityDefinitions": {
+		"BasicAuth": {"type": "basic"},
+		"OAuth": {"type": "oauth2", "flow": "application", "tokenUrl": "https://example.com/token"}
+	}
+}`
+
+func mockConvert(t *testing.T, swagger string) map[string]any {
+	b, err := Convert([]byte(swagger))
+	assert.NoError(t, err)
+
+	doc := map[string]any{}
+	assert.NoError(t, json.Unmarshal(b, &doc))
+	return doc
+}
+
+func TestConvert(t *testing.T) {
+	doc := mockConvert(t, mockSwagger)
+	operation := doc["paths"].(map[string]any)["/document/{id}"].(map[string]any)["post"].(map[string]any)
+
+	tts := []struct {
+		name string
+		have any
+		want string
+	}{
+		{
+			name: "version",
+			have: doc["openapi"],
+			want: `"3.1.0"`,
+		},
+		{
+			name: "servers",
+			have: doc["servers"],
+			want: `[{"url": "/api/v1"}]`,
+		},
+		{
+			name: "parameters",
+			have: operation["parameters"],
+			want: `[
+				{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}},
+				{"name": "limit", "in": "query", "description": "Limit", "schema": {"type": "integer"}},
+				{"name": "field", "in": "query", "schema": {"type": "array", "items": {"type": "string"}}}
+			]`,
+		},
+		{
+			name: "request body",
+			have: operation["requestBody"],
+			want: `{"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/apiv1.Request"}}}}`,
+		},
+		{
+			name: "responses",
+			have: operation["responses"],
+			want: `{
+				"200": {"description": "Success", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/apiv1.Reply"}}}},
+				"400": {"description": "Bad Request", "headers": {"Retry-After": {"description": "Seconds", "schema": {"type": "integer"}}}}
+			}`,
+		},
+		{
+			name: "form data",
+			have: doc["paths"].(map[string]any)["/upload"],
+			want: `{"put": {
+				"requestBody": {"content": {"multipart/form-data": {"schema": {
+					"type": "object",
+					"properties": {"file": {"type": "string", "contentMediaType": "application/octet-stream"}},
+					"required": ["file"]
+				}}}},
+				"responses": {"204": {"description": "No Content"}}
+			}}`,
+		},
+		{
+			name: "schemas",
+			have: doc["components"].(map[string]any)["schemas"],
+			want: `{
+				"apiv1.Request": {"type": "object", "properties": {"reply": {"$ref": "#/components/schemas/apiv1.Reply"}}},
+				"apiv1.Reply": {"type": "object", "properties": {"note": {"type": ["string", "null"]}}}
+			}`,
+		},
+		{
+			name: "security schemes",
+			have: doc["components"].(map[string]any)["securitySchemes"],
+			want: `{
+				"BasicAuth": {"type": "http", "scheme": "basic"},
+				"OAuth": {"type": "oauth2", "flows": {"clientCredentials": {"tokenUrl": "https://example.com/token", "scopes": {}}}}
+			}`,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			have, err := json.Marshal(tt.have)
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(have))
+		})
+	}
+}
+
+func TestConvertVersion(t *testing.T) {
+	_, err := Convert([]byte(`{"openapi": "3.0.0"}`))
+	assert.Error(t, err)
+}
+
+func TestConvertDocs(t *testing.T) {
+	files, err := filepath.Glob("../../docs/*/swagger.json")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, files)
+
+	for _, file := range files {
+		t.Run(file, func(t *testing.T) {
+			swagger, err := os.ReadFile(file)
+			assert.NoError(t, err)
+
+			b, err := Convert(swagger)
+			assert.NoError(t, err)
+			assert.False(t, strings.Contains(string(b), "#/definitions/"), "all references are rewritten")
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Drift compares the operations of an OpenAPI 3 spec with the routes of a gin engine, it returns one line per difference.
+//
+// A documented path is matched under the path of the first server, or at the root for endpoints like /.well-known/jwks.json
+// that a service registers outside its base path. Routes outside the base path only need to be documented if they are not
+// in ignore, given as "GET /health".
+func Drift(spec []byte, routes gin.RoutesInfo, ignore ...string) ([]string, error) {
+	doc := struct {
+		Servers []struct {
+			URL string `json:"url"`
+		} `json:"servers"`
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}{}
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Servers) == 0 {
+		return nil, errors.New("spec has no servers")
+	}
+
+	u, err := url.Parse(doc.Servers[0].URL)
+	if err != nil {
+		return nil, err
+	}
+	basePath := strings.TrimSuffix(u.Path, "/")
+
+	registered := map[string]bool{}
+	for _, route := range routes {
+		registered[route.Method+" "+specPath(route.Path)] = false
+	}
+
+	drift := []string{}
+	for path, item := range doc.Paths {
+		for method := range item {
+			if !isOperation(method) {
+				continue
+			}
+			method = strings.ToUpper(method)
+
+			route := method + " " + basePath + path
+			if _, ok := registered[route]; !ok {
+				route = method + " " + path
+			}
+			if _, ok := registered[route]; !ok {
+				drift = append(drift, method+" "+basePath+path+" is documented but not routed")
+				continue
+			}
+			registered[route] = true
+		}
+	}
+
+	ignored := map[string]bool{}
+	for _, route := range ignore {
+		method, path, _ := strings.Cut(route, " ")
+		ignored[method+" "+specPath(path)] = true
+	}
+	for route, documented := range registered {
+		if documented || ignored[route] {
+			continue
+		}
+		_, path, _ := strings.Cut(route, " ")
+		if basePath == "" || strings.HasPrefix(path, basePath+"/") {
+			drift = append(drift, route+" is routed but not documented")
+		}
+	}
+
+	sort.Strings(drift)
+	return drift, nil
+}
+
+// specPath rewrites the :param and *param segments of a gin path to {param}
+func specPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func isOperation(method string) bool {
+	for _, m := range operationMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package openapi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrift(t *testing.T) {
+	spec := `{
+		"openapi": "3.1.0",
+		"servers": [{"url": "/api/v1"}],
+		"paths": {
+			"/document": {"post": {}, "delete": {}},
+			"/session/{session_id}": {"get": {}, "parameters": []},
+			"/.well-known/jwks.json": {"get": {}}
+		}
+	}`
+
+	routes := gin.RoutesInfo{
+		{Method: http.MethodGet, Path: "/health"},
+		{Method: http.MethodGet, Path: "/swagger/*any"},
+		{Method: http.MethodGet, Path: "/.well-known/jwks.json"},
+		{Method: http.MethodPost, Path: "/api/v1/document"},
+		{Method: http.MethodGet, Path: "/api/v1/session/:session_id"},
+	}
+
+	tts := []struct {
+		name   string
+		routes gin.RoutesInfo
+		ignore []string
+		want   []string
+	}{
+		{
+			name:   "documented but not routed",
+			routes: routes,
+			want:   []string{"DELETE /api/v1/document is documented but not routed"},
+		},
+		{
+			name:   "undocumented route",
+			routes: append(routes, gin.RouteInfo{Method: http.MethodDelete, Path: "/api/v1/document"}, gin.RouteInfo{Method: http.MethodPost, Path: "/api/v1/upload"}),
+			want:   []string{"POST /api/v1/upload is routed but not documented"},
+		},
+		{
+			name:   "ignored",
+			routes: append(routes, gin.RouteInfo{Method: http.MethodDelete, Path: "/api/v1/document"}, gin.RouteInfo{Method: http.MethodGet, Path: "/api/v1/internal/*path"}),
+			ignore: []string{"GET /api/v1/internal/*path"},
+			want:   []string{},
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Drift([]byte(spec), tt.routes, tt.ignore...)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDriftRootBasePath(t *testing.T) {
+	spec := `{"openapi": "3.1.0", "servers": [{"url": "/"}], "paths": {"/statuslists/{purpose}": {"get": {}}}}`
+
+	routes := gin.RoutesInfo{
+		{Method: http.MethodGet, Path: "/health"},
+		{Method: http.MethodGet, Path: "/statuslists/:purpose"},
+		{Method: http.MethodPost, Path: "/inclusion_proof"},
+	}
+
+	got, err := Drift([]byte(spec), routes, "GET /health")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"POST /inclusion_proof is routed but not documented"}, got)
+}
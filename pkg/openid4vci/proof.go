@@ -0,0 +1,98 @@
+package openid4vci
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"vc/pkg/keyresolver"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lestrrat-go/jwx/jwk"
+)
+
+const (
+	// ProofTypeJWT is the proof_type of a proof of possession JWT
+	ProofTypeJWT = "jwt"
+
+	// typeProofJWT is the typ of the proof of possession JWT
+	typeProofJWT = "openid4vci-proof+jwt"
+
+	// clockSkew is the time a proof may be issued in the future
+	clockSkew = 30 * time.Second
+
+	// maxAge is the time a proof is accepted after it was issued
+	maxAge = 5 * time.Minute
+)
+
+// ErrInvalidProof is returned when the proof of possession of the holder key does not verify
+var ErrInvalidProof = errors.New("invalid proof")
+
+// signingMethods are the asymmetric algorithms a proof may be signed with
+var signingMethods = []string{"ES256", "ES384", "ES512", "EdDSA", "RS256", "RS384", "RS512", "PS256", "PS384", "PS512"}
+
+// Proof is the OpenID4VCI proof of possession of the key the credential is bound to, OpenID4VCI 1.0 section 8.2.1
+type Proof struct {
+	ProofType string `json:"proof_type" validate:"required,oneof=jwt"`
+	JWT       string `json:"jwt" validate:"required"`
+}
+
+// HolderKey verifies the proof and returns the holder public key as a JWK. The key is taken from the jwk header of the
+// proof, or resolved from its kid with resolver, e.g. a did:jwk or did:key. The proof must be addressed to audience, the
+// credential issuer identifier, and be issued within the last five minutes.
+func (p *Proof) HolderKey(ctx context.Context, resolver keyresolver.Resolver, audience string) (jwk.Key, error) {
+	if p.ProofType != ProofTypeJWT {
+		return nil, fmt.Errorf("%w: proof_type %q", ErrInvalidProof, p.ProofType)
+	}
+
+	var holderKey any
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(p.JWT, claims, func(token *jwt.Token) (any, error) {
+		if typ, _ := token.Header["typ"].(string); typ != typeProofJWT {
+			return nil, fmt.Errorf("typ %q", typ)
+		}
+
+		header, hasJWK := token.Header["jwk"]
+		kid, _ := token.Header["kid"].(string)
+		switch {
+		case hasJWK && kid != "":
+			return nil, errors.New("jwk and kid are mutually exclusive")
+		case hasJWK:
+			b, err := json.Marshal(header)
+			if err != nil {
+				return nil, err
+			}
+			key, err := jwk.ParseKey(b)
+			if err != nil {
+				return nil, err
+			}
+			// the holder key must not carry private key material
+			if _, ok := key.(interface{ D() []byte }); ok {
+				return nil, errors.New("jwk is a private key")
+			}
+			if err := key.Raw(&holderKey); err != nil {
+				return nil, err
+			}
+		case kid != "" && resolver != nil:
+			key, err := resolver.Resolve(ctx, kid)
+			if err != nil {
+				return nil, err
+			}
+			holderKey = key
+		default:
+			return nil, errors.New("no holder key")
+		}
+
+		return holderKey, nil
+	}, jwt.WithValidMethods(signingMethods), jwt.WithAudience(audience), jwt.WithIssuedAt(), jwt.WithLeeway(clockSkew))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProof, err)
+	}
+
+	if claims.IssuedAt == nil || time.Since(claims.IssuedAt.Time) > maxAge {
+		return nil, fmt.Errorf("%w: iat is missing or too old", ErrInvalidProof)
+	}
+
+	return jwk.New(holderKey)
+}
@@ -0,0 +1,148 @@
+package openid4vci
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+	"time"
+	"vc/pkg/keyresolver"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/stretchr/testify/assert"
+)
+
+const audience = "https://issuer.example.com"
+
+func mockProof(t *testing.T, key *ecdsa.PrivateKey, header map[string]any, claims jwt.MapClaims) *Proof {
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	for k, v := range header {
+		token.Header[k] = v
+	}
+	signed, err := token.SignedString(key)
+	assert.NoError(t, err)
+
+	return &Proof{ProofType: ProofTypeJWT, JWT: signed}
+}
+
+func TestHolderKey(t *testing.T) {
+	holder, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	publicJWK, err := jwk.New(holder.Public())
+	assert.NoError(t, err)
+	privateJWK, err := jwk.New(holder)
+	assert.NoError(t, err)
+	didJWK, err := keyresolver.EncodeDIDJWK(holder.Public())
+	assert.NoError(t, err)
+
+	claims := func(aud string, iat time.Time) jwt.MapClaims {
+		return jwt.MapClaims{"aud": aud, "iat": iat.Unix(), "nonce": "n"}
+	}
+
+	tts := []struct {
+		name    string
+		key     *ecdsa.PrivateKey
+		header  map[string]any
+		claims  jwt.MapClaims
+		wantErr bool
+	}{
+		{
+			name:   "jwk header",
+			key:    holder,
+			header: map[string]any{"typ": typeProofJWT, "jwk": publicJWK},
+			claims: claims(audience, time.Now()),
+		},
+		{
+			name:   "did:jwk kid",
+			key:    holder,
+			header: map[string]any{"typ": typeProofJWT, "kid": didJWK + "#0"},
+			claims: claims(audience, time.Now()),
+		},
+		{
+			name:    "signed with another key",
+			key:     other,
+			header:  map[string]any{"typ": typeProofJWT, "jwk": publicJWK},
+			claims:  claims(audience, time.Now()),
+			wantErr: true,
+		},
+		{
+			name:    "wrong typ",
+			key:     holder,
+			header:  map[string]any{"typ": "JWT", "jwk": publicJWK},
+			claims:  claims(audience, time.Now()),
+			wantErr: true,
+		},
+		{
+			name:    "wrong audience",
+			key:     holder,
+			header:  map[string]any{"typ": typeProofJWT, "jwk": publicJWK},
+			claims:  claims("https://other.example.com", time.Now()),
+			wantErr: true,
+		},
+		{
+			name:    "too old",
+			key:     holder,
+			header:  map[string]any{"typ": typeProofJWT, "jwk": publicJWK},
+			claims:  claims(audience, time.Now().Add(-10*time.Minute)),
+			wantErr: true,
+		},
+		{
+			name:    "without iat",
+			key:     holder,
+			header:  map[string]any{"typ": typeProofJWT, "jwk": publicJWK},
+			claims:  jwt.MapClaims{"aud": audience},
+			wantErr: true,
+		},
+		{
+			name:    "private jwk",
+			key:     holder,
+			header:  map[string]any{"typ": typeProofJWT, "jwk": privateJWK},
+			claims:  claims(audience, time.Now()),
+			wantErr: true,
+		},
+		{
+			name:    "jwk and kid",
+			key:     holder,
+			header:  map[string]any{"typ": typeProofJWT, "jwk": publicJWK, "kid": didJWK},
+			claims:  claims(audience, time.Now()),
+			wantErr: true,
+		},
+		{
+			name:    "no key",
+			key:     holder,
+			header:  map[string]any{"typ": typeProofJWT},
+			claims:  claims(audience, time.Now()),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			proof := mockProof(t, tt.key, tt.header, tt.claims)
+
+			got, err := proof.HolderKey(context.Background(), keyresolver.DIDJWK{}, audience)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidProof)
+				return
+			}
+			assert.NoError(t, err)
+
+			want, err := json.Marshal(publicJWK)
+			assert.NoError(t, err)
+			b, err := json.Marshal(got)
+			assert.NoError(t, err)
+			assert.JSONEq(t, string(want), string(b))
+		})
+	}
+}
+
+func TestHolderKeyProofType(t *testing.T) {
+	_, err := (&Proof{ProofType: "cwt", JWT: "x"}).HolderKey(context.Background(), nil, audience)
+	assert.ErrorIs(t, err, ErrInvalidProof)
+}
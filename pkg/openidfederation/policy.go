@@ -0,0 +1,149 @@
+package openidfederation
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ApplyPolicy returns metadata with policy applied, policy is the metadata_policy of a subordinate statement. The
+// operators are applied in the order value, add, default, one_of, subset_of, superset_of and essential.
+func ApplyPolicy(metadata map[string]any, policy map[string]map[string]map[string]any) (map[string]any, error) {
+	result := make(map[string]any, len(metadata))
+	for entityType, entityMetadata := range metadata {
+		result[entityType] = entityMetadata
+	}
+
+	for _, entityType := range sortedKeys(policy) {
+		entityMetadata := map[string]any{}
+		existing, ok := result[entityType].(map[string]any)
+		for name, value := range existing {
+			entityMetadata[name] = value
+		}
+
+		for _, name := range sortedKeys(policy[entityType]) {
+			if err := applyOperators(entityMetadata, name, policy[entityType][name]); err != nil {
+				return nil, fmt.Errorf("%w: %s %s %w", ErrMetadataPolicy, entityType, name, err)
+			}
+		}
+
+		// a policy does not add metadata of an entity type the entity does not have
+		if ok {
+			result[entityType] = entityMetadata
+		}
+	}
+
+	return result, nil
+}
+
+// applyOperators applies the operators of the policy of the metadata parameter name
+func applyOperators(metadata map[string]any, name string, operators map[string]any) error {
+	if value, ok := operators["value"]; ok {
+		if value == nil {
+			delete(metadata, name)
+		} else {
+			metadata[name] = value
+		}
+	}
+
+	if add, ok := operators["add"]; ok {
+		values, _ := toList(metadata[name])
+		for _, v := range mustList(add) {
+			if !containsValue(values, v) {
+				values = append(values, v)
+			}
+		}
+		metadata[name] = values
+	}
+
+	if value, ok := operators["default"]; ok {
+		if _, present := metadata[name]; !present {
+			metadata[name] = value
+		}
+	}
+
+	value, present := metadata[name]
+
+	if oneOf, ok := operators["one_of"]; ok && present {
+		if !containsValue(mustList(oneOf), value) {
+			return fmt.Errorf("%v is not one of %v", value, oneOf)
+		}
+	}
+
+	if subsetOf, ok := operators["subset_of"]; ok && present {
+		values, isList := toList(value)
+		if !isList {
+			return fmt.Errorf("is not a list")
+		}
+		allowed := mustList(subsetOf)
+		subset := []any{}
+		for _, v := range values {
+			if containsValue(allowed, v) {
+				subset = append(subset, v)
+			}
+		}
+		metadata[name] = subset
+	}
+
+	if supersetOf, ok := operators["superset_of"]; ok && present {
+		values, isList := toList(metadata[name])
+		if !isList {
+			return fmt.Errorf("is not a list")
+		}
+		for _, v := range mustList(supersetOf) {
+			if !containsValue(values, v) {
+				return fmt.Errorf("does not contain %v", v)
+			}
+		}
+	}
+
+	if essential, _ := operators["essential"].(bool); essential {
+		if _, present := metadata[name]; !present {
+			return fmt.Errorf("is missing")
+		}
+	}
+
+	return nil
+}
+
+// toList returns v as a list, true if it is one
+func toList(v any) ([]any, bool) {
+	switch list := v.(type) {
+	case []any:
+		return append([]any{}, list...), true
+	case []string:
+		values := make([]any, len(list))
+		for i, s := range list {
+			values[i] = s
+		}
+		return values, true
+	default:
+		return nil, false
+	}
+}
+
+// mustList returns v as a list, a single value is a list of one
+func mustList(v any) []any {
+	if list, ok := toList(v); ok {
+		return list
+	}
+	return []any{v}
+}
+
+func containsValue(values []any, v any) bool {
+	for _, value := range values {
+		if reflect.DeepEqual(value, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,91 @@
+package openidfederation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyPolicy(t *testing.T) {
+	metadata := map[string]any{
+		"openid_credential_issuer": map[string]any{
+			"credential_issuer":   "https://issuer.sunet.se",
+			"signing_alg_values":  []any{"ES256", "RS256"},
+			"display_name":        "SUNET",
+			"credential_endpoint": "https://issuer.sunet.se/api/v1/credential",
+		},
+	}
+
+	tts := []struct {
+		name    string
+		policy  map[string]any
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name:   "value replaces",
+			policy: map[string]any{"display_name": map[string]any{"value": "SUNET issuer"}},
+			want:   map[string]any{"display_name": "SUNET issuer"},
+		},
+		{
+			name:   "value null removes",
+			policy: map[string]any{"display_name": map[string]any{"value": nil}},
+			want:   map[string]any{"display_name": nil},
+		},
+		{
+			name:   "add and subset_of",
+			policy: map[string]any{"signing_alg_values": map[string]any{"add": []any{"ES384"}, "subset_of": []any{"ES256", "ES384"}}},
+			want:   map[string]any{"signing_alg_values": []any{"ES256", "ES384"}},
+		},
+		{
+			name:   "default of missing parameter",
+			policy: map[string]any{"logo_uri": map[string]any{"default": "https://sunet.se/logo.svg"}},
+			want:   map[string]any{"logo_uri": "https://sunet.se/logo.svg"},
+		},
+		{
+			name:    "one_of not satisfied",
+			policy:  map[string]any{"display_name": map[string]any{"one_of": []any{"DC4EU"}}},
+			wantErr: true,
+		},
+		{
+			name:    "superset_of not satisfied",
+			policy:  map[string]any{"signing_alg_values": map[string]any{"superset_of": []any{"EdDSA"}}},
+			wantErr: true,
+		},
+		{
+			name:    "essential missing",
+			policy:  map[string]any{"jwks_uri": map[string]any{"essential": true}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := map[string]map[string]map[string]any{"openid_credential_issuer": {}}
+			for name, operators := range tt.policy {
+				policy["openid_credential_issuer"][name] = operators.(map[string]any)
+			}
+
+			got, err := ApplyPolicy(metadata, policy)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, ErrMetadataPolicy), "got %v", err)
+				return
+			}
+			assert.NoError(t, err)
+
+			issuer := got["openid_credential_issuer"].(map[string]any)
+			for name, want := range tt.want {
+				if want == nil {
+					assert.NotContains(t, issuer, name)
+					continue
+				}
+				assert.Equal(t, want, issuer[name])
+			}
+			assert.Equal(t, "https://issuer.sunet.se", issuer["credential_issuer"])
+		})
+	}
+
+	// the metadata of the entity is not changed
+	assert.Equal(t, "SUNET", metadata["openid_credential_issuer"].(map[string]any)["display_name"])
+}
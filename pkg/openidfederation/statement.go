@@ -0,0 +1,180 @@
+package openidfederation
+
+import (
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lestrrat-go/jwx/jwk"
+)
+
+const (
+	// TypeEntityStatement is the JWT typ of entity statements
+	TypeEntityStatement = "entity-statement+jwt"
+
+	// MediaTypeEntityStatement is the content type entity statements are served with
+	MediaTypeEntityStatement = "application/entity-statement+jwt"
+
+	// WellKnownPath is where an entity publishes its entity configuration, relative to its entity id
+	WellKnownPath = "/.well-known/openid-federation"
+)
+
+var (
+	// ErrInvalidStatement is returned when an entity statement is malformed, expired or its signature does not verify
+	ErrInvalidStatement = errors.New("invalid entity statement")
+
+	// ErrNoTrustChain is returned when no trust chain leads to a configured trust anchor
+	ErrNoTrustChain = errors.New("no trust chain to a trust anchor")
+
+	// ErrMetadataPolicy is returned when metadata does not satisfy the metadata policy of a superior
+	ErrMetadataPolicy = errors.New("metadata policy not satisfied")
+
+	// ErrInvalidTrustMark is returned when a trust mark is malformed, expired or not issued by an allowed issuer
+	ErrInvalidTrustMark = errors.New("invalid trust mark")
+)
+
+// EntityStatement is a signed statement about an entity, an entity configuration when iss equals sub
+type EntityStatement struct {
+	Issuer         string          `json:"iss"`
+	Subject        string          `json:"sub"`
+	IssuedAt       int64           `json:"iat"`
+	ExpiresAt      int64           `json:"exp"`
+	JWKS           json.RawMessage `json:"jwks,omitempty"`
+	AuthorityHints []string        `json:"authority_hints,omitempty"`
+	Metadata       map[string]any  `json:"metadata,omitempty"`
+
+	// MetadataPolicy is set by a superior in a subordinate statement, by entity type and metadata parameter
+	MetadataPolicy map[string]map[string]map[string]any `json:"metadata_policy,omitempty"`
+
+	// TrustMarks are in an entity configuration, signed by trust mark issuers
+	TrustMarks []TrustMarkRef `json:"trust_marks,omitempty"`
+
+	// TrustMarkIssuers are in a trust anchor configuration, the entities allowed to issue each trust mark id
+	TrustMarkIssuers map[string][]string `json:"trust_mark_issuers,omitempty"`
+}
+
+// Keys returns the federation keys of the subject
+func (s *EntityStatement) Keys() (jwk.Set, error) {
+	if len(s.JWKS) == 0 {
+		return nil, fmt.Errorf("%w: no jwks", ErrInvalidStatement)
+	}
+	return jwk.Parse(s.JWKS)
+}
+
+// FetchEndpoint returns the federation_fetch_endpoint of an entity configuration
+func (s *EntityStatement) FetchEndpoint() string {
+	entity, _ := s.Metadata["federation_entity"].(map[string]any)
+	endpoint, _ := entity["federation_fetch_endpoint"].(string)
+	return endpoint
+}
+
+// Sign signs the statement with key, kid identifies the key in the issuer federation jwks
+func (s *EntityStatement) Sign(signingMethod jwt.SigningMethod, key crypto.Signer, kid string) (string, error) {
+	return sign(s, TypeEntityStatement, signingMethod, key, kid)
+}
+
+// Parse verifies signed with keys, an entity configuration is verified with its own keys if keys is nil
+func Parse(signed string, keys jwk.Set) (*EntityStatement, error) {
+	statement := &EntityStatement{}
+	err := verify(signed, TypeEntityStatement, statement, func() (jwk.Set, error) {
+		if keys != nil {
+			return keys, nil
+		}
+		if statement.Issuer != statement.Subject {
+			return nil, fmt.Errorf("not an entity configuration")
+		}
+		return statement.Keys()
+	}, jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidStatement, err)
+	}
+
+	return statement, nil
+}
+
+// sign signs the JSON claims of v as a JWT of type typ
+func sign(v any, typ string, signingMethod jwt.SigningMethod, key crypto.Signer, kid string) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	claims := jwt.MapClaims{}
+	if err := json.Unmarshal(b, &claims); err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(signingMethod, claims)
+	token.Header["typ"] = typ
+	token.Header["kid"] = kid
+
+	return token.SignedString(key)
+}
+
+// verify verifies signed as a JWT of type typ and decodes its claims into v, keys is called after the decoding
+func verify(signed, typ string, v any, keys func() (jwk.Set, error), options ...jwt.ParserOption) error {
+	options = append([]jwt.ParserOption{jwt.WithIssuedAt()}, options...)
+	token, err := jwt.NewParser(options...).Parse(signed, func(token *jwt.Token) (any, error) {
+		if got, _ := token.Header["typ"].(string); got != typ {
+			return nil, fmt.Errorf("typ %q", got)
+		}
+
+		if err := decodeClaims(token.Claims.(jwt.MapClaims), v); err != nil {
+			return nil, err
+		}
+
+		set, err := keys()
+		if err != nil {
+			return nil, err
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		key, ok := set.LookupKeyID(kid)
+		if !ok {
+			return nil, fmt.Errorf("unknown kid %q", kid)
+		}
+
+		var publicKey any
+		if err := key.Raw(&publicKey); err != nil {
+			return nil, err
+		}
+		return publicKey, nil
+	})
+	if err != nil {
+		return err
+	}
+	if !token.Valid {
+		return fmt.Errorf("token is not valid")
+	}
+
+	return nil
+}
+
+func decodeClaims(claims jwt.MapClaims, v any) error {
+	b, err := json.Marshal(claims)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// NewEntityConfiguration creates an entity configuration of entityID valid for ttl
+func NewEntityConfiguration(entityID string, keys jwk.Set, authorityHints []string, metadata map[string]any, ttl time.Duration) (*EntityStatement, error) {
+	jwks, err := json.Marshal(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &EntityStatement{
+		Issuer:         entityID,
+		Subject:        entityID,
+		IssuedAt:       now.Unix(),
+		ExpiresAt:      now.Add(ttl).Unix(),
+		JWKS:           jwks,
+		AuthorityHints: authorityHints,
+		Metadata:       metadata,
+	}, nil
+}
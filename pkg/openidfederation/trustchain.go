@@ -0,0 +1,333 @@
+package openidfederation
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lestrrat-go/jwx/jwk"
+)
+
+const (
+	// maxChainLength limits how many superiors are followed to reach a trust anchor
+	maxChainLength = 5
+
+	// maxStatementSize is the maximum size of a fetched entity statement
+	maxStatementSize = 1 << 20
+)
+
+// Resolver resolves trust chains from an entity to a trust anchor
+type Resolver struct {
+	httpClient *http.Client
+
+	// trustAnchors are the federation keys of the trust anchors by entity id
+	trustAnchors map[string]jwk.Set
+}
+
+// NewResolver creates a resolver trusting trustAnchors, the federation keys by entity id
+func NewResolver(httpClient *http.Client, trustAnchors map[string]jwk.Set) *Resolver {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Resolver{
+		httpClient:   httpClient,
+		trustAnchors: trustAnchors,
+	}
+}
+
+// TrustChain is a resolved trust chain of an entity
+type TrustChain struct {
+	// Statements are the signed statements from the entity configuration of the subject to the one of the trust
+	// anchor, as returned by ResolveTrustChain
+	Statements []string
+
+	EntityID      string
+	TrustAnchorID string
+
+	// Metadata is the metadata of the subject with the metadata policies of its superiors applied
+	Metadata map[string]any
+
+	// TrustMarks are the ids of the trust marks of the subject that verify and are issued by an issuer the trust
+	// anchor allows
+	TrustMarks []string
+
+	// ExpiresAt is the earliest expiry of the statements, the chain has to be resolved again after it
+	ExpiresAt time.Time
+}
+
+// HasTrustMark reports if the subject has a valid trust mark of id
+func (c *TrustChain) HasTrustMark(id string) bool {
+	for _, trustMark := range c.TrustMarks {
+		if trustMark == id {
+			return true
+		}
+	}
+	return false
+}
+
+// link is one statement of a trust chain
+type link struct {
+	signed    string
+	statement *EntityStatement
+}
+
+// ResolveTrustChain returns the trust chain of the signed entity configuration, starting with it and ending with the
+// entity configuration of a trust anchor, in between are the subordinate statements of each superior.
+func (r *Resolver) ResolveTrustChain(ctx context.Context, entityConfiguration string) ([]string, error) {
+	chain, err := r.resolveChain(ctx, entityConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	return statements(chain), nil
+}
+
+// Resolve fetches the entity configuration of entityID and resolves its trust chain, with the metadata policies
+// applied and the trust marks verified
+func (r *Resolver) Resolve(ctx context.Context, entityID string) (*TrustChain, error) {
+	entityConfiguration, err := r.fetch(ctx, strings.TrimSuffix(entityID, "/")+WellKnownPath)
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := r.resolveChain(ctx, entityConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	subject, trustAnchor := chain[0].statement, chain[len(chain)-1].statement
+	if strings.TrimSuffix(subject.Subject, "/") != strings.TrimSuffix(entityID, "/") {
+		return nil, fmt.Errorf("%w: entity configuration of %s", ErrInvalidStatement, subject.Subject)
+	}
+
+	trustChain := &TrustChain{
+		Statements:    statements(chain),
+		EntityID:      subject.Subject,
+		TrustAnchorID: trustAnchor.Subject,
+		Metadata:      subject.Metadata,
+		ExpiresAt:     time.Unix(subject.ExpiresAt, 0),
+	}
+
+	// the policy of the superior closest to the trust anchor is applied first
+	for i := len(chain) - 2; i > 0; i-- {
+		statement := chain[i].statement
+		if expiresAt := time.Unix(statement.ExpiresAt, 0); expiresAt.Before(trustChain.ExpiresAt) {
+			trustChain.ExpiresAt = expiresAt
+		}
+		if trustChain.Metadata, err = ApplyPolicy(trustChain.Metadata, statement.MetadataPolicy); err != nil {
+			return nil, err
+		}
+	}
+	if expiresAt := time.Unix(trustAnchor.ExpiresAt, 0); expiresAt.Before(trustChain.ExpiresAt) {
+		trustChain.ExpiresAt = expiresAt
+	}
+
+	trustChain.TrustMarks = r.verifyTrustMarks(ctx, subject, trustAnchor)
+
+	return trustChain, nil
+}
+
+// verifyTrustMarks returns the ids of the trust marks of subject that verify, issued by an issuer trustAnchor
+// allows. The trust anchor issues trust marks itself, other issuers must have a trust chain of their own.
+func (r *Resolver) verifyTrustMarks(ctx context.Context, subject, trustAnchor *EntityStatement) []string {
+	var ids []string
+	for _, ref := range subject.TrustMarks {
+		issuer, err := trustMarkIssuer(ref.TrustMark)
+		if err != nil || !slices.Contains(trustAnchor.TrustMarkIssuers[ref.ID], issuer) {
+			continue
+		}
+
+		keys, err := r.trustMarkIssuerKeys(ctx, issuer, trustAnchor)
+		if err != nil {
+			continue
+		}
+
+		trustMark, err := ParseTrustMark(ref.TrustMark, keys)
+		if err != nil || trustMark.ID != ref.ID || trustMark.Subject != subject.Subject {
+			continue
+		}
+
+		ids = append(ids, ref.ID)
+	}
+
+	return ids
+}
+
+func (r *Resolver) trustMarkIssuerKeys(ctx context.Context, issuer string, trustAnchor *EntityStatement) (jwk.Set, error) {
+	if issuer == trustAnchor.Subject {
+		return trustAnchor.Keys()
+	}
+
+	entityConfiguration, err := r.fetch(ctx, strings.TrimSuffix(issuer, "/")+WellKnownPath)
+	if err != nil {
+		return nil, err
+	}
+	chain, err := r.resolveChain(ctx, entityConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	return chain[0].statement.Keys()
+}
+
+// resolveChain returns the trust chain of the signed entity configuration
+func (r *Resolver) resolveChain(ctx context.Context, entityConfiguration string) ([]link, error) {
+	statement, err := Parse(entityConfiguration, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := r.resolve(ctx, statement, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]link{{signed: entityConfiguration, statement: statement}}, chain...), nil
+}
+
+func statements(chain []link) []string {
+	signed := make([]string, len(chain))
+	for i, l := range chain {
+		signed[i] = l.signed
+	}
+	return signed
+}
+
+// resolve returns the statements above subject, the subject keys are checked against the subordinate statement
+func (r *Resolver) resolve(ctx context.Context, subject *EntityStatement, depth int) ([]link, error) {
+	if depth >= maxChainLength {
+		return nil, ErrNoTrustChain
+	}
+
+	var errs []error
+	for _, superiorID := range subject.AuthorityHints {
+		chain, err := r.resolveSuperior(ctx, subject, superiorID, depth)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", superiorID, err))
+			continue
+		}
+		return chain, nil
+	}
+
+	return nil, errors.Join(append([]error{ErrNoTrustChain}, errs...)...)
+}
+
+func (r *Resolver) resolveSuperior(ctx context.Context, subject *EntityStatement, superiorID string, depth int) ([]link, error) {
+	signedSuperior, err := r.fetch(ctx, strings.TrimSuffix(superiorID, "/")+WellKnownPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// a trust anchor configuration must be signed with the configured keys, others are self-signed
+	anchorKeys, isTrustAnchor := r.trustAnchors[superiorID]
+	superior, err := Parse(signedSuperior, anchorKeys)
+	if err != nil {
+		return nil, err
+	}
+	if superior.Subject != superiorID {
+		return nil, fmt.Errorf("%w: sub %s", ErrInvalidStatement, superior.Subject)
+	}
+
+	fetchEndpoint := superior.FetchEndpoint()
+	if fetchEndpoint == "" {
+		return nil, fmt.Errorf("%w: no federation_fetch_endpoint", ErrInvalidStatement)
+	}
+
+	superiorKeys, err := superior.Keys()
+	if err != nil {
+		return nil, err
+	}
+
+	signedSubordinate, err := r.fetch(ctx, fetchEndpoint+"?sub="+url.QueryEscape(subject.Subject))
+	if err != nil {
+		return nil, err
+	}
+	subordinate, err := Parse(signedSubordinate, superiorKeys)
+	if err != nil {
+		return nil, err
+	}
+	if subordinate.Issuer != superiorID || subordinate.Subject != subject.Subject {
+		return nil, fmt.Errorf("%w: subordinate statement %s about %s", ErrInvalidStatement, subordinate.Issuer, subordinate.Subject)
+	}
+
+	// the superior vouches for the keys the subject signs its entity configuration with
+	subjectKeys, err := subject.Keys()
+	if err != nil {
+		return nil, err
+	}
+	vouchedKeys, err := subordinate.Keys()
+	if err != nil {
+		return nil, err
+	}
+	if err := containsKeys(vouchedKeys, subjectKeys); err != nil {
+		return nil, err
+	}
+
+	subordinateLink := link{signed: signedSubordinate, statement: subordinate}
+	if isTrustAnchor {
+		return []link{subordinateLink, {signed: signedSuperior, statement: superior}}, nil
+	}
+
+	chain, err := r.resolve(ctx, superior, depth+1)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]link{subordinateLink}, chain...), nil
+}
+
+// containsKeys returns nil if every key in keys is in set
+func containsKeys(set, keys jwk.Set) error {
+	for i := 0; i < keys.Len(); i++ {
+		key, _ := keys.Get(i)
+		vouched, ok := set.LookupKeyID(key.KeyID())
+		if !ok {
+			return fmt.Errorf("%w: key %s is not in the subordinate statement", ErrInvalidStatement, key.KeyID())
+		}
+
+		want, err := key.Thumbprint(crypto.SHA256)
+		if err != nil {
+			return err
+		}
+		got, err := vouched.Thumbprint(crypto.SHA256)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(want, got) {
+			return fmt.Errorf("%w: key %s differs from the subordinate statement", ErrInvalidStatement, key.KeyID())
+		}
+	}
+	return nil
+}
+
+func (r *Resolver) fetch(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetch %s: %s", url, resp.Status)
+	}
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxStatementSize))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
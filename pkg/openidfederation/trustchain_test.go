@@ -0,0 +1,243 @@
+package openidfederation
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockEntity struct {
+	key  *ecdsa.PrivateKey
+	kid  string
+	keys jwk.Set
+}
+
+func newMockEntity(t *testing.T) *mockEntity {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	publicKey, err := jwk.New(key.Public())
+	assert.NoError(t, err)
+	thumbprint, err := publicKey.Thumbprint(crypto.SHA256)
+	assert.NoError(t, err)
+	kid := base64.RawURLEncoding.EncodeToString(thumbprint)
+	assert.NoError(t, publicKey.Set(jwk.KeyIDKey, kid))
+
+	keys := jwk.NewSet()
+	keys.Add(publicKey)
+
+	return &mockEntity{key: key, kid: kid, keys: keys}
+}
+
+func (e *mockEntity) sign(t *testing.T, statement *EntityStatement) string {
+	signed, err := statement.Sign(jwt.SigningMethodES256, e.key, e.kid)
+	assert.NoError(t, err)
+	return signed
+}
+
+func TestResolveTrustChain(t *testing.T) {
+	leaf := newMockEntity(t)
+	anchor := newMockEntity(t)
+	other := newMockEntity(t)
+
+	server := httptest.NewServer(nil)
+	defer server.Close()
+	leafID := server.URL + "/leaf"
+	anchorID := server.URL + "/anchor"
+
+	leafConfiguration, err := NewEntityConfiguration(leafID, leaf.keys, []string{anchorID}, nil, time.Hour)
+	assert.NoError(t, err)
+	signedLeaf := leaf.sign(t, leafConfiguration)
+
+	anchorConfiguration, err := NewEntityConfiguration(anchorID, anchor.keys, nil, map[string]any{
+		"federation_entity": map[string]any{"federation_fetch_endpoint": anchorID + "/fetch"},
+	}, time.Hour)
+	assert.NoError(t, err)
+
+	tts := []struct {
+		name         string
+		anchorKeys   jwk.Set
+		vouchedKeys  jwk.Set
+		wantChainLen int
+		wantErr      error
+	}{
+		{
+			name:         "resolved",
+			anchorKeys:   anchor.keys,
+			vouchedKeys:  leaf.keys,
+			wantChainLen: 3,
+		},
+		{
+			name:        "trust anchor signed with another key",
+			anchorKeys:  other.keys,
+			vouchedKeys: leaf.keys,
+			wantErr:     ErrNoTrustChain,
+		},
+		{
+			name:        "leaf key not vouched for",
+			anchorKeys:  anchor.keys,
+			vouchedKeys: other.keys,
+			wantErr:     ErrNoTrustChain,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			subordinate, err := NewEntityConfiguration(leafID, tt.vouchedKeys, nil, nil, time.Hour)
+			assert.NoError(t, err)
+			subordinate.Issuer = anchorID
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/anchor"+WellKnownPath, func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(anchor.sign(t, anchorConfiguration)))
+			})
+			mux.HandleFunc("/anchor/fetch", func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("sub") != leafID {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Write([]byte(anchor.sign(t, subordinate)))
+			})
+			server.Config.Handler = mux
+
+			resolver := NewResolver(server.Client(), map[string]jwk.Set{anchorID: tt.anchorKeys})
+			chain, err := resolver.ResolveTrustChain(context.Background(), signedLeaf)
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), "got %v", err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, chain, tt.wantChainLen)
+			assert.Equal(t, signedLeaf, chain[0])
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	leaf := newMockEntity(t)
+	anchor := newMockEntity(t)
+	other := newMockEntity(t)
+
+	server := httptest.NewServer(nil)
+	defer server.Close()
+	leafID := server.URL + "/leaf"
+	anchorID := server.URL + "/anchor"
+
+	sign := func(e *mockEntity, m *TrustMark) string {
+		signed, err := m.Sign(jwt.SigningMethodES256, e.key, e.kid)
+		assert.NoError(t, err)
+		return signed
+	}
+	now := time.Now().Unix()
+
+	tts := []struct {
+		name           string
+		trustMarks     []TrustMarkRef
+		policy         map[string]map[string]map[string]any
+		wantTrustMarks []string
+		wantErr        error
+	}{
+		{
+			name: "trust mark of the trust anchor",
+			trustMarks: []TrustMarkRef{
+				{ID: "https://dc4eu.eu/issuer", TrustMark: sign(anchor, &TrustMark{Issuer: anchorID, Subject: leafID, ID: "https://dc4eu.eu/issuer", IssuedAt: now})},
+			},
+			wantTrustMarks: []string{"https://dc4eu.eu/issuer"},
+		},
+		{
+			name: "trust mark signed with another key",
+			trustMarks: []TrustMarkRef{
+				{ID: "https://dc4eu.eu/issuer", TrustMark: sign(other, &TrustMark{Issuer: anchorID, Subject: leafID, ID: "https://dc4eu.eu/issuer", IssuedAt: now})},
+			},
+		},
+		{
+			name: "trust mark id not allowed for the issuer",
+			trustMarks: []TrustMarkRef{
+				{ID: "https://dc4eu.eu/wallet", TrustMark: sign(anchor, &TrustMark{Issuer: anchorID, Subject: leafID, ID: "https://dc4eu.eu/wallet", IssuedAt: now})},
+			},
+		},
+		{
+			name: "expired trust mark",
+			trustMarks: []TrustMarkRef{
+				{ID: "https://dc4eu.eu/issuer", TrustMark: sign(anchor, &TrustMark{Issuer: anchorID, Subject: leafID, ID: "https://dc4eu.eu/issuer", IssuedAt: now - 7200, ExpiresAt: now - 3600})},
+			},
+		},
+		{
+			name: "metadata policy",
+			policy: map[string]map[string]map[string]any{
+				"openid_credential_issuer": {"organization_name": {"value": "SUNET"}},
+			},
+		},
+		{
+			name: "metadata policy not satisfied",
+			policy: map[string]map[string]map[string]any{
+				"openid_credential_issuer": {"jwks_uri": {"essential": true}},
+			},
+			wantErr: ErrMetadataPolicy,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			leafConfiguration, err := NewEntityConfiguration(leafID, leaf.keys, []string{anchorID}, map[string]any{
+				"openid_credential_issuer": map[string]any{"credential_issuer": leafID},
+			}, time.Hour)
+			assert.NoError(t, err)
+			leafConfiguration.TrustMarks = tt.trustMarks
+
+			anchorConfiguration, err := NewEntityConfiguration(anchorID, anchor.keys, nil, map[string]any{
+				"federation_entity": map[string]any{"federation_fetch_endpoint": anchorID + "/fetch"},
+			}, time.Hour)
+			assert.NoError(t, err)
+			anchorConfiguration.TrustMarkIssuers = map[string][]string{"https://dc4eu.eu/issuer": {anchorID}}
+
+			subordinate, err := NewEntityConfiguration(leafID, leaf.keys, nil, nil, 30*time.Minute)
+			assert.NoError(t, err)
+			subordinate.Issuer = anchorID
+			subordinate.MetadataPolicy = tt.policy
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/leaf"+WellKnownPath, func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(leaf.sign(t, leafConfiguration)))
+			})
+			mux.HandleFunc("/anchor"+WellKnownPath, func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(anchor.sign(t, anchorConfiguration)))
+			})
+			mux.HandleFunc("/anchor/fetch", func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(anchor.sign(t, subordinate)))
+			})
+			server.Config.Handler = mux
+
+			resolver := NewResolver(server.Client(), map[string]jwk.Set{anchorID: anchor.keys})
+			chain, err := resolver.Resolve(context.Background(), leafID)
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), "got %v", err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, chain.Statements, 3)
+			assert.Equal(t, leafID, chain.EntityID)
+			assert.Equal(t, anchorID, chain.TrustAnchorID)
+			assert.Equal(t, tt.wantTrustMarks, chain.TrustMarks)
+			assert.Equal(t, subordinate.ExpiresAt, chain.ExpiresAt.Unix())
+
+			issuer := chain.Metadata["openid_credential_issuer"].(map[string]any)
+			assert.Equal(t, leafID, issuer["credential_issuer"])
+			if tt.policy != nil {
+				assert.Equal(t, "SUNET", issuer["organization_name"])
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package openidfederation
+
+import (
+	"crypto"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lestrrat-go/jwx/jwk"
+)
+
+// TypeTrustMark is the JWT typ of trust marks
+const TypeTrustMark = "trust-mark+jwt"
+
+// TrustMarkRef is a trust mark in the trust_marks of an entity configuration
+type TrustMarkRef struct {
+	ID        string `json:"id"`
+	TrustMark string `json:"trust_mark"`
+}
+
+// TrustMark is a signed statement by a trust mark issuer that the subject meets the requirements of the trust mark id
+type TrustMark struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	ID        string `json:"id"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+}
+
+// Sign signs the trust mark with key, kid identifies the key in the issuer federation jwks
+func (m *TrustMark) Sign(signingMethod jwt.SigningMethod, key crypto.Signer, kid string) (string, error) {
+	return sign(m, TypeTrustMark, signingMethod, key, kid)
+}
+
+// ParseTrustMark verifies signed with keys, the federation keys of the trust mark issuer. A trust mark without exp
+// does not expire.
+func ParseTrustMark(signed string, keys jwk.Set) (*TrustMark, error) {
+	trustMark := &TrustMark{}
+	if err := verify(signed, TypeTrustMark, trustMark, func() (jwk.Set, error) { return keys, nil }); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTrustMark, err)
+	}
+
+	return trustMark, nil
+}
+
+// trustMarkIssuer returns the unverified iss of a trust mark, to find the keys it is verified with
+func trustMarkIssuer(signed string) (string, error) {
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(signed, claims); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidTrustMark, err)
+	}
+	return claims.GetIssuer()
+}
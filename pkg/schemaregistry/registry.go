@@ -0,0 +1,271 @@
+package schemaregistry
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// maxSchemaSize is the maximum size of a fetched schema
+const maxSchemaSize = 1 << 20
+
+var (
+	// ErrSchemaNotFound is returned when no source has a schema for the document type and version
+	ErrSchemaNotFound = errors.New("schema not found")
+
+	// ErrSchemaValidation is wrapped by *ValidationError
+	ErrSchemaValidation = errors.New("document_data does not conform to the schema")
+)
+
+//go:embed schemas
+var builtin embed.FS
+
+// Violation is one value that does not conform to the schema
+type Violation struct {
+	// Path is a JSON pointer to the value, e.g. /cardHolder/birthDate
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned when document data does not conform to its schema
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = fmt.Sprintf("%s: %s", v.Path, v.Message)
+	}
+	return ErrSchemaValidation.Error() + ": " + strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrSchemaValidation
+}
+
+// Source returns the raw JSON schema of a document type and version, or ErrSchemaNotFound
+type Source interface {
+	Schema(ctx context.Context, documentType, version string) ([]byte, error)
+}
+
+// schemaPath is where a source keeps the schema of documentType and version, relative to its root
+func schemaPath(documentType, version string) string {
+	return path.Join(documentType, version+".json")
+}
+
+// FSSource reads schemas from a file system laid out as <document_type>/<version>.json
+type FSSource struct {
+	fsys fs.FS
+}
+
+// NewFSSource creates a source reading from fsys, e.g. os.DirFS(dir)
+func NewFSSource(fsys fs.FS) *FSSource {
+	return &FSSource{fsys: fsys}
+}
+
+// Schema implements Source
+func (s *FSSource) Schema(ctx context.Context, documentType, version string) ([]byte, error) {
+	name := schemaPath(documentType, version)
+	if !fs.ValidPath(name) {
+		return nil, ErrSchemaNotFound
+	}
+
+	b, err := fs.ReadFile(s.fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrSchemaNotFound
+	}
+	return b, err
+}
+
+// BuiltinSource returns the schemas shipped with vc, for EHIC, PDA1, ELM, PID and mDL
+func BuiltinSource() *FSSource {
+	fsys, _ := fs.Sub(builtin, "schemas")
+	return NewFSSource(fsys)
+}
+
+// HTTPSource fetches schemas from <base url>/<document_type>/<version>.json
+type HTTPSource struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+// NewHTTPSource creates a source fetching from baseURL
+func NewHTTPSource(httpClient *http.Client, baseURL string) *HTTPSource {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &HTTPSource{
+		httpClient: httpClient,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// Schema implements Source
+func (s *HTTPSource) Schema(ctx context.Context, documentType, version string) ([]byte, error) {
+	name := schemaPath(documentType, version)
+	if !fs.ValidPath(name) {
+		return nil, ErrSchemaNotFound
+	}
+
+	url := s.baseURL + "/" + name
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/schema+json, application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, ErrSchemaNotFound
+	default:
+		return nil, fmt.Errorf("fetch schema %s: %s", url, resp.Status)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, maxSchemaSize))
+}
+
+type cacheEntry struct {
+	// schema is nil if no source has one
+	schema    *jsonschema.Schema
+	expiresAt time.Time
+}
+
+// Registry compiles and caches the schemas of document types, asking its sources in order
+type Registry struct {
+	sources []Source
+	ttl     time.Duration
+
+	mu    sync.Mutex
+	cache map[string]*cacheEntry
+}
+
+// New creates a registry, the first source with a schema for a document type and version wins
+func New(ttl time.Duration, sources ...Source) *Registry {
+	return &Registry{
+		sources: sources,
+		ttl:     ttl,
+		cache:   map[string]*cacheEntry{},
+	}
+}
+
+// schema returns the compiled schema of documentType and version from the cache, looking it up if missing or expired
+func (r *Registry) schema(ctx context.Context, documentType, version string) (*jsonschema.Schema, error) {
+	key := schemaPath(documentType, version)
+
+	r.mu.Lock()
+	entry, ok := r.cache[key]
+	r.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		if entry.schema == nil {
+			return nil, ErrSchemaNotFound
+		}
+		return entry.schema, nil
+	}
+
+	schema, err := r.lookup(ctx, key, documentType, version)
+	if err != nil && !errors.Is(err, ErrSchemaNotFound) {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.cache[key] = &cacheEntry{schema: schema, expiresAt: time.Now().Add(r.ttl)}
+	r.mu.Unlock()
+
+	return schema, err
+}
+
+func (r *Registry) lookup(ctx context.Context, key, documentType, version string) (*jsonschema.Schema, error) {
+	for _, source := range r.sources {
+		b, err := source.Schema(ctx, documentType, version)
+		if errors.Is(err, ErrSchemaNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(b))
+		if err != nil {
+			return nil, fmt.Errorf("schema %s: %w", key, err)
+		}
+
+		compiler := jsonschema.NewCompiler()
+		compiler.AssertFormat()
+		if err := compiler.AddResource(key, doc); err != nil {
+			return nil, fmt.Errorf("schema %s: %w", key, err)
+		}
+		return compiler.Compile(key)
+	}
+
+	return nil, ErrSchemaNotFound
+}
+
+// Validate validates the document data of documentType and version against its schema.
+// ErrSchemaNotFound is returned if no source has a schema, a *ValidationError lists every violating value.
+func (r *Registry) Validate(ctx context.Context, documentType, version string, data map[string]any) error {
+	schema, err := r.schema(ctx, documentType, version)
+	if err != nil {
+		return err
+	}
+
+	// the validator expects JSON types, e.g. float64 and not int
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+
+	err = schema.Validate(instance)
+	if err == nil {
+		return nil
+	}
+	validationErr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return err
+	}
+
+	result := &ValidationError{}
+	result.add(validationErr.DetailedOutput())
+
+	return result
+}
+
+// add adds the leaves of unit, the errors of its keywords, as violations
+func (e *ValidationError) add(unit *jsonschema.OutputUnit) {
+	for i := range unit.Errors {
+		e.add(&unit.Errors[i])
+	}
+	if unit.Error == nil {
+		return
+	}
+
+	path := unit.InstanceLocation
+	if path == "" {
+		path = "/"
+	}
+	e.Violations = append(e.Violations, Violation{Path: path, Message: unit.Error.String()})
+}
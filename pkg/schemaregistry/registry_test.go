@@ -0,0 +1,174 @@
+package schemaregistry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+	"vc/pkg/pda1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const mockEHIC = `{
+	"pid": {"firstName": "Magnus", "lastName": "Svensson", "gender": "male", "pins": [], "exhibitorID": "1234567890"},
+	"cardHolder": {"familyName": "Svensson", "givenName": "Magnus", "birthDate": "1970-01-01", "id": "1", "cardholderStatus": "active"},
+	"competentInstitution": {"institutionName": "Försäkringskassan", "id": "SE:1"},
+	"cardInformation": {
+		"id": "80752",
+		"issuanceDate": "2024-01-01",
+		"validSince": "2024-01-01",
+		"expiryDate": "2029-01-01",
+		"invalidSince": "2029-01-01",
+		"signature": {"issuer": "SUNET", "seal": "1"}
+	},
+	"signature": {"issuer": "SUNET", "seal": "1"}
+}`
+
+func mockDocument(t *testing.T, s string) map[string]any {
+	doc := map[string]any{}
+	assert.NoError(t, json.Unmarshal([]byte(s), &doc))
+	return doc
+}
+
+func TestValidateBuiltin(t *testing.T) {
+	registry := New(time.Hour, BuiltinSource())
+
+	tts := []struct {
+		name       string
+		modify     func(doc map[string]any)
+		violations []string
+	}{
+		{
+			name:   "valid",
+			modify: func(doc map[string]any) {},
+		},
+		{
+			name: "missing field",
+			modify: func(doc map[string]any) {
+				delete(doc["cardHolder"].(map[string]any), "birthDate")
+			},
+			violations: []string{"/cardHolder"},
+		},
+		{
+			name: "wrong type",
+			modify: func(doc map[string]any) {
+				doc["cardInformation"].(map[string]any)["signature"].(map[string]any)["seal"] = 1
+			},
+			violations: []string{"/cardInformation/signature/seal"},
+		},
+		{
+			name: "several violations",
+			modify: func(doc map[string]any) {
+				doc["pid"].(map[string]any)["pins"] = "1234"
+				doc["competentInstitution"].(map[string]any)["id"] = ""
+			},
+			violations: []string{"/competentInstitution/id", "/pid/pins"},
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := mockDocument(t, mockEHIC)
+			tt.modify(doc)
+
+			err := registry.Validate(context.Background(), "EHIC", "1.0.0", doc)
+			if tt.violations == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, ErrSchemaValidation)
+			validationErr, ok := err.(*ValidationError)
+			assert.True(t, ok)
+
+			paths := []string{}
+			for _, v := range validationErr.Violations {
+				paths = append(paths, v.Path)
+			}
+			assert.ElementsMatch(t, tt.violations, paths)
+		})
+	}
+}
+
+func TestValidateBuiltinPDA1(t *testing.T) {
+	registry := New(time.Hour, BuiltinSource())
+
+	// the zero values of the sections are what the issuer gets for omitted sections
+	document := pda1.Document{
+		PersonalDetails: pda1.Section1{
+			Sex:         "01",
+			Surname:     "Svensson",
+			Forenames:   "Magnus",
+			DateBirth:   "1970-01-01",
+			Nationality: "SE",
+		},
+		CompletingInstitution: pda1.Section6{Date: time.Now()},
+	}
+	b, err := json.Marshal(document)
+	assert.NoError(t, err)
+
+	err = registry.Validate(context.Background(), "PDA1", "1.0.0", mockDocument(t, string(b)))
+	assert.NoError(t, err)
+
+	document.PersonalDetails.Nationality = "se"
+	b, err = json.Marshal(document)
+	assert.NoError(t, err)
+
+	err = registry.Validate(context.Background(), "PDA1", "1.0.0", mockDocument(t, string(b)))
+	assert.Equal(t, &ValidationError{Violations: []Violation{{Path: "/personalDetails/nationality", Message: "'se' does not match pattern '^[A-Z]{2}$'"}}}, err)
+}
+
+func TestValidateSchemaNotFound(t *testing.T) {
+	registry := New(time.Hour, BuiltinSource())
+
+	err := registry.Validate(context.Background(), "EHIC", "9.9.9", map[string]any{})
+	assert.ErrorIs(t, err, ErrSchemaNotFound)
+
+	err = registry.Validate(context.Background(), "../EHIC", "1.0.0", map[string]any{})
+	assert.ErrorIs(t, err, ErrSchemaNotFound)
+}
+
+func TestValidateSourceOrder(t *testing.T) {
+	local := NewFSSource(fstest.MapFS{
+		"EHIC/1.0.0.json": {Data: []byte(`{"type": "object", "required": ["local"]}`)},
+	})
+	registry := New(time.Hour, local, BuiltinSource())
+
+	err := registry.Validate(context.Background(), "EHIC", "1.0.0", mockDocument(t, mockEHIC))
+	assert.Equal(t, &ValidationError{Violations: []Violation{{Path: "/", Message: "missing property 'local'"}}}, err)
+
+	err = registry.Validate(context.Background(), "PDA1", "1.0.0", map[string]any{})
+	assert.ErrorIs(t, err, ErrSchemaValidation)
+}
+
+func TestHTTPSource(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/schemas/PID/1.0.0.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"type": "object", "properties": {"birth_date": {"type": "string", "format": "date"}}}`))
+	}))
+	defer server.Close()
+
+	registry := New(time.Hour, NewHTTPSource(server.Client(), server.URL+"/schemas/"))
+
+	err := registry.Validate(context.Background(), "PID", "1.0.0", map[string]any{"birth_date": "1970-01-01"})
+	assert.NoError(t, err)
+
+	err = registry.Validate(context.Background(), "PID", "1.0.0", map[string]any{"birth_date": "01/01/1970"})
+	assert.ErrorIs(t, err, ErrSchemaValidation)
+	assert.Equal(t, 1, requests, "schema is cached")
+
+	err = registry.Validate(context.Background(), "mDL", "1.0.0", map[string]any{})
+	assert.ErrorIs(t, err, ErrSchemaNotFound)
+	err = registry.Validate(context.Background(), "mDL", "1.0.0", map[string]any{})
+	assert.ErrorIs(t, err, ErrSchemaNotFound)
+	assert.Equal(t, 2, requests, "missing schema is cached")
+}
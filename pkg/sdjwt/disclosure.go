@@ -1,8 +1,12 @@
 package sdjwt
 
 import (
+	"crypto/subtle"
 	"encoding/base64"
+	"encoding/json"
 	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
 )
 
 // Disclosure keeps a disclosure
@@ -27,9 +31,44 @@ func (d DisclosuresV2) new(dd []string) error {
 	}
 	return nil
 }
+
+// get returns the disclosure of the digest key, every digest is compared in constant time
 func (d DisclosuresV2) get(key string) (Disclosure, bool) {
-	v, ok := d[key]
-	return v, ok
+	var found Disclosure
+	ok := false
+	for digest, disclosure := range d {
+		if digestEqual(digest, key) {
+			found, ok = disclosure, true
+		}
+	}
+	return found, ok
+}
+
+// digestEqual compares two disclosure digests in constant time, so the time taken does not reveal how much of a
+// guessed digest matched
+func digestEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// disclosedDigest is a decoded disclosure and its digest
+type disclosedDigest struct {
+	digest     string
+	disclosure []any
+}
+
+// disclosedDigests are the disclosures of an SD-JWT, looked up by digest without a map so that every lookup compares
+// all digests in constant time
+type disclosedDigests []disclosedDigest
+
+func (d disclosedDigests) get(digest string) ([]any, bool) {
+	var found []any
+	ok := false
+	for _, v := range d {
+		if digestEqual(v.digest, digest) {
+			found, ok = v.disclosure, true
+		}
+	}
+	return found, ok
 }
 
 func (d *Disclosure) makeClaimHash() {
@@ -60,3 +99,122 @@ func (d *Disclosure) parse(s string) error {
 	d.makeClaimHash()
 	return nil
 }
+
+// DisclosedClaims returns claims, the payload of an issuer signed JWT, with the digests of the disclosures replaced by
+// the disclosed claims. Undisclosed digests, _sd and _sd_alg are removed.
+func DisclosedClaims(claims map[string]any, disclosures []string) (map[string]any, error) {
+	disclosed := disclosedDigests{}
+	for _, d := range disclosures {
+		if d == "" {
+			continue
+		}
+		decoded, err := base64.RawURLEncoding.DecodeString(d)
+		if err != nil {
+			return nil, err
+		}
+		var disclosure []any
+		if err := json.Unmarshal(decoded, &disclosure); err != nil {
+			return nil, err
+		}
+		if len(disclosure) != 2 && len(disclosure) != 3 {
+			return nil, ErrMalformedDisclosure
+		}
+		disclosed = append(disclosed, disclosedDigest{digest: hash(d), disclosure: disclosure})
+	}
+
+	return discloseObject(claims, disclosed), nil
+}
+
+func discloseObject(object map[string]any, disclosed disclosedDigests) map[string]any {
+	result := map[string]any{}
+	for k, v := range object {
+		if k == "_sd" || k == "_sd_alg" {
+			continue
+		}
+		result[k] = discloseValue(v, disclosed)
+	}
+
+	digests, _ := object["_sd"].([]any)
+	for _, digest := range digests {
+		s, _ := digest.(string)
+		disclosure, ok := disclosed.get(s)
+		if !ok || len(disclosure) != 3 {
+			continue
+		}
+		name, ok := disclosure[1].(string)
+		if !ok {
+			continue
+		}
+		result[name] = discloseValue(disclosure[2], disclosed)
+	}
+
+	return result
+}
+
+func discloseValue(value any, disclosed disclosedDigests) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return discloseObject(v, disclosed)
+	case jwt.MapClaims:
+		return discloseObject(v, disclosed)
+	case []any:
+		result := []any{}
+		for _, item := range v {
+			element, ok := item.(map[string]any)
+			if !ok || len(element) != 1 || element["..."] == nil {
+				result = append(result, discloseValue(item, disclosed))
+				continue
+			}
+			s, _ := element["..."].(string)
+			disclosure, ok := disclosed.get(s)
+			if !ok || len(disclosure) != 2 {
+				continue
+			}
+			result = append(result, discloseValue(disclosure[1], disclosed))
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+// saltBits returns the number of bits a base64url encoded salt of its length can hold, 6 bits per character. It is an
+// upper bound of the entropy of the salt, which can not be told from the encoding.
+func saltBits(salt string) int {
+	return len(salt) * 6
+}
+
+// CheckSalts returns ErrSaltTooShort if the salt of a disclosure encodes less than minBits bits, a minBits of 0 accepts
+// any salt. This is a minimum length policy that rejects short salts, it can not tell if a salt was randomly generated.
+// Malformed disclosures return ErrMalformedDisclosure.
+func CheckSalts(disclosures []string, minBits int) error {
+	if minBits <= 0 {
+		return nil
+	}
+
+	for _, d := range disclosures {
+		if d == "" {
+			continue
+		}
+		decoded, err := base64.RawURLEncoding.DecodeString(d)
+		if err != nil {
+			return err
+		}
+		var disclosure []any
+		if err := json.Unmarshal(decoded, &disclosure); err != nil {
+			return err
+		}
+		if len(disclosure) != 2 && len(disclosure) != 3 {
+			return ErrMalformedDisclosure
+		}
+		salt, ok := disclosure[0].(string)
+		if !ok {
+			return ErrMalformedDisclosure
+		}
+		if saltBits(salt) < minBits {
+			return ErrSaltTooShort
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package sdjwt
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckSalts(t *testing.T) {
+	disclosure := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+	// 22 base64url characters, 132 bits
+	salt := "_26bc4LT-ac6q2KI6cBW5e"
+
+	tts := []struct {
+		name        string
+		disclosures []string
+		minBits     int
+		want        error
+	}{
+		{
+			name:        "long enough",
+			disclosures: []string{disclosure(`["` + salt + `","given_name","Magnus"]`), disclosure(`["` + salt + `","SE"]`)},
+			minBits:     128,
+		},
+		{
+			name:        "too short",
+			disclosures: []string{disclosure(`["` + salt + `","given_name","Magnus"]`), disclosure(`["salt2","SE"]`)},
+			minBits:     128,
+			want:        ErrSaltTooShort,
+		},
+		{
+			name:        "no policy",
+			disclosures: []string{disclosure(`["salt2","SE"]`)},
+		},
+		{
+			name:        "salt is not a string",
+			disclosures: []string{disclosure(`[1,"given_name","Magnus"]`)},
+			minBits:     128,
+			want:        ErrMalformedDisclosure,
+		},
+		{
+			name:        "malformed",
+			disclosures: []string{disclosure(`["` + salt + `"]`)},
+			minBits:     128,
+			want:        ErrMalformedDisclosure,
+		},
+		{
+			name:    "no disclosures",
+			minBits: 128,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CheckSalts(tt.disclosures, tt.minBits))
+		})
+	}
+}
+
+func TestDisclosuresV2Get(t *testing.T) {
+	disclosures := DisclosuresV2{}
+	assert.NoError(t, disclosures.new([]string{base64.RawStdEncoding.EncodeToString([]byte(`["salt","given_name","Magnus"]`))}))
+
+	for digest := range disclosures {
+		got, ok := disclosures.get(digest)
+		assert.True(t, ok)
+		assert.Equal(t, "given_name", got.name)
+	}
+
+	_, ok := disclosures.get("unknown")
+	assert.False(t, ok)
+}
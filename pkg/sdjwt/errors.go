@@ -8,6 +8,37 @@ var (
 	// ErrTokenNotValid is returned when the JWT token is not valid
 	ErrTokenNotValid = errors.New("token is not valid")
 
+	// ErrAlgorithmMismatch is returned when the alg header of a token is not the expected signing algorithm
+	ErrAlgorithmMismatch = errors.New("signing algorithm does not match")
+
 	// ErrBase64EncodedEmpty is returned when the base64 encoded string is empty in Instruction
 	ErrBase64EncodedEmpty = errors.New("base64Encoded is empty")
+
+	// ErrVCTNotAllowed is returned when a vct is not in the VCTM allow list
+	ErrVCTNotAllowed = errors.New("vct is not allowed")
+
+	// ErrIntegrityMismatch is returned when a VCTM does not match its vct#integrity
+	ErrIntegrityMismatch = errors.New("vct#integrity does not match")
+
+	// ErrMalformedDisclosure is returned when a disclosure is not a JSON array of two or three elements
+	ErrMalformedDisclosure = errors.New("malformed disclosure")
+
+	// ErrSaltTooShort is returned when the salt of a disclosure carries less entropy than the salt policy requires
+	ErrSaltTooShort = errors.New("disclosure salt is too short")
+
+	// ErrMalformedStatus is returned when the status claim is not a status object with a status_list reference
+	ErrMalformedStatus = errors.New("malformed status claim")
+
+	// ErrCredentialRevoked is returned when the status list marks the credential as invalid
+	ErrCredentialRevoked = errors.New("credential is revoked")
+
+	// ErrCredentialSuspended is returned when the status list marks the credential as suspended
+	ErrCredentialSuspended = errors.New("credential is suspended")
+
+	// ErrSchemaValidation is returned, wrapped in a *SchemaError, when claims do not conform to the VCTM schema
+	ErrSchemaValidation = errors.New("claims do not conform to vctm schema")
+
+	// ErrQueryNotSatisfied is returned, wrapped in a *QueryError, when a credential does not satisfy a DCQL query or a
+	// presentation definition
+	ErrQueryNotSatisfied = errors.New("query is not satisfied")
 )
@@ -48,3 +48,48 @@ type ChildArrayInstructionV2 struct {
 
 // InstructionsV2 is a list of instructions
 type InstructionsV2 []any
+
+// Claims returns the claims the instructions describe, as they appear when every disclosure is disclosed
+func (i InstructionsV2) Claims() map[string]any {
+	claims := map[string]any{}
+	collectClaims(i, claims)
+	return claims
+}
+
+func collectClaims(instructions []any, storage map[string]any) {
+	for _, instruction := range instructions {
+		switch claim := instruction.(type) {
+		case *ParentInstructionV2:
+			children := map[string]any{}
+			collectClaims(claim.Children, children)
+			storage[claim.Name] = children
+		case *RecursiveInstructionV2:
+			if len(claim.Children) == 0 {
+				storage[claim.Name] = claim.Value
+				continue
+			}
+			children := map[string]any{}
+			collectClaims(claim.Children, children)
+			storage[claim.Name] = children
+		case *ChildInstructionV2:
+			storage[claim.Name] = claim.Value
+		case *ChildArrayInstructionV2:
+			storage[claim.Name] = claim.values()
+		case ChildArrayInstructionV2:
+			storage[claim.Name] = claim.values()
+		}
+	}
+}
+
+// values returns the values of the array, from the children if it has any
+func (c *ChildArrayInstructionV2) values() []any {
+	if len(c.Children) == 0 {
+		return c.Value
+	}
+
+	values := make([]any, 0, len(c.Children))
+	for _, child := range c.Children {
+		values = append(values, child.Value)
+	}
+	return values
+}
@@ -1,13 +1,14 @@
 package sdjwt
 
 import (
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
+	"vc/pkg/statuslist"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
-	"go.step.sm/crypto/randutil"
 )
 
 // Instruction instructs how to build a SD-JWT
@@ -52,13 +53,22 @@ type SDJWT struct {
 	KeyBinding  string
 }
 
+// saltBytes is the number of random bytes of a disclosure salt
+const saltBytes = 16
+
 var (
-	newSalt = func() string {
-		r, _ := randutil.ASCII(17)
-		return base64.RawURLEncoding.EncodeToString([]byte(r))
-	}
+	newSalt = randomSalt
 )
 
+// randomSalt returns saltBytes random bytes base64url encoded, 128 bits of entropy in 22 characters
+func randomSalt() string {
+	b := make([]byte, saltBytes)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
 func newUUID() string {
 	return uuid.NewString()
 }
@@ -78,10 +88,10 @@ func addToArray(key string, value any, storage jwt.MapClaims) {
 	}
 }
 
-func (i InstructionsV2) createSDJWT() (jwt.MapClaims, DisclosuresV2, error) {
+func (i InstructionsV2) createSDJWT(previousDisclosures []string) (jwt.MapClaims, DisclosuresV2, error) {
 	storage := jwt.MapClaims{}
 	disclosures := DisclosuresV2{}
-	if err := makeSDV2(i, storage, disclosures); err != nil {
+	if err := makeSDV2(i, storage, disclosures, newSaltSource(previousDisclosures)); err != nil {
 		return nil, nil, err
 	}
 	return storage, disclosures, nil
@@ -95,6 +105,10 @@ func sign(claims jwt.MapClaims, signingMethod jwt.SigningMethod, signingKey any,
 		token.Header["typ"] = config.HeaderType
 	}
 
+	if config.KID != "" {
+		token.Header["kid"] = config.KID
+	}
+
 	return token.SignedString(signingKey)
 }
 
@@ -105,36 +119,68 @@ func sign(claims jwt.MapClaims, signingMethod jwt.SigningMethod, signingKey any,
 
 // Config configs sd-jwt-vc
 type Config struct {
-	ISS        string
-	NBF        int64
-	EXP        int64
-	VCT        string
-	Status     string
+	ISS    string
+	NBF    int64
+	EXP    int64
+	VCT    string
+	Status string
+	// CNF binds the credential to the holder key, the credential has no cnf claim if nil
 	CNF        jwt.MapClaims
 	HeaderType string
 
+	// VCTIntegrity is the subresource integrity of the vct type metadata, added as vct#integrity if set
+	VCTIntegrity string
+
+	// StatusList is the status list entry of the credential, added as the status claim if set
+	StatusList *statuslist.StatusReference
+
+	// KID is set in the JWT header to identify the signing key
+	KID string
+
 	// SUB MAY be selectively disclosed
 	SUB string
 	// IAT MAY be selectively disclosed
 	IAT int64
+
+	// PreviousDisclosures are the disclosures of the credential this one refreshes. Claims with the same name and
+	// value reuse the salt of their previous disclosure and keep their digest.
+	PreviousDisclosures []string
 }
 
-// SDJWT returns a signed SD-JWT with disclosures.
-// Maybe this should return a more structured return of jwt and disclosures
-func (i InstructionsV2) SDJWT(signingMethod jwt.SigningMethod, signingKey any, config *Config) (*SDJWT, error) {
-	rawSDJWT, disclosures, err := i.createSDJWT()
+// Unsigned returns the payload and disclosures of the SD-JWT without signing it
+func (i InstructionsV2) Unsigned(config *Config) (jwt.MapClaims, DisclosuresV2, error) {
+	rawSDJWT, disclosures, err := i.createSDJWT(config.PreviousDisclosures)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	rawSDJWT["iss"] = config.ISS
 	rawSDJWT["nbf"] = config.NBF
 	rawSDJWT["exp"] = config.EXP
-	rawSDJWT["cnf"] = config.CNF
+	if config.CNF != nil {
+		rawSDJWT["cnf"] = config.CNF
+	}
 	rawSDJWT["vct"] = config.VCT
+	if config.VCTIntegrity != "" {
+		rawSDJWT["vct#integrity"] = config.VCTIntegrity
+	}
 	rawSDJWT["status"] = ""
+	if config.StatusList != nil {
+		rawSDJWT["status"] = &statuslist.Status{StatusList: config.StatusList}
+	}
 	rawSDJWT["_sd_alg"] = "sha-256"
 
+	return rawSDJWT, disclosures, nil
+}
+
+// SDJWT returns a signed SD-JWT with disclosures.
+// Maybe this should return a more structured return of jwt and disclosures
+func (i InstructionsV2) SDJWT(signingMethod jwt.SigningMethod, signingKey any, config *Config) (*SDJWT, error) {
+	rawSDJWT, disclosures, err := i.Unsigned(config)
+	if err != nil {
+		return nil, err
+	}
+
 	signedJWT, err := sign(rawSDJWT, signingMethod, signingKey, config)
 	if err != nil {
 		return nil, err
@@ -17,40 +17,31 @@ var (
 	ErrValueAndChildrenPresent = fmt.Errorf("value and children present")
 )
 
-func (c *ChildInstructionV2) makeClaimHash() {
-	c.Salt = newSalt()
-	s := fmt.Sprintf("[%q,%q,%q]", c.Salt, c.Name, c.Value)
-	c.DisclosureHash = base64.RawURLEncoding.EncodeToString([]byte(s))
+func (c *ChildInstructionV2) makeClaimHash(salts *saltSource) {
+	disclosure := func(salt string) string {
+		s := fmt.Sprintf("[%q,%q,%q]", salt, c.Name, c.Value)
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+	c.Salt = salts.salt(c.Name, disclosure)
+	c.DisclosureHash = disclosure(c.Salt)
 	c.ClaimHash = hash(c.DisclosureHash)
 }
 
-func (r *RecursiveInstructionV2) makeClaimHash() error {
-	r.Salt = newSalt()
-
-	childClaims := map[string][]string{
-		"_sd": r.ChildrenClaimHash,
-	}
-
-	j, err := json.Marshal(childClaims)
-	if err != nil {
-		return err
-	}
-
-	s := fmt.Sprintf("[%q,%q,%s]", r.Salt, r.Name, string(j))
-	r.DisclosureHash = base64.RawURLEncoding.EncodeToString([]byte(s))
-	r.ClaimHash = hash(r.DisclosureHash)
-
-	return nil
+func (r *RecursiveInstructionV2) makeClaimHash(salts *saltSource) error {
+	return r.recursiveHashClaim(r.ChildrenClaimHash, salts)
 }
 
-func (p *ParentInstructionV2) makeClaimHash() error {
-	p.Salt = newSalt()
+func (p *ParentInstructionV2) makeClaimHash(salts *saltSource) error {
 	childrenClaims, err := claimStringRepresentation(p.Children)
 	if err != nil {
 		return err
 	}
-	s := fmt.Sprintf("[%q,%q,%s]", p.Salt, p.Name, childrenClaims)
-	p.DisclosureHash = base64.RawURLEncoding.EncodeToString([]byte(s))
+	disclosure := func(salt string) string {
+		s := fmt.Sprintf("[%q,%q,%s]", salt, p.Name, childrenClaims)
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+	p.Salt = salts.salt(p.Name, disclosure)
+	p.DisclosureHash = disclosure(p.Salt)
 	p.ClaimHash = hash(p.DisclosureHash)
 
 	return nil
@@ -63,9 +54,8 @@ func (p *ParentInstructionV2) makeClaimHash() error {
 //	c.ClaimHash = hash(c.DisclosureHash)
 //}
 
-func (r *RecursiveInstructionV2) recursiveHashClaim(claimHashes []string) error {
+func (r *RecursiveInstructionV2) recursiveHashClaim(claimHashes []string, salts *saltSource) error {
 	// make claimHash of children claimHashes
-	r.Salt = newSalt()
 	childrenClaims := map[string][]string{
 		"_sd": claimHashes,
 	}
@@ -74,8 +64,12 @@ func (r *RecursiveInstructionV2) recursiveHashClaim(claimHashes []string) error
 	if err != nil {
 		return err
 	}
-	s := fmt.Sprintf("[%q,%q,%s]", r.Salt, r.Name, string(b))
-	r.DisclosureHash = base64.RawURLEncoding.EncodeToString([]byte(s))
+	disclosure := func(salt string) string {
+		s := fmt.Sprintf("[%q,%q,%s]", salt, r.Name, string(b))
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+	r.Salt = salts.salt(r.Name, disclosure)
+	r.DisclosureHash = disclosure(r.Salt)
 	r.ClaimHash = hash(r.DisclosureHash)
 
 	return nil
@@ -180,16 +174,16 @@ func addUID(instruction any) {
 	}
 }
 
-func recursiveClaimHandler(instructions []any, parent any, disclosures DisclosuresV2) error {
+func recursiveClaimHandler(instructions []any, parent any, disclosures DisclosuresV2, salts *saltSource) error {
 	for _, instruction := range instructions {
 		switch instruction.(type) {
 		case *RecursiveInstructionV2:
 			addUID(instruction)
 			child := instruction.(*RecursiveInstructionV2)
-			if err := recursiveClaimHandler(child.Children, child, disclosures); err != nil {
+			if err := recursiveClaimHandler(child.Children, child, disclosures, salts); err != nil {
 				return err
 			}
-			if err := child.makeClaimHash(); err != nil {
+			if err := child.makeClaimHash(salts); err != nil {
 				return err
 			}
 			child.addToDisclosures(disclosures)
@@ -205,7 +199,7 @@ func recursiveClaimHandler(instructions []any, parent any, disclosures Disclosur
 		case *ChildInstructionV2:
 			addUID(instruction)
 			child := instruction.(*ChildInstructionV2)
-			child.makeClaimHash()
+			child.makeClaimHash(salts)
 			child.addToDisclosures(disclosures)
 			switch parentClaim := parent.(type) {
 			case *RecursiveInstructionV2:
@@ -220,14 +214,14 @@ func recursiveClaimHandler(instructions []any, parent any, disclosures Disclosur
 	return nil
 }
 
-func makeSDV2(instructions []any, storage jwt.MapClaims, disclosures DisclosuresV2) error {
+func makeSDV2(instructions []any, storage jwt.MapClaims, disclosures DisclosuresV2, salts *saltSource) error {
 	for _, i := range instructions {
 		switch claim := i.(type) {
 		case *ParentInstructionV2:
 
 			if claim.SelectiveDisclosure {
 				// Parent is Selective Disclosure witch means that all of its children are also Selective Disclosure, but not recursive.
-				if err := claim.makeClaimHash(); err != nil {
+				if err := claim.makeClaimHash(salts); err != nil {
 					return err
 				}
 				addToArray("_sd", claim.ClaimHash, storage)
@@ -238,16 +232,16 @@ func makeSDV2(instructions []any, storage jwt.MapClaims, disclosures Disclosures
 			}
 
 			storage[claim.Name] = jwt.MapClaims{}
-			if err := makeSDV2(claim.Children, storage[claim.Name].(jwt.MapClaims), disclosures); err != nil {
+			if err := makeSDV2(claim.Children, storage[claim.Name].(jwt.MapClaims), disclosures, salts); err != nil {
 				return err
 			}
 
 		case *RecursiveInstructionV2:
-			if err := recursiveClaimHandler(claim.Children, claim, disclosures); err != nil {
+			if err := recursiveClaimHandler(claim.Children, claim, disclosures, salts); err != nil {
 				return err
 			}
 
-			if err := claim.recursiveHashClaim(claim.ChildrenClaimHash); err != nil {
+			if err := claim.recursiveHashClaim(claim.ChildrenClaimHash, salts); err != nil {
 				return err
 			}
 
@@ -257,7 +251,7 @@ func makeSDV2(instructions []any, storage jwt.MapClaims, disclosures Disclosures
 
 		case *ChildInstructionV2:
 			if claim.SelectiveDisclosure {
-				claim.makeClaimHash()
+				claim.makeClaimHash(salts)
 				claim.addToDisclosures(disclosures)
 				addToArray("_sd", claim.ClaimHash, storage)
 			} else {
@@ -267,7 +261,7 @@ func makeSDV2(instructions []any, storage jwt.MapClaims, disclosures Disclosures
 		case *ChildArrayInstructionV2:
 			for _, child := range claim.Children {
 				if child.SelectiveDisclosure {
-					child.makeClaimHash()
+					child.makeClaimHash(salts)
 					addToArray(claim.Name, map[string]string{"...": child.ClaimHash}, storage)
 
 					child.addToDisclosures(disclosures)
@@ -412,7 +412,7 @@ func TestMakeSDV2(t *testing.T) {
 			}
 			storage := jwt.MapClaims{}
 			disclosures := DisclosuresV2{}
-			err := makeSDV2(tt.have, storage, disclosures)
+			err := makeSDV2(tt.have, storage, disclosures, nil)
 			assert.NoError(t, err)
 
 			//s, err := json.Marshal(storage)
@@ -460,7 +460,7 @@ func TestRecursiveClaimHandler(t *testing.T) {
 				return "salt_zyx"
 			}
 			disclosures := DisclosuresV2{}
-			err := recursiveClaimHandler(tt.have, tt.have[0], disclosures)
+			err := recursiveClaimHandler(tt.have, tt.have[0], disclosures, nil)
 			assert.NoError(t, err)
 
 			parent := tt.have[0].(*RecursiveInstructionV2)
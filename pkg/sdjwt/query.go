@@ -0,0 +1,611 @@
+package sdjwt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// formats are the credential formats of SD-JWT VCs in DCQL queries and presentation definitions
+var formats = []string{"dc+sd-jwt", "vc+sd-jwt"}
+
+// DCQLQuery is an OpenID4VP Digital Credentials Query Language query
+type DCQLQuery struct {
+	Credentials []DCQLCredentialQuery `json:"credentials"`
+}
+
+// DCQLCredentialQuery requests one credential
+type DCQLCredentialQuery struct {
+	ID     string      `json:"id"`
+	Format string      `json:"format"`
+	Meta   DCQLMeta    `json:"meta"`
+	Claims []DCQLClaim `json:"claims,omitempty"`
+
+	// ClaimSets are alternative sets of claim ids, in the order the verifier prefers them
+	ClaimSets [][]string `json:"claim_sets,omitempty"`
+}
+
+// DCQLMeta holds the SD-JWT VC specific constraints of a credential query
+type DCQLMeta struct {
+	VCTValues []string `json:"vct_values,omitempty"`
+}
+
+// DCQLClaim requests one claim
+type DCQLClaim struct {
+	ID string `json:"id,omitempty"`
+
+	// Path is a claims path pointer, each element a string for an object key, an integer for an array index or null
+	// for all elements of an array
+	Path []any `json:"path"`
+
+	// Values are the values the claim is accepted with, any value if empty
+	Values []any `json:"values,omitempty"`
+}
+
+// PresentationDefinition is a DIF Presentation Exchange presentation definition
+type PresentationDefinition struct {
+	ID               string            `json:"id"`
+	InputDescriptors []InputDescriptor `json:"input_descriptors"`
+}
+
+// InputDescriptor requests one credential
+type InputDescriptor struct {
+	ID          string                     `json:"id"`
+	Format      map[string]any             `json:"format,omitempty"`
+	Constraints InputDescriptorConstraints `json:"constraints"`
+}
+
+// InputDescriptorConstraints are the fields an input descriptor requests
+type InputDescriptorConstraints struct {
+	LimitDisclosure string  `json:"limit_disclosure,omitempty"`
+	Fields          []Field `json:"fields,omitempty"`
+}
+
+// Field requests the first of its paths that resolves to a value the filter accepts
+type Field struct {
+	// Path are JSONPath expressions, e.g. $.address.country, $['given_name'] or $.nationalities[*]
+	Path []string `json:"path"`
+
+	// Filter is a JSON Schema the value must conform to
+	Filter json.RawMessage `json:"filter,omitempty"`
+
+	// Optional fields are not disclosed
+	Optional bool `json:"optional,omitempty"`
+}
+
+// QueryMatch is the least set of disclosures of a credential that satisfies a query
+type QueryMatch struct {
+	// QueryID is the id of the DCQL credential query or the input descriptor the credential satisfies
+	QueryID string `json:"query_id"`
+
+	// Disclosures are in the order of the credential
+	Disclosures []string `json:"disclosures"`
+}
+
+// Presentation returns the SD-JWT of issuerJWT with the disclosures of the match, without key binding
+func (m *QueryMatch) Presentation(issuerJWT string) string {
+	return strings.Join(append([]string{issuerJWT}, m.Disclosures...), "~") + "~"
+}
+
+// QueryError is returned when a credential does not satisfy a query
+type QueryError struct {
+	// Reasons are why each credential query or input descriptor is not satisfied
+	Reasons []string
+}
+
+func (e *QueryError) Error() string {
+	return ErrQueryNotSatisfied.Error() + ": " + strings.Join(e.Reasons, "; ")
+}
+
+func (e *QueryError) Unwrap() error {
+	return ErrQueryNotSatisfied
+}
+
+// claimNode is a claim of a credential and the disclosure that reveals it, empty if the claim is always visible
+type claimNode struct {
+	disclosure string
+	value      any
+	object     map[string]*claimNode
+	array      []*claimNode
+}
+
+// claimSelection is a claim selected by a path and the disclosures that reveal it and all of its sub-claims
+type claimSelection struct {
+	node        *claimNode
+	disclosures []string
+}
+
+// credentialClaims is a parsed SD-JWT on the holder side
+type credentialClaims struct {
+	issuerJWT   string
+	disclosures []string
+	root        *claimNode
+}
+
+// parseCredential parses credential, <jwt>~<disclosure>~...~ with an optional key binding JWT, without verifying it
+func parseCredential(credential string) (*credentialClaims, error) {
+	parts := strings.Split(credential, "~")
+	c := &credentialClaims{issuerJWT: parts[0]}
+
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(c.issuerJWT, claims); err != nil {
+		return nil, err
+	}
+
+	// the last part is empty or a key binding JWT
+	disclosed, encoded := map[string][]any{}, map[string]string{}
+	for _, d := range parts[1:max(len(parts)-1, 1)] {
+		if d == "" {
+			continue
+		}
+		decoded, err := base64.RawURLEncoding.DecodeString(d)
+		if err != nil {
+			return nil, err
+		}
+		var disclosure []any
+		if err := json.Unmarshal(decoded, &disclosure); err != nil {
+			return nil, err
+		}
+		if len(disclosure) != 2 && len(disclosure) != 3 {
+			return nil, ErrMalformedDisclosure
+		}
+		digest := hash(d)
+		disclosed[digest], encoded[digest] = disclosure, d
+		c.disclosures = append(c.disclosures, d)
+	}
+
+	c.root = newClaimNode(map[string]any(claims), "", disclosed, encoded)
+
+	return c, nil
+}
+
+// newClaimNode returns the claim tree of value, revealed by disclosure
+func newClaimNode(value any, disclosure string, disclosed map[string][]any, encoded map[string]string) *claimNode {
+	node := &claimNode{disclosure: disclosure}
+
+	switch v := value.(type) {
+	case map[string]any:
+		node.object = map[string]*claimNode{}
+		for k, child := range v {
+			if k == "_sd" || k == "_sd_alg" {
+				continue
+			}
+			node.object[k] = newClaimNode(child, "", disclosed, encoded)
+		}
+		digests, _ := v["_sd"].([]any)
+		for _, digest := range digests {
+			s, _ := digest.(string)
+			d, ok := disclosed[s]
+			if !ok || len(d) != 3 {
+				continue
+			}
+			name, ok := d[1].(string)
+			if !ok {
+				continue
+			}
+			node.object[name] = newClaimNode(d[2], encoded[s], disclosed, encoded)
+		}
+	case []any:
+		node.array = []*claimNode{}
+		for _, item := range v {
+			element, ok := item.(map[string]any)
+			if !ok || len(element) != 1 || element["..."] == nil {
+				node.array = append(node.array, newClaimNode(item, "", disclosed, encoded))
+				continue
+			}
+			s, _ := element["..."].(string)
+			d, ok := disclosed[s]
+			if !ok || len(d) != 2 {
+				continue
+			}
+			node.array = append(node.array, newClaimNode(d[1], encoded[s], disclosed, encoded))
+		}
+	default:
+		node.value = v
+	}
+
+	return node
+}
+
+// plain returns the fully disclosed value of the claim
+func (n *claimNode) plain() any {
+	switch {
+	case n.object != nil:
+		object := make(map[string]any, len(n.object))
+		for k, child := range n.object {
+			object[k] = child.plain()
+		}
+		return object
+	case n.array != nil:
+		array := make([]any, len(n.array))
+		for i, child := range n.array {
+			array[i] = child.plain()
+		}
+		return array
+	default:
+		return n.value
+	}
+}
+
+// subtree returns the disclosures of the sub-claims of n
+func (n *claimNode) subtree() []string {
+	var disclosures []string
+	for _, child := range n.object {
+		disclosures = append(disclosures, child.reveal()...)
+	}
+	for _, child := range n.array {
+		disclosures = append(disclosures, child.reveal()...)
+	}
+	return disclosures
+}
+
+// reveal returns the disclosure of n and those of its sub-claims
+func (n *claimNode) reveal() []string {
+	disclosures := n.subtree()
+	if n.disclosure != "" {
+		disclosures = append(disclosures, n.disclosure)
+	}
+	return disclosures
+}
+
+// find returns the claims selected by path below n, a path to an object or an array selects all of its sub-claims.
+// A null component selects every element of an array or every member of an object.
+func (n *claimNode) find(path []any, ancestors []string) []claimSelection {
+	if n.disclosure != "" {
+		ancestors = append(slices.Clip(ancestors), n.disclosure)
+	}
+
+	if len(path) == 0 {
+		return []claimSelection{{node: n, disclosures: append(slices.Clone(ancestors), n.subtree()...)}}
+	}
+
+	var children []*claimNode
+	switch component := path[0].(type) {
+	case string:
+		if child, ok := n.object[component]; ok {
+			children = append(children, child)
+		}
+	case nil:
+		children = n.array
+		for _, k := range sortedKeys(n.object) {
+			children = append(children, n.object[k])
+		}
+	default:
+		i, ok := pathIndex(component)
+		if ok && i >= 0 && i < len(n.array) {
+			children = append(children, n.array[i])
+		}
+	}
+
+	var selections []claimSelection
+	for _, child := range children {
+		selections = append(selections, child.find(path[1:], ancestors)...)
+	}
+	return selections
+}
+
+// pathIndex returns an array index of a claims path, decoded JSON numbers are float64
+func pathIndex(component any) (int, bool) {
+	switch i := component.(type) {
+	case int:
+		return i, true
+	case int64:
+		return int(i), true
+	case float64:
+		return int(i), i == float64(int(i))
+	case json.Number:
+		n, err := strconv.Atoi(i.String())
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// vct returns the vct claim, it is never selectively disclosed
+func (c *credentialClaims) vct() string {
+	if node, ok := c.root.object["vct"]; ok {
+		vct, _ := node.value.(string)
+		return vct
+	}
+	return ""
+}
+
+// ordered returns the disclosures of set in the order of the credential
+func (c *credentialClaims) ordered(set map[string]bool) []string {
+	disclosures := []string{}
+	for _, d := range c.disclosures {
+		if set[d] {
+			disclosures = append(disclosures, d)
+		}
+	}
+	return disclosures
+}
+
+// MatchDCQL returns the least set of disclosures of credential that satisfies a credential query of query, the
+// first credential query the credential satisfies is used. Of the claim sets of a credential query the one with the
+// fewest disclosures is used, the first of them on a tie. A *QueryError tells why no credential query is satisfied.
+func MatchDCQL(credential string, query *DCQLQuery) (*QueryMatch, error) {
+	c, err := parseCredential(credential)
+	if err != nil {
+		return nil, err
+	}
+
+	queryErr := &QueryError{}
+	for _, credentialQuery := range query.Credentials {
+		disclosures, err := c.matchCredentialQuery(&credentialQuery)
+		if err != nil {
+			queryErr.Reasons = append(queryErr.Reasons, fmt.Sprintf("credential query %s: %s", credentialQuery.ID, err))
+			continue
+		}
+		return &QueryMatch{QueryID: credentialQuery.ID, Disclosures: c.ordered(disclosures)}, nil
+	}
+
+	if len(queryErr.Reasons) == 0 {
+		queryErr.Reasons = []string{"no credential queries"}
+	}
+	return nil, queryErr
+}
+
+func (c *credentialClaims) matchCredentialQuery(query *DCQLCredentialQuery) (map[string]bool, error) {
+	if !slices.Contains(formats, query.Format) {
+		return nil, fmt.Errorf("format %s is not an sd-jwt format", query.Format)
+	}
+	if len(query.Meta.VCTValues) > 0 && !slices.Contains(query.Meta.VCTValues, c.vct()) {
+		return nil, fmt.Errorf("vct %s is not one of %s", c.vct(), strings.Join(query.Meta.VCTValues, ", "))
+	}
+
+	claims := map[string]map[string]bool{}
+	var missing []string
+	for i, claim := range query.Claims {
+		id := claim.ID
+		if id == "" {
+			id = strconv.Itoa(i)
+		}
+		disclosures, ok := c.matchClaim(&claim)
+		if !ok {
+			missing = append(missing, fmt.Sprintf("claim %s", pathString(claim.Path)))
+			continue
+		}
+		claims[id] = disclosures
+	}
+
+	if len(query.ClaimSets) == 0 {
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("no %s", strings.Join(missing, ", no "))
+		}
+		return union(claims, nil), nil
+	}
+
+	var best map[string]bool
+	for _, claimSet := range query.ClaimSets {
+		if !containsAll(claims, claimSet) {
+			continue
+		}
+		disclosures := union(claims, claimSet)
+		if best == nil || len(disclosures) < len(best) {
+			best = disclosures
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("no claim set is satisfied")
+	}
+
+	return best, nil
+}
+
+// matchClaim returns the disclosures of the claims selected by claim, false if none is selected
+func (c *credentialClaims) matchClaim(claim *DCQLClaim) (map[string]bool, bool) {
+	disclosures := map[string]bool{}
+	matched := false
+	for _, selection := range c.root.find(claim.Path, nil) {
+		if len(claim.Values) > 0 && !slices.ContainsFunc(claim.Values, func(v any) bool { return jsonEqual(v, selection.node.plain()) }) {
+			continue
+		}
+		matched = true
+		for _, d := range selection.disclosures {
+			disclosures[d] = true
+		}
+	}
+	return disclosures, matched
+}
+
+// MatchPresentationDefinition returns the least set of disclosures of credential that satisfies an input descriptor
+// of definition, the first input descriptor the credential satisfies is used. Optional fields are not disclosed and
+// of a path selecting several claims only the first the filter accepts is. A *QueryError tells why no input
+// descriptor is satisfied.
+func MatchPresentationDefinition(credential string, definition *PresentationDefinition) (*QueryMatch, error) {
+	c, err := parseCredential(credential)
+	if err != nil {
+		return nil, err
+	}
+
+	queryErr := &QueryError{}
+	for _, descriptor := range definition.InputDescriptors {
+		disclosures, err := c.matchInputDescriptor(&descriptor)
+		if err != nil {
+			queryErr.Reasons = append(queryErr.Reasons, fmt.Sprintf("input descriptor %s: %s", descriptor.ID, err))
+			continue
+		}
+		return &QueryMatch{QueryID: descriptor.ID, Disclosures: c.ordered(disclosures)}, nil
+	}
+
+	if len(queryErr.Reasons) == 0 {
+		queryErr.Reasons = []string{"no input descriptors"}
+	}
+	return nil, queryErr
+}
+
+func (c *credentialClaims) matchInputDescriptor(descriptor *InputDescriptor) (map[string]bool, error) {
+	if len(descriptor.Format) > 0 && !slices.ContainsFunc(formats, func(format string) bool {
+		_, ok := descriptor.Format[format]
+		return ok
+	}) {
+		return nil, fmt.Errorf("no sd-jwt format")
+	}
+
+	disclosures := map[string]bool{}
+	for _, field := range descriptor.Constraints.Fields {
+		if field.Optional {
+			continue
+		}
+
+		selection, err := c.matchField(&field)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range selection.disclosures {
+			disclosures[d] = true
+		}
+	}
+
+	return disclosures, nil
+}
+
+// matchField returns the first claim of the paths of field the filter accepts
+func (c *credentialClaims) matchField(field *Field) (*claimSelection, error) {
+	var filter *jsonschema.Schema
+	if len(field.Filter) > 0 {
+		var err error
+		if filter, err = compileFilter(field.Filter); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, jsonPath := range field.Path {
+		path, err := parseJSONPath(jsonPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, selection := range c.root.find(path, nil) {
+			if filter != nil && filter.Validate(selection.node.plain()) != nil {
+				continue
+			}
+			return &selection, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no field %s", strings.Join(field.Path, " or "))
+}
+
+// compileFilter compiles the JSON Schema filter of a field
+func compileFilter(filter json.RawMessage) (*jsonschema.Schema, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(filter))
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("filter.json", doc); err != nil {
+		return nil, err
+	}
+
+	return compiler.Compile("filter.json")
+}
+
+// parseJSONPath returns the claims path of the JSONPath subset used in presentation definitions: $ followed by
+// .name, ['name'], ["name"], [index], [*] or .*
+func parseJSONPath(jsonPath string) ([]any, error) {
+	rest, ok := strings.CutPrefix(jsonPath, "$")
+	if !ok {
+		return nil, fmt.Errorf("json path %s does not start with $", jsonPath)
+	}
+
+	path := []any{}
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, ".*"):
+			path = append(path, nil)
+			rest = rest[2:]
+		case rest[0] == '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("json path %s has an empty name", jsonPath)
+			}
+			path = append(path, rest[1:end+1])
+			rest = rest[end+1:]
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("json path %s has an unclosed [", jsonPath)
+			}
+			component := rest[1:end]
+			switch {
+			case component == "*":
+				path = append(path, nil)
+			case len(component) >= 2 && (component[0] == '\'' || component[0] == '"') && component[len(component)-1] == component[0]:
+				path = append(path, component[1:len(component)-1])
+			default:
+				i, err := strconv.Atoi(component)
+				if err != nil {
+					return nil, fmt.Errorf("json path %s has an unsupported selector [%s]", jsonPath, component)
+				}
+				path = append(path, i)
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("json path %s is not supported", jsonPath)
+		}
+	}
+
+	return path, nil
+}
+
+// pathString returns a claims path as a JSON array, for error messages
+func pathString(path []any) string {
+	b, _ := json.Marshal(path)
+	return string(b)
+}
+
+func jsonEqual(a, b any) bool {
+	x, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	y, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(x, y)
+}
+
+// union returns the disclosures of the claims of ids, of all claims if ids is nil
+func union(claims map[string]map[string]bool, ids []string) map[string]bool {
+	disclosures := map[string]bool{}
+	for id, claim := range claims {
+		if ids != nil && !slices.Contains(ids, id) {
+			continue
+		}
+		for d := range claim {
+			disclosures[d] = true
+		}
+	}
+	return disclosures
+}
+
+func sortedKeys(m map[string]*claimNode) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func containsAll(claims map[string]map[string]bool, ids []string) bool {
+	for _, id := range ids {
+		if _, ok := claims[id]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,225 @@
+package sdjwt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockQueryCredential returns an SD-JWT with selectively disclosable given_name, family_name, address and its
+// country and locality and the elements of nationalities, and its disclosures by name
+func mockQueryCredential(t *testing.T) (string, map[string]string) {
+	disclosure := func(v ...any) string {
+		b, err := json.Marshal(v)
+		assert.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+
+	d := map[string]string{
+		"given_name":  disclosure("salt1", "given_name", "Magnus"),
+		"family_name": disclosure("salt2", "family_name", "Svensson"),
+		"country":     disclosure("salt3", "country", "SE"),
+		"locality":    disclosure("salt4", "locality", "Stockholm"),
+		"SE":          disclosure("salt6", "SE"),
+		"FI":          disclosure("salt7", "FI"),
+	}
+	d["address"] = disclosure("salt5", "address", map[string]any{"_sd": []any{hash(d["country"]), hash(d["locality"])}})
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iss":           "https://issuer.sunet.se",
+		"vct":           "https://credential.sunet.se/pid",
+		"_sd_alg":       "sha-256",
+		"_sd":           []any{hash(d["given_name"]), hash(d["family_name"]), hash(d["address"])},
+		"nationalities": []any{map[string]any{"...": hash(d["SE"])}, map[string]any{"...": hash(d["FI"])}},
+	})
+	signed, err := token.SignedString([]byte("secret"))
+	assert.NoError(t, err)
+
+	order := []string{"given_name", "family_name", "address", "country", "locality", "SE", "FI"}
+	disclosures := make([]string, len(order))
+	for i, name := range order {
+		disclosures[i] = d[name]
+	}
+
+	return signed + "~" + strings.Join(disclosures, "~") + "~", d
+}
+
+func TestMatchDCQL(t *testing.T) {
+	credential, d := mockQueryCredential(t)
+
+	tts := []struct {
+		name            string
+		query           string
+		wantQueryID     string
+		wantDisclosures []string
+		wantErr         bool
+	}{
+		{
+			name:            "claims",
+			query:           `{"credentials":[{"id":"pid","format":"dc+sd-jwt","meta":{"vct_values":["https://credential.sunet.se/pid"]},"claims":[{"path":["given_name"]},{"path":["address","country"]}]}]}`,
+			wantQueryID:     "pid",
+			wantDisclosures: []string{d["given_name"], d["address"], d["country"]},
+		},
+		{
+			name:            "object with its sub-claims",
+			query:           `{"credentials":[{"id":"pid","format":"dc+sd-jwt","claims":[{"path":["address"]}]}]}`,
+			wantQueryID:     "pid",
+			wantDisclosures: []string{d["address"], d["country"], d["locality"]},
+		},
+		{
+			name:            "array element by value",
+			query:           `{"credentials":[{"id":"pid","format":"dc+sd-jwt","claims":[{"path":["nationalities",null],"values":["FI"]}]}]}`,
+			wantQueryID:     "pid",
+			wantDisclosures: []string{d["FI"]},
+		},
+		{
+			name:            "array element by index",
+			query:           `{"credentials":[{"id":"pid","format":"dc+sd-jwt","claims":[{"path":["nationalities",0]}]}]}`,
+			wantQueryID:     "pid",
+			wantDisclosures: []string{d["SE"]},
+		},
+		{
+			name:            "smallest claim set",
+			query:           `{"credentials":[{"id":"pid","format":"dc+sd-jwt","claims":[{"id":"a","path":["address","locality"]},{"id":"g","path":["given_name"]},{"id":"b","path":["birth_date"]}],"claim_sets":[["b"],["a"],["g"]]}]}`,
+			wantQueryID:     "pid",
+			wantDisclosures: []string{d["given_name"]},
+		},
+		{
+			name:            "no claims",
+			query:           `{"credentials":[{"id":"pid","format":"vc+sd-jwt"}]}`,
+			wantQueryID:     "pid",
+			wantDisclosures: []string{},
+		},
+		{
+			name:            "second credential query",
+			query:           `{"credentials":[{"id":"mdl","format":"mso_mdoc"},{"id":"pid","format":"dc+sd-jwt","claims":[{"path":["family_name"]}]}]}`,
+			wantQueryID:     "pid",
+			wantDisclosures: []string{d["family_name"]},
+		},
+		{
+			name:    "other vct",
+			query:   `{"credentials":[{"id":"ehic","format":"dc+sd-jwt","meta":{"vct_values":["https://credential.sunet.se/ehic"]}}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "missing claim",
+			query:   `{"credentials":[{"id":"pid","format":"dc+sd-jwt","claims":[{"path":["given_name"]},{"path":["birth_date"]}]}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "value not accepted",
+			query:   `{"credentials":[{"id":"pid","format":"dc+sd-jwt","claims":[{"path":["address","country"],"values":["NO"]}]}]}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			query := &DCQLQuery{}
+			assert.NoError(t, json.Unmarshal([]byte(tt.query), query))
+
+			got, err := MatchDCQL(credential, query)
+			if tt.wantErr {
+				var queryErr *QueryError
+				assert.True(t, errors.As(err, &queryErr), "got %v", err)
+				assert.ErrorIs(t, err, ErrQueryNotSatisfied)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantQueryID, got.QueryID)
+			assert.Equal(t, tt.wantDisclosures, got.Disclosures)
+		})
+	}
+}
+
+func TestMatchPresentationDefinition(t *testing.T) {
+	credential, d := mockQueryCredential(t)
+
+	tts := []struct {
+		name            string
+		definition      string
+		wantQueryID     string
+		wantDisclosures []string
+		wantErr         bool
+	}{
+		{
+			name:            "fields",
+			definition:      `{"id":"pd","input_descriptors":[{"id":"pid","format":{"vc+sd-jwt":{}},"constraints":{"limit_disclosure":"required","fields":[{"path":["$.vct"],"filter":{"const":"https://credential.sunet.se/pid"}},{"path":["$.birth_date","$['given_name']"]},{"path":["$.address.locality"],"optional":true}]}}]}`,
+			wantQueryID:     "pid",
+			wantDisclosures: []string{d["given_name"]},
+		},
+		{
+			name:            "first array element the filter accepts",
+			definition:      `{"id":"pd","input_descriptors":[{"id":"pid","constraints":{"fields":[{"path":["$.nationalities[*]"],"filter":{"type":"string","enum":["FI","NO"]}}]}}]}`,
+			wantQueryID:     "pid",
+			wantDisclosures: []string{d["FI"]},
+		},
+		{
+			name:            "nested field",
+			definition:      `{"id":"pd","input_descriptors":[{"id":"pid","constraints":{"fields":[{"path":["$.address['country']"],"filter":{"type":"string","pattern":"^[A-Z]{2}$"}}]}}]}`,
+			wantQueryID:     "pid",
+			wantDisclosures: []string{d["address"], d["country"]},
+		},
+		{
+			name:       "filter not accepted",
+			definition: `{"id":"pd","input_descriptors":[{"id":"pid","constraints":{"fields":[{"path":["$.family_name"],"filter":{"const":"Andersson"}}]}}]}`,
+			wantErr:    true,
+		},
+		{
+			name:       "not an sd-jwt format",
+			definition: `{"id":"pd","input_descriptors":[{"id":"mdl","format":{"mso_mdoc":{}},"constraints":{}}]}`,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			definition := &PresentationDefinition{}
+			assert.NoError(t, json.Unmarshal([]byte(tt.definition), definition))
+
+			got, err := MatchPresentationDefinition(credential, definition)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrQueryNotSatisfied)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantQueryID, got.QueryID)
+			assert.Equal(t, tt.wantDisclosures, got.Disclosures)
+		})
+	}
+}
+
+func TestParseJSONPath(t *testing.T) {
+	tts := []struct {
+		jsonPath string
+		want     []any
+		wantErr  bool
+	}{
+		{jsonPath: "$.vct", want: []any{"vct"}},
+		{jsonPath: "$.address.country", want: []any{"address", "country"}},
+		{jsonPath: `$['address']["street_address"]`, want: []any{"address", "street_address"}},
+		{jsonPath: "$.nationalities[1]", want: []any{"nationalities", 1}},
+		{jsonPath: "$.nationalities[*]", want: []any{"nationalities", nil}},
+		{jsonPath: "$.address.*", want: []any{"address", nil}},
+		{jsonPath: "vct", wantErr: true},
+		{jsonPath: "$..vct", wantErr: true},
+		{jsonPath: "$.nationalities[?(@ == 'SE')]", wantErr: true},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.jsonPath, func(t *testing.T) {
+			got, err := parseJSONPath(tt.jsonPath)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package sdjwt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+)
+
+// saltSource hands out the salts of disclosures. Claims that a previous credential disclosed with the same name and
+// value get the salt of that disclosure, so that their digests are the same in the refreshed credential. A nil
+// saltSource always returns new salts.
+type saltSource struct {
+	// previous holds the previous disclosures not yet reused, by claim name
+	previous map[string][]previousDisclosure
+}
+
+type previousDisclosure struct {
+	salt       string
+	disclosure string
+}
+
+// newSaltSource returns a saltSource reusing the salts of disclosures, nil if there are none
+func newSaltSource(disclosures []string) *saltSource {
+	if len(disclosures) == 0 {
+		return nil
+	}
+
+	s := &saltSource{previous: map[string][]previousDisclosure{}}
+	for _, disclosure := range disclosures {
+		salt, name, ok := disclosureSaltAndName(disclosure)
+		if !ok {
+			continue
+		}
+		s.previous[name] = append(s.previous[name], previousDisclosure{salt: salt, disclosure: disclosure})
+	}
+
+	return s
+}
+
+// salt returns the salt of the previous disclosure of name that disclosure, the disclosure of the claim with a
+// salt, reproduces, or a new salt. Each previous disclosure is reused once.
+func (s *saltSource) salt(name string, disclosure func(salt string) string) string {
+	if s == nil {
+		return newSalt()
+	}
+
+	for i, previous := range s.previous[name] {
+		if disclosure(previous.salt) == previous.disclosure {
+			s.previous[name] = append(s.previous[name][:i], s.previous[name][i+1:]...)
+			return previous.salt
+		}
+	}
+
+	return newSalt()
+}
+
+// disclosureSaltAndName returns the salt and claim name, the first elements of a disclosure. Only these are decoded,
+// the value is compared as the encoded disclosure.
+func disclosureSaltAndName(disclosure string) (string, string, bool) {
+	decoded, err := base64.RawURLEncoding.DecodeString(disclosure)
+	if err != nil {
+		return "", "", false
+	}
+
+	dec := json.NewDecoder(strings.NewReader(string(decoded)))
+	if token, err := dec.Token(); err != nil || token != json.Delim('[') {
+		return "", "", false
+	}
+	salt, err := dec.Token()
+	if err != nil {
+		return "", "", false
+	}
+	name, err := dec.Token()
+	if err != nil {
+		return "", "", false
+	}
+
+	saltString, ok := salt.(string)
+	if !ok {
+		return "", "", false
+	}
+	nameString, ok := name.(string)
+	if !ok {
+		return "", "", false
+	}
+
+	return saltString, nameString, true
+}
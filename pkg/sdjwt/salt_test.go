@@ -0,0 +1,150 @@
+package sdjwt
+
+import (
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// uniqueSalts makes newSalt return distinct salts for the test, other tests replace it with a constant
+func uniqueSalts(t *testing.T) {
+	previous := newSalt
+	n := 0
+	newSalt = func() string {
+		n++
+		return fmt.Sprintf("salt_%d", n)
+	}
+	t.Cleanup(func() { newSalt = previous })
+}
+
+func TestRandomSalt(t *testing.T) {
+	salt := randomSalt()
+	assert.Len(t, salt, 22)
+	assert.NotEqual(t, salt, randomSalt())
+
+	disclosure := base64.RawURLEncoding.EncodeToString([]byte(`["` + salt + `","given_name","Magnus"]`))
+	assert.NoError(t, CheckSalts([]string{disclosure}, 128), "issued salts pass the default verifier policy")
+}
+
+func saltTestInstructions(familyName string) InstructionsV2 {
+	return InstructionsV2{
+		&ChildInstructionV2{Name: "given_name", Value: "Magnus", SelectiveDisclosure: true},
+		&ChildInstructionV2{Name: "family_name", Value: familyName, SelectiveDisclosure: true},
+		&ParentInstructionV2{
+			Name:                "address",
+			SelectiveDisclosure: true,
+			Children: []any{
+				&ChildInstructionV2{Name: "country", Value: "SE"},
+			},
+		},
+		&RecursiveInstructionV2{
+			Name: "nationalities",
+			Children: []any{
+				&ChildInstructionV2{Name: "first", Value: "SE"},
+			},
+		},
+	}
+}
+
+func TestPreviousDisclosures(t *testing.T) {
+	uniqueSalts(t)
+
+	_, previous, err := saltTestInstructions("Svensson").Unsigned(&Config{})
+	assert.NoError(t, err)
+
+	tts := []struct {
+		name       string
+		familyName string
+		want       int
+	}{
+		{
+			name:       "unchanged",
+			familyName: "Svensson",
+			want:       5,
+		},
+		{
+			name:       "family_name changed",
+			familyName: "Karlsson",
+			want:       4,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			_, disclosures, err := saltTestInstructions(tt.familyName).Unsigned(&Config{PreviousDisclosures: previous.ArrayHashes()})
+			assert.NoError(t, err)
+
+			kept := 0
+			for _, d := range disclosures.ArrayHashes() {
+				if slices.Contains(previous.ArrayHashes(), d) {
+					kept++
+				}
+			}
+			assert.Equal(t, tt.want, kept)
+			assert.Len(t, disclosures, 5)
+		})
+	}
+}
+
+func TestPreviousDisclosuresReusedOnce(t *testing.T) {
+	uniqueSalts(t)
+
+	instructions := func() InstructionsV2 {
+		return InstructionsV2{
+			&ChildArrayInstructionV2{
+				Name: "nationalities",
+				Children: []ChildInstructionV2{
+					{Name: "nationality", Value: "SE", SelectiveDisclosure: true},
+					{Name: "nationality", Value: "SE", SelectiveDisclosure: true},
+				},
+			},
+		}
+	}
+
+	_, previous, err := instructions().Unsigned(&Config{})
+	assert.NoError(t, err)
+
+	_, disclosures, err := instructions().Unsigned(&Config{PreviousDisclosures: previous.ArrayHashes()})
+	assert.NoError(t, err)
+
+	assert.Equal(t, previous.ArrayHashes(), disclosures.ArrayHashes())
+	assert.NotEqual(t, disclosures.ArrayHashes()[0], disclosures.ArrayHashes()[1])
+}
+
+func TestDisclosureSaltAndName(t *testing.T) {
+	tts := []struct {
+		name       string
+		disclosure string
+		wantSalt   string
+		wantName   string
+		wantOK     bool
+	}{
+		{
+			name:       "string value",
+			disclosure: "WyJzYWx0X3p5eCIsImdpdmVuX25hbWUiLCJNYWdudXMiXQ",
+			wantSalt:   "salt_zyx",
+			wantName:   "given_name",
+			wantOK:     true,
+		},
+		{
+			name:       "not base64url",
+			disclosure: "not a disclosure!",
+		},
+		{
+			name:       "not an array",
+			disclosure: "eyJzYWx0Ijoic2FsdF96eXgifQ",
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			salt, name, ok := disclosureSaltAndName(tt.disclosure)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantSalt, salt)
+			assert.Equal(t, tt.wantName, name)
+		})
+	}
+}
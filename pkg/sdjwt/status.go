@@ -0,0 +1,60 @@
+package sdjwt
+
+import (
+	"context"
+	"encoding/json"
+	"vc/pkg/statuslist"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// StatusFetcher looks up the status of a referenced token in its status list
+type StatusFetcher interface {
+	FetchStatus(ctx context.Context, ref *statuslist.StatusReference) (uint8, error)
+}
+
+// ParseStatus returns the status_list reference of the status claim, nil if the credential has no status
+func ParseStatus(claims jwt.MapClaims) (*statuslist.StatusReference, error) {
+	v, ok := claims["status"]
+	if !ok || v == nil || v == "" {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &statuslist.Status{}
+	if err := json.Unmarshal(b, status); err != nil {
+		return nil, ErrMalformedStatus
+	}
+	if status.StatusList == nil || status.StatusList.URI == "" || status.StatusList.Idx < 0 {
+		return nil, ErrMalformedStatus
+	}
+
+	return status.StatusList, nil
+}
+
+// CheckStatus fetches the status of a credential with a status claim, ErrCredentialRevoked or ErrCredentialSuspended
+// is returned unless the status is valid. Credentials without a status claim pass.
+func CheckStatus(ctx context.Context, claims jwt.MapClaims, fetcher StatusFetcher) error {
+	ref, err := ParseStatus(claims)
+	if err != nil || ref == nil {
+		return err
+	}
+
+	status, err := fetcher.FetchStatus(ctx, ref)
+	if err != nil {
+		return err
+	}
+
+	switch status {
+	case statuslist.StatusValid:
+		return nil
+	case statuslist.StatusSuspended:
+		return ErrCredentialSuspended
+	default:
+		return ErrCredentialRevoked
+	}
+}
@@ -0,0 +1,79 @@
+package sdjwt
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"vc/pkg/statuslist"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockStatusFetcher returns the status at the index of the reference
+type mockStatusFetcher []uint8
+
+func (m mockStatusFetcher) FetchStatus(ctx context.Context, ref *statuslist.StatusReference) (uint8, error) {
+	if ref.Idx >= int64(len(m)) {
+		return 0, errors.New("index out of range")
+	}
+	return m[ref.Idx], nil
+}
+
+func TestCheckStatus(t *testing.T) {
+	fetcher := mockStatusFetcher{statuslist.StatusValid, statuslist.StatusInvalid, statuslist.StatusSuspended}
+
+	status := func(idx int64) jwt.MapClaims {
+		payload, _, err := InstructionsV2{}.Unsigned(&Config{
+			StatusList: &statuslist.StatusReference{Idx: idx, URI: "https://registry.sunet.se/statuslists/token"},
+		})
+		assert.NoError(t, err)
+		return payload
+	}
+
+	tts := []struct {
+		name   string
+		claims jwt.MapClaims
+		want   error
+	}{
+		{
+			name:   "no status claim",
+			claims: jwt.MapClaims{},
+		},
+		{
+			name:   "empty status claim",
+			claims: jwt.MapClaims{"status": ""},
+		},
+		{
+			name:   "valid",
+			claims: status(0),
+		},
+		{
+			name:   "revoked",
+			claims: status(1),
+			want:   ErrCredentialRevoked,
+		},
+		{
+			name:   "suspended",
+			claims: status(2),
+			want:   ErrCredentialSuspended,
+		},
+		{
+			name:   "no status_list",
+			claims: jwt.MapClaims{"status": map[string]any{"other": true}},
+			want:   ErrMalformedStatus,
+		},
+		{
+			name:   "not an object",
+			claims: jwt.MapClaims{"status": 1},
+			want:   ErrMalformedStatus,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckStatus(context.Background(), tt.claims, fetcher)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
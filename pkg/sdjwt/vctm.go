@@ -0,0 +1,286 @@
+package sdjwt
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	gohash "hash"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// maxVCTMSize limits the size of a fetched type metadata document
+const maxVCTMSize = 1 << 20
+
+// VCTM is SD-JWT VC type metadata, fields not used by this package are kept in Raw
+type VCTM struct {
+	VCT                string          `json:"vct"`
+	Name               string          `json:"name,omitempty"`
+	Description        string          `json:"description,omitempty"`
+	Extends            string          `json:"extends,omitempty"`
+	ExtendsIntegrity   string          `json:"extends#integrity,omitempty"`
+	Display            []VCTMDisplay   `json:"display,omitempty"`
+	Claims             []VCTMClaim     `json:"claims,omitempty"`
+	Schema             json.RawMessage `json:"schema,omitempty"`
+	SchemaURI          string          `json:"schema_uri,omitempty"`
+	SchemaURIIntegrity string          `json:"schema_uri#integrity,omitempty"`
+
+	// Raw is the document as fetched, integrity is computed over it
+	Raw []byte `json:"-"`
+
+	schemaOnce sync.Once
+	schema     *jsonschema.Schema
+	schemaErr  error
+}
+
+// VCTMDisplay is the display metadata of a credential type for one locale
+type VCTMDisplay struct {
+	Lang        string         `json:"lang"`
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Rendering   *VCTMRendering `json:"rendering,omitempty"`
+}
+
+// VCTMRendering holds the rendering methods of a credential type
+type VCTMRendering struct {
+	Simple       *VCTMSimpleRendering `json:"simple,omitempty"`
+	SVGTemplates []VCTMSVGTemplate    `json:"svg_templates,omitempty"`
+}
+
+// VCTMSimpleRendering is the simple rendering method
+type VCTMSimpleRendering struct {
+	Logo            *VCTMLogo `json:"logo,omitempty"`
+	BackgroundColor string    `json:"background_color,omitempty"`
+	TextColor       string    `json:"text_color,omitempty"`
+}
+
+// VCTMLogo is a logo reference
+type VCTMLogo struct {
+	URI          string `json:"uri"`
+	URIIntegrity string `json:"uri#integrity,omitempty"`
+	AltText      string `json:"alt_text,omitempty"`
+}
+
+// VCTMSVGTemplate is an SVG template reference
+type VCTMSVGTemplate struct {
+	URI          string         `json:"uri"`
+	URIIntegrity string         `json:"uri#integrity,omitempty"`
+	Properties   map[string]any `json:"properties,omitempty"`
+}
+
+// VCTMClaim is the metadata of one claim, path elements are strings for object keys, integers for array indexes and null for all array elements
+type VCTMClaim struct {
+	Path    []any              `json:"path"`
+	Display []VCTMClaimDisplay `json:"display,omitempty"`
+	SD      string             `json:"sd,omitempty"`
+	SVGID   string             `json:"svg_id,omitempty"`
+}
+
+// VCTMClaimDisplay is the display metadata of a claim for one locale
+type VCTMClaimDisplay struct {
+	Lang        string `json:"lang"`
+	Label       string `json:"label"`
+	Description string `json:"description,omitempty"`
+}
+
+// Integrity returns the sha256 subresource integrity string of b, used as vct#integrity
+func Integrity(b []byte) string {
+	sum := sha256.Sum256(b)
+	return "sha256-" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// verifyIntegrity checks b against a subresource integrity string, e.g. "sha256-...", with space separated alternatives
+func verifyIntegrity(b []byte, integrity string) error {
+	for _, alternative := range strings.Fields(integrity) {
+		alg, digest, ok := strings.Cut(alternative, "-")
+		if !ok {
+			continue
+		}
+
+		var h gohash.Hash
+		switch alg {
+		case "sha256":
+			h = sha256.New()
+		case "sha384":
+			h = sha512.New384()
+		case "sha512":
+			h = sha512.New()
+		default:
+			continue
+		}
+		h.Write(b)
+
+		want, err := base64.StdEncoding.DecodeString(digest)
+		if err != nil {
+			continue
+		}
+		if subtle.ConstantTimeCompare(h.Sum(nil), want) == 1 {
+			return nil
+		}
+	}
+
+	return ErrIntegrityMismatch
+}
+
+type vctmCacheEntry struct {
+	vctm      *VCTM
+	expiresAt time.Time
+}
+
+// VCTMResolver fetches type metadata from the vct URL and caches it
+type VCTMResolver struct {
+	httpClient *http.Client
+	ttl        time.Duration
+	allowList  []string
+
+	mu    sync.Mutex
+	cache map[string]*vctmCacheEntry
+}
+
+// NewVCTMResolver creates a resolver, allowList holds vct URL prefixes, an empty allow list allows any https vct
+func NewVCTMResolver(httpClient *http.Client, ttl time.Duration, allowList []string) *VCTMResolver {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &VCTMResolver{
+		httpClient: httpClient,
+		ttl:        ttl,
+		allowList:  allowList,
+		cache:      map[string]*vctmCacheEntry{},
+	}
+}
+
+func (r *VCTMResolver) allowed(vct string) bool {
+	if len(r.allowList) == 0 {
+		return strings.HasPrefix(vct, "https://")
+	}
+	for _, prefix := range r.allowList {
+		if strings.HasPrefix(vct, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve returns the type metadata of vct, if integrity is not empty the document must match it
+func (r *VCTMResolver) Resolve(ctx context.Context, vct, integrity string) (*VCTM, error) {
+	if !r.allowed(vct) {
+		return nil, fmt.Errorf("%w: %s", ErrVCTNotAllowed, vct)
+	}
+
+	vctm, err := r.cached(ctx, vct)
+	if err != nil {
+		return nil, err
+	}
+
+	if integrity != "" {
+		if err := verifyIntegrity(vctm.Raw, integrity); err != nil {
+			return nil, err
+		}
+	}
+
+	return vctm, nil
+}
+
+// cached returns vct from the cache, fetching it if missing or expired
+func (r *VCTMResolver) cached(ctx context.Context, vct string) (*VCTM, error) {
+	r.mu.Lock()
+	entry, ok := r.cache[vct]
+	r.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.vctm, nil
+	}
+
+	vctm, err := r.fetch(ctx, vct)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.cache[vct] = &vctmCacheEntry{vctm: vctm, expiresAt: time.Now().Add(r.ttl)}
+	r.mu.Unlock()
+
+	return vctm, nil
+}
+
+func (r *VCTMResolver) fetch(ctx context.Context, vct string) (*VCTM, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, vct, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch vctm %s: %s", vct, resp.Status)
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxVCTMSize))
+	if err != nil {
+		return nil, err
+	}
+
+	vctm := &VCTM{}
+	if err := json.Unmarshal(raw, vctm); err != nil {
+		return nil, err
+	}
+	if vctm.VCT != vct {
+		return nil, fmt.Errorf("vctm vct %q does not match %q", vctm.VCT, vct)
+	}
+	vctm.Raw = raw
+
+	if len(vctm.Schema) == 0 && vctm.SchemaURI != "" {
+		vctm.Schema, err = r.fetchSchema(ctx, vctm)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return vctm, nil
+}
+
+// fetchSchema fetches the schema referenced by schema_uri and checks it against schema_uri#integrity
+func (r *VCTMResolver) fetchSchema(ctx context.Context, vctm *VCTM) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, vctm.SchemaURI, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch vctm schema %s: %s", vctm.SchemaURI, resp.Status)
+	}
+
+	schema, err := io.ReadAll(io.LimitReader(resp.Body, maxVCTMSize))
+	if err != nil {
+		return nil, err
+	}
+
+	if vctm.SchemaURIIntegrity != "" {
+		if err := verifyIntegrity(schema, vctm.SchemaURIIntegrity); err != nil {
+			return nil, err
+		}
+	}
+
+	return schema, nil
+}
@@ -0,0 +1,165 @@
+package sdjwt
+
+import (
+	"strings"
+)
+
+// Rendering is a credential localized for display, claims are in the order of the type metadata
+type Rendering struct {
+	Lang            string            `json:"lang,omitempty"`
+	Name            string            `json:"name,omitempty"`
+	Description     string            `json:"description,omitempty"`
+	Logo            *VCTMLogo         `json:"logo,omitempty"`
+	BackgroundColor string            `json:"background_color,omitempty"`
+	TextColor       string            `json:"text_color,omitempty"`
+	SVGTemplates    []VCTMSVGTemplate `json:"svg_templates,omitempty"`
+	Claims          []RenderedClaim   `json:"claims"`
+}
+
+// RenderedClaim is one labeled claim value
+type RenderedClaim struct {
+	Path        []any  `json:"path"`
+	Label       string `json:"label"`
+	Description string `json:"description,omitempty"`
+	Value       any    `json:"value"`
+	SVGID       string `json:"svg_id,omitempty"`
+}
+
+// localeIndex returns the index of the entry best matching locale: exact match, then same language, then the first entry
+func localeIndex(langs []string, locale string) int {
+	if len(langs) == 0 {
+		return -1
+	}
+	for i, lang := range langs {
+		if strings.EqualFold(lang, locale) {
+			return i
+		}
+	}
+	language, _, _ := strings.Cut(locale, "-")
+	for i, lang := range langs {
+		l, _, _ := strings.Cut(lang, "-")
+		if strings.EqualFold(l, language) {
+			return i
+		}
+	}
+	return 0
+}
+
+// Render returns the disclosed claims of a verified credential labeled for locale, e.g. "sv-SE".
+// Claims without a value, e.g. not disclosed, are left out, as are claims not described by the type metadata.
+func (v *VCTM) Render(claims map[string]any, locale string) *Rendering {
+	rendering := &Rendering{Claims: []RenderedClaim{}}
+
+	langs := make([]string, len(v.Display))
+	for i, d := range v.Display {
+		langs[i] = d.Lang
+	}
+	if i := localeIndex(langs, locale); i >= 0 {
+		display := v.Display[i]
+		rendering.Lang = display.Lang
+		rendering.Name = display.Name
+		rendering.Description = display.Description
+		if display.Rendering != nil {
+			if simple := display.Rendering.Simple; simple != nil {
+				rendering.Logo = simple.Logo
+				rendering.BackgroundColor = simple.BackgroundColor
+				rendering.TextColor = simple.TextColor
+			}
+			rendering.SVGTemplates = display.Rendering.SVGTemplates
+		}
+	}
+
+	for _, claim := range v.Claims {
+		value, ok := claimValue(claims, claim.Path)
+		if !ok {
+			continue
+		}
+
+		rendered := RenderedClaim{
+			Path:  claim.Path,
+			Value: value,
+			SVGID: claim.SVGID,
+		}
+
+		claimLangs := make([]string, len(claim.Display))
+		for i, d := range claim.Display {
+			claimLangs[i] = d.Lang
+		}
+		if i := localeIndex(claimLangs, locale); i >= 0 {
+			rendered.Label = claim.Display[i].Label
+			rendered.Description = claim.Display[i].Description
+		} else {
+			// without display metadata the last path element is the best label available
+			rendered.Label = pathLabel(claim.Path)
+		}
+
+		rendering.Claims = append(rendering.Claims, rendered)
+	}
+
+	return rendering
+}
+
+func pathLabel(path []any) string {
+	for i := len(path) - 1; i >= 0; i-- {
+		if s, ok := path[i].(string); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+// claimValue selects the value at path, a null path element selects all elements of an array
+func claimValue(v any, path []any) (any, bool) {
+	if len(path) == 0 {
+		return v, v != nil
+	}
+
+	switch element := path[0].(type) {
+	case string:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		child, ok := m[element]
+		if !ok {
+			return nil, false
+		}
+		return claimValue(child, path[1:])
+
+	case float64, int:
+		a, ok := v.([]any)
+		if !ok {
+			return nil, false
+		}
+		i := toInt(element)
+		if i < 0 || i >= len(a) {
+			return nil, false
+		}
+		return claimValue(a[i], path[1:])
+
+	case nil:
+		a, ok := v.([]any)
+		if !ok {
+			return nil, false
+		}
+		values := []any{}
+		for _, child := range a {
+			if value, ok := claimValue(child, path[1:]); ok {
+				values = append(values, value)
+			}
+		}
+		return values, len(values) > 0
+	}
+
+	return nil, false
+}
+
+func toInt(v any) int {
+	switch n := v.(type) {
+	case float64:
+		return int(n)
+	case int:
+		return n
+	}
+	return -1
+}
@@ -0,0 +1,75 @@
+package sdjwt
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var mockVCTM = []byte(`{
+	"vct": "https://credential.sunet.se/pid",
+	"display": [
+		{"lang": "en-US", "name": "Person ID", "rendering": {"simple": {"logo": {"uri": "https://credential.sunet.se/logo.svg"}, "background_color": "#12107c"}}},
+		{"lang": "sv-SE", "name": "Personlig identitet"}
+	],
+	"claims": [
+		{"path": ["given_name"], "display": [{"lang": "en-US", "label": "Given name"}, {"lang": "sv-SE", "label": "Förnamn"}]},
+		{"path": ["family_name"], "display": [{"lang": "en-US", "label": "Family name"}, {"lang": "sv-SE", "label": "Efternamn"}]},
+		{"path": ["nationalities", null], "display": [{"lang": "en-US", "label": "Nationality"}]},
+		{"path": ["address", "street_address"]},
+		{"path": ["birth_date"], "display": [{"lang": "en-US", "label": "Date of birth"}]}
+	]
+}`)
+
+func TestVCTMRender(t *testing.T) {
+	vctm := &VCTM{}
+	assert.NoError(t, json.Unmarshal(mockVCTM, vctm))
+
+	claims := map[string]any{
+		"family_name":   "Svensson",
+		"given_name":    "Magnus",
+		"nationalities": []any{"SE", "FI"},
+		"address":       map[string]any{"street_address": "Storgatan 1"},
+	}
+
+	tts := []struct {
+		name       string
+		locale     string
+		wantName   string
+		wantLabels []string
+	}{
+		{
+			name:       "exact locale",
+			locale:     "sv-SE",
+			wantName:   "Personlig identitet",
+			wantLabels: []string{"Förnamn", "Efternamn", "Nationality", "street_address"},
+		},
+		{
+			name:       "language match",
+			locale:     "en-GB",
+			wantName:   "Person ID",
+			wantLabels: []string{"Given name", "Family name", "Nationality", "street_address"},
+		},
+		{
+			name:       "fallback to first",
+			locale:     "de-DE",
+			wantName:   "Person ID",
+			wantLabels: []string{"Given name", "Family name", "Nationality", "street_address"},
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			rendering := vctm.Render(claims, tt.locale)
+			assert.Equal(t, tt.wantName, rendering.Name)
+
+			labels := []string{}
+			for _, claim := range rendering.Claims {
+				labels = append(labels, claim.Label)
+			}
+			assert.Equal(t, tt.wantLabels, labels)
+			assert.Equal(t, []any{"SE", "FI"}, rendering.Claims[2].Value)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package sdjwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// schemaResource is the location the VCTM schema is compiled under, refs to other locations are not resolved
+const schemaResource = "vctm-schema.json"
+
+// SchemaViolation is one claim that does not conform to the VCTM schema
+type SchemaViolation struct {
+	// Path is a JSON pointer to the claim, e.g. /address/country
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// SchemaError is returned when claims do not conform to the VCTM schema
+type SchemaError struct {
+	Violations []SchemaViolation
+}
+
+func (e *SchemaError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = fmt.Sprintf("%s: %s", v.Path, v.Message)
+	}
+	return ErrSchemaValidation.Error() + ": " + strings.Join(messages, "; ")
+}
+
+func (e *SchemaError) Unwrap() error {
+	return ErrSchemaValidation
+}
+
+// compileSchema compiles the embedded or fetched schema, nil if the type metadata has none
+func (v *VCTM) compileSchema() (*jsonschema.Schema, error) {
+	if len(v.Schema) == 0 {
+		return nil, nil
+	}
+
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(v.Schema))
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(schemaResource, doc); err != nil {
+		return nil, err
+	}
+
+	return compiler.Compile(schemaResource)
+}
+
+// ValidateClaims validates the processed, i.e. fully disclosed, claims of a credential against the schema of the type metadata.
+// Type metadata without a schema accepts any claims. A *SchemaError lists every violating claim.
+func (v *VCTM) ValidateClaims(claims map[string]any) error {
+	v.schemaOnce.Do(func() {
+		v.schema, v.schemaErr = v.compileSchema()
+	})
+	if v.schemaErr != nil {
+		return v.schemaErr
+	}
+	if v.schema == nil {
+		return nil
+	}
+
+	// the validator expects JSON types, e.g. float64 and not int
+	b, err := json.Marshal(claims)
+	if err != nil {
+		return err
+	}
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+
+	err = v.schema.Validate(instance)
+	if err == nil {
+		return nil
+	}
+	validationErr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return err
+	}
+
+	schemaErr := &SchemaError{}
+	for _, unit := range validationErr.BasicOutput().Errors {
+		if unit.Error == nil {
+			continue
+		}
+		path := unit.InstanceLocation
+		if path == "" {
+			path = "/"
+		}
+		schemaErr.Violations = append(schemaErr.Violations, SchemaViolation{Path: path, Message: unit.Error.String()})
+	}
+
+	return schemaErr
+}
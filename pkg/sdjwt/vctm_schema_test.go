@@ -0,0 +1,101 @@
+package sdjwt
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var mockSchema = []byte(`{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"required": ["vct", "given_name", "address"],
+	"properties": {
+		"vct": {"type": "string"},
+		"given_name": {"type": "string"},
+		"address": {
+			"type": "object",
+			"properties": {"country": {"type": "string", "minLength": 2, "maxLength": 2}}
+		}
+	}
+}`)
+
+func TestVCTMValidateClaims(t *testing.T) {
+	tts := []struct {
+		name      string
+		claims    map[string]any
+		wantPaths []string
+	}{
+		{
+			name: "valid",
+			claims: map[string]any{
+				"vct":        "https://credential.sunet.se/pid",
+				"given_name": "Magnus",
+				"address":    map[string]any{"country": "SE"},
+			},
+		},
+		{
+			name: "wrong type and nested violation",
+			claims: map[string]any{
+				"vct":        "https://credential.sunet.se/pid",
+				"given_name": 1,
+				"address":    map[string]any{"country": "Sweden"},
+			},
+			wantPaths: []string{"/address/country", "/given_name"},
+		},
+		{
+			name: "missing claim",
+			claims: map[string]any{
+				"vct":        "https://credential.sunet.se/pid",
+				"given_name": "Magnus",
+			},
+			wantPaths: []string{"/"},
+		},
+	}
+
+	vctm := &VCTM{Schema: mockSchema}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			err := vctm.ValidateClaims(tt.claims)
+			if tt.wantPaths == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.True(t, errors.Is(err, ErrSchemaValidation), "got %v", err)
+
+			var schemaErr *SchemaError
+			assert.True(t, errors.As(err, &schemaErr))
+
+			paths := []string{}
+			for _, v := range schemaErr.Violations {
+				paths = append(paths, v.Path)
+			}
+			assert.ElementsMatch(t, tt.wantPaths, paths)
+		})
+	}
+}
+
+func TestDisclosedClaims(t *testing.T) {
+	givenName := base64.RawURLEncoding.EncodeToString([]byte(`["salt1","given_name","Magnus"]`))
+	country := base64.RawURLEncoding.EncodeToString([]byte(`["salt2","SE"]`))
+	familyName := base64.RawURLEncoding.EncodeToString([]byte(`["salt3","family_name","Svensson"]`))
+
+	claims := map[string]any{
+		"vct":           "https://credential.sunet.se/pid",
+		"_sd_alg":       "sha-256",
+		"_sd":           []any{hash(givenName), hash(familyName)},
+		"nationalities": []any{map[string]any{"...": hash(country)}, "FI"},
+	}
+
+	got, err := DisclosedClaims(claims, []string{givenName, country})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"vct":           "https://credential.sunet.se/pid",
+		"given_name":    "Magnus",
+		"nationalities": []any{"SE", "FI"},
+	}, got)
+}
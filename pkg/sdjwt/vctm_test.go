@@ -0,0 +1,74 @@
+package sdjwt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVCTMResolver(t *testing.T) {
+	var server *httptest.Server
+	fetches := 0
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		w.Write([]byte(`{"vct":"` + server.URL + `/pid","name":"PID"}`))
+	}))
+	defer server.Close()
+
+	vct := server.URL + "/pid"
+	raw := []byte(`{"vct":"` + vct + `","name":"PID"}`)
+
+	tts := []struct {
+		name      string
+		vct       string
+		integrity string
+		wantErr   error
+	}{
+		{
+			name: "without integrity",
+			vct:  vct,
+		},
+		{
+			name:      "matching integrity",
+			vct:       vct,
+			integrity: Integrity(raw),
+		},
+		{
+			name:      "one matching alternative",
+			vct:       vct,
+			integrity: "sha256-AAAA " + Integrity(raw),
+		},
+		{
+			name:      "integrity mismatch",
+			vct:       vct,
+			integrity: Integrity([]byte("other")),
+			wantErr:   ErrIntegrityMismatch,
+		},
+		{
+			name:    "not in allow list",
+			vct:     "https://other.example.com/pid",
+			wantErr: ErrVCTNotAllowed,
+		},
+	}
+
+	r := NewVCTMResolver(server.Client(), time.Hour, []string{server.URL})
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			vctm, err := r.Resolve(context.Background(), tt.vct, tt.integrity)
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), "got %v", err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "PID", vctm.Name)
+		})
+	}
+
+	assert.Equal(t, 1, fetches, "metadata is cached")
+}
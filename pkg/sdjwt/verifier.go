@@ -42,14 +42,21 @@ func parseToken(token string) (string, []byte, error) {
 	return signatureString, sig, nil
 }
 
-// VerifySignature verifies the signature of a token
+// VerifySignature verifies the signature of a token with pubKey, the alg header of the token must be signingAlg if set
 func VerifySignature(token, signingAlg string, pubKey any) error {
+	jwtToken, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		return err
+	}
+	if signingAlg != "" && jwtToken.Method.Alg() != signingAlg {
+		return ErrAlgorithmMismatch
+	}
+
 	signingString, sig, err := parseToken(token)
 	if err != nil {
 		return err
 	}
 
-	jwtToken, _ := jwt.Parse(token, nil)
 	return jwtToken.Method.Verify(signingString, sig, pubKey)
 }
 
@@ -1,5 +1,67 @@
 package sdjwt
 
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"strings"
+	"testing"
+	"vc/pkg/signing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifySignatureAlgorithms(t *testing.T) {
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	assert.NoError(t, err)
+	p521, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	assert.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	tts := []struct {
+		name string
+		key  crypto.Signer
+		alg  string
+	}{
+		{name: "ES256", key: p256, alg: "ES256"},
+		{name: "ES384", key: p384, alg: "ES384"},
+		{name: "ES512", key: p521, alg: "ES512"},
+		{name: "Ed25519", key: edKey, alg: "EdDSA"},
+	}
+
+	instruction := InstructionsV2{
+		&ChildInstructionV2{Name: "given_name", Value: "Test", SelectiveDisclosure: true},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			signer, err := signing.NewSoftware(tt.key)
+			assert.NoError(t, err)
+
+			signed, err := instruction.SDJWT(signer.SigningMethod(), signer, &Config{KID: "kid-1", VCT: "test"})
+			assert.NoError(t, err)
+
+			assert.NoError(t, VerifySignature(signed.JWT, tt.alg, signer.Public()))
+			assert.NoError(t, VerifySignature(signed.JWT, "", signer.Public()))
+
+			other := "ES256"
+			if tt.alg == "ES256" {
+				other = "EdDSA"
+			}
+			assert.ErrorIs(t, VerifySignature(signed.JWT, other, signer.Public()), ErrAlgorithmMismatch)
+
+			parts := strings.Split(signed.JWT, ".")
+			tampered := parts[0] + "." + parts[1] + "e." + parts[2]
+			assert.Error(t, VerifySignature(tampered, tt.alg, signer.Public()))
+		})
+	}
+}
+
 //func TestParseAndValidate(t *testing.T) {
 //	type want struct {
 //		jwt        jwt.MapClaims
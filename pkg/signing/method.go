@@ -0,0 +1,89 @@
+package signing
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/asn1"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// signingMethod is a jwt.SigningMethod signing with any crypto.Signer, the private key never leaves the key manager
+type signingMethod struct {
+	alg     string
+	hash    crypto.Hash
+	keySize int
+}
+
+func newSigningMethod(publicKey crypto.PublicKey) (*signingMethod, error) {
+	switch pub := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		keySize := (pub.Curve.Params().BitSize + 7) / 8
+		switch pub.Curve {
+		case elliptic.P256():
+			return &signingMethod{alg: "ES256", hash: crypto.SHA256, keySize: keySize}, nil
+		case elliptic.P384():
+			return &signingMethod{alg: "ES384", hash: crypto.SHA384, keySize: keySize}, nil
+		case elliptic.P521():
+			return &signingMethod{alg: "ES512", hash: crypto.SHA512, keySize: keySize}, nil
+		}
+	case *rsa.PublicKey:
+		return &signingMethod{alg: "RS256", hash: crypto.SHA256}, nil
+	case ed25519.PublicKey:
+		return &signingMethod{alg: "EdDSA"}, nil
+	}
+
+	return nil, ErrUnsupportedKey
+}
+
+// Alg implements jwt.SigningMethod
+func (m *signingMethod) Alg() string {
+	return m.alg
+}
+
+// Sign implements jwt.SigningMethod, key must be a crypto.Signer
+func (m *signingMethod) Sign(signingString string, key any) ([]byte, error) {
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, jwt.ErrInvalidKeyType
+	}
+
+	digest := []byte(signingString)
+	if m.hash != 0 {
+		h := m.hash.New()
+		h.Write(digest)
+		digest = h.Sum(nil)
+	}
+
+	sig, err := signer.Sign(rand.Reader, digest, m.hash)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.keySize == 0 {
+		return sig, nil
+	}
+
+	// crypto.Signer returns ASN.1 encoded ECDSA signatures, JWS uses the fixed size r || s encoding
+	var ecSig struct {
+		R, S *big.Int
+	}
+	if _, err := asn1.Unmarshal(sig, &ecSig); err != nil {
+		return nil, err
+	}
+	out := make([]byte, 2*m.keySize)
+	ecSig.R.FillBytes(out[:m.keySize])
+	ecSig.S.FillBytes(out[m.keySize:])
+
+	return out, nil
+}
+
+// Verify implements jwt.SigningMethod, key is the public key
+func (m *signingMethod) Verify(signingString string, sig []byte, key any) error {
+	return jwt.GetSigningMethod(m.alg).Verify(signingString, sig, key)
+}
@@ -0,0 +1,52 @@
+package signing
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigningMethod(t *testing.T) {
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	assert.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	tts := []struct {
+		name    string
+		key     crypto.Signer
+		wantAlg string
+	}{
+		{name: "P-256", key: p256, wantAlg: "ES256"},
+		{name: "P-384", key: p384, wantAlg: "ES384"},
+		{name: "RSA", key: rsaKey, wantAlg: "RS256"},
+		{name: "Ed25519", key: edKey, wantAlg: "EdDSA"},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			signer, err := NewSoftware(tt.key)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantAlg, signer.Algorithm())
+
+			signed, err := jwt.NewWithClaims(signer.SigningMethod(), jwt.MapClaims{"iss": "test"}).SignedString(signer)
+			assert.NoError(t, err)
+
+			_, err = jwt.Parse(signed, func(token *jwt.Token) (any, error) {
+				return signer.Public(), nil
+			}, jwt.WithValidMethods([]string{tt.wantAlg}))
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package signing
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"vc/pkg/model"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.step.sm/crypto/kms"
+	"go.step.sm/crypto/kms/apiv1"
+	"go.step.sm/crypto/kms/uri"
+
+	// register the key manager backends
+	_ "go.step.sm/crypto/kms/awskms"
+	_ "go.step.sm/crypto/kms/cloudkms"
+	_ "go.step.sm/crypto/kms/pkcs11"
+)
+
+var (
+	// ErrUnsupportedKey is returned when the signing key algorithm is not supported
+	ErrUnsupportedKey = errors.New("unsupported signing key")
+)
+
+// Signer signs with a key held in software, in a PKCS#11 token, in AWS KMS or in GCP Cloud KMS
+type Signer struct {
+	crypto.Signer
+	keyManager apiv1.KeyManager
+	method     jwt.SigningMethod
+}
+
+// New creates a signer from cfg, softwareKeyPath is the PEM encoded ECDSA or Ed25519 key used by the software type
+func New(ctx context.Context, cfg model.Signing, softwareKeyPath string) (*Signer, error) {
+	if cfg.Type == "" || cfg.Type == "software" {
+		keyByte, err := os.ReadFile(softwareKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		privateKey, err := parsePrivateKeyPEM(keyByte)
+		if err != nil {
+			return nil, err
+		}
+		return NewSoftware(privateKey)
+	}
+
+	opts, signingKey, err := keyManagerOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	keyManager, err := kms.New(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := keyManager.CreateSigner(&apiv1.CreateSignerRequest{SigningKey: signingKey})
+	if err != nil {
+		keyManager.Close()
+		return nil, err
+	}
+
+	method, err := newSigningMethod(signer.Public())
+	if err != nil {
+		keyManager.Close()
+		return nil, err
+	}
+
+	return &Signer{Signer: signer, keyManager: keyManager, method: method}, nil
+}
+
+// NewSoftware creates a signer from a key held in memory
+func NewSoftware(key crypto.Signer) (*Signer, error) {
+	method, err := newSigningMethod(key.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Signer{Signer: key, method: method}, nil
+}
+
+// parsePrivateKeyPEM parses a PEM encoded ECDSA key, in SEC 1 or PKCS #8 form, or a PKCS #8 Ed25519 key
+func parsePrivateKeyPEM(keyByte []byte) (crypto.Signer, error) {
+	if ecKey, err := jwt.ParseECPrivateKeyFromPEM(keyByte); err == nil {
+		return ecKey, nil
+	}
+
+	edKey, err := jwt.ParseEdPrivateKeyFromPEM(keyByte)
+	if err != nil {
+		return nil, ErrUnsupportedKey
+	}
+	signer, ok := edKey.(crypto.Signer)
+	if !ok {
+		return nil, ErrUnsupportedKey
+	}
+
+	return signer, nil
+}
+
+// SigningMethod returns the JWT signing method matching the key, it accepts the Signer as signing key
+func (s *Signer) SigningMethod() jwt.SigningMethod {
+	return s.method
+}
+
+// Algorithm returns the JOSE algorithm name of the key, example: ES256
+func (s *Signer) Algorithm() string {
+	return s.method.Alg()
+}
+
+// Close releases the key manager session
+func (s *Signer) Close() error {
+	if s.keyManager == nil {
+		return nil
+	}
+	return s.keyManager.Close()
+}
+
+// keyManagerOptions returns the key manager options and the signing key name for cfg
+func keyManagerOptions(cfg model.Signing) (apiv1.Options, string, error) {
+	switch cfg.Type {
+	case "pkcs11":
+		if cfg.KeyLabel == "" {
+			return apiv1.Options{}, "", fmt.Errorf("pkcs11 signing requires key_label")
+		}
+		values := url.Values{}
+		if cfg.ModulePath != "" {
+			values.Set("module-path", cfg.ModulePath)
+		}
+		if cfg.TokenLabel != "" {
+			values.Set("token", cfg.TokenLabel)
+		}
+		if cfg.Slot != "" {
+			values.Set("slot-id", cfg.Slot)
+		}
+		return apiv1.Options{
+			Type: apiv1.PKCS11,
+			URI:  uri.New("pkcs11", values).String(),
+			Pin:  cfg.Pin,
+		}, uri.New("pkcs11", url.Values{"object": []string{cfg.KeyLabel}}).String(), nil
+
+	case "awskms":
+		if cfg.KeyID == "" {
+			return apiv1.Options{}, "", fmt.Errorf("awskms signing requires key_id")
+		}
+		return apiv1.Options{
+			Type:            apiv1.AmazonKMS,
+			Region:          cfg.Region,
+			CredentialsFile: cfg.CredentialsFile,
+		}, cfg.KeyID, nil
+
+	case "cloudkms":
+		if cfg.KeyID == "" {
+			return apiv1.Options{}, "", fmt.Errorf("cloudkms signing requires key_id")
+		}
+		return apiv1.Options{
+			Type:            apiv1.CloudKMS,
+			CredentialsFile: cfg.CredentialsFile,
+		}, cfg.KeyID, nil
+
+	default:
+		return apiv1.Options{}, "", fmt.Errorf("unknown signing type %q", cfg.Type)
+	}
+}
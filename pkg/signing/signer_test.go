@@ -0,0 +1,57 @@
+package signing
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"vc/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSoftwarePEM(t *testing.T) {
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	assert.NoError(t, err)
+	sec1, err := x509.MarshalECPrivateKey(p384)
+	assert.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(edKey)
+	assert.NoError(t, err)
+
+	tts := []struct {
+		name    string
+		block   *pem.Block
+		public  crypto.PublicKey
+		wantAlg string
+		wantErr error
+	}{
+		{name: "ECDSA", block: &pem.Block{Type: "EC PRIVATE KEY", Bytes: sec1}, public: p384.Public(), wantAlg: "ES384"},
+		{name: "Ed25519", block: &pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}, public: edKey.Public(), wantAlg: "EdDSA"},
+		{name: "garbage", block: &pem.Block{Type: "PRIVATE KEY", Bytes: []byte("garbage")}, wantErr: ErrUnsupportedKey},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "key.pem")
+			assert.NoError(t, os.WriteFile(path, pem.EncodeToMemory(tt.block), 0600))
+
+			signer, err := New(context.Background(), model.Signing{}, path)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantAlg, signer.Algorithm())
+			assert.True(t, signer.Public().(interface{ Equal(crypto.PublicKey) bool }).Equal(tt.public))
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package statuslist
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// PurposeRevocation is used to cancel the validity of a credential, not reversible
+	PurposeRevocation = "revocation"
+
+	// PurposeSuspension is used to temporarily prevent the acceptance of a credential
+	PurposeSuspension = "suspension"
+
+	// MinBitstringSize is the minimum number of entries in a bitstring status list (16KB), to provide group privacy
+	MinBitstringSize = 131072
+
+	// multibaseBase64URL is the multibase prefix for base64url without padding
+	multibaseBase64URL = "u"
+)
+
+var (
+	// ErrIndexOutOfRange is returned when an index is outside the bitstring
+	ErrIndexOutOfRange = errors.New("status list index out of range")
+
+	// ErrUnknownStatusPurpose is returned when the status purpose is not supported
+	ErrUnknownStatusPurpose = errors.New("unknown status purpose")
+
+	// ErrMultibaseEncoding is returned when the encoded list lacks the base64url multibase prefix
+	ErrMultibaseEncoding = errors.New("encoded list is not multibase base64url")
+)
+
+// ValidPurpose returns true if purpose is a supported statusPurpose
+func ValidPurpose(purpose string) bool {
+	switch purpose {
+	case PurposeRevocation, PurposeSuspension:
+		return true
+	default:
+		return false
+	}
+}
+
+// Bitstring is a W3C Bitstring Status List, index 0 is the left-most bit of the first byte
+type Bitstring struct {
+	bits []byte
+	size int
+}
+
+// NewBitstring creates a bitstring with at least size entries, rounded up to MinBitstringSize
+func NewBitstring(size int) *Bitstring {
+	if size < MinBitstringSize {
+		size = MinBitstringSize
+	}
+	return &Bitstring{
+		bits: make([]byte, (size+7)/8),
+		size: size,
+	}
+}
+
+// Len returns the number of entries in the bitstring
+func (b *Bitstring) Len() int {
+	return b.size
+}
+
+// Set sets the status bit for index
+func (b *Bitstring) Set(index int, value bool) error {
+	if index < 0 || index >= b.size {
+		return ErrIndexOutOfRange
+	}
+	mask := byte(1 << (7 - uint(index%8)))
+	if value {
+		b.bits[index/8] |= mask
+	} else {
+		b.bits[index/8] &^= mask
+	}
+	return nil
+}
+
+// Get returns the status bit for index
+func (b *Bitstring) Get(index int) (bool, error) {
+	if index < 0 || index >= b.size {
+		return false, ErrIndexOutOfRange
+	}
+	mask := byte(1 << (7 - uint(index%8)))
+	return b.bits[index/8]&mask != 0, nil
+}
+
+// Encode returns the GZIP compressed, multibase base64url encoded bitstring
+func (b *Bitstring) Encode() (string, error) {
+	buf := &bytes.Buffer{}
+	w := gzip.NewWriter(buf)
+	if _, err := w.Write(b.bits); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	return multibaseBase64URL + base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// DecodeBitstring decodes an encodedList value into a bitstring
+func DecodeBitstring(encodedList string) (*Bitstring, error) {
+	if !strings.HasPrefix(encodedList, multibaseBase64URL) {
+		return nil, ErrMultibaseEncoding
+	}
+
+	compressed, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(encodedList, multibaseBase64URL))
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	bits, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Bitstring{bits: bits, size: len(bits) * 8}, nil
+}
+
+// BitstringStatusList is the credentialSubject of a BitstringStatusListCredential
+type BitstringStatusList struct {
+	ID            string `json:"id"`
+	Type          string `json:"type"`
+	StatusPurpose string `json:"statusPurpose"`
+	EncodedList   string `json:"encodedList"`
+	TTL           int64  `json:"ttl,omitempty"`
+}
+
+// BitstringStatusListCredential is a W3C VC-DM 2.0 credential carrying a status list
+type BitstringStatusListCredential struct {
+	Context           []string             `json:"@context"`
+	ID                string               `json:"id"`
+	Type              []string             `json:"type"`
+	Issuer            string               `json:"issuer"`
+	ValidFrom         string               `json:"validFrom"`
+	ValidUntil        string               `json:"validUntil,omitempty"`
+	CredentialSubject *BitstringStatusList `json:"credentialSubject"`
+}
+
+// NewBitstringStatusListCredential creates a status list credential, ttl is in milliseconds according to the specification
+func NewBitstringStatusListCredential(id, issuer, purpose string, list *Bitstring, ttl int64) (*BitstringStatusListCredential, error) {
+	if !ValidPurpose(purpose) {
+		return nil, ErrUnknownStatusPurpose
+	}
+
+	encodedList, err := list.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	credential := &BitstringStatusListCredential{
+		Context:   []string{"https://www.w3.org/ns/credentials/v2"},
+		ID:        id,
+		Type:      []string{"VerifiableCredential", "BitstringStatusListCredential"},
+		Issuer:    issuer,
+		ValidFrom: time.Now().UTC().Format(time.RFC3339),
+		CredentialSubject: &BitstringStatusList{
+			ID:            fmt.Sprintf("%s#list", id),
+			Type:          "BitstringStatusList",
+			StatusPurpose: purpose,
+			EncodedList:   encodedList,
+			TTL:           ttl,
+		},
+	}
+
+	return credential, nil
+}
+
+// BitstringStatusListEntry is the credentialStatus object embedded in an issued credential
+type BitstringStatusListEntry struct {
+	ID                   string `json:"id"`
+	Type                 string `json:"type"`
+	StatusPurpose        string `json:"statusPurpose"`
+	StatusListIndex      string `json:"statusListIndex"`
+	StatusListCredential string `json:"statusListCredential"`
+}
+
+// NewBitstringStatusListEntry creates a credentialStatus entry pointing to index in statusListCredential
+func NewBitstringStatusListEntry(statusListCredential, purpose string, index int64) *BitstringStatusListEntry {
+	i := strconv.FormatInt(index, 10)
+	return &BitstringStatusListEntry{
+		ID:                   fmt.Sprintf("%s#%s", statusListCredential, i),
+		Type:                 "BitstringStatusListEntry",
+		StatusPurpose:        purpose,
+		StatusListIndex:      i,
+		StatusListCredential: statusListCredential,
+	}
+}
@@ -0,0 +1,105 @@
+package statuslist
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitstringSetGet(t *testing.T) {
+	tts := []struct {
+		name    string
+		index   int
+		wantErr error
+	}{
+		{
+			name:  "first",
+			index: 0,
+		},
+		{
+			name:  "middle",
+			index: 94567,
+		},
+		{
+			name:  "last",
+			index: MinBitstringSize - 1,
+		},
+		{
+			name:    "out of range",
+			index:   MinBitstringSize,
+			wantErr: ErrIndexOutOfRange,
+		},
+		{
+			name:    "negative",
+			index:   -1,
+			wantErr: ErrIndexOutOfRange,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBitstring(0)
+
+			err := b.Set(tt.index, true)
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr != nil {
+				return
+			}
+
+			got, err := b.Get(tt.index)
+			assert.NoError(t, err)
+			assert.True(t, got)
+
+			assert.NoError(t, b.Set(tt.index, false))
+			got, err = b.Get(tt.index)
+			assert.NoError(t, err)
+			assert.False(t, got)
+		})
+	}
+}
+
+func TestBitstringBitOrder(t *testing.T) {
+	b := NewBitstring(0)
+	assert.NoError(t, b.Set(0, true))
+	assert.NoError(t, b.Set(9, true))
+
+	assert.Equal(t, byte(0x80), b.bits[0])
+	assert.Equal(t, byte(0x40), b.bits[1])
+}
+
+func TestBitstringEncodeDecode(t *testing.T) {
+	b := NewBitstring(0)
+	for _, i := range []int{1, 42, 94567} {
+		assert.NoError(t, b.Set(i, true))
+	}
+
+	encoded, err := b.Encode()
+	assert.NoError(t, err)
+
+	decoded, err := DecodeBitstring(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, b.Len(), decoded.Len())
+
+	for _, i := range []int{0, 1, 2, 42, 94567} {
+		want, _ := b.Get(i)
+		got, err := decoded.Get(i)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got, "index %d", i)
+	}
+}
+
+func TestDecodeBitstringSpecExample(t *testing.T) {
+	// Example encodedList from the W3C Bitstring Status List specification, 16KB of zeros
+	decoded, err := DecodeBitstring("uH4sIAAAAAAAAA-3BMQEAAADCoPVPbQwfoAAAAAAAAAAAAAAAAAAAAIC3AYbSVKsAQAAA")
+	assert.NoError(t, err)
+	assert.Equal(t, MinBitstringSize, decoded.Len())
+
+	got, err := decoded.Get(94567)
+	assert.NoError(t, err)
+	assert.False(t, got)
+}
+
+func TestDecodeBitstringNoMultibase(t *testing.T) {
+	_, err := DecodeBitstring("H4sIAAAAAAAAA")
+	assert.Equal(t, ErrMultibaseEncoding, err)
+}
@@ -0,0 +1,86 @@
+package statuslist
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// TokenPath is the path, relative to the status list base URL, of the token status list published by the registry
+	TokenPath = "token"
+
+	// maxTokenSize is the maximum size of a fetched status list token
+	maxTokenSize = 1 << 20
+
+	// typeStatusListJWT is the typ of a status list token in JWT format
+	typeStatusListJWT = "statuslist+jwt"
+)
+
+// Fetcher fetches status list tokens in JWT format and looks up the status of referenced tokens
+type Fetcher struct {
+	client *http.Client
+	key    func(ctx context.Context) jwt.Keyfunc
+}
+
+// NewFetcher creates a fetcher, key returns the key function the status list tokens are verified with
+func NewFetcher(client *http.Client, key func(ctx context.Context) jwt.Keyfunc) *Fetcher {
+	return &Fetcher{
+		client: client,
+		key:    key,
+	}
+}
+
+// FetchStatus fetches the status list token at ref.URI and returns the status at ref.Idx
+func (f *Fetcher) FetchStatus(ctx context.Context, ref *StatusReference) (uint8, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ref.URI, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", MediaTypeStatusListJWT)
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("fetch %s: %s", ref.URI, resp.Status)
+	}
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxTokenSize))
+	if err != nil {
+		return 0, err
+	}
+
+	keyFunc := f.key(ctx)
+	claims := &struct {
+		jwt.RegisteredClaims
+		StatusList StatusListJWT `json:"status_list"`
+	}{}
+	_, err = jwt.ParseWithClaims(string(b), claims, func(token *jwt.Token) (any, error) {
+		if typ, _ := token.Header["typ"].(string); typ != typeStatusListJWT {
+			return nil, fmt.Errorf("typ %q", typ)
+		}
+		return keyFunc(token)
+	}, jwt.WithSubject(ref.URI))
+	if err != nil {
+		return 0, err
+	}
+
+	lst, err := base64.RawURLEncoding.DecodeString(claims.StatusList.Lst)
+	if err != nil {
+		return 0, err
+	}
+	list, err := DecompressTokenStatusList(lst, claims.StatusList.Bits)
+	if err != nil {
+		return 0, err
+	}
+
+	return list.Get(int(ref.Idx))
+}
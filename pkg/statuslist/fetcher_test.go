@@ -0,0 +1,64 @@
+package statuslist
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetcher(t *testing.T) {
+	signingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	list, err := NewTokenStatusList(16, 2)
+	assert.NoError(t, err)
+	assert.NoError(t, list.Set(3, StatusInvalid))
+	assert.NoError(t, list.Set(5, StatusSuspended))
+	statusList, err := list.JWTClaim("")
+	assert.NoError(t, err)
+
+	typ := "statuslist+jwt"
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, MediaTypeStatusListJWT, r.Header.Get("Accept"))
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+			"sub":         server.URL + "/token",
+			"status_list": statusList,
+		})
+		token.Header["typ"] = typ
+		signed, err := token.SignedString(signingKey)
+		assert.NoError(t, err)
+		w.Write([]byte(signed))
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher(server.Client(), func(ctx context.Context) jwt.Keyfunc {
+		return func(token *jwt.Token) (any, error) {
+			return &signingKey.PublicKey, nil
+		}
+	})
+
+	ctx := context.Background()
+	for idx, want := range map[int64]uint8{0: StatusValid, 3: StatusInvalid, 5: StatusSuspended} {
+		got, err := fetcher.FetchStatus(ctx, &StatusReference{Idx: idx, URI: server.URL + "/token"})
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	_, err = fetcher.FetchStatus(ctx, &StatusReference{Idx: 16, URI: server.URL + "/token"})
+	assert.ErrorIs(t, err, ErrIndexOutOfRange)
+
+	_, err = fetcher.FetchStatus(ctx, &StatusReference{Idx: 0, URI: server.URL + "/other"})
+	assert.Error(t, err, "sub does not match the uri")
+
+	typ = "jwt"
+	_, err = fetcher.FetchStatus(ctx, &StatusReference{Idx: 0, URI: server.URL + "/token"})
+	assert.Error(t, err, "wrong typ")
+}
@@ -0,0 +1,188 @@
+package statuslist
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"errors"
+	"io"
+)
+
+const (
+	// StatusValid means the referenced token is valid
+	StatusValid uint8 = 0x00
+
+	// StatusInvalid means the referenced token is revoked, not reversible
+	StatusInvalid uint8 = 0x01
+
+	// StatusSuspended means the referenced token is temporarily invalid
+	StatusSuspended uint8 = 0x02
+
+	// MediaTypeStatusListJWT is the media type of a status list token in JWT format
+	MediaTypeStatusListJWT = "application/statuslist+jwt"
+
+	// MediaTypeStatusListCWT is the media type of a status list token in CWT format
+	MediaTypeStatusListCWT = "application/statuslist+cwt"
+)
+
+var (
+	// ErrInvalidBits is returned when bits is not one of 1, 2, 4 or 8
+	ErrInvalidBits = errors.New("status list bits must be 1, 2, 4 or 8")
+
+	// ErrStatusOutOfRange is returned when a status does not fit in the configured bits
+	ErrStatusOutOfRange = errors.New("status value does not fit in status list bits")
+)
+
+// TokenStatusList is an IETF OAuth Token Status List, index 0 is the least significant bits of the first byte
+type TokenStatusList struct {
+	bits   int
+	size   int
+	values []byte
+}
+
+// NewTokenStatusList creates a token status list with size entries of bits each
+func NewTokenStatusList(size, bits int) (*TokenStatusList, error) {
+	switch bits {
+	case 1, 2, 4, 8:
+	default:
+		return nil, ErrInvalidBits
+	}
+
+	return &TokenStatusList{
+		bits:   bits,
+		size:   size,
+		values: make([]byte, (size*bits+7)/8),
+	}, nil
+}
+
+// Bits returns the number of bits per entry
+func (l *TokenStatusList) Bits() int {
+	return l.bits
+}
+
+// Len returns the number of entries in the list
+func (l *TokenStatusList) Len() int {
+	return l.size
+}
+
+// Set sets the status of index
+func (l *TokenStatusList) Set(index int, status uint8) error {
+	if index < 0 || index >= l.size {
+		return ErrIndexOutOfRange
+	}
+	mask := byte(1<<l.bits - 1)
+	if status&^mask != 0 {
+		return ErrStatusOutOfRange
+	}
+
+	perByte := 8 / l.bits
+	shift := uint((index % perByte) * l.bits)
+	l.values[index/perByte] = l.values[index/perByte]&^(mask<<shift) | status<<shift
+
+	return nil
+}
+
+// Get returns the status of index
+func (l *TokenStatusList) Get(index int) (uint8, error) {
+	if index < 0 || index >= l.size {
+		return 0, ErrIndexOutOfRange
+	}
+	mask := byte(1<<l.bits - 1)
+
+	perByte := 8 / l.bits
+	shift := uint((index % perByte) * l.bits)
+
+	return l.values[index/perByte] >> shift & mask, nil
+}
+
+// Compress returns the list compressed with DEFLATE in the ZLIB data format
+func (l *TokenStatusList) Compress() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	w, err := zlib.NewWriterLevel(buf, zlib.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(l.values); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// DecompressTokenStatusList decompresses lst into a token status list with bits per entry
+func DecompressTokenStatusList(lst []byte, bits int) (*TokenStatusList, error) {
+	r, err := zlib.NewReader(bytes.NewReader(lst))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	values, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := NewTokenStatusList(len(values)*8/bits, bits)
+	if err != nil {
+		return nil, err
+	}
+	l.values = values
+
+	return l, nil
+}
+
+// StatusListJWT is the status_list claim of a status list token in JWT format
+type StatusListJWT struct {
+	Bits           int    `json:"bits"`
+	Lst            string `json:"lst"`
+	AggregationURI string `json:"aggregation_uri,omitempty"`
+}
+
+// StatusListCWT is the status_list claim of a status list token in CWT format
+type StatusListCWT struct {
+	Bits           int    `cbor:"bits"`
+	Lst            []byte `cbor:"lst"`
+	AggregationURI string `cbor:"aggregation_uri,omitempty"`
+}
+
+// JWTClaim returns the status_list claim for a JWT, lst is base64url encoded
+func (l *TokenStatusList) JWTClaim(aggregationURI string) (*StatusListJWT, error) {
+	lst, err := l.Compress()
+	if err != nil {
+		return nil, err
+	}
+
+	return &StatusListJWT{
+		Bits:           l.bits,
+		Lst:            base64.RawURLEncoding.EncodeToString(lst),
+		AggregationURI: aggregationURI,
+	}, nil
+}
+
+// CWTClaim returns the status_list claim for a CWT, lst is a byte string
+func (l *TokenStatusList) CWTClaim(aggregationURI string) (*StatusListCWT, error) {
+	lst, err := l.Compress()
+	if err != nil {
+		return nil, err
+	}
+
+	return &StatusListCWT{
+		Bits:           l.bits,
+		Lst:            lst,
+		AggregationURI: aggregationURI,
+	}, nil
+}
+
+// StatusReference is the status_list member of a referenced token's status claim
+type StatusReference struct {
+	Idx int64  `json:"idx" cbor:"idx"`
+	URI string `json:"uri" cbor:"uri"`
+}
+
+// Status is the status claim of a referenced token, such as an SD-JWT VC
+type Status struct {
+	StatusList *StatusReference `json:"status_list" cbor:"status_list"`
+}
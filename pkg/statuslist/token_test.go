@@ -0,0 +1,127 @@
+package statuslist
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenStatusListSpecExample(t *testing.T) {
+	// Example status list with one bit per entry from the IETF Token Status List specification
+	statuses := []uint8{1, 0, 0, 1, 1, 1, 0, 1, 1, 1, 0, 0, 0, 1, 0, 1}
+
+	l, err := NewTokenStatusList(len(statuses), 1)
+	assert.NoError(t, err)
+	for i, status := range statuses {
+		assert.NoError(t, l.Set(i, status))
+	}
+
+	assert.Equal(t, []byte{0xb9, 0xa3}, l.values)
+}
+
+func TestTokenStatusListSetGet(t *testing.T) {
+	tts := []struct {
+		name    string
+		bits    int
+		index   int
+		status  uint8
+		wantErr error
+	}{
+		{
+			name:   "1 bit",
+			bits:   1,
+			index:  7,
+			status: StatusInvalid,
+		},
+		{
+			name:   "2 bits",
+			bits:   2,
+			index:  5,
+			status: StatusSuspended,
+		},
+		{
+			name:   "4 bits",
+			bits:   4,
+			index:  3,
+			status: 0x0f,
+		},
+		{
+			name:   "8 bits",
+			bits:   8,
+			index:  9,
+			status: 0xff,
+		},
+		{
+			name:    "status too large",
+			bits:    1,
+			index:   0,
+			status:  StatusSuspended,
+			wantErr: ErrStatusOutOfRange,
+		},
+		{
+			name:    "out of range",
+			bits:    2,
+			index:   16,
+			status:  StatusInvalid,
+			wantErr: ErrIndexOutOfRange,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := NewTokenStatusList(16, tt.bits)
+			assert.NoError(t, err)
+
+			err = l.Set(tt.index, tt.status)
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr != nil {
+				return
+			}
+
+			got, err := l.Get(tt.index)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, got)
+
+			for i := 0; i < l.Len(); i++ {
+				if i == tt.index {
+					continue
+				}
+				got, err := l.Get(i)
+				assert.NoError(t, err)
+				assert.Equal(t, StatusValid, got, "index %d", i)
+			}
+		})
+	}
+}
+
+func TestTokenStatusListInvalidBits(t *testing.T) {
+	_, err := NewTokenStatusList(16, 3)
+	assert.Equal(t, ErrInvalidBits, err)
+}
+
+func TestTokenStatusListCompress(t *testing.T) {
+	l, err := NewTokenStatusList(MinBitstringSize, 2)
+	assert.NoError(t, err)
+	assert.NoError(t, l.Set(42, StatusSuspended))
+	assert.NoError(t, l.Set(1000, StatusInvalid))
+
+	claim, err := l.JWTClaim("")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, claim.Bits)
+
+	lst, err := base64.RawURLEncoding.DecodeString(claim.Lst)
+	assert.NoError(t, err)
+
+	decompressed, err := DecompressTokenStatusList(lst, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, l.Len(), decompressed.Len())
+
+	got, err := decompressed.Get(42)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusSuspended, got)
+
+	got, err = decompressed.Get(1000)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusInvalid, got)
+}
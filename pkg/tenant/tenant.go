@@ -0,0 +1,148 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"vc/pkg/model"
+
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// Default is the tenant of the authentic sources that are not in a configured tenant
+	Default = ""
+
+	// MetadataKey is the gRPC metadata key the tenant is passed to other services in
+	MetadataKey = "x-tenant"
+)
+
+// ErrUnknownTenant is returned for a tenant that is not configured, e.g. from the metadata of another service with a
+// different tenant configuration
+var ErrUnknownTenant = errors.New("unknown tenant")
+
+type contextKey struct{}
+
+// NewContext returns ctx with the tenant name
+func NewContext(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, contextKey{}, name)
+}
+
+// FromContext returns the tenant of ctx, Default if ctx has none
+func FromContext(ctx context.Context) string {
+	name, _ := ctx.Value(contextKey{}).(string)
+	return name
+}
+
+// OutgoingContext adds the tenant of ctx to the outgoing gRPC metadata
+func OutgoingContext(ctx context.Context) context.Context {
+	name := FromContext(ctx)
+	if name == Default {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, MetadataKey, name)
+}
+
+// FromIncomingContext returns ctx with the tenant of the incoming gRPC metadata
+func FromIncomingContext(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if names := md.Get(MetadataKey); len(names) > 0 {
+		return NewContext(ctx, names[0])
+	}
+	return ctx
+}
+
+// Database returns the name of the mongo database of a tenant, base for the default tenant
+func Database(base, name string) string {
+	if name == Default {
+		return base
+	}
+	return base + "_" + name
+}
+
+// Tenants looks up the tenant of authentic sources
+type Tenants struct {
+	names             []string
+	byName            map[string]*model.Tenant
+	byAuthenticSource map[string]string
+
+	apiKeys            map[string]string
+	clientCertificates map[string]string
+}
+
+// New validates the tenant configuration, an authentic source, API key or client certificate may belong to one tenant only
+func New(cfg []model.Tenant) (*Tenants, error) {
+	t := &Tenants{
+		names:              []string{Default},
+		byName:             map[string]*model.Tenant{},
+		byAuthenticSource:  map[string]string{},
+		apiKeys:            map[string]string{},
+		clientCertificates: map[string]string{},
+	}
+
+	for i := range cfg {
+		tenant := &cfg[i]
+		if tenant.Name == Default {
+			return nil, fmt.Errorf("tenant %d has no name", i)
+		}
+		if _, ok := t.byName[tenant.Name]; ok {
+			return nil, fmt.Errorf("tenant %s is configured twice", tenant.Name)
+		}
+		t.names = append(t.names, tenant.Name)
+		t.byName[tenant.Name] = tenant
+
+		for _, authenticSource := range tenant.AuthenticSources {
+			if other, ok := t.byAuthenticSource[authenticSource]; ok {
+				return nil, fmt.Errorf("authentic source %s is in tenant %s and %s", authenticSource, other, tenant.Name)
+			}
+			t.byAuthenticSource[authenticSource] = tenant.Name
+		}
+
+		for key, authenticSource := range tenant.APIKeys {
+			if _, ok := t.apiKeys[key]; ok {
+				return nil, fmt.Errorf("an api key of tenant %s is also used by another tenant", tenant.Name)
+			}
+			t.apiKeys[key] = authenticSource
+			if t.byAuthenticSource[authenticSource] != tenant.Name {
+				return nil, fmt.Errorf("an api key of tenant %s is for authentic source %s of another tenant", tenant.Name, authenticSource)
+			}
+		}
+
+		for fingerprint, authenticSource := range tenant.ClientCertificates {
+			if _, ok := t.clientCertificates[fingerprint]; ok {
+				return nil, fmt.Errorf("client certificate %s of tenant %s is also used by another tenant", fingerprint, tenant.Name)
+			}
+			t.clientCertificates[fingerprint] = authenticSource
+			if t.byAuthenticSource[authenticSource] != tenant.Name {
+				return nil, fmt.Errorf("client certificate %s of tenant %s is for authentic source %s of another tenant", fingerprint, tenant.Name, authenticSource)
+			}
+		}
+	}
+
+	return t, nil
+}
+
+// Names returns the default tenant followed by the configured tenants
+func (t *Tenants) Names() []string {
+	return t.names
+}
+
+// Get returns the configuration of a tenant, nil for the default tenant and unknown names
+func (t *Tenants) Get(name string) *model.Tenant {
+	return t.byName[name]
+}
+
+// ByAuthenticSource returns the tenant of authenticSource
+func (t *Tenants) ByAuthenticSource(authenticSource string) string {
+	return t.byAuthenticSource[authenticSource]
+}
+
+// APIKeys returns the API keys of all tenants, mapped to their authentic source
+func (t *Tenants) APIKeys() map[string]string {
+	return t.apiKeys
+}
+
+// ClientCertificates returns the client certificate fingerprints of all tenants, mapped to their authentic source
+func (t *Tenants) ClientCertificates() map[string]string {
+	return t.clientCertificates
+}
@@ -0,0 +1,90 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+	"vc/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestNew(t *testing.T) {
+	tts := []struct {
+		name    string
+		cfg     []model.Tenant
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			cfg: []model.Tenant{
+				{Name: "edu", AuthenticSources: []string{"LADOK"}, APIKeys: map[string]string{"key-ladok": "LADOK"}},
+				{Name: "social", AuthenticSources: []string{"FK"}, ClientCertificates: map[string]string{"abcd": "FK"}},
+			},
+		},
+		{
+			name:    "no name",
+			cfg:     []model.Tenant{{AuthenticSources: []string{"LADOK"}}},
+			wantErr: true,
+		},
+		{
+			name: "duplicate name",
+			cfg: []model.Tenant{
+				{Name: "edu", AuthenticSources: []string{"LADOK"}},
+				{Name: "edu", AuthenticSources: []string{"FK"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "authentic source in two tenants",
+			cfg: []model.Tenant{
+				{Name: "edu", AuthenticSources: []string{"LADOK"}},
+				{Name: "social", AuthenticSources: []string{"LADOK"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "api key for authentic source of another tenant",
+			cfg: []model.Tenant{
+				{Name: "edu", AuthenticSources: []string{"LADOK"}},
+				{Name: "social", AuthenticSources: []string{"FK"}, APIKeys: map[string]string{"key": "LADOK"}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tenants, err := New([]model.Tenant{{Name: "edu", AuthenticSources: []string{"LADOK"}, SigningKID: "edu-key"}})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{Default, "edu"}, tenants.Names())
+	assert.Equal(t, "edu", tenants.ByAuthenticSource("LADOK"))
+	assert.Equal(t, Default, tenants.ByAuthenticSource("SUNET"))
+	assert.Equal(t, "edu-key", tenants.Get("edu").SigningKID)
+	assert.Nil(t, tenants.Get(Default))
+
+	assert.Equal(t, "vc", Database("vc", Default))
+	assert.Equal(t, "vc_edu", Database("vc", "edu"))
+}
+
+func TestGRPCMetadata(t *testing.T) {
+	ctx := OutgoingContext(NewContext(context.Background(), "edu"))
+	md, _ := metadata.FromOutgoingContext(ctx)
+
+	ctx = FromIncomingContext(metadata.NewIncomingContext(context.Background(), md))
+	assert.Equal(t, "edu", FromContext(ctx))
+
+	assert.Equal(t, Default, FromContext(FromIncomingContext(context.Background())))
+}
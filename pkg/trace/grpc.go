@@ -0,0 +1,18 @@
+package trace
+
+import (
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+)
+
+// GRPCServerOption instruments a gRPC server, each call gets a server span that is a child of the span propagated by
+// the client, and handlers continue the trace from their ctx
+func GRPCServerOption() grpc.ServerOption {
+	return grpc.StatsHandler(otelgrpc.NewServerHandler())
+}
+
+// GRPCClientOption instruments a gRPC client connection, each call gets a client span that is a child of the span in
+// the call ctx, and the trace is propagated to the server
+func GRPCClientOption() grpc.DialOption {
+	return grpc.WithStatsHandler(otelgrpc.NewClientHandler())
+}
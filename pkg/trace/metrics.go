@@ -0,0 +1,44 @@
+package trace
+
+import (
+	"context"
+	"time"
+	"vc/pkg/model"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+// newMeterProvider returns a meter provider periodically exporting metrics to the same collector as the traces if
+// metrics are enabled, and read by prometheus if it is not nil
+func newMeterProvider(ctx context.Context, cfg *model.Cfg, serviceName string, prometheus *Prometheus) (*sdkmetric.MeterProvider, error) {
+	opts := []sdkmetric.Option{
+		sdkmetric.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(serviceName),
+		)),
+	}
+
+	if cfg.Common.Tracing.Metrics.Enabled {
+		exp, err := otlpmetrichttp.New(ctx,
+			otlpmetrichttp.WithEndpoint(cfg.Common.Tracing.Addr),
+			otlpmetrichttp.WithInsecure(),
+			otlpmetrichttp.WithTimeout(time.Duration(cfg.Common.Tracing.Timeout)*time.Second),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exp,
+			sdkmetric.WithInterval(time.Duration(cfg.Common.Tracing.Metrics.Interval)*time.Second),
+		)))
+	}
+
+	if prometheus != nil {
+		opts = append(opts, sdkmetric.WithReader(prometheus.reader))
+	}
+
+	return sdkmetric.NewMeterProvider(opts...), nil
+}
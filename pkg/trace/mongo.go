@@ -0,0 +1,69 @@
+package trace
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// mongoCommand identifies a command between its started and finished events
+type mongoCommand struct {
+	connectionID string
+	requestID    int64
+}
+
+// NewMongoMonitor returns a command monitor that records each MongoDB command as a client span, a child of the span
+// in the ctx the command was run with. The command document is not recorded since it may hold personal data.
+// Events are passed on to next, which may be nil.
+func NewMongoMonitor(next *event.CommandMonitor) *event.CommandMonitor {
+	tracer := otel.Tracer("vc/pkg/trace/mongo")
+	spans := &sync.Map{}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			attrs := []attribute.KeyValue{
+				semconv.DBSystemMongoDB,
+				semconv.DBNamespace(evt.DatabaseName),
+				semconv.DBOperationName(evt.CommandName),
+			}
+			name := evt.CommandName + " " + evt.DatabaseName
+			// the first element of a command is its name, with the collection as value
+			if collection, ok := evt.Command.Index(0).Value().StringValueOK(); ok {
+				attrs = append(attrs, semconv.DBCollectionName(collection))
+				name += "." + collection
+			}
+
+			_, span := tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+			spans.Store(mongoCommand{evt.ConnectionID, evt.RequestID}, span)
+
+			if next != nil && next.Started != nil {
+				next.Started(ctx, evt)
+			}
+		},
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			if span, ok := spans.LoadAndDelete(mongoCommand{evt.ConnectionID, evt.RequestID}); ok {
+				span.(trace.Span).End()
+			}
+
+			if next != nil && next.Succeeded != nil {
+				next.Succeeded(ctx, evt)
+			}
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			if span, ok := spans.LoadAndDelete(mongoCommand{evt.ConnectionID, evt.RequestID}); ok {
+				span.(trace.Span).SetStatus(codes.Error, evt.Failure)
+				span.(trace.Span).End()
+			}
+
+			if next != nil && next.Failed != nil {
+				next.Failed(ctx, evt)
+			}
+		},
+	}
+}
@@ -0,0 +1,74 @@
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestMongoMonitor(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	started := 0
+	monitor := NewMongoMonitor(&event.CommandMonitor{
+		Started: func(context.Context, *event.CommandStartedEvent) { started++ },
+	})
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "apiv1:Test")
+
+	command, err := bson.Marshal(bson.D{{Key: "find", Value: "vc_datastore"}, {Key: "filter", Value: bson.D{{Key: "identities.given_name", Value: "Magnus"}}}})
+	assert.NoError(t, err)
+
+	monitor.Started(ctx, &event.CommandStartedEvent{
+		Command:      command,
+		DatabaseName: "vc",
+		CommandName:  "find",
+		RequestID:    1,
+		ConnectionID: "localhost:27017[-1]",
+	})
+	monitor.Succeeded(ctx, &event.CommandSucceededEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "find", RequestID: 1, ConnectionID: "localhost:27017[-1]"},
+	})
+
+	monitor.Started(ctx, &event.CommandStartedEvent{
+		Command:      command,
+		DatabaseName: "vc",
+		CommandName:  "find",
+		RequestID:    2,
+		ConnectionID: "localhost:27017[-1]",
+	})
+	monitor.Failed(ctx, &event.CommandFailedEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "find", RequestID: 2, ConnectionID: "localhost:27017[-1]"},
+		Failure:              "connection reset",
+	})
+	parent.End()
+
+	assert.Equal(t, 2, started)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 3)
+
+	succeeded := spans[0]
+	assert.Equal(t, "find vc.vc_datastore", succeeded.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), succeeded.Parent().SpanID())
+	assert.Equal(t, codes.Unset, succeeded.Status().Code)
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		attribute.String("db.system", "mongodb"),
+		attribute.String("db.namespace", "vc"),
+		attribute.String("db.operation.name", "find"),
+		attribute.String("db.collection.name", "vc_datastore"),
+	}, succeeded.Attributes())
+
+	failed := spans[1]
+	assert.Equal(t, codes.Error, failed.Status().Code)
+	assert.Equal(t, "connection reset", failed.Status().Description)
+}
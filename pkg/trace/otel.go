@@ -10,6 +10,7 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
@@ -19,6 +20,10 @@ import (
 // Tracer is a wrapper for opentelemetry tracer
 type Tracer struct {
 	TP *sdktrace.TracerProvider
+	// MP is nil when metrics are not enabled
+	MP *sdkmetric.MeterProvider
+	// Prometheus is nil when the /metrics endpoint is not enabled
+	Prometheus *Prometheus
 	trace.Tracer
 	log *logger.Log
 }
@@ -30,14 +35,31 @@ func New(ctx context.Context, cfg *model.Cfg, serviceName string, log *logger.Lo
 		return nil, err
 	}
 
+	sampler, err := newSampler(cfg.Common.Tracing.Sampler)
+	if err != nil {
+		return nil, err
+	}
+
 	tracer := &Tracer{
-		TP:  newTraceProvider(exp, serviceName),
+		TP:  newTraceProvider(exp, serviceName, sampler),
 		log: log.New("trace"),
 	}
 
 	otel.SetTracerProvider(tracer.TP)
 	otel.SetTextMapPropagator(jaegerPropagator.Jaeger{})
 
+	if cfg.Common.Tracing.Metrics.Prometheus {
+		tracer.Prometheus = newPrometheus()
+	}
+
+	if cfg.Common.Tracing.Metrics.Enabled || cfg.Common.Tracing.Metrics.Prometheus {
+		tracer.MP, err = newMeterProvider(ctx, cfg, serviceName, tracer.Prometheus)
+		if err != nil {
+			return nil, err
+		}
+		otel.SetMeterProvider(tracer.MP)
+	}
+
 	tracer.Tracer = otel.Tracer("")
 
 	return tracer, nil
@@ -51,7 +73,7 @@ func NewForTesting(ctx context.Context, projectName string, log *logger.Log) (*T
 	}
 
 	tracer := &Tracer{
-		TP:  newTraceProvider(exp, projectName),
+		TP:  newTraceProvider(exp, projectName, sdktrace.AlwaysSample()),
 		log: log,
 	}
 
@@ -66,6 +88,11 @@ func NewForTesting(ctx context.Context, projectName string, log *logger.Log) (*T
 // Shutdown shuts down the tracer
 func (t *Tracer) Shutdown(ctx context.Context) error {
 	t.log.Info("Shutting down tracer")
+	if t.MP != nil {
+		if err := t.MP.Shutdown(ctx); err != nil {
+			t.log.Error(err, "meter provider shutdown failed")
+		}
+	}
 	return t.TP.Shutdown(ctx)
 }
 
@@ -81,9 +108,10 @@ func newExporter(ctx context.Context, cfg *model.Cfg) (sdktrace.SpanExporter, er
 	)
 }
 
-func newTraceProvider(exp sdktrace.SpanExporter, serviceName string) *sdktrace.TracerProvider {
+func newTraceProvider(exp sdktrace.SpanExporter, serviceName string, sampler sdktrace.Sampler) *sdktrace.TracerProvider {
 	return sdktrace.NewTracerProvider(
 		sdktrace.WithBatcher(exp),
+		sdktrace.WithSampler(sampler),
 		sdktrace.WithResource(resource.NewWithAttributes(
 			semconv.SchemaURL,
 			semconv.ServiceName(serviceName),
@@ -0,0 +1,215 @@
+package trace
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// prometheusContentType is the media type of the Prometheus text exposition format
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Prometheus serves the metrics of the meter provider in the Prometheus text exposition format
+type Prometheus struct {
+	reader *sdkmetric.ManualReader
+}
+
+func newPrometheus() *Prometheus {
+	return &Prometheus{reader: sdkmetric.NewManualReader()}
+}
+
+// ServeHTTP collects the metrics and writes them, one family per metric name
+func (p *Prometheus) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rm := &metricdata.ResourceMetrics{}
+	if err := p.reader.Collect(r.Context(), rm); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", prometheusContentType)
+	w.Write(formatPrometheus(rm))
+}
+
+// prometheusFamily is the metrics of one name, several scopes may record the same metric
+type prometheusFamily struct {
+	help    string
+	kind    string
+	samples bytes.Buffer
+}
+
+// formatPrometheus formats the metrics of rm, families sorted by name. Counters get the _total suffix and units of
+// seconds and bytes are added to the name, as the Prometheus naming conventions ask.
+func formatPrometheus(rm *metricdata.ResourceMetrics) []byte {
+	families := map[string]*prometheusFamily{}
+	family := func(name, help, kind string) *prometheusFamily {
+		f, ok := families[name]
+		if !ok {
+			f = &prometheusFamily{help: help, kind: kind}
+			families[name] = f
+		}
+		return f
+	}
+
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			name := prometheusName(m.Name, m.Unit)
+
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				writeSum(family, name, m.Description, data.IsMonotonic, data.DataPoints)
+			case metricdata.Sum[float64]:
+				writeSum(family, name, m.Description, data.IsMonotonic, data.DataPoints)
+			case metricdata.Gauge[int64]:
+				writeDataPoints(&family(name, m.Description, "gauge").samples, name, data.DataPoints)
+			case metricdata.Gauge[float64]:
+				writeDataPoints(&family(name, m.Description, "gauge").samples, name, data.DataPoints)
+			case metricdata.Histogram[int64]:
+				writeHistogram(&family(name, m.Description, "histogram").samples, name, data.DataPoints)
+			case metricdata.Histogram[float64]:
+				writeHistogram(&family(name, m.Description, "histogram").samples, name, data.DataPoints)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := &bytes.Buffer{}
+	for _, name := range names {
+		f := families[name]
+		if f.help != "" {
+			fmt.Fprintf(out, "# HELP %s %s\n", name, escapeHelp(f.help))
+		}
+		fmt.Fprintf(out, "# TYPE %s %s\n", name, f.kind)
+		out.Write(f.samples.Bytes())
+	}
+
+	return out.Bytes()
+}
+
+func writeSum[N int64 | float64](family func(name, help, kind string) *prometheusFamily, name, help string, monotonic bool, points []metricdata.DataPoint[N]) {
+	if !monotonic {
+		writeDataPoints(&family(name, help, "gauge").samples, name, points)
+		return
+	}
+
+	name += "_total"
+	writeDataPoints(&family(name, help, "counter").samples, name, points)
+}
+
+func writeDataPoints[N int64 | float64](out *bytes.Buffer, name string, points []metricdata.DataPoint[N]) {
+	sortByAttributes(points, func(point metricdata.DataPoint[N]) attribute.Set { return point.Attributes })
+
+	for _, point := range points {
+		fmt.Fprintf(out, "%s%s %s\n", name, prometheusLabels(point.Attributes), formatFloat(float64(point.Value)))
+	}
+}
+
+// writeHistogram writes the cumulative buckets, sum and count of each data point
+func writeHistogram[N int64 | float64](out *bytes.Buffer, name string, points []metricdata.HistogramDataPoint[N]) {
+	sortByAttributes(points, func(point metricdata.HistogramDataPoint[N]) attribute.Set { return point.Attributes })
+
+	for _, point := range points {
+		var cumulative uint64
+		for i, bound := range point.Bounds {
+			cumulative += point.BucketCounts[i]
+			fmt.Fprintf(out, "%s_bucket%s %d\n", name, prometheusLabels(point.Attributes, "le", formatFloat(bound)), cumulative)
+		}
+		fmt.Fprintf(out, "%s_bucket%s %d\n", name, prometheusLabels(point.Attributes, "le", "+Inf"), point.Count)
+
+		labels := prometheusLabels(point.Attributes)
+		fmt.Fprintf(out, "%s_sum%s %s\n", name, labels, formatFloat(float64(point.Sum)))
+		fmt.Fprintf(out, "%s_count%s %d\n", name, labels, point.Count)
+	}
+}
+
+// sortByAttributes sorts points by their labels, the order of data points is random but scrapes are easier to compare
+// in a stable order
+func sortByAttributes[P any](points []P, attributes func(P) attribute.Set) {
+	sort.SliceStable(points, func(i, j int) bool {
+		return prometheusLabels(attributes(points[i])) < prometheusLabels(attributes(points[j]))
+	})
+}
+
+// prometheusName returns name with the characters Prometheus does not allow replaced by _, e.g. the dots of otel
+// names, and the base unit as suffix
+func prometheusName(name, unit string) string {
+	name = sanitizeName(name)
+
+	switch unit {
+	case "s":
+		name += "_seconds"
+	case "ms":
+		name += "_milliseconds"
+	case "By":
+		name += "_bytes"
+	}
+
+	return name
+}
+
+func sanitizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == ':' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// prometheusLabels formats the attributes of set and the extra label name and value pairs
+func prometheusLabels(set attribute.Set, extra ...string) string {
+	if set.Len() == 0 && len(extra) == 0 {
+		return ""
+	}
+
+	labels := make([]string, 0, set.Len()+len(extra)/2)
+	iter := set.Iter()
+	for iter.Next() {
+		kv := iter.Attribute()
+		labels = append(labels, fmt.Sprintf("%s=%q", sanitizeName(string(kv.Key)), escapeLabelValue(kv.Value.Emit())))
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		labels = append(labels, fmt.Sprintf("%s=%q", extra[i], extra[i+1]))
+	}
+
+	return "{" + strings.Join(labels, ",") + "}"
+}
+
+// escapeLabelValue leaves the escaping of backslashes, quotes and newlines to %q, and replaces the other characters %q
+// would escape in a way Prometheus does not read
+func escapeLabelValue(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\\' || r == '"' || r == '\n' || strconv.IsPrint(r) {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
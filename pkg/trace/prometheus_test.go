@@ -0,0 +1,76 @@
+package trace
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func TestPrometheus(t *testing.T) {
+	ctx := context.Background()
+	p := newPrometheus()
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(p.reader)).Meter("test")
+
+	counter, err := meter.Int64Counter("apigw.documents.uploaded", metric.WithDescription("Number of documents uploaded"))
+	assert.NoError(t, err)
+	counter.Add(ctx, 2, metric.WithAttributes(attribute.String("authentic_source", "SUNET")))
+	counter.Add(ctx, 1, metric.WithAttributes(attribute.String("authentic_source", `a "quoted"\source`)))
+
+	histogram, err := meter.Float64Histogram("http.server.duration", metric.WithUnit("s"), metric.WithExplicitBucketBoundaries(0.1, 1))
+	assert.NoError(t, err)
+	histogram.Record(ctx, 0.05, metric.WithAttributes(attribute.String("route", "/api/v1/upload")))
+	histogram.Record(ctx, 0.5, metric.WithAttributes(attribute.String("route", "/api/v1/upload")))
+	histogram.Record(ctx, 2, metric.WithAttributes(attribute.String("route", "/api/v1/upload")))
+
+	gauge, err := meter.Int64UpDownCounter("webhook.queue")
+	assert.NoError(t, err)
+	gauge.Add(ctx, 3)
+
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, prometheusContentType, rec.Header().Get("Content-Type"))
+
+	body, err := io.ReadAll(rec.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, `# HELP apigw_documents_uploaded_total Number of documents uploaded
+# TYPE apigw_documents_uploaded_total counter
+apigw_documents_uploaded_total{authentic_source="SUNET"} 2
+apigw_documents_uploaded_total{authentic_source="a \"quoted\"\\source"} 1
+# TYPE http_server_duration_seconds histogram
+http_server_duration_seconds_bucket{route="/api/v1/upload",le="0.1"} 1
+http_server_duration_seconds_bucket{route="/api/v1/upload",le="1"} 2
+http_server_duration_seconds_bucket{route="/api/v1/upload",le="+Inf"} 3
+http_server_duration_seconds_sum{route="/api/v1/upload"} 2.55
+http_server_duration_seconds_count{route="/api/v1/upload"} 3
+# TYPE webhook_queue gauge
+webhook_queue 3
+`, string(body))
+}
+
+func TestPrometheusName(t *testing.T) {
+	tts := []struct {
+		name string
+		unit string
+		want string
+	}{
+		{name: "http.server.requests", want: "http_server_requests"},
+		{name: "http.server.duration", unit: "s", want: "http_server_duration_seconds"},
+		{name: "upload-size", unit: "By", want: "upload_size_bytes"},
+		{name: "verifier.session.replays", unit: "1", want: "verifier_session_replays"},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, prometheusName(tt.name, tt.unit))
+		})
+	}
+}
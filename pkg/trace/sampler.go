@@ -0,0 +1,82 @@
+package trace
+
+import (
+	"fmt"
+	"sync"
+	"time"
+	"vc/pkg/model"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newSampler returns a parent based sampler, root spans are sampled according to cfg
+func newSampler(cfg model.Sampler) (sdktrace.Sampler, error) {
+	var root sdktrace.Sampler
+	switch cfg.Type {
+	case "", "always":
+		root = sdktrace.AlwaysSample()
+	case "ratio":
+		root = sdktrace.TraceIDRatioBased(cfg.Ratio)
+	case "ratelimit":
+		root = newRateLimitSampler(cfg.RateLimit)
+	default:
+		return nil, fmt.Errorf("unknown sampler type %q", cfg.Type)
+	}
+
+	return sdktrace.ParentBased(root), nil
+}
+
+// rateLimitSampler samples at most limit traces per second, using a token bucket with a burst of one second
+type rateLimitSampler struct {
+	mu     sync.Mutex
+	limit  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+func newRateLimitSampler(limit float64) *rateLimitSampler {
+	return &rateLimitSampler{
+		limit:  limit,
+		tokens: limit,
+		last:   time.Now(),
+		now:    time.Now,
+	}
+}
+
+// ShouldSample implements sdktrace.Sampler
+func (s *rateLimitSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	decision := sdktrace.Drop
+	if s.allow() {
+		decision = sdktrace.RecordAndSample
+	}
+
+	return sdktrace.SamplingResult{
+		Decision:   decision,
+		Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+}
+
+// Description implements sdktrace.Sampler
+func (s *rateLimitSampler) Description() string {
+	return fmt.Sprintf("RateLimitSampler{%g}", s.limit)
+}
+
+func (s *rateLimitSampler) allow() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.tokens += now.Sub(s.last).Seconds() * s.limit
+	if s.tokens > s.limit {
+		s.tokens = s.limit
+	}
+	s.last = now
+
+	if s.tokens < 1 {
+		return false
+	}
+	s.tokens--
+	return true
+}
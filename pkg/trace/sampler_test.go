@@ -0,0 +1,48 @@
+package trace
+
+import (
+	"context"
+	"testing"
+	"time"
+	"vc/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestRateLimitSampler(t *testing.T) {
+	now := time.Now()
+	s := newRateLimitSampler(2)
+	s.now = func() time.Time { return now }
+	s.last = now
+
+	params := sdktrace.SamplingParameters{ParentContext: context.Background()}
+
+	assert.Equal(t, sdktrace.RecordAndSample, s.ShouldSample(params).Decision)
+	assert.Equal(t, sdktrace.RecordAndSample, s.ShouldSample(params).Decision)
+	assert.Equal(t, sdktrace.Drop, s.ShouldSample(params).Decision)
+
+	now = now.Add(500 * time.Millisecond)
+	assert.Equal(t, sdktrace.RecordAndSample, s.ShouldSample(params).Decision)
+	assert.Equal(t, sdktrace.Drop, s.ShouldSample(params).Decision)
+}
+
+func TestNewSampler(t *testing.T) {
+	tts := []struct {
+		name    string
+		cfg     model.Sampler
+		wantErr bool
+	}{
+		{name: "default", cfg: model.Sampler{}},
+		{name: "ratio", cfg: model.Sampler{Type: "ratio", Ratio: 0.5}},
+		{name: "ratelimit", cfg: model.Sampler{Type: "ratelimit", RateLimit: 10}},
+		{name: "unknown", cfg: model.Sampler{Type: "sometimes"}, wantErr: true},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newSampler(tt.cfg)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}